@@ -58,6 +58,13 @@ type ACL struct {
 	// We use an iradix for the purposes of ordered iteration.
 	wildcardHostVolumes *iradix.Tree
 
+	// nodePools maps a node pool to a capabilitySet
+	nodePools *iradix.Tree
+
+	// wildcardNodePools maps a glob pattern of node pool names to a capabilitySet
+	// We use an iradix for the purposes of ordered iteration.
+	wildcardNodePools *iradix.Tree
+
 	agent    string
 	node     string
 	operator string
@@ -95,6 +102,8 @@ func NewACL(management bool, policies []*Policy) (*ACL, error) {
 	wnsTxn := iradix.New().Txn()
 	hvTxn := iradix.New().Txn()
 	whvTxn := iradix.New().Txn()
+	npTxn := iradix.New().Txn()
+	wnpTxn := iradix.New().Txn()
 
 	for _, policy := range policies {
 	NAMESPACES:
@@ -183,6 +192,49 @@ func NewACL(management bool, policies []*Policy) (*ACL, error) {
 			}
 		}
 
+	NODEPOOLS:
+		for _, np := range policy.NodePools {
+			// Should the node pool be matched using a glob?
+			globDefinition := strings.Contains(np.Name, "*")
+
+			// Check for existing capabilities
+			var capabilities capabilitySet
+
+			if globDefinition {
+				raw, ok := wnpTxn.Get([]byte(np.Name))
+				if ok {
+					capabilities = raw.(capabilitySet)
+				} else {
+					capabilities = make(capabilitySet)
+					wnpTxn.Insert([]byte(np.Name), capabilities)
+				}
+			} else {
+				raw, ok := npTxn.Get([]byte(np.Name))
+				if ok {
+					capabilities = raw.(capabilitySet)
+				} else {
+					capabilities = make(capabilitySet)
+					npTxn.Insert([]byte(np.Name), capabilities)
+				}
+			}
+
+			// Deny always takes precedence
+			if capabilities.Check(NodePoolCapabilityDeny) {
+				continue
+			}
+
+			// Add in all the capabilities
+			for _, cap := range np.Capabilities {
+				if cap == NodePoolCapabilityDeny {
+					// Overwrite any existing capabilities
+					capabilities.Clear()
+					capabilities.Set(NodePoolCapabilityDeny)
+					continue NODEPOOLS
+				}
+				capabilities.Set(cap)
+			}
+		}
+
 		// Take the maximum privilege for agent, node, and operator
 		if policy.Agent != nil {
 			acl.agent = maxPrivilege(acl.agent, policy.Agent.Policy)
@@ -206,6 +258,8 @@ func NewACL(management bool, policies []*Policy) (*ACL, error) {
 	acl.wildcardNamespaces = wnsTxn.Commit()
 	acl.hostVolumes = hvTxn.Commit()
 	acl.wildcardHostVolumes = whvTxn.Commit()
+	acl.nodePools = npTxn.Commit()
+	acl.wildcardNodePools = wnpTxn.Commit()
 
 	return acl, nil
 }
@@ -291,6 +345,44 @@ func (a *ACL) AllowHostVolume(ns string) bool {
 	return !capabilities.Check(PolicyDeny)
 }
 
+// AllowNodePoolOperation checks if a given operation is allowed for a node pool
+func (a *ACL) AllowNodePoolOperation(pool string, op string) bool {
+	// Hot path management tokens
+	if a.management {
+		return true
+	}
+
+	// Check for a matching capability set
+	capabilities, ok := a.matchingNodePoolCapabilitySet(pool)
+	if !ok {
+		return false
+	}
+
+	// Check if the capability has been granted
+	return capabilities.Check(op)
+}
+
+// AllowNodePool checks if any operations are allowed for a node pool
+func (a *ACL) AllowNodePool(pool string) bool {
+	// Hot path management tokens
+	if a.management {
+		return true
+	}
+
+	// Check for a matching capability set
+	capabilities, ok := a.matchingNodePoolCapabilitySet(pool)
+	if !ok {
+		return false
+	}
+
+	// Check if the capability has been granted
+	if len(capabilities) == 0 {
+		return false
+	}
+
+	return !capabilities.Check(NodePoolCapabilityDeny)
+}
+
 // matchingNamespaceCapabilitySet looks for a capabilitySet that matches the namespace,
 // if no concrete definitions are found, then we return the closest matching
 // glob.
@@ -323,6 +415,22 @@ func (a *ACL) matchingHostVolumeCapabilitySet(name string) (capabilitySet, bool)
 	return a.findClosestMatchingGlob(a.wildcardHostVolumes, name)
 }
 
+// matchingNodePoolCapabilitySet looks for a capabilitySet that matches the node pool name,
+// if no concrete definitions are found, then we return the closest matching
+// glob.
+// The closest matching glob is the one that has the smallest character
+// difference between the node pool name and the glob.
+func (a *ACL) matchingNodePoolCapabilitySet(name string) (capabilitySet, bool) {
+	// Check for a concrete matching capability set
+	raw, ok := a.nodePools.Get([]byte(name))
+	if ok {
+		return raw.(capabilitySet), true
+	}
+
+	// We didn't find a concrete match, so lets try and evaluate globs.
+	return a.findClosestMatchingGlob(a.wildcardNodePools, name)
+}
+
 type matchingGlob struct {
 	name          string
 	difference    int
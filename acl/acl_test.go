@@ -364,6 +364,68 @@ func TestWildcardHostVolumeMatching(t *testing.T) {
 		})
 	}
 }
+
+func TestAllowNodePoolOperation(t *testing.T) {
+	tests := []struct {
+		Policy string
+		Pool   string
+		Op     string
+		Allow  bool
+	}{
+		{
+			Policy: `node_pool "prod" { policy = "read" }`,
+			Pool:   "prod",
+			Op:     NodePoolCapabilityRead,
+			Allow:  true,
+		},
+		{
+			Policy: `node_pool "prod" { policy = "read" }`,
+			Pool:   "prod",
+			Op:     NodePoolCapabilityWrite,
+			Allow:  false,
+		},
+		{
+			Policy: `node_pool "prod" { policy = "write" }`,
+			Pool:   "prod",
+			Op:     NodePoolCapabilityDelete,
+			Allow:  true,
+		},
+		{ // Wildcard matches
+			Policy: `node_pool "prod-*" { policy = "write" }`,
+			Pool:   "prod-api",
+			Op:     NodePoolCapabilityWrite,
+			Allow:  true,
+		},
+		{ // Concrete matches take precedence
+			Policy: `node_pool "prod-api" { policy = "deny" }
+			         node_pool "prod-*" { policy = "write" }`,
+			Pool:  "prod-api",
+			Op:    NodePoolCapabilityRead,
+			Allow: false,
+		},
+		{ // Unknown pools are denied
+			Policy: `node_pool "prod" { policy = "write" }`,
+			Pool:   "dev",
+			Op:     NodePoolCapabilityRead,
+			Allow:  false,
+		},
+	}
+
+	for _, tc := range tests {
+		t.Run(tc.Policy, func(t *testing.T) {
+			assert := assert.New(t)
+
+			policy, err := Parse(tc.Policy)
+			assert.NoError(err)
+
+			acl, err := NewACL(false, []*Policy{policy})
+			assert.Nil(err)
+
+			assert.Equal(tc.Allow, acl.AllowNodePoolOperation(tc.Pool, tc.Op))
+		})
+	}
+}
+
 func TestACL_matchingCapabilitySet_returnsAllMatches(t *testing.T) {
 	tests := []struct {
 		Policy        string
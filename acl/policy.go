@@ -45,6 +45,8 @@ const (
 	NamespaceCapabilityReadJobScaling       = "read-job-scaling"
 	NamespaceCapabilityScaleJob             = "scale-job"
 	NamespaceCapabilitySubmitRecommendation = "submit-recommendation"
+	NamespaceCapabilityVariablesRead        = "variables-read"
+	NamespaceCapabilityVariablesWrite       = "variables-write"
 )
 
 var (
@@ -62,14 +64,29 @@ const (
 	HostVolumeCapabilityMountReadWrite = "mount-readwrite"
 )
 
+const (
+	// The following are the fine-grained capabilities that can be granted for
+	// a node pool. The Policy stanza is a short hand for granting several of
+	// these. When capabilities are combined we take the union of all
+	// capabilities. If the deny capability is present, it takes precedence
+	// and overwrites all other capabilities.
+
+	NodePoolCapabilityDeny   = "deny"
+	NodePoolCapabilityRead   = "read"
+	NodePoolCapabilityWrite  = "write"
+	NodePoolCapabilityDelete = "delete"
+)
+
 var (
-	validVolume = regexp.MustCompile("^[a-zA-Z0-9-*]{1,128}$")
+	validVolume   = regexp.MustCompile("^[a-zA-Z0-9-*]{1,128}$")
+	validNodePool = regexp.MustCompile("^[a-zA-Z0-9-_.*]{1,128}$")
 )
 
 // Policy represents a parsed HCL or JSON policy.
 type Policy struct {
 	Namespaces  []*NamespacePolicy  `hcl:"namespace,expand"`
 	HostVolumes []*HostVolumePolicy `hcl:"host_volume,expand"`
+	NodePools   []*NodePoolPolicy   `hcl:"node_pool,expand"`
 	Agent       *AgentPolicy        `hcl:"agent"`
 	Node        *NodePolicy         `hcl:"node"`
 	Operator    *OperatorPolicy     `hcl:"operator"`
@@ -83,6 +100,7 @@ type Policy struct {
 func (p *Policy) IsEmpty() bool {
 	return len(p.Namespaces) == 0 &&
 		len(p.HostVolumes) == 0 &&
+		len(p.NodePools) == 0 &&
 		p.Agent == nil &&
 		p.Node == nil &&
 		p.Operator == nil &&
@@ -104,6 +122,13 @@ type HostVolumePolicy struct {
 	Capabilities []string
 }
 
+// NodePoolPolicy is the policy for a specific node pool
+type NodePoolPolicy struct {
+	Name         string `hcl:",key"`
+	Policy       string
+	Capabilities []string
+}
+
 type AgentPolicy struct {
 	Policy string
 }
@@ -151,7 +176,8 @@ func isNamespaceCapabilityValid(cap string) bool {
 		NamespaceCapabilityReadFS, NamespaceCapabilityAllocLifecycle,
 		NamespaceCapabilityAllocExec, NamespaceCapabilityAllocNodeExec,
 		NamespaceCapabilityCSIReadVolume, NamespaceCapabilityCSIWriteVolume, NamespaceCapabilityCSIListVolume, NamespaceCapabilityCSIMountVolume, NamespaceCapabilityCSIRegisterPlugin,
-		NamespaceCapabilityListScalingPolicies, NamespaceCapabilityReadScalingPolicy, NamespaceCapabilityReadJobScaling, NamespaceCapabilityScaleJob:
+		NamespaceCapabilityListScalingPolicies, NamespaceCapabilityReadScalingPolicy, NamespaceCapabilityReadJobScaling, NamespaceCapabilityScaleJob,
+		NamespaceCapabilityVariablesRead, NamespaceCapabilityVariablesWrite:
 		return true
 	// Separate the enterprise-only capabilities
 	case NamespaceCapabilitySentinelOverride, NamespaceCapabilitySubmitRecommendation:
@@ -172,6 +198,7 @@ func expandNamespacePolicy(policy string) []string {
 		NamespaceCapabilityReadJobScaling,
 		NamespaceCapabilityListScalingPolicies,
 		NamespaceCapabilityReadScalingPolicy,
+		NamespaceCapabilityVariablesRead,
 	}
 
 	write := make([]string, len(read))
@@ -188,6 +215,7 @@ func expandNamespacePolicy(policy string) []string {
 		NamespaceCapabilityCSIMountVolume,
 		NamespaceCapabilityCSIWriteVolume,
 		NamespaceCapabilitySubmitRecommendation,
+		NamespaceCapabilityVariablesWrite,
 	}...)
 
 	switch policy {
@@ -231,6 +259,28 @@ func expandHostVolumePolicy(policy string) []string {
 	}
 }
 
+func isNodePoolCapabilityValid(cap string) bool {
+	switch cap {
+	case NodePoolCapabilityDeny, NodePoolCapabilityRead, NodePoolCapabilityWrite, NodePoolCapabilityDelete:
+		return true
+	default:
+		return false
+	}
+}
+
+func expandNodePoolPolicy(policy string) []string {
+	switch policy {
+	case PolicyDeny:
+		return []string{NodePoolCapabilityDeny}
+	case PolicyRead:
+		return []string{NodePoolCapabilityRead}
+	case PolicyWrite:
+		return []string{NodePoolCapabilityRead, NodePoolCapabilityWrite, NodePoolCapabilityDelete}
+	default:
+		return nil
+	}
+}
+
 // Parse is used to parse the specified ACL rules into an
 // intermediary set of policies, before being compiled into
 // the ACL
@@ -296,6 +346,27 @@ func Parse(rules string) (*Policy, error) {
 		}
 	}
 
+	for _, np := range p.NodePools {
+		if !validNodePool.MatchString(np.Name) {
+			return nil, fmt.Errorf("Invalid node pool name: %#v", np)
+		}
+		if np.Policy != "" && !isPolicyValid(np.Policy) {
+			return nil, fmt.Errorf("Invalid node pool policy: %#v", np)
+		}
+		for _, cap := range np.Capabilities {
+			if !isNodePoolCapabilityValid(cap) {
+				return nil, fmt.Errorf("Invalid node pool capability '%s': %#v", cap, np)
+			}
+		}
+
+		// Expand the short hand policy to the capabilities and
+		// add to any existing capabilities
+		if np.Policy != "" {
+			extraCap := expandNodePoolPolicy(np.Policy)
+			np.Capabilities = append(np.Capabilities, extraCap...)
+		}
+	}
+
 	if p.Agent != nil && !isPolicyValid(p.Agent.Policy) {
 		return nil, fmt.Errorf("Invalid agent policy: %#v", p.Agent)
 	}
@@ -35,6 +35,7 @@ func TestParse(t *testing.T) {
 							NamespaceCapabilityReadJobScaling,
 							NamespaceCapabilityListScalingPolicies,
 							NamespaceCapabilityReadScalingPolicy,
+							NamespaceCapabilityVariablesRead,
 						},
 					},
 				},
@@ -84,6 +85,7 @@ func TestParse(t *testing.T) {
 							NamespaceCapabilityReadJobScaling,
 							NamespaceCapabilityListScalingPolicies,
 							NamespaceCapabilityReadScalingPolicy,
+							NamespaceCapabilityVariablesRead,
 						},
 					},
 					{
@@ -97,6 +99,7 @@ func TestParse(t *testing.T) {
 							NamespaceCapabilityReadJobScaling,
 							NamespaceCapabilityListScalingPolicies,
 							NamespaceCapabilityReadScalingPolicy,
+							NamespaceCapabilityVariablesRead,
 							NamespaceCapabilityScaleJob,
 							NamespaceCapabilitySubmitJob,
 							NamespaceCapabilityDispatchJob,
@@ -107,6 +110,7 @@ func TestParse(t *testing.T) {
 							NamespaceCapabilityCSIMountVolume,
 							NamespaceCapabilityCSIWriteVolume,
 							NamespaceCapabilitySubmitRecommendation,
+							NamespaceCapabilityVariablesWrite,
 						},
 					},
 					{
@@ -284,6 +288,46 @@ func TestParse(t *testing.T) {
 			"Invalid host volume name",
 			nil,
 		},
+		{
+			`
+			node_pool "prod-*" {
+				policy = "write"
+			}
+			node_pool "dev" {
+				capabilities = ["read"]
+			}
+			`,
+			"",
+			&Policy{
+				NodePools: []*NodePoolPolicy{
+					{
+						Name:   "prod-*",
+						Policy: PolicyWrite,
+						Capabilities: []string{
+							NodePoolCapabilityRead,
+							NodePoolCapabilityWrite,
+							NodePoolCapabilityDelete,
+						},
+					},
+					{
+						Name:   "dev",
+						Policy: "",
+						Capabilities: []string{
+							NodePoolCapabilityRead,
+						},
+					},
+				},
+			},
+		},
+		{
+			`
+			node_pool "dev" {
+				capabilities = ["mount-readonly"]
+			}
+			`,
+			"Invalid node pool capability",
+			nil,
+		},
 		{
 			`
 			plugin {
@@ -26,6 +26,7 @@ const (
 	AllocClientStatusComplete = "complete"
 	AllocClientStatusFailed   = "failed"
 	AllocClientStatusLost     = "lost"
+	AllocClientStatusUnknown  = "unknown"
 )
 
 // Allocations is used to query the alloc-related endpoints.
@@ -197,10 +198,14 @@ type AllocationMetric struct {
 	QuotaExhausted     []string
 	ResourcesExhausted map[string]*Resources
 	// Deprecated, replaced with ScoreMetaData
-	Scores            map[string]float64
-	AllocationTime    time.Duration
-	CoalescedFailures int
-	ScoreMetaData     []*NodeScoreMeta
+	Scores             map[string]float64
+	AllocationTime     time.Duration
+	CoalescedFailures  int
+	PlacementsWithheld int
+	SpreadSkewFiltered map[string]int
+	ScoreMetaData      []*NodeScoreMeta
+	NodeExplanations   []*NodeExplanation
+	ClassExplanations  map[string]*ClassExplanation
 }
 
 // NodeScoreMeta is used to serialize node scoring metadata
@@ -211,6 +216,29 @@ type NodeScoreMeta struct {
 	NormScore float64
 }
 
+// NodeExplanation explains the outcome of evaluating a node for a
+// placement when explaining a job plan
+type NodeExplanation struct {
+	NodeID    string
+	NodeName  string
+	NodeClass string
+	Filtered  string
+	Exhausted string
+	Scores    map[string]float64
+	NormScore float64
+}
+
+// ClassExplanation summarizes the outcome of evaluating the nodes of a node
+// class for a placement when explaining a job plan
+type ClassExplanation struct {
+	Evaluated int
+	Filtered  int
+	Exhausted int
+	Scored    int
+	TopScore  float64
+	Reasons   map[string]int
+}
+
 // Stub returns a list stub for the allocation
 func (a *Allocation) Stub() *AllocationListStub {
 	return &AllocationListStub{
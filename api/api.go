@@ -73,6 +73,13 @@ type QueryOptions struct {
 	// that support paginated lists.
 	NextToken string
 
+	// Filter is a boolean expression evaluated against the objects returned
+	// by queries that support filtered lists.
+	Filter string
+
+	// Reverse is used to reverse the default order of list results.
+	Reverse bool
+
 	// ctx is an optional context pass through to the underlying HTTP
 	// request layer. Use Context() and WithContext() to manage this.
 	ctx context.Context
@@ -113,6 +120,10 @@ type QueryMeta struct {
 
 	// How long did the request take
 	RequestTime time.Duration
+
+	// NextToken is the token used to fetch the next page of a paginated
+	// list. It is empty if there are no more results.
+	NextToken string
 }
 
 // WriteMeta is used to return meta data about a write
@@ -574,6 +585,18 @@ func (r *request) setQueryOptions(q *QueryOptions) {
 	if q.Prefix != "" {
 		r.params.Set("prefix", q.Prefix)
 	}
+	if q.Filter != "" {
+		r.params.Set("filter", q.Filter)
+	}
+	if q.PerPage != 0 {
+		r.params.Set("per_page", fmt.Sprint(q.PerPage))
+	}
+	if q.NextToken != "" {
+		r.params.Set("next_token", q.NextToken)
+	}
+	if q.Reverse {
+		r.params.Set("reverse", "true")
+	}
 	for k, v := range q.Params {
 		r.params.Set(k, v)
 	}
@@ -919,11 +942,20 @@ func (c *Client) write(endpoint string, in, out interface{}, q *WriteOptions) (*
 // delete is used to do a DELETE request against an endpoint
 // and serialize/deserialized using the standard Nomad conventions.
 func (c *Client) delete(endpoint string, out interface{}, q *WriteOptions) (*WriteMeta, error) {
+	return c.deleteWithBody(endpoint, nil, out, q)
+}
+
+// deleteWithBody is used to do a DELETE request with a request body against
+// an endpoint and serialize/deserialized using the standard Nomad conventions.
+func (c *Client) deleteWithBody(endpoint string, in, out interface{}, q *WriteOptions) (*WriteMeta, error) {
 	r, err := c.newRequest("DELETE", endpoint)
 	if err != nil {
 		return nil, err
 	}
 	r.setWriteOptions(q)
+	if in != nil {
+		r.obj = in
+	}
 	rtt, resp, err := requireOK(c.doRequest(r))
 	if err != nil {
 		return nil, err
@@ -966,6 +998,9 @@ func parseQueryMeta(resp *http.Response, q *QueryMeta) error {
 	default:
 		q.KnownLeader = false
 	}
+
+	// Parse the X-Nomad-NextToken
+	q.NextToken = header.Get("X-Nomad-NextToken")
 	return nil
 }
 
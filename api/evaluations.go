@@ -40,6 +40,29 @@ func (e *Evaluations) Info(evalID string, q *QueryOptions) (*Evaluation, *QueryM
 	return &resp, qm, nil
 }
 
+// Count is used to count the evaluations matching the filter expression of
+// the query options, across all namespaces.
+func (e *Evaluations) Count(q *QueryOptions) (*EvalCountResponse, *QueryMeta, error) {
+	var resp EvalCountResponse
+	qm, err := e.client.query("/v1/evaluations/count", &resp, q)
+	if err != nil {
+		return nil, nil, err
+	}
+	return &resp, qm, nil
+}
+
+// Delete is used to batch delete evaluations using their IDs or a filter
+// expression. The eval broker must be paused and the request requires a
+// management token.
+func (e *Evaluations) Delete(req *EvalDeleteRequest, w *WriteOptions) (*EvalDeleteResponse, *WriteMeta, error) {
+	var resp EvalDeleteResponse
+	wm, err := e.client.deleteWithBody("/v1/evaluations", req, &resp, w)
+	if err != nil {
+		return nil, nil, err
+	}
+	return &resp, wm, nil
+}
+
 // Allocations is used to retrieve a set of allocations given
 // an evaluation ID.
 func (e *Evaluations) Allocations(evalID string, q *QueryOptions) ([]*AllocationListStub, *QueryMeta, error) {
@@ -52,6 +75,25 @@ func (e *Evaluations) Allocations(evalID string, q *QueryOptions) ([]*Allocation
 	return resp, qm, nil
 }
 
+// EvalDeleteRequest is used to delete evaluations. Evals and Filter are
+// mutually exclusive.
+type EvalDeleteRequest struct {
+	Evals  []string
+	Filter string
+	WriteRequest
+}
+
+// EvalDeleteResponse is the response to an evaluations delete request.
+type EvalDeleteResponse struct {
+	// Count is the number of evaluations deleted.
+	Count int
+}
+
+// EvalCountResponse is the response to an evaluations count request.
+type EvalCountResponse struct {
+	Count int
+}
+
 // Evaluation is used to serialize an evaluation.
 type Evaluation struct {
 	ID                   string
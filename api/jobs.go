@@ -344,6 +344,7 @@ func (j *Jobs) PeriodicForce(jobID string, q *WriteOptions) (string, *WriteMeta,
 type PlanOptions struct {
 	Diff           bool
 	PolicyOverride bool
+	Explain        bool
 }
 
 func (j *Jobs) Plan(job *Job, diff bool, q *WriteOptions) (*JobPlanResponse, *WriteMeta, error) {
@@ -363,6 +364,7 @@ func (j *Jobs) PlanOpts(job *Job, opts *PlanOptions, q *WriteOptions) (*JobPlanR
 	if opts != nil {
 		req.Diff = opts.Diff
 		req.PolicyOverride = opts.PolicyOverride
+		req.Explain = opts.Explain
 	}
 
 	var resp JobPlanResponse
@@ -384,11 +386,32 @@ func (j *Jobs) Summary(jobID string, q *QueryOptions) (*JobSummary, *QueryMeta,
 
 func (j *Jobs) Dispatch(jobID string, meta map[string]string,
 	payload []byte, q *WriteOptions) (*JobDispatchResponse, *WriteMeta, error) {
-	var resp JobDispatchResponse
-	req := &JobDispatchRequest{
-		JobID:   jobID,
+	return j.DispatchOpts(jobID, &DispatchOptions{
 		Meta:    meta,
 		Payload: payload,
+	}, q)
+}
+
+// DispatchOptions is used to pass through the options of a dispatch request.
+type DispatchOptions struct {
+	Meta    map[string]string
+	Payload []byte
+
+	// MaxRunDuration, if set, overrides the max_run_duration of every task
+	// group of the dispatched job.
+	MaxRunDuration time.Duration
+}
+
+// DispatchOpts is used to dispatch a new job with the passed options.
+func (j *Jobs) DispatchOpts(jobID string, opts *DispatchOptions, q *WriteOptions) (*JobDispatchResponse, *WriteMeta, error) {
+	var resp JobDispatchResponse
+	req := &JobDispatchRequest{
+		JobID: jobID,
+	}
+	if opts != nil {
+		req.Meta = opts.Meta
+		req.Payload = opts.Payload
+		req.MaxRunDuration = opts.MaxRunDuration
 	}
 	wm, err := j.client.write("/v1/job/"+url.PathEscape(jobID)+"/dispatch", req, &resp, q)
 	if err != nil {
@@ -712,6 +735,29 @@ type MultiregionRegion struct {
 	Meta        map[string]string `hcl:"meta,block"`
 }
 
+const (
+	JobDependencyConditionComplete = "complete"
+	JobDependencyConditionFailed   = "failed"
+	JobDependencyConditionDead     = "dead"
+)
+
+// JobDependency is a job that must reach a given status before the
+// dependent job is scheduled.
+type JobDependency struct {
+	JobID     *string `mapstructure:"job" hcl:"job,optional"`
+	Child     *string `hcl:"child,optional"`
+	Condition *string `hcl:"condition,optional"`
+}
+
+func (d *JobDependency) Canonicalize() {
+	if d.JobID == nil {
+		d.JobID = stringToPtr("")
+	}
+	if d.Condition == nil || *d.Condition == "" {
+		d.Condition = stringToPtr(JobDependencyConditionComplete)
+	}
+}
+
 // PeriodicConfig is for serializing periodic config for a job.
 type PeriodicConfig struct {
 	Enabled         *bool   `hcl:"enabled,optional"`
@@ -797,12 +843,16 @@ type Job struct {
 	Priority         *int                    `hcl:"priority,optional"`
 	AllAtOnce        *bool                   `mapstructure:"all_at_once" hcl:"all_at_once,optional"`
 	Datacenters      []string                `hcl:"datacenters,optional"`
+	NodePool         *string                 `mapstructure:"node_pool" hcl:"node_pool,optional"`
+	Reservation      *string                 `hcl:"reservation,optional"`
 	Constraints      []*Constraint           `hcl:"constraint,block"`
 	Affinities       []*Affinity             `hcl:"affinity,block"`
+	AllocAffinities  []*AllocAffinity        `hcl:"alloc_affinity,block"`
 	TaskGroups       []*TaskGroup            `hcl:"group,block"`
 	Update           *UpdateStrategy         `hcl:"update,block"`
 	Multiregion      *Multiregion            `hcl:"multiregion,block"`
 	Spreads          []*Spread               `hcl:"spread,block"`
+	DependsOn        []*JobDependency        `hcl:"depends_on,block"`
 	Periodic         *PeriodicConfig         `hcl:"periodic,block"`
 	ParameterizedJob *ParameterizedJobConfig `hcl:"parameterized,block"`
 	Reschedule       *ReschedulePolicy       `hcl:"reschedule,block"`
@@ -932,9 +982,15 @@ func (j *Job) Canonicalize() {
 	for _, spread := range j.Spreads {
 		spread.Canonicalize()
 	}
+	for _, d := range j.DependsOn {
+		d.Canonicalize()
+	}
 	for _, a := range j.Affinities {
 		a.Canonicalize()
 	}
+	for _, a := range j.AllocAffinities {
+		a.Canonicalize()
+	}
 }
 
 // LookupTaskGroup finds a task group by name
@@ -983,6 +1039,7 @@ type TaskGroupSummary struct {
 	Running  int
 	Starting int
 	Lost     int
+	Unknown  int
 }
 
 // JobListStub is used to return a subset of information about
@@ -993,6 +1050,7 @@ type JobListStub struct {
 	Name              string
 	Namespace         string `json:",omitempty"`
 	Datacenters       []string
+	NodePool          string
 	Type              string
 	Priority          int
 	Periodic          bool
@@ -1081,6 +1139,12 @@ func (j *Job) AddAffinity(a *Affinity) *Job {
 	return j
 }
 
+// AddAllocAffinity is used to add an alloc affinity to a job.
+func (j *Job) AddAllocAffinity(a *AllocAffinity) *Job {
+	j.AllocAffinities = append(j.AllocAffinities, a)
+	return j
+}
+
 // AddTaskGroup adds a task group to an existing job.
 func (j *Job) AddTaskGroup(grp *TaskGroup) *Job {
 	j.TaskGroups = append(j.TaskGroups, grp)
@@ -1198,6 +1262,7 @@ type JobPlanRequest struct {
 	Job            *Job
 	Diff           bool
 	PolicyOverride bool
+	Explain        bool
 	WriteRequest
 }
 
@@ -1207,6 +1272,7 @@ type JobPlanResponse struct {
 	Diff               *JobDiff
 	Annotations        *PlanAnnotations
 	FailedTGAllocs     map[string]*AllocationMetric
+	PlacedTGAllocs     map[string]*AllocationMetric
 	NextPeriodicLaunch time.Time
 
 	// Warnings contains any warnings about the given job. These may include
@@ -1270,9 +1336,10 @@ type DesiredUpdates struct {
 }
 
 type JobDispatchRequest struct {
-	JobID   string
-	Payload []byte
-	Meta    map[string]string
+	JobID          string
+	Payload        []byte
+	Meta           map[string]string
+	MaxRunDuration time.Duration
 }
 
 type JobDispatchResponse struct {
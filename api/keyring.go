@@ -0,0 +1,93 @@
+package api
+
+import (
+	"fmt"
+	"net/url"
+)
+
+// Keyring is used to access the server keyring of root keys used to encrypt
+// variables and other sensitive data.
+type Keyring struct {
+	client *Client
+}
+
+// Keyring returns a handle to the keyring endpoints.
+func (c *Client) Keyring() *Keyring {
+	return &Keyring{client: c}
+}
+
+// EncryptionAlgorithm chooses which algorithm is used for encrypting and
+// decrypting data with a root key.
+type EncryptionAlgorithm string
+
+const (
+	EncryptionAlgorithmAES256GCM EncryptionAlgorithm = "aes256-gcm"
+)
+
+// RootKeyMeta is the metadata used to refer to a root key. The key material
+// itself is never returned by the API.
+type RootKeyMeta struct {
+	KeyID       string // UUID
+	Algorithm   EncryptionAlgorithm
+	CreateTime  int64
+	CreateIndex uint64
+	ModifyIndex uint64
+	State       RootKeyState
+}
+
+// RootKeyState enumerates the possible states of a root key.
+type RootKeyState string
+
+const (
+	RootKeyStateInactive RootKeyState = "inactive"
+	RootKeyStateActive   RootKeyState = "active"
+)
+
+// KeyringRotateOptions are the options for rotating the root key.
+type KeyringRotateOptions struct {
+	// Algorithm is the encryption algorithm of the new key. Defaults to
+	// AES-256-GCM.
+	Algorithm EncryptionAlgorithm
+
+	// Full re-encrypts all existing variables and ACL tokens with the new
+	// key.
+	Full bool
+}
+
+// List lists the metadata of all the root keys in the keyring.
+func (k *Keyring) List(q *QueryOptions) ([]*RootKeyMeta, *QueryMeta, error) {
+	var resp []*RootKeyMeta
+	qm, err := k.client.query("/v1/operator/keyring/keys", &resp, q)
+	if err != nil {
+		return nil, nil, err
+	}
+	return resp, qm, nil
+}
+
+// Delete deletes an inactive root key from the keyring.
+func (k *Keyring) Delete(keyID string, w *WriteOptions) (*WriteMeta, error) {
+	wm, err := k.client.delete(fmt.Sprintf("/v1/operator/keyring/key/%v", url.PathEscape(keyID)), nil, w)
+	return wm, err
+}
+
+// Rotate generates a new root key and makes it the active key used for
+// encryption.
+func (k *Keyring) Rotate(opts *KeyringRotateOptions, w *WriteOptions) (*RootKeyMeta, *WriteMeta, error) {
+	qp := url.Values{}
+	if opts != nil {
+		if opts.Algorithm != "" {
+			qp.Set("algo", string(opts.Algorithm))
+		}
+		if opts.Full {
+			qp.Set("full", "true")
+		}
+	}
+	resp := &struct {
+		Key *RootKeyMeta
+	}{}
+	wm, err := k.client.write("/v1/operator/keyring/rotate?"+qp.Encode(), nil, resp, w)
+	if err != nil {
+		return nil, nil, err
+	}
+	return resp.Key, wm, nil
+}
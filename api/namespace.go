@@ -70,6 +70,7 @@ type Namespace struct {
 	Name        string
 	Description string
 	Quota       string
+	Weight      int
 	CreateIndex uint64
 	ModifyIndex uint64
 }
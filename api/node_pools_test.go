@@ -0,0 +1,44 @@
+package api
+
+import (
+	"testing"
+
+	"github.com/stretchr/testify/require"
+)
+
+func TestNodePools_RegisterInfoDelete(t *testing.T) {
+	t.Parallel()
+	c, s := makeClient(t, nil, nil)
+	defer s.Stop()
+	nodePools := c.NodePools()
+
+	pool := &NodePool{
+		Name:        "dev",
+		Description: "development nodes",
+		Meta:        map[string]string{"team": "dev"},
+	}
+	wm, err := nodePools.Register(pool, nil)
+	require.NoError(t, err)
+	assertWriteMeta(t, wm)
+
+	// The built-in node pools are always present.
+	resp, qm, err := nodePools.List(nil)
+	require.NoError(t, err)
+	assertQueryMeta(t, qm)
+	require.Len(t, resp, 3)
+
+	out, _, err := nodePools.Info(pool.Name, nil)
+	require.NoError(t, err)
+	require.Equal(t, pool.Description, out.Description)
+	require.Equal(t, pool.Meta, out.Meta)
+
+	wm, err = nodePools.Delete(pool.Name, nil)
+	require.NoError(t, err)
+	assertWriteMeta(t, wm)
+
+	_, _, err = nodePools.Info(pool.Name, nil)
+	require.Error(t, err)
+
+	_, err = nodePools.Delete(NodePoolDefault, nil)
+	require.Error(t, err)
+}
@@ -9,9 +9,10 @@ import (
 )
 
 const (
-	NodeStatusInit  = "initializing"
-	NodeStatusReady = "ready"
-	NodeStatusDown  = "down"
+	NodeStatusInit         = "initializing"
+	NodeStatusReady        = "ready"
+	NodeStatusDown         = "down"
+	NodeStatusDisconnected = "disconnected"
 
 	// NodeSchedulingEligible and Ineligible marks the node as eligible or not,
 	// respectively, for receiving allocations. This is orthogonal to the node
@@ -521,6 +522,7 @@ type DrainMetadata struct {
 type Node struct {
 	ID                    string
 	Datacenter            string
+	NodePool              string
 	Name                  string
 	HTTPAddr              string
 	TLSEnabled            bool
@@ -563,6 +565,13 @@ type NodeCpuResources struct {
 	CpuShares          int64
 	TotalCpuCores      uint16
 	ReservableCpuCores []uint16
+	NUMANodes          []NUMANode
+}
+
+// NUMANode is a NUMA node of a client and the CPU cores that belong to it.
+type NUMANode struct {
+	ID    uint16
+	Cores []uint16
 }
 
 type NodeMemoryResources struct {
@@ -832,6 +841,7 @@ type NodeListStub struct {
 	Address               string
 	ID                    string
 	Datacenter            string
+	NodePool              string
 	Name                  string
 	NodeClass             string
 	Version               string
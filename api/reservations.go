@@ -0,0 +1,104 @@
+package api
+
+import (
+	"errors"
+	"net/url"
+	"time"
+)
+
+// Reservations is used to access reservations endpoints.
+type Reservations struct {
+	client *Client
+}
+
+// Reservations returns a handle on the reservations endpoints.
+func (c *Client) Reservations() *Reservations {
+	return &Reservations{client: c}
+}
+
+// List is used to list the reservations of a namespace.
+func (r *Reservations) List(q *QueryOptions) ([]*Reservation, *QueryMeta, error) {
+	var resp []*Reservation
+	qm, err := r.client.query("/v1/reservations", &resp, q)
+	if err != nil {
+		return nil, nil, err
+	}
+	return resp, qm, nil
+}
+
+// PrefixList is used to list reservations that match a given prefix.
+func (r *Reservations) PrefixList(prefix string, q *QueryOptions) ([]*Reservation, *QueryMeta, error) {
+	if q == nil {
+		q = &QueryOptions{}
+	}
+	q.Prefix = prefix
+	return r.List(q)
+}
+
+// Info is used to fetch details of a specific reservation.
+func (r *Reservations) Info(name string, q *QueryOptions) (*Reservation, *QueryMeta, error) {
+	if name == "" {
+		return nil, nil, errors.New("missing reservation name")
+	}
+
+	var resp Reservation
+	qm, err := r.client.query("/v1/reservation/"+url.PathEscape(name), &resp, q)
+	if err != nil {
+		return nil, nil, err
+	}
+	return &resp, qm, nil
+}
+
+// Create is used to create a reservation. The returned reservation holds the
+// nodes its instances were placed on.
+func (r *Reservations) Create(reservation *Reservation, w *WriteOptions) (*Reservation, *WriteMeta, error) {
+	if reservation == nil {
+		return nil, nil, errors.New("missing reservation")
+	}
+	if reservation.Name == "" {
+		return nil, nil, errors.New("missing reservation name")
+	}
+
+	var resp Reservation
+	wm, err := r.client.write("/v1/reservations", reservation, &resp, w)
+	if err != nil {
+		return nil, nil, err
+	}
+	return &resp, wm, nil
+}
+
+// Delete is used to delete a reservation.
+func (r *Reservations) Delete(name string, w *WriteOptions) (*WriteMeta, error) {
+	if name == "" {
+		return nil, errors.New("missing reservation name")
+	}
+
+	wm, err := r.client.delete("/v1/reservation/"+url.PathEscape(name), nil, w)
+	if err != nil {
+		return nil, err
+	}
+	return wm, nil
+}
+
+// Reservation is used to serialize a capacity reservation.
+type Reservation struct {
+	Name        string
+	Namespace   string
+	Datacenters []string
+	NodePool    string
+	Constraints []*Constraint
+	Resources   *ReservationResources
+	Count       int
+	ExpiresAt   time.Time
+	NodeIDs     []string
+	CreateIndex uint64
+	ModifyIndex uint64
+}
+
+// ReservationResources are the resources held by each instance of a
+// reservation.
+type ReservationResources struct {
+	CPU      int
+	MemoryMB int
+	DiskMB   int
+}
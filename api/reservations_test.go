@@ -0,0 +1,37 @@
+package api
+
+import (
+	"testing"
+
+	"github.com/stretchr/testify/require"
+)
+
+func TestReservations_CreateInfoDelete(t *testing.T) {
+	t.Parallel()
+	c, s := makeClient(t, nil, nil)
+	defer s.Stop()
+	reservations := c.Reservations()
+
+	// Reservations that can't be placed are rejected, and there are no
+	// clients in the test server
+	reservation := &Reservation{
+		Name:        "failover",
+		Datacenters: []string{"dc1"},
+		Resources:   &ReservationResources{CPU: 100},
+		Count:       1,
+	}
+	_, _, err := reservations.Create(reservation, nil)
+	require.Error(t, err)
+	require.Contains(t, err.Error(), "failed to place reservation")
+
+	_, _, err = reservations.Info(reservation.Name, nil)
+	require.Error(t, err)
+
+	resp, qm, err := reservations.List(nil)
+	require.NoError(t, err)
+	assertQueryMeta(t, qm)
+	require.Empty(t, resp)
+
+	_, err = reservations.Delete(reservation.Name, nil)
+	require.Error(t, err)
+}
@@ -14,6 +14,7 @@ type Resources struct {
 	DiskMB      *int               `mapstructure:"disk" hcl:"disk,optional"`
 	Networks    []*NetworkResource `hcl:"network,block"`
 	Devices     []*RequestedDevice `hcl:"device,block"`
+	NUMA        *NUMAResource      `hcl:"numa,block"`
 
 	// COMPAT(0.10)
 	// XXX Deprecated. Please do not use. The field will be removed in Nomad
@@ -94,6 +95,16 @@ func (r *Resources) Merge(other *Resources) {
 	if len(other.Devices) != 0 {
 		r.Devices = other.Devices
 	}
+	if other.NUMA != nil {
+		r.NUMA = other.NUMA
+	}
+}
+
+// NUMAResource configures how the reserved cores of a task are placed across
+// the NUMA nodes of a client.
+type NUMAResource struct {
+	// Affinity is one of "none", "prefer" or "require".
+	Affinity string `hcl:"affinity,optional"`
 }
 
 type Port struct {
@@ -116,12 +116,21 @@ type Service struct {
 	CanaryMeta        map[string]string `hcl:"canary_meta,block"`
 	TaskName          string            `mapstructure:"task" hcl:"task,optional"`
 	OnUpdate          string            `mapstructure:"on_update" hcl:"on_update,optional"`
+	Provider          string            `hcl:"provider,optional"`
 }
 
 const (
 	OnUpdateRequireHealthy = "require_healthy"
 	OnUpdateIgnoreWarn     = "ignore_warnings"
 	OnUpdateIgnore         = "ignore"
+
+	// ServiceProviderConsul is the default provider for services when no
+	// parameter is set.
+	ServiceProviderConsul = "consul"
+
+	// ServiceProviderNomad registers the service in the Nomad servers'
+	// built-in service catalog.
+	ServiceProviderNomad = "nomad"
 )
 
 // Canonicalize the Service by ensuring its name and address mode are set. Task
@@ -145,6 +154,11 @@ func (s *Service) Canonicalize(t *Task, tg *TaskGroup, job *Job) {
 		s.OnUpdate = OnUpdateRequireHealthy
 	}
 
+	// Default to the Consul service provider
+	if s.Provider == "" {
+		s.Provider = ServiceProviderConsul
+	}
+
 	s.Connect.Canonicalize()
 
 	// Canonicalize CheckRestart on Checks and merge Service.CheckRestart
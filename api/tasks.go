@@ -180,6 +180,29 @@ func (a *Affinity) Canonicalize() {
 	}
 }
 
+// AllocAffinity is used to serialize alloc affinities, which express
+// placement preferences relative to the allocations of another job
+type AllocAffinity struct {
+	Job       string `hcl:"job,optional"`                        // ID of the job whose allocations are matched
+	TaskGroup string `mapstructure:"group" hcl:"group,optional"` // Optional task group of the job
+	Weight    *int8  `hcl:"weight,optional"`                     // Weight applied to nodes running matching allocations. Can be negative
+	Hard      bool   `hcl:"hard,optional"`                       // Whether nodes that don't satisfy the affinity are infeasible
+}
+
+func NewAllocAffinity(job string, taskGroup string, weight int8) *AllocAffinity {
+	return &AllocAffinity{
+		Job:       job,
+		TaskGroup: taskGroup,
+		Weight:    int8ToPtr(weight),
+	}
+}
+
+func (a *AllocAffinity) Canonicalize() {
+	if a.Weight == nil {
+		a.Weight = int8ToPtr(50)
+	}
+}
+
 func NewDefaultReschedulePolicy(jobType string) *ReschedulePolicy {
 	var dp *ReschedulePolicy
 	switch jobType {
@@ -258,6 +281,8 @@ type Spread struct {
 	Attribute    string          `hcl:"attribute,optional"`
 	Weight       *int8           `hcl:"weight,optional"`
 	SpreadTarget []*SpreadTarget `hcl:"target,block"`
+	MaxSkew      int             `mapstructure:"max_skew" hcl:"max_skew,optional"`
+	Hard         bool            `hcl:"hard,optional"`
 }
 
 // SpreadTarget is used to serialize target allocation spread percentages
@@ -285,6 +310,9 @@ func (s *Spread) Canonicalize() {
 	if s.Weight == nil {
 		s.Weight = int8ToPtr(50)
 	}
+	if s.Hard && s.MaxSkew == 0 {
+		s.MaxSkew = 1
+	}
 }
 
 // EphemeralDisk is an ephemeral disk object
@@ -418,6 +446,7 @@ type TaskGroup struct {
 	Count                     *int                      `hcl:"count,optional"`
 	Constraints               []*Constraint             `hcl:"constraint,block"`
 	Affinities                []*Affinity               `hcl:"affinity,block"`
+	AllocAffinities           []*AllocAffinity          `hcl:"alloc_affinity,block"`
 	Tasks                     []*Task                   `hcl:"task,block"`
 	Spreads                   []*Spread                 `hcl:"spread,block"`
 	Volumes                   map[string]*VolumeRequest `hcl:"volume,block"`
@@ -431,6 +460,9 @@ type TaskGroup struct {
 	Services                  []*Service                `hcl:"service,block"`
 	ShutdownDelay             *time.Duration            `mapstructure:"shutdown_delay" hcl:"shutdown_delay,optional"`
 	StopAfterClientDisconnect *time.Duration            `mapstructure:"stop_after_client_disconnect" hcl:"stop_after_client_disconnect,optional"`
+	MaxClientDisconnect       *time.Duration            `mapstructure:"max_client_disconnect" hcl:"max_client_disconnect,optional"`
+	MaxRunDuration            *time.Duration            `mapstructure:"max_run_duration" hcl:"max_run_duration,optional"`
+	Placement                 *string                   `hcl:"placement,optional"`
 	Scaling                   *ScalingPolicy            `hcl:"scaling,block"`
 	Consul                    *Consul                   `hcl:"consul,block"`
 }
@@ -545,6 +577,9 @@ func (g *TaskGroup) Canonicalize(job *Job) {
 	for _, a := range g.Affinities {
 		a.Canonicalize()
 	}
+	for _, a := range g.AllocAffinities {
+		a.Canonicalize()
+	}
 	for _, n := range g.Networks {
 		n.Canonicalize()
 	}
@@ -602,6 +637,12 @@ func (g *TaskGroup) AddAffinity(a *Affinity) *TaskGroup {
 	return g
 }
 
+// AddAllocAffinity is used to add a new alloc affinity to a task group.
+func (g *TaskGroup) AddAllocAffinity(a *AllocAffinity) *TaskGroup {
+	g.AllocAffinities = append(g.AllocAffinities, a)
+	return g
+}
+
 // RequireDisk adds a ephemeral disk to the task group
 func (g *TaskGroup) RequireDisk(disk *EphemeralDisk) *TaskGroup {
 	g.EphemeralDisk = disk
@@ -616,14 +657,34 @@ func (g *TaskGroup) AddSpread(s *Spread) *TaskGroup {
 
 // LogConfig provides configuration for log rotation
 type LogConfig struct {
-	MaxFiles      *int `mapstructure:"max_files" hcl:"max_files,optional"`
-	MaxFileSizeMB *int `mapstructure:"max_file_size" hcl:"max_file_size,optional"`
+	MaxFiles        *int          `mapstructure:"max_files" hcl:"max_files,optional"`
+	MaxFileSizeMB   *int          `mapstructure:"max_file_size" hcl:"max_file_size,optional"`
+	Sinks           []*LogSink    `mapstructure:"log_sink" hcl:"log_sink,block"`
+	RateLimit       *LogRateLimit `mapstructure:"rate_limit" hcl:"rate_limit,block"`
+	CompressRotated *bool         `mapstructure:"compress_rotated" hcl:"compress_rotated,optional"`
+}
+
+// LogRateLimit limits the rate of a task's output. Output exceeding the limit
+// is dropped.
+type LogRateLimit struct {
+	LinesPerSecond int `mapstructure:"lines_per_second" hcl:"lines_per_second,optional"`
+	BytesPerSecond int `mapstructure:"bytes_per_second" hcl:"bytes_per_second,optional"`
+}
+
+// LogSink is a destination the lines of a task's output are shipped to in
+// addition to the rotated log files.
+type LogSink struct {
+	Type       string `hcl:"type,optional"`
+	Address    string `hcl:"address,optional"`
+	Path       string `hcl:"path,optional"`
+	BufferSize int    `mapstructure:"buffer_size" hcl:"buffer_size,optional"`
 }
 
 func DefaultLogConfig() *LogConfig {
 	return &LogConfig{
-		MaxFiles:      intToPtr(10),
-		MaxFileSizeMB: intToPtr(10),
+		MaxFiles:        intToPtr(10),
+		MaxFileSizeMB:   intToPtr(10),
+		CompressRotated: boolToPtr(false),
 	}
 }
 
@@ -634,6 +695,9 @@ func (l *LogConfig) Canonicalize() {
 	if l.MaxFileSizeMB == nil {
 		l.MaxFileSizeMB = intToPtr(10)
 	}
+	if l.CompressRotated == nil {
+		l.CompressRotated = boolToPtr(false)
+	}
 }
 
 // DispatchPayloadConfig configures how a task gets its input from a job dispatch
@@ -641,6 +705,12 @@ type DispatchPayloadConfig struct {
 	File string `hcl:"file,optional"`
 }
 
+// WorkloadIdentity configures how a task is given its workload identity.
+type WorkloadIdentity struct {
+	Env  bool `hcl:"env,optional"`
+	File bool `hcl:"file,optional"`
+}
+
 const (
 	TaskLifecycleHookPrestart  = "prestart"
 	TaskLifecycleHookPoststart = "poststart"
@@ -677,6 +747,7 @@ type Task struct {
 	Vault           *Vault                 `hcl:"vault,block"`
 	Templates       []*Template            `hcl:"template,block"`
 	DispatchPayload *DispatchPayloadConfig `hcl:"dispatch_payload,block"`
+	Identity        *WorkloadIdentity      `hcl:"identity,block"`
 	VolumeMounts    []*VolumeMount         `hcl:"volume_mount,block"`
 	CSIPluginConfig *TaskCSIPluginConfig   `mapstructure:"csi_plugin" json:",omitempty" hcl:"csi_plugin,block"`
 	Leader          bool                   `hcl:"leader,optional"`
@@ -778,6 +849,7 @@ type Template struct {
 	EmbeddedTmpl *string        `mapstructure:"data" hcl:"data,optional"`
 	ChangeMode   *string        `mapstructure:"change_mode" hcl:"change_mode,optional"`
 	ChangeSignal *string        `mapstructure:"change_signal" hcl:"change_signal,optional"`
+	ChangeScript *ChangeScript  `mapstructure:"change_script" hcl:"change_script,block"`
 	Splay        *time.Duration `mapstructure:"splay" hcl:"splay,optional"`
 	Perms        *string        `mapstructure:"perms" hcl:"perms,optional"`
 	LeftDelim    *string        `mapstructure:"left_delimiter" hcl:"left_delimiter,optional"`
@@ -809,6 +881,9 @@ func (tmpl *Template) Canonicalize() {
 		sig := *tmpl.ChangeSignal
 		tmpl.ChangeSignal = stringToPtr(strings.ToUpper(sig))
 	}
+	if tmpl.ChangeScript != nil {
+		tmpl.ChangeScript.Canonicalize()
+	}
 	if tmpl.Splay == nil {
 		tmpl.Splay = timeToPtr(5 * time.Second)
 	}
@@ -831,6 +906,30 @@ func (tmpl *Template) Canonicalize() {
 	}
 }
 
+// ChangeScript is the script executed within the task when a template with
+// change_mode "script" is re-rendered.
+type ChangeScript struct {
+	Command     *string        `mapstructure:"command" hcl:"command"`
+	Args        []string       `mapstructure:"args" hcl:"args,optional"`
+	Timeout     *time.Duration `mapstructure:"timeout" hcl:"timeout,optional"`
+	FailOnError *bool          `mapstructure:"fail_on_error" hcl:"fail_on_error,optional"`
+}
+
+func (cs *ChangeScript) Canonicalize() {
+	if cs.Command == nil {
+		cs.Command = stringToPtr("")
+	}
+	if cs.Args == nil {
+		cs.Args = []string{}
+	}
+	if cs.Timeout == nil {
+		cs.Timeout = timeToPtr(5 * time.Second)
+	}
+	if cs.FailOnError == nil {
+		cs.FailOnError = boolToPtr(false)
+	}
+}
+
 type Vault struct {
 	Policies     []string `hcl:"policies,optional"`
 	Namespace    *string  `mapstructure:"namespace" hcl:"namespace,optional"`
@@ -406,6 +406,23 @@ func TestTask_Canonicalize_TaskLifecycle(t *testing.T) {
 	}
 }
 
+func TestTemplate_Canonicalize_ChangeScript(t *testing.T) {
+	tmpl := &Template{
+		ChangeMode: stringToPtr("script"),
+		ChangeScript: &ChangeScript{
+			Command: stringToPtr("/bin/reload"),
+		},
+	}
+	tmpl.Canonicalize()
+
+	require.Equal(t, &ChangeScript{
+		Command:     stringToPtr("/bin/reload"),
+		Args:        []string{},
+		Timeout:     timeToPtr(5 * time.Second),
+		FailOnError: boolToPtr(false),
+	}, tmpl.ChangeScript)
+}
+
 // Ensures no regression on https://github.com/hashicorp/nomad/issues/3132
 func TestTaskGroup_Canonicalize_Update(t *testing.T) {
 	// Job with an Empty() Update
@@ -0,0 +1,323 @@
+package api
+
+import (
+	"bytes"
+	"errors"
+	"fmt"
+	"io"
+	"net/http"
+	"net/url"
+	"strconv"
+	"strings"
+)
+
+var (
+	// ErrVariableNotFound is returned when a variable does not exist at the
+	// requested path.
+	ErrVariableNotFound = errors.New("variable not found")
+
+	// ErrVariableMissingItems is returned when attempting to write a
+	// variable without any items.
+	ErrVariableMissingItems = errors.New("variable missing items")
+)
+
+// Variables is used to access variables.
+type Variables struct {
+	client *Client
+}
+
+// Variable specifies the metadata and contents to be stored in the encrypted
+// Nomad backend.
+type Variable struct {
+	// Namespace is the Nomad namespace associated with the variable
+	Namespace string
+
+	// Path is the path to the variable
+	Path string
+
+	// Raft indexes to track creation and modification
+	CreateIndex uint64
+	ModifyIndex uint64
+
+	// Times provided as a convenience for operators expressed as time.UnixNanos
+	CreateTime int64
+	ModifyTime int64
+
+	// Items are the key/value pairs stored in the variable. They are
+	// encrypted as a single unit.
+	Items VariableItems
+}
+
+// VariableMetadata specifies the metadata for a variable and is used as the
+// list object.
+type VariableMetadata struct {
+	// Namespace is the Nomad namespace associated with the variable
+	Namespace string
+
+	// Path is the path to the variable
+	Path string
+
+	// Raft indexes to track creation and modification
+	CreateIndex uint64
+	ModifyIndex uint64
+
+	// Times provided as a convenience for operators expressed as time.UnixNanos
+	CreateTime int64
+	ModifyTime int64
+}
+
+// VariableItems are the key/value pairs of a variable.
+type VariableItems map[string]string
+
+// ErrCASConflict is returned when a check-and-set write or delete fails
+// because the variable was modified since it was read. Conflict holds the
+// current value of the variable; its Items are empty when the caller does not
+// have permission to read it.
+type ErrCASConflict struct {
+	CheckIndex uint64
+	Conflict   *Variable
+}
+
+func (e ErrCASConflict) Error() string {
+	return fmt.Sprintf("cas conflict: expected ModifyIndex %v; found %v", e.CheckIndex, e.Conflict.ModifyIndex)
+}
+
+// Variables returns a new handle on the variables.
+func (c *Client) Variables() *Variables {
+	return &Variables{client: c}
+}
+
+// NewVariable generates a new empty variable at the specified path.
+func NewVariable(path string) *Variable {
+	return &Variable{
+		Path:  path,
+		Items: make(VariableItems),
+	}
+}
+
+// Copy returns a new deep copy of the variable.
+func (sv1 *Variable) Copy() *Variable {
+	if sv1 == nil {
+		return nil
+	}
+	var out Variable = *sv1
+	out.Items = make(VariableItems, len(sv1.Items))
+	for k, v := range sv1.Items {
+		out.Items[k] = v
+	}
+	return &out
+}
+
+// Metadata returns the VariableMetadata component of a variable.
+func (sv *Variable) Metadata() *VariableMetadata {
+	return &VariableMetadata{
+		Namespace:   sv.Namespace,
+		Path:        sv.Path,
+		CreateIndex: sv.CreateIndex,
+		ModifyIndex: sv.ModifyIndex,
+		CreateTime:  sv.CreateTime,
+		ModifyTime:  sv.ModifyTime,
+	}
+}
+
+// List is used to dump all of the variables, can be used to pass prefix via
+// QueryOptions rather than as a parameter.
+func (vars *Variables) List(q *QueryOptions) ([]*VariableMetadata, *QueryMeta, error) {
+	var resp []*VariableMetadata
+	qm, err := vars.client.query("/v1/vars", &resp, q)
+	if err != nil {
+		return nil, nil, err
+	}
+	return resp, qm, nil
+}
+
+// PrefixList is used to do a prefix List search over variables.
+func (vars *Variables) PrefixList(prefix string, q *QueryOptions) ([]*VariableMetadata, *QueryMeta, error) {
+	if q == nil {
+		q = &QueryOptions{Prefix: prefix}
+	} else {
+		q.Prefix = prefix
+	}
+	return vars.List(q)
+}
+
+// Read is used to query a single variable by path. It returns
+// ErrVariableNotFound if no variable exists at the path.
+func (vars *Variables) Read(path string, q *QueryOptions) (*Variable, *QueryMeta, error) {
+	path, err := cleanPathString(path)
+	if err != nil {
+		return nil, nil, err
+	}
+
+	r, err := vars.client.newRequest("GET", "/v1/var/"+path)
+	if err != nil {
+		return nil, nil, err
+	}
+	r.setQueryOptions(q)
+
+	rtt, resp, err := vars.client.doRequest(r)
+	if err != nil {
+		return nil, nil, err
+	}
+	defer resp.Body.Close()
+
+	switch resp.StatusCode {
+	case http.StatusOK:
+	case http.StatusNotFound:
+		return nil, nil, ErrVariableNotFound
+	default:
+		return nil, nil, unexpectedResponse(resp)
+	}
+
+	qm := &QueryMeta{}
+	parseQueryMeta(resp, qm)
+	qm.RequestTime = rtt
+
+	var out Variable
+	if err := decodeBody(resp, &out); err != nil {
+		return nil, nil, err
+	}
+	return &out, qm, nil
+}
+
+// Create is used to create a variable, overwriting any existing value at
+// the path.
+func (vars *Variables) Create(v *Variable, qo *WriteOptions) (*Variable, *WriteMeta, error) {
+	return vars.put(v, nil, qo)
+}
+
+// Update is used to update a variable, overwriting any existing value at
+// the path.
+func (vars *Variables) Update(v *Variable, qo *WriteOptions) (*Variable, *WriteMeta, error) {
+	return vars.put(v, nil, qo)
+}
+
+// CheckedUpdate is used to update a variable if the modify index of the
+// variable matches the ModifyIndex of the passed variable. A ModifyIndex of
+// zero only creates the variable if none exists at the path. It returns an
+// ErrCASConflict holding the current value on a conflict.
+func (vars *Variables) CheckedUpdate(v *Variable, qo *WriteOptions) (*Variable, *WriteMeta, error) {
+	checkIndex := v.ModifyIndex
+	return vars.put(v, &checkIndex, qo)
+}
+
+// Delete is used to delete a variable.
+func (vars *Variables) Delete(path string, qo *WriteOptions) (*WriteMeta, error) {
+	return vars.delete(path, nil, qo)
+}
+
+// CheckedDelete is used to delete a variable if its modify index matches
+// checkIndex. It returns an ErrCASConflict holding the current value on a
+// conflict.
+func (vars *Variables) CheckedDelete(path string, checkIndex uint64, qo *WriteOptions) (*WriteMeta, error) {
+	return vars.delete(path, &checkIndex, qo)
+}
+
+func (vars *Variables) put(v *Variable, checkIndex *uint64, qo *WriteOptions) (*Variable, *WriteMeta, error) {
+	if v == nil {
+		return nil, nil, errors.New("must pass non-nil variable")
+	}
+	if len(v.Items) == 0 {
+		return nil, nil, ErrVariableMissingItems
+	}
+
+	path, err := cleanPathString(v.Path)
+	if err != nil {
+		return nil, nil, err
+	}
+
+	r, err := vars.client.newRequest("PUT", "/v1/var/"+path)
+	if err != nil {
+		return nil, nil, err
+	}
+	r.setWriteOptions(qo)
+	r.obj = v
+	if checkIndex != nil {
+		r.params.Set("cas", strconv.FormatUint(*checkIndex, 10))
+	}
+
+	var out Variable
+	wm, err := vars.doWrite(r, checkIndex, &out)
+	if err != nil {
+		return nil, nil, err
+	}
+	return &out, wm, nil
+}
+
+func (vars *Variables) delete(path string, checkIndex *uint64, qo *WriteOptions) (*WriteMeta, error) {
+	path, err := cleanPathString(path)
+	if err != nil {
+		return nil, err
+	}
+
+	r, err := vars.client.newRequest("DELETE", "/v1/var/"+path)
+	if err != nil {
+		return nil, err
+	}
+	r.setWriteOptions(qo)
+	if checkIndex != nil {
+		r.params.Set("cas", strconv.FormatUint(*checkIndex, 10))
+	}
+
+	return vars.doWrite(r, checkIndex, nil)
+}
+
+// doWrite performs the write request, converting a 409 response into an
+// ErrCASConflict.
+func (vars *Variables) doWrite(r *request, checkIndex *uint64, out interface{}) (*WriteMeta, error) {
+	rtt, resp, err := vars.client.doRequest(r)
+	if err != nil {
+		return nil, err
+	}
+	defer resp.Body.Close()
+
+	switch resp.StatusCode {
+	case http.StatusOK:
+	case http.StatusConflict:
+		conflict := new(Variable)
+		if err := decodeBody(resp, conflict); err != nil {
+			return nil, err
+		}
+		e := ErrCASConflict{Conflict: conflict}
+		if checkIndex != nil {
+			e.CheckIndex = *checkIndex
+		}
+		return nil, e
+	default:
+		return nil, unexpectedResponse(resp)
+	}
+
+	wm := &WriteMeta{RequestTime: rtt}
+	parseWriteMeta(resp, wm)
+
+	if out != nil && resp.ContentLength != 0 {
+		if err := decodeBody(resp, out); err != nil {
+			return nil, err
+		}
+	}
+	return wm, nil
+}
+
+// unexpectedResponse builds the same error requireOK returns for non-200
+// responses.
+func unexpectedResponse(resp *http.Response) error {
+	var buf bytes.Buffer
+	io.Copy(&buf, resp.Body)
+	return fmt.Errorf("Unexpected response code: %d (%s)", resp.StatusCode, buf.Bytes())
+}
+
+// cleanPathString removes leading and trailing slashes from the path and
+// escapes each of its segments, since they would otherwise create empty path
+// segments.
+func cleanPathString(path string) (string, error) {
+	path = strings.Trim(path, " /")
+	if path == "" {
+		return "", errors.New("variable path is required")
+	}
+
+	segments := strings.Split(path, "/")
+	for i, segment := range segments {
+		segments[i] = url.PathEscape(segment)
+	}
+	return strings.Join(segments, "/"), nil
+}
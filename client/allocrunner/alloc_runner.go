@@ -15,6 +15,7 @@ import (
 	"github.com/hashicorp/nomad/client/allocrunner/interfaces"
 	"github.com/hashicorp/nomad/client/allocrunner/state"
 	"github.com/hashicorp/nomad/client/allocrunner/taskrunner"
+	"github.com/hashicorp/nomad/client/allocrunner/taskrunner/getter"
 	"github.com/hashicorp/nomad/client/allocwatcher"
 	"github.com/hashicorp/nomad/client/config"
 	"github.com/hashicorp/nomad/client/consul"
@@ -175,6 +176,9 @@ type allocRunner struct {
 	// rpcClient is the RPC Client that should be used by the allocrunner and its
 	// hooks to communicate with Nomad Servers.
 	rpcClient RPCer
+
+	// artifactCache is the client's artifact cache, or nil if disabled
+	artifactCache *getter.Cache
 }
 
 // RPCer is the interface needed by hooks to make RPC calls.
@@ -218,6 +222,7 @@ func NewAllocRunner(config *Config) (*allocRunner, error) {
 		driverManager:            config.DriverManager,
 		serversContactedCh:       config.ServersContactedCh,
 		rpcClient:                config.RPCClient,
+		artifactCache:            config.ArtifactCache,
 	}
 
 	// Create the logger based on the allocation ID
@@ -266,6 +271,8 @@ func (ar *allocRunner) initTaskRunners(tasks []*structs.Task) error {
 			DriverManager:        ar.driverManager,
 			ServersContactedCh:   ar.serversContactedCh,
 			StartConditionMetCtx: ar.taskHookCoordinator.startConditionForTask(task),
+			ArtifactCache:        ar.artifactCache,
+			RPCClient:            ar.rpcClient,
 		}
 
 		if ar.cpusetManager != nil {
@@ -868,6 +875,13 @@ func (ar *allocRunner) handleAllocUpdate(update *structs.Allocation) {
 		ar.killTasks()
 	}
 
+	// The servers marked the alloc unknown while the client was
+	// disconnected, so send its current state to let the scheduler
+	// reconcile it.
+	if update.ClientStatus == structs.AllocClientStatusUnknown && !update.TerminalStatus() {
+		ar.logger.Debug("reconnecting allocation", "modify_index", update.AllocModifyIndex)
+		ar.TaskStateUpdated()
+	}
 }
 
 func (ar *allocRunner) Listener() *cstructs.AllocListener {
@@ -24,6 +24,8 @@ type networkStatusGetter interface {
 // deregistration.
 type groupServiceHook struct {
 	allocID             string
+	jobID               string
+	namespace           string
 	group               string
 	restarter           agentconsul.WorkloadRestarter
 	consulClient        consul.ConsulServiceAPI
@@ -67,6 +69,8 @@ func newGroupServiceHook(cfg groupServiceHookConfig) *groupServiceHook {
 
 	h := &groupServiceHook{
 		allocID:             cfg.alloc.ID,
+		jobID:               cfg.alloc.JobID,
+		namespace:           cfg.alloc.Namespace,
 		group:               cfg.alloc.TaskGroup,
 		restarter:           cfg.restarter,
 		consulClient:        cfg.consul,
@@ -222,6 +226,8 @@ func (h *groupServiceHook) getWorkloadServices() *agentconsul.WorkloadServices {
 	// Create task services struct with request's driver metadata
 	return &agentconsul.WorkloadServices{
 		AllocID:         h.allocID,
+		JobID:           h.jobID,
+		Namespace:       h.namespace,
 		Group:           h.group,
 		ConsulNamespace: h.consulNamespace,
 		Restarter:       h.restarter,
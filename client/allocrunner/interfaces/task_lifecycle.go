@@ -56,6 +56,9 @@ type TaskPrestartRequest struct {
 	// Vault token may optionally be set if a Vault token is available
 	VaultToken string
 
+	// NomadToken is the task's current workload identity, if any
+	NomadToken string
+
 	// TaskDir contains the task's directory tree on the host
 	TaskDir *allocdir.TaskDir
 
@@ -151,6 +154,9 @@ type TaskExitedHook interface {
 type TaskUpdateRequest struct {
 	VaultToken string
 
+	// NomadToken is the task's current workload identity, if any
+	NomadToken string
+
 	// Alloc is the current version of the allocation (may have been
 	// updated since the hook was created)
 	Alloc *structs.Allocation
@@ -8,20 +8,33 @@ import (
 	"github.com/hashicorp/nomad/client/allocrunner/interfaces"
 	"github.com/hashicorp/nomad/client/allocrunner/taskrunner/getter"
 	ti "github.com/hashicorp/nomad/client/allocrunner/taskrunner/interfaces"
+	"github.com/hashicorp/nomad/client/config"
 	"github.com/hashicorp/nomad/nomad/structs"
 )
 
 // artifactHook downloads artifacts for a task.
 type artifactHook struct {
 	eventEmitter ti.EventEmitter
-	logger       log.Logger
+
+	// cache is the client's artifact cache, or nil if disabled
+	cache *getter.Cache
+
+	// sandbox downloads artifacts in a separate process, or is nil if
+	// disabled
+	sandbox *getter.Sandbox
+
+	logger log.Logger
 }
 
-func newArtifactHook(e ti.EventEmitter, logger log.Logger) *artifactHook {
+func newArtifactHook(e ti.EventEmitter, cache *getter.Cache, config *config.ArtifactConfig, logger log.Logger) *artifactHook {
 	h := &artifactHook{
 		eventEmitter: e,
+		cache:        cache,
 	}
 	h.logger = logger.Named(h.Name())
+	if config != nil && !config.DisableSandbox {
+		h.sandbox = getter.NewSandbox(config, h.logger)
+	}
 	return h
 }
 
@@ -52,7 +65,7 @@ func (h *artifactHook) Prestart(ctx context.Context, req *interfaces.TaskPrestar
 
 		h.logger.Debug("downloading artifact", "artifact", artifact.GetterSource)
 		//XXX add ctx to GetArtifact to allow cancelling long downloads
-		if err := getter.GetArtifact(req.TaskEnv, artifact); err != nil {
+		if err := h.getArtifact(req.TaskEnv, artifact); err != nil {
 
 			wrapped := structs.NewRecoverableError(
 				fmt.Errorf("failed to download artifact %q: %v", artifact.GetterSource, err),
@@ -72,3 +85,25 @@ func (h *artifactHook) Prestart(ctx context.Context, req *interfaces.TaskPrestar
 	resp.Done = true
 	return nil
 }
+
+// getArtifact downloads the artifact into the task directory, through the
+// artifact cache if enabled, and emits an event for cached artifacts.
+func (h *artifactHook) getArtifact(taskEnv getter.EnvReplacer, artifact *structs.TaskArtifact) error {
+	if h.cache == nil {
+		return getter.GetArtifact(taskEnv, artifact, h.sandbox)
+	}
+
+	cached, hit, err := h.cache.GetArtifact(taskEnv, artifact, h.sandbox)
+	if err != nil || !cached {
+		return err
+	}
+
+	if hit {
+		h.eventEmitter.EmitEvent(structs.NewTaskEvent(structs.TaskArtifactCacheHit).
+			SetMessage(fmt.Sprintf("Using cached artifact %q", artifact.GetterSource)))
+	} else {
+		h.eventEmitter.EmitEvent(structs.NewTaskEvent(structs.TaskArtifactCached).
+			SetMessage(fmt.Sprintf("Downloaded artifact %q into the cache", artifact.GetterSource)))
+	}
+	return nil
+}
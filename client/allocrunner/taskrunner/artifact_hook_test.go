@@ -2,16 +2,20 @@ package taskrunner
 
 import (
 	"context"
+	"crypto/md5"
+	"fmt"
 	"io/ioutil"
 	"net/http"
 	"net/http/httptest"
 	"os"
 	"path/filepath"
 	"sort"
+	"sync"
 	"testing"
 
 	"github.com/hashicorp/nomad/client/allocdir"
 	"github.com/hashicorp/nomad/client/allocrunner/interfaces"
+	"github.com/hashicorp/nomad/client/allocrunner/taskrunner/getter"
 	"github.com/hashicorp/nomad/client/taskenv"
 	"github.com/hashicorp/nomad/helper"
 	"github.com/hashicorp/nomad/helper/testlog"
@@ -23,20 +27,29 @@ import (
 var _ interfaces.TaskPrestartHook = (*artifactHook)(nil)
 
 type mockEmitter struct {
+	lock   sync.Mutex
 	events []*structs.TaskEvent
 }
 
 func (m *mockEmitter) EmitEvent(ev *structs.TaskEvent) {
+	m.lock.Lock()
+	defer m.lock.Unlock()
 	m.events = append(m.events, ev)
 }
 
+func (m *mockEmitter) Events() []*structs.TaskEvent {
+	m.lock.Lock()
+	defer m.lock.Unlock()
+	return append([]*structs.TaskEvent(nil), m.events...)
+}
+
 // TestTaskRunner_ArtifactHook_Recoverable asserts that failures to download
 // artifacts are a recoverable error.
 func TestTaskRunner_ArtifactHook_Recoverable(t *testing.T) {
 	t.Parallel()
 
 	me := &mockEmitter{}
-	artifactHook := newArtifactHook(me, testlog.HCLogger(t))
+	artifactHook := newArtifactHook(me, nil, nil, testlog.HCLogger(t))
 
 	req := &interfaces.TaskPrestartRequest{
 		TaskEnv: taskenv.NewEmptyTaskEnv(),
@@ -69,7 +82,7 @@ func TestTaskRunner_ArtifactHook_PartialDone(t *testing.T) {
 	t.Parallel()
 
 	me := &mockEmitter{}
-	artifactHook := newArtifactHook(me, testlog.HCLogger(t))
+	artifactHook := newArtifactHook(me, nil, nil, testlog.HCLogger(t))
 
 	// Create a source directory with 1 of the 2 artifacts
 	srcdir, err := ioutil.TempDir("", "nomadtest-src")
@@ -158,3 +171,63 @@ func TestTaskRunner_ArtifactHook_PartialDone(t *testing.T) {
 	require.True(t, resp.Done)
 	require.Len(t, resp.State, 2)
 }
+
+// TestTaskRunner_ArtifactHook_Cache asserts that artifacts with a checksum are
+// installed from the artifact cache and emit events for it.
+func TestTaskRunner_ArtifactHook_Cache(t *testing.T) {
+	t.Parallel()
+
+	srcdir, err := ioutil.TempDir("", "nomadtest-src")
+	require.NoError(t, err)
+	defer os.RemoveAll(srcdir)
+
+	contents := []byte("hello world\n")
+	require.NoError(t, ioutil.WriteFile(filepath.Join(srcdir, "foo.txt"), contents, 0644))
+
+	ts := httptest.NewServer(http.FileServer(http.Dir(srcdir)))
+	defer ts.Close()
+
+	cacheDir, err := ioutil.TempDir("", "nomadtest-cache")
+	require.NoError(t, err)
+	defer os.RemoveAll(cacheDir)
+
+	cache, err := getter.NewCache(cacheDir, 1024*1024, testlog.HCLogger(t))
+	require.NoError(t, err)
+
+	me := &mockEmitter{}
+	artifactHook := newArtifactHook(me, cache, nil, testlog.HCLogger(t))
+
+	artifact := &structs.TaskArtifact{
+		GetterSource: ts.URL + "/foo.txt",
+		GetterOptions: map[string]string{
+			"checksum": fmt.Sprintf("md5:%x", md5.Sum(contents)),
+		},
+		GetterMode: structs.GetterModeAny,
+	}
+
+	for _, expected := range []string{structs.TaskArtifactCached, structs.TaskArtifactCacheHit} {
+		destdir, err := ioutil.TempDir("", "nomadtest-dest")
+		require.NoError(t, err)
+		defer os.RemoveAll(destdir)
+
+		req := &interfaces.TaskPrestartRequest{
+			TaskEnv: taskenv.NewTaskEnv(nil, nil, nil, nil, destdir, ""),
+			TaskDir: &allocdir.TaskDir{Dir: destdir},
+			Task: &structs.Task{
+				Artifacts: []*structs.TaskArtifact{artifact},
+			},
+		}
+		resp := interfaces.TaskPrestartResponse{}
+
+		require.NoError(t, artifactHook.Prestart(context.Background(), req, &resp))
+		require.True(t, resp.Done)
+
+		b, err := ioutil.ReadFile(filepath.Join(destdir, "foo.txt"))
+		require.NoError(t, err)
+		require.Equal(t, contents, b)
+
+		events := me.Events()
+		require.Equal(t, expected, events[len(events)-1].Type)
+		require.Contains(t, events[len(events)-1].Message, artifact.GetterSource)
+	}
+}
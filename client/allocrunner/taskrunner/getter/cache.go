@@ -0,0 +1,493 @@
+package getter
+
+import (
+	"crypto/sha256"
+	"encoding/hex"
+	"fmt"
+	"io"
+	"io/ioutil"
+	"os"
+	"path/filepath"
+	"sort"
+	"sync"
+	"time"
+
+	hclog "github.com/hashicorp/go-hclog"
+	"github.com/hashicorp/nomad/helper"
+	"github.com/hashicorp/nomad/nomad/structs"
+)
+
+const (
+	// cacheDataName is the name of the downloaded artifact within the
+	// directory of a cache entry
+	cacheDataName = "data"
+
+	// cacheTmpPrefix prefixes the names of artifacts being downloaded and of
+	// evicted entries being removed. They are removed when the cache is
+	// created.
+	cacheTmpPrefix = ".tmp-"
+)
+
+// Cache is a node-wide cache of downloaded artifacts. Artifacts are keyed by
+// their URL, including their checksum, their mode and their headers, so only
+// artifacts with a checksum are cached. Cached artifacts are copied into task
+// directories, so tasks can't modify the cached copy other tasks get. The
+// least recently used artifacts are evicted when the cache exceeds its
+// maximum size.
+type Cache struct {
+	dir     string
+	maxSize int64
+	logger  hclog.Logger
+
+	// lock guards the fields below
+	lock sync.Mutex
+
+	// entries are the cached artifacts by key
+	entries map[string]*cacheEntry
+
+	// size is the total size of the cached artifacts
+	size int64
+
+	// downloads are the downloads in progress by key
+	downloads map[string]*cacheDownload
+
+	// now returns the current time and is overridden in tests
+	now func() time.Time
+}
+
+// cacheEntry is a cached artifact
+type cacheEntry struct {
+	key      string
+	size     int64
+	lastUsed time.Time
+
+	// refs is the number of tasks installing the artifact. Entries in use
+	// aren't evicted.
+	refs int
+}
+
+// cacheDownload is an artifact being downloaded into the cache
+type cacheDownload struct {
+	doneCh chan struct{}
+	err    error
+}
+
+// NewCache returns a cache of artifacts stored in dir and limited to maxSize
+// bytes. Artifacts cached by a previous cache in the same directory are kept.
+func NewCache(dir string, maxSize int64, logger hclog.Logger) (*Cache, error) {
+	if err := os.MkdirAll(dir, 0700); err != nil {
+		return nil, fmt.Errorf("failed to create artifact cache directory: %v", err)
+	}
+
+	c := &Cache{
+		dir:       dir,
+		maxSize:   maxSize,
+		logger:    logger.Named("artifact_cache"),
+		entries:   make(map[string]*cacheEntry),
+		downloads: make(map[string]*cacheDownload),
+		now:       time.Now,
+	}
+	if err := c.load(); err != nil {
+		return nil, err
+	}
+
+	c.lock.Lock()
+	c.evictLocked(c.maxSize)
+	c.lock.Unlock()
+	return c, nil
+}
+
+// load adds the artifacts found in the cache directory to the cache and
+// removes anything else.
+func (c *Cache) load() error {
+	files, err := ioutil.ReadDir(c.dir)
+	if err != nil {
+		return fmt.Errorf("failed to list artifact cache directory: %v", err)
+	}
+
+	for _, fi := range files {
+		path := filepath.Join(c.dir, fi.Name())
+		if !fi.IsDir() || !isCacheKey(fi.Name()) {
+			if err := os.RemoveAll(path); err != nil {
+				c.logger.Warn("failed to remove file from artifact cache", "path", path, "error", err)
+			}
+			continue
+		}
+
+		size, err := dirSize(path)
+		if err != nil {
+			c.logger.Warn("failed to load cached artifact", "path", path, "error", err)
+			continue
+		}
+		c.entries[fi.Name()] = &cacheEntry{
+			key:      fi.Name(),
+			size:     size,
+			lastUsed: fi.ModTime(),
+		}
+		c.size += size
+	}
+	return nil
+}
+
+// GetArtifact installs an artifact into the specified task directory from the
+// cache, downloading it into the cache first if needed. Artifacts that can't
+// be cached are downloaded directly. Artifacts are downloaded in the sandbox if
+// not nil. It returns whether the artifact was cached, and whether it was
+// already cached.
+func (c *Cache) GetArtifact(taskEnv EnvReplacer, artifact *structs.TaskArtifact, sandbox *Sandbox) (cached bool, hit bool, err error) {
+	req, err := newArtifactRequest(taskEnv, artifact, sandbox)
+	if err != nil {
+		return false, false, err
+	}
+
+	if artifact.GetterOptions["checksum"] == "" {
+		return false, false, req.get(req.dest)
+	}
+
+	entry, hit, err := c.acquire(req)
+	if err != nil {
+		return true, false, err
+	}
+	defer c.release(entry)
+
+	src := filepath.Join(c.dir, entry.key, cacheDataName)
+	if err := installArtifact(src, req.dest, req.installRoot(req.dest)); err != nil {
+		return true, hit, newGetError(req.url, fmt.Errorf("failed to install cached artifact: %v", err), true)
+	}
+	return true, hit, nil
+}
+
+// acquire returns the cache entry of the artifact, downloading it if it isn't
+// cached, and whether it was cached. The entry must be released once
+// installed.
+func (c *Cache) acquire(req *artifactRequest) (*cacheEntry, bool, error) {
+	key := cacheKey(req)
+	for {
+		c.lock.Lock()
+		if entry, ok := c.entries[key]; ok {
+			entry.refs++
+			entry.lastUsed = c.now()
+			c.lock.Unlock()
+
+			// Persist the last use for when the cache is reloaded
+			path := filepath.Join(c.dir, key)
+			if err := os.Chtimes(path, entry.lastUsed, entry.lastUsed); err != nil {
+				c.logger.Debug("failed to update cached artifact access time", "path", path, "error", err)
+			}
+			return entry, true, nil
+		}
+
+		// Wait for a download of the same artifact in progress
+		if download, ok := c.downloads[key]; ok {
+			c.lock.Unlock()
+			<-download.doneCh
+			if download.err != nil {
+				return nil, false, download.err
+			}
+			continue
+		}
+
+		download := &cacheDownload{doneCh: make(chan struct{})}
+		c.downloads[key] = download
+		c.lock.Unlock()
+
+		entry, err := c.download(key, req)
+
+		c.lock.Lock()
+		delete(c.downloads, key)
+		if err == nil {
+			entry.refs++
+			c.entries[key] = entry
+			c.size += entry.size
+			c.evictLocked(c.maxSize)
+		}
+		download.err = err
+		close(download.doneCh)
+		c.lock.Unlock()
+
+		return entry, false, err
+	}
+}
+
+// download downloads the artifact into the cache directory of the key.
+func (c *Cache) download(key string, req *artifactRequest) (*cacheEntry, error) {
+	tmpDir, err := ioutil.TempDir(c.dir, cacheTmpPrefix)
+	if err != nil {
+		return nil, newGetError(req.url, fmt.Errorf("failed to create artifact cache directory: %v", err), true)
+	}
+	defer os.RemoveAll(tmpDir)
+
+	c.logger.Debug("downloading artifact into cache", "key", key)
+	if err := req.get(filepath.Join(tmpDir, cacheDataName)); err != nil {
+		return nil, err
+	}
+
+	size, err := dirSize(tmpDir)
+	if err != nil {
+		return nil, newGetError(req.url, fmt.Errorf("failed to cache artifact: %v", err), true)
+	}
+
+	if err := os.Rename(tmpDir, filepath.Join(c.dir, key)); err != nil {
+		return nil, newGetError(req.url, fmt.Errorf("failed to cache artifact: %v", err), true)
+	}
+
+	return &cacheEntry{
+		key:      key,
+		size:     size,
+		lastUsed: c.now(),
+	}, nil
+}
+
+// release marks the entry as no longer in use.
+func (c *Cache) release(entry *cacheEntry) {
+	c.lock.Lock()
+	defer c.lock.Unlock()
+	entry.refs--
+	c.evictLocked(c.maxSize)
+}
+
+// EvictOldest evicts the least recently used artifact not in use. It returns
+// false if no artifact could be evicted. The artifact is removed from disk
+// before returning, so that its space is free once the caller checks again.
+func (c *Cache) EvictOldest() bool {
+	c.lock.Lock()
+	entry := c.oldestLocked()
+	if entry == nil {
+		c.lock.Unlock()
+		return false
+	}
+	trash := c.trashLocked(entry)
+	c.lock.Unlock()
+
+	if trash != "" {
+		c.removeTrash(trash)
+	}
+	return true
+}
+
+// Size returns the total size of the cached artifacts.
+func (c *Cache) Size() int64 {
+	c.lock.Lock()
+	defer c.lock.Unlock()
+	return c.size
+}
+
+// evictLocked evicts the least recently used artifacts not in use until the
+// cache size is at most size. The lock must be held.
+func (c *Cache) evictLocked(size int64) {
+	for c.size > size {
+		entry := c.oldestLocked()
+		if entry == nil {
+			return
+		}
+		c.removeLocked(entry)
+	}
+}
+
+// oldestLocked returns the least recently used entry not in use, or nil if
+// all entries are in use. The lock must be held.
+func (c *Cache) oldestLocked() *cacheEntry {
+	var oldest *cacheEntry
+	for _, entry := range c.entries {
+		if entry.refs > 0 {
+			continue
+		}
+		if oldest == nil || entry.lastUsed.Before(oldest.lastUsed) {
+			oldest = entry
+		}
+	}
+	return oldest
+}
+
+// removeLocked removes the entry from the cache. Its directory is renamed
+// under the lock so that the key can be downloaded again right away, and
+// removed in the background. The lock must be held.
+func (c *Cache) removeLocked(entry *cacheEntry) {
+	if trash := c.trashLocked(entry); trash != "" {
+		go c.removeTrash(trash)
+	}
+}
+
+// trashLocked removes the entry from the cache and renames its directory,
+// returning the new path to remove or an empty string if it couldn't be
+// renamed. The lock must be held.
+func (c *Cache) trashLocked(entry *cacheEntry) string {
+	delete(c.entries, entry.key)
+	c.size -= entry.size
+	c.logger.Debug("evicting cached artifact", "key", entry.key, "size", entry.size)
+
+	path := filepath.Join(c.dir, entry.key)
+	trash := filepath.Join(c.dir, fmt.Sprintf("%s%s-%d", cacheTmpPrefix, entry.key, c.now().UnixNano()))
+	if err := os.Rename(path, trash); err != nil {
+		c.logger.Warn("failed to evict cached artifact", "path", path, "error", err)
+		return ""
+	}
+	return trash
+}
+
+// removeTrash removes the renamed directory of an evicted entry.
+func (c *Cache) removeTrash(trash string) {
+	if err := os.RemoveAll(trash); err != nil {
+		c.logger.Warn("failed to remove evicted artifact", "path", trash, "error", err)
+	}
+}
+
+// cacheKey returns the key of the artifact requested.
+func cacheKey(req *artifactRequest) string {
+	h := sha256.New()
+	fmt.Fprintf(h, "%s\n%d\n", req.url, req.mode)
+
+	names := make([]string, 0, len(req.headers))
+	for name := range req.headers {
+		names = append(names, name)
+	}
+	sort.Strings(names)
+	for _, name := range names {
+		for _, value := range req.headers[name] {
+			fmt.Fprintf(h, "%s: %s\n", name, value)
+		}
+	}
+	return hex.EncodeToString(h.Sum(nil))
+}
+
+// isCacheKey returns whether name is a cache key.
+func isCacheKey(name string) bool {
+	if len(name) != sha256.Size*2 {
+		return false
+	}
+	_, err := hex.DecodeString(name)
+	return err == nil
+}
+
+// dirSize returns the total size of the regular files within dir.
+func dirSize(dir string) (int64, error) {
+	var size int64
+	err := filepath.Walk(dir, func(path string, info os.FileInfo, err error) error {
+		if err != nil {
+			return err
+		}
+		if info.Mode().IsRegular() {
+			size += info.Size()
+		}
+		return nil
+	})
+	return size, err
+}
+
+// installArtifact installs the cached artifact at src, a file or a directory,
+// to dest within root. Files are copied rather than linked, since a task could
+// otherwise modify the cached file. Directories are merged into existing
+// directories, as when downloading. Symlinks already within root, which may
+// have been created by a task, are only followed if they resolve within root.
+func installArtifact(src, dest, root string) error {
+	return filepath.Walk(src, func(path string, info os.FileInfo, err error) error {
+		if err != nil {
+			return err
+		}
+
+		rel, err := filepath.Rel(src, path)
+		if err != nil {
+			return err
+		}
+		target := filepath.Join(dest, rel)
+
+		switch {
+		case info.IsDir():
+			return mkdirBeneath(root, target, info.Mode().Perm())
+		case info.Mode()&os.ModeSymlink != 0:
+			link, err := os.Readlink(path)
+			if err != nil {
+				return err
+			}
+			if err := mkdirBeneath(root, filepath.Dir(target), 0755); err != nil {
+				return err
+			}
+			if err := removeExisting(target); err != nil {
+				return err
+			}
+			return os.Symlink(link, target)
+		case info.Mode().IsRegular():
+			if err := mkdirBeneath(root, filepath.Dir(target), 0755); err != nil {
+				return err
+			}
+			if err := removeExisting(target); err != nil {
+				return err
+			}
+			return copyFile(path, target, info.Mode().Perm())
+		default:
+			// Skip special files
+			return nil
+		}
+	})
+}
+
+// mkdirBeneath creates the directory at path, along with any missing parents,
+// after ensuring that the existing part of the path doesn't resolve outside of
+// root through symlinks.
+func mkdirBeneath(root, path string, perm os.FileMode) error {
+	if err := checkBeneath(root, path); err != nil {
+		return err
+	}
+	return os.MkdirAll(path, perm)
+}
+
+// checkBeneath returns an error if path, or its deepest existing parent,
+// resolves outside of root through symlinks.
+func checkBeneath(root, path string) error {
+	realRoot, err := filepath.EvalSymlinks(root)
+	if err != nil {
+		return err
+	}
+
+	existing := path
+	for {
+		if _, err := os.Lstat(existing); err == nil {
+			break
+		} else if !os.IsNotExist(err) {
+			return err
+		}
+		parent := filepath.Dir(existing)
+		if parent == existing {
+			break
+		}
+		existing = parent
+	}
+
+	resolved, err := filepath.EvalSymlinks(existing)
+	if err != nil {
+		return err
+	}
+	if helper.PathEscapesSandbox(realRoot, resolved) {
+		return fmt.Errorf("refusing to install artifact to %q: path resolves to %q outside of %q", path, resolved, root)
+	}
+	return nil
+}
+
+// removeExisting removes the file at path, if any, so that it can be replaced.
+func removeExisting(path string) error {
+	if err := os.Remove(path); err != nil && !os.IsNotExist(err) {
+		return err
+	}
+	return nil
+}
+
+// copyFile copies the file at src to dest, created with the given mode.
+func copyFile(src, dest string, mode os.FileMode) error {
+	in, err := os.Open(src)
+	if err != nil {
+		return err
+	}
+	defer in.Close()
+
+	// The existing file was removed, so refuse to follow a symlink created
+	// in its place in the meantime
+	out, err := os.OpenFile(dest, os.O_WRONLY|os.O_CREATE|os.O_EXCL, mode)
+	if err != nil {
+		return err
+	}
+	if _, err := io.Copy(out, in); err != nil {
+		out.Close()
+		return err
+	}
+	return out.Close()
+}
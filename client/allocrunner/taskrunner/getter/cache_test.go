@@ -0,0 +1,258 @@
+package getter
+
+import (
+	"fmt"
+	"io/ioutil"
+	"net/http"
+	"net/http/httptest"
+	"os"
+	"path/filepath"
+	"sync"
+	"sync/atomic"
+	"testing"
+	"time"
+
+	"github.com/hashicorp/nomad/helper/testlog"
+	"github.com/hashicorp/nomad/nomad/structs"
+	"github.com/stretchr/testify/require"
+)
+
+// countingServer returns a test server hosting the test fixtures and a
+// function returning the number of GET requests served.
+func countingServer(t *testing.T) (*httptest.Server, func() int64) {
+	var requests int64
+	files := http.FileServer(http.Dir(filepath.Dir("./test-fixtures/")))
+	ts := httptest.NewServer(http.HandlerFunc(func(w http.ResponseWriter, r *http.Request) {
+		if r.Method == http.MethodGet {
+			atomic.AddInt64(&requests, 1)
+		}
+		files.ServeHTTP(w, r)
+	}))
+	t.Cleanup(ts.Close)
+	return ts, func() int64 { return atomic.LoadInt64(&requests) }
+}
+
+func testCache(t *testing.T, maxSize int64) *Cache {
+	dir, err := ioutil.TempDir("", "nomad-test-cache")
+	require.NoError(t, err)
+	t.Cleanup(func() { removeAllT(t, dir) })
+
+	c, err := NewCache(dir, maxSize, testlog.HCLogger(t))
+	require.NoError(t, err)
+	return c
+}
+
+func testTaskDir(t *testing.T) string {
+	taskDir, err := ioutil.TempDir("", "nomad-test")
+	require.NoError(t, err)
+	t.Cleanup(func() { removeAllT(t, taskDir) })
+	return taskDir
+}
+
+func testShArtifact(ts *httptest.Server) *structs.TaskArtifact {
+	return &structs.TaskArtifact{
+		GetterSource: fmt.Sprintf("%s/test.sh", ts.URL),
+		GetterOptions: map[string]string{
+			"checksum": "md5:bce963762aa2dbfed13caf492a45fb72",
+		},
+	}
+}
+
+func testArchiveArtifact(ts *httptest.Server) *structs.TaskArtifact {
+	return &structs.TaskArtifact{
+		GetterSource: fmt.Sprintf("%s/archive.tar.gz", ts.URL),
+		GetterOptions: map[string]string{
+			"checksum": "sha1:20bab73c72c56490856f913cf594bad9a4d730f6",
+		},
+	}
+}
+
+func TestCache_GetArtifact_Hit(t *testing.T) {
+	ts, requests := countingServer(t)
+	c := testCache(t, 1024*1024)
+
+	// The first task downloads the artifact into the cache
+	taskDir1 := testTaskDir(t)
+	cached, hit, err := c.GetArtifact(noopTaskEnv(taskDir1), testShArtifact(ts), nil)
+	require.NoError(t, err)
+	require.True(t, cached)
+	require.False(t, hit)
+
+	// The second task gets it from the cache
+	taskDir2 := testTaskDir(t)
+	cached, hit, err = c.GetArtifact(noopTaskEnv(taskDir2), testShArtifact(ts), nil)
+	require.NoError(t, err)
+	require.True(t, cached)
+	require.True(t, hit)
+	require.Equal(t, int64(1), requests())
+
+	checkContents(taskDir2, map[string]string{"test.sh": "sleep 1\n"}, t)
+
+	// Each task gets its own copy, so a task modifying it doesn't affect
+	// the cache
+	fi1, err := os.Stat(filepath.Join(taskDir1, "test.sh"))
+	require.NoError(t, err)
+	fi2, err := os.Stat(filepath.Join(taskDir2, "test.sh"))
+	require.NoError(t, err)
+	require.False(t, os.SameFile(fi1, fi2))
+	require.Equal(t, int64(len("sleep 1\n")), c.Size())
+
+	require.NoError(t, ioutil.WriteFile(filepath.Join(taskDir2, "test.sh"), []byte("poisoned\n"), 0644))
+	taskDir3 := testTaskDir(t)
+	_, hit, err = c.GetArtifact(noopTaskEnv(taskDir3), testShArtifact(ts), nil)
+	require.NoError(t, err)
+	require.True(t, hit)
+	checkContents(taskDir3, map[string]string{"test.sh": "sleep 1\n"}, t)
+}
+
+func TestCache_GetArtifact_Archive(t *testing.T) {
+	ts, requests := countingServer(t)
+	c := testCache(t, 1024*1024)
+
+	for i := 0; i < 2; i++ {
+		// Create some of the same files that exist in the artifact to ensure
+		// they are overridden
+		taskDir := testTaskDir(t)
+		createContents(taskDir, map[string]string{
+			"exist/my.config": "to be replaced",
+			"untouched":       "existing top-level",
+		}, t)
+
+		_, hit, err := c.GetArtifact(noopTaskEnv(taskDir), testArchiveArtifact(ts), nil)
+		require.NoError(t, err)
+		require.Equal(t, i == 1, hit)
+
+		checkContents(taskDir, map[string]string{
+			"untouched":       "existing top-level",
+			"exist/my.config": "hello world\n",
+			"new/my.config":   "hello world\n",
+			"test.sh":         "sleep 1\n",
+		}, t)
+	}
+	require.Equal(t, int64(1), requests())
+}
+
+func TestCache_GetArtifact_Symlinks(t *testing.T) {
+	ts, _ := countingServer(t)
+	c := testCache(t, 1024*1024)
+
+	// A task may have replaced a directory with a symlink leaving the task
+	// directory, which must not be followed
+	taskDir := testTaskDir(t)
+	outside := testTaskDir(t)
+	require.NoError(t, os.Symlink(outside, filepath.Join(taskDir, "local")))
+
+	artifact := testShArtifact(ts)
+	artifact.RelativeDest = "local/"
+	_, _, err := c.GetArtifact(noopTaskEnv(taskDir), artifact, nil)
+	require.Error(t, err)
+	require.Contains(t, err.Error(), "outside of")
+	_, err = os.Stat(filepath.Join(outside, "test.sh"))
+	require.True(t, os.IsNotExist(err))
+
+	// Symlinks within the task directory are followed
+	require.NoError(t, os.Remove(filepath.Join(taskDir, "local")))
+	require.NoError(t, os.Mkdir(filepath.Join(taskDir, "real"), 0755))
+	require.NoError(t, os.Symlink(filepath.Join(taskDir, "real"), filepath.Join(taskDir, "local")))
+	_, _, err = c.GetArtifact(noopTaskEnv(taskDir), artifact, nil)
+	require.NoError(t, err)
+	checkContents(filepath.Join(taskDir, "real"), map[string]string{"test.sh": "sleep 1\n"}, t)
+}
+
+func TestCache_GetArtifact_NoChecksum(t *testing.T) {
+	ts, requests := countingServer(t)
+	c := testCache(t, 1024*1024)
+
+	artifact := &structs.TaskArtifact{
+		GetterSource: fmt.Sprintf("%s/test.sh", ts.URL),
+	}
+	for i := 0; i < 2; i++ {
+		taskDir := testTaskDir(t)
+		cached, hit, err := c.GetArtifact(noopTaskEnv(taskDir), artifact, nil)
+		require.NoError(t, err)
+		require.False(t, cached)
+		require.False(t, hit)
+		checkContents(taskDir, map[string]string{"test.sh": "sleep 1\n"}, t)
+	}
+
+	require.Equal(t, int64(2), requests())
+	require.Zero(t, c.Size())
+}
+
+func TestCache_GetArtifact_Concurrent(t *testing.T) {
+	ts, requests := countingServer(t)
+	c := testCache(t, 1024*1024)
+
+	var wg sync.WaitGroup
+	errCh := make(chan error, 5)
+	for i := 0; i < 5; i++ {
+		taskDir := testTaskDir(t)
+		wg.Add(1)
+		go func() {
+			defer wg.Done()
+			_, _, err := c.GetArtifact(noopTaskEnv(taskDir), testShArtifact(ts), nil)
+			errCh <- err
+		}()
+	}
+	wg.Wait()
+	close(errCh)
+
+	for err := range errCh {
+		require.NoError(t, err)
+	}
+	require.Equal(t, int64(1), requests())
+}
+
+func TestCache_Evict(t *testing.T) {
+	ts, _ := countingServer(t)
+	c := testCache(t, 1024*1024)
+
+	now := time.Now()
+	c.now = func() time.Time { return now }
+	get := func(artifact *structs.TaskArtifact) {
+		now = now.Add(time.Second)
+		_, _, err := c.GetArtifact(noopTaskEnv(testTaskDir(t)), artifact, nil)
+		require.NoError(t, err)
+	}
+
+	// The archive is the least recently used
+	get(testShArtifact(ts))
+	get(testArchiveArtifact(ts))
+	get(testShArtifact(ts))
+	require.Len(t, c.entries, 2)
+
+	require.True(t, c.EvictOldest())
+	require.Len(t, c.entries, 1)
+	require.Equal(t, int64(len("sleep 1\n")), c.Size())
+
+	// The remaining artifact is kept when the cache is reloaded
+	reloaded, err := NewCache(c.dir, c.maxSize, testlog.HCLogger(t))
+	require.NoError(t, err)
+	require.Equal(t, c.Size(), reloaded.Size())
+
+	require.True(t, c.EvictOldest())
+	require.False(t, c.EvictOldest())
+	require.Zero(t, c.Size())
+
+	// Evicted artifacts are removed from disk before EvictOldest returns
+	entries, err := ioutil.ReadDir(c.dir)
+	require.NoError(t, err)
+	require.Empty(t, entries)
+}
+
+func TestCache_Evict_MaxSize(t *testing.T) {
+	ts, requests := countingServer(t)
+
+	// Artifacts larger than the cache are installed but not kept
+	c := testCache(t, 1)
+	for i := 0; i < 2; i++ {
+		taskDir := testTaskDir(t)
+		_, hit, err := c.GetArtifact(noopTaskEnv(taskDir), testShArtifact(ts), nil)
+		require.NoError(t, err)
+		require.False(t, hit)
+		checkContents(taskDir, map[string]string{"test.sh": "sleep 1\n"}, t)
+	}
+
+	require.Equal(t, int64(2), requests())
+	require.Zero(t, c.Size())
+}
@@ -5,10 +5,12 @@ import (
 	"fmt"
 	"net/http"
 	"net/url"
+	"path/filepath"
 	"strings"
 	"sync"
 
 	gg "github.com/hashicorp/go-getter"
+	"github.com/hashicorp/nomad/helper"
 
 	"github.com/hashicorp/nomad/nomad/structs"
 )
@@ -129,17 +131,55 @@ func getHeaders(env EnvReplacer, m map[string]string) http.Header {
 	return headers
 }
 
-// GetArtifact downloads an artifact into the specified task directory.
-func GetArtifact(taskEnv EnvReplacer, artifact *structs.TaskArtifact) error {
+// GetArtifact downloads an artifact into the specified task directory, in the
+// sandbox if not nil.
+func GetArtifact(taskEnv EnvReplacer, artifact *structs.TaskArtifact, sandbox *Sandbox) error {
+	req, err := newArtifactRequest(taskEnv, artifact, sandbox)
+	if err != nil {
+		return err
+	}
+	return req.get(req.dest)
+}
+
+// artifactRequest is an artifact with its variables interpolated, ready to be
+// downloaded.
+type artifactRequest struct {
+	url     string
+	dest    string
+	mode    gg.ClientMode
+	headers http.Header
+
+	// taskDir is the task directory, which sandboxed downloads are staged in
+	taskDir string
+
+	// sandbox downloads the artifact, or nil to download it in process
+	sandbox *Sandbox
+}
+
+// installRoot returns the directory the artifact downloaded into dest must
+// stay within: the task directory, the allocation directory for destinations
+// in the shared alloc dir, or the parent of dest for destinations created by
+// the client, such as the cache.
+func (r *artifactRequest) installRoot(dest string) string {
+	if !helper.PathEscapesSandbox(r.taskDir, dest) {
+		return r.taskDir
+	}
+	if allocDir := filepath.Dir(r.taskDir); !helper.PathEscapesSandbox(allocDir, dest) {
+		return allocDir
+	}
+	return filepath.Dir(dest)
+}
+
+func newArtifactRequest(taskEnv EnvReplacer, artifact *structs.TaskArtifact, sandbox *Sandbox) (*artifactRequest, error) {
 	ggURL, err := getGetterUrl(taskEnv, artifact)
 	if err != nil {
-		return newGetError(artifact.GetterSource, err, false)
+		return nil, newGetError(artifact.GetterSource, err, false)
 	}
 
 	dest, escapes := taskEnv.ClientPath(artifact.RelativeDest, true)
 	// Verify the destination is still in the task sandbox after interpolation
 	if escapes {
-		return newGetError(artifact.RelativeDest,
+		return nil, newGetError(artifact.RelativeDest,
 			errors.New("artifact destination path escapes the alloc directory"),
 			false)
 	}
@@ -153,11 +193,29 @@ func GetArtifact(taskEnv EnvReplacer, artifact *structs.TaskArtifact) error {
 		mode = gg.ClientModeDir
 	}
 
-	headers := getHeaders(taskEnv, artifact.GetterHeaders)
-	if err := getClient(ggURL, headers, mode, dest).Get(); err != nil {
-		return newGetError(ggURL, err, true)
-	}
+	taskDir, _ := taskEnv.ClientPath(".", false)
 
+	return &artifactRequest{
+		url:     ggURL,
+		dest:    dest,
+		mode:    mode,
+		headers: getHeaders(taskEnv, artifact.GetterHeaders),
+		taskDir: taskDir,
+		sandbox: sandbox,
+	}, nil
+}
+
+// get downloads the artifact into dest.
+func (r *artifactRequest) get(dest string) error {
+	if r.sandbox != nil {
+		return r.sandbox.get(r, dest)
+	}
+	if err := checkBeneath(r.installRoot(dest), dest); err != nil {
+		return newGetError(r.url, err, false)
+	}
+	if err := getClient(r.url, r.headers, r.mode, dest).Get(); err != nil {
+		return newGetError(r.url, err, true)
+	}
 	return nil
 }
 
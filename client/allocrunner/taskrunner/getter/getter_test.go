@@ -127,7 +127,7 @@ func TestGetArtifact_Headers(t *testing.T) {
 	taskEnv := upperReplacer{
 		taskDir: taskDir,
 	}
-	err = GetArtifact(taskEnv, artifact)
+	err = GetArtifact(taskEnv, artifact, nil)
 	require.NoError(t, err)
 
 	// Verify artifact exists.
@@ -161,7 +161,7 @@ func TestGetArtifact_FileAndChecksum(t *testing.T) {
 	}
 
 	// Download the artifact
-	if err := GetArtifact(noopTaskEnv(taskDir), artifact); err != nil {
+	if err := GetArtifact(noopTaskEnv(taskDir), artifact, nil); err != nil {
 		t.Fatalf("GetArtifact failed: %v", err)
 	}
 
@@ -195,7 +195,7 @@ func TestGetArtifact_File_RelativeDest(t *testing.T) {
 	}
 
 	// Download the artifact
-	if err := GetArtifact(noopTaskEnv(taskDir), artifact); err != nil {
+	if err := GetArtifact(noopTaskEnv(taskDir), artifact, nil); err != nil {
 		t.Fatalf("GetArtifact failed: %v", err)
 	}
 
@@ -229,7 +229,7 @@ func TestGetArtifact_File_EscapeDest(t *testing.T) {
 	}
 
 	// attempt to download the artifact
-	err = GetArtifact(noopTaskEnv(taskDir), artifact)
+	err = GetArtifact(noopTaskEnv(taskDir), artifact, nil)
 	if err == nil || !strings.Contains(err.Error(), "escapes") {
 		t.Fatalf("expected GetArtifact to disallow sandbox escape: %v", err)
 	}
@@ -279,7 +279,7 @@ func TestGetArtifact_InvalidChecksum(t *testing.T) {
 	}
 
 	// Download the artifact and expect an error
-	if err := GetArtifact(noopTaskEnv(taskDir), artifact); err == nil {
+	if err := GetArtifact(noopTaskEnv(taskDir), artifact, nil); err == nil {
 		t.Fatalf("GetArtifact should have failed")
 	}
 }
@@ -344,7 +344,7 @@ func TestGetArtifact_Archive(t *testing.T) {
 		},
 	}
 
-	if err := GetArtifact(noopTaskEnv(taskDir), artifact); err != nil {
+	if err := GetArtifact(noopTaskEnv(taskDir), artifact, nil); err != nil {
 		t.Fatalf("GetArtifact failed: %v", err)
 	}
 
@@ -377,7 +377,7 @@ func TestGetArtifact_Setuid(t *testing.T) {
 		},
 	}
 
-	require.NoError(t, GetArtifact(noopTaskEnv(taskDir), artifact))
+	require.NoError(t, GetArtifact(noopTaskEnv(taskDir), artifact, nil))
 
 	var expected map[string]int
 
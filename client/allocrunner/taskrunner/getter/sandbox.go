@@ -0,0 +1,330 @@
+package getter
+
+import (
+	"bytes"
+	"encoding/json"
+	"errors"
+	"flag"
+	"fmt"
+	"io/ioutil"
+	"net/http"
+	"os"
+	"os/exec"
+	"path/filepath"
+	"strings"
+	"sync/atomic"
+	"time"
+
+	gg "github.com/hashicorp/go-getter"
+	hclog "github.com/hashicorp/go-hclog"
+	"github.com/hashicorp/nomad/client/config"
+)
+
+const (
+	// sandboxCommand is the argument the client executes itself with to
+	// download an artifact in a sandboxed process.
+	sandboxCommand = "artifact-getter"
+
+	// sandboxStagingPrefix prefixes the directories within task directories
+	// that sandboxed processes download artifacts into.
+	sandboxStagingPrefix = ".artifact-"
+
+	// sandboxDataName is the name of the downloaded artifact within the
+	// staging directory
+	sandboxDataName = "data"
+
+	// sandboxSizeCheckInterval is the interval at which the size of a
+	// sandboxed download is checked against the maximum size.
+	sandboxSizeCheckInterval = 500 * time.Millisecond
+)
+
+var (
+	// sandboxEnvVars are the environment variables of the client always
+	// passed on to the sandboxed process, so that getters can find system
+	// binaries and use the configured proxies without seeing the rest of the
+	// client's environment.
+	sandboxEnvVars = []string{
+		"PATH", "HOME", "TMPDIR",
+		"HTTP_PROXY", "HTTPS_PROXY", "NO_PROXY", "ALL_PROXY",
+		"http_proxy", "https_proxy", "no_proxy", "all_proxy",
+	}
+
+	// errConfineUnsupported is returned when the sandboxed process can't be
+	// confined to its working directory on this platform or kernel.
+	errConfineUnsupported = errors.New("filesystem isolation is not supported")
+
+	// errTooLarge signals that a download was killed for exceeding the
+	// maximum size
+	errTooLarge = errors.New("artifact too large")
+)
+
+// Sandbox downloads artifacts in a separate process, which is killed when it
+// exceeds the configured timeout or maximum size. On Linux the process runs
+// as an unprivileged user when the client runs as root, and is restricted by
+// Landlock to writing within the directory it downloads into and reading
+// only that directory and system paths, so that malicious artifacts can't
+// modify or read the client host. Where the process can't be restricted,
+// downloads run without filesystem isolation unless it is required.
+type Sandbox struct {
+	config *config.ArtifactConfig
+	logger hclog.Logger
+
+	// isolationUnsupported is set once a sandboxed process couldn't be
+	// confined, after which downloads run without filesystem isolation
+	isolationUnsupported int32
+}
+
+// NewSandbox returns a sandbox for downloading artifacts.
+func NewSandbox(config *config.ArtifactConfig, logger hclog.Logger) *Sandbox {
+	return &Sandbox{
+		config: config,
+		logger: logger.Named("artifact_sandbox"),
+	}
+}
+
+// sandboxRequest is the artifact request sent to the sandboxed process
+type sandboxRequest struct {
+	URL     string
+	Mode    gg.ClientMode
+	Headers http.Header
+}
+
+// sandboxResponse is the result returned by the sandboxed process
+type sandboxResponse struct {
+	Error string
+
+	// IsolationUnsupported is set if the download was refused because the
+	// process couldn't be confined to its working directory
+	IsolationUnsupported bool
+}
+
+// get downloads the artifact into dest. The sandboxed process downloads into
+// a staging directory within the task directory, from which the artifact is
+// installed.
+func (s *Sandbox) get(req *artifactRequest, dest string) error {
+	staging, err := ioutil.TempDir(req.taskDir, sandboxStagingPrefix)
+	if err != nil {
+		return newGetError(req.url, fmt.Errorf("failed to create artifact staging directory: %v", err), true)
+	}
+	defer os.RemoveAll(staging)
+
+	if err := s.run(req, staging); err != nil {
+		return err
+	}
+
+	src := filepath.Join(staging, sandboxDataName)
+	if err := s.restoreOwner(src); err != nil {
+		return newGetError(req.url, fmt.Errorf("failed to change owner of artifact: %v", err), true)
+	}
+	if err := installArtifact(src, dest, req.installRoot(dest)); err != nil {
+		return newGetError(req.url, fmt.Errorf("failed to install artifact: %v", err), true)
+	}
+	return nil
+}
+
+// run downloads the artifact into the staging directory in a sandboxed
+// process.
+func (s *Sandbox) run(req *artifactRequest, staging string) error {
+	bin, err := os.Executable()
+	if err != nil {
+		return newGetError(req.url, fmt.Errorf("failed to find artifact download executable: %v", err), true)
+	}
+
+	args := []string{sandboxCommand}
+	if s.isolate() {
+		args = append(args, "-isolate")
+	}
+	input, err := json.Marshal(&sandboxRequest{
+		URL:     req.url,
+		Mode:    req.mode,
+		Headers: req.headers,
+	})
+	if err != nil {
+		return newGetError(req.url, err, false)
+	}
+
+	var stdout, stderr bytes.Buffer
+	cmd := exec.Command(bin, args...)
+	cmd.Dir = staging
+	cmd.Env = sandboxEnv(s.config.EnvVars)
+	cmd.Stdin = bytes.NewReader(input)
+	cmd.Stdout = &stdout
+	cmd.Stderr = &stderr
+	cmd.SysProcAttr, err = s.sysProcAttr(staging)
+	if err != nil {
+		return newGetError(req.url, fmt.Errorf("failed to prepare artifact download: %v", err), true)
+	}
+
+	if err := cmd.Start(); err != nil {
+		return newGetError(req.url, fmt.Errorf("failed to start artifact download: %v", err), true)
+	}
+
+	doneCh := make(chan struct{})
+	killedCh := make(chan error, 1)
+	go s.watch(cmd, staging, doneCh, killedCh)
+
+	err = cmd.Wait()
+	close(doneCh)
+	if killErr := <-killedCh; killErr == errTooLarge {
+		return s.tooLarge(req)
+	} else if killErr != nil {
+		return newGetError(req.url, killErr, true)
+	}
+
+	if msg := strings.TrimSpace(stderr.String()); msg != "" {
+		s.logger.Debug("artifact download output", "artifact", req.url, "output", msg)
+	}
+
+	if err != nil {
+		var resp sandboxResponse
+		if jsonErr := json.Unmarshal(stdout.Bytes(), &resp); jsonErr == nil && resp.Error != "" {
+			if resp.IsolationUnsupported {
+				if s.config.RequireFilesystemIsolation {
+					s.logger.Warn("refusing to download artifact without filesystem isolation, unset require_filesystem_isolation to allow it",
+						"artifact", req.url, "error", resp.Error)
+					return newGetError(req.url, errors.New(resp.Error), false)
+				}
+
+				// Nothing was downloaded yet, so download again unconfined
+				if atomic.CompareAndSwapInt32(&s.isolationUnsupported, 0, 1) {
+					s.logger.Warn("filesystem isolation is not supported, downloading artifacts without it",
+						"error", resp.Error)
+				}
+				return s.run(req, staging)
+			}
+			return newGetError(req.url, errors.New(resp.Error), true)
+		}
+		return newGetError(req.url, fmt.Errorf("artifact download failed: %v: %s", err, strings.TrimSpace(stderr.String())), true)
+	}
+
+	// The download may have exceeded the maximum size since the last check
+	if max := s.config.MaxSize; max > 0 {
+		if size, err := dirSize(staging); err == nil && size > max {
+			return s.tooLarge(req)
+		}
+	}
+	return nil
+}
+
+// isolate returns whether the sandboxed process should be confined to its
+// working directory.
+func (s *Sandbox) isolate() bool {
+	if s.config.DisableFilesystemIsolation {
+		return false
+	}
+	return s.config.RequireFilesystemIsolation || atomic.LoadInt32(&s.isolationUnsupported) == 0
+}
+
+// tooLarge returns the error of a download exceeding the maximum size, which
+// isn't recoverable.
+func (s *Sandbox) tooLarge(req *artifactRequest) error {
+	return newGetError(req.url, fmt.Errorf("artifact exceeds the maximum size of %d bytes", s.config.MaxSize), false)
+}
+
+// watch kills the sandboxed process if it exceeds the timeout or the maximum
+// size, until doneCh is closed. The reason it was killed, or nil, is sent on
+// killedCh.
+func (s *Sandbox) watch(cmd *exec.Cmd, staging string, doneCh <-chan struct{}, killedCh chan<- error) {
+	var timeoutCh <-chan time.Time
+	if s.config.Timeout > 0 {
+		timer := time.NewTimer(s.config.Timeout)
+		defer timer.Stop()
+		timeoutCh = timer.C
+	}
+
+	var sizeCh <-chan time.Time
+	if s.config.MaxSize > 0 {
+		ticker := time.NewTicker(sandboxSizeCheckInterval)
+		defer ticker.Stop()
+		sizeCh = ticker.C
+	}
+
+	for {
+		select {
+		case <-doneCh:
+			killedCh <- nil
+			return
+		case <-timeoutCh:
+			killProcess(cmd)
+			killedCh <- fmt.Errorf("artifact download timed out after %v", s.config.Timeout)
+			return
+		case <-sizeCh:
+			// Files may be removed while walking, so use the partial size
+			size, _ := dirSize(staging)
+			if size > s.config.MaxSize {
+				killProcess(cmd)
+				killedCh <- errTooLarge
+				return
+			}
+		}
+	}
+}
+
+// sandboxEnv returns the environment of the sandboxed process, made of the
+// default variables and the extra variables configured to be passed on. It
+// is never nil, as the process would inherit the whole environment of the
+// client.
+func sandboxEnv(extra []string) []string {
+	env := []string{}
+	for _, name := range append(sandboxEnvVars[:len(sandboxEnvVars):len(sandboxEnvVars)], extra...) {
+		if value, ok := os.LookupEnv(name); ok {
+			env = append(env, name+"="+value)
+		}
+	}
+	return env
+}
+
+// runSandboxed is run by the sandboxed process to download the artifact
+// requested on stdin into its working directory. It returns the exit code of
+// the process.
+func runSandboxed(args []string) int {
+	flags := flag.NewFlagSet(sandboxCommand, flag.ContinueOnError)
+	isolate := flags.Bool("isolate", false, "restrict writes to the working directory")
+	confined := flags.Bool("confined", false, "the process is already restricted")
+	if err := flags.Parse(args); err != nil {
+		return 1
+	}
+
+	if *isolate && !*confined {
+		// confine only returns if the process couldn't be confined. The client
+		// decides whether to download again without isolation.
+		err := confine()
+		resp := &sandboxResponse{
+			Error:                fmt.Sprintf("failed to isolate artifact download: %v", err),
+			IsolationUnsupported: err == errConfineUnsupported,
+		}
+		json.NewEncoder(os.Stdout).Encode(resp)
+		return 1
+	}
+
+	var req sandboxRequest
+	if err := json.NewDecoder(os.Stdin).Decode(&req); err != nil {
+		return sandboxFailed(fmt.Errorf("failed to decode artifact request: %v", err))
+	}
+
+	wd, err := os.Getwd()
+	if err != nil {
+		return sandboxFailed(err)
+	}
+
+	// Temporary files, such as archives being decompressed, must also be
+	// written within the working directory
+	tmpDir := filepath.Join(wd, "tmp")
+	if err := os.Mkdir(tmpDir, 0700); err != nil {
+		return sandboxFailed(err)
+	}
+	os.Setenv("TMPDIR", tmpDir)
+
+	dest := filepath.Join(wd, sandboxDataName)
+	if err := getClient(req.URL, req.Headers, req.Mode, dest).Get(); err != nil {
+		return sandboxFailed(err)
+	}
+	return 0
+}
+
+// sandboxFailed writes the error of the sandboxed process as its response
+// and returns its exit code.
+func sandboxFailed(err error) int {
+	json.NewEncoder(os.Stdout).Encode(&sandboxResponse{Error: err.Error()})
+	return 1
+}
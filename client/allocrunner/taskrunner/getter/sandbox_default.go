@@ -0,0 +1,31 @@
+//go:build !linux
+// +build !linux
+
+package getter
+
+import (
+	"os/exec"
+	"syscall"
+)
+
+// sysProcAttr returns the attributes of the sandboxed process, which runs as
+// the client user on this platform.
+func (s *Sandbox) sysProcAttr(string) (*syscall.SysProcAttr, error) {
+	return nil, nil
+}
+
+// restoreOwner is a no-op as downloads are owned by the client user on this
+// platform.
+func (s *Sandbox) restoreOwner(string) error {
+	return nil
+}
+
+// killProcess kills the sandboxed process.
+func killProcess(cmd *exec.Cmd) {
+	cmd.Process.Kill()
+}
+
+// confine is not supported on this platform.
+func confine() error {
+	return errConfineUnsupported
+}
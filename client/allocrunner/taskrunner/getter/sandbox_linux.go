@@ -0,0 +1,219 @@
+//go:build linux
+// +build linux
+
+package getter
+
+import (
+	"fmt"
+	"os"
+	"os/exec"
+	"os/user"
+	"path/filepath"
+	"runtime"
+	"strconv"
+	"syscall"
+	"unsafe"
+
+	"golang.org/x/sys/unix"
+)
+
+// landlockFileAccess are the Landlock access rights that apply to files, as
+// opposed to directories. Reading and executing files is restricted to the
+// system paths and the working directory of the sandboxed process, and
+// writing to the working directory.
+const landlockFileAccess = unix.LANDLOCK_ACCESS_FS_EXECUTE |
+	unix.LANDLOCK_ACCESS_FS_WRITE_FILE |
+	unix.LANDLOCK_ACCESS_FS_READ_FILE
+
+// landlockAccess are all the Landlock filesystem access rights restricted for
+// the sandboxed process.
+const landlockAccess = landlockFileAccess |
+	unix.LANDLOCK_ACCESS_FS_READ_DIR |
+	unix.LANDLOCK_ACCESS_FS_REMOVE_DIR |
+	unix.LANDLOCK_ACCESS_FS_REMOVE_FILE |
+	unix.LANDLOCK_ACCESS_FS_MAKE_CHAR |
+	unix.LANDLOCK_ACCESS_FS_MAKE_DIR |
+	unix.LANDLOCK_ACCESS_FS_MAKE_REG |
+	unix.LANDLOCK_ACCESS_FS_MAKE_SOCK |
+	unix.LANDLOCK_ACCESS_FS_MAKE_FIFO |
+	unix.LANDLOCK_ACCESS_FS_MAKE_BLOCK |
+	unix.LANDLOCK_ACCESS_FS_MAKE_SYM
+
+// landlockReadAccess are the access rights granted beneath the system paths.
+const landlockReadAccess = unix.LANDLOCK_ACCESS_FS_EXECUTE |
+	unix.LANDLOCK_ACCESS_FS_READ_FILE |
+	unix.LANDLOCK_ACCESS_FS_READ_DIR
+
+// landlockSystemPaths are the paths the sandboxed process may read and
+// execute, so that getters can run system binaries such as git, resolve
+// hostnames and verify certificates. Paths missing on the host are skipped.
+var landlockSystemPaths = []string{
+	"/bin",
+	"/sbin",
+	"/usr",
+	"/lib",
+	"/lib32",
+	"/lib64",
+	"/etc/alternatives",
+	"/etc/ca-certificates",
+	"/etc/gitconfig",
+	"/etc/group",
+	"/etc/host.conf",
+	"/etc/hosts",
+	"/etc/ld.so.cache",
+	"/etc/localtime",
+	"/etc/nsswitch.conf",
+	"/etc/passwd",
+	"/etc/pki",
+	"/etc/resolv.conf",
+	"/etc/ssh/ssh_config",
+	"/etc/ssh/ssh_config.d",
+	"/etc/ssh/ssh_known_hosts",
+	"/etc/ssl",
+	"/run/systemd/resolve",
+	"/dev/random",
+	"/dev/urandom",
+}
+
+// sysProcAttr returns the attributes of the sandboxed process. It runs in its
+// own process group so that the processes started by getters are killed with
+// it, and as the configured user when the client runs as root, in which case
+// the staging directory is owned by the user.
+func (s *Sandbox) sysProcAttr(staging string) (*syscall.SysProcAttr, error) {
+	attr := &syscall.SysProcAttr{Setpgid: true}
+	if os.Geteuid() != 0 || s.config.User == "" {
+		return attr, nil
+	}
+
+	u, err := user.Lookup(s.config.User)
+	if err != nil {
+		return nil, err
+	}
+	uid, err := strconv.Atoi(u.Uid)
+	if err != nil {
+		return nil, fmt.Errorf("invalid uid %q of user %q: %v", u.Uid, u.Username, err)
+	}
+	gid, err := strconv.Atoi(u.Gid)
+	if err != nil {
+		return nil, fmt.Errorf("invalid gid %q of user %q: %v", u.Gid, u.Username, err)
+	}
+
+	if err := os.Chown(staging, uid, gid); err != nil {
+		return nil, err
+	}
+	attr.Credential = &syscall.Credential{Uid: uint32(uid), Gid: uint32(gid)}
+	return attr, nil
+}
+
+// restoreOwner makes the client the owner of the files downloaded by the
+// configured user.
+func (s *Sandbox) restoreOwner(dir string) error {
+	if os.Geteuid() != 0 || s.config.User == "" {
+		return nil
+	}
+
+	uid, gid := os.Getuid(), os.Getgid()
+	return filepath.Walk(dir, func(path string, info os.FileInfo, err error) error {
+		if err != nil {
+			return err
+		}
+		return os.Lchown(path, uid, gid)
+	})
+}
+
+// killProcess kills the process group of the sandboxed process.
+func killProcess(cmd *exec.Cmd) {
+	syscall.Kill(-cmd.Process.Pid, syscall.SIGKILL)
+}
+
+// confine restricts the process with Landlock to reading and executing the
+// system paths, and to reading and writing within its working directory.
+// Landlock only restricts the calling thread and the processes it executes,
+// so the restricted thread executes the process again, with the -confined
+// flag. It only returns if the process couldn't be confined.
+func confine() error {
+	runtime.LockOSThread()
+	defer runtime.UnlockOSThread()
+
+	abi, _, errno := unix.Syscall(unix.SYS_LANDLOCK_CREATE_RULESET, 0, 0, unix.LANDLOCK_CREATE_RULESET_VERSION)
+	if errno == unix.ENOSYS || errno == unix.EOPNOTSUPP || (errno == 0 && abi < 1) {
+		return errConfineUnsupported
+	} else if errno != 0 {
+		return fmt.Errorf("failed to check Landlock support: %v", errno)
+	}
+
+	// Landlock denies moving files between directories unless the ruleset
+	// handles it, which is only supported since the second version.
+	access := uint64(landlockAccess)
+	if abi >= 2 {
+		access |= unix.LANDLOCK_ACCESS_FS_REFER
+	}
+
+	bin, err := os.Executable()
+	if err != nil {
+		return err
+	}
+
+	attr := unix.LandlockRulesetAttr{Access_fs: access}
+	fd, _, errno := unix.Syscall(unix.SYS_LANDLOCK_CREATE_RULESET,
+		uintptr(unsafe.Pointer(&attr)), unsafe.Sizeof(attr), 0)
+	if errno != 0 {
+		return fmt.Errorf("failed to create Landlock ruleset: %v", errno)
+	}
+	defer unix.Close(int(fd))
+
+	if err := landlockAllow(fd, ".", access); err != nil {
+		return err
+	}
+	for _, path := range landlockSystemPaths {
+		if err := landlockAllow(fd, path, landlockReadAccess); err != nil && !os.IsNotExist(err) {
+			return err
+		}
+	}
+	if err := landlockAllow(fd, bin, landlockReadAccess); err != nil {
+		return err
+	}
+	if err := landlockAllow(fd, os.DevNull, unix.LANDLOCK_ACCESS_FS_READ_FILE|unix.LANDLOCK_ACCESS_FS_WRITE_FILE); err != nil {
+		return err
+	}
+
+	if err := unix.Prctl(unix.PR_SET_NO_NEW_PRIVS, 1, 0, 0, 0); err != nil {
+		return fmt.Errorf("failed to set no new privileges: %v", err)
+	}
+	if _, _, errno := unix.Syscall(unix.SYS_LANDLOCK_RESTRICT_SELF, fd, 0, 0); errno != 0 {
+		return fmt.Errorf("failed to enforce Landlock ruleset: %v", errno)
+	}
+
+	args := append(os.Args[:len(os.Args):len(os.Args)], "-confined")
+	return syscall.Exec(bin, args, os.Environ())
+}
+
+// landlockAllow adds a rule allowing the access rights beneath path to the
+// Landlock ruleset. Only the rights that apply to files are allowed when the
+// path is a file.
+func landlockAllow(ruleset uintptr, path string, access uint64) error {
+	fd, err := unix.Open(path, unix.O_PATH|unix.O_CLOEXEC, 0)
+	if err != nil {
+		return &os.PathError{Op: "open", Path: path, Err: err}
+	}
+	defer unix.Close(fd)
+
+	var stat unix.Stat_t
+	if err := unix.Fstat(fd, &stat); err != nil {
+		return &os.PathError{Op: "stat", Path: path, Err: err}
+	}
+	if stat.Mode&unix.S_IFMT != unix.S_IFDIR {
+		access &= landlockFileAccess
+	}
+
+	attr := unix.LandlockPathBeneathAttr{
+		Allowed_access: access,
+		Parent_fd:      int32(fd),
+	}
+	_, _, errno := unix.Syscall6(unix.SYS_LANDLOCK_ADD_RULE, ruleset,
+		unix.LANDLOCK_RULE_PATH_BENEATH, uintptr(unsafe.Pointer(&attr)), 0, 0, 0)
+	if errno != 0 {
+		return fmt.Errorf("failed to allow access to %q: %v", path, errno)
+	}
+	return nil
+}
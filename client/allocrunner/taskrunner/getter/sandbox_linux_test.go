@@ -0,0 +1,53 @@
+//go:build linux
+// +build linux
+
+package getter
+
+import (
+	"io/ioutil"
+	"os/exec"
+	"path/filepath"
+	"testing"
+
+	"github.com/hashicorp/nomad/client/config"
+	"github.com/hashicorp/nomad/nomad/structs"
+	"github.com/stretchr/testify/require"
+)
+
+// TestSandbox_GetArtifact_ReadIsolation asserts that the sandboxed process
+// can run system binaries, but can't read files outside of the system paths
+// and its staging directory.
+func TestSandbox_GetArtifact_ReadIsolation(t *testing.T) {
+	if _, err := exec.LookPath("git"); err != nil {
+		t.Skip("git not found")
+	}
+
+	// The repository is outside of the task directory, as the files of the
+	// client would be
+	repo := testTaskDir(t)
+	require.NoError(t, ioutil.WriteFile(filepath.Join(repo, "test.sh"), []byte("sleep 1\n"), 0644))
+	for _, args := range [][]string{
+		{"init", "-q"},
+		{"add", "test.sh"},
+		{"-c", "user.name=test", "-c", "user.email=test@example.com", "commit", "-q", "-m", "test"},
+	} {
+		cmd := exec.Command("git", args...)
+		cmd.Dir = repo
+		out, err := cmd.CombinedOutput()
+		require.NoError(t, err, string(out))
+	}
+	artifact := &structs.TaskArtifact{GetterSource: "git::file://" + repo}
+
+	taskDir := testTaskDir(t)
+	err := GetArtifact(noopTaskEnv(taskDir), artifact, testSandbox(t, nil))
+	require.Error(t, err)
+	require.Contains(t, err.Error(), "Could not read from remote repository")
+	requireNoStaging(t, taskDir)
+
+	// Without isolation the repository can be read
+	sandbox := testSandbox(t, func(c *config.ArtifactConfig) {
+		c.DisableFilesystemIsolation = true
+	})
+	require.NoError(t, GetArtifact(noopTaskEnv(taskDir), artifact, sandbox))
+	checkContents(taskDir, map[string]string{"test.sh": "sleep 1\n"}, t)
+}
@@ -0,0 +1,238 @@
+package getter
+
+import (
+	"fmt"
+	"net/http"
+	"net/http/httptest"
+	"os"
+	"path/filepath"
+	"runtime"
+	"strings"
+	"testing"
+	"time"
+
+	"github.com/hashicorp/nomad/client/config"
+	"github.com/hashicorp/nomad/helper/testlog"
+	"github.com/hashicorp/nomad/nomad/structs"
+	"github.com/stretchr/testify/require"
+)
+
+func testSandbox(t *testing.T, cb func(*config.ArtifactConfig)) *Sandbox {
+	conf := config.DefaultConfig().Artifact
+
+	// Test binaries are generally not executable by unprivileged users
+	conf.User = ""
+
+	if cb != nil {
+		cb(conf)
+	}
+	return NewSandbox(conf, testlog.HCLogger(t))
+}
+
+// requireNoStaging asserts the staging directories were removed from the
+// task directory.
+func requireNoStaging(t *testing.T, taskDir string) {
+	matches, err := filepath.Glob(filepath.Join(taskDir, sandboxStagingPrefix+"*"))
+	require.NoError(t, err)
+	require.Empty(t, matches)
+}
+
+func TestSandbox_GetArtifact_File(t *testing.T) {
+	ts, _ := countingServer(t)
+	sandbox := testSandbox(t, nil)
+
+	taskDir := testTaskDir(t)
+	artifact := testShArtifact(ts)
+	artifact.RelativeDest = "local/"
+	require.NoError(t, GetArtifact(noopTaskEnv(taskDir), artifact, sandbox))
+
+	checkContents(taskDir, map[string]string{"local/test.sh": "sleep 1\n"}, t)
+	requireNoStaging(t, taskDir)
+}
+
+func TestSandbox_GetArtifact_Archive(t *testing.T) {
+	ts, _ := countingServer(t)
+	sandbox := testSandbox(t, nil)
+
+	// Create some of the same files that exist in the artifact to ensure
+	// they are overridden
+	taskDir := testTaskDir(t)
+	createContents(taskDir, map[string]string{
+		"exist/my.config": "to be replaced",
+		"untouched":       "existing top-level",
+	}, t)
+
+	require.NoError(t, GetArtifact(noopTaskEnv(taskDir), testArchiveArtifact(ts), sandbox))
+
+	checkContents(taskDir, map[string]string{
+		"untouched":       "existing top-level",
+		"exist/my.config": "hello world\n",
+		"new/my.config":   "hello world\n",
+		"test.sh":         "sleep 1\n",
+	}, t)
+	requireNoStaging(t, taskDir)
+}
+
+func TestSandbox_GetArtifact_Error(t *testing.T) {
+	ts, _ := countingServer(t)
+	sandbox := testSandbox(t, nil)
+
+	taskDir := testTaskDir(t)
+	artifact := &structs.TaskArtifact{
+		GetterSource: fmt.Sprintf("%s/missing.sh", ts.URL),
+	}
+	err := GetArtifact(noopTaskEnv(taskDir), artifact, sandbox)
+	require.Error(t, err)
+	require.Contains(t, err.Error(), "404")
+	require.True(t, structs.IsRecoverable(err))
+	requireNoStaging(t, taskDir)
+}
+
+func TestSandbox_GetArtifact_Timeout(t *testing.T) {
+	doneCh := make(chan struct{})
+	ts := httptest.NewServer(http.HandlerFunc(func(w http.ResponseWriter, r *http.Request) {
+		select {
+		case <-doneCh:
+		case <-r.Context().Done():
+		}
+	}))
+	defer ts.Close()
+	defer close(doneCh)
+
+	sandbox := testSandbox(t, func(c *config.ArtifactConfig) {
+		c.Timeout = 500 * time.Millisecond
+	})
+
+	taskDir := testTaskDir(t)
+	artifact := &structs.TaskArtifact{
+		GetterSource: fmt.Sprintf("%s/slow.sh", ts.URL),
+	}
+	err := GetArtifact(noopTaskEnv(taskDir), artifact, sandbox)
+	require.Error(t, err)
+	require.Contains(t, err.Error(), "timed out")
+	require.True(t, structs.IsRecoverable(err))
+	requireNoStaging(t, taskDir)
+}
+
+func TestSandbox_GetArtifact_MaxSize(t *testing.T) {
+	ts := httptest.NewServer(http.HandlerFunc(func(w http.ResponseWriter, r *http.Request) {
+		w.Write([]byte(strings.Repeat("x", 1024*1024)))
+	}))
+	defer ts.Close()
+
+	sandbox := testSandbox(t, func(c *config.ArtifactConfig) {
+		c.MaxSize = 1024
+	})
+
+	taskDir := testTaskDir(t)
+	artifact := &structs.TaskArtifact{
+		GetterSource: fmt.Sprintf("%s/large.txt", ts.URL),
+	}
+	err := GetArtifact(noopTaskEnv(taskDir), artifact, sandbox)
+	require.Error(t, err)
+	require.Contains(t, err.Error(), "exceeds the maximum size of 1024 bytes")
+	require.False(t, structs.IsRecoverable(err))
+	requireNoStaging(t, taskDir)
+}
+
+func TestSandbox_Cache(t *testing.T) {
+	ts, requests := countingServer(t)
+	sandbox := testSandbox(t, nil)
+	c := testCache(t, 1024*1024)
+
+	for i := 0; i < 2; i++ {
+		taskDir := testTaskDir(t)
+		_, hit, err := c.GetArtifact(noopTaskEnv(taskDir), testArchiveArtifact(ts), sandbox)
+		require.NoError(t, err)
+		require.Equal(t, i == 1, hit)
+
+		checkContents(taskDir, map[string]string{
+			"exist/my.config": "hello world\n",
+			"new/my.config":   "hello world\n",
+			"test.sh":         "sleep 1\n",
+		}, t)
+		requireNoStaging(t, taskDir)
+	}
+	require.Equal(t, int64(1), requests())
+}
+
+func TestSandbox_Env(t *testing.T) {
+	for name, value := range map[string]string{
+		"HTTPS_PROXY": "http://proxy.example.com:3128",
+		"NOMAD_TOKEN": "secret",
+		"AWS_PROFILE": "artifacts",
+	} {
+		orig, ok := os.LookupEnv(name)
+		require.NoError(t, os.Setenv(name, value))
+		if ok {
+			defer os.Setenv(name, orig)
+		} else {
+			defer os.Unsetenv(name)
+		}
+	}
+
+	env := sandboxEnv(nil)
+	require.NotNil(t, env)
+	require.Contains(t, env, "HTTPS_PROXY=http://proxy.example.com:3128")
+	for _, kv := range env {
+		require.False(t, strings.HasPrefix(kv, "NOMAD_TOKEN="), kv)
+		require.False(t, strings.HasPrefix(kv, "AWS_PROFILE="), kv)
+	}
+
+	// Configured variables are passed on in addition to the defaults
+	env = sandboxEnv([]string{"AWS_PROFILE"})
+	require.Contains(t, env, "HTTPS_PROXY=http://proxy.example.com:3128")
+	require.Contains(t, env, "AWS_PROFILE=artifacts")
+}
+
+func TestSandbox_Isolate(t *testing.T) {
+	cases := []struct {
+		name        string
+		disable     bool
+		require     bool
+		unsupported bool
+		expected    bool
+	}{
+		{name: "default", expected: true},
+		{name: "disabled", disable: true, expected: false},
+		{name: "unsupported", unsupported: true, expected: false},
+		{name: "required", require: true, unsupported: true, expected: true},
+	}
+	for _, tc := range cases {
+		t.Run(tc.name, func(t *testing.T) {
+			s := testSandbox(t, func(c *config.ArtifactConfig) {
+				c.DisableFilesystemIsolation = tc.disable
+				c.RequireFilesystemIsolation = tc.require
+			})
+			if tc.unsupported {
+				s.isolationUnsupported = 1
+			}
+			require.Equal(t, tc.expected, s.isolate())
+		})
+	}
+}
+
+// TestSandbox_GetArtifact_IsolationUnsupported asserts that downloads fall
+// back to running without filesystem isolation where it isn't supported,
+// unless it is required.
+func TestSandbox_GetArtifact_IsolationUnsupported(t *testing.T) {
+	if runtime.GOOS == "linux" {
+		t.Skip("filesystem isolation is supported on Linux")
+	}
+	ts, _ := countingServer(t)
+	artifact := testShArtifact(ts)
+
+	taskDir := testTaskDir(t)
+	sandbox := testSandbox(t, func(c *config.ArtifactConfig) {
+		c.RequireFilesystemIsolation = true
+	})
+	err := GetArtifact(noopTaskEnv(taskDir), artifact, sandbox)
+	require.Error(t, err)
+	require.False(t, err.(*GetError).IsRecoverable())
+	requireNoStaging(t, taskDir)
+
+	sandbox = testSandbox(t, nil)
+	require.NoError(t, GetArtifact(noopTaskEnv(taskDir), artifact, sandbox))
+	require.Equal(t, int32(1), sandbox.isolationUnsupported)
+	checkContents(taskDir, map[string]string{"test.sh": "sleep 1\n"}, t)
+}
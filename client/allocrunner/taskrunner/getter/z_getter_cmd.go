@@ -0,0 +1,16 @@
+package getter
+
+import (
+	"os"
+)
+
+// Install a handler for downloading artifacts in a sandboxed process, which
+// the client executes itself as.
+// This init() must be initialized last in package required by the child
+// process. It's recommended to avoid any other `init()` or inline any
+// necessary calls here.
+func init() {
+	if len(os.Args) > 1 && os.Args[1] == sandboxCommand {
+		os.Exit(runSandboxed(os.Args[2:]))
+	}
+}
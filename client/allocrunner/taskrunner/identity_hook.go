@@ -0,0 +1,241 @@
+package taskrunner
+
+import (
+	"context"
+	"io/ioutil"
+	"path/filepath"
+	"sync"
+	"time"
+
+	log "github.com/hashicorp/go-hclog"
+	"github.com/hashicorp/nomad/client/allocrunner/interfaces"
+	"github.com/hashicorp/nomad/client/taskenv"
+	"github.com/hashicorp/nomad/nomad/structs"
+	"gopkg.in/square/go-jose.v2/jwt"
+)
+
+const (
+	identityHookName = "identity"
+
+	// identityRenewBackoffBase and identityRenewBackoffLimit bound the time
+	// waited between failed attempts to renew the workload identity.
+	identityRenewBackoffBase  = 5 * time.Second
+	identityRenewBackoffLimit = 1 * time.Minute
+)
+
+type identityUpdateHandler interface {
+	updatedNomadToken(token string)
+}
+
+func (tr *TaskRunner) updatedNomadToken(token string) {
+	// Update the task runner
+	tr.setNomadToken(token)
+
+	// Trigger update hooks with the new workload identity
+	tr.triggerUpdateHooks()
+}
+
+type identityHookConfig struct {
+	alloc   *structs.Allocation
+	task    string
+	node    *structs.Node
+	rpc     RPCer
+	updater identityUpdateHandler
+	logger  log.Logger
+}
+
+// identityHook exposes the task's signed workload identity to the task as
+// configured by its identity block, and renews the identity before it
+// expires.
+type identityHook struct {
+	alloc   *structs.Allocation
+	task    string
+	node    *structs.Node
+	rpc     RPCer
+	updater identityUpdateHandler
+	logger  log.Logger
+
+	// lock guards the fields below
+	lock sync.Mutex
+
+	// tokenPath is the path the workload identity is written to, if the
+	// task's identity block asks for it
+	tokenPath string
+
+	// ctx and cancel are used to stop the renewal loop
+	ctx    context.Context
+	cancel context.CancelFunc
+}
+
+func newIdentityHook(config *identityHookConfig) *identityHook {
+	h := &identityHook{
+		alloc:   config.alloc,
+		task:    config.task,
+		node:    config.node,
+		rpc:     config.rpc,
+		updater: config.updater,
+	}
+	h.logger = config.logger.Named(h.Name())
+	return h
+}
+
+func (*identityHook) Name() string {
+	return identityHookName
+}
+
+func (h *identityHook) Prestart(ctx context.Context, req *interfaces.TaskPrestartRequest, resp *interfaces.TaskPrestartResponse) error {
+	token := req.NomadToken
+	if token == "" {
+		// Allocations placed while the keyring was uninitialized have no
+		// signed identities
+		h.logger.Debug("no workload identity signed for task")
+		resp.Done = true
+		return nil
+	}
+
+	h.lock.Lock()
+	defer h.lock.Unlock()
+
+	// The token is rewritten on every prestart, since the secrets dir is
+	// not persisted across client restarts.
+	if req.Task.Identity != nil && req.Task.Identity.File {
+		h.tokenPath = filepath.Join(req.TaskDir.SecretsDir, structs.WorkloadIdentityFile)
+		if err := ioutil.WriteFile(h.tokenPath, []byte(token), 0600); err != nil {
+			return err
+		}
+	}
+
+	if req.Task.Identity != nil && req.Task.Identity.Env {
+		resp.Env = map[string]string{
+			taskenv.WorkloadToken: token,
+		}
+	}
+
+	// The identity is also used by the client on the task's behalf, so it
+	// is renewed even if it isn't exposed to the task.
+	if h.ctx == nil {
+		h.ctx, h.cancel = context.WithCancel(context.Background())
+		go h.run(token)
+	}
+
+	return nil
+}
+
+func (h *identityHook) Stop(ctx context.Context, req *interfaces.TaskStopRequest, resp *interfaces.TaskStopResponse) error {
+	h.lock.Lock()
+	defer h.lock.Unlock()
+
+	if h.cancel != nil {
+		h.cancel()
+	}
+	return nil
+}
+
+// run renews the workload identity once half of its lifetime has elapsed
+// until the hook is stopped. Identities without an expiry are not renewed.
+func (h *identityHook) run(token string) {
+	for {
+		renewAt, ok := identityRenewTime(token)
+		if !ok {
+			return
+		}
+
+		timer := time.NewTimer(time.Until(renewAt))
+		select {
+		case <-h.ctx.Done():
+			timer.Stop()
+			return
+		case <-timer.C:
+		}
+
+		newToken, err := h.renew()
+		if err != nil {
+			return
+		}
+		token = newToken
+	}
+}
+
+// renew requests a new workload identity from the servers, retrying with
+// backoff until it succeeds or the hook is stopped.
+func (h *identityHook) renew() (string, error) {
+	backoff := identityRenewBackoffBase
+	for {
+		token, err := h.sign()
+		if err == nil {
+			if err := h.setToken(token); err != nil {
+				h.logger.Error("failed to write renewed workload identity", "error", err)
+			}
+			return token, nil
+		}
+
+		h.logger.Warn("failed to renew workload identity", "error", err, "retry", backoff)
+		select {
+		case <-h.ctx.Done():
+			return "", h.ctx.Err()
+		case <-time.After(backoff):
+		}
+
+		backoff *= 2
+		if backoff > identityRenewBackoffLimit {
+			backoff = identityRenewBackoffLimit
+		}
+	}
+}
+
+// sign asks the servers to sign a new workload identity for the task.
+func (h *identityHook) sign() (string, error) {
+	req := &structs.SignIdentitiesRequest{
+		NodeID:   h.node.ID,
+		SecretID: h.node.SecretID,
+		AllocID:  h.alloc.ID,
+		Tasks:    []string{h.task},
+		QueryOptions: structs.QueryOptions{
+			Region:     h.alloc.Job.Region,
+			AllowStale: true,
+		},
+	}
+	var resp structs.SignIdentitiesResponse
+	if err := h.rpc.RPC("Node.SignIdentities", req, &resp); err != nil {
+		return "", err
+	}
+	return resp.SignedIdentities[h.task], nil
+}
+
+// setToken rewrites the token file, if any, and hands the new workload
+// identity to the task runner. The task's environment is only updated the
+// next time the task is started.
+func (h *identityHook) setToken(token string) error {
+	h.lock.Lock()
+	tokenPath := h.tokenPath
+	h.lock.Unlock()
+
+	h.updater.updatedNomadToken(token)
+
+	if tokenPath == "" {
+		return nil
+	}
+	return ioutil.WriteFile(tokenPath, []byte(token), 0600)
+}
+
+// identityRenewTime returns when the workload identity should be renewed,
+// which is halfway through its lifetime. It returns false if the identity
+// can't be parsed or never expires.
+func identityRenewTime(token string) (time.Time, bool) {
+	parsed, err := jwt.ParseSigned(token)
+	if err != nil {
+		return time.Time{}, false
+	}
+
+	// The servers verify the token; the client only needs its lifetime.
+	var claims jwt.Claims
+	if err := parsed.UnsafeClaimsWithoutVerification(&claims); err != nil {
+		return time.Time{}, false
+	}
+	if claims.Expiry == nil || claims.IssuedAt == nil {
+		return time.Time{}, false
+	}
+
+	issued, expiry := claims.IssuedAt.Time(), claims.Expiry.Time()
+	return issued.Add(expiry.Sub(issued) / 2), true
+}
@@ -0,0 +1,194 @@
+package taskrunner
+
+import (
+	"context"
+	"crypto/ed25519"
+	"crypto/rand"
+	"fmt"
+	"io/ioutil"
+	"os"
+	"path/filepath"
+	"sync"
+	"testing"
+	"time"
+
+	"github.com/hashicorp/go-hclog"
+	"github.com/hashicorp/nomad/client/allocdir"
+	"github.com/hashicorp/nomad/client/allocrunner/interfaces"
+	"github.com/hashicorp/nomad/client/taskenv"
+	"github.com/hashicorp/nomad/helper/testlog"
+	"github.com/hashicorp/nomad/nomad/mock"
+	"github.com/hashicorp/nomad/nomad/structs"
+	"github.com/hashicorp/nomad/testutil"
+	"github.com/stretchr/testify/require"
+	jose "gopkg.in/square/go-jose.v2"
+	"gopkg.in/square/go-jose.v2/jwt"
+)
+
+// Statically assert the identity hook implements the expected interfaces
+var _ interfaces.TaskPrestartHook = (*identityHook)(nil)
+var _ interfaces.TaskStopHook = (*identityHook)(nil)
+
+// mockIdentitySigner implements RPCer and identityUpdateHandler to sign and
+// record renewed workload identities.
+type mockIdentitySigner struct {
+	lock    sync.Mutex
+	signed  int
+	updated []string
+}
+
+func (m *mockIdentitySigner) RPC(method string, args interface{}, reply interface{}) error {
+	m.lock.Lock()
+	defer m.lock.Unlock()
+	m.signed++
+	req := args.(*structs.SignIdentitiesRequest)
+	resp := reply.(*structs.SignIdentitiesResponse)
+	resp.SignedIdentities = map[string]string{req.Tasks[0]: "renewed"}
+	return nil
+}
+
+func (m *mockIdentitySigner) updatedNomadToken(token string) {
+	m.lock.Lock()
+	defer m.lock.Unlock()
+	m.updated = append(m.updated, token)
+}
+
+func testIdentityHook(alloc *structs.Allocation, logger hclog.Logger, signer *mockIdentitySigner) *identityHook {
+	return newIdentityHook(&identityHookConfig{
+		alloc:   alloc,
+		task:    alloc.Job.TaskGroups[0].Tasks[0].Name,
+		node:    mock.Node(),
+		rpc:     signer,
+		updater: signer,
+		logger:  logger,
+	})
+}
+
+// TestTaskRunner_IdentityHook_NoIdentity asserts that the workload identity
+// isn't exposed if the task has no identity block.
+func TestTaskRunner_IdentityHook_NoIdentity(t *testing.T) {
+	t.Parallel()
+
+	require := require.New(t)
+	logger := testlog.HCLogger(t)
+	allocDir := allocdir.NewAllocDir(logger, "nomadtest_noidentity")
+	defer allocDir.Destroy()
+
+	alloc := mock.BatchAlloc()
+	task := alloc.Job.TaskGroups[0].Tasks[0]
+	alloc.SignedIdentities = map[string]string{task.Name: "a.b.c"}
+	taskDir := allocDir.NewTaskDir(task.Name)
+	require.NoError(taskDir.Build(false, nil))
+
+	h := testIdentityHook(alloc, logger, &mockIdentitySigner{})
+	defer h.Stop(context.Background(), nil, nil)
+
+	req := interfaces.TaskPrestartRequest{
+		Task:       task,
+		TaskDir:    taskDir,
+		NomadToken: "a.b.c",
+	}
+	resp := interfaces.TaskPrestartResponse{}
+
+	require.NoError(h.Prestart(context.Background(), &req, &resp))
+	require.Empty(resp.Env)
+
+	_, err := os.Stat(filepath.Join(req.TaskDir.SecretsDir, structs.WorkloadIdentityFile))
+	require.True(os.IsNotExist(err))
+}
+
+// TestTaskRunner_IdentityHook_Ok asserts that the workload identity is
+// exposed as an environment variable and a file in the secrets dir.
+func TestTaskRunner_IdentityHook_Ok(t *testing.T) {
+	t.Parallel()
+
+	require := require.New(t)
+	logger := testlog.HCLogger(t)
+	allocDir := allocdir.NewAllocDir(logger, "nomadtest_identity")
+	defer allocDir.Destroy()
+
+	alloc := mock.BatchAlloc()
+	task := alloc.Job.TaskGroups[0].Tasks[0]
+	task.Identity = &structs.WorkloadIdentity{
+		Env:  true,
+		File: true,
+	}
+	alloc.SignedIdentities = map[string]string{task.Name: "a.b.c"}
+	taskDir := allocDir.NewTaskDir(task.Name)
+	require.NoError(taskDir.Build(false, nil))
+
+	h := testIdentityHook(alloc, logger, &mockIdentitySigner{})
+	defer h.Stop(context.Background(), nil, nil)
+
+	req := interfaces.TaskPrestartRequest{
+		Task:       task,
+		TaskDir:    taskDir,
+		NomadToken: "a.b.c",
+	}
+	resp := interfaces.TaskPrestartResponse{}
+
+	require.NoError(h.Prestart(context.Background(), &req, &resp))
+	require.False(resp.Done)
+	require.Equal("a.b.c", resp.Env[taskenv.WorkloadToken])
+
+	data, err := ioutil.ReadFile(filepath.Join(req.TaskDir.SecretsDir, structs.WorkloadIdentityFile))
+	require.NoError(err)
+	require.Equal("a.b.c", string(data))
+}
+
+// TestTaskRunner_IdentityHook_Renew asserts that the workload identity is
+// renewed once half its lifetime has elapsed.
+func TestTaskRunner_IdentityHook_Renew(t *testing.T) {
+	t.Parallel()
+
+	require := require.New(t)
+	logger := testlog.HCLogger(t)
+	allocDir := allocdir.NewAllocDir(logger, "nomadtest_identity_renew")
+	defer allocDir.Destroy()
+
+	alloc := mock.BatchAlloc()
+	task := alloc.Job.TaskGroups[0].Tasks[0]
+	task.Identity = &structs.WorkloadIdentity{File: true}
+	taskDir := allocDir.NewTaskDir(task.Name)
+	require.NoError(taskDir.Build(false, nil))
+
+	// Sign an identity which is already past half of its lifetime
+	_, key, err := ed25519.GenerateKey(rand.Reader)
+	require.NoError(err)
+	signer, err := jose.NewSigner(jose.SigningKey{Algorithm: jose.EdDSA, Key: key}, nil)
+	require.NoError(err)
+	now := time.Now()
+	claims := structs.NewIdentityClaims(alloc, task.Name, now.Add(-structs.WorkloadIdentityTTL+time.Minute))
+	token, err := jwt.Signed(signer).Claims(claims).CompactSerialize()
+	require.NoError(err)
+
+	mockSigner := &mockIdentitySigner{}
+	h := testIdentityHook(alloc, logger, mockSigner)
+	defer h.Stop(context.Background(), nil, nil)
+
+	req := interfaces.TaskPrestartRequest{
+		Task:       task,
+		TaskDir:    taskDir,
+		NomadToken: token,
+	}
+	require.NoError(h.Prestart(context.Background(), &req, &interfaces.TaskPrestartResponse{}))
+
+	tokenPath := filepath.Join(req.TaskDir.SecretsDir, structs.WorkloadIdentityFile)
+	testutil.WaitForResult(func() (bool, error) {
+		data, err := ioutil.ReadFile(tokenPath)
+		if err != nil {
+			return false, err
+		}
+		if string(data) != "renewed" {
+			return false, fmt.Errorf("identity not renewed: %q", data)
+		}
+		return true, nil
+	}, func(err error) {
+		require.NoError(err)
+	})
+
+	mockSigner.lock.Lock()
+	defer mockSigner.lock.Unlock()
+	require.Equal(1, mockSigner.signed)
+	require.Equal([]string{"renewed"}, mockSigner.updated)
+}
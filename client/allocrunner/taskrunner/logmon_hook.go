@@ -11,7 +11,10 @@ import (
 	hclog "github.com/hashicorp/go-hclog"
 	plugin "github.com/hashicorp/go-plugin"
 	"github.com/hashicorp/nomad/client/allocrunner/interfaces"
+	ti "github.com/hashicorp/nomad/client/allocrunner/taskrunner/interfaces"
 	"github.com/hashicorp/nomad/client/logmon"
+	"github.com/hashicorp/nomad/client/logmon/logging"
+	"github.com/hashicorp/nomad/helper"
 	"github.com/hashicorp/nomad/helper/uuid"
 	"github.com/hashicorp/nomad/nomad/structs"
 	bstructs "github.com/hashicorp/nomad/plugins/base/structs"
@@ -25,6 +28,10 @@ const (
 	// logmonReattachKey is the HookData key where logmon's reattach config
 	// is stored.
 	logmonReattachKey = "reattach_config"
+
+	// logsDroppedCheckInterval is the interval at which logmon is checked for
+	// output dropped by the task's log rate limit.
+	logsDroppedCheckInterval = 30 * time.Second
 )
 
 // logmonHook launches logmon and manages task logging
@@ -37,6 +44,15 @@ type logmonHook struct {
 
 	config *logmonHookConfig
 
+	// droppedCheckInterval is the interval at which dropped logs are checked
+	droppedCheckInterval time.Duration
+
+	// cancelDroppedWatch stops watching for dropped logs, if started
+	cancelDroppedWatch context.CancelFunc
+
+	// eventEmitter is used to emit events to the task
+	eventEmitter ti.EventEmitter
+
 	logger hclog.Logger
 }
 
@@ -48,9 +64,11 @@ type logmonHookConfig struct {
 
 func newLogMonHook(tr *TaskRunner, logger hclog.Logger) *logmonHook {
 	hook := &logmonHook{
-		runner: tr,
-		config: tr.logmonHookConfig,
-		logger: logger,
+		runner:               tr,
+		config:               tr.logmonHookConfig,
+		droppedCheckInterval: logsDroppedCheckInterval,
+		eventEmitter:         tr,
+		logger:               logger,
 	}
 
 	return hook
@@ -129,6 +147,10 @@ func (h *logmonHook) Prestart(ctx context.Context,
 			return err
 		}
 
+		if req.Task.LogConfig.RateLimit != nil {
+			h.watchDropped(h.logmon)
+		}
+
 		rCfg := pstructs.ReattachConfigFromGoPlugin(h.logmonPluginClient.ReattachConfig())
 		jsonCfg, err := json.Marshal(rCfg)
 		if err != nil {
@@ -176,15 +198,24 @@ func (h *logmonHook) prestartOneLoop(ctx context.Context, req *interfaces.TaskPr
 		}
 	}
 
-	err := h.logmon.Start(&logmon.LogConfig{
-		LogDir:        h.config.logDir,
-		StdoutLogFile: fmt.Sprintf("%s.stdout", req.Task.Name),
-		StderrLogFile: fmt.Sprintf("%s.stderr", req.Task.Name),
-		StdoutFifo:    h.config.stdoutFifo,
-		StderrFifo:    h.config.stderrFifo,
-		MaxFiles:      req.Task.LogConfig.MaxFiles,
-		MaxFileSizeMB: req.Task.LogConfig.MaxFileSizeMB,
-	})
+	cfg := &logmon.LogConfig{
+		LogDir:          h.config.logDir,
+		StdoutLogFile:   fmt.Sprintf("%s.stdout", req.Task.Name),
+		StderrLogFile:   fmt.Sprintf("%s.stderr", req.Task.Name),
+		StdoutFifo:      h.config.stdoutFifo,
+		StderrFifo:      h.config.stderrFifo,
+		MaxFiles:        req.Task.LogConfig.MaxFiles,
+		MaxFileSizeMB:   req.Task.LogConfig.MaxFileSizeMB,
+		Sinks:           h.logSinks(req.Task),
+		TaskMeta:        h.taskMeta(req.Task),
+		CompressRotated: req.Task.LogConfig.CompressRotated,
+	}
+	if rateLimit := req.Task.LogConfig.RateLimit; rateLimit != nil {
+		cfg.RateLimitLines = rateLimit.LinesPerSecond
+		cfg.RateLimitBytes = rateLimit.BytesPerSecond
+	}
+
+	err := h.logmon.Start(cfg)
 	if err != nil {
 		h.logger.Error("failed to start logmon", "error", err)
 		return err
@@ -193,7 +224,151 @@ func (h *logmonHook) prestartOneLoop(ctx context.Context, req *interfaces.TaskPr
 	return nil
 }
 
+// watchDropped starts watching for output dropped by the task's log rate limit,
+// emitting a task event whenever more was dropped. Any previous watch is
+// stopped.
+func (h *logmonHook) watchDropped(l logmon.LogMon) {
+	h.stopWatchDropped()
+
+	// Only report what is dropped from now on, as logmon may have been
+	// reattached to after the previous drops were reported
+	stats, err := l.Stats()
+	if err != nil {
+		// Older logmon processes don't support stats
+		h.logger.Debug("failed to get logmon stats, not reporting dropped logs", "error", err)
+		return
+	}
+	last := *stats
+
+	ctx, cancel := context.WithCancel(context.Background())
+	h.cancelDroppedWatch = cancel
+
+	go func() {
+		ticker := time.NewTicker(h.droppedCheckInterval)
+		defer ticker.Stop()
+
+		for {
+			select {
+			case <-ctx.Done():
+				return
+			case <-ticker.C:
+			}
+
+			stats, err := l.Stats()
+			if err != nil {
+				h.logger.Debug("failed to get logmon stats, no longer reporting dropped logs", "error", err)
+				return
+			}
+
+			// The counters are reset when logmon restarts logging for the task
+			if stats.DroppedLines < last.DroppedLines || stats.DroppedBytes < last.DroppedBytes {
+				last = logmon.LogStats{}
+			}
+
+			if lines := stats.DroppedLines - last.DroppedLines; lines > 0 {
+				bytes := stats.DroppedBytes - last.DroppedBytes
+				event := structs.NewTaskEvent(structs.TaskLogsDropped).
+					SetMessage(fmt.Sprintf("Log rate limit exceeded, dropped %d lines (%d bytes)", lines, bytes))
+				h.eventEmitter.EmitEvent(event)
+			}
+			last = *stats
+		}
+	}()
+}
+
+func (h *logmonHook) stopWatchDropped() {
+	if h.cancelDroppedWatch != nil {
+		h.cancelDroppedWatch()
+		h.cancelDroppedWatch = nil
+	}
+}
+
+// logSinks returns the log sinks of the client followed by the log sinks of
+// the task. Paths of task file sinks are relative to the log directory, and
+// the other task sinks may only ship to destinations the client allows.
+func (h *logmonHook) logSinks(task *structs.Task) []*logging.SinkConfig {
+	var sinks []*logging.SinkConfig
+	if h.runner.clientConfig != nil {
+		for _, sink := range h.runner.clientConfig.LogSinks {
+			sinks = append(sinks, logSinkConfig(sink, sink.Path))
+		}
+	}
+	for _, sink := range task.LogConfig.Sinks {
+		path := sink.Path
+		if sink.Type == structs.LogSinkTypeFile {
+			// Jobs are validated on submission, but the client must never
+			// write outside of the log directory on their behalf
+			if filepath.IsAbs(sink.Path) || helper.PathEscapesSandbox(h.config.logDir, filepath.Join(h.config.logDir, sink.Path)) {
+				h.logger.Error("skipping log sink with path outside of the log directory", "path", sink.Path)
+				continue
+			}
+			path = filepath.Clean(sink.Path)
+		} else if !h.logSinkAllowed(sink) {
+			h.logger.Error("skipping log sink with an address not allowed by the client", "type", sink.Type, "address", sink.Address)
+			continue
+		}
+		sinks = append(sinks, logSinkConfig(sink, path))
+	}
+	return sinks
+}
+
+// logSinkAllowed returns whether a task may ship its output to the address
+// of a network or syslog sink. Only the addresses of the client's own sinks
+// and the addresses the client explicitly allows may be used.
+func (h *logmonHook) logSinkAllowed(sink *structs.LogSink) bool {
+	if h.runner.clientConfig == nil {
+		return false
+	}
+
+	address := logSinkAddress(sink)
+	for _, allowed := range h.runner.clientConfig.LogSinkAllowedAddresses {
+		if address == allowed {
+			return true
+		}
+	}
+	for _, clientSink := range h.runner.clientConfig.LogSinks {
+		if clientSink.Type == sink.Type && logSinkAddress(clientSink) == address {
+			return true
+		}
+	}
+	return false
+}
+
+// logSinkAddress returns the address a sink ships to, including the default
+// address of syslog sinks.
+func logSinkAddress(sink *structs.LogSink) string {
+	if sink.Type == structs.LogSinkTypeSyslog && sink.Address == "" {
+		return logging.DefaultSyslogAddress
+	}
+	return sink.Address
+}
+
+func logSinkConfig(sink *structs.LogSink, path string) *logging.SinkConfig {
+	return &logging.SinkConfig{
+		Type:       sink.Type,
+		Address:    sink.Address,
+		Path:       path,
+		BufferSize: sink.BufferSize,
+	}
+}
+
+// taskMeta returns the metadata identifying the task in the lines shipped to
+// log sinks.
+func (h *logmonHook) taskMeta(task *structs.Task) logging.TaskMeta {
+	meta := logging.TaskMeta{
+		AllocID:  h.runner.allocID,
+		TaskName: task.Name,
+	}
+	if alloc := h.runner.Alloc(); alloc != nil {
+		meta.Namespace = alloc.Namespace
+		meta.JobID = alloc.JobID
+		meta.TaskGroup = alloc.TaskGroup
+	}
+	return meta
+}
+
 func (h *logmonHook) Stop(_ context.Context, req *interfaces.TaskStopRequest, _ *interfaces.TaskStopResponse) error {
+	h.stopWatchDropped()
 
 	// It's possible that Stop was called without calling Prestart on agent
 	// restarts. Attempt to reattach to an existing logmon.
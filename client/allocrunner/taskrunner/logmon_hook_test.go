@@ -6,14 +6,20 @@ import (
 	"io/ioutil"
 	"net"
 	"os"
+	"sync"
 	"testing"
+	"time"
 
 	plugin "github.com/hashicorp/go-plugin"
 	"github.com/hashicorp/nomad/client/allocrunner/interfaces"
+	"github.com/hashicorp/nomad/client/config"
+	"github.com/hashicorp/nomad/client/logmon"
 	"github.com/hashicorp/nomad/helper"
 	"github.com/hashicorp/nomad/helper/testlog"
 	"github.com/hashicorp/nomad/nomad/mock"
+	"github.com/hashicorp/nomad/nomad/structs"
 	pstructs "github.com/hashicorp/nomad/plugins/shared/structs"
+	"github.com/hashicorp/nomad/testutil"
 	"github.com/stretchr/testify/require"
 )
 
@@ -56,6 +62,52 @@ func TestTaskRunner_LogmonHook_LoadReattach(t *testing.T) {
 	require.Equal(t, orig, cfg)
 }
 
+// TestTaskRunner_LogmonHook_LogSinks asserts that task file sinks are
+// relative to the log directory and skipped if they escape it, and that the
+// other task sinks are skipped unless the client allows their address.
+func TestTaskRunner_LogmonHook_LogSinks(t *testing.T) {
+	t.Parallel()
+
+	alloc := mock.BatchAlloc()
+	task := alloc.Job.TaskGroups[0].Tasks[0]
+	task.LogConfig.Sinks = []*structs.LogSink{
+		{Type: structs.LogSinkTypeFile, Path: "web.json"},
+		{Type: structs.LogSinkTypeFile, Path: "../../../etc/cron.d/evil"},
+		{Type: structs.LogSinkTypeFile, Path: "/etc/cron.d/evil"},
+		{Type: structs.LogSinkTypeTCP, Address: "logs.example.com:5000"},
+		{Type: structs.LogSinkTypeTCP, Address: "127.0.0.1:22"},
+		{Type: structs.LogSinkTypeSyslog},
+		{Type: structs.LogSinkTypeSyslog, Address: "unix:///run/docker.sock"},
+		{Type: structs.LogSinkTypeHTTP, Address: "http://169.254.169.254/"},
+	}
+
+	hookConf := newLogMonHookConfig(task.Name, "/alloc/logs")
+	runner := &TaskRunner{
+		logmonHookConfig: hookConf,
+		clientConfig: &config.Config{
+			LogSinks: []*structs.LogSink{
+				{Type: structs.LogSinkTypeSyslog},
+			},
+			LogSinkAllowedAddresses: []string{"logs.example.com:5000"},
+		},
+	}
+	hook := newLogMonHook(runner, testlog.HCLogger(t))
+
+	sinks := hook.logSinks(task)
+	require.Len(t, sinks, 4)
+	require.Equal(t, structs.LogSinkTypeSyslog, sinks[0].Type)
+	require.Equal(t, "web.json", sinks[1].Path)
+	require.Equal(t, "logs.example.com:5000", sinks[2].Address)
+	require.Equal(t, structs.LogSinkTypeSyslog, sinks[3].Type)
+	require.Empty(t, sinks[3].Address)
+
+	// Without client configuration only file sinks are used
+	runner.clientConfig = nil
+	sinks = hook.logSinks(task)
+	require.Len(t, sinks, 1)
+	require.Equal(t, "web.json", sinks[0].Path)
+}
+
 // TestTaskRunner_LogmonHook_StartStop asserts that a new logmon is created the
 // first time Prestart is called, reattached to on subsequent restarts, and
 // killed on Stop.
@@ -105,3 +157,68 @@ func TestTaskRunner_LogmonHook_StartStop(t *testing.T) {
 	}
 	require.NoError(t, hook.Stop(context.Background(), &stopReq, nil))
 }
+
+// mockLogMon is a LogMon returning the stats it is set to
+type mockLogMon struct {
+	lock  sync.Mutex
+	stats logmon.LogStats
+}
+
+func (m *mockLogMon) Start(*logmon.LogConfig) error { return nil }
+func (m *mockLogMon) Stop() error                   { return nil }
+
+func (m *mockLogMon) Stats() (*logmon.LogStats, error) {
+	m.lock.Lock()
+	defer m.lock.Unlock()
+	stats := m.stats
+	return &stats, nil
+}
+
+func (m *mockLogMon) setDropped(lines, bytes uint64) {
+	m.lock.Lock()
+	defer m.lock.Unlock()
+	m.stats = logmon.LogStats{DroppedLines: lines, DroppedBytes: bytes}
+}
+
+// TestTaskRunner_LogmonHook_WatchDropped asserts that output dropped by the
+// log rate limit is reported as task events, once.
+func TestTaskRunner_LogmonHook_WatchDropped(t *testing.T) {
+	t.Parallel()
+
+	me := &mockEmitter{}
+	hook := &logmonHook{
+		droppedCheckInterval: 10 * time.Millisecond,
+		eventEmitter:         me,
+		logger:               testlog.HCLogger(t),
+	}
+
+	// Drops from before the watch started are not reported
+	l := &mockLogMon{}
+	l.setDropped(5, 50)
+	hook.watchDropped(l)
+	defer hook.stopWatchDropped()
+
+	l.setDropped(8, 80)
+	testutil.WaitForResult(func() (bool, error) {
+		return len(me.Events()) == 1, nil
+	}, func(error) {
+		t.Fatalf("expected 1 event, got %d", len(me.Events()))
+	})
+
+	// Lower counters mean logging was restarted with new counters
+	l.setDropped(2, 20)
+	testutil.WaitForResult(func() (bool, error) {
+		return len(me.Events()) == 2, nil
+	}, func(error) {
+		t.Fatalf("expected 2 events, got %d", len(me.Events()))
+	})
+
+	events := me.Events()
+	require.Equal(t, structs.TaskLogsDropped, events[0].Type)
+	require.Equal(t, "Log rate limit exceeded, dropped 3 lines (30 bytes)", events[0].Message)
+	require.Equal(t, "Log rate limit exceeded, dropped 2 lines (20 bytes)", events[1].Message)
+
+	// Nothing is reported when nothing more was dropped
+	time.Sleep(50 * time.Millisecond)
+	require.Len(t, me.Events(), 2)
+}
@@ -0,0 +1,134 @@
+package taskrunner
+
+import (
+	"context"
+	"fmt"
+	"strconv"
+	"sync"
+	"time"
+
+	log "github.com/hashicorp/go-hclog"
+	"github.com/hashicorp/nomad/client/allocrunner/interfaces"
+	ti "github.com/hashicorp/nomad/client/allocrunner/taskrunner/interfaces"
+	"github.com/hashicorp/nomad/nomad/structs"
+)
+
+const (
+	// HookNameMaxRunDuration is the name of the max run duration hook
+	HookNameMaxRunDuration = "max_run_duration"
+
+	// maxRunDurationStartedAtKey is the hook state key that persists when
+	// the task first started, so restarts of the task or the client don't
+	// reset its deadline.
+	maxRunDurationStartedAtKey = "started_at"
+)
+
+// maxRunDurationHook kills a task once it has run for longer than the
+// max_run_duration of its task group.
+type maxRunDurationHook struct {
+	lifecycle ti.TaskLifecycle
+	maxRun    time.Duration
+	logger    log.Logger
+
+	// startedAt is when the task first started
+	startedAt time.Time
+
+	// cancel stops the deadline timer and is called when the task exits
+	cancel context.CancelFunc
+
+	mu sync.Mutex
+}
+
+func newMaxRunDurationHook(lifecycle ti.TaskLifecycle, maxRun time.Duration, logger log.Logger) *maxRunDurationHook {
+	h := &maxRunDurationHook{
+		lifecycle: lifecycle,
+		maxRun:    maxRun,
+	}
+	h.logger = logger.Named(h.Name())
+	return h
+}
+
+func (*maxRunDurationHook) Name() string {
+	return HookNameMaxRunDuration
+}
+
+func (h *maxRunDurationHook) Prestart(_ context.Context, req *interfaces.TaskPrestartRequest, resp *interfaces.TaskPrestartResponse) error {
+	h.mu.Lock()
+	defer h.mu.Unlock()
+
+	startedAt := time.Now()
+	if prev, ok := req.PreviousState[maxRunDurationStartedAtKey]; ok {
+		nanos, err := strconv.ParseInt(prev, 10, 64)
+		if err != nil {
+			h.logger.Warn("failed to parse persisted start time, resetting deadline", "error", err)
+		} else {
+			startedAt = time.Unix(0, nanos)
+		}
+	}
+	h.startedAt = startedAt
+
+	resp.State = map[string]string{
+		maxRunDurationStartedAtKey: strconv.FormatInt(startedAt.UnixNano(), 10),
+	}
+	return nil
+}
+
+func (h *maxRunDurationHook) Poststart(_ context.Context, _ *interfaces.TaskPoststartRequest, _ *interfaces.TaskPoststartResponse) error {
+	h.mu.Lock()
+	defer h.mu.Unlock()
+
+	if h.cancel != nil {
+		h.cancel()
+	}
+
+	// The Poststart context is only valid for the scope of the request, so
+	// the deadline is tracked with a new context canceled when the task
+	// exits.
+	ctx, cancel := context.WithCancel(context.Background())
+	h.cancel = cancel
+	go h.enforce(ctx, time.Until(h.startedAt.Add(h.maxRun)))
+
+	return nil
+}
+
+// enforce kills the task once remaining has elapsed, unless ctx is canceled
+// first.
+func (h *maxRunDurationHook) enforce(ctx context.Context, remaining time.Duration) {
+	timer := time.NewTimer(remaining)
+	defer timer.Stop()
+
+	select {
+	case <-ctx.Done():
+		return
+	case <-timer.C:
+	}
+
+	h.logger.Info("task exceeded max run duration, killing", "max_run_duration", h.maxRun)
+	event := structs.NewTaskEvent(structs.TaskMaxRunDurationExceeded).
+		SetFailsTask().
+		SetDisplayMessage(fmt.Sprintf("Task exceeded max run duration of %v", h.maxRun))
+	if err := h.lifecycle.Kill(context.Background(), event); err != nil {
+		h.logger.Error("failed to kill task after exceeding max run duration", "error", err)
+	}
+}
+
+func (h *maxRunDurationHook) Exited(context.Context, *interfaces.TaskExitedRequest, *interfaces.TaskExitedResponse) error {
+	h.stop()
+	return nil
+}
+
+func (h *maxRunDurationHook) Stop(context.Context, *interfaces.TaskStopRequest, *interfaces.TaskStopResponse) error {
+	h.stop()
+	return nil
+}
+
+// stop cancels the deadline timer if it is running.
+func (h *maxRunDurationHook) stop() {
+	h.mu.Lock()
+	defer h.mu.Unlock()
+
+	if h.cancel != nil {
+		h.cancel()
+		h.cancel = nil
+	}
+}
@@ -0,0 +1,105 @@
+package taskrunner
+
+import (
+	"context"
+	"strconv"
+	"testing"
+	"time"
+
+	"github.com/hashicorp/nomad/client/allocrunner/interfaces"
+	"github.com/hashicorp/nomad/helper/testlog"
+	"github.com/hashicorp/nomad/nomad/structs"
+	"github.com/stretchr/testify/require"
+)
+
+// Statically assert the max run duration hook implements the expected interfaces
+var _ interfaces.TaskPrestartHook = (*maxRunDurationHook)(nil)
+var _ interfaces.TaskPoststartHook = (*maxRunDurationHook)(nil)
+var _ interfaces.TaskExitedHook = (*maxRunDurationHook)(nil)
+var _ interfaces.TaskStopHook = (*maxRunDurationHook)(nil)
+
+// mockKillLifecycle records the events of Kill calls.
+type mockKillLifecycle struct {
+	kills chan *structs.TaskEvent
+}
+
+func newMockKillLifecycle() *mockKillLifecycle {
+	return &mockKillLifecycle{kills: make(chan *structs.TaskEvent, 1)}
+}
+
+func (m *mockKillLifecycle) Restart(context.Context, *structs.TaskEvent, bool) error { return nil }
+func (m *mockKillLifecycle) Signal(*structs.TaskEvent, string) error                 { return nil }
+func (m *mockKillLifecycle) IsRunning() bool                                         { return true }
+
+func (m *mockKillLifecycle) Kill(_ context.Context, event *structs.TaskEvent) error {
+	m.kills <- event
+	return nil
+}
+
+// TestTaskRunner_MaxRunDurationHook_Kill asserts that the hook kills the task
+// and marks it failed once the max run duration elapses.
+func TestTaskRunner_MaxRunDurationHook_Kill(t *testing.T) {
+	t.Parallel()
+
+	lifecycle := newMockKillLifecycle()
+	h := newMaxRunDurationHook(lifecycle, 50*time.Millisecond, testlog.HCLogger(t))
+
+	resp := &interfaces.TaskPrestartResponse{}
+	require.NoError(t, h.Prestart(context.Background(), &interfaces.TaskPrestartRequest{}, resp))
+	require.Contains(t, resp.State, maxRunDurationStartedAtKey)
+	require.NoError(t, h.Poststart(context.Background(), &interfaces.TaskPoststartRequest{}, nil))
+
+	select {
+	case event := <-lifecycle.kills:
+		require.Equal(t, structs.TaskMaxRunDurationExceeded, event.Type)
+		require.True(t, event.FailsTask)
+	case <-time.After(5 * time.Second):
+		t.Fatal("timed out waiting for task to be killed")
+	}
+}
+
+// TestTaskRunner_MaxRunDurationHook_Restore asserts that the deadline is
+// measured from the persisted start time rather than the latest start.
+func TestTaskRunner_MaxRunDurationHook_Restore(t *testing.T) {
+	t.Parallel()
+
+	lifecycle := newMockKillLifecycle()
+	h := newMaxRunDurationHook(lifecycle, time.Hour, testlog.HCLogger(t))
+
+	startedAt := time.Now().Add(-2 * time.Hour).UnixNano()
+	req := &interfaces.TaskPrestartRequest{
+		PreviousState: map[string]string{
+			maxRunDurationStartedAtKey: strconv.FormatInt(startedAt, 10),
+		},
+	}
+	resp := &interfaces.TaskPrestartResponse{}
+	require.NoError(t, h.Prestart(context.Background(), req, resp))
+	require.Equal(t, req.PreviousState, resp.State)
+	require.NoError(t, h.Poststart(context.Background(), &interfaces.TaskPoststartRequest{}, nil))
+
+	select {
+	case event := <-lifecycle.kills:
+		require.Equal(t, structs.TaskMaxRunDurationExceeded, event.Type)
+	case <-time.After(5 * time.Second):
+		t.Fatal("timed out waiting for task to be killed")
+	}
+}
+
+// TestTaskRunner_MaxRunDurationHook_Exited asserts that tasks exiting before
+// the max run duration elapses are not killed.
+func TestTaskRunner_MaxRunDurationHook_Exited(t *testing.T) {
+	t.Parallel()
+
+	lifecycle := newMockKillLifecycle()
+	h := newMaxRunDurationHook(lifecycle, 100*time.Millisecond, testlog.HCLogger(t))
+
+	require.NoError(t, h.Prestart(context.Background(), &interfaces.TaskPrestartRequest{}, &interfaces.TaskPrestartResponse{}))
+	require.NoError(t, h.Poststart(context.Background(), &interfaces.TaskPoststartRequest{}, nil))
+	require.NoError(t, h.Exited(context.Background(), &interfaces.TaskExitedRequest{}, nil))
+
+	select {
+	case <-lifecycle.kills:
+		t.Fatal("unexpected kill of exited task")
+	case <-time.After(300 * time.Millisecond):
+	}
+}
@@ -35,6 +35,8 @@ type serviceHookConfig struct {
 
 type serviceHook struct {
 	allocID         string
+	jobID           string
+	namespace       string
 	taskName        string
 	consulNamespace string
 	consulServices  consul.ConsulServiceAPI
@@ -62,6 +64,8 @@ type serviceHook struct {
 func newServiceHook(c serviceHookConfig) *serviceHook {
 	h := &serviceHook{
 		allocID:         c.alloc.ID,
+		jobID:           c.alloc.JobID,
+		namespace:       c.alloc.Namespace,
 		taskName:        c.task.Name,
 		consulServices:  c.consulServices,
 		consulNamespace: c.consulNamespace,
@@ -192,6 +196,8 @@ func (h *serviceHook) getWorkloadServices() *agentconsul.WorkloadServices {
 	// Create task services struct with request's driver metadata
 	return &agentconsul.WorkloadServices{
 		AllocID:         h.allocID,
+		JobID:           h.jobID,
+		Namespace:       h.namespace,
 		Task:            h.taskName,
 		ConsulNamespace: h.consulNamespace,
 		Restarter:       h.restarter,
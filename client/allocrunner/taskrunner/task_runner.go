@@ -16,6 +16,7 @@ import (
 	"github.com/hashicorp/hcl/v2/hcldec"
 	"github.com/hashicorp/nomad/client/allocdir"
 	"github.com/hashicorp/nomad/client/allocrunner/interfaces"
+	"github.com/hashicorp/nomad/client/allocrunner/taskrunner/getter"
 	"github.com/hashicorp/nomad/client/allocrunner/taskrunner/restarts"
 	"github.com/hashicorp/nomad/client/allocrunner/taskrunner/state"
 	"github.com/hashicorp/nomad/client/config"
@@ -180,6 +181,14 @@ type TaskRunner struct {
 	vaultToken     string
 	vaultTokenLock sync.Mutex
 
+	// nomadToken is the task's current workload identity. It should be
+	// accessed with the getter.
+	nomadToken     string
+	nomadTokenLock sync.Mutex
+
+	// rpcClient is used to renew the task's workload identity
+	rpcClient RPCer
+
 	// baseLabels are used when emitting tagged metrics. All task runner metrics
 	// will have these tags, and optionally more.
 	baseLabels []metrics.Label
@@ -233,6 +242,9 @@ type TaskRunner struct {
 	networkIsolationSpec *drivers.NetworkIsolationSpec
 
 	allocHookResources *cstructs.AllocHookResources
+
+	// artifactCache is the client's artifact cache, or nil if disabled
+	artifactCache *getter.Cache
 }
 
 type Config struct {
@@ -287,6 +299,18 @@ type Config struct {
 
 	// startConditionMetCtx is done when TR should start the task
 	StartConditionMetCtx <-chan struct{}
+
+	// ArtifactCache is the client's artifact cache, or nil if disabled
+	ArtifactCache *getter.Cache
+
+	// RPCClient is used to make RPC calls to the servers on behalf of the
+	// task
+	RPCClient RPCer
+}
+
+// RPCer is the interface needed by hooks to make RPC calls.
+type RPCer interface {
+	RPC(method string, args interface{}, reply interface{}) error
 }
 
 func NewTaskRunner(config *Config) (*TaskRunner, error) {
@@ -342,6 +366,9 @@ func NewTaskRunner(config *Config) (*TaskRunner, error) {
 		maxEvents:              defaultMaxEvents,
 		serversContactedCh:     config.ServersContactedCh,
 		startConditionMetCtx:   config.StartConditionMetCtx,
+		artifactCache:          config.ArtifactCache,
+		nomadToken:             config.Alloc.SignedIdentities[config.Task.Name],
+		rpcClient:              config.RPCClient,
 	}
 
 	// Create the logger based on the allocation ID
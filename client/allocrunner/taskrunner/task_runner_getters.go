@@ -71,6 +71,18 @@ func (tr *TaskRunner) setVaultToken(token string) {
 	tr.envBuilder.SetVaultToken(token, ns, tr.task.Vault.Env)
 }
 
+func (tr *TaskRunner) getNomadToken() string {
+	tr.nomadTokenLock.Lock()
+	defer tr.nomadTokenLock.Unlock()
+	return tr.nomadToken
+}
+
+func (tr *TaskRunner) setNomadToken(token string) {
+	tr.nomadTokenLock.Lock()
+	defer tr.nomadTokenLock.Unlock()
+	tr.nomadToken = token
+}
+
 // getDriverHandle returns a driver handle.
 func (tr *TaskRunner) getDriverHandle() *DriverHandle {
 	tr.handleLock.Lock()
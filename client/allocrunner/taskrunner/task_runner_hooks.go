@@ -64,12 +64,25 @@ func (tr *TaskRunner) initHooks() {
 		newTaskDirHook(tr, hookLogger),
 		newLogMonHook(tr, hookLogger),
 		newDispatchHook(alloc, hookLogger),
+		newIdentityHook(&identityHookConfig{
+			alloc:   alloc,
+			task:    tr.taskName,
+			node:    tr.clientConfig.Node,
+			rpc:     tr.rpcClient,
+			updater: tr,
+			logger:  hookLogger,
+		}),
 		newVolumeHook(tr, hookLogger),
-		newArtifactHook(tr, hookLogger),
+		newArtifactHook(tr, tr.artifactCache, tr.clientConfig.Artifact, hookLogger),
 		newStatsHook(tr, tr.clientConfig.StatsCollectionInterval, hookLogger),
 		newDeviceHook(tr.devicemanager, hookLogger),
 	}
 
+	// If the task group has a max run duration, add the hook.
+	if tg := alloc.Job.LookupTaskGroup(alloc.TaskGroup); tg != nil && tg.MaxRunDuration != nil {
+		tr.runnerHooks = append(tr.runnerHooks, newMaxRunDurationHook(tr, *tg.MaxRunDuration, hookLogger))
+	}
+
 	// If the task has a CSI stanza, add the hook.
 	if task.CSIPluginConfig != nil {
 		tr.runnerHooks = append(tr.runnerHooks, newCSIPluginSupervisorHook(filepath.Join(tr.clientConfig.StateDir, "csi"), tr, tr, hookLogger))
@@ -95,13 +108,15 @@ func (tr *TaskRunner) initHooks() {
 	// If there are templates is enabled, add the hook
 	if len(task.Templates) != 0 {
 		tr.runnerHooks = append(tr.runnerHooks, newTemplateHook(&templateHookConfig{
-			logger:          hookLogger,
-			lifecycle:       tr,
-			events:          tr,
-			templates:       task.Templates,
-			clientConfig:    tr.clientConfig,
-			envBuilder:      tr.envBuilder,
-			consulNamespace: consulNamespace,
+			logger:             hookLogger,
+			lifecycle:          tr,
+			events:             tr,
+			templates:          task.Templates,
+			clientConfig:       tr.clientConfig,
+			envBuilder:         tr.envBuilder,
+			driverCapabilities: tr.driverCapabilities,
+			consulNamespace:    consulNamespace,
+			nomadNamespace:     tr.alloc.Namespace,
 		}))
 	}
 
@@ -226,6 +241,7 @@ func (tr *TaskRunner) prestart() error {
 		}
 
 		req.VaultToken = tr.getVaultToken()
+		req.NomadToken = tr.getNomadToken()
 
 		// Time the prestart hook
 		var start time.Time
@@ -466,6 +482,7 @@ func (tr *TaskRunner) updateHooks() {
 		// Build the request
 		req := interfaces.TaskUpdateRequest{
 			VaultToken: tr.getVaultToken(),
+			NomadToken: tr.getNomadToken(),
 			Alloc:      alloc,
 			TaskEnv:    tr.envBuilder.Build(),
 		}
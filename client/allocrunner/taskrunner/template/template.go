@@ -64,6 +64,11 @@ type TaskTemplateManager struct {
 	// shutdown marks whether the manager has been shutdown
 	shutdown     bool
 	shutdownLock sync.Mutex
+
+	// driverExec is used to execute change scripts within the task. It is
+	// nil until the task has started.
+	driverExec     interfaces.ScriptExecutor
+	driverExecLock sync.Mutex
 }
 
 // TaskTemplateManagerConfig is used to configure an instance of the
@@ -94,6 +99,14 @@ type TaskTemplateManagerConfig struct {
 	// VaultNamespace is the Vault namespace for the task
 	VaultNamespace string
 
+	// NomadNamespace is the Nomad namespace for the task, used to look up
+	// variables with the nomadVar template function
+	NomadNamespace string
+
+	// NomadToken is the workload identity of the task, used to read
+	// variables in place of the client's node secret if set.
+	NomadToken string
+
 	// TaskDir is the task's directory
 	TaskDir string
 
@@ -189,6 +202,14 @@ func (tm *TaskTemplateManager) Stop() {
 	}
 }
 
+// SetDriverExec sets the executor used to run change scripts within the task
+// once it has started.
+func (tm *TaskTemplateManager) SetDriverExec(exec interfaces.ScriptExecutor) {
+	tm.driverExecLock.Lock()
+	defer tm.driverExecLock.Unlock()
+	tm.driverExec = exec
+}
+
 // run is the long lived loop that handles errors and templates being rendered
 func (tm *TaskTemplateManager) run() {
 	// Runner is nil if there is no templates
@@ -389,6 +410,7 @@ func (tm *TaskTemplateManager) onTemplateRendered(handledRenders map[string]time
 
 	var handling []string
 	signals := make(map[string]struct{})
+	var scripts []*structs.ChangeScript
 	restart := false
 	var splay time.Duration
 
@@ -433,6 +455,8 @@ func (tm *TaskTemplateManager) onTemplateRendered(handledRenders map[string]time
 				signals[tmpl.ChangeSignal] = struct{}{}
 			case structs.TemplateChangeModeRestart:
 				restart = true
+			case structs.TemplateChangeModeScript:
+				scripts = append(scripts, tmpl.ChangeScript)
 			case structs.TemplateChangeModeNoop:
 				continue
 			}
@@ -445,7 +469,7 @@ func (tm *TaskTemplateManager) onTemplateRendered(handledRenders map[string]time
 		handling = append(handling, id)
 	}
 
-	if restart || len(signals) != 0 {
+	if restart || len(signals) != 0 || len(scripts) != 0 {
 		if splay != 0 {
 			ns := splay.Nanoseconds()
 			offset := rand.Int63n(ns)
@@ -489,8 +513,49 @@ func (tm *TaskTemplateManager) onTemplateRendered(handledRenders map[string]time
 						SetDisplayMessage(fmt.Sprintf("Template failed to send signals %v: %v", flat, err)))
 			}
 		}
+
+		// Change scripts are unnecessary if the task is being restarted
+		if !restart {
+			for _, script := range scripts {
+				tm.runChangeScript(script)
+			}
+		}
+	}
+
+}
+
+// runChangeScript executes the change script of a re-rendered template within
+// the task. The task is killed if the script fails and is configured to fail
+// the task on error.
+func (tm *TaskTemplateManager) runChangeScript(script *structs.ChangeScript) {
+	tm.driverExecLock.Lock()
+	exec := tm.driverExec
+	tm.driverExecLock.Unlock()
+
+	var err error
+	if exec == nil {
+		err = fmt.Errorf("task is not running")
+	} else if _, code, execErr := exec.Exec(script.Timeout, script.Command, script.Args); execErr != nil {
+		err = execErr
+	} else if code != 0 {
+		err = fmt.Errorf("exited with code %d", code)
+	}
+
+	if err == nil {
+		tm.config.Events.EmitEvent(structs.NewTaskEvent(consulTemplateSourceName).
+			SetDisplayMessage(fmt.Sprintf("Template re-rendered, ran change script %q", script.Command)))
+		return
 	}
 
+	msg := fmt.Sprintf("Template failed to run change script %q: %v", script.Command, err)
+	if script.FailOnError {
+		tm.config.Lifecycle.Kill(context.Background(),
+			structs.NewTaskEvent(structs.TaskKilling).
+				SetFailsTask().
+				SetDisplayMessage(msg))
+		return
+	}
+	tm.config.Events.EmitEvent(structs.NewTaskEvent(structs.TaskHookFailed).SetDisplayMessage(msg))
 }
 
 // allTemplatesNoop returns whether all the managed templates have change mode noop.
@@ -726,6 +791,34 @@ func newRunnerConfig(config *TaskTemplateManagerConfig,
 		}
 	}
 
+	// Setup the Nomad config, which is used to read variables. The task's
+	// workload identity is used if it has one, otherwise the client
+	// authenticates with its node secret, which only allows reading the
+	// variables of jobs running on this node.
+	if cc.Node != nil && cc.Node.HTTPAddr != "" {
+		scheme := "http://"
+		if cc.TLSConfig != nil && cc.TLSConfig.EnableHTTP {
+			scheme = "https://"
+		}
+		nomadAddr := scheme + cc.Node.HTTPAddr
+		conf.Nomad.Address = &nomadAddr
+		conf.Nomad.Namespace = &config.NomadNamespace
+		conf.Nomad.Token = &cc.Node.SecretID
+		if config.NomadToken != "" {
+			conf.Nomad.Token = &config.NomadToken
+		}
+
+		if cc.TLSConfig != nil && cc.TLSConfig.EnableHTTP {
+			conf.Nomad.SSL = &ctconf.SSLConfig{
+				Enabled: helper.BoolToPtr(true),
+				Verify:  helper.BoolToPtr(true),
+				Cert:    &cc.TLSConfig.CertFile,
+				Key:     &cc.TLSConfig.KeyFile,
+				CaCert:  &cc.TLSConfig.CAFile,
+			}
+		}
+	}
+
 	conf.Finalize()
 	return conf, nil
 }
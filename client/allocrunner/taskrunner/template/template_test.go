@@ -116,6 +116,23 @@ func (m *MockTaskHooks) EmitEvent(event *structs.TaskEvent) {
 
 func (m *MockTaskHooks) SetState(state string, event *structs.TaskEvent) {}
 
+// mockExecutor is a mock of the ScriptExecutor interface recording the
+// commands executed
+type mockExecutor struct {
+	ExitCode int
+	Err      error
+	ExecCh   chan []string
+}
+
+func newMockExecutor() *mockExecutor {
+	return &mockExecutor{ExecCh: make(chan []string, 1)}
+}
+
+func (m *mockExecutor) Exec(timeout time.Duration, cmd string, args []string) ([]byte, int, error) {
+	m.ExecCh <- append([]string{cmd}, args...)
+	return nil, m.ExitCode, m.Err
+}
+
 // testHarness is used to test the TaskTemplateManager by spinning up
 // Consul/Vault as needed
 type testHarness struct {
@@ -1183,6 +1200,142 @@ func TestTaskTemplateManager_Signal_Error(t *testing.T) {
 	require.Contains(harness.mockHooks.KillEvent.DisplayMessage, "failed to send signals")
 }
 
+// testScriptTemplate returns a template rendering a file in the task
+// directory, with change mode script, and the path of the file.
+func testScriptTemplate(t *testing.T, harness *testHarness, failOnError bool) string {
+	input := filepath.Join(harness.taskDir, "input.txt")
+	require.NoError(t, ioutil.WriteFile(input, []byte("cat"), 0644))
+
+	harness.templates = []*structs.Template{
+		{
+			EmbeddedTmpl: fmt.Sprintf(`{{ file %q }}`, input),
+			DestPath:     "my.tmpl",
+			ChangeMode:   structs.TemplateChangeModeScript,
+			ChangeScript: &structs.ChangeScript{
+				Command:     "/bin/reload",
+				Args:        []string{"-c", "my.tmpl"},
+				Timeout:     5 * time.Second,
+				FailOnError: failOnError,
+			},
+		},
+	}
+	return input
+}
+
+func TestTaskTemplateManager_Rerender_Script(t *testing.T) {
+	t.Parallel()
+	require := require.New(t)
+
+	harness := newTestHarness(t, nil, false, false)
+	input := testScriptTemplate(t, harness, false)
+	harness.start(t)
+	defer harness.stop()
+
+	exec := newMockExecutor()
+	harness.manager.SetDriverExec(exec)
+
+	select {
+	case <-harness.mockHooks.UnblockCh:
+	case <-time.After(time.Duration(5*testutil.TestMultiplier()) * time.Second):
+		t.Fatalf("Task unblock should have been called")
+	}
+
+	// Wait for the file to be watched before changing its size, as its
+	// modification time may not change
+	time.Sleep(500 * time.Millisecond)
+	require.NoError(ioutil.WriteFile(input, []byte("horse"), 0644))
+
+	select {
+	case cmd := <-exec.ExecCh:
+		require.Equal([]string{"/bin/reload", "-c", "my.tmpl"}, cmd)
+	case <-harness.mockHooks.RestartCh:
+		t.Fatalf("Restart with script change mode: %+v", harness.mockHooks)
+	case <-time.After(time.Duration(10*testutil.TestMultiplier()) * time.Second):
+		t.Fatalf("Should have run the change script: %+v", harness.mockHooks)
+	}
+
+	select {
+	case event := <-harness.mockHooks.EmitEventCh:
+		require.Equal(consulTemplateSourceName, event.Type)
+		require.Contains(event.DisplayMessage, "ran change script")
+	case <-time.After(time.Duration(1*testutil.TestMultiplier()) * time.Second):
+		t.Fatalf("Should have emitted an event")
+	}
+
+	raw, err := ioutil.ReadFile(filepath.Join(harness.taskDir, "my.tmpl"))
+	require.NoError(err)
+	require.Equal("horse", string(raw))
+}
+
+func TestTaskTemplateManager_Script_Error(t *testing.T) {
+	t.Parallel()
+
+	cases := []struct {
+		name        string
+		failOnError bool
+		exitCode    int
+		err         error
+	}{
+		{name: "exit code", exitCode: 1},
+		{name: "exec error", err: fmt.Errorf("test error")},
+		{name: "fail on error", failOnError: true, exitCode: 1},
+	}
+
+	for _, tc := range cases {
+		tc := tc
+		t.Run(tc.name, func(t *testing.T) {
+			t.Parallel()
+			require := require.New(t)
+
+			harness := newTestHarness(t, nil, false, false)
+			input := testScriptTemplate(t, harness, tc.failOnError)
+			harness.start(t)
+			defer harness.stop()
+
+			exec := newMockExecutor()
+			exec.ExitCode = tc.exitCode
+			exec.Err = tc.err
+			harness.manager.SetDriverExec(exec)
+
+			select {
+			case <-harness.mockHooks.UnblockCh:
+			case <-time.After(time.Duration(5*testutil.TestMultiplier()) * time.Second):
+				t.Fatalf("Task unblock should have been called")
+			}
+
+			time.Sleep(500 * time.Millisecond)
+			require.NoError(ioutil.WriteFile(input, []byte("horse"), 0644))
+
+			select {
+			case <-exec.ExecCh:
+			case <-time.After(time.Duration(10*testutil.TestMultiplier()) * time.Second):
+				t.Fatalf("Should have run the change script: %+v", harness.mockHooks)
+			}
+
+			if tc.failOnError {
+				select {
+				case <-harness.mockHooks.KillCh:
+				case <-time.After(time.Duration(1*testutil.TestMultiplier()) * time.Second):
+					t.Fatalf("Should have killed the task: %+v", harness.mockHooks)
+				}
+				require.True(harness.mockHooks.KillEvent.FailsTask)
+				require.Contains(harness.mockHooks.KillEvent.DisplayMessage, "failed to run change script")
+				return
+			}
+
+			select {
+			case event := <-harness.mockHooks.EmitEventCh:
+				require.Equal(structs.TaskHookFailed, event.Type)
+				require.Contains(event.DisplayMessage, "failed to run change script")
+			case <-harness.mockHooks.KillCh:
+				t.Fatalf("Should not have killed the task: %+v", harness.mockHooks)
+			case <-time.After(time.Duration(1*testutil.TestMultiplier()) * time.Second):
+				t.Fatalf("Should have emitted an event")
+			}
+		})
+	}
+}
+
 // TestTaskTemplateManager_FiltersProcessEnvVars asserts that we only render
 // environment variables found in task env-vars and not read the nomad host
 // process environment variables.  nomad host process environment variables
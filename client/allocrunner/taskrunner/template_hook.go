@@ -12,6 +12,7 @@ import (
 	"github.com/hashicorp/nomad/client/config"
 	"github.com/hashicorp/nomad/client/taskenv"
 	"github.com/hashicorp/nomad/nomad/structs"
+	"github.com/hashicorp/nomad/plugins/drivers"
 )
 
 const (
@@ -37,8 +38,14 @@ type templateHookConfig struct {
 	// envBuilder is the environment variable builder for the task.
 	envBuilder *taskenv.Builder
 
+	// driverCapabilities are the capabilities of the task's driver
+	driverCapabilities *drivers.Capabilities
+
 	// consulNamespace is the current Consul namespace
 	consulNamespace string
+
+	// nomadNamespace is the job's Nomad namespace
+	nomadNamespace string
 }
 
 type templateHook struct {
@@ -60,8 +67,14 @@ type templateHook struct {
 	// vaultNamespace is the current Vault namespace
 	vaultNamespace string
 
+	// nomadToken is the task's current workload identity
+	nomadToken string
+
 	// taskDir is the task directory
 	taskDir string
+
+	// driverExec is used to execute change scripts within the task
+	driverExec ti.ScriptExecutor
 }
 
 func newTemplateHook(config *templateHookConfig) *templateHook {
@@ -85,15 +98,28 @@ func (h *templateHook) Prestart(ctx context.Context, req *interfaces.TaskPrestar
 		return nil
 	}
 
-	// Store the current Vault token and the task directory
+	// Store the current Vault token, workload identity and the task
+	// directory
 	h.taskDir = req.TaskDir.Dir
 	h.vaultToken = req.VaultToken
+	h.nomadToken = req.NomadToken
 
 	// Set vault namespace if specified
 	if req.Task.Vault != nil {
 		h.vaultNamespace = req.Task.Vault.Namespace
 	}
 
+	// Change scripts are executed within the task, which the driver must
+	// support. Builtin drivers are checked when the job is submitted but
+	// external driver plugins can only be checked here.
+	if caps := h.config.driverCapabilities; caps != nil && !caps.Exec {
+		for _, tmpl := range h.config.templates {
+			if tmpl.ChangeMode == structs.TemplateChangeModeScript {
+				return fmt.Errorf("template %q has change mode script but the task driver doesn't support exec", tmpl.DestPath)
+			}
+		}
+	}
+
 	unblockCh, err := h.newManager()
 	if err != nil {
 		return err
@@ -119,6 +145,8 @@ func (h *templateHook) newManager() (unblock chan struct{}, err error) {
 		ConsulNamespace:      h.config.consulNamespace,
 		VaultToken:           h.vaultToken,
 		VaultNamespace:       h.vaultNamespace,
+		NomadNamespace:       h.config.nomadNamespace,
+		NomadToken:           h.nomadToken,
 		TaskDir:              h.taskDir,
 		EnvBuilder:           h.config.envBuilder,
 		MaxTemplateEventRate: template.DefaultMaxTemplateEventRate,
@@ -128,10 +156,27 @@ func (h *templateHook) newManager() (unblock chan struct{}, err error) {
 		return nil, err
 	}
 
+	if h.driverExec != nil {
+		m.SetDriverExec(h.driverExec)
+	}
+
 	h.templateManager = m
 	return unblock, nil
 }
 
+// Poststart sets the executor used to run change scripts within the task.
+func (h *templateHook) Poststart(ctx context.Context, req *interfaces.TaskPoststartRequest, resp *interfaces.TaskPoststartResponse) error {
+	h.managerLock.Lock()
+	defer h.managerLock.Unlock()
+
+	h.driverExec = req.DriverExec
+	if h.templateManager != nil {
+		h.templateManager.SetDriverExec(req.DriverExec)
+	}
+
+	return nil
+}
+
 func (h *templateHook) Stop(ctx context.Context, req *interfaces.TaskStopRequest, resp *interfaces.TaskStopResponse) error {
 	h.managerLock.Lock()
 	defer h.managerLock.Unlock()
@@ -144,7 +189,7 @@ func (h *templateHook) Stop(ctx context.Context, req *interfaces.TaskStopRequest
 	return nil
 }
 
-// Handle new Vault token
+// Handle new Vault token or workload identity
 func (h *templateHook) Update(ctx context.Context, req *interfaces.TaskUpdateRequest, resp *interfaces.TaskUpdateResponse) error {
 	h.managerLock.Lock()
 	defer h.managerLock.Unlock()
@@ -154,11 +199,12 @@ func (h *templateHook) Update(ctx context.Context, req *interfaces.TaskUpdateReq
 		return nil
 	}
 
-	// Check if the Vault token has changed
-	if req.VaultToken == h.vaultToken {
+	// Check if the Vault token or workload identity has changed
+	if req.VaultToken == h.vaultToken && req.NomadToken == h.nomadToken {
 		return nil
 	} else {
 		h.vaultToken = req.VaultToken
+		h.nomadToken = req.NomadToken
 	}
 
 	// Shutdown the old template
@@ -0,0 +1,55 @@
+package taskrunner
+
+import (
+	"context"
+	"testing"
+	"time"
+
+	"github.com/hashicorp/nomad/client/allocdir"
+	"github.com/hashicorp/nomad/client/allocrunner/interfaces"
+	"github.com/hashicorp/nomad/helper/testlog"
+	"github.com/hashicorp/nomad/nomad/structs"
+	"github.com/hashicorp/nomad/plugins/drivers"
+	"github.com/stretchr/testify/require"
+)
+
+// Statically assert the template hook implements the expected interfaces
+var _ interfaces.TaskPrestartHook = (*templateHook)(nil)
+var _ interfaces.TaskPoststartHook = (*templateHook)(nil)
+var _ interfaces.TaskUpdateHook = (*templateHook)(nil)
+var _ interfaces.TaskStopHook = (*templateHook)(nil)
+
+// TestTaskRunner_TemplateHook_ScriptUnsupported asserts that templates with
+// change mode script fail the task if its driver doesn't support exec.
+func TestTaskRunner_TemplateHook_ScriptUnsupported(t *testing.T) {
+	t.Parallel()
+
+	task := &structs.Task{
+		Templates: []*structs.Template{
+			{
+				EmbeddedTmpl: "hello",
+				DestPath:     "local/hello.txt",
+				ChangeMode:   structs.TemplateChangeModeScript,
+				ChangeScript: &structs.ChangeScript{
+					Command: "/bin/reload",
+					Timeout: 5 * time.Second,
+				},
+			},
+		},
+	}
+
+	hook := newTemplateHook(&templateHookConfig{
+		logger:             testlog.HCLogger(t),
+		templates:          task.Templates,
+		driverCapabilities: &drivers.Capabilities{Exec: false},
+	})
+
+	req := &interfaces.TaskPrestartRequest{
+		Task:    task,
+		TaskDir: &allocdir.TaskDir{Dir: t.TempDir()},
+	}
+	err := hook.Prestart(context.Background(), req, &interfaces.TaskPrestartResponse{})
+	require.Error(t, err)
+	require.Contains(t, err.Error(), "doesn't support exec")
+	require.False(t, structs.IsRecoverable(err))
+}
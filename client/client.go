@@ -28,6 +28,7 @@ import (
 	"github.com/hashicorp/nomad/client/allocrunner"
 	"github.com/hashicorp/nomad/client/allocrunner/interfaces"
 	arstate "github.com/hashicorp/nomad/client/allocrunner/state"
+	"github.com/hashicorp/nomad/client/allocrunner/taskrunner/getter"
 	"github.com/hashicorp/nomad/client/allocwatcher"
 	"github.com/hashicorp/nomad/client/config"
 	consulApi "github.com/hashicorp/nomad/client/consul"
@@ -39,6 +40,7 @@ import (
 	"github.com/hashicorp/nomad/client/pluginmanager/csimanager"
 	"github.com/hashicorp/nomad/client/pluginmanager/drivermanager"
 	"github.com/hashicorp/nomad/client/servers"
+	"github.com/hashicorp/nomad/client/serviceregistration"
 	"github.com/hashicorp/nomad/client/state"
 	"github.com/hashicorp/nomad/client/stats"
 	cstructs "github.com/hashicorp/nomad/client/structs"
@@ -229,6 +231,11 @@ type Client struct {
 	// and checks.
 	consulService consulApi.ConsulServiceAPI
 
+	// serviceRegWrapper wraps the consulService client and the Nomad native
+	// service registration handler, dispatching workloads to the provider
+	// used by their services.
+	serviceRegWrapper *serviceregistration.HandlerWrapper
+
 	// consulProxies is Nomad's custom Consul client for looking up supported
 	// envoy versions
 	consulProxies consulApi.SupportedProxiesAPI
@@ -263,6 +270,10 @@ type Client struct {
 	// in the node automatically
 	garbageCollector *AllocGarbageCollector
 
+	// artifactCache is the node-local cache of downloaded artifacts shared by
+	// allocations, or nil if disabled
+	artifactCache *getter.Cache
+
 	// clientACLResolver holds the ACL resolution state
 	clientACLResolver
 
@@ -386,6 +397,16 @@ func NewClient(cfg *config.Config, consulCatalog consul.CatalogAPI, consulProxie
 		return nil, fmt.Errorf("failed to initialize client: %v", err)
 	}
 
+	// initialize the artifact cache (needs to happen after init)
+	if size := c.config.ArtifactCacheMaxSizeMB; size > 0 {
+		cacheDir := filepath.Join(c.config.StateDir, "artifacts")
+		cache, err := getter.NewCache(cacheDir, int64(size)*MB, c.logger)
+		if err != nil {
+			return nil, fmt.Errorf("failed to initialize artifact cache: %v", err)
+		}
+		c.artifactCache = cache
+	}
+
 	// initialize the dynamic registry (needs to happen after init)
 	c.dynamicRegistry =
 		dynamicplugins.NewRegistry(c.stateDB, map[string]dynamicplugins.PluginDispenser{
@@ -410,6 +431,10 @@ func NewClient(cfg *config.Config, consulCatalog consul.CatalogAPI, consulProxie
 		return nil, fmt.Errorf("node setup failed: %v", err)
 	}
 
+	// Set up the service registration wrapper now that the node identity is
+	// known; the Nomad provider registers services using the node secret.
+	c.setupServiceRegistrationHandlers()
+
 	// Store the config copy before restoring state but after it has been
 	// initialized.
 	c.configLock.Lock()
@@ -496,7 +521,12 @@ func NewClient(cfg *config.Config, consulCatalog consul.CatalogAPI, consulProxie
 		ParallelDestroys:    cfg.GCParallelDestroys,
 		ReservedDiskMB:      cfg.Node.Reserved.DiskMB,
 	}
-	c.garbageCollector = NewAllocGarbageCollector(c.logger, statsCollector, c, gcConfig)
+	var artifactCache ArtifactCache
+	if c.artifactCache != nil {
+		artifactCache = c.artifactCache
+		gcConfig.ArtifactCacheSharesAllocDir = sameFilesystem(cfg.StateDir, cfg.AllocDir)
+	}
+	c.garbageCollector = NewAllocGarbageCollector(c.logger, statsCollector, c, artifactCache, gcConfig)
 	go c.garbageCollector.Run()
 
 	// Set the preconfigured list of static servers
@@ -1140,7 +1170,7 @@ func (c *Client) restoreState() error {
 			StateDB:             c.stateDB,
 			StateUpdater:        c,
 			DeviceStatsReporter: c,
-			Consul:              c.consulService,
+			Consul:              c.serviceRegWrapper,
 			ConsulSI:            c.tokensClient,
 			ConsulProxies:       c.consulProxies,
 			Vault:               c.vaultClient,
@@ -1153,6 +1183,7 @@ func (c *Client) restoreState() error {
 			DriverManager:       c.drivermanager,
 			ServersContactedCh:  c.serversContactedCh,
 			RPCClient:           c,
+			ArtifactCache:       c.artifactCache,
 		}
 		c.configLock.RUnlock()
 
@@ -1363,6 +1394,21 @@ func (c *Client) nodeID() (id, secret string, err error) {
 }
 
 // setupNode is used to setup the initial node
+// setupServiceRegistrationHandlers initializes the Nomad native service
+// registration handler and wraps it with the Consul service client.
+func (c *Client) setupServiceRegistrationHandlers() {
+	nomadHandler := serviceregistration.NewNomadHandler(&serviceregistration.NomadHandlerConfig{
+		Log:        c.logger,
+		RPCFn:      c.RPC,
+		NodeID:     c.NodeID(),
+		NodeSecret: c.secretNodeID(),
+		Datacenter: c.Datacenter(),
+		Region:     c.Region(),
+	})
+	c.serviceRegWrapper = serviceregistration.NewHandlerWrapper(
+		c.logger.Named("service_registration"), c.consulService, nomadHandler)
+}
+
 func (c *Client) setupNode() error {
 	node := c.config.Node
 	if node == nil {
@@ -2452,7 +2498,7 @@ func (c *Client) addAlloc(alloc *structs.Allocation, migrateToken string) error
 		Logger:              c.logger,
 		ClientConfig:        c.configCopy,
 		StateDB:             c.stateDB,
-		Consul:              c.consulService,
+		Consul:              c.serviceRegWrapper,
 		ConsulProxies:       c.consulProxies,
 		ConsulSI:            c.tokensClient,
 		Vault:               c.vaultClient,
@@ -2466,6 +2512,7 @@ func (c *Client) addAlloc(alloc *structs.Allocation, migrateToken string) error
 		DeviceManager:       c.devicemanager,
 		DriverManager:       c.drivermanager,
 		RPCClient:           c,
+		ArtifactCache:       c.artifactCache,
 	}
 	c.configLock.RUnlock()
 
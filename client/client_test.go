@@ -1125,6 +1125,7 @@ func TestClient_UpdateNodeFromDevicesAccumulates(t *testing.T) {
 			CpuShares:          123,
 			ReservableCpuCores: client.configCopy.Node.NodeResources.Cpu.ReservableCpuCores,
 			TotalCpuCores:      client.configCopy.Node.NodeResources.Cpu.TotalCpuCores,
+			NUMANodes:          client.configCopy.Node.NodeResources.Cpu.NUMANodes,
 		},
 		Memory: structs.NodeMemoryResources{MemoryMB: 1024},
 		Devices: []*structs.NodeDeviceResource{
@@ -1167,6 +1168,7 @@ func TestClient_UpdateNodeFromDevicesAccumulates(t *testing.T) {
 			CpuShares:          123,
 			ReservableCpuCores: client.configCopy.Node.NodeResources.Cpu.ReservableCpuCores,
 			TotalCpuCores:      client.configCopy.Node.NodeResources.Cpu.TotalCpuCores,
+			NUMANodes:          client.configCopy.Node.NodeResources.Cpu.NUMANodes,
 		},
 		Memory: structs.NodeMemoryResources{MemoryMB: 2048},
 		Devices: []*structs.NodeDeviceResource{
@@ -191,6 +191,10 @@ type Config struct {
 	// before garbage collection is triggered.
 	GCMaxAllocs int
 
+	// ArtifactCacheMaxSizeMB is the maximum size of the node-local cache of
+	// downloaded artifacts. Zero disables the cache.
+	ArtifactCacheMaxSizeMB int
+
 	// LogLevel is the level of the logs to putout
 	LogLevel string
 
@@ -213,6 +217,9 @@ type Config struct {
 	// TemplateConfig includes configuration for template rendering
 	TemplateConfig *ClientTemplateConfig
 
+	// Artifact includes configuration for downloading artifacts
+	Artifact *ArtifactConfig
+
 	// RPCHoldTimeout is how long an RPC can be "held" before it is errored.
 	// This is used to paper over a loss of leadership by instead holding RPCs,
 	// so that the caller experiences a slow response rather than an error.
@@ -258,6 +265,14 @@ type Config struct {
 	// HostNetworks is a map of the conigured host networks by name.
 	HostNetworks map[string]*structs.ClientHostNetworkConfig
 
+	// LogSinks are additional destinations the output of every task is
+	// shipped to by logmon.
+	LogSinks []*structs.LogSink
+
+	// LogSinkAllowedAddresses are the addresses the syslog, tcp and http log
+	// sinks of tasks may ship to, in addition to those of LogSinks.
+	LogSinkAllowedAddresses []string
+
 	// BindWildcardDefaultHostNetwork toggles if the default host network should accept all
 	// destinations (true) or only filter on the IP of the default host network (false) when
 	// port mapping. This allows Nomad clients with no defined host networks to accept and
@@ -292,6 +307,47 @@ func (c *ClientTemplateConfig) Copy() *ClientTemplateConfig {
 	return nc
 }
 
+// ArtifactConfig is the configuration specific to downloading artifacts.
+type ArtifactConfig struct {
+	// DisableSandbox downloads artifacts within the client process instead
+	// of a separate sandboxed process.
+	DisableSandbox bool
+
+	// DisableFilesystemIsolation disables restricting the sandboxed process
+	// to writing within the directory it downloads into.
+	DisableFilesystemIsolation bool
+
+	// RequireFilesystemIsolation fails downloads where the sandboxed process
+	// can't be restricted, instead of running it without isolation.
+	RequireFilesystemIsolation bool
+
+	// EnvVars are the names of the environment variables of the client
+	// passed on to the sandboxed process, in addition to the defaults.
+	EnvVars []string
+
+	// User is the user the sandboxed process runs as when the client runs
+	// as root.
+	User string
+
+	// Timeout is the maximum duration of a sandboxed download.
+	Timeout time.Duration
+
+	// MaxSize is the maximum size in bytes of a sandboxed download, or zero
+	// for no limit.
+	MaxSize int64
+}
+
+func (c *ArtifactConfig) Copy() *ArtifactConfig {
+	if c == nil {
+		return nil
+	}
+
+	nc := new(ArtifactConfig)
+	*nc = *c
+	nc.EnvVars = helper.CopySliceString(nc.EnvVars)
+	return nc
+}
+
 func (c *Config) Copy() *Config {
 	nc := new(Config)
 	*nc = *c
@@ -299,9 +355,12 @@ func (c *Config) Copy() *Config {
 	nc.Servers = helper.CopySliceString(nc.Servers)
 	nc.Options = helper.CopyMapStringString(nc.Options)
 	nc.HostVolumes = structs.CopyMapStringClientHostVolumeConfig(nc.HostVolumes)
+	nc.LogSinks = structs.CopySliceLogSinks(nc.LogSinks)
+	nc.LogSinkAllowedAddresses = helper.CopySliceString(nc.LogSinkAllowedAddresses)
 	nc.ConsulConfig = c.ConsulConfig.Copy()
 	nc.VaultConfig = c.VaultConfig.Copy()
 	nc.TemplateConfig = c.TemplateConfig.Copy()
+	nc.Artifact = c.Artifact.Copy()
 	if c.ReservableCores != nil {
 		nc.ReservableCores = make([]uint16, len(c.ReservableCores))
 		copy(nc.ReservableCores, c.ReservableCores)
@@ -331,6 +390,10 @@ func DefaultConfig() *Config {
 			FunctionDenylist: []string{"plugin"},
 			DisableSandbox:   false,
 		},
+		Artifact: &ArtifactConfig{
+			User:    "nobody",
+			Timeout: 30 * time.Minute,
+		},
 		RPCHoldTimeout:     5 * time.Second,
 		CNIPath:            "/opt/cni/bin",
 		CNIConfigDir:       "/opt/cni/config",
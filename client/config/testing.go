@@ -57,5 +57,9 @@ func TestClientConfig(t testing.T) (*Config, func()) {
 	// Loosen GC threshold
 	conf.GCDiskUsageThreshold = 98.0
 	conf.GCInodeUsageThreshold = 98.0
+
+	// Test binaries are generally not executable by unprivileged users, so
+	// download artifacts as the test user
+	conf.Artifact.User = ""
 	return conf, cleanup
 }
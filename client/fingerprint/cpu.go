@@ -2,6 +2,12 @@ package fingerprint
 
 import (
 	"fmt"
+	"io/ioutil"
+	"os"
+	"path/filepath"
+	"sort"
+	"strconv"
+	"strings"
 
 	"github.com/hashicorp/nomad/lib/cpuset"
 
@@ -16,6 +22,10 @@ const (
 	// common on EC2 instances, where the env_aws fingerprinter will follow up,
 	// setting an accurate value.
 	defaultCPUTicks = 1000 // 1 core * 1 GHz
+
+	// sysfsNUMANodePath is the sysfs directory describing the NUMA nodes of
+	// the machine.
+	sysfsNUMANodePath = "/sys/devices/system/node"
 )
 
 // CPUFingerprint is used to fingerprint the CPU
@@ -32,7 +42,7 @@ func NewCPUFingerprint(logger log.Logger) Fingerprint {
 
 func (f *CPUFingerprint) Fingerprint(req *FingerprintRequest, resp *FingerprintResponse) error {
 	cfg := req.Config
-	setResourcesCPU := func(totalCompute int, totalCores uint16, reservableCores []uint16) {
+	setResourcesCPU := func(totalCompute int, totalCores uint16, reservableCores []uint16, numaNodes []structs.NUMANode) {
 		// COMPAT(0.10): Remove in 0.10
 		resp.Resources = &structs.Resources{
 			CPU: totalCompute,
@@ -43,6 +53,7 @@ func (f *CPUFingerprint) Fingerprint(req *FingerprintRequest, resp *FingerprintR
 				CpuShares:          int64(totalCompute),
 				TotalCpuCores:      totalCores,
 				ReservableCpuCores: reservableCores,
+				NUMANodes:          numaNodes,
 			},
 		}
 	}
@@ -81,6 +92,14 @@ func (f *CPUFingerprint) Fingerprint(req *FingerprintRequest, resp *FingerprintR
 		}
 	}
 
+	numaNodes, err := f.deriveNUMANodes()
+	if err != nil {
+		f.logger.Warn("failed to detect NUMA topology", "error", err)
+	} else if len(numaNodes) > 0 {
+		resp.AddAttribute("numa.node_count", strconv.Itoa(len(numaNodes)))
+		f.logger.Debug("detected NUMA nodes", "count", len(numaNodes))
+	}
+
 	tt := int(stats.TotalTicksAvailable())
 	if cfg.CpuCompute > 0 {
 		f.logger.Debug("using user specified cpu compute", "cpu_compute", cfg.CpuCompute)
@@ -97,8 +116,50 @@ func (f *CPUFingerprint) Fingerprint(req *FingerprintRequest, resp *FingerprintR
 	}
 
 	resp.AddAttribute("cpu.totalcompute", fmt.Sprintf("%d", tt))
-	setResourcesCPU(tt, uint16(numCores), reservableCores)
+	setResourcesCPU(tt, uint16(numCores), reservableCores, numaNodes)
 	resp.Detected = true
 
 	return nil
 }
+
+// numaNodesFromSysfs reads the NUMA topology from the given sysfs directory,
+// which contains a nodeN directory with a cpulist file for each NUMA node. A
+// missing directory means the machine doesn't report a NUMA topology.
+func numaNodesFromSysfs(root string) ([]structs.NUMANode, error) {
+	entries, err := ioutil.ReadDir(root)
+	if err != nil {
+		if os.IsNotExist(err) {
+			return nil, nil
+		}
+		return nil, err
+	}
+
+	var nodes []structs.NUMANode
+	for _, entry := range entries {
+		name := entry.Name()
+		if !entry.IsDir() || !strings.HasPrefix(name, "node") {
+			continue
+		}
+		id, err := strconv.ParseUint(strings.TrimPrefix(name, "node"), 10, 16)
+		if err != nil {
+			continue
+		}
+
+		raw, err := ioutil.ReadFile(filepath.Join(root, name, "cpulist"))
+		if err != nil {
+			return nil, err
+		}
+		cores, err := cpuset.Parse(string(raw))
+		if err != nil {
+			return nil, fmt.Errorf("failed to parse cpulist of NUMA node %d: %v", id, err)
+		}
+
+		nodes = append(nodes, structs.NUMANode{
+			ID:    uint16(id),
+			Cores: cores.ToSlice(),
+		})
+	}
+
+	sort.Slice(nodes, func(i, j int) bool { return nodes[i].ID < nodes[j].ID })
+	return nodes, nil
+}
@@ -3,6 +3,12 @@
 
 package fingerprint
 
+import "github.com/hashicorp/nomad/nomad/structs"
+
 func (f *CPUFingerprint) deriveReservableCores(req *FingerprintRequest) ([]uint16, error) {
 	return nil, nil
 }
+
+func (f *CPUFingerprint) deriveNUMANodes() ([]structs.NUMANode, error) {
+	return nil, nil
+}
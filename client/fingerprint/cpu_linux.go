@@ -2,6 +2,7 @@ package fingerprint
 
 import (
 	"github.com/hashicorp/nomad/client/lib/cgutil"
+	"github.com/hashicorp/nomad/nomad/structs"
 )
 
 func (f *CPUFingerprint) deriveReservableCores(req *FingerprintRequest) ([]uint16, error) {
@@ -11,3 +12,7 @@ func (f *CPUFingerprint) deriveReservableCores(req *FingerprintRequest) ([]uint1
 	}
 	return cgutil.GetCPUsFromCgroup(parent)
 }
+
+func (f *CPUFingerprint) deriveNUMANodes() ([]structs.NUMANode, error) {
+	return numaNodesFromSysfs(sysfsNUMANodePath)
+}
@@ -7,6 +7,7 @@ import (
 	"github.com/hashicorp/nomad/client/config"
 	"github.com/hashicorp/nomad/helper/testlog"
 	"github.com/hashicorp/nomad/nomad/structs"
+	"github.com/stretchr/testify/require"
 )
 
 func TestCPUFingerprint(t *testing.T) {
@@ -108,3 +109,17 @@ func TestCPUFingerprint_OverrideCompute(t *testing.T) {
 		}
 	}
 }
+
+func TestCPUFingerprint_NUMANodesFromSysfs(t *testing.T) {
+	nodes, err := numaNodesFromSysfs("./test_fixtures/numa")
+	require.NoError(t, err)
+	require.Equal(t, []structs.NUMANode{
+		{ID: 0, Cores: []uint16{0, 1, 2, 3}},
+		{ID: 1, Cores: []uint16{4, 5, 6, 7}},
+	}, nodes)
+
+	// A missing sysfs directory means there is no NUMA topology.
+	nodes, err = numaNodesFromSysfs("./test_fixtures/does-not-exist")
+	require.NoError(t, err)
+	require.Nil(t, nodes)
+}
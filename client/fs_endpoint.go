@@ -2,9 +2,12 @@ package client
 
 import (
 	"bytes"
+	"compress/gzip"
 	"context"
+	"encoding/binary"
 	"fmt"
 	"io"
+	"io/ioutil"
 	"math"
 	"net/http"
 	"os"
@@ -35,6 +38,10 @@ var (
 	invalidOrigin        = fmt.Errorf("origin must be start or end")
 )
 
+// compressedLogSuffix is the suffix of rotated log files that were compressed
+// by logmon.
+const compressedLogSuffix = ".gz"
+
 const (
 	// streamFramesBuffer is the number of stream frames that will be buffered
 	// before back pressure is applied on the stream framer.
@@ -560,6 +567,7 @@ func (f *FileSystem) logsImpl(ctx context.Context, follow, plain bool, offset in
 		if err != nil {
 			return fmt.Errorf("failed to list entries: %v", err)
 		}
+		entries = uncompressedLogSizes(fs, logPath, entries)
 
 		// If we are not following logs, determine the max index for the logs we are
 		// interested in so we can stop there.
@@ -592,7 +600,11 @@ func (f *FileSystem) logsImpl(ctx context.Context, follow, plain bool, offset in
 		}
 
 		p := filepath.Join(logPath, logEntry.Name)
-		err = f.streamFile(ctx, openOffset, p, 0, fs, framer, eofCancelCh)
+		if strings.HasSuffix(logEntry.Name, compressedLogSuffix) {
+			err = f.streamCompressedFile(ctx, openOffset, p, fs, framer, eofCancelCh)
+		} else {
+			err = f.streamFile(ctx, openOffset, p, 0, fs, framer, eofCancelCh)
+		}
 
 		// Check if the context is cancelled
 		select {
@@ -763,6 +775,94 @@ OUTER:
 	}
 }
 
+// streamCompressedFile streams the decompressed contents of a gzipped log file
+// from the given offset into its decompressed contents. Compressed files are
+// never written to, so once the end of the file is reached it only waits for
+// eofCancelCh to fire.
+func (f *FileSystem) streamCompressedFile(ctx context.Context, offset int64, path string,
+	fs allocdir.AllocDirFS, framer *sframer.StreamFramer, eofCancelCh chan error) error {
+
+	file, err := fs.ReadAt(path, 0)
+	if err != nil {
+		return err
+	}
+	defer file.Close()
+
+	gz, err := gzip.NewReader(file)
+	if err != nil {
+		return err
+	}
+	defer gz.Close()
+
+	// Skip to the offset
+	if _, err := io.CopyN(ioutil.Discard, gz, offset); err != nil && err != io.EOF {
+		return err
+	}
+
+	data := make([]byte, streamFrameSize)
+	for {
+		n, readErr := gz.Read(data)
+		offset += int64(n)
+		if readErr != nil && readErr != io.EOF {
+			return readErr
+		}
+
+		if n != 0 {
+			if err := framer.Send(path, "", data[:n], offset); err != nil {
+				return parseFramerErr(err)
+			}
+		}
+
+		if readErr == io.EOF {
+			break
+		}
+	}
+
+	select {
+	case <-framer.ExitCh():
+		return nil
+	case <-ctx.Done():
+		return nil
+	case err, ok := <-eofCancelCh:
+		if !ok {
+			return nil
+		}
+
+		return err
+	}
+}
+
+// uncompressedLogSizes returns the entries with the size of compressed log
+// files replaced by the size of their decompressed contents, so that offsets
+// are computed the same way for all log files. The size is read from the gzip
+// trailer, which holds it modulo 2^32. Entries whose size can't be read are
+// returned as is.
+func uncompressedLogSizes(fs allocdir.AllocDirFS, logPath string, entries []*cstructs.AllocFileInfo) []*cstructs.AllocFileInfo {
+	out := make([]*cstructs.AllocFileInfo, len(entries))
+	for i, entry := range entries {
+		out[i] = entry
+		if entry.IsDir || !strings.HasSuffix(entry.Name, compressedLogSuffix) || entry.Size < 4 {
+			continue
+		}
+
+		file, err := fs.ReadAt(filepath.Join(logPath, entry.Name), entry.Size-4)
+		if err != nil {
+			continue
+		}
+		var trailer [4]byte
+		_, err = io.ReadFull(file, trailer[:])
+		file.Close()
+		if err != nil {
+			continue
+		}
+
+		e := *entry
+		e.Size = int64(binary.LittleEndian.Uint32(trailer[:]))
+		out[i] = &e
+	}
+	return out
+}
+
 // blockUntilNextLog returns a channel that will have data sent when the next
 // log index or anything greater is created.
 func blockUntilNextLog(ctx context.Context, fs allocdir.AllocDirFS, logPath, task, logType string, nextIndex int64) chan error {
@@ -836,9 +936,11 @@ func (a indexTupleArray) Swap(i, j int)      { a[i], a[j] = a[j], a[i] }
 
 // logIndexes takes a set of entries and returns a indexTupleArray of
 // the desired log file entries. If the indexes could not be determined, an
-// error is returned.
+// error is returned. If a log file exists both compressed and uncompressed,
+// because it is being compressed, the uncompressed entry is returned.
 func logIndexes(entries []*cstructs.AllocFileInfo, task, logType string) (indexTupleArray, error) {
 	var indexes []indexTuple
+	positions := make(map[int64]int)
 	prefix := fmt.Sprintf("%s.%s.", task, logType)
 	for _, entry := range entries {
 		if entry.IsDir {
@@ -850,6 +952,8 @@ func logIndexes(entries []*cstructs.AllocFileInfo, task, logType string) (indexT
 		if idxStr == entry.Name {
 			continue
 		}
+		compressed := strings.HasSuffix(idxStr, compressedLogSuffix)
+		idxStr = strings.TrimSuffix(idxStr, compressedLogSuffix)
 
 		// Convert to an int
 		idx, err := strconv.Atoi(idxStr)
@@ -857,7 +961,15 @@ func logIndexes(entries []*cstructs.AllocFileInfo, task, logType string) (indexT
 			return nil, fmt.Errorf("failed to convert %q to a log index: %v", idxStr, err)
 		}
 
-		indexes = append(indexes, indexTuple{idx: int64(idx), entry: entry})
+		tuple := indexTuple{idx: int64(idx), entry: entry}
+		if i, ok := positions[tuple.idx]; ok {
+			if !compressed {
+				indexes[i] = tuple
+			}
+			continue
+		}
+		positions[tuple.idx] = len(indexes)
+		indexes = append(indexes, tuple)
 	}
 
 	return indexTupleArray(indexes), nil
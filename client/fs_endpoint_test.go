@@ -1,6 +1,8 @@
 package client
 
 import (
+	"bytes"
+	"compress/gzip"
 	"context"
 	"fmt"
 	"io"
@@ -1900,6 +1902,94 @@ func TestFS_logsImpl_NoFollow(t *testing.T) {
 	}
 }
 
+func TestFS_logsImpl_Compressed(t *testing.T) {
+	t.Parallel()
+
+	c, cleanup := TestClient(t, nil)
+	defer cleanup()
+
+	// Get a temp alloc dir and create the log dir
+	ad := tempAllocDir(t)
+	defer os.RemoveAll(ad.AllocDir)
+
+	logDir := filepath.Join(ad.SharedDir, allocdir.LogDirName)
+	require.NoError(t, os.MkdirAll(logDir, 0777))
+
+	// Create compressed rotated log files followed by the current one
+	task := "foo"
+	logType := "stdout"
+	for i, contents := range []string{"01234", "56789"} {
+		var buf bytes.Buffer
+		gz := gzip.NewWriter(&buf)
+		_, err := gz.Write([]byte(contents))
+		require.NoError(t, err)
+		require.NoError(t, gz.Close())
+
+		logFile := fmt.Sprintf("%s.%s.%d.gz", task, logType, i)
+		require.NoError(t, ioutil.WriteFile(filepath.Join(logDir, logFile), buf.Bytes(), 0777))
+	}
+	logFile := fmt.Sprintf("%s.%s.2", task, logType)
+	require.NoError(t, ioutil.WriteFile(filepath.Join(logDir, logFile), []byte("ab"), 0777))
+
+	// Start the reader
+	expected := []byte("89ab")
+	resultCh := make(chan struct{})
+	frames := make(chan *sframer.StreamFrame, 4)
+	var received []byte
+	go func() {
+		for {
+			frame, ok := <-frames
+			if !ok {
+				return
+			}
+
+			if frame.IsHeartbeat() {
+				continue
+			}
+
+			received = append(received, frame.Data...)
+			if reflect.DeepEqual(received, expected) {
+				close(resultCh)
+				return
+			}
+		}
+	}()
+
+	// Start streaming the last bytes of the logs, which begin in a
+	// compressed file
+	ctx, cancel := context.WithTimeout(context.Background(), time.Second)
+	defer cancel()
+
+	require.NoError(t, c.endpoints.FileSystem.logsImpl(
+		ctx, false, false, 4,
+		OriginEnd, task, logType, ad, frames))
+
+	select {
+	case <-resultCh:
+	case <-time.After(10 * time.Duration(testutil.TestMultiplier()) * streamBatchWindow):
+		t.Fatalf("did not receive data: got %q", string(received))
+	}
+}
+
+func TestFS_logIndexes_Compressed(t *testing.T) {
+	entries := []*cstructs.AllocFileInfo{
+		{Name: "foo.stdout.0.gz"},
+		{Name: "foo.stdout.1.gz"},
+		{Name: "foo.stdout.1"},
+		{Name: "foo.stdout.2"},
+		{Name: ".foo.stdout.2.gz.tmp"},
+	}
+
+	indexes, err := logIndexes(entries, "foo", "stdout")
+	require.NoError(t, err)
+
+	var names []string
+	for _, index := range indexes {
+		names = append(names, index.entry.Name)
+	}
+	require.Equal(t, []string{"foo.stdout.0.gz", "foo.stdout.1", "foo.stdout.2"}, names)
+}
+
 func TestFS_logsImpl_Follow(t *testing.T) {
 	t.Parallel()
 
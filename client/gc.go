@@ -26,6 +26,11 @@ type GCConfig struct {
 	Interval            time.Duration
 	ReservedDiskMB      int
 	ParallelDestroys    int
+
+	// ArtifactCacheSharesAllocDir is whether the artifact cache is on the
+	// same filesystem as the allocation directory, so that evicting cached
+	// artifacts relieves its disk pressure.
+	ArtifactCacheSharesAllocDir bool
 }
 
 // AllocCounter is used by AllocGarbageCollector to discover how many un-GC'd
@@ -34,6 +39,14 @@ type AllocCounter interface {
 	NumAllocs() int
 }
 
+// ArtifactCache is used by AllocGarbageCollector to free disk space used by
+// cached artifacts once there are no terminal allocations left to collect.
+type ArtifactCache interface {
+	// EvictOldest evicts the least recently used artifact, returning false
+	// if there was none to evict.
+	EvictOldest() bool
+}
+
 // AllocGarbageCollector garbage collects terminated allocations on a node
 type AllocGarbageCollector struct {
 	config *GCConfig
@@ -47,6 +60,9 @@ type AllocGarbageCollector struct {
 	// allocCounter return the number of un-GC'd allocs on this node
 	allocCounter AllocCounter
 
+	// artifactCache is evicted from under disk pressure, may be nil
+	artifactCache ArtifactCache
+
 	// destroyCh is a semaphore for rate limiting concurrent garbage
 	// collections
 	destroyCh chan struct{}
@@ -62,8 +78,8 @@ type AllocGarbageCollector struct {
 
 // NewAllocGarbageCollector returns a garbage collector for terminated
 // allocations on a node. Must call Run() in a goroutine enable periodic
-// garbage collection.
-func NewAllocGarbageCollector(logger hclog.Logger, statsCollector stats.NodeStatsCollector, ac AllocCounter, config *GCConfig) *AllocGarbageCollector {
+// garbage collection. The artifact cache may be nil.
+func NewAllocGarbageCollector(logger hclog.Logger, statsCollector stats.NodeStatsCollector, ac AllocCounter, cache ArtifactCache, config *GCConfig) *AllocGarbageCollector {
 	logger = logger.Named("gc")
 	// Require at least 1 to make progress
 	if config.ParallelDestroys <= 0 {
@@ -75,6 +91,7 @@ func NewAllocGarbageCollector(logger hclog.Logger, statsCollector stats.NodeStat
 		allocRunners:   NewIndexedGCAllocPQ(),
 		statsCollector: statsCollector,
 		allocCounter:   ac,
+		artifactCache:  cache,
 		config:         config,
 		logger:         logger,
 		destroyCh:      make(chan struct{}, config.ParallelDestroys),
@@ -130,6 +147,7 @@ func (a *AllocGarbageCollector) keepUsageBelowThreshold() error {
 		// See if we are below thresholds for used disk space and inode usage
 		diskStats := a.statsCollector.Stats().AllocDirStats
 		reason := ""
+		diskPressure := false
 		logf := a.logger.Warn
 
 		liveAllocs := a.allocCounter.NumAllocs()
@@ -138,9 +156,11 @@ func (a *AllocGarbageCollector) keepUsageBelowThreshold() error {
 		case diskStats.UsedPercent > a.config.DiskUsageThreshold:
 			reason = fmt.Sprintf("disk usage of %.0f is over gc threshold of %.0f",
 				diskStats.UsedPercent, a.config.DiskUsageThreshold)
+			diskPressure = true
 		case diskStats.InodesUsedPercent > a.config.InodeUsageThreshold:
 			reason = fmt.Sprintf("inode usage of %.0f is over gc threshold of %.0f",
 				diskStats.InodesUsedPercent, a.config.InodeUsageThreshold)
+			diskPressure = true
 		case liveAllocs > a.config.MaxAllocs:
 			// if we're unable to gc, don't WARN until at least 2x over limit
 			if liveAllocs < (a.config.MaxAllocs * 2) {
@@ -157,6 +177,16 @@ func (a *AllocGarbageCollector) keepUsageBelowThreshold() error {
 		// Collect an allocation
 		gcAlloc := a.allocRunners.Pop()
 		if gcAlloc == nil {
+			// Fall back to evicting cached artifacts to free disk space.
+			// Evictions only free space in the allocation directory if the
+			// cache shares its filesystem, so otherwise a single artifact is
+			// evicted per pass rather than emptying the cache.
+			if diskPressure && a.evictArtifact(reason) {
+				if a.config.ArtifactCacheSharesAllocDir {
+					continue
+				}
+				break
+			}
 			logf("garbage collection skipped because no terminal allocations", "reason", reason)
 			break
 		}
@@ -193,6 +223,16 @@ func (a *AllocGarbageCollector) destroyAllocRunner(allocID string, ar AllocRunne
 	<-a.destroyCh
 }
 
+// evictArtifact evicts the least recently used cached artifact. Returns true
+// if an artifact was evicted.
+func (a *AllocGarbageCollector) evictArtifact(reason string) bool {
+	if a.artifactCache == nil || !a.artifactCache.EvictOldest() {
+		return false
+	}
+	a.logger.Info("evicted cached artifact", "reason", reason)
+	return true
+}
+
 func (a *AllocGarbageCollector) Stop() {
 	close(a.shutdownCh)
 }
@@ -312,6 +352,9 @@ func (a *AllocGarbageCollector) MakeRoomFor(allocations []*structs.Allocation) e
 
 		gcAlloc := a.allocRunners.Pop()
 		if gcAlloc == nil {
+			if a.evictArtifact("freeing disk space for new allocations") && a.config.ArtifactCacheSharesAllocDir {
+				continue
+			}
 			break
 		}
 
@@ -2,6 +2,7 @@ package client
 
 import (
 	"fmt"
+	"path/filepath"
 	"testing"
 	"time"
 
@@ -89,6 +90,21 @@ func (m *MockAllocCounter) NumAllocs() int {
 	return m.allocs
 }
 
+// MockArtifactCache implements ArtifactCache interface.
+type MockArtifactCache struct {
+	artifacts int
+	evicted   int
+}
+
+func (m *MockArtifactCache) EvictOldest() bool {
+	if m.artifacts == 0 {
+		return false
+	}
+	m.artifacts--
+	m.evicted++
+	return true
+}
+
 type MockStatsCollector struct {
 	availableValues []uint64
 	usedPercents    []float64
@@ -124,7 +140,7 @@ func (m *MockStatsCollector) Stats() *stats.HostStats {
 func TestAllocGarbageCollector_MarkForCollection(t *testing.T) {
 	t.Parallel()
 	logger := testlog.HCLogger(t)
-	gc := NewAllocGarbageCollector(logger, &MockStatsCollector{}, &MockAllocCounter{}, gcConfig())
+	gc := NewAllocGarbageCollector(logger, &MockStatsCollector{}, &MockAllocCounter{}, nil, gcConfig())
 
 	ar1, cleanup1 := allocrunner.TestAllocRunnerFromAlloc(t, mock.Alloc())
 	defer cleanup1()
@@ -140,7 +156,7 @@ func TestAllocGarbageCollector_MarkForCollection(t *testing.T) {
 func TestAllocGarbageCollector_Collect(t *testing.T) {
 	t.Parallel()
 	logger := testlog.HCLogger(t)
-	gc := NewAllocGarbageCollector(logger, &MockStatsCollector{}, &MockAllocCounter{}, gcConfig())
+	gc := NewAllocGarbageCollector(logger, &MockStatsCollector{}, &MockAllocCounter{}, nil, gcConfig())
 
 	ar1, cleanup1 := allocrunner.TestAllocRunnerFromAlloc(t, mock.Alloc())
 	defer cleanup1()
@@ -166,7 +182,7 @@ func TestAllocGarbageCollector_Collect(t *testing.T) {
 func TestAllocGarbageCollector_CollectAll(t *testing.T) {
 	t.Parallel()
 	logger := testlog.HCLogger(t)
-	gc := NewAllocGarbageCollector(logger, &MockStatsCollector{}, &MockAllocCounter{}, gcConfig())
+	gc := NewAllocGarbageCollector(logger, &MockStatsCollector{}, &MockAllocCounter{}, nil, gcConfig())
 
 	ar1, cleanup1 := allocrunner.TestAllocRunnerFromAlloc(t, mock.Alloc())
 	defer cleanup1()
@@ -189,7 +205,7 @@ func TestAllocGarbageCollector_MakeRoomForAllocations_EnoughSpace(t *testing.T)
 	statsCollector := &MockStatsCollector{}
 	conf := gcConfig()
 	conf.ReservedDiskMB = 20
-	gc := NewAllocGarbageCollector(logger, statsCollector, &MockAllocCounter{}, conf)
+	gc := NewAllocGarbageCollector(logger, statsCollector, &MockAllocCounter{}, nil, conf)
 
 	ar1, cleanup1 := allocrunner.TestAllocRunnerFromAlloc(t, mock.Alloc())
 	defer cleanup1()
@@ -231,7 +247,7 @@ func TestAllocGarbageCollector_MakeRoomForAllocations_GC_Partial(t *testing.T) {
 	statsCollector := &MockStatsCollector{}
 	conf := gcConfig()
 	conf.ReservedDiskMB = 20
-	gc := NewAllocGarbageCollector(logger, statsCollector, &MockAllocCounter{}, conf)
+	gc := NewAllocGarbageCollector(logger, statsCollector, &MockAllocCounter{}, nil, conf)
 
 	ar1, cleanup1 := allocrunner.TestAllocRunnerFromAlloc(t, mock.Alloc())
 	defer cleanup1()
@@ -274,7 +290,7 @@ func TestAllocGarbageCollector_MakeRoomForAllocations_GC_All(t *testing.T) {
 	statsCollector := &MockStatsCollector{}
 	conf := gcConfig()
 	conf.ReservedDiskMB = 20
-	gc := NewAllocGarbageCollector(logger, statsCollector, &MockAllocCounter{}, conf)
+	gc := NewAllocGarbageCollector(logger, statsCollector, &MockAllocCounter{}, nil, conf)
 
 	ar1, cleanup1 := allocrunner.TestAllocRunnerFromAlloc(t, mock.Alloc())
 	defer cleanup1()
@@ -313,7 +329,7 @@ func TestAllocGarbageCollector_MakeRoomForAllocations_GC_Fallback(t *testing.T)
 	statsCollector := &MockStatsCollector{}
 	conf := gcConfig()
 	conf.ReservedDiskMB = 20
-	gc := NewAllocGarbageCollector(logger, statsCollector, &MockAllocCounter{}, conf)
+	gc := NewAllocGarbageCollector(logger, statsCollector, &MockAllocCounter{}, nil, conf)
 
 	ar1, cleanup1 := allocrunner.TestAllocRunnerFromAlloc(t, mock.Alloc())
 	cleanup1()
@@ -499,7 +515,7 @@ func TestAllocGarbageCollector_UsageBelowThreshold(t *testing.T) {
 	statsCollector := &MockStatsCollector{}
 	conf := gcConfig()
 	conf.ReservedDiskMB = 20
-	gc := NewAllocGarbageCollector(logger, statsCollector, &MockAllocCounter{}, conf)
+	gc := NewAllocGarbageCollector(logger, statsCollector, &MockAllocCounter{}, nil, conf)
 
 	ar1, cleanup1 := allocrunner.TestAllocRunnerFromAlloc(t, mock.Alloc())
 	defer cleanup1()
@@ -538,7 +554,7 @@ func TestAllocGarbageCollector_UsedPercentThreshold(t *testing.T) {
 	statsCollector := &MockStatsCollector{}
 	conf := gcConfig()
 	conf.ReservedDiskMB = 20
-	gc := NewAllocGarbageCollector(logger, statsCollector, &MockAllocCounter{}, conf)
+	gc := NewAllocGarbageCollector(logger, statsCollector, &MockAllocCounter{}, nil, conf)
 
 	ar1, cleanup1 := allocrunner.TestAllocRunnerFromAlloc(t, mock.Alloc())
 	defer cleanup1()
@@ -572,3 +588,61 @@ func TestAllocGarbageCollector_UsedPercentThreshold(t *testing.T) {
 		t.Fatalf("gcAlloc: %v", gcAlloc)
 	}
 }
+
+func TestAllocGarbageCollector_EvictArtifacts(t *testing.T) {
+	t.Parallel()
+	logger := testlog.HCLogger(t)
+	statsCollector := &MockStatsCollector{}
+	cache := &MockArtifactCache{artifacts: 5}
+	conf := gcConfig()
+	conf.ArtifactCacheSharesAllocDir = true
+	gc := NewAllocGarbageCollector(logger, statsCollector, &MockAllocCounter{}, cache, conf)
+
+	// Cached artifacts are evicted until the disk usage is below the
+	// threshold as there are no terminal allocations
+	statsCollector.availableValues = []uint64{1000, 1000, 1000}
+	statsCollector.usedPercents = []float64{85, 85, 60}
+	statsCollector.inodePercents = []float64{50, 50, 50}
+
+	require.NoError(t, gc.keepUsageBelowThreshold())
+	require.Equal(t, 2, cache.evicted)
+
+	// Cached artifacts are not evicted for being over the alloc limit
+	statsCollector.index = 0
+	statsCollector.availableValues = []uint64{1000}
+	statsCollector.usedPercents = []float64{20}
+	statsCollector.inodePercents = []float64{10}
+	gc.allocCounter = &MockAllocCounter{allocs: 200}
+
+	require.NoError(t, gc.keepUsageBelowThreshold())
+	require.Equal(t, 2, cache.evicted)
+}
+
+func TestAllocGarbageCollector_EvictArtifacts_OtherFilesystem(t *testing.T) {
+	t.Parallel()
+	logger := testlog.HCLogger(t)
+	statsCollector := &MockStatsCollector{}
+	cache := &MockArtifactCache{artifacts: 5}
+	gc := NewAllocGarbageCollector(logger, statsCollector, &MockAllocCounter{}, cache, gcConfig())
+
+	// Evicting doesn't free space in the allocation directory when the
+	// cache is on another filesystem, so only one artifact is evicted per
+	// pass even though the disk usage stays over the threshold
+	statsCollector.availableValues = []uint64{1000, 1000, 1000}
+	statsCollector.usedPercents = []float64{85, 85, 85}
+	statsCollector.inodePercents = []float64{50, 50, 50}
+
+	require.NoError(t, gc.keepUsageBelowThreshold())
+	require.Equal(t, 1, cache.evicted)
+
+	statsCollector.index = 0
+	require.NoError(t, gc.keepUsageBelowThreshold())
+	require.Equal(t, 2, cache.evicted)
+}
+
+func TestSameFilesystem(t *testing.T) {
+	t.Parallel()
+	dir := t.TempDir()
+	require.True(t, sameFilesystem(dir, filepath.Join(dir, ".")))
+	require.False(t, sameFilesystem(dir, filepath.Join(dir, "missing")))
+}
@@ -0,0 +1,32 @@
+//go:build !windows
+// +build !windows
+
+package client
+
+import (
+	"os"
+	"syscall"
+)
+
+// sameFilesystem returns whether both paths are on the same filesystem. It
+// returns false if either path can't be inspected.
+func sameFilesystem(a, b string) bool {
+	aInfo, err := os.Stat(a)
+	if err != nil {
+		return false
+	}
+	bInfo, err := os.Stat(b)
+	if err != nil {
+		return false
+	}
+
+	aStat, ok := aInfo.Sys().(*syscall.Stat_t)
+	if !ok {
+		return false
+	}
+	bStat, ok := bInfo.Sys().(*syscall.Stat_t)
+	if !ok {
+		return false
+	}
+	return aStat.Dev == bStat.Dev
+}
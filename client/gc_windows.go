@@ -0,0 +1,20 @@
+package client
+
+import (
+	"path/filepath"
+	"strings"
+)
+
+// sameFilesystem returns whether both paths are on the same volume. It
+// returns false if either path can't be made absolute.
+func sameFilesystem(a, b string) bool {
+	aAbs, err := filepath.Abs(a)
+	if err != nil {
+		return false
+	}
+	bAbs, err := filepath.Abs(b)
+	if err != nil {
+		return false
+	}
+	return strings.EqualFold(filepath.VolumeName(aAbs), filepath.VolumeName(bAbs))
+}
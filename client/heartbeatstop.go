@@ -41,8 +41,7 @@ func newHeartbeatStop(
 // allocHook is called after (re)storing a new AllocRunner in the client. It registers the
 // allocation to be stopped if the taskgroup is configured appropriately
 func (h *heartbeatStop) allocHook(alloc *structs.Allocation) {
-	tg := allocTaskGroup(alloc)
-	if tg.StopAfterClientDisconnect != nil {
+	if stopAfterClientDisconnect(alloc) != nil {
 		h.allocHookCh <- alloc
 	}
 }
@@ -50,9 +49,8 @@ func (h *heartbeatStop) allocHook(alloc *structs.Allocation) {
 // shouldStop is called on a restored alloc to determine if lastOk is sufficiently in the
 // past that it should be prevented from restarting
 func (h *heartbeatStop) shouldStop(alloc *structs.Allocation) bool {
-	tg := allocTaskGroup(alloc)
-	if tg.StopAfterClientDisconnect != nil {
-		return h.shouldStopAfter(time.Now(), *tg.StopAfterClientDisconnect)
+	if interval := stopAfterClientDisconnect(alloc); interval != nil {
+		return h.shouldStopAfter(time.Now(), *interval)
 	}
 	return false
 }
@@ -97,9 +95,8 @@ func (h *heartbeatStop) watch() {
 			delete(h.allocInterval, allocID)
 
 		case alloc := <-h.allocHookCh:
-			tg := allocTaskGroup(alloc)
-			if tg.StopAfterClientDisconnect != nil {
-				h.allocInterval[alloc.ID] = *tg.StopAfterClientDisconnect
+			if interval := stopAfterClientDisconnect(alloc); interval != nil {
+				h.allocInterval[alloc.ID] = *interval
 			}
 
 		case <-timeout:
@@ -156,3 +153,15 @@ func allocTaskGroup(alloc *structs.Allocation) *structs.TaskGroup {
 	}
 	return nil
 }
+
+// stopAfterClientDisconnect returns the interval after which the alloc should
+// be stopped when the client can't heartbeat, or nil if it should keep
+// running. Allocs configured with max_client_disconnect keep running so the
+// servers can reconcile them once the client reconnects.
+func stopAfterClientDisconnect(alloc *structs.Allocation) *time.Duration {
+	tg := allocTaskGroup(alloc)
+	if tg == nil || tg.MaxClientDisconnect != nil {
+		return nil
+	}
+	return tg.StopAfterClientDisconnect
+}
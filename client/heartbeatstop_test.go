@@ -63,3 +63,26 @@ func TestHeartbeatStop_allocHook(t *testing.T) {
 
 	require.Empty(t, client.allocs[alloc.ID])
 }
+
+func TestHeartbeatStop_stopAfterClientDisconnect(t *testing.T) {
+	t.Parallel()
+
+	d := 1 * time.Minute
+	alloc := &structs.Allocation{
+		ID:        uuid.Generate(),
+		TaskGroup: "foo",
+		Job: &structs.Job{
+			TaskGroups: []*structs.TaskGroup{
+				{
+					Name:                      "foo",
+					StopAfterClientDisconnect: &d,
+				},
+			},
+		},
+	}
+	require.Equal(t, &d, stopAfterClientDisconnect(alloc))
+
+	// allocations that tolerate disconnected clients are never stopped
+	alloc.Job.TaskGroups[0].MaxClientDisconnect = &d
+	require.Nil(t, stopAfterClientDisconnect(alloc))
+}
@@ -34,6 +34,7 @@ type TaskCgroupInfo struct {
 	CgroupPath         string
 	RelativeCgroupPath string
 	Cpuset             cpuset.CPUSet
+	Mems               cpuset.CPUSet
 	Error              error
 }
 
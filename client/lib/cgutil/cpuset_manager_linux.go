@@ -69,6 +69,7 @@ func (c *cpusetManager) AddAlloc(alloc *structs.Allocation) {
 			CgroupPath:         cgroupPath,
 			RelativeCgroupPath: relativeCgroupPath,
 			Cpuset:             taskCpuset,
+			Mems:               cpuset.New(resources.Cpu.NUMANodes...),
 		}
 	}
 	c.mu.Lock()
@@ -239,15 +240,20 @@ func (c *cpusetManager) reconcileCpusets() {
 			continue
 		}
 
-		// copy cpuset.mems from parent
-		_, parentMems, err := getCpusetSubsystemSettings(filepath.Dir(info.CgroupPath))
-		if err != nil {
-			c.logger.Error("failed to read parent cgroup settings for task", "path", info.CgroupPath, "error", err)
-			info.Error = err
-			continue
+		// pin cpuset.mems to the NUMA nodes of the task's cores, or copy
+		// cpuset.mems from parent if the task has no NUMA placement
+		mems := info.Mems.String()
+		if info.Mems.Size() == 0 {
+			_, parentMems, err := getCpusetSubsystemSettings(filepath.Dir(info.CgroupPath))
+			if err != nil {
+				c.logger.Error("failed to read parent cgroup settings for task", "path", info.CgroupPath, "error", err)
+				info.Error = err
+				continue
+			}
+			mems = parentMems
 		}
-		if err := fscommon.WriteFile(info.CgroupPath, "cpuset.mems", parentMems); err != nil {
-			c.logger.Error("failed to write cgroup cpuset.mems setting for task", "path", info.CgroupPath, "mems", parentMems, "error", err)
+		if err := fscommon.WriteFile(info.CgroupPath, "cpuset.mems", mems); err != nil {
+			c.logger.Error("failed to write cgroup cpuset.mems setting for task", "path", info.CgroupPath, "mems", mems, "error", err)
 			info.Error = err
 			continue
 		}
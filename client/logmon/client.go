@@ -19,13 +19,29 @@ const logmonRPCTimeout = 1 * time.Minute
 
 func (c *logmonClient) Start(cfg *LogConfig) error {
 	req := &proto.StartRequest{
-		LogDir:         cfg.LogDir,
-		StdoutFileName: cfg.StdoutLogFile,
-		StderrFileName: cfg.StderrLogFile,
-		MaxFiles:       uint32(cfg.MaxFiles),
-		MaxFileSizeMb:  uint32(cfg.MaxFileSizeMB),
-		StdoutFifo:     cfg.StdoutFifo,
-		StderrFifo:     cfg.StderrFifo,
+		LogDir:          cfg.LogDir,
+		StdoutFileName:  cfg.StdoutLogFile,
+		StderrFileName:  cfg.StderrLogFile,
+		MaxFiles:        uint32(cfg.MaxFiles),
+		MaxFileSizeMb:   uint32(cfg.MaxFileSizeMB),
+		StdoutFifo:      cfg.StdoutFifo,
+		StderrFifo:      cfg.StderrFifo,
+		AllocId:         cfg.TaskMeta.AllocID,
+		Namespace:       cfg.TaskMeta.Namespace,
+		JobId:           cfg.TaskMeta.JobID,
+		TaskGroup:       cfg.TaskMeta.TaskGroup,
+		TaskName:        cfg.TaskMeta.TaskName,
+		RateLimitLines:  uint32(cfg.RateLimitLines),
+		RateLimitBytes:  uint32(cfg.RateLimitBytes),
+		CompressRotated: cfg.CompressRotated,
+	}
+	for _, sink := range cfg.Sinks {
+		req.Sinks = append(req.Sinks, &proto.LogSink{
+			Type:       sink.Type,
+			Address:    sink.Address,
+			Path:       sink.Path,
+			BufferSize: uint32(sink.BufferSize),
+		})
 	}
 	ctx, cancel := context.WithTimeout(context.Background(), logmonRPCTimeout)
 	defer cancel()
@@ -42,3 +58,18 @@ func (c *logmonClient) Stop() error {
 	_, err := c.client.Stop(ctx, req)
 	return grpcutils.HandleGrpcErr(err, c.doneCtx)
 }
+
+func (c *logmonClient) Stats() (*LogStats, error) {
+	req := &proto.StatsRequest{}
+	ctx, cancel := context.WithTimeout(context.Background(), logmonRPCTimeout)
+	defer cancel()
+
+	resp, err := c.client.Stats(ctx, req)
+	if err != nil {
+		return nil, grpcutils.HandleGrpcErr(err, c.doneCtx)
+	}
+	return &LogStats{
+		DroppedLines: resp.DroppedLines,
+		DroppedBytes: resp.DroppedBytes,
+	}, nil
+}
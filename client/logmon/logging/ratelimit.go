@@ -0,0 +1,146 @@
+package logging
+
+import (
+	"bytes"
+	"io"
+	"sync/atomic"
+	"time"
+)
+
+// tokenBucket is a token bucket refilled at a constant rate, holding at most
+// a second worth of tokens.
+type tokenBucket struct {
+	rate   float64
+	tokens float64
+	last   time.Time
+}
+
+func newTokenBucket(rate int, now time.Time) *tokenBucket {
+	return &tokenBucket{
+		rate:   float64(rate),
+		tokens: float64(rate),
+		last:   now,
+	}
+}
+
+// allow returns whether n tokens are available and takes them if so. Requests
+// for more tokens than the bucket holds are allowed once the bucket is full,
+// leaving the bucket in debt, so they aren't rejected forever.
+func (b *tokenBucket) allow(n int, now time.Time) bool {
+	b.tokens += now.Sub(b.last).Seconds() * b.rate
+	if b.tokens > b.rate {
+		b.tokens = b.rate
+	}
+	b.last = now
+
+	need := float64(n)
+	if need > b.rate {
+		need = b.rate
+	}
+	if b.tokens < need {
+		return false
+	}
+	b.tokens -= float64(n)
+	return true
+}
+
+// RateLimitedWriter limits the rate of the lines and bytes written through it.
+// Lines exceeding the rate are dropped as a whole, and counted. Writes never
+// block or fail because of the limit.
+type RateLimitedWriter struct {
+	w io.WriteCloser
+
+	lines *tokenBucket
+	bytes *tokenBucket
+
+	// dropping is whether the rest of the current line is dropped
+	dropping bool
+
+	// midLine is whether the last write ended within a line
+	midLine bool
+
+	droppedLines uint64
+	droppedBytes uint64
+
+	// now returns the current time and is overridden in tests
+	now func() time.Time
+}
+
+// NewRateLimitedWriter returns a writer allowing linesPerSecond lines and
+// bytesPerSecond bytes per second to be written to w. A limit of zero means
+// unlimited.
+func NewRateLimitedWriter(w io.WriteCloser, linesPerSecond, bytesPerSecond int) *RateLimitedWriter {
+	r := &RateLimitedWriter{
+		w:   w,
+		now: time.Now,
+	}
+
+	now := r.now()
+	if linesPerSecond > 0 {
+		r.lines = newTokenBucket(linesPerSecond, now)
+	}
+	if bytesPerSecond > 0 {
+		r.bytes = newTokenBucket(bytesPerSecond, now)
+	}
+	return r
+}
+
+// Write writes the lines within the rate limit to the underlying writer and
+// drops the others. It always reports the whole input as written, unless the
+// underlying writer fails.
+func (r *RateLimitedWriter) Write(p []byte) (int, error) {
+	now := r.now()
+
+	// Collect the allowed segments so the underlying writer is called once
+	var allowed []byte
+	for rest := p; len(rest) > 0; {
+		segment := rest
+		endsLine := false
+		if i := bytes.IndexByte(rest, '\n'); i >= 0 {
+			segment = rest[:i+1]
+			endsLine = true
+		}
+		rest = rest[len(segment):]
+
+		// Whether a line is dropped is decided at its start, and the bytes
+		// bucket is checked for each segment of it
+		if !r.midLine {
+			r.dropping = r.lines != nil && !r.lines.allow(1, now)
+			if r.dropping {
+				atomic.AddUint64(&r.droppedLines, 1)
+			}
+		}
+		if !r.dropping && r.bytes != nil && !r.bytes.allow(len(segment), now) {
+			r.dropping = true
+			atomic.AddUint64(&r.droppedLines, 1)
+		}
+
+		if r.dropping {
+			atomic.AddUint64(&r.droppedBytes, uint64(len(segment)))
+		} else if len(allowed) == 0 && len(rest) == 0 {
+			// Avoid copying when the whole input is allowed
+			allowed = segment
+		} else {
+			allowed = append(allowed, segment...)
+		}
+		r.midLine = !endsLine
+	}
+
+	if len(allowed) > 0 {
+		if _, err := r.w.Write(allowed); err != nil {
+			return 0, err
+		}
+	}
+	return len(p), nil
+}
+
+// Close closes the underlying writer.
+func (r *RateLimitedWriter) Close() error {
+	return r.w.Close()
+}
+
+// Dropped returns the number of lines and bytes dropped so far. A line is
+// counted once even if it was dropped over several writes.
+func (r *RateLimitedWriter) Dropped() (lines, bytes uint64) {
+	return atomic.LoadUint64(&r.droppedLines), atomic.LoadUint64(&r.droppedBytes)
+}
@@ -0,0 +1,112 @@
+package logging
+
+import (
+	"bytes"
+	"testing"
+	"time"
+
+	"github.com/stretchr/testify/require"
+)
+
+// nopWriteCloser adds a no-op Close to a bytes.Buffer
+type nopWriteCloser struct {
+	bytes.Buffer
+}
+
+func (*nopWriteCloser) Close() error { return nil }
+
+// newTestRateLimitedWriter returns a rate limited writer whose clock is
+// advanced by calling the returned function.
+func newTestRateLimitedWriter(linesPerSecond, bytesPerSecond int) (*RateLimitedWriter, *nopWriteCloser, func(time.Duration)) {
+	buf := &nopWriteCloser{}
+	r := NewRateLimitedWriter(buf, linesPerSecond, bytesPerSecond)
+
+	now := time.Now()
+	r.now = func() time.Time { return now }
+	if r.lines != nil {
+		r.lines.last = now
+	}
+	if r.bytes != nil {
+		r.bytes.last = now
+	}
+	return r, buf, func(d time.Duration) { now = now.Add(d) }
+}
+
+func TestRateLimitedWriter_Lines(t *testing.T) {
+	r, buf, advance := newTestRateLimitedWriter(2, 0)
+
+	n, err := r.Write([]byte("one\ntwo\nthree\n"))
+	require.NoError(t, err)
+	require.Equal(t, 14, n)
+	require.Equal(t, "one\ntwo\n", buf.String())
+
+	lines, bytes := r.Dropped()
+	require.Equal(t, uint64(1), lines)
+	require.Equal(t, uint64(6), bytes)
+
+	// Half a second refills a single line
+	advance(500 * time.Millisecond)
+	_, err = r.Write([]byte("four\nfive\n"))
+	require.NoError(t, err)
+	require.Equal(t, "one\ntwo\nfour\n", buf.String())
+
+	lines, bytes = r.Dropped()
+	require.Equal(t, uint64(2), lines)
+	require.Equal(t, uint64(11), bytes)
+}
+
+func TestRateLimitedWriter_PartialLines(t *testing.T) {
+	r, buf, advance := newTestRateLimitedWriter(1, 0)
+
+	// A line is allowed or dropped as a whole, even over several writes
+	_, err := r.Write([]byte("one"))
+	require.NoError(t, err)
+	_, err = r.Write([]byte(" more\ntw"))
+	require.NoError(t, err)
+	advance(time.Second)
+	_, err = r.Write([]byte("o\nthree\n"))
+	require.NoError(t, err)
+
+	require.Equal(t, "one more\nthree\n", buf.String())
+	lines, bytes := r.Dropped()
+	require.Equal(t, uint64(1), lines)
+	require.Equal(t, uint64(4), bytes)
+}
+
+func TestRateLimitedWriter_Bytes(t *testing.T) {
+	r, buf, advance := newTestRateLimitedWriter(0, 10)
+
+	_, err := r.Write([]byte("12345\n12345\n"))
+	require.NoError(t, err)
+	require.Equal(t, "12345\n", buf.String())
+
+	// Lines longer than the limit are written once the bucket is full
+	advance(time.Second)
+	_, err = r.Write([]byte("1234567890abcdef\n"))
+	require.NoError(t, err)
+	require.Equal(t, "12345\n1234567890abcdef\n", buf.String())
+
+	// and leave it in debt
+	advance(500 * time.Millisecond)
+	_, err = r.Write([]byte("1\n"))
+	require.NoError(t, err)
+	require.Equal(t, "12345\n1234567890abcdef\n", buf.String())
+
+	lines, bytes := r.Dropped()
+	require.Equal(t, uint64(2), lines)
+	require.Equal(t, uint64(8), bytes)
+}
+
+func TestRateLimitedWriter_Unlimited(t *testing.T) {
+	r, buf, _ := newTestRateLimitedWriter(0, 0)
+
+	for i := 0; i < 100; i++ {
+		_, err := r.Write([]byte("line\n"))
+		require.NoError(t, err)
+	}
+	require.Equal(t, 500, buf.Len())
+
+	lines, bytes := r.Dropped()
+	require.Zero(t, lines)
+	require.Zero(t, bytes)
+}
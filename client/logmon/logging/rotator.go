@@ -3,7 +3,9 @@ package logging
 import (
 	"bufio"
 	"bytes"
+	"compress/gzip"
 	"fmt"
+	"io"
 	"io/ioutil"
 	"os"
 	"path/filepath"
@@ -31,6 +33,9 @@ const (
 
 	// newLineDelimiter is the delimiter used for new lines.
 	newLineDelimiter = '\n'
+
+	// compressedSuffix is the suffix of rotated files once compressed.
+	compressedSuffix = ".gz"
 )
 
 // FileRotator writes bytes to a rotated set of files
@@ -38,6 +43,10 @@ type FileRotator struct {
 	MaxFiles int   // MaxFiles is the maximum number of rotated files allowed in a path
 	FileSize int64 // FileSize is the size a rotated file is allowed to grow
 
+	// CompressRotated gzips files once they are rotated. It must be set
+	// before the first write.
+	CompressRotated bool
+
 	path             string // path is the path on the file system where the rotated set of files are opened
 	baseFileName     string // baseFileName is the base file name of the rotated files
 	logFileIdx       int    // logFileIdx is the current index of the rotated files
@@ -51,6 +60,7 @@ type FileRotator struct {
 	flushTicker *time.Ticker
 	logger      hclog.Logger
 	purgeCh     chan struct{}
+	compressCh  chan int
 	doneCh      chan struct{}
 
 	closed     bool
@@ -71,6 +81,7 @@ func NewFileRotator(path string, baseFile string, maxFiles int,
 		flushTicker: time.NewTicker(bufferFlushDuration),
 		logger:      logger,
 		purgeCh:     make(chan struct{}, 1),
+		compressCh:  make(chan int, 1),
 		doneCh:      make(chan struct{}),
 	}
 
@@ -78,6 +89,7 @@ func NewFileRotator(path string, baseFile string, maxFiles int,
 		return nil, err
 	}
 	go rotator.purgeOldFiles()
+	go rotator.compressOldFiles()
 	go rotator.flushPeriodically()
 	return rotator, nil
 }
@@ -169,24 +181,67 @@ func (f *FileRotator) nextFile() error {
 				continue
 			}
 		}
+		if _, err := os.Stat(logFileName + compressedSuffix); err == nil {
+			continue
+		}
 		f.logFileIdx = nextFileIdx
 		if err := f.createFile(); err != nil {
 			return err
 		}
 		break
 	}
-	// Purge old files if we have more files than MaxFiles
+
 	f.closedLock.Lock()
 	defer f.closedLock.Unlock()
-	if f.logFileIdx-f.oldestLogFileIdx >= f.MaxFiles && !f.closed {
+	if f.closed {
+		return nil
+	}
+
+	// Purge old files if we have more files than MaxFiles
+	if f.logFileIdx-f.oldestLogFileIdx >= f.MaxFiles {
 		select {
 		case f.purgeCh <- struct{}{}:
 		default:
 		}
 	}
+
+	// Compress the files before the new one. Replace a pending request, as
+	// the new one covers its files.
+	if f.CompressRotated {
+		select {
+		case f.compressCh <- f.logFileIdx:
+		default:
+			select {
+			case <-f.compressCh:
+			default:
+			}
+			f.compressCh <- f.logFileIdx
+		}
+	}
 	return nil
 }
 
+// rotatedFileIndex returns the index of the rotated file with the given name,
+// and whether the file is compressed. ok is false if the name isn't the name
+// of a rotated file.
+func (f *FileRotator) rotatedFileIndex(name string) (idx int, compressed bool, ok bool) {
+	prefix := fmt.Sprintf("%s.", f.baseFileName)
+	if !strings.HasPrefix(name, prefix) {
+		return 0, false, false
+	}
+
+	fileIdx := strings.TrimPrefix(name, prefix)
+	if strings.HasSuffix(fileIdx, compressedSuffix) {
+		fileIdx = strings.TrimSuffix(fileIdx, compressedSuffix)
+		compressed = true
+	}
+	n, err := strconv.Atoi(fileIdx)
+	if err != nil {
+		return 0, false, false
+	}
+	return n, compressed, true
+}
+
 // lastFile finds out the rotated file with the largest index in a path.
 func (f *FileRotator) lastFile() error {
 	finfos, err := ioutil.ReadDir(f.path)
@@ -194,20 +249,21 @@ func (f *FileRotator) lastFile() error {
 		return err
 	}
 
-	prefix := fmt.Sprintf("%s.", f.baseFileName)
 	for _, fi := range finfos {
 		if fi.IsDir() {
 			continue
 		}
-		if strings.HasPrefix(fi.Name(), prefix) {
-			fileIdx := strings.TrimPrefix(fi.Name(), prefix)
-			n, err := strconv.Atoi(fileIdx)
-			if err != nil {
-				continue
-			}
-			if n > f.logFileIdx {
-				f.logFileIdx = n
-			}
+		n, compressed, ok := f.rotatedFileIndex(fi.Name())
+		if !ok {
+			continue
+		}
+
+		// Compressed files were rotated, so they are never appended to
+		if compressed {
+			n++
+		}
+		if n > f.logFileIdx {
+			f.logFileIdx = n
 		}
 	}
 	if err := f.createFile(); err != nil {
@@ -261,6 +317,7 @@ func (f *FileRotator) Close() error {
 	if !f.closed {
 		close(f.doneCh)
 		close(f.purgeCh)
+		close(f.compressCh)
 		f.closed = true
 		f.currentFile.Close()
 	}
@@ -280,15 +337,20 @@ func (f *FileRotator) purgeOldFiles() {
 				f.logger.Error("error getting directory listing", "err", err)
 				return
 			}
-			// Inserting all the rotated files in a slice
+			// Inserting all the rotated files in a slice. A file being
+			// compressed may exist both compressed and uncompressed.
+			seen := make(map[int]struct{}, len(files))
 			for _, fi := range files {
 				if strings.HasPrefix(fi.Name(), f.baseFileName) {
-					fileIdx := strings.TrimPrefix(fi.Name(), fmt.Sprintf("%s.", f.baseFileName))
-					n, err := strconv.Atoi(fileIdx)
-					if err != nil {
-						f.logger.Error("error extracting file index", "err", err)
+					n, _, ok := f.rotatedFileIndex(fi.Name())
+					if !ok {
+						f.logger.Error("error extracting file index", "filename", fi.Name())
+						continue
+					}
+					if _, ok := seen[n]; ok {
 						continue
 					}
+					seen[n] = struct{}{}
 					fIndexes = append(fIndexes, n)
 				}
 			}
@@ -305,9 +367,11 @@ func (f *FileRotator) purgeOldFiles() {
 			toDelete := fIndexes[0 : len(fIndexes)-f.MaxFiles]
 			for _, fIndex := range toDelete {
 				fname := filepath.Join(f.path, fmt.Sprintf("%s.%d", f.baseFileName, fIndex))
-				err := os.RemoveAll(fname)
-				if err != nil {
-					f.logger.Error("error removing file", "filename", fname, "err", err)
+				for _, name := range []string{fname, fname + compressedSuffix} {
+					err := os.RemoveAll(name)
+					if err != nil {
+						f.logger.Error("error removing file", "filename", name, "err", err)
+					}
 				}
 			}
 			f.oldestLogFileIdx = fIndexes[0]
@@ -317,6 +381,81 @@ func (f *FileRotator) purgeOldFiles() {
 	}
 }
 
+// compressOldFiles gzips the uncompressed rotated files with an index lower
+// than the one received, which are no longer written to.
+func (f *FileRotator) compressOldFiles() {
+	for {
+		select {
+		case currentIdx, ok := <-f.compressCh:
+			if !ok {
+				return
+			}
+
+			files, err := ioutil.ReadDir(f.path)
+			if err != nil {
+				f.logger.Error("error getting directory listing", "err", err)
+				continue
+			}
+			for _, fi := range files {
+				n, compressed, ok := f.rotatedFileIndex(fi.Name())
+				if !ok || compressed || fi.IsDir() || n >= currentIdx {
+					continue
+				}
+
+				// Stop compressing once closed, the remaining files are
+				// compressed after the next rotation
+				select {
+				case <-f.doneCh:
+					return
+				default:
+				}
+
+				if err := compressFile(filepath.Join(f.path, fi.Name())); err != nil {
+					f.logger.Error("error compressing file", "filename", fi.Name(), "err", err)
+				}
+			}
+		case <-f.doneCh:
+			return
+		}
+	}
+}
+
+// compressFile replaces the file at path with a gzipped file of the same name
+// and the ".gz" suffix. The compressed file is written under a hidden name
+// first, so a partially written file is never read as a rotated file.
+func compressFile(path string) error {
+	src, err := os.Open(path)
+	if err != nil {
+		return err
+	}
+	defer src.Close()
+
+	dir, name := filepath.Split(path)
+	tmpPath := filepath.Join(dir, "."+name+compressedSuffix+".tmp")
+	dst, err := os.OpenFile(tmpPath, os.O_WRONLY|os.O_CREATE|os.O_TRUNC, 0644)
+	if err != nil {
+		return err
+	}
+
+	gz := gzip.NewWriter(dst)
+	_, err = io.Copy(gz, src)
+	if err == nil {
+		err = gz.Close()
+	}
+	if cerr := dst.Close(); err == nil {
+		err = cerr
+	}
+	if err == nil {
+		err = os.Rename(tmpPath, path+compressedSuffix)
+	}
+	if err != nil {
+		os.Remove(tmpPath)
+		return err
+	}
+
+	return os.Remove(path)
+}
+
 // flushBuffer flushes the buffer
 func (f *FileRotator) flushBuffer() error {
 	f.bufLock.Lock()
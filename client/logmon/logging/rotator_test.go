@@ -1,11 +1,13 @@
 package logging
 
 import (
+	"compress/gzip"
 	"fmt"
 	"io/ioutil"
 	"math/rand"
 	"os"
 	"path/filepath"
+	"reflect"
 	"testing"
 
 	"github.com/hashicorp/nomad/helper/testlog"
@@ -288,6 +290,114 @@ func TestFileRotator_PurgeOldFiles(t *testing.T) {
 	})
 }
 
+func TestFileRotator_CompressRotated(t *testing.T) {
+	defer goleak.VerifyNone(t)
+
+	path, err := ioutil.TempDir("", pathPrefix)
+	require.NoError(t, err)
+	defer os.RemoveAll(path)
+
+	fr, err := NewFileRotator(path, baseFileName, 10, 5, testlog.HCLogger(t))
+	require.NoError(t, err)
+	defer fr.Close()
+	fr.CompressRotated = true
+
+	str := "abcdefghijkl"
+	nw, err := fr.Write([]byte(str))
+	require.NoError(t, err)
+	require.Equal(t, len(str), nw)
+
+	testutil.WaitForResult(func() (bool, error) {
+		for i, expected := range []string{"abcde", "fghij"} {
+			fname := filepath.Join(path, fmt.Sprintf("redis.stdout.%d", i))
+			if _, err := os.Stat(fname); err == nil {
+				return false, fmt.Errorf("expected file %v to be compressed", fname)
+			}
+
+			f, err := os.Open(fname + ".gz")
+			if err != nil {
+				return false, err
+			}
+			gz, err := gzip.NewReader(f)
+			if err != nil {
+				f.Close()
+				return false, err
+			}
+			contents, err := ioutil.ReadAll(gz)
+			f.Close()
+			if err != nil {
+				return false, err
+			}
+			if string(contents) != expected {
+				return false, fmt.Errorf("expected %q, actual: %q", expected, contents)
+			}
+		}
+		return true, nil
+	}, func(err error) {
+		require.NoError(t, err)
+	})
+
+	// The file being written to is never compressed
+	_, err = os.Stat(filepath.Join(path, "redis.stdout.2"))
+	require.NoError(t, err)
+}
+
+func TestFileRotator_OpenLastFile_Compressed(t *testing.T) {
+	defer goleak.VerifyNone(t)
+
+	path, err := ioutil.TempDir("", pathPrefix)
+	require.NoError(t, err)
+	defer os.RemoveAll(path)
+
+	f, err := os.Create(filepath.Join(path, "redis.stdout.3.gz"))
+	require.NoError(t, err)
+	f.Close()
+
+	fr, err := NewFileRotator(path, baseFileName, 10, 10, testlog.HCLogger(t))
+	require.NoError(t, err)
+	defer fr.Close()
+
+	require.Equal(t, filepath.Join(path, "redis.stdout.4"), fr.currentFile.Name())
+}
+
+func TestFileRotator_PurgeOldFiles_Compressed(t *testing.T) {
+	defer goleak.VerifyNone(t)
+
+	path, err := ioutil.TempDir("", pathPrefix)
+	require.NoError(t, err)
+	defer os.RemoveAll(path)
+
+	fr, err := NewFileRotator(path, baseFileName, 2, 2, testlog.HCLogger(t))
+	require.NoError(t, err)
+	defer fr.Close()
+	fr.CompressRotated = true
+
+	str := "abcdeghijklmn"
+	nw, err := fr.Write([]byte(str))
+	require.NoError(t, err)
+	require.Equal(t, len(str), nw)
+
+	testutil.WaitForResult(func() (bool, error) {
+		f, err := ioutil.ReadDir(path)
+		if err != nil {
+			return false, fmt.Errorf("failed to read dir %v: %w", path, err)
+		}
+
+		var names []string
+		for _, fi := range f {
+			names = append(names, fi.Name())
+		}
+		expected := []string{"redis.stdout.5.gz", "redis.stdout.6"}
+		if !reflect.DeepEqual(names, expected) {
+			return false, fmt.Errorf("expected files: %v, got: %v", expected, names)
+		}
+
+		return true, nil
+	}, func(err error) {
+		require.NoError(t, err)
+	})
+}
+
 func BenchmarkRotator(b *testing.B) {
 	kb := 1024
 	for _, inputSize := range []int{kb, 2 * kb, 4 * kb, 8 * kb, 16 * kb, 32 * kb, 64 * kb, 128 * kb, 256 * kb} {
@@ -0,0 +1,312 @@
+package logging
+
+import (
+	"bytes"
+	"fmt"
+	"sync"
+	"sync/atomic"
+	"time"
+
+	hclog "github.com/hashicorp/go-hclog"
+)
+
+const (
+	// SinkTypeSyslog ships lines as RFC5424 messages to a syslog socket.
+	SinkTypeSyslog = "syslog"
+
+	// SinkTypeFile appends lines to a local file as JSON objects.
+	SinkTypeFile = "file"
+
+	// SinkTypeTCP ships lines as newline delimited JSON objects over TCP.
+	SinkTypeTCP = "tcp"
+
+	// SinkTypeHTTP ships batches of lines as newline delimited JSON objects
+	// in the body of HTTP POST requests.
+	SinkTypeHTTP = "http"
+
+	// defaultSinkBufferSize is the number of lines buffered by a sink when
+	// its configuration doesn't set a size.
+	defaultSinkBufferSize = 1024
+
+	// sinkBatchSize is the maximum number of lines written to a sink at
+	// once.
+	sinkBatchSize = 256
+
+	// sinkCloseTimeout is how long closing a sink waits for the buffered
+	// lines to be written before they are discarded.
+	sinkCloseTimeout = 5 * time.Second
+
+	// sinkDropWarnInterval is the minimum interval between warnings about
+	// lines dropped by a sink.
+	sinkDropWarnInterval = time.Minute
+
+	// maxLineSize is the size after which output without a newline is
+	// shipped as a line of its own.
+	maxLineSize = 64 * 1024
+)
+
+// SinkConfig configures a destination the lines of a task's output are
+// shipped to in addition to the rotated log files.
+type SinkConfig struct {
+	// Type is one of "syslog", "file", "tcp" or "http".
+	Type string
+
+	// Address is the syslog socket URL, the TCP host and port or the HTTP
+	// URL lines are shipped to.
+	Address string
+
+	// Path is the file lines are appended to for file sinks.
+	Path string
+
+	// Root is the directory the path of a file sink must resolve beneath,
+	// even through symlinks. It is set for the file sinks of tasks, which
+	// are confined to the log directory.
+	Root string
+
+	// BufferSize is the number of lines buffered before new lines are
+	// dropped.
+	BufferSize int
+}
+
+// TaskMeta identifies the task a line was written by.
+type TaskMeta struct {
+	AllocID   string `json:"alloc_id"`
+	Namespace string `json:"namespace"`
+	JobID     string `json:"job_id"`
+	TaskGroup string `json:"task_group"`
+	TaskName  string `json:"task"`
+}
+
+// Line is a single line written by a task, along with the metadata shipped
+// with it.
+type Line struct {
+	TaskMeta
+	Time    time.Time `json:"timestamp"`
+	Stream  string    `json:"stream"`
+	Message string    `json:"message"`
+}
+
+// sinkWriter writes batches of lines to a destination. Writes may block;
+// the Sink in front of the writer ensures the task never waits on them.
+type sinkWriter interface {
+	WriteLines(lines []*Line) error
+	Close() error
+}
+
+// Sink buffers the lines sent to it and writes them to its destination in
+// the background. Lines sent while the buffer is full are dropped, so a slow
+// or unavailable destination never blocks the task.
+type Sink struct {
+	config *SinkConfig
+	writer sinkWriter
+	logger hclog.Logger
+
+	lines   chan *Line
+	dropped uint64
+
+	// stopCh is closed to discard the buffered lines when closing the sink
+	// times out, and doneCh is closed once the writer is closed
+	stopCh chan struct{}
+	doneCh chan struct{}
+
+	// closed guards sending to lines once Close was called
+	closed     bool
+	closedLock sync.RWMutex
+}
+
+// NewSink returns a sink shipping lines to the destination of the
+// configuration. Connections are established lazily, so an unavailable
+// destination doesn't cause an error.
+func NewSink(config *SinkConfig, logger hclog.Logger) (*Sink, error) {
+	var writer sinkWriter
+	var err error
+	switch config.Type {
+	case SinkTypeSyslog:
+		writer, err = newSyslogWriter(config.Address)
+	case SinkTypeFile:
+		writer = newFileWriter(config.Root, config.Path)
+	case SinkTypeTCP:
+		writer = newTCPWriter(config.Address)
+	case SinkTypeHTTP:
+		writer = newHTTPWriter(config.Address)
+	default:
+		err = fmt.Errorf("unknown log sink type %q", config.Type)
+	}
+	if err != nil {
+		return nil, err
+	}
+	return newSink(config, writer, logger), nil
+}
+
+func newSink(config *SinkConfig, writer sinkWriter, logger hclog.Logger) *Sink {
+	size := config.BufferSize
+	if size <= 0 {
+		size = defaultSinkBufferSize
+	}
+
+	s := &Sink{
+		config: config,
+		writer: writer,
+		logger: logger.With("sink", config.Type),
+		lines:  make(chan *Line, size),
+		stopCh: make(chan struct{}),
+		doneCh: make(chan struct{}),
+	}
+	go s.run()
+	return s
+}
+
+// Send queues the line to be written. It never blocks: the line is dropped
+// if the buffer is full.
+func (s *Sink) Send(line *Line) {
+	s.closedLock.RLock()
+	defer s.closedLock.RUnlock()
+	if s.closed {
+		return
+	}
+
+	select {
+	case s.lines <- line:
+	default:
+		atomic.AddUint64(&s.dropped, 1)
+	}
+}
+
+// Dropped returns the number of lines dropped because the buffer was full.
+func (s *Sink) Dropped() uint64 {
+	return atomic.LoadUint64(&s.dropped)
+}
+
+// Close stops accepting lines and waits for the buffered lines to be written
+// before closing the destination. If writing them times out, the remaining
+// lines are discarded and the destination is closed once the pending write
+// returns.
+func (s *Sink) Close() {
+	s.closedLock.Lock()
+	if s.closed {
+		s.closedLock.Unlock()
+		return
+	}
+	s.closed = true
+	close(s.lines)
+	s.closedLock.Unlock()
+
+	select {
+	case <-s.doneCh:
+	case <-time.After(sinkCloseTimeout):
+		s.logger.Warn("timed out writing buffered lines to log sink")
+		close(s.stopCh)
+	}
+}
+
+// run writes the buffered lines in batches until the sink is closed, then
+// closes the writer. The writer is only ever used by this goroutine.
+func (s *Sink) run() {
+	defer close(s.doneCh)
+	defer func() {
+		if err := s.writer.Close(); err != nil {
+			s.logger.Warn("failed to close log sink", "error", err)
+		}
+	}()
+
+	var lastDropped uint64
+	var lastWarn time.Time
+	batch := make([]*Line, 0, sinkBatchSize)
+	for line := range s.lines {
+		select {
+		case <-s.stopCh:
+			return
+		default:
+		}
+
+		batch = append(batch[:0], line)
+	DRAIN:
+		for len(batch) < sinkBatchSize {
+			select {
+			case line, ok := <-s.lines:
+				if !ok {
+					break DRAIN
+				}
+				batch = append(batch, line)
+			default:
+				break DRAIN
+			}
+		}
+
+		if err := s.writer.WriteLines(batch); err != nil {
+			s.logger.Warn("failed to write to log sink", "lines", len(batch), "error", err)
+		}
+
+		if dropped := s.Dropped(); dropped != lastDropped && time.Since(lastWarn) > sinkDropWarnInterval {
+			s.logger.Warn("log sink buffer full, dropped lines", "dropped", dropped-lastDropped)
+			lastDropped = dropped
+			lastWarn = time.Now()
+		}
+	}
+}
+
+// LineWriter splits the output of a task stream into lines and sends them
+// to sinks. Writes never block or fail.
+type LineWriter struct {
+	meta   TaskMeta
+	stream string
+	sinks  []*Sink
+	buf    []byte
+}
+
+// NewLineWriter returns a writer sending the lines written to it to the
+// sinks, tagged with the task metadata and the name of the stream.
+func NewLineWriter(meta TaskMeta, stream string, sinks []*Sink) *LineWriter {
+	return &LineWriter{
+		meta:   meta,
+		stream: stream,
+		sinks:  sinks,
+	}
+}
+
+// Write sends each complete line of the output to the sinks. Incomplete
+// lines are held until they are completed by following writes, or until
+// they exceed the maximum line size.
+func (w *LineWriter) Write(p []byte) (int, error) {
+	w.buf = append(w.buf, p...)
+	for {
+		i := bytes.IndexByte(w.buf, '\n')
+		if i < 0 {
+			break
+		}
+		w.send(w.buf[:i])
+		w.buf = w.buf[i+1:]
+	}
+
+	for len(w.buf) >= maxLineSize {
+		w.send(w.buf[:maxLineSize])
+		w.buf = w.buf[maxLineSize:]
+	}
+
+	// Release the consumed part of the buffer
+	if len(w.buf) == 0 {
+		w.buf = nil
+	}
+	return len(p), nil
+}
+
+// Close sends the incomplete line held by the writer, if any.
+func (w *LineWriter) Close() error {
+	if len(w.buf) > 0 {
+		w.send(w.buf)
+		w.buf = nil
+	}
+	return nil
+}
+
+func (w *LineWriter) send(b []byte) {
+	line := &Line{
+		TaskMeta: w.meta,
+		Time:     time.Now(),
+		Stream:   w.stream,
+		Message:  string(bytes.TrimSuffix(b, []byte{'\r'})),
+	}
+	for _, sink := range w.sinks {
+		sink.Send(line)
+	}
+}
@@ -0,0 +1,348 @@
+package logging
+
+import (
+	"bufio"
+	"encoding/json"
+	"fmt"
+	"io/ioutil"
+	"net"
+	"net/http"
+	"net/http/httptest"
+	"os"
+	"path/filepath"
+	"strings"
+	"sync"
+	"sync/atomic"
+	"testing"
+	"time"
+
+	"github.com/hashicorp/nomad/helper/testlog"
+	"github.com/hashicorp/nomad/testutil"
+	"github.com/stretchr/testify/require"
+)
+
+var testTaskMeta = TaskMeta{
+	AllocID:   "a0b1c2d3-0000-0000-0000-000000000000",
+	Namespace: "default",
+	JobID:     "example",
+	TaskGroup: "cache",
+	TaskName:  "redis",
+}
+
+// blockingWriter blocks writes until it is unblocked, recording the lines
+// written to it.
+type blockingWriter struct {
+	unblockCh chan struct{}
+
+	// writing is set once a write started, and closed once the writer was
+	// closed
+	writing int32
+	closed  int32
+
+	lines []*Line
+	l     sync.Mutex
+}
+
+func (w *blockingWriter) WriteLines(lines []*Line) error {
+	if atomic.LoadInt32(&w.closed) == 1 {
+		return fmt.Errorf("write after close")
+	}
+	atomic.StoreInt32(&w.writing, 1)
+	<-w.unblockCh
+	w.l.Lock()
+	defer w.l.Unlock()
+	w.lines = append(w.lines, lines...)
+	return nil
+}
+
+func (w *blockingWriter) Close() error {
+	atomic.StoreInt32(&w.closed, 1)
+	return nil
+}
+
+func (w *blockingWriter) written() int {
+	w.l.Lock()
+	defer w.l.Unlock()
+	return len(w.lines)
+}
+
+func TestLineWriter_SplitsLines(t *testing.T) {
+	w := &blockingWriter{unblockCh: make(chan struct{})}
+	close(w.unblockCh)
+	sink := newSink(&SinkConfig{Type: "test"}, w, testlog.HCLogger(t))
+
+	lw := NewLineWriter(testTaskMeta, "stdout", []*Sink{sink})
+	n, err := lw.Write([]byte("foo\nba"))
+	require.NoError(t, err)
+	require.Equal(t, 6, n)
+	lw.Write([]byte("r\r\nbaz"))
+	lw.Write([]byte(strings.Repeat("x", maxLineSize+1)))
+	require.NoError(t, lw.Close())
+	sink.Close()
+
+	require.Len(t, w.lines, 4)
+	require.Equal(t, "foo", w.lines[0].Message)
+	require.Equal(t, "bar", w.lines[1].Message)
+	require.Equal(t, "baz"+strings.Repeat("x", maxLineSize-3), w.lines[2].Message)
+	require.Equal(t, "xxxx", w.lines[3].Message)
+	for _, line := range w.lines {
+		require.Equal(t, testTaskMeta, line.TaskMeta)
+		require.Equal(t, "stdout", line.Stream)
+	}
+}
+
+func TestSink_DropsWhenFull(t *testing.T) {
+	w := &blockingWriter{unblockCh: make(chan struct{})}
+	sink := newSink(&SinkConfig{Type: "test", BufferSize: 2}, w, testlog.HCLogger(t))
+
+	// The first line is taken by the run loop, which then blocks writing it;
+	// the following two fill the buffer
+	sink.Send(&Line{Message: "1"})
+	testutil.WaitForResult(func() (bool, error) {
+		return atomic.LoadInt32(&w.writing) == 1, fmt.Errorf("line not written")
+	}, func(err error) {
+		require.NoError(t, err)
+	})
+
+	// Sending must never block, even with a blocked destination
+	doneCh := make(chan struct{})
+	go func() {
+		for i := 2; i <= 10; i++ {
+			sink.Send(&Line{Message: fmt.Sprint(i)})
+		}
+		close(doneCh)
+	}()
+	select {
+	case <-doneCh:
+	case <-time.After(5 * time.Second):
+		t.Fatalf("sending lines blocked")
+	}
+	require.EqualValues(t, 7, sink.Dropped())
+
+	close(w.unblockCh)
+	sink.Close()
+	require.Equal(t, 3, w.written())
+
+	// Lines sent after closing are discarded
+	sink.Send(&Line{Message: "11"})
+	require.Equal(t, 3, w.written())
+}
+
+func TestSink_CloseTimeout(t *testing.T) {
+	w := &blockingWriter{unblockCh: make(chan struct{})}
+	sink := newSink(&SinkConfig{Type: "test"}, w, testlog.HCLogger(t))
+
+	sink.Send(&Line{Message: "1"})
+	testutil.WaitForResult(func() (bool, error) {
+		return atomic.LoadInt32(&w.writing) == 1, fmt.Errorf("line not written")
+	}, func(err error) {
+		require.NoError(t, err)
+	})
+	sink.Send(&Line{Message: "2"})
+
+	// Closing gives up on the blocked write, but the writer isn't closed
+	// while it is still in use
+	sink.Close()
+	require.EqualValues(t, 0, atomic.LoadInt32(&w.closed))
+
+	// Once the write returns, the remaining lines are discarded and the
+	// writer is closed
+	close(w.unblockCh)
+	select {
+	case <-sink.doneCh:
+	case <-time.After(5 * time.Second):
+		t.Fatalf("sink not closed")
+	}
+	require.EqualValues(t, 1, atomic.LoadInt32(&w.closed))
+	require.Equal(t, 1, w.written())
+}
+
+func TestSink_File(t *testing.T) {
+	dir, err := ioutil.TempDir("", "logsink")
+	require.NoError(t, err)
+	defer os.RemoveAll(dir)
+
+	path := filepath.Join(dir, "redis.json")
+	sink, err := NewSink(&SinkConfig{Type: SinkTypeFile, Path: path}, testlog.HCLogger(t))
+	require.NoError(t, err)
+
+	lw := NewLineWriter(testTaskMeta, "stderr", []*Sink{sink})
+	lw.Write([]byte("hello\nworld\n"))
+	sink.Close()
+
+	f, err := os.Open(path)
+	require.NoError(t, err)
+	defer f.Close()
+
+	var lines []*Line
+	scanner := bufio.NewScanner(f)
+	for scanner.Scan() {
+		var line Line
+		require.NoError(t, json.Unmarshal(scanner.Bytes(), &line))
+		lines = append(lines, &line)
+	}
+	require.Len(t, lines, 2)
+	require.Equal(t, "hello", lines[0].Message)
+	require.Equal(t, "world", lines[1].Message)
+	require.Equal(t, "stderr", lines[1].Stream)
+	require.Equal(t, testTaskMeta, lines[1].TaskMeta)
+}
+
+func TestSink_TCP(t *testing.T) {
+	ln, err := net.Listen("tcp", "127.0.0.1:0")
+	require.NoError(t, err)
+	defer ln.Close()
+
+	linesCh := make(chan *Line, 10)
+	go func() {
+		conn, err := ln.Accept()
+		if err != nil {
+			return
+		}
+		defer conn.Close()
+		scanner := bufio.NewScanner(conn)
+		for scanner.Scan() {
+			var line Line
+			if err := json.Unmarshal(scanner.Bytes(), &line); err == nil {
+				linesCh <- &line
+			}
+		}
+	}()
+
+	sink, err := NewSink(&SinkConfig{Type: SinkTypeTCP, Address: ln.Addr().String()}, testlog.HCLogger(t))
+	require.NoError(t, err)
+	defer sink.Close()
+
+	lw := NewLineWriter(testTaskMeta, "stdout", []*Sink{sink})
+	lw.Write([]byte("hello\n"))
+
+	select {
+	case line := <-linesCh:
+		require.Equal(t, "hello", line.Message)
+		require.Equal(t, testTaskMeta.AllocID, line.AllocID)
+	case <-time.After(5 * time.Second):
+		t.Fatalf("timed out waiting for line")
+	}
+}
+
+func TestSink_HTTP(t *testing.T) {
+	bodyCh := make(chan string, 10)
+	srv := httptest.NewServer(http.HandlerFunc(func(w http.ResponseWriter, r *http.Request) {
+		body, _ := ioutil.ReadAll(r.Body)
+		if r.Method != "POST" || r.Header.Get("Content-Type") != "application/x-ndjson" {
+			w.WriteHeader(http.StatusBadRequest)
+			return
+		}
+		bodyCh <- string(body)
+	}))
+	defer srv.Close()
+
+	sink, err := NewSink(&SinkConfig{Type: SinkTypeHTTP, Address: srv.URL}, testlog.HCLogger(t))
+	require.NoError(t, err)
+
+	lw := NewLineWriter(testTaskMeta, "stdout", []*Sink{sink})
+	lw.Write([]byte("hello\n"))
+	sink.Close()
+
+	select {
+	case body := <-bodyCh:
+		require.Contains(t, body, `"message":"hello"`)
+		require.Contains(t, body, `"task":"redis"`)
+	case <-time.After(5 * time.Second):
+		t.Fatalf("timed out waiting for request")
+	}
+}
+
+func TestSink_Syslog(t *testing.T) {
+	dir, err := ioutil.TempDir("", "logsink")
+	require.NoError(t, err)
+	defer os.RemoveAll(dir)
+
+	path := filepath.Join(dir, "syslog.sock")
+	conn, err := net.ListenUnixgram("unixgram", &net.UnixAddr{Name: path, Net: "unixgram"})
+	require.NoError(t, err)
+	defer conn.Close()
+
+	sink, err := NewSink(&SinkConfig{Type: SinkTypeSyslog, Address: "unixgram://" + path}, testlog.HCLogger(t))
+	require.NoError(t, err)
+
+	lw := NewLineWriter(testTaskMeta, "stderr", []*Sink{sink})
+	lw.Write([]byte("oops\n"))
+	sink.Close()
+
+	conn.SetReadDeadline(time.Now().Add(5 * time.Second))
+	buf := make([]byte, 1024)
+	n, err := conn.Read(buf)
+	require.NoError(t, err)
+
+	msg := string(buf[:n])
+	require.True(t, strings.HasPrefix(msg, "<11>1 "), msg)
+	require.Contains(t, msg, " redis "+testTaskMeta.AllocID+" stderr - oops")
+}
+
+func TestNewSink_Invalid(t *testing.T) {
+	_, err := NewSink(&SinkConfig{Type: "kafka"}, testlog.HCLogger(t))
+	require.Error(t, err)
+
+	_, err = NewSink(&SinkConfig{Type: SinkTypeSyslog, Address: "udp://127.0.0.1:514"}, testlog.HCLogger(t))
+	require.Error(t, err)
+}
+
+func TestSink_File_Symlinks(t *testing.T) {
+	dir, err := ioutil.TempDir("", "logsink")
+	require.NoError(t, err)
+	defer os.RemoveAll(dir)
+
+	root := filepath.Join(dir, "logs")
+	outside := filepath.Join(dir, "outside")
+	require.NoError(t, os.Mkdir(root, 0755))
+	require.NoError(t, os.Mkdir(outside, 0755))
+
+	target := filepath.Join(outside, "target")
+	require.NoError(t, ioutil.WriteFile(target, nil, 0644))
+	require.NoError(t, os.Symlink(target, filepath.Join(root, "file.json")))
+	require.NoError(t, os.Symlink(outside, filepath.Join(root, "dir")))
+
+	for _, path := range []string{"file.json", "dir/new.json"} {
+		w := newFileWriter(root, filepath.Join(root, path))
+		require.Error(t, w.WriteLines([]*Line{{Message: "hello"}}), path)
+		require.NoError(t, w.Close())
+	}
+
+	b, err := ioutil.ReadFile(target)
+	require.NoError(t, err)
+	require.Empty(t, b)
+	_, err = os.Stat(filepath.Join(outside, "new.json"))
+	require.True(t, os.IsNotExist(err))
+
+	// Files beneath the root are written
+	w := newFileWriter(root, filepath.Join(root, "web.json"))
+	require.NoError(t, w.WriteLines([]*Line{{Message: "hello"}}))
+	require.NoError(t, w.Close())
+}
+
+func TestSink_File_SwappedDirectory(t *testing.T) {
+	dir, err := ioutil.TempDir("", "logsink")
+	require.NoError(t, err)
+	defer os.RemoveAll(dir)
+
+	root := filepath.Join(dir, "logs")
+	outside := filepath.Join(dir, "outside")
+	require.NoError(t, os.MkdirAll(filepath.Join(root, "dir"), 0755))
+	require.NoError(t, os.Mkdir(outside, 0755))
+
+	w := newFileWriter(root, filepath.Join(root, "dir", "web.json"))
+	defer w.Close()
+	require.NoError(t, w.WriteLines([]*Line{{Message: "hello"}}))
+
+	// Swap the directory for a symlink and force the writer to reopen the
+	// file, which must not follow the symlink.
+	require.NoError(t, w.f.Close())
+	w.f = nil
+	require.NoError(t, os.RemoveAll(filepath.Join(root, "dir")))
+	require.NoError(t, os.Symlink(outside, filepath.Join(root, "dir")))
+
+	require.Error(t, w.WriteLines([]*Line{{Message: "world"}}))
+	_, err = os.Stat(filepath.Join(outside, "web.json"))
+	require.True(t, os.IsNotExist(err))
+}
@@ -0,0 +1,299 @@
+package logging
+
+import (
+	"bufio"
+	"bytes"
+	"context"
+	"encoding/json"
+	"fmt"
+	"io"
+	"io/ioutil"
+	"net"
+	"net/http"
+	"net/url"
+	"os"
+	"path/filepath"
+	"strings"
+	"time"
+)
+
+const (
+	// sinkWriteTimeout bounds how long writing a batch of lines to a network
+	// destination may take.
+	sinkWriteTimeout = 10 * time.Second
+
+	// DefaultSyslogAddress is the syslog socket used when a syslog sink
+	// doesn't set an address.
+	DefaultSyslogAddress = "unixgram:///dev/log"
+
+	// syslogFacility is the facility of the messages written to syslog,
+	// which is the "user-level messages" facility.
+	syslogFacility = 1
+
+	// syslogSeverityInfo and syslogSeverityErr are the severities of the
+	// messages written for stdout and stderr lines.
+	syslogSeverityInfo = 6
+	syslogSeverityErr  = 3
+)
+
+// fileWriter appends lines to a file as JSON objects, one per line. The file
+// is opened on the first write, and reopened after errors.
+type fileWriter struct {
+	root string
+	path string
+	f    *os.File
+}
+
+func newFileWriter(root, path string) *fileWriter {
+	return &fileWriter{root: root, path: path}
+}
+
+// open opens the file for appending. The file itself is never a symlink, and
+// when the writer has a root the file is opened relative to the root without
+// following symlinks, so a task can't redirect the writes of the client by
+// swapping directories for symlinks.
+func (w *fileWriter) open() (*os.File, error) {
+	if w.root == "" {
+		return os.OpenFile(w.path, os.O_CREATE|os.O_APPEND|os.O_WRONLY|openNoFollow, 0644)
+	}
+
+	rel, err := filepath.Rel(w.root, w.path)
+	if err != nil {
+		return nil, err
+	}
+	if rel == "." || rel == ".." || strings.HasPrefix(rel, ".."+string(filepath.Separator)) {
+		return nil, fmt.Errorf("%q is not beneath %q", w.path, w.root)
+	}
+	return openBeneath(w.root, rel)
+}
+
+func (w *fileWriter) WriteLines(lines []*Line) error {
+	if w.f == nil {
+		f, err := w.open()
+		if err != nil {
+			return fmt.Errorf("failed to open %q: %v", w.path, err)
+		}
+		w.f = f
+	}
+
+	// Write the batch at once, so lines of concurrent writers appending to
+	// the same file aren't interleaved
+	if _, err := w.f.Write(encodeJSONLines(lines)); err != nil {
+		w.f.Close()
+		w.f = nil
+		return err
+	}
+	return nil
+}
+
+func (w *fileWriter) Close() error {
+	if w.f == nil {
+		return nil
+	}
+	return w.f.Close()
+}
+
+// connWriter writes to a connection that is dialed on the first write, and
+// redialed after errors.
+type connWriter struct {
+	network string
+	address string
+	conn    net.Conn
+}
+
+func (w *connWriter) write(b []byte) error {
+	if w.conn == nil {
+		conn, err := net.DialTimeout(w.network, w.address, sinkWriteTimeout)
+		if err != nil {
+			return err
+		}
+		w.conn = conn
+	}
+
+	w.conn.SetWriteDeadline(time.Now().Add(sinkWriteTimeout))
+	if _, err := w.conn.Write(b); err != nil {
+		w.conn.Close()
+		w.conn = nil
+		return err
+	}
+	return nil
+}
+
+func (w *connWriter) Close() error {
+	if w.conn == nil {
+		return nil
+	}
+	return w.conn.Close()
+}
+
+// tcpWriter ships lines as newline delimited JSON objects over a TCP
+// connection.
+type tcpWriter struct {
+	connWriter
+}
+
+func newTCPWriter(address string) *tcpWriter {
+	return &tcpWriter{connWriter{network: "tcp", address: address}}
+}
+
+func (w *tcpWriter) WriteLines(lines []*Line) error {
+	return w.write(encodeJSONLines(lines))
+}
+
+// syslogWriter ships lines as RFC5424 messages to a syslog socket. Messages
+// are framed with octet counting (RFC6587) on TCP, terminated by a newline
+// on unix stream sockets and sent as one datagram each otherwise.
+type syslogWriter struct {
+	connWriter
+	hostname string
+}
+
+func newSyslogWriter(address string) (*syslogWriter, error) {
+	if address == "" {
+		address = DefaultSyslogAddress
+	}
+
+	u, err := url.Parse(address)
+	if err != nil {
+		return nil, fmt.Errorf("invalid syslog address %q: %v", address, err)
+	}
+
+	w := &syslogWriter{
+		connWriter: connWriter{network: u.Scheme},
+		hostname:   "-",
+	}
+	switch u.Scheme {
+	case "unix", "unixgram":
+		w.address = u.Path
+	case "tcp":
+		w.address = u.Host
+	default:
+		return nil, fmt.Errorf("syslog address %q must use the unix, unixgram or tcp scheme", address)
+	}
+
+	if hostname, err := os.Hostname(); err == nil && hostname != "" {
+		w.hostname = hostname
+	}
+	return w, nil
+}
+
+func (w *syslogWriter) WriteLines(lines []*Line) error {
+	if w.network == "unixgram" {
+		for _, line := range lines {
+			if err := w.write(w.format(line)); err != nil {
+				return err
+			}
+		}
+		return nil
+	}
+
+	var buf bytes.Buffer
+	for _, line := range lines {
+		msg := w.format(line)
+		if w.network == "tcp" {
+			fmt.Fprintf(&buf, "%d ", len(msg))
+			buf.Write(msg)
+		} else {
+			buf.Write(msg)
+			buf.WriteByte('\n')
+		}
+	}
+	return w.write(buf.Bytes())
+}
+
+// format returns the line as an RFC5424 message. The task name is used as
+// the APP-NAME, the allocation ID as the PROCID and the stream as the MSGID.
+func (w *syslogWriter) format(line *Line) []byte {
+	severity := syslogSeverityInfo
+	if line.Stream == "stderr" {
+		severity = syslogSeverityErr
+	}
+
+	return []byte(fmt.Sprintf("<%d>1 %s %s %s %s %s - %s",
+		syslogFacility*8+severity,
+		line.Time.UTC().Format("2006-01-02T15:04:05.000000Z07:00"),
+		syslogHeaderField(w.hostname, 255),
+		syslogHeaderField(line.TaskName, 48),
+		syslogHeaderField(line.AllocID, 128),
+		syslogHeaderField(line.Stream, 32),
+		line.Message))
+}
+
+// syslogHeaderField returns the value as an RFC5424 header field, which is
+// limited to printable ASCII characters and may not be empty.
+func syslogHeaderField(value string, maxLen int) string {
+	field := strings.Map(func(r rune) rune {
+		if r < 33 || r > 126 {
+			return '_'
+		}
+		return r
+	}, value)
+
+	if field == "" {
+		return "-"
+	}
+	if len(field) > maxLen {
+		field = field[:maxLen]
+	}
+	return field
+}
+
+// httpWriter ships batches of lines as newline delimited JSON objects in the
+// body of HTTP POST requests.
+type httpWriter struct {
+	url    string
+	client *http.Client
+
+	ctx    context.Context
+	cancel context.CancelFunc
+}
+
+func newHTTPWriter(url string) *httpWriter {
+	ctx, cancel := context.WithCancel(context.Background())
+	return &httpWriter{
+		url:    url,
+		client: &http.Client{Timeout: sinkWriteTimeout},
+		ctx:    ctx,
+		cancel: cancel,
+	}
+}
+
+func (w *httpWriter) WriteLines(lines []*Line) error {
+	req, err := http.NewRequest("POST", w.url, bytes.NewReader(encodeJSONLines(lines)))
+	if err != nil {
+		return err
+	}
+	req = req.WithContext(w.ctx)
+	req.Header.Set("Content-Type", "application/x-ndjson")
+
+	resp, err := w.client.Do(req)
+	if err != nil {
+		return err
+	}
+	defer resp.Body.Close()
+
+	// Drain the body so the connection can be reused
+	io.Copy(ioutil.Discard, bufio.NewReader(io.LimitReader(resp.Body, 64*1024)))
+
+	if resp.StatusCode < 200 || resp.StatusCode > 299 {
+		return fmt.Errorf("unexpected response code %d", resp.StatusCode)
+	}
+	return nil
+}
+
+func (w *httpWriter) Close() error {
+	w.cancel()
+	w.client.CloseIdleConnections()
+	return nil
+}
+
+// encodeJSONLines returns the lines as newline delimited JSON objects.
+func encodeJSONLines(lines []*Line) []byte {
+	var buf bytes.Buffer
+	enc := json.NewEncoder(&buf)
+	for _, line := range lines {
+		// Lines only hold strings and times, so encoding can't fail
+		enc.Encode(line)
+	}
+	return buf.Bytes()
+}
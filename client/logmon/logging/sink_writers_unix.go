@@ -0,0 +1,44 @@
+//go:build !windows
+// +build !windows
+
+package logging
+
+import (
+	"os"
+	"path/filepath"
+	"strings"
+
+	"golang.org/x/sys/unix"
+)
+
+// openNoFollow makes opening a file sink fail if the file is a symlink.
+const openNoFollow = unix.O_NOFOLLOW
+
+// openBeneath opens the file at the relative path beneath root for appending.
+// Each directory is opened relative to its parent without following
+// symlinks, so the file can't be redirected outside of root by swapping a
+// directory for a symlink while it is opened.
+func openBeneath(root, rel string) (*os.File, error) {
+	dirfd, err := unix.Open(root, unix.O_RDONLY|unix.O_DIRECTORY|unix.O_CLOEXEC, 0)
+	if err != nil {
+		return nil, &os.PathError{Op: "open", Path: root, Err: err}
+	}
+
+	parts := strings.Split(filepath.Clean(rel), string(filepath.Separator))
+	for _, part := range parts[:len(parts)-1] {
+		fd, err := unix.Openat(dirfd, part, unix.O_RDONLY|unix.O_DIRECTORY|unix.O_NOFOLLOW|unix.O_CLOEXEC, 0)
+		unix.Close(dirfd)
+		if err != nil {
+			return nil, &os.PathError{Op: "open", Path: filepath.Join(root, rel), Err: err}
+		}
+		dirfd = fd
+	}
+
+	fd, err := unix.Openat(dirfd, parts[len(parts)-1],
+		unix.O_CREAT|unix.O_APPEND|unix.O_WRONLY|unix.O_NOFOLLOW|unix.O_CLOEXEC, 0644)
+	unix.Close(dirfd)
+	if err != nil {
+		return nil, &os.PathError{Op: "open", Path: filepath.Join(root, rel), Err: err}
+	}
+	return os.NewFile(uintptr(fd), filepath.Join(root, rel)), nil
+}
@@ -0,0 +1,31 @@
+package logging
+
+import (
+	"fmt"
+	"os"
+	"path/filepath"
+
+	"github.com/hashicorp/nomad/helper"
+)
+
+// openNoFollow is not supported on Windows, where creating symlinks requires
+// elevated privileges.
+const openNoFollow = 0
+
+// openBeneath opens the file at the relative path beneath root for appending,
+// refusing to open it if its directory resolves outside of root.
+func openBeneath(root, rel string) (*os.File, error) {
+	resolvedRoot, err := filepath.EvalSymlinks(root)
+	if err != nil {
+		return nil, err
+	}
+	path := filepath.Join(root, rel)
+	dir, err := filepath.EvalSymlinks(filepath.Dir(path))
+	if err != nil {
+		return nil, err
+	}
+	if helper.PathEscapesSandbox(resolvedRoot, dir) {
+		return nil, fmt.Errorf("directory of %q escapes %q", path, root)
+	}
+	return os.OpenFile(filepath.Join(dir, filepath.Base(path)), os.O_CREATE|os.O_APPEND|os.O_WRONLY, 0644)
+}
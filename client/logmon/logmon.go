@@ -4,6 +4,7 @@ import (
 	"fmt"
 	"io"
 	"os"
+	"path/filepath"
 	"strings"
 	"sync"
 	"time"
@@ -41,11 +42,36 @@ type LogConfig struct {
 
 	// MaxFileSizeMB is the max log file size in MB allowed before rotation occures
 	MaxFileSizeMB int
+
+	// Sinks are the destinations the lines of stdout and stderr are shipped
+	// to in addition to the log files. Relative file sink paths are relative
+	// to LogDir and can't escape it
+	Sinks []*logging.SinkConfig
+
+	// TaskMeta identifies the task in the lines shipped to sinks
+	TaskMeta logging.TaskMeta
+
+	// RateLimitLines and RateLimitBytes are the number of lines and bytes
+	// per second allowed for each of stdout and stderr. Zero is unlimited.
+	RateLimitLines int
+	RateLimitBytes int
+
+	// CompressRotated gzips log files once rotated
+	CompressRotated bool
+}
+
+// LogStats are the statistics of a task's logging
+type LogStats struct {
+	// DroppedLines and DroppedBytes are the output dropped by the rate limit
+	// since the task logger was started
+	DroppedLines uint64
+	DroppedBytes uint64
 }
 
 type LogMon interface {
 	Start(*LogConfig) error
 	Stop() error
+	Stats() (*LogStats, error)
 }
 
 func NewLogMon(logger hclog.Logger) LogMon {
@@ -98,6 +124,15 @@ func (l *logmonImpl) Stop() error {
 	return nil
 }
 
+func (l *logmonImpl) Stats() (*LogStats, error) {
+	l.lock.Lock()
+	defer l.lock.Unlock()
+	if l.tl == nil {
+		return &LogStats{}, nil
+	}
+	return l.tl.Stats(), nil
+}
+
 type TaskLogger struct {
 	config *LogConfig
 
@@ -106,6 +141,12 @@ type TaskLogger struct {
 
 	// rotator for stderr
 	lre *logRotatorWrapper
+
+	// sinks the lines of stdout and stderr are shipped to
+	sinks []*logging.Sink
+
+	// limiters rate limit stdout and stderr, if a rate limit is set
+	limiters []*logging.RateLimitedWriter
 }
 
 // IsRunning will return true as long as one rotator wrapper is still running
@@ -133,20 +174,56 @@ func (tl *TaskLogger) Close() {
 		}()
 	}
 	wg.Wait()
+
+	// Close the sinks once the rotators are closed, so no more lines are
+	// sent to them
+	tl.closeSinks()
+}
+
+// Stats returns the output dropped by the rate limits of stdout and stderr.
+func (tl *TaskLogger) Stats() *LogStats {
+	stats := &LogStats{}
+	for _, limiter := range tl.limiters {
+		lines, bytes := limiter.Dropped()
+		stats.DroppedLines += lines
+		stats.DroppedBytes += bytes
+	}
+	return stats
 }
 
 func NewTaskLogger(cfg *LogConfig, logger hclog.Logger) (*TaskLogger, error) {
 	tl := &TaskLogger{config: cfg}
 
+	for _, sinkConfig := range cfg.Sinks {
+		// Relative file sink paths are set by tasks, and are confined to
+		// the log directory
+		if sinkConfig.Type == logging.SinkTypeFile && !filepath.IsAbs(sinkConfig.Path) {
+			c := *sinkConfig
+			c.Root = cfg.LogDir
+			c.Path = filepath.Join(cfg.LogDir, sinkConfig.Path)
+			sinkConfig = &c
+		}
+
+		sink, err := logging.NewSink(sinkConfig, logger)
+		if err != nil {
+			tl.closeSinks()
+			return nil, fmt.Errorf("failed to create %s log sink: %v", sinkConfig.Type, err)
+		}
+		tl.sinks = append(tl.sinks, sink)
+	}
+
 	logFileSize := int64(cfg.MaxFileSizeMB * 1024 * 1024)
 	lro, err := logging.NewFileRotator(cfg.LogDir, cfg.StdoutLogFile,
 		cfg.MaxFiles, logFileSize, logger)
 	if err != nil {
+		tl.closeSinks()
 		return nil, fmt.Errorf("failed to create stdout logfile for %q: %v", cfg.StdoutLogFile, err)
 	}
+	lro.CompressRotated = cfg.CompressRotated
 
-	wrapperOut, err := newLogRotatorWrapper(cfg.StdoutFifo, logger, lro)
+	wrapperOut, err := newLogRotatorWrapper(cfg.StdoutFifo, logger, tl.withRateLimit(tl.withSinks(lro, "stdout")))
 	if err != nil {
+		tl.closeSinks()
 		return nil, err
 	}
 
@@ -155,11 +232,14 @@ func NewTaskLogger(cfg *LogConfig, logger hclog.Logger) (*TaskLogger, error) {
 	lre, err := logging.NewFileRotator(cfg.LogDir, cfg.StderrLogFile,
 		cfg.MaxFiles, logFileSize, logger)
 	if err != nil {
+		tl.closeSinks()
 		return nil, fmt.Errorf("failed to create stderr logfile for %q: %v", cfg.StderrLogFile, err)
 	}
+	lre.CompressRotated = cfg.CompressRotated
 
-	wrapperErr, err := newLogRotatorWrapper(cfg.StderrFifo, logger, lre)
+	wrapperErr, err := newLogRotatorWrapper(cfg.StderrFifo, logger, tl.withRateLimit(tl.withSinks(lre, "stderr")))
 	if err != nil {
+		tl.closeSinks()
 		return nil, err
 	}
 
@@ -169,6 +249,53 @@ func NewTaskLogger(cfg *LogConfig, logger hclog.Logger) (*TaskLogger, error) {
 
 }
 
+// withSinks returns a writer writing to the rotator and shipping the lines of
+// the stream to the sinks of the task logger, if any.
+func (tl *TaskLogger) withSinks(rotator io.WriteCloser, stream string) io.WriteCloser {
+	if len(tl.sinks) == 0 {
+		return rotator
+	}
+	return &teeWriteCloser{
+		primary:   rotator,
+		secondary: logging.NewLineWriter(tl.config.TaskMeta, stream, tl.sinks),
+	}
+}
+
+// withRateLimit returns a writer dropping the output exceeding the rate limit
+// of the task logger, if any, before it is written to w.
+func (tl *TaskLogger) withRateLimit(w io.WriteCloser) io.WriteCloser {
+	if tl.config.RateLimitLines <= 0 && tl.config.RateLimitBytes <= 0 {
+		return w
+	}
+	limiter := logging.NewRateLimitedWriter(w, tl.config.RateLimitLines, tl.config.RateLimitBytes)
+	tl.limiters = append(tl.limiters, limiter)
+	return limiter
+}
+
+func (tl *TaskLogger) closeSinks() {
+	for _, sink := range tl.sinks {
+		sink.Close()
+	}
+}
+
+// teeWriteCloser writes to a primary writer and a secondary writer that never
+// fails, so errors and short writes are the primary writer's.
+type teeWriteCloser struct {
+	primary   io.WriteCloser
+	secondary io.WriteCloser
+}
+
+func (t *teeWriteCloser) Write(p []byte) (int, error) {
+	n, err := t.primary.Write(p)
+	t.secondary.Write(p[:n])
+	return n, err
+}
+
+func (t *teeWriteCloser) Close() error {
+	t.secondary.Close()
+	return t.primary.Close()
+}
+
 // logRotatorWrapper wraps our log rotator and exposes a pipe that can feed the
 // log rotator data. The processOutWriter should be attached to the process and
 // data will be copied from the reader to the rotator.
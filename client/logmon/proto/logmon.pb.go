@@ -25,16 +25,25 @@ var _ = math.Inf
 const _ = proto.ProtoPackageIsVersion3 // please upgrade the proto package
 
 type StartRequest struct {
-	LogDir               string   `protobuf:"bytes,1,opt,name=log_dir,json=logDir,proto3" json:"log_dir,omitempty"`
-	StdoutFileName       string   `protobuf:"bytes,2,opt,name=stdout_file_name,json=stdoutFileName,proto3" json:"stdout_file_name,omitempty"`
-	StderrFileName       string   `protobuf:"bytes,3,opt,name=stderr_file_name,json=stderrFileName,proto3" json:"stderr_file_name,omitempty"`
-	MaxFiles             uint32   `protobuf:"varint,4,opt,name=max_files,json=maxFiles,proto3" json:"max_files,omitempty"`
-	MaxFileSizeMb        uint32   `protobuf:"varint,5,opt,name=max_file_size_mb,json=maxFileSizeMb,proto3" json:"max_file_size_mb,omitempty"`
-	StdoutFifo           string   `protobuf:"bytes,6,opt,name=stdout_fifo,json=stdoutFifo,proto3" json:"stdout_fifo,omitempty"`
-	StderrFifo           string   `protobuf:"bytes,7,opt,name=stderr_fifo,json=stderrFifo,proto3" json:"stderr_fifo,omitempty"`
-	XXX_NoUnkeyedLiteral struct{} `json:"-"`
-	XXX_unrecognized     []byte   `json:"-"`
-	XXX_sizecache        int32    `json:"-"`
+	LogDir               string     `protobuf:"bytes,1,opt,name=log_dir,json=logDir,proto3" json:"log_dir,omitempty"`
+	StdoutFileName       string     `protobuf:"bytes,2,opt,name=stdout_file_name,json=stdoutFileName,proto3" json:"stdout_file_name,omitempty"`
+	StderrFileName       string     `protobuf:"bytes,3,opt,name=stderr_file_name,json=stderrFileName,proto3" json:"stderr_file_name,omitempty"`
+	MaxFiles             uint32     `protobuf:"varint,4,opt,name=max_files,json=maxFiles,proto3" json:"max_files,omitempty"`
+	MaxFileSizeMb        uint32     `protobuf:"varint,5,opt,name=max_file_size_mb,json=maxFileSizeMb,proto3" json:"max_file_size_mb,omitempty"`
+	StdoutFifo           string     `protobuf:"bytes,6,opt,name=stdout_fifo,json=stdoutFifo,proto3" json:"stdout_fifo,omitempty"`
+	StderrFifo           string     `protobuf:"bytes,7,opt,name=stderr_fifo,json=stderrFifo,proto3" json:"stderr_fifo,omitempty"`
+	Sinks                []*LogSink `protobuf:"bytes,8,rep,name=sinks,proto3" json:"sinks,omitempty"`
+	AllocId              string     `protobuf:"bytes,9,opt,name=alloc_id,json=allocId,proto3" json:"alloc_id,omitempty"`
+	Namespace            string     `protobuf:"bytes,10,opt,name=namespace,proto3" json:"namespace,omitempty"`
+	JobId                string     `protobuf:"bytes,11,opt,name=job_id,json=jobId,proto3" json:"job_id,omitempty"`
+	TaskGroup            string     `protobuf:"bytes,12,opt,name=task_group,json=taskGroup,proto3" json:"task_group,omitempty"`
+	TaskName             string     `protobuf:"bytes,13,opt,name=task_name,json=taskName,proto3" json:"task_name,omitempty"`
+	RateLimitLines       uint32     `protobuf:"varint,14,opt,name=rate_limit_lines,json=rateLimitLines,proto3" json:"rate_limit_lines,omitempty"`
+	RateLimitBytes       uint32     `protobuf:"varint,15,opt,name=rate_limit_bytes,json=rateLimitBytes,proto3" json:"rate_limit_bytes,omitempty"`
+	CompressRotated      bool       `protobuf:"varint,16,opt,name=compress_rotated,json=compressRotated,proto3" json:"compress_rotated,omitempty"`
+	XXX_NoUnkeyedLiteral struct{}   `json:"-"`
+	XXX_unrecognized     []byte     `json:"-"`
+	XXX_sizecache        int32      `json:"-"`
 }
 
 func (m *StartRequest) Reset()         { *m = StartRequest{} }
@@ -111,6 +120,69 @@ func (m *StartRequest) GetStderrFifo() string {
 	return ""
 }
 
+func (m *StartRequest) GetSinks() []*LogSink {
+	if m != nil {
+		return m.Sinks
+	}
+	return nil
+}
+
+func (m *StartRequest) GetAllocId() string {
+	if m != nil {
+		return m.AllocId
+	}
+	return ""
+}
+
+func (m *StartRequest) GetNamespace() string {
+	if m != nil {
+		return m.Namespace
+	}
+	return ""
+}
+
+func (m *StartRequest) GetJobId() string {
+	if m != nil {
+		return m.JobId
+	}
+	return ""
+}
+
+func (m *StartRequest) GetTaskGroup() string {
+	if m != nil {
+		return m.TaskGroup
+	}
+	return ""
+}
+
+func (m *StartRequest) GetTaskName() string {
+	if m != nil {
+		return m.TaskName
+	}
+	return ""
+}
+
+func (m *StartRequest) GetRateLimitLines() uint32 {
+	if m != nil {
+		return m.RateLimitLines
+	}
+	return 0
+}
+
+func (m *StartRequest) GetRateLimitBytes() uint32 {
+	if m != nil {
+		return m.RateLimitBytes
+	}
+	return 0
+}
+
+func (m *StartRequest) GetCompressRotated() bool {
+	if m != nil {
+		return m.CompressRotated
+	}
+	return false
+}
+
 type StartResponse struct {
 	XXX_NoUnkeyedLiteral struct{} `json:"-"`
 	XXX_unrecognized     []byte   `json:"-"`
@@ -204,11 +276,155 @@ func (m *StopResponse) XXX_DiscardUnknown() {
 
 var xxx_messageInfo_StopResponse proto.InternalMessageInfo
 
+type LogSink struct {
+	Type                 string   `protobuf:"bytes,1,opt,name=type,proto3" json:"type,omitempty"`
+	Address              string   `protobuf:"bytes,2,opt,name=address,proto3" json:"address,omitempty"`
+	Path                 string   `protobuf:"bytes,3,opt,name=path,proto3" json:"path,omitempty"`
+	BufferSize           uint32   `protobuf:"varint,4,opt,name=buffer_size,json=bufferSize,proto3" json:"buffer_size,omitempty"`
+	XXX_NoUnkeyedLiteral struct{} `json:"-"`
+	XXX_unrecognized     []byte   `json:"-"`
+	XXX_sizecache        int32    `json:"-"`
+}
+
+func (m *LogSink) Reset()         { *m = LogSink{} }
+func (m *LogSink) String() string { return proto.CompactTextString(m) }
+func (*LogSink) ProtoMessage()    {}
+func (*LogSink) Descriptor() ([]byte, []int) {
+	return fileDescriptor_be72d5e24d2ecba6, []int{4}
+}
+
+func (m *LogSink) XXX_Unmarshal(b []byte) error {
+	return xxx_messageInfo_LogSink.Unmarshal(m, b)
+}
+func (m *LogSink) XXX_Marshal(b []byte, deterministic bool) ([]byte, error) {
+	return xxx_messageInfo_LogSink.Marshal(b, m, deterministic)
+}
+func (m *LogSink) XXX_Merge(src proto.Message) {
+	xxx_messageInfo_LogSink.Merge(m, src)
+}
+func (m *LogSink) XXX_Size() int {
+	return xxx_messageInfo_LogSink.Size(m)
+}
+func (m *LogSink) XXX_DiscardUnknown() {
+	xxx_messageInfo_LogSink.DiscardUnknown(m)
+}
+
+var xxx_messageInfo_LogSink proto.InternalMessageInfo
+
+func (m *LogSink) GetType() string {
+	if m != nil {
+		return m.Type
+	}
+	return ""
+}
+
+func (m *LogSink) GetAddress() string {
+	if m != nil {
+		return m.Address
+	}
+	return ""
+}
+
+func (m *LogSink) GetPath() string {
+	if m != nil {
+		return m.Path
+	}
+	return ""
+}
+
+func (m *LogSink) GetBufferSize() uint32 {
+	if m != nil {
+		return m.BufferSize
+	}
+	return 0
+}
+
+type StatsRequest struct {
+	XXX_NoUnkeyedLiteral struct{} `json:"-"`
+	XXX_unrecognized     []byte   `json:"-"`
+	XXX_sizecache        int32    `json:"-"`
+}
+
+func (m *StatsRequest) Reset()         { *m = StatsRequest{} }
+func (m *StatsRequest) String() string { return proto.CompactTextString(m) }
+func (*StatsRequest) ProtoMessage()    {}
+func (*StatsRequest) Descriptor() ([]byte, []int) {
+	return fileDescriptor_be72d5e24d2ecba6, []int{5}
+}
+
+func (m *StatsRequest) XXX_Unmarshal(b []byte) error {
+	return xxx_messageInfo_StatsRequest.Unmarshal(m, b)
+}
+func (m *StatsRequest) XXX_Marshal(b []byte, deterministic bool) ([]byte, error) {
+	return xxx_messageInfo_StatsRequest.Marshal(b, m, deterministic)
+}
+func (m *StatsRequest) XXX_Merge(src proto.Message) {
+	xxx_messageInfo_StatsRequest.Merge(m, src)
+}
+func (m *StatsRequest) XXX_Size() int {
+	return xxx_messageInfo_StatsRequest.Size(m)
+}
+func (m *StatsRequest) XXX_DiscardUnknown() {
+	xxx_messageInfo_StatsRequest.DiscardUnknown(m)
+}
+
+var xxx_messageInfo_StatsRequest proto.InternalMessageInfo
+
+type StatsResponse struct {
+	DroppedLines         uint64   `protobuf:"varint,1,opt,name=dropped_lines,json=droppedLines,proto3" json:"dropped_lines,omitempty"`
+	DroppedBytes         uint64   `protobuf:"varint,2,opt,name=dropped_bytes,json=droppedBytes,proto3" json:"dropped_bytes,omitempty"`
+	XXX_NoUnkeyedLiteral struct{} `json:"-"`
+	XXX_unrecognized     []byte   `json:"-"`
+	XXX_sizecache        int32    `json:"-"`
+}
+
+func (m *StatsResponse) Reset()         { *m = StatsResponse{} }
+func (m *StatsResponse) String() string { return proto.CompactTextString(m) }
+func (*StatsResponse) ProtoMessage()    {}
+func (*StatsResponse) Descriptor() ([]byte, []int) {
+	return fileDescriptor_be72d5e24d2ecba6, []int{6}
+}
+
+func (m *StatsResponse) XXX_Unmarshal(b []byte) error {
+	return xxx_messageInfo_StatsResponse.Unmarshal(m, b)
+}
+func (m *StatsResponse) XXX_Marshal(b []byte, deterministic bool) ([]byte, error) {
+	return xxx_messageInfo_StatsResponse.Marshal(b, m, deterministic)
+}
+func (m *StatsResponse) XXX_Merge(src proto.Message) {
+	xxx_messageInfo_StatsResponse.Merge(m, src)
+}
+func (m *StatsResponse) XXX_Size() int {
+	return xxx_messageInfo_StatsResponse.Size(m)
+}
+func (m *StatsResponse) XXX_DiscardUnknown() {
+	xxx_messageInfo_StatsResponse.DiscardUnknown(m)
+}
+
+var xxx_messageInfo_StatsResponse proto.InternalMessageInfo
+
+func (m *StatsResponse) GetDroppedLines() uint64 {
+	if m != nil {
+		return m.DroppedLines
+	}
+	return 0
+}
+
+func (m *StatsResponse) GetDroppedBytes() uint64 {
+	if m != nil {
+		return m.DroppedBytes
+	}
+	return 0
+}
+
 func init() {
 	proto.RegisterType((*StartRequest)(nil), "hashicorp.nomad.client.logmon.proto.StartRequest")
 	proto.RegisterType((*StartResponse)(nil), "hashicorp.nomad.client.logmon.proto.StartResponse")
 	proto.RegisterType((*StopRequest)(nil), "hashicorp.nomad.client.logmon.proto.StopRequest")
 	proto.RegisterType((*StopResponse)(nil), "hashicorp.nomad.client.logmon.proto.StopResponse")
+	proto.RegisterType((*LogSink)(nil), "hashicorp.nomad.client.logmon.proto.LogSink")
+	proto.RegisterType((*StatsRequest)(nil), "hashicorp.nomad.client.logmon.proto.StatsRequest")
+	proto.RegisterType((*StatsResponse)(nil), "hashicorp.nomad.client.logmon.proto.StatsResponse")
 }
 
 func init() {
@@ -216,27 +432,44 @@ func init() {
 }
 
 var fileDescriptor_be72d5e24d2ecba6 = []byte{
-	// 320 bytes of a gzipped FileDescriptorProto
-	0x1f, 0x8b, 0x08, 0x00, 0x00, 0x00, 0x00, 0x00, 0x02, 0xff, 0x94, 0x90, 0x31, 0x6f, 0xc2, 0x30,
-	0x10, 0x85, 0x1b, 0x0a, 0xa1, 0x1c, 0x0d, 0x45, 0x5e, 0x1a, 0xd1, 0xa1, 0x28, 0x1d, 0xca, 0x14,
-	0x0a, 0xfd, 0x07, 0x55, 0xd5, 0xa9, 0x74, 0x80, 0xad, 0x4b, 0x64, 0xc0, 0x09, 0x96, 0x62, 0x5f,
-	0x6a, 0x1b, 0x09, 0xb1, 0xf6, 0xd7, 0xf6, 0x5f, 0x54, 0x71, 0x4c, 0x94, 0x11, 0x26, 0xeb, 0xee,
-	0x7d, 0x4f, 0xf7, 0xfc, 0x60, 0xbc, 0xc9, 0x39, 0x93, 0x66, 0x9a, 0x63, 0x26, 0x50, 0x4e, 0x0b,
-	0x85, 0x06, 0xdd, 0x10, 0xdb, 0x81, 0x3c, 0xed, 0xa8, 0xde, 0xf1, 0x0d, 0xaa, 0x22, 0x96, 0x28,
-	0xe8, 0x36, 0xae, 0x1c, 0x71, 0x13, 0x8a, 0x7e, 0x5b, 0x70, 0xbb, 0x32, 0x54, 0x99, 0x25, 0xfb,
-	0xd9, 0x33, 0x6d, 0xc8, 0x3d, 0x74, 0x73, 0xcc, 0x92, 0x2d, 0x57, 0xa1, 0x37, 0xf6, 0x26, 0xbd,
-	0xa5, 0x9f, 0x63, 0xf6, 0xce, 0x15, 0x99, 0xc0, 0x50, 0x9b, 0x2d, 0xee, 0x4d, 0x92, 0xf2, 0x9c,
-	0x25, 0x92, 0x0a, 0x16, 0xb6, 0x2c, 0x31, 0xa8, 0xf6, 0x1f, 0x3c, 0x67, 0x5f, 0x54, 0x30, 0x47,
-	0x32, 0xa5, 0x1a, 0xe4, 0x75, 0x4d, 0x32, 0xa5, 0x6a, 0xf2, 0x01, 0x7a, 0x82, 0x1e, 0x2c, 0xa6,
-	0xc3, 0xf6, 0xd8, 0x9b, 0x04, 0xcb, 0x1b, 0x41, 0x0f, 0xa5, 0xae, 0xc9, 0x33, 0x0c, 0x4f, 0x62,
-	0xa2, 0xf9, 0x91, 0x25, 0x62, 0x1d, 0x76, 0x2c, 0x13, 0x38, 0x66, 0xc5, 0x8f, 0x6c, 0xb1, 0x26,
-	0x8f, 0xd0, 0xaf, 0x93, 0xa5, 0x18, 0xfa, 0xf6, 0x14, 0x9c, 0x42, 0xa5, 0xe8, 0x80, 0x2a, 0x50,
-	0x8a, 0x61, 0xb7, 0x06, 0x6c, 0x96, 0x14, 0xa3, 0x3b, 0x08, 0x5c, 0x09, 0xba, 0x40, 0xa9, 0x59,
-	0x14, 0x40, 0x7f, 0x65, 0xb0, 0x70, 0xa5, 0x44, 0x83, 0xb2, 0xa4, 0x72, 0xac, 0xe4, 0xf9, 0x9f,
-	0x07, 0xfe, 0x27, 0x66, 0x0b, 0x94, 0xa4, 0x80, 0x8e, 0xb5, 0x92, 0x59, 0x7c, 0x46, 0xdf, 0x71,
-	0xb3, 0xeb, 0xd1, 0xfc, 0x12, 0x8b, 0x4b, 0x76, 0x45, 0x04, 0xb4, 0xcb, 0x30, 0xe4, 0xe5, 0x4c,
-	0x77, 0xfd, 0x8d, 0xd1, 0xec, 0x02, 0xc7, 0xe9, 0xdc, 0x5b, 0xf7, 0xbb, 0x63, 0xf7, 0x6b, 0xdf,
-	0x3e, 0xaf, 0xff, 0x01, 0x00, 0x00, 0xff, 0xff, 0xd8, 0xb4, 0x87, 0xfc, 0x7a, 0x02, 0x00, 0x00,
+	// 582 bytes of a gzipped FileDescriptorProto
+	0x1f, 0x8b, 0x08, 0x00, 0x00, 0x00, 0x00, 0x00, 0x02, 0xff, 0x94, 0x53, 0xcd, 0x6e, 0xdb, 0x3c,
+	0x10, 0x8c, 0x13, 0xff, 0xae, 0x2d, 0xc7, 0x20, 0xf0, 0xe1, 0x63, 0xd3, 0x16, 0x35, 0x9c, 0x43,
+	0x5d, 0xa0, 0x70, 0x9a, 0xf4, 0x0d, 0x82, 0xa2, 0x45, 0x00, 0xa7, 0x07, 0xf9, 0xd4, 0x5e, 0x04,
+	0xca, 0xa2, 0x1c, 0x26, 0x92, 0x96, 0x25, 0x69, 0x20, 0xc9, 0x63, 0xf6, 0xda, 0x97, 0x29, 0xf8,
+	0x23, 0xc5, 0xcd, 0xc9, 0x39, 0x59, 0x3b, 0x33, 0x8b, 0x5d, 0xce, 0x8e, 0x61, 0xba, 0x2e, 0x04,
+	0xaf, 0xcc, 0x59, 0x81, 0x9b, 0x12, 0xab, 0x33, 0xa9, 0xd0, 0x60, 0x28, 0x16, 0xae, 0x20, 0xa7,
+	0x37, 0x4c, 0xdf, 0x88, 0x35, 0x2a, 0xb9, 0xa8, 0xb0, 0x64, 0xd9, 0xc2, 0x77, 0x2c, 0x76, 0x45,
+	0xb3, 0xdf, 0x6d, 0x18, 0xad, 0x0c, 0x53, 0x26, 0xe6, 0xbf, 0xb6, 0x5c, 0x1b, 0xf2, 0x3f, 0xf4,
+	0x0a, 0xdc, 0x24, 0x99, 0x50, 0xb4, 0x35, 0x6d, 0xcd, 0x07, 0x71, 0xb7, 0xc0, 0xcd, 0x17, 0xa1,
+	0xc8, 0x1c, 0x26, 0xda, 0x64, 0xb8, 0x35, 0x49, 0x2e, 0x0a, 0x9e, 0x54, 0xac, 0xe4, 0xf4, 0xd0,
+	0x29, 0xc6, 0x1e, 0xff, 0x2a, 0x0a, 0xfe, 0x9d, 0x95, 0x3c, 0x28, 0xb9, 0x52, 0x3b, 0xca, 0xa3,
+	0x46, 0xc9, 0x95, 0x6a, 0x94, 0xaf, 0x61, 0x50, 0xb2, 0x7b, 0x27, 0xd3, 0xb4, 0x3d, 0x6d, 0xcd,
+	0xa3, 0xb8, 0x5f, 0xb2, 0x7b, 0xcb, 0x6b, 0xf2, 0x1e, 0x26, 0x35, 0x99, 0x68, 0xf1, 0xc8, 0x93,
+	0x32, 0xa5, 0x1d, 0xa7, 0x89, 0x82, 0x66, 0x25, 0x1e, 0xf9, 0x75, 0x4a, 0xde, 0xc1, 0xb0, 0xd9,
+	0x2c, 0x47, 0xda, 0x75, 0xa3, 0xa0, 0x5e, 0x2a, 0xc7, 0x20, 0xf0, 0x0b, 0xe5, 0x48, 0x7b, 0x8d,
+	0xc0, 0xed, 0x92, 0x23, 0xb9, 0x84, 0x8e, 0x16, 0xd5, 0x9d, 0xa6, 0xfd, 0xe9, 0xd1, 0x7c, 0x78,
+	0xf1, 0x71, 0xb1, 0x87, 0x75, 0x8b, 0x25, 0x6e, 0x56, 0xa2, 0xba, 0x8b, 0x7d, 0x2b, 0x79, 0x05,
+	0x7d, 0x56, 0x14, 0xb8, 0x4e, 0x44, 0x46, 0x07, 0x6e, 0x42, 0xcf, 0xd5, 0x57, 0x19, 0x79, 0x03,
+	0x03, 0x6b, 0x82, 0x96, 0x6c, 0xcd, 0x29, 0x38, 0xee, 0x09, 0x20, 0xff, 0x41, 0xf7, 0x16, 0x53,
+	0xdb, 0x36, 0x74, 0x54, 0xe7, 0x16, 0xd3, 0xab, 0x8c, 0xbc, 0x05, 0x30, 0x4c, 0xdf, 0x25, 0x1b,
+	0x85, 0x5b, 0x49, 0x47, 0xbe, 0xcb, 0x22, 0xdf, 0x2c, 0x60, 0xad, 0x73, 0xb4, 0x73, 0x37, 0x72,
+	0x6c, 0xdf, 0x02, 0xf5, 0x05, 0x14, 0x33, 0x3c, 0x29, 0x44, 0x29, 0x4c, 0x52, 0x88, 0x8a, 0x6b,
+	0x3a, 0x76, 0xd6, 0x8d, 0x2d, 0xbe, 0xb4, 0xf0, 0xd2, 0xa2, 0xcf, 0x94, 0xe9, 0x83, 0xe1, 0x9a,
+	0x1e, 0x3f, 0x53, 0x5e, 0x5a, 0x94, 0x7c, 0x80, 0xc9, 0x1a, 0x4b, 0xa9, 0xb8, 0xd6, 0x89, 0x42,
+	0xc3, 0x0c, 0xcf, 0xe8, 0x64, 0xda, 0x9a, 0xf7, 0xe3, 0xe3, 0x1a, 0x8f, 0x3d, 0x3c, 0x3b, 0x86,
+	0x28, 0x64, 0x4a, 0x4b, 0xac, 0x34, 0x9f, 0x45, 0x30, 0x5c, 0x19, 0x94, 0x21, 0x63, 0xb3, 0x31,
+	0x8c, 0x7c, 0x19, 0xe8, 0x02, 0x7a, 0xc1, 0x4c, 0x42, 0xa0, 0x6d, 0x1e, 0x24, 0x0f, 0xd9, 0x73,
+	0xdf, 0x84, 0x42, 0x8f, 0x65, 0x99, 0x1d, 0x10, 0x02, 0x57, 0x97, 0x56, 0x2d, 0x99, 0xb9, 0x09,
+	0xe9, 0x72, 0xdf, 0xf6, 0xd8, 0xe9, 0x36, 0xcf, 0xb9, 0x72, 0xa1, 0x09, 0xa9, 0x02, 0x0f, 0xd9,
+	0xc0, 0xf8, 0xe9, 0xcc, 0xe8, 0x7a, 0x9b, 0x1f, 0x10, 0x85, 0xda, 0xaf, 0x43, 0x4e, 0x21, 0xca,
+	0x14, 0x4a, 0xc9, 0xb3, 0x60, 0x9d, 0x5d, 0xa6, 0x1d, 0x8f, 0x02, 0xe8, 0x8d, 0xdb, 0x11, 0x79,
+	0xd7, 0x0e, 0xff, 0x11, 0x39, 0xcf, 0x2e, 0xfe, 0x1c, 0x42, 0x77, 0x89, 0x9b, 0x6b, 0xac, 0x88,
+	0x84, 0x8e, 0xf3, 0x84, 0x9c, 0xef, 0x15, 0xae, 0xdd, 0xff, 0xe4, 0xc9, 0xc5, 0x4b, 0x5a, 0x82,
+	0xa7, 0x07, 0xa4, 0x84, 0xb6, 0x75, 0x99, 0x7c, 0xda, 0xb3, 0xbb, 0xb9, 0xcf, 0xc9, 0xf9, 0x0b,
+	0x3a, 0x9a, 0x71, 0xfe, 0x81, 0x46, 0xef, 0xff, 0x40, 0xa3, 0x5f, 0xfc, 0xc0, 0xa7, 0x2b, 0xcd,
+	0x0e, 0x2e, 0x7b, 0x3f, 0x3b, 0x8e, 0x48, 0xbb, 0xee, 0xe7, 0xf3, 0xdf, 0x01, 0x00, 0x0b, 0x56,
+	0xe9, 0x3e, 0x14, 0x05, 0x00, 0x00,
 }
 
 // Reference imports to suppress errors if they are not otherwise used.
@@ -253,6 +486,7 @@ const _ = grpc.SupportPackageIsVersion6
 type LogMonClient interface {
 	Start(ctx context.Context, in *StartRequest, opts ...grpc.CallOption) (*StartResponse, error)
 	Stop(ctx context.Context, in *StopRequest, opts ...grpc.CallOption) (*StopResponse, error)
+	Stats(ctx context.Context, in *StatsRequest, opts ...grpc.CallOption) (*StatsResponse, error)
 }
 
 type logMonClient struct {
@@ -281,10 +515,20 @@ func (c *logMonClient) Stop(ctx context.Context, in *StopRequest, opts ...grpc.C
 	return out, nil
 }
 
+func (c *logMonClient) Stats(ctx context.Context, in *StatsRequest, opts ...grpc.CallOption) (*StatsResponse, error) {
+	out := new(StatsResponse)
+	err := c.cc.Invoke(ctx, "/hashicorp.nomad.client.logmon.proto.LogMon/Stats", in, out, opts...)
+	if err != nil {
+		return nil, err
+	}
+	return out, nil
+}
+
 // LogMonServer is the server API for LogMon service.
 type LogMonServer interface {
 	Start(context.Context, *StartRequest) (*StartResponse, error)
 	Stop(context.Context, *StopRequest) (*StopResponse, error)
+	Stats(context.Context, *StatsRequest) (*StatsResponse, error)
 }
 
 // UnimplementedLogMonServer can be embedded to have forward compatible implementations.
@@ -297,6 +541,9 @@ func (*UnimplementedLogMonServer) Start(ctx context.Context, req *StartRequest)
 func (*UnimplementedLogMonServer) Stop(ctx context.Context, req *StopRequest) (*StopResponse, error) {
 	return nil, status.Errorf(codes.Unimplemented, "method Stop not implemented")
 }
+func (*UnimplementedLogMonServer) Stats(ctx context.Context, req *StatsRequest) (*StatsResponse, error) {
+	return nil, status.Errorf(codes.Unimplemented, "method Stats not implemented")
+}
 
 func RegisterLogMonServer(s *grpc.Server, srv LogMonServer) {
 	s.RegisterService(&_LogMon_serviceDesc, srv)
@@ -338,6 +585,24 @@ func _LogMon_Stop_Handler(srv interface{}, ctx context.Context, dec func(interfa
 	return interceptor(ctx, in, info, handler)
 }
 
+func _LogMon_Stats_Handler(srv interface{}, ctx context.Context, dec func(interface{}) error, interceptor grpc.UnaryServerInterceptor) (interface{}, error) {
+	in := new(StatsRequest)
+	if err := dec(in); err != nil {
+		return nil, err
+	}
+	if interceptor == nil {
+		return srv.(LogMonServer).Stats(ctx, in)
+	}
+	info := &grpc.UnaryServerInfo{
+		Server:     srv,
+		FullMethod: "/hashicorp.nomad.client.logmon.proto.LogMon/Stats",
+	}
+	handler := func(ctx context.Context, req interface{}) (interface{}, error) {
+		return srv.(LogMonServer).Stats(ctx, req.(*StatsRequest))
+	}
+	return interceptor(ctx, in, info, handler)
+}
+
 var _LogMon_serviceDesc = grpc.ServiceDesc{
 	ServiceName: "hashicorp.nomad.client.logmon.proto.LogMon",
 	HandlerType: (*LogMonServer)(nil),
@@ -350,6 +615,10 @@ var _LogMon_serviceDesc = grpc.ServiceDesc{
 			MethodName: "Stop",
 			Handler:    _LogMon_Stop_Handler,
 		},
+		{
+			MethodName: "Stats",
+			Handler:    _LogMon_Stats_Handler,
+		},
 	},
 	Streams:  []grpc.StreamDesc{},
 	Metadata: "client/logmon/proto/logmon.proto",
@@ -4,6 +4,7 @@ import (
 	"golang.org/x/net/context"
 
 	plugin "github.com/hashicorp/go-plugin"
+	"github.com/hashicorp/nomad/client/logmon/logging"
 	"github.com/hashicorp/nomad/client/logmon/proto"
 )
 
@@ -14,13 +15,31 @@ type logmonServer struct {
 
 func (s *logmonServer) Start(ctx context.Context, req *proto.StartRequest) (*proto.StartResponse, error) {
 	cfg := &LogConfig{
-		LogDir:        req.LogDir,
-		StdoutLogFile: req.StdoutFileName,
-		StderrLogFile: req.StderrFileName,
-		MaxFiles:      int(req.MaxFiles),
-		MaxFileSizeMB: int(req.MaxFileSizeMb),
-		StdoutFifo:    req.StdoutFifo,
-		StderrFifo:    req.StderrFifo,
+		LogDir:          req.LogDir,
+		StdoutLogFile:   req.StdoutFileName,
+		StderrLogFile:   req.StderrFileName,
+		MaxFiles:        int(req.MaxFiles),
+		MaxFileSizeMB:   int(req.MaxFileSizeMb),
+		StdoutFifo:      req.StdoutFifo,
+		StderrFifo:      req.StderrFifo,
+		RateLimitLines:  int(req.RateLimitLines),
+		RateLimitBytes:  int(req.RateLimitBytes),
+		CompressRotated: req.CompressRotated,
+		TaskMeta: logging.TaskMeta{
+			AllocID:   req.AllocId,
+			Namespace: req.Namespace,
+			JobID:     req.JobId,
+			TaskGroup: req.TaskGroup,
+			TaskName:  req.TaskName,
+		},
+	}
+	for _, sink := range req.Sinks {
+		cfg.Sinks = append(cfg.Sinks, &logging.SinkConfig{
+			Type:       sink.Type,
+			Address:    sink.Address,
+			Path:       sink.Path,
+			BufferSize: int(sink.BufferSize),
+		})
 	}
 
 	err := s.impl.Start(cfg)
@@ -34,3 +53,14 @@ func (s *logmonServer) Start(ctx context.Context, req *proto.StartRequest) (*pro
 func (s *logmonServer) Stop(ctx context.Context, req *proto.StopRequest) (*proto.StopResponse, error) {
 	return &proto.StopResponse{}, s.impl.Stop()
 }
+
+func (s *logmonServer) Stats(ctx context.Context, req *proto.StatsRequest) (*proto.StatsResponse, error) {
+	stats, err := s.impl.Stats()
+	if err != nil {
+		return nil, err
+	}
+	return &proto.StatsResponse{
+		DroppedLines: stats.DroppedLines,
+		DroppedBytes: stats.DroppedBytes,
+	}, nil
+}
@@ -0,0 +1,202 @@
+package serviceregistration
+
+import (
+	"fmt"
+	"strings"
+
+	"github.com/hashicorp/go-hclog"
+	"github.com/hashicorp/go-multierror"
+	"github.com/hashicorp/nomad/client/consul"
+	agentconsul "github.com/hashicorp/nomad/command/agent/consul"
+	"github.com/hashicorp/nomad/nomad/structs"
+)
+
+// NomadHandler is the Nomad native service discovery implementation of the
+// consul.ConsulServiceAPI interface. Service registrations are written to the
+// Nomad servers via RPC using the node secret ID.
+type NomadHandler struct {
+	cfg *NomadHandlerConfig
+	log hclog.Logger
+}
+
+// NomadHandlerConfig holds the information required to create a NomadHandler.
+type NomadHandlerConfig struct {
+	// Log is the logger used by the handler.
+	Log hclog.Logger
+
+	// RPCFn is the client RPC function used to communicate with the servers.
+	RPCFn func(method string, args, resp interface{}) error
+
+	// NodeID, NodeSecret, Datacenter and Region describe the client node
+	// which owns the registrations.
+	NodeID     string
+	NodeSecret string
+	Datacenter string
+	Region     string
+}
+
+// NewNomadHandler returns a ready to use NomadHandler.
+func NewNomadHandler(cfg *NomadHandlerConfig) *NomadHandler {
+	return &NomadHandler{
+		cfg: cfg,
+		log: cfg.Log.Named("service_registration.nomad"),
+	}
+}
+
+// Ensure NomadHandler satisfies the ConsulServiceAPI interface.
+var _ consul.ConsulServiceAPI = (*NomadHandler)(nil)
+
+// RegisterWorkload registers all the services of the workload with the Nomad
+// servers.
+func (s *NomadHandler) RegisterWorkload(workload *agentconsul.WorkloadServices) error {
+	// Check whether there are any services to add. This shouldn't be needed
+	// as the callers check this before calling, but it avoids an RPC.
+	if len(workload.Services) == 0 {
+		return nil
+	}
+
+	// Use a multierror, so we can catch all service errors and pass them back
+	// to the caller together.
+	var mErr multierror.Error
+
+	registrations := make([]*structs.ServiceRegistration, len(workload.Services))
+
+	for i, service := range workload.Services {
+		registration, err := s.serviceRegistration(service, workload)
+		if err != nil {
+			mErr.Errors = append(mErr.Errors, err)
+		}
+		registrations[i] = registration
+	}
+
+	if err := mErr.ErrorOrNil(); err != nil {
+		return err
+	}
+
+	args := structs.ServiceRegistrationUpsertRequest{
+		Services: registrations,
+		WriteRequest: structs.WriteRequest{
+			Region:    s.cfg.Region,
+			Namespace: workload.Namespace,
+			AuthToken: s.cfg.NodeSecret,
+		},
+	}
+
+	var resp structs.ServiceRegistrationUpsertResponse
+
+	return s.cfg.RPCFn("ServiceRegistration.Upsert", &args, &resp)
+}
+
+// RemoveWorkload iterates the services and removes them from the service
+// registration state.
+func (s *NomadHandler) RemoveWorkload(workload *agentconsul.WorkloadServices) {
+	for _, serviceSpec := range workload.Services {
+		s.removeService(workload, serviceSpec)
+	}
+}
+
+func (s *NomadHandler) removeService(workload *agentconsul.WorkloadServices, serviceSpec *structs.Service) {
+
+	// Generate the consistent ID for this service, so we know what to remove.
+	id := agentconsul.MakeAllocServiceID(workload.AllocID, workload.Name(), serviceSpec)
+
+	deleteArgs := structs.ServiceRegistrationDeleteByIDRequest{
+		ID: id,
+		WriteRequest: structs.WriteRequest{
+			Region:    s.cfg.Region,
+			Namespace: workload.Namespace,
+			AuthToken: s.cfg.NodeSecret,
+		},
+	}
+
+	var deleteResp structs.ServiceRegistrationDeleteByIDResponse
+
+	err := s.cfg.RPCFn("ServiceRegistration.DeleteByID", &deleteArgs, &deleteResp)
+	if err == nil {
+		return
+	}
+
+	// The Nomad API exposes service registration deletion to handle
+	// orphaned service registrations. In the event a service is removed
+	// accidentally that is still running, we will hit this error when we
+	// eventually want to remove it. We therefore want to handle this,
+	// while ensuring the operator can see.
+	if strings.Contains(err.Error(), "service registration not found") {
+		s.log.Info("attempted to delete non-existent service registration",
+			"service_id", id, "namespace", workload.Namespace)
+		return
+	}
+
+	// Log the error as there is nothing left to do, so the operator can see
+	// it and identify any problems.
+	s.log.Error("failed to delete service registration",
+		"error", err, "service_id", id, "namespace", workload.Namespace)
+}
+
+// UpdateWorkload removes workload as specified by the old parameter, and adds
+// the new workload as specified by the new parameter. Callers do not know
+// whether the services have changed, so any registrations which remain are
+// upserted and only those removed are deleted.
+func (s *NomadHandler) UpdateWorkload(old, new *agentconsul.WorkloadServices) error {
+	newIDs := make(map[string]struct{}, len(new.Services))
+	for _, service := range new.Services {
+		newIDs[agentconsul.MakeAllocServiceID(new.AllocID, new.Name(), service)] = struct{}{}
+	}
+
+	// Remove any services which are no longer part of the workload.
+	for _, existingSvc := range old.Services {
+		existingID := agentconsul.MakeAllocServiceID(old.AllocID, old.Name(), existingSvc)
+		if _, ok := newIDs[existingID]; !ok {
+			s.removeService(old, existingSvc)
+		}
+	}
+
+	return s.RegisterWorkload(new)
+}
+
+// AllocRegistrations is currently a noop implementation as the Nomad provider
+// does not support health check which is the sole subsystem caller of this
+// function.
+func (s *NomadHandler) AllocRegistrations(_ string) (*agentconsul.AllocRegistration, error) {
+	return nil, nil
+}
+
+// UpdateTTL is currently a noop implementation as the Nomad provider does not
+// support health check which is the sole subsystem caller of this function.
+func (s *NomadHandler) UpdateTTL(_, _, _, _ string) error {
+	return nil
+}
+
+// serviceRegistration converts the passed service into a service registration
+// object, resolving the address and port from the workload networking.
+func (s *NomadHandler) serviceRegistration(service *structs.Service, workload *agentconsul.WorkloadServices) (
+	*structs.ServiceRegistration, error) {
+
+	ip, port, err := agentconsul.GetAddress(
+		service.AddressMode, service.PortLabel, workload.Networks,
+		workload.DriverNetwork, workload.Ports, workload.NetworkStatus)
+	if err != nil {
+		return nil, fmt.Errorf("unable to get address for service %q: %v", service.Name, err)
+	}
+
+	// Determine the tags for the service.
+	tags := make([]string, len(service.Tags))
+	copy(tags, service.Tags)
+	if workload.Canary && len(service.CanaryTags) > 0 {
+		tags = make([]string, len(service.CanaryTags))
+		copy(tags, service.CanaryTags)
+	}
+
+	return &structs.ServiceRegistration{
+		ID:          agentconsul.MakeAllocServiceID(workload.AllocID, workload.Name(), service),
+		ServiceName: service.Name,
+		NodeID:      s.cfg.NodeID,
+		JobID:       workload.JobID,
+		AllocID:     workload.AllocID,
+		Namespace:   workload.Namespace,
+		Datacenter:  s.cfg.Datacenter,
+		Tags:        tags,
+		Address:     ip,
+		Port:        port,
+	}, nil
+}
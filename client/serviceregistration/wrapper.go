@@ -0,0 +1,136 @@
+package serviceregistration
+
+import (
+	"fmt"
+
+	"github.com/hashicorp/go-hclog"
+	"github.com/hashicorp/nomad/client/consul"
+	agentconsul "github.com/hashicorp/nomad/command/agent/consul"
+	"github.com/hashicorp/nomad/nomad/structs"
+)
+
+// HandlerWrapper is used to wrap service registration implementations of the
+// consul.ConsulServiceAPI interface. It dispatches each workload to the
+// handler matching the provider of its services. Validation ensures all the
+// services of a task group use the same provider.
+type HandlerWrapper struct {
+	log hclog.Logger
+
+	// consulServiceProvider is the handler for services where Consul is the
+	// provider. This provider is always created and available.
+	consulServiceProvider consul.ConsulServiceAPI
+
+	// nomadServiceProvider is the handler for services where Nomad is the
+	// provider.
+	nomadServiceProvider consul.ConsulServiceAPI
+}
+
+// Ensure HandlerWrapper satisfies the ConsulServiceAPI interface.
+var _ consul.ConsulServiceAPI = (*HandlerWrapper)(nil)
+
+// NewHandlerWrapper configures and returns a HandlerWrapper for use within
+// client hooks that need to interact with service and check registrations.
+func NewHandlerWrapper(
+	log hclog.Logger, consulProvider, nomadProvider consul.ConsulServiceAPI) *HandlerWrapper {
+	return &HandlerWrapper{
+		log:                   log,
+		nomadServiceProvider:  nomadProvider,
+		consulServiceProvider: consulProvider,
+	}
+}
+
+// RegisterWorkload wraps the RegisterWorkload function of the provider used
+// by the workload services.
+func (h *HandlerWrapper) RegisterWorkload(workload *agentconsul.WorkloadServices) error {
+	if len(workload.Services) == 0 {
+		return nil
+	}
+
+	provider, err := h.providerFor(workload)
+	if err != nil {
+		return err
+	}
+	return provider.RegisterWorkload(workload)
+}
+
+// RemoveWorkload wraps the RemoveWorkload function of the provider used by
+// the workload services.
+func (h *HandlerWrapper) RemoveWorkload(workload *agentconsul.WorkloadServices) {
+	if len(workload.Services) == 0 {
+		return
+	}
+
+	provider, err := h.providerFor(workload)
+	if err != nil {
+		h.log.Error("failed to remove workload", "error", err)
+		return
+	}
+	provider.RemoveWorkload(workload)
+}
+
+// UpdateWorkload wraps the UpdateWorkload function of the provider used by
+// the workload services. If the provider has changed between the old and new
+// workloads, the old workload is removed from its provider and the new one
+// registered with the other.
+func (h *HandlerWrapper) UpdateWorkload(old, new *agentconsul.WorkloadServices) error {
+
+	// If neither the old nor the new workloads have services, there is
+	// nothing to do.
+	if len(old.Services) == 0 && len(new.Services) == 0 {
+		return nil
+	}
+
+	// If the old workload had no services, this is an addition.
+	if len(old.Services) == 0 {
+		return h.RegisterWorkload(new)
+	}
+
+	// If the new workload has no services, this is a removal.
+	if len(new.Services) == 0 {
+		h.RemoveWorkload(old)
+		return nil
+	}
+
+	oldProvider, err := h.providerFor(old)
+	if err != nil {
+		return err
+	}
+	newProvider, err := h.providerFor(new)
+	if err != nil {
+		return err
+	}
+
+	// A change of provider cannot be handled by an update within a single
+	// provider.
+	if oldProvider != newProvider {
+		oldProvider.RemoveWorkload(old)
+		return newProvider.RegisterWorkload(new)
+	}
+	return newProvider.UpdateWorkload(old, new)
+}
+
+// AllocRegistrations returns the Consul registrations for the allocation.
+// Checks are only supported by the Consul provider.
+func (h *HandlerWrapper) AllocRegistrations(allocID string) (*agentconsul.AllocRegistration, error) {
+	return h.consulServiceProvider.AllocRegistrations(allocID)
+}
+
+// UpdateTTL updates the TTL of a Consul check. Checks are only supported by
+// the Consul provider.
+func (h *HandlerWrapper) UpdateTTL(id, namespace, output, status string) error {
+	return h.consulServiceProvider.UpdateTTL(id, namespace, output, status)
+}
+
+// providerFor returns the handler responsible for the workload services. The
+// provider of the first service is used, as task group validation ensures a
+// single provider is used.
+func (h *HandlerWrapper) providerFor(workload *agentconsul.WorkloadServices) (consul.ConsulServiceAPI, error) {
+	switch provider := workload.Services[0].Provider; provider {
+	case structs.ServiceProviderNomad:
+		return h.nomadServiceProvider, nil
+	case "", structs.ServiceProviderConsul:
+		return h.consulServiceProvider, nil
+	default:
+		return nil, fmt.Errorf("unknown service registration provider: %q", provider)
+	}
+}
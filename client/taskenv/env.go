@@ -108,6 +108,10 @@ const (
 	// VaultToken is the environment variable for passing the Vault token
 	VaultToken = "VAULT_TOKEN"
 
+	// WorkloadToken is the environment variable for passing the task's
+	// workload identity
+	WorkloadToken = "NOMAD_TOKEN"
+
 	// VaultNamespace is the environment variable for passing the Vault namespace, if applicable
 	VaultNamespace = "VAULT_NAMESPACE"
 )
@@ -29,6 +29,12 @@ List Options:
 
   -t
     Format and display the ACL tokens using a Go template.
+
+  -per-page
+    How many results to show per page.
+
+  -page-token
+    Where to start pagination.
 `
 
 	return strings.TrimSpace(helpText)
@@ -37,8 +43,10 @@ List Options:
 func (c *ACLTokenListCommand) AutocompleteFlags() complete.Flags {
 	return mergeAutocompleteFlags(c.Meta.AutocompleteFlags(FlagSetClient),
 		complete.Flags{
-			"-json": complete.PredictNothing,
-			"-t":    complete.PredictAnything,
+			"-json":       complete.PredictNothing,
+			"-t":          complete.PredictAnything,
+			"-per-page":   complete.PredictAnything,
+			"-page-token": complete.PredictAnything,
 		})
 }
 
@@ -54,12 +62,15 @@ func (c *ACLTokenListCommand) Name() string { return "acl token list" }
 
 func (c *ACLTokenListCommand) Run(args []string) int {
 	var json bool
-	var tmpl string
+	var tmpl, pageToken string
+	var perPage int
 
 	flags := c.Meta.FlagSet(c.Name(), FlagSetClient)
 	flags.Usage = func() { c.Ui.Output(c.Help()) }
 	flags.BoolVar(&json, "json", false, "")
 	flags.StringVar(&tmpl, "t", "", "")
+	flags.IntVar(&perPage, "per-page", 0, "")
+	flags.StringVar(&pageToken, "page-token", "", "")
 
 	if err := flags.Parse(args); err != nil {
 		return 1
@@ -81,7 +92,11 @@ func (c *ACLTokenListCommand) Run(args []string) int {
 	}
 
 	// Fetch info on the policy
-	tokens, _, err := client.ACLTokens().List(nil)
+	opts := &api.QueryOptions{
+		PerPage:   int32(perPage),
+		NextToken: pageToken,
+	}
+	tokens, qm, err := client.ACLTokens().List(opts)
 	if err != nil {
 		c.Ui.Error(fmt.Sprintf("Error listing ACL tokens: %s", err))
 		return 1
@@ -99,6 +114,10 @@ func (c *ACLTokenListCommand) Run(args []string) int {
 	}
 
 	c.Ui.Output(formatTokens(tokens))
+
+	if qm.NextToken != "" {
+		c.Ui.Output(paginationHint(qm.NextToken))
+	}
 	return 0
 }
 
@@ -397,12 +397,14 @@ func TestHTTP_ACLTokenCreate(t *testing.T) {
 			t.Fatalf("missing index")
 		}
 
-		// Check token was created
+		// Check token was created. The state store only holds the
+		// encrypted secret, so look the token up by its secret.
 		state := s.Agent.server.State()
-		out, err := state.ACLTokenByAccessorID(nil, outTK.AccessorID)
+		out, err := state.ACLTokenBySecretID(nil, outTK.SecretID)
 		assert.Nil(t, err)
 		assert.NotNil(t, out)
-		assert.Equal(t, outTK, out)
+		assert.Equal(t, outTK.AccessorID, out.AccessorID)
+		assert.Equal(t, outTK.Hash, out.Hash)
 	})
 }
 
@@ -26,6 +26,7 @@ import (
 	"github.com/hashicorp/nomad/client/state"
 	"github.com/hashicorp/nomad/command/agent/consul"
 	"github.com/hashicorp/nomad/command/agent/event"
+	"github.com/hashicorp/nomad/helper"
 	"github.com/hashicorp/nomad/helper/pluginutils/loader"
 	"github.com/hashicorp/nomad/helper/uuid"
 	"github.com/hashicorp/nomad/nomad"
@@ -602,6 +603,20 @@ func convertClientConfig(agentConfig *Config) (*clientconfig.Config, error) {
 	}
 	conf.TemplateConfig.DisableSandbox = agentConfig.Client.TemplateConfig.DisableSandbox
 
+	if artifact := agentConfig.Client.Artifact; artifact != nil {
+		conf.Artifact.DisableSandbox = artifact.DisableSandbox
+		conf.Artifact.DisableFilesystemIsolation = artifact.DisableFilesystemIsolation
+		conf.Artifact.RequireFilesystemIsolation = artifact.RequireFilesystemIsolation
+		conf.Artifact.EnvVars = artifact.SetEnvironmentVariables
+		if artifact.User != "" {
+			conf.Artifact.User = artifact.User
+		}
+		if artifact.Timeout != 0 {
+			conf.Artifact.Timeout = artifact.Timeout
+		}
+		conf.Artifact.MaxSize = int64(artifact.MaxSizeMB) * 1024 * 1024
+	}
+
 	hvMap := make(map[string]*structs.ClientHostVolumeConfig, len(agentConfig.Client.HostVolumes))
 	for _, v := range agentConfig.Client.HostVolumes {
 		hvMap[v.Name] = v
@@ -614,6 +629,7 @@ func convertClientConfig(agentConfig *Config) (*clientconfig.Config, error) {
 	conf.Node.Name = agentConfig.NodeName
 	conf.Node.Meta = agentConfig.Client.Meta
 	conf.Node.NodeClass = agentConfig.Client.NodeClass
+	conf.Node.NodePool = agentConfig.Client.NodePool
 
 	// Set up the HTTP advertise address
 	conf.Node.HTTPAddr = agentConfig.AdvertiseAddrs.HTTP
@@ -673,6 +689,7 @@ func convertClientConfig(agentConfig *Config) (*clientconfig.Config, error) {
 	conf.GCDiskUsageThreshold = agentConfig.Client.GCDiskUsageThreshold
 	conf.GCInodeUsageThreshold = agentConfig.Client.GCInodeUsageThreshold
 	conf.GCMaxAllocs = agentConfig.Client.GCMaxAllocs
+	conf.ArtifactCacheMaxSizeMB = agentConfig.Client.ArtifactCacheMaxSizeMB
 	if agentConfig.Client.NoHostUUID != nil {
 		conf.NoHostUUID = *agentConfig.Client.NoHostUUID
 	} else {
@@ -696,6 +713,11 @@ func convertClientConfig(agentConfig *Config) (*clientconfig.Config, error) {
 	}
 	conf.BindWildcardDefaultHostNetwork = agentConfig.Client.BindWildcardDefaultHostNetwork
 
+	for _, ls := range agentConfig.Client.LogSinks {
+		conf.LogSinks = append(conf.LogSinks, ls.LogSink.Copy())
+	}
+	conf.LogSinkAllowedAddresses = helper.CopySliceString(agentConfig.Client.LogSinkAllowedAddresses)
+
 	conf.CgroupParent = agentConfig.Client.CgroupParent
 	if agentConfig.Client.ReserveableCores != "" {
 		cores, err := cpuset.Parse(agentConfig.Client.ReserveableCores)
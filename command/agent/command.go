@@ -321,6 +321,18 @@ func (c *Command) isValidConfig(config, cmdConfig *Config) bool {
 		return false
 	}
 
+	// Check that the client node pool is valid
+	if config.Client.Enabled && config.Client.NodePool != "" {
+		if err := structs.ValidateNodePoolName(config.Client.NodePool); err != nil {
+			c.Ui.Error(fmt.Sprintf("Invalid node pool: %v", err))
+			return false
+		}
+		if config.Client.NodePool == structs.NodePoolAll {
+			c.Ui.Error(fmt.Sprintf("Node pool %q is reserved and cannot be used by clients", structs.NodePoolAll))
+			return false
+		}
+	}
+
 	// Set up the TLS configuration properly if we have one.
 	// XXX chelseakomlo: set up a TLSConfig New method which would wrap
 	// constructor-type actions like this.
@@ -373,6 +385,13 @@ func (c *Command) isValidConfig(config, cmdConfig *Config) bool {
 		return false
 	}
 
+	for _, sink := range config.Client.LogSinks {
+		if err := sink.Validate(); err != nil {
+			c.Ui.Error(fmt.Sprintf("Invalid client log_sink %q: %v", sink.Name, err))
+			return false
+		}
+	}
+
 	if config.Client.MinDynamicPort < 0 || config.Client.MinDynamicPort > structs.MaxValidPort {
 		c.Ui.Error(fmt.Sprintf("Invalid dynamic port range: min_dynamic_port=%d", config.Client.MinDynamicPort))
 		return false
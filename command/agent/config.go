@@ -193,6 +193,9 @@ type ClientConfig struct {
 	// NodeClass is used to group the node by class
 	NodeClass string `hcl:"node_class"`
 
+	// NodePool is the node pool the node belongs to
+	NodePool string `hcl:"node_pool"`
+
 	// Options is used for configuration of nomad internals,
 	// like fingerprinters and drivers. The format is:
 	//
@@ -267,6 +270,10 @@ type ClientConfig struct {
 	// before garbage collection is triggered.
 	GCMaxAllocs int `hcl:"gc_max_allocs"`
 
+	// ArtifactCacheMaxSizeMB is the maximum size of the node-local cache of
+	// downloaded artifacts. Zero disables the cache.
+	ArtifactCacheMaxSizeMB int `hcl:"artifact_cache_max_size_mb"`
+
 	// NoHostUUID disables using the host's UUID and will force generation of a
 	// random UUID.
 	NoHostUUID *bool `hcl:"no_host_uuid"`
@@ -277,6 +284,9 @@ type ClientConfig struct {
 	// TemplateConfig includes configuration for template rendering
 	TemplateConfig *ClientTemplateConfig `hcl:"template"`
 
+	// Artifact includes configuration for downloading artifacts
+	Artifact *ClientArtifactConfig `hcl:"artifact"`
+
 	// ServerJoin contains information that is used to attempt to join servers
 	ServerJoin *ServerJoin `hcl:"server_join"`
 
@@ -310,6 +320,14 @@ type ClientConfig struct {
 	// matching any destination address (true). Defaults to true
 	BindWildcardDefaultHostNetwork bool `hcl:"bind_wildcard_default_host_network"`
 
+	// LogSinks are additional destinations the output of every task on the
+	// client is shipped to, on top of the sinks configured by the task.
+	LogSinks []*structs.ClientLogSinkConfig `hcl:"log_sink"`
+
+	// LogSinkAllowedAddresses are the addresses the syslog, tcp and http log
+	// sinks of tasks may ship to, in addition to those of LogSinks.
+	LogSinkAllowedAddresses []string `hcl:"log_sink_allowed_addresses"`
+
 	// CgroupParent sets the parent cgroup for subsystems managed by Nomad. If the cgroup
 	// doest not exist Nomad will attempt to create it during startup. Defaults to '/nomad'
 	CgroupParent string `hcl:"cgroup_parent"`
@@ -336,6 +354,82 @@ type ClientTemplateConfig struct {
 	DisableSandbox bool `hcl:"disable_file_sandbox"`
 }
 
+// ClientArtifactConfig is configuration on the client specific to
+// downloading artifacts
+type ClientArtifactConfig struct {
+	// DisableSandbox downloads artifacts within the client process instead
+	// of a separate sandboxed process.
+	DisableSandbox bool `hcl:"disable_sandbox"`
+
+	// DisableFilesystemIsolation allows the sandboxed process to write
+	// outside of the directory it downloads into.
+	DisableFilesystemIsolation bool `hcl:"disable_filesystem_isolation"`
+
+	// RequireFilesystemIsolation fails downloads where the sandboxed process
+	// can't be restricted, instead of running it without isolation.
+	RequireFilesystemIsolation bool `hcl:"require_filesystem_isolation"`
+
+	// SetEnvironmentVariables are the names of the environment variables of
+	// the client passed on to the sandboxed process, in addition to the
+	// PATH, HOME, TMPDIR and proxy variables.
+	SetEnvironmentVariables []string `hcl:"set_environment_variables"`
+
+	// User is the user the sandboxed process runs as when the client runs
+	// as root. Defaults to "nobody".
+	User string `hcl:"user"`
+
+	// Timeout is the maximum duration of a sandboxed download. Defaults to
+	// "30m".
+	Timeout    time.Duration `hcl:"-"`
+	TimeoutHCL string        `hcl:"timeout" json:"-"`
+
+	// MaxSizeMB is the maximum size of a sandboxed download. Zero allows
+	// artifacts of any size.
+	MaxSizeMB int `hcl:"max_size_mb"`
+
+	// ExtraKeysHCL is used by hcl to surface unexpected keys
+	ExtraKeysHCL []string `hcl:",unusedKeys" json:"-"`
+}
+
+func (a *ClientArtifactConfig) Merge(b *ClientArtifactConfig) *ClientArtifactConfig {
+	if a == nil {
+		return b
+	}
+
+	result := *a
+
+	if b == nil {
+		return &result
+	}
+
+	if b.DisableSandbox {
+		result.DisableSandbox = b.DisableSandbox
+	}
+	if b.DisableFilesystemIsolation {
+		result.DisableFilesystemIsolation = b.DisableFilesystemIsolation
+	}
+	if b.RequireFilesystemIsolation {
+		result.RequireFilesystemIsolation = b.RequireFilesystemIsolation
+	}
+	if b.SetEnvironmentVariables != nil {
+		result.SetEnvironmentVariables = b.SetEnvironmentVariables
+	}
+	if b.User != "" {
+		result.User = b.User
+	}
+	if b.Timeout != 0 {
+		result.Timeout = b.Timeout
+	}
+	if b.TimeoutHCL != "" {
+		result.TimeoutHCL = b.TimeoutHCL
+	}
+	if b.MaxSizeMB != 0 {
+		result.MaxSizeMB = b.MaxSizeMB
+	}
+
+	return &result
+}
+
 // ACLConfig is configuration specific to the ACL system
 type ACLConfig struct {
 	// Enabled controls if we are enforce and manage ACLs
@@ -1599,6 +1693,9 @@ func (a *ClientConfig) Merge(b *ClientConfig) *ClientConfig {
 	if b.NodeClass != "" {
 		result.NodeClass = b.NodeClass
 	}
+	if b.NodePool != "" {
+		result.NodePool = b.NodePool
+	}
 	if b.NetworkInterface != "" {
 		result.NetworkInterface = b.NetworkInterface
 	}
@@ -1650,6 +1747,9 @@ func (a *ClientConfig) Merge(b *ClientConfig) *ClientConfig {
 	if b.GCMaxAllocs != 0 {
 		result.GCMaxAllocs = b.GCMaxAllocs
 	}
+	if b.ArtifactCacheMaxSizeMB != 0 {
+		result.ArtifactCacheMaxSizeMB = b.ArtifactCacheMaxSizeMB
+	}
 	// NoHostUUID defaults to true, merge if false
 	if b.NoHostUUID != nil {
 		result.NoHostUUID = b.NoHostUUID
@@ -1663,6 +1763,10 @@ func (a *ClientConfig) Merge(b *ClientConfig) *ClientConfig {
 		result.TemplateConfig = b.TemplateConfig
 	}
 
+	if b.Artifact != nil {
+		result.Artifact = result.Artifact.Merge(b.Artifact)
+	}
+
 	// Add the servers
 	result.Servers = append(result.Servers, b.Servers...)
 
@@ -1722,6 +1826,14 @@ func (a *ClientConfig) Merge(b *ClientConfig) *ClientConfig {
 	if b.BindWildcardDefaultHostNetwork {
 		result.BindWildcardDefaultHostNetwork = true
 	}
+
+	result.LogSinks = a.LogSinks
+	if len(b.LogSinks) != 0 {
+		result.LogSinks = append(result.LogSinks, b.LogSinks...)
+	}
+	if len(b.LogSinkAllowedAddresses) != 0 {
+		result.LogSinkAllowedAddresses = append(result.LogSinkAllowedAddresses, b.LogSinkAllowedAddresses...)
+	}
 	return &result
 }
 
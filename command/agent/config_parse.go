@@ -64,6 +64,12 @@ func ParseConfigFile(path string) (*Config, error) {
 		{"telemetry.collection_interval", &c.Telemetry.collectionInterval, &c.Telemetry.CollectionInterval},
 	}
 
+	if c.Client.Artifact != nil {
+		tds = append(tds, td{
+			"client.artifact.timeout", &c.Client.Artifact.Timeout, &c.Client.Artifact.TimeoutHCL,
+		})
+	}
+
 	// Add enterprise audit sinks for time.Duration parsing
 	for i, sink := range c.Audit.Sinks {
 		tds = append(tds, td{
@@ -145,6 +151,12 @@ func extraKeys(c *Config) error {
 		helper.RemoveEqualFold(&c.Client.ExtraKeysHCL, "host_network")
 	}
 
+	// Remove LogSink extra keys
+	for _, ls := range c.Client.LogSinks {
+		helper.RemoveEqualFold(&c.Client.ExtraKeysHCL, ls.Name)
+		helper.RemoveEqualFold(&c.Client.ExtraKeysHCL, "log_sink")
+	}
+
 	// Remove AuditConfig extra keys
 	for _, f := range c.Audit.Filters {
 		helper.RemoveEqualFold(&c.Audit.ExtraKeysHCL, f.Name)
@@ -44,6 +44,7 @@ var basicConfig = &Config{
 		AllocDir:  "/tmp/alloc",
 		Servers:   []string{"a.b.c:80", "127.0.0.1:1234"},
 		NodeClass: "linux-medium-64bit",
+		NodePool:  "dev",
 		ServerJoin: &ServerJoin{
 			RetryJoin:        []string{"1.1.1.1", "2.2.2.2"},
 			RetryInterval:    time.Duration(15) * time.Second,
@@ -75,20 +76,35 @@ var basicConfig = &Config{
 			DiskMB:        10,
 			ReservedPorts: "1,100,10-12",
 		},
-		GCInterval:            6 * time.Second,
-		GCIntervalHCL:         "6s",
-		GCParallelDestroys:    6,
-		GCDiskUsageThreshold:  82,
-		GCInodeUsageThreshold: 91,
-		GCMaxAllocs:           50,
-		NoHostUUID:            helper.BoolToPtr(false),
-		DisableRemoteExec:     true,
+		GCInterval:             6 * time.Second,
+		GCIntervalHCL:          "6s",
+		GCParallelDestroys:     6,
+		GCDiskUsageThreshold:   82,
+		GCInodeUsageThreshold:  91,
+		GCMaxAllocs:            50,
+		ArtifactCacheMaxSizeMB: 512,
+		NoHostUUID:             helper.BoolToPtr(false),
+		DisableRemoteExec:      true,
+		Artifact: &ClientArtifactConfig{
+			User:                    "artifacts",
+			Timeout:                 10 * time.Minute,
+			TimeoutHCL:              "10m",
+			MaxSizeMB:               1024,
+			SetEnvironmentVariables: []string{"AWS_PROFILE"},
+		},
 		HostVolumes: []*structs.ClientHostVolumeConfig{
 			{Name: "tmp", Path: "/tmp"},
 		},
-		CNIPath:             "/tmp/cni_path",
-		BridgeNetworkName:   "custom_bridge_name",
-		BridgeNetworkSubnet: "custom_bridge_subnet",
+		LogSinks: []*structs.ClientLogSinkConfig{
+			{
+				Name:    "syslog",
+				LogSink: structs.LogSink{Type: "syslog", Address: "unixgram:///dev/log"},
+			},
+		},
+		LogSinkAllowedAddresses: []string{"logs.example.com:5000"},
+		CNIPath:                 "/tmp/cni_path",
+		BridgeNetworkName:       "custom_bridge_name",
+		BridgeNetworkSubnet:     "custom_bridge_subnet",
 	},
 	Server: &ServerConfig{
 		Enabled:                   true,
@@ -281,6 +281,7 @@ func TestConfig_Merge(t *testing.T) {
 			StateDir:  "/tmp/state2",
 			AllocDir:  "/tmp/alloc2",
 			NodeClass: "class2",
+			NodePool:  "pool2",
 			Servers:   []string{"server2"},
 			Meta: map[string]string{
 				"baz": "zip",
@@ -1205,6 +1206,34 @@ func TestMergeServerJoin(t *testing.T) {
 	}
 }
 
+func TestMergeClientArtifactConfig(t *testing.T) {
+	require := require.New(t)
+
+	var nilConfig *ClientArtifactConfig
+	require.Nil(nilConfig.Merge(nil))
+
+	a := &ClientArtifactConfig{
+		User:      "artifacts",
+		Timeout:   10 * time.Minute,
+		MaxSizeMB: 100,
+	}
+	b := &ClientArtifactConfig{
+		DisableFilesystemIsolation: true,
+		SetEnvironmentVariables:    []string{"AWS_PROFILE"},
+		Timeout:                    5 * time.Minute,
+	}
+
+	require.Equal(a, nilConfig.Merge(a))
+	require.Equal(a, a.Merge(nil))
+	require.Equal(&ClientArtifactConfig{
+		DisableFilesystemIsolation: true,
+		SetEnvironmentVariables:    []string{"AWS_PROFILE"},
+		User:                       "artifacts",
+		Timeout:                    5 * time.Minute,
+		MaxSizeMB:                  100,
+	}, a.Merge(b))
+}
+
 func TestTelemetry_PrefixFilters(t *testing.T) {
 	t.Parallel()
 	cases := []struct {
@@ -1051,7 +1051,7 @@ func (c *ServiceClient) serviceRegs(ops *operations, service *structs.Service, w
 	}
 
 	// Determine the address to advertise based on the mode
-	ip, port, err := getAddress(addrMode, service.PortLabel, workload.Networks, workload.DriverNetwork, workload.Ports, workload.NetworkStatus)
+	ip, port, err := GetAddress(addrMode, service.PortLabel, workload.Networks, workload.DriverNetwork, workload.Ports, workload.NetworkStatus)
 	if err != nil {
 		return nil, fmt.Errorf("unable to get address for service %q: %v", service.Name, err)
 	}
@@ -1181,7 +1181,7 @@ func (c *ServiceClient) checkRegs(serviceID string, service *structs.Service,
 			}
 
 			var err error
-			ip, port, err = getAddress(addrMode, portLabel, workload.Networks, workload.DriverNetwork, workload.Ports, workload.NetworkStatus)
+			ip, port, err = GetAddress(addrMode, portLabel, workload.Networks, workload.DriverNetwork, workload.Ports, workload.NetworkStatus)
 			if err != nil {
 				return nil, fmt.Errorf("error getting address for check %q: %v", check.Name, err)
 			}
@@ -1769,10 +1769,10 @@ func getNomadSidecar(id string, services map[string]*api.AgentService) *api.Agen
 	return services[sidecarID]
 }
 
-// getAddress returns the IP and port to use for a service or check. If no port
+// GetAddress returns the IP and port to use for a service or check. If no port
 // label is specified (an empty value), zero values are returned because no
 // address could be resolved.
-func getAddress(addrMode, portLabel string, networks structs.Networks, driverNet *drivers.DriverNetwork, ports structs.AllocatedPorts, netStatus *structs.AllocNetworkStatus) (string, int, error) {
+func GetAddress(addrMode, portLabel string, networks structs.Networks, driverNet *drivers.DriverNetwork, ports structs.AllocatedPorts, netStatus *structs.AllocNetworkStatus) (string, int, error) {
 	switch addrMode {
 	case structs.AddressModeAuto:
 		if driverNet.Advertise() {
@@ -1780,7 +1780,7 @@ func getAddress(addrMode, portLabel string, networks structs.Networks, driverNet
 		} else {
 			addrMode = structs.AddressModeHost
 		}
-		return getAddress(addrMode, portLabel, networks, driverNet, ports, netStatus)
+		return GetAddress(addrMode, portLabel, networks, driverNet, ports, netStatus)
 	case structs.AddressModeHost:
 		if portLabel == "" {
 			if len(networks) != 1 {
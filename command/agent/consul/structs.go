@@ -13,6 +13,11 @@ import (
 type WorkloadServices struct {
 	AllocID string
 
+	// JobID and Namespace identify the job which the allocation belongs to.
+	// They are required when registering services with the Nomad provider.
+	JobID     string
+	Namespace string
+
 	// Name of the task and task group the services are defined for. For
 	// group based services, Task will be empty.
 	Task  string
@@ -1686,7 +1686,7 @@ func TestGetAddress(t *testing.T) {
 			}
 
 			// Run getAddress
-			ip, port, err := getAddress(tc.Mode, tc.PortLabel, networks, tc.Driver, tc.Ports, tc.Status)
+			ip, port, err := GetAddress(tc.Mode, tc.PortLabel, networks, tc.Driver, tc.Ports, tc.Status)
 
 			// Assert the results
 			assert.Equal(t, tc.ExpectedIP, ip, "IP mismatch")
@@ -150,6 +150,7 @@ func (s *HTTPServer) jobPlan(resp http.ResponseWriter, req *http.Request,
 		Job:            sJob,
 		Diff:           args.Diff,
 		PolicyOverride: args.PolicyOverride,
+		Explain:        args.Explain,
 		WriteRequest:   *writeReq,
 	}
 
@@ -818,6 +819,16 @@ func ApiJobToStructJob(job *api.Job) *structs.Job {
 		Affinities:     ApiAffinitiesToStructs(job.Affinities),
 	}
 
+	if job.NodePool != nil {
+		j.NodePool = *job.NodePool
+	}
+
+	if job.Reservation != nil {
+		j.Reservation = *job.Reservation
+	}
+
+	j.AllocAffinities = ApiAllocAffinitiesToStructs(job.AllocAffinities)
+
 	// Update has been pushed into the task groups. stagger and max_parallel are
 	// preserved at the job level, but all other values are discarded. The job.Update
 	// api value is merged into TaskGroups already in api.Canonicalize
@@ -839,6 +850,19 @@ func ApiJobToStructJob(job *api.Job) *structs.Job {
 		}
 	}
 
+	if len(job.DependsOn) > 0 {
+		j.DependsOn = make([]*structs.JobDependency, len(job.DependsOn))
+		for i, d := range job.DependsOn {
+			j.DependsOn[i] = &structs.JobDependency{
+				JobID:     *d.JobID,
+				Condition: *d.Condition,
+			}
+			if d.Child != nil {
+				j.DependsOn[i].Child = *d.Child
+			}
+		}
+	}
+
 	if job.Periodic != nil {
 		j.Periodic = &structs.PeriodicConfig{
 			Enabled:         *job.Periodic.Enabled,
@@ -895,6 +919,7 @@ func ApiTgToStructsTG(job *structs.Job, taskGroup *api.TaskGroup, tg *structs.Ta
 	tg.Meta = taskGroup.Meta
 	tg.Constraints = ApiConstraintsToStructs(taskGroup.Constraints)
 	tg.Affinities = ApiAffinitiesToStructs(taskGroup.Affinities)
+	tg.AllocAffinities = ApiAllocAffinitiesToStructs(taskGroup.AllocAffinities)
 	tg.Networks = ApiNetworkResourceToStructs(taskGroup.Networks)
 	tg.Services = ApiServicesToStructs(taskGroup.Services, true)
 	tg.Consul = apiConsulToStructs(taskGroup.Consul)
@@ -914,6 +939,18 @@ func ApiTgToStructsTG(job *structs.Job, taskGroup *api.TaskGroup, tg *structs.Ta
 		tg.StopAfterClientDisconnect = taskGroup.StopAfterClientDisconnect
 	}
 
+	if taskGroup.MaxClientDisconnect != nil {
+		tg.MaxClientDisconnect = taskGroup.MaxClientDisconnect
+	}
+
+	if taskGroup.Placement != nil {
+		tg.Placement = *taskGroup.Placement
+	}
+
+	if taskGroup.MaxRunDuration != nil {
+		tg.MaxRunDuration = taskGroup.MaxRunDuration
+	}
+
 	if taskGroup.ReschedulePolicy != nil {
 		tg.ReschedulePolicy = &structs.ReschedulePolicy{
 			Attempts:      *taskGroup.ReschedulePolicy.Attempts,
@@ -1076,8 +1113,11 @@ func ApiTaskToStructsTask(job *structs.Job, group *structs.TaskGroup,
 	structsTask.Resources = ApiResourcesToStructs(apiTask.Resources)
 
 	structsTask.LogConfig = &structs.LogConfig{
-		MaxFiles:      *apiTask.LogConfig.MaxFiles,
-		MaxFileSizeMB: *apiTask.LogConfig.MaxFileSizeMB,
+		MaxFiles:        *apiTask.LogConfig.MaxFiles,
+		MaxFileSizeMB:   *apiTask.LogConfig.MaxFileSizeMB,
+		Sinks:           apiLogSinksToStructs(apiTask.LogConfig.Sinks),
+		RateLimit:       apiLogRateLimitToStructs(apiTask.LogConfig.RateLimit),
+		CompressRotated: dereferenceBool(apiTask.LogConfig.CompressRotated),
 	}
 
 	if len(apiTask.Artifacts) > 0 {
@@ -1114,6 +1154,7 @@ func ApiTaskToStructsTask(job *structs.Job, group *structs.TaskGroup,
 					EmbeddedTmpl: *template.EmbeddedTmpl,
 					ChangeMode:   *template.ChangeMode,
 					ChangeSignal: *template.ChangeSignal,
+					ChangeScript: apiChangeScriptToStructs(template.ChangeScript),
 					Splay:        *template.Splay,
 					Perms:        *template.Perms,
 					LeftDelim:    *template.LeftDelim,
@@ -1130,6 +1171,13 @@ func ApiTaskToStructsTask(job *structs.Job, group *structs.TaskGroup,
 		}
 	}
 
+	if apiTask.Identity != nil {
+		structsTask.Identity = &structs.WorkloadIdentity{
+			Env:  apiTask.Identity.Env,
+			File: apiTask.Identity.File,
+		}
+	}
+
 	if apiTask.Lifecycle != nil {
 		structsTask.Lifecycle = &structs.TaskLifecycleConfig{
 			Hook:    apiTask.Lifecycle.Hook,
@@ -1168,6 +1216,12 @@ func ApiResourcesToStructs(in *api.Resources) *structs.Resources {
 		out.MemoryMaxMB = *in.MemoryMaxMB
 	}
 
+	if in.NUMA != nil {
+		out.NUMA = &structs.NUMA{
+			Affinity: in.NUMA.Affinity,
+		}
+	}
+
 	// COMPAT(0.10): Only being used to issue warnings
 	if in.IOPS != nil {
 		out.IOPS = *in.IOPS
@@ -1260,6 +1314,7 @@ func ApiServicesToStructs(in []*api.Service, group bool) []*structs.Service {
 			Meta:              helper.CopyMapStringString(s.Meta),
 			CanaryMeta:        helper.CopyMapStringString(s.CanaryMeta),
 			OnUpdate:          s.OnUpdate,
+			Provider:          s.Provider,
 		}
 
 		if l := len(s.Checks); l != 0 {
@@ -1587,9 +1642,57 @@ func apiLogConfigToStructs(in *api.LogConfig) *structs.LogConfig {
 		return nil
 	}
 	return &structs.LogConfig{
-		MaxFiles:      dereferenceInt(in.MaxFiles),
-		MaxFileSizeMB: dereferenceInt(in.MaxFileSizeMB),
+		MaxFiles:        dereferenceInt(in.MaxFiles),
+		MaxFileSizeMB:   dereferenceInt(in.MaxFileSizeMB),
+		Sinks:           apiLogSinksToStructs(in.Sinks),
+		RateLimit:       apiLogRateLimitToStructs(in.RateLimit),
+		CompressRotated: dereferenceBool(in.CompressRotated),
+	}
+}
+
+func apiLogRateLimitToStructs(in *api.LogRateLimit) *structs.LogRateLimit {
+	if in == nil {
+		return nil
+	}
+	return &structs.LogRateLimit{
+		LinesPerSecond: in.LinesPerSecond,
+		BytesPerSecond: in.BytesPerSecond,
+	}
+}
+
+func apiLogSinksToStructs(in []*api.LogSink) []*structs.LogSink {
+	if len(in) == 0 {
+		return nil
+	}
+
+	out := make([]*structs.LogSink, len(in))
+	for i, sink := range in {
+		out[i] = &structs.LogSink{
+			Type:       sink.Type,
+			Address:    sink.Address,
+			Path:       sink.Path,
+			BufferSize: sink.BufferSize,
+		}
+	}
+	return out
+}
+
+func apiChangeScriptToStructs(in *api.ChangeScript) *structs.ChangeScript {
+	if in == nil {
+		return nil
+	}
+
+	out := &structs.ChangeScript{
+		Args:        in.Args,
+		FailOnError: dereferenceBool(in.FailOnError),
+	}
+	if in.Command != nil {
+		out.Command = *in.Command
 	}
+	if in.Timeout != nil {
+		out.Timeout = *in.Timeout
+	}
+	return out
 }
 
 func dereferenceInt(in *int) int {
@@ -1599,6 +1702,13 @@ func dereferenceInt(in *int) int {
 	return *in
 }
 
+func dereferenceBool(in *bool) bool {
+	if in == nil {
+		return false
+	}
+	return *in
+}
+
 func ApiConstraintsToStructs(in []*api.Constraint) []*structs.Constraint {
 	if in == nil {
 		return nil
@@ -1646,10 +1756,30 @@ func ApiAffinityToStructs(a1 *api.Affinity) *structs.Affinity {
 	}
 }
 
+func ApiAllocAffinitiesToStructs(in []*api.AllocAffinity) []*structs.AllocAffinity {
+	if in == nil {
+		return nil
+	}
+
+	out := make([]*structs.AllocAffinity, len(in))
+	for i, a := range in {
+		out[i] = &structs.AllocAffinity{
+			Job:       a.Job,
+			TaskGroup: a.TaskGroup,
+			Weight:    *a.Weight,
+			Hard:      a.Hard,
+		}
+	}
+
+	return out
+}
+
 func ApiSpreadToStructs(a1 *api.Spread) *structs.Spread {
 	ret := &structs.Spread{}
 	ret.Attribute = a1.Attribute
 	ret.Weight = *a1.Weight
+	ret.MaxSkew = a1.MaxSkew
+	ret.Hard = a1.Hard
 	if a1.SpreadTarget != nil {
 		ret.SpreadTarget = make([]*structs.SpreadTarget, len(a1.SpreadTarget))
 		for i, st := range a1.SpreadTarget {
@@ -1928,6 +1928,7 @@ func TestJobs_ApiJobToStructsJob(t *testing.T) {
 		Priority:    helper.IntToPtr(50),
 		AllAtOnce:   helper.BoolToPtr(true),
 		Datacenters: []string{"dc1", "dc2"},
+		NodePool:    helper.StringToPtr("dev"),
 		Constraints: []*api.Constraint{
 			{
 				LTarget: "a",
@@ -1965,6 +1966,17 @@ func TestJobs_ApiJobToStructsJob(t *testing.T) {
 				},
 			},
 		},
+		DependsOn: []*api.JobDependency{
+			{
+				JobID:     helper.StringToPtr("upstream"),
+				Condition: helper.StringToPtr("failed"),
+			},
+			{
+				JobID:     helper.StringToPtr("dispatch"),
+				Child:     helper.StringToPtr("dispatch/dispatch-1234"),
+				Condition: helper.StringToPtr("complete"),
+			},
+		},
 		Periodic: &api.PeriodicConfig{
 			Enabled:         helper.BoolToPtr(true),
 			Spec:            helper.StringToPtr("spec"),
@@ -2286,6 +2298,12 @@ func TestJobs_ApiJobToStructsJob(t *testing.T) {
 								EmbeddedTmpl: helper.StringToPtr("embedded"),
 								ChangeMode:   helper.StringToPtr("change"),
 								ChangeSignal: helper.StringToPtr("signal"),
+								ChangeScript: &api.ChangeScript{
+									Command:     helper.StringToPtr("/bin/reload"),
+									Args:        []string{"-c", "dest"},
+									Timeout:     helper.TimeToPtr(5 * time.Second),
+									FailOnError: helper.BoolToPtr(true),
+								},
 								Splay:        helper.TimeToPtr(1 * time.Minute),
 								Perms:        helper.StringToPtr("666"),
 								LeftDelim:    helper.StringToPtr("abc"),
@@ -2322,6 +2340,7 @@ func TestJobs_ApiJobToStructsJob(t *testing.T) {
 		Priority:       50,
 		AllAtOnce:      true,
 		Datacenters:    []string{"dc1", "dc2"},
+		NodePool:       "dev",
 		Constraints: []*structs.Constraint{
 			{
 				LTarget: "a",
@@ -2349,6 +2368,17 @@ func TestJobs_ApiJobToStructsJob(t *testing.T) {
 				},
 			},
 		},
+		DependsOn: []*structs.JobDependency{
+			{
+				JobID:     "upstream",
+				Condition: structs.JobDependencyConditionFailed,
+			},
+			{
+				JobID:     "dispatch",
+				Child:     "dispatch/dispatch-1234",
+				Condition: structs.JobDependencyConditionComplete,
+			},
+		},
 		Update: structs.UpdateStrategy{
 			Stagger:     1 * time.Second,
 			MaxParallel: 5,
@@ -2464,6 +2494,7 @@ func TestJobs_ApiJobToStructsJob(t *testing.T) {
 						EnableTagOverride: true,
 						PortLabel:         "1234",
 						AddressMode:       "auto",
+						Provider:          "consul",
 						Meta: map[string]string{
 							"servicemeta": "foobar",
 						},
@@ -2555,6 +2586,7 @@ func TestJobs_ApiJobToStructsJob(t *testing.T) {
 								EnableTagOverride: true,
 								PortLabel:         "foo",
 								AddressMode:       "auto",
+								Provider:          "consul",
 								Meta: map[string]string{
 									"servicemeta": "foobar",
 								},
@@ -2679,6 +2711,12 @@ func TestJobs_ApiJobToStructsJob(t *testing.T) {
 								EmbeddedTmpl: "embedded",
 								ChangeMode:   "change",
 								ChangeSignal: "SIGNAL",
+								ChangeScript: &structs.ChangeScript{
+									Command:     "/bin/reload",
+									Args:        []string{"-c", "dest"},
+									Timeout:     5 * time.Second,
+									FailOnError: true,
+								},
 								Splay:        1 * time.Minute,
 								Perms:        "666",
 								LeftDelim:    "abc",
@@ -3094,6 +3132,32 @@ func TestConversion_apiLogConfigToStructs(t *testing.T) {
 		MaxFiles:      helper.IntToPtr(2),
 		MaxFileSizeMB: helper.IntToPtr(8),
 	}))
+	require.Equal(t, &structs.LogConfig{
+		MaxFiles:      2,
+		MaxFileSizeMB: 8,
+		Sinks: []*structs.LogSink{
+			{Type: "tcp", Address: "127.0.0.1:5000", BufferSize: 64},
+			{Type: "file", Path: "web.json"},
+		},
+	}, apiLogConfigToStructs(&api.LogConfig{
+		MaxFiles:      helper.IntToPtr(2),
+		MaxFileSizeMB: helper.IntToPtr(8),
+		Sinks: []*api.LogSink{
+			{Type: "tcp", Address: "127.0.0.1:5000", BufferSize: 64},
+			{Type: "file", Path: "web.json"},
+		},
+	}))
+	require.Equal(t, &structs.LogConfig{
+		MaxFiles:        2,
+		MaxFileSizeMB:   8,
+		RateLimit:       &structs.LogRateLimit{LinesPerSecond: 100, BytesPerSecond: 4096},
+		CompressRotated: true,
+	}, apiLogConfigToStructs(&api.LogConfig{
+		MaxFiles:        helper.IntToPtr(2),
+		MaxFileSizeMB:   helper.IntToPtr(8),
+		RateLimit:       &api.LogRateLimit{LinesPerSecond: 100, BytesPerSecond: 4096},
+		CompressRotated: helper.BoolToPtr(true),
+	}))
 }
 
 func TestConversion_apiResourcesToStructs(t *testing.T) {
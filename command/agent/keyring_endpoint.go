@@ -0,0 +1,123 @@
+package agent
+
+import (
+	"crypto/ed25519"
+	"net/http"
+	"strings"
+
+	"github.com/hashicorp/nomad/nomad/structs"
+	jose "gopkg.in/square/go-jose.v2"
+)
+
+// KeyringRequest is used to route operator/keyring API requests to the
+// implementing functions.
+func (s *HTTPServer) KeyringRequest(resp http.ResponseWriter, req *http.Request) (interface{}, error) {
+	path := strings.TrimPrefix(req.URL.Path, "/v1/operator/keyring/")
+	switch {
+	case path == "keys":
+		if req.Method != http.MethodGet {
+			return nil, CodedError(http.StatusMethodNotAllowed, ErrInvalidMethod)
+		}
+		return s.keyringListRequest(resp, req)
+	case path == "rotate":
+		if req.Method != http.MethodPut && req.Method != http.MethodPost {
+			return nil, CodedError(http.StatusMethodNotAllowed, ErrInvalidMethod)
+		}
+		return s.keyringRotateRequest(resp, req)
+	case strings.HasPrefix(path, "key/"):
+		keyID := strings.TrimPrefix(path, "key/")
+		if keyID == "" {
+			return nil, CodedError(http.StatusBadRequest, "missing root key ID")
+		}
+		if req.Method != http.MethodDelete {
+			return nil, CodedError(http.StatusMethodNotAllowed, ErrInvalidMethod)
+		}
+		return s.keyringDeleteRequest(resp, req, keyID)
+	default:
+		return nil, CodedError(http.StatusNotFound, ErrInvalidMethod)
+	}
+}
+
+func (s *HTTPServer) keyringListRequest(resp http.ResponseWriter, req *http.Request) (interface{}, error) {
+	args := structs.KeyringListRootKeyMetaRequest{}
+	if s.parse(resp, req, &args.Region, &args.QueryOptions) {
+		return nil, nil
+	}
+
+	var out structs.KeyringListRootKeyMetaResponse
+	if err := s.agent.RPC("Keyring.List", &args, &out); err != nil {
+		return nil, err
+	}
+
+	setMeta(resp, &out.QueryMeta)
+	if out.Keys == nil {
+		out.Keys = make([]*structs.RootKeyMeta, 0)
+	}
+	return out.Keys, nil
+}
+
+func (s *HTTPServer) keyringRotateRequest(resp http.ResponseWriter, req *http.Request) (interface{}, error) {
+	args := structs.KeyringRotateRootKeyRequest{}
+	s.parseWriteRequest(req, &args.WriteRequest)
+
+	query := req.URL.Query()
+	switch query.Get("algo") {
+	case string(structs.EncryptionAlgorithmAES256GCM), "":
+		args.Algorithm = structs.EncryptionAlgorithmAES256GCM
+	default:
+		return nil, CodedError(http.StatusBadRequest, "Invalid key algorithm")
+	}
+
+	if _, ok := query["full"]; ok {
+		args.Full = true
+	}
+
+	var out structs.KeyringRotateRootKeyResponse
+	if err := s.agent.RPC("Keyring.Rotate", &args, &out); err != nil {
+		return nil, err
+	}
+	setIndex(resp, out.Index)
+	return out, nil
+}
+
+func (s *HTTPServer) keyringDeleteRequest(resp http.ResponseWriter, req *http.Request, keyID string) (interface{}, error) {
+	args := structs.KeyringDeleteRootKeyRequest{KeyID: keyID}
+	s.parseWriteRequest(req, &args.WriteRequest)
+
+	var out structs.KeyringDeleteRootKeyResponse
+	if err := s.agent.RPC("Keyring.Delete", &args, &out); err != nil {
+		return nil, err
+	}
+	setIndex(resp, out.Index)
+	return nil, nil
+}
+
+// JWKSRequest is used to handle requests for the JSON Web Key Set of the
+// public keys used to verify workload identities.
+func (s *HTTPServer) JWKSRequest(resp http.ResponseWriter, req *http.Request) (interface{}, error) {
+	if req.Method != http.MethodGet {
+		return nil, CodedError(http.StatusMethodNotAllowed, ErrInvalidMethod)
+	}
+
+	args := structs.GenericRequest{}
+	if s.parse(resp, req, &args.Region, &args.QueryOptions) {
+		return nil, nil
+	}
+
+	var out structs.KeyringListPublicResponse
+	if err := s.agent.RPC("Keyring.ListPublic", &args, &out); err != nil {
+		return nil, err
+	}
+	setMeta(resp, &out.QueryMeta)
+
+	jwks := make([]jose.JSONWebKey, 0, len(out.PublicKeys))
+	for _, pubKey := range out.PublicKeys {
+		jwks = append(jwks, jose.JSONWebKey{
+			Key:       ed25519.PublicKey(pubKey.PublicKey),
+			KeyID:     pubKey.KeyID,
+			Algorithm: pubKey.Algorithm,
+			Use:       pubKey.Use,
+		})
+	}
+	return &jose.JSONWebKeySet{Keys: jwks}, nil
+}
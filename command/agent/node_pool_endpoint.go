@@ -0,0 +1,192 @@
+package agent
+
+import (
+	"net/http"
+	"strings"
+
+	"github.com/hashicorp/nomad/nomad/structs"
+)
+
+func (s *HTTPServer) NodePoolsRequest(resp http.ResponseWriter, req *http.Request) (interface{}, error) {
+	switch req.Method {
+	case "GET":
+		return s.nodePoolList(resp, req)
+	case "PUT", "POST":
+		return s.nodePoolUpsert(resp, req, "")
+	default:
+		return nil, CodedError(405, ErrInvalidMethod)
+	}
+}
+
+func (s *HTTPServer) NodePoolSpecificRequest(resp http.ResponseWriter, req *http.Request) (interface{}, error) {
+	path := strings.TrimPrefix(req.URL.Path, "/v1/node/pool/")
+	switch {
+	case strings.HasSuffix(path, "/nodes"):
+		poolName := strings.TrimSuffix(path, "/nodes")
+		return s.nodePoolNodesList(resp, req, poolName)
+	case strings.HasSuffix(path, "/jobs"):
+		poolName := strings.TrimSuffix(path, "/jobs")
+		return s.nodePoolJobsList(resp, req, poolName)
+	default:
+		return s.nodePoolCRUD(resp, req, path)
+	}
+}
+
+func (s *HTTPServer) nodePoolCRUD(resp http.ResponseWriter, req *http.Request, poolName string) (interface{}, error) {
+	if len(poolName) == 0 {
+		return nil, CodedError(400, "Missing Node Pool Name")
+	}
+	switch req.Method {
+	case "GET":
+		return s.nodePoolQuery(resp, req, poolName)
+	case "PUT", "POST":
+		return s.nodePoolUpsert(resp, req, poolName)
+	case "DELETE":
+		return s.nodePoolDelete(resp, req, poolName)
+	default:
+		return nil, CodedError(405, ErrInvalidMethod)
+	}
+}
+
+func (s *HTTPServer) nodePoolList(resp http.ResponseWriter, req *http.Request) (interface{}, error) {
+	args := structs.NodePoolListRequest{}
+	if s.parse(resp, req, &args.Region, &args.QueryOptions) {
+		return nil, nil
+	}
+
+	var out structs.NodePoolListResponse
+	if err := s.agent.RPC("NodePool.List", &args, &out); err != nil {
+		return nil, err
+	}
+
+	setMeta(resp, &out.QueryMeta)
+	if out.NodePools == nil {
+		out.NodePools = make([]*structs.NodePool, 0)
+	}
+	return out.NodePools, nil
+}
+
+func (s *HTTPServer) nodePoolQuery(resp http.ResponseWriter, req *http.Request,
+	poolName string) (interface{}, error) {
+	args := structs.NodePoolSpecificRequest{
+		Name: poolName,
+	}
+	if s.parse(resp, req, &args.Region, &args.QueryOptions) {
+		return nil, nil
+	}
+
+	var out structs.SingleNodePoolResponse
+	if err := s.agent.RPC("NodePool.GetNodePool", &args, &out); err != nil {
+		return nil, err
+	}
+
+	setMeta(resp, &out.QueryMeta)
+	if out.NodePool == nil {
+		return nil, CodedError(404, "node pool not found")
+	}
+	return out.NodePool, nil
+}
+
+func (s *HTTPServer) nodePoolUpsert(resp http.ResponseWriter, req *http.Request,
+	poolName string) (interface{}, error) {
+	// Parse the node pool
+	var pool structs.NodePool
+	if err := decodeBody(req, &pool); err != nil {
+		return nil, CodedError(500, err.Error())
+	}
+
+	// Ensure the node pool name matches
+	if poolName != "" && pool.Name != poolName {
+		return nil, CodedError(400, "Node pool name does not match request path")
+	}
+
+	// Format the request
+	args := structs.NodePoolUpsertRequest{
+		NodePools: []*structs.NodePool{&pool},
+	}
+	s.parseWriteRequest(req, &args.WriteRequest)
+
+	var out structs.GenericResponse
+	if err := s.agent.RPC("NodePool.UpsertNodePools", &args, &out); err != nil {
+		return nil, err
+	}
+	setIndex(resp, out.Index)
+	return nil, nil
+}
+
+func (s *HTTPServer) nodePoolDelete(resp http.ResponseWriter, req *http.Request,
+	poolName string) (interface{}, error) {
+
+	args := structs.NodePoolDeleteRequest{
+		Names: []string{poolName},
+	}
+	s.parseWriteRequest(req, &args.WriteRequest)
+
+	var out structs.GenericResponse
+	if err := s.agent.RPC("NodePool.DeleteNodePools", &args, &out); err != nil {
+		return nil, err
+	}
+	setIndex(resp, out.Index)
+	return nil, nil
+}
+
+func (s *HTTPServer) nodePoolNodesList(resp http.ResponseWriter, req *http.Request,
+	poolName string) (interface{}, error) {
+	if req.Method != "GET" {
+		return nil, CodedError(405, ErrInvalidMethod)
+	}
+
+	args := structs.NodePoolNodesRequest{
+		Name: poolName,
+	}
+	if s.parse(resp, req, &args.Region, &args.QueryOptions) {
+		return nil, nil
+	}
+
+	// Parse resources field selection
+	resources, err := parseBool(req, "resources")
+	if err != nil {
+		return nil, err
+	}
+	if resources != nil {
+		args.Fields = &structs.NodeStubFields{
+			Resources: *resources,
+		}
+	}
+
+	var out structs.NodePoolNodesResponse
+	if err := s.agent.RPC("NodePool.ListNodes", &args, &out); err != nil {
+		return nil, err
+	}
+
+	setMeta(resp, &out.QueryMeta)
+	if out.Nodes == nil {
+		out.Nodes = make([]*structs.NodeListStub, 0)
+	}
+	return out.Nodes, nil
+}
+
+func (s *HTTPServer) nodePoolJobsList(resp http.ResponseWriter, req *http.Request,
+	poolName string) (interface{}, error) {
+	if req.Method != "GET" {
+		return nil, CodedError(405, ErrInvalidMethod)
+	}
+
+	args := structs.NodePoolJobsRequest{
+		Name: poolName,
+	}
+	if s.parse(resp, req, &args.Region, &args.QueryOptions) {
+		return nil, nil
+	}
+
+	var out structs.NodePoolJobsResponse
+	if err := s.agent.RPC("NodePool.ListJobs", &args, &out); err != nil {
+		return nil, err
+	}
+
+	setMeta(resp, &out.QueryMeta)
+	if out.Jobs == nil {
+		out.Jobs = make([]*structs.JobListStub, 0)
+	}
+	return out.Jobs, nil
+}
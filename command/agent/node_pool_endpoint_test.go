@@ -0,0 +1,63 @@
+package agent
+
+import (
+	"net/http"
+	"net/http/httptest"
+	"testing"
+
+	"github.com/hashicorp/nomad/nomad/mock"
+	"github.com/hashicorp/nomad/nomad/structs"
+	"github.com/stretchr/testify/require"
+)
+
+func TestHTTP_NodePool_CRUD(t *testing.T) {
+	t.Parallel()
+	httpTest(t, nil, func(s *TestAgent) {
+		pool := mock.NodePool()
+		buf := encodeReq(pool)
+		req, err := http.NewRequest("PUT", "/v1/node/pools", buf)
+		require.NoError(t, err)
+		respW := httptest.NewRecorder()
+		_, err = s.Server.NodePoolsRequest(respW, req)
+		require.NoError(t, err)
+		require.NotZero(t, respW.HeaderMap.Get("X-Nomad-Index"))
+
+		// List the node pools, including the built-in ones
+		req, err = http.NewRequest("GET", "/v1/node/pools", nil)
+		require.NoError(t, err)
+		respW = httptest.NewRecorder()
+		obj, err := s.Server.NodePoolsRequest(respW, req)
+		require.NoError(t, err)
+		require.Len(t, obj.([]*structs.NodePool), 3)
+
+		// Read the node pool
+		req, err = http.NewRequest("GET", "/v1/node/pool/"+pool.Name, nil)
+		require.NoError(t, err)
+		respW = httptest.NewRecorder()
+		obj, err = s.Server.NodePoolSpecificRequest(respW, req)
+		require.NoError(t, err)
+		require.Equal(t, pool.Description, obj.(*structs.NodePool).Description)
+
+		// List its jobs
+		req, err = http.NewRequest("GET", "/v1/node/pool/"+pool.Name+"/jobs", nil)
+		require.NoError(t, err)
+		respW = httptest.NewRecorder()
+		obj, err = s.Server.NodePoolSpecificRequest(respW, req)
+		require.NoError(t, err)
+		require.Empty(t, obj.([]*structs.JobListStub))
+
+		// Delete the node pool
+		req, err = http.NewRequest("DELETE", "/v1/node/pool/"+pool.Name, nil)
+		require.NoError(t, err)
+		respW = httptest.NewRecorder()
+		_, err = s.Server.NodePoolSpecificRequest(respW, req)
+		require.NoError(t, err)
+
+		req, err = http.NewRequest("GET", "/v1/node/pool/"+pool.Name, nil)
+		require.NoError(t, err)
+		respW = httptest.NewRecorder()
+		_, err = s.Server.NodePoolSpecificRequest(respW, req)
+		require.Error(t, err)
+		require.Contains(t, err.Error(), "not found")
+	})
+}
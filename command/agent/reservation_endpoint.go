@@ -0,0 +1,110 @@
+package agent
+
+import (
+	"net/http"
+	"strings"
+
+	"github.com/hashicorp/nomad/nomad/structs"
+)
+
+func (s *HTTPServer) ReservationsRequest(resp http.ResponseWriter, req *http.Request) (interface{}, error) {
+	switch req.Method {
+	case "GET":
+		return s.reservationList(resp, req)
+	case "PUT", "POST":
+		return s.reservationCreate(resp, req)
+	default:
+		return nil, CodedError(405, ErrInvalidMethod)
+	}
+}
+
+func (s *HTTPServer) ReservationSpecificRequest(resp http.ResponseWriter, req *http.Request) (interface{}, error) {
+	name := strings.TrimPrefix(req.URL.Path, "/v1/reservation/")
+	if len(name) == 0 {
+		return nil, CodedError(400, "Missing Reservation Name")
+	}
+	switch req.Method {
+	case "GET":
+		return s.reservationQuery(resp, req, name)
+	case "DELETE":
+		return s.reservationDelete(resp, req, name)
+	default:
+		return nil, CodedError(405, ErrInvalidMethod)
+	}
+}
+
+func (s *HTTPServer) reservationList(resp http.ResponseWriter, req *http.Request) (interface{}, error) {
+	args := structs.ReservationListRequest{}
+	if s.parse(resp, req, &args.Region, &args.QueryOptions) {
+		return nil, nil
+	}
+
+	var out structs.ReservationListResponse
+	if err := s.agent.RPC("Reservation.List", &args, &out); err != nil {
+		return nil, err
+	}
+
+	setMeta(resp, &out.QueryMeta)
+	if out.Reservations == nil {
+		out.Reservations = make([]*structs.Reservation, 0)
+	}
+	return out.Reservations, nil
+}
+
+func (s *HTTPServer) reservationQuery(resp http.ResponseWriter, req *http.Request,
+	name string) (interface{}, error) {
+	args := structs.ReservationSpecificRequest{
+		Name: name,
+	}
+	if s.parse(resp, req, &args.Region, &args.QueryOptions) {
+		return nil, nil
+	}
+
+	var out structs.SingleReservationResponse
+	if err := s.agent.RPC("Reservation.GetReservation", &args, &out); err != nil {
+		return nil, err
+	}
+
+	setMeta(resp, &out.QueryMeta)
+	if out.Reservation == nil {
+		return nil, CodedError(404, "reservation not found")
+	}
+	return out.Reservation, nil
+}
+
+func (s *HTTPServer) reservationCreate(resp http.ResponseWriter, req *http.Request) (interface{}, error) {
+	// Parse the reservation
+	var reservation structs.Reservation
+	if err := decodeBody(req, &reservation); err != nil {
+		return nil, CodedError(500, err.Error())
+	}
+
+	// Format the request
+	args := structs.ReservationUpsertRequest{
+		Reservation: &reservation,
+	}
+	s.parseWriteRequest(req, &args.WriteRequest)
+
+	var out structs.SingleReservationResponse
+	if err := s.agent.RPC("Reservation.Create", &args, &out); err != nil {
+		return nil, err
+	}
+	setIndex(resp, out.Index)
+	return out.Reservation, nil
+}
+
+func (s *HTTPServer) reservationDelete(resp http.ResponseWriter, req *http.Request,
+	name string) (interface{}, error) {
+
+	args := structs.ReservationDeleteRequest{
+		Name: name,
+	}
+	s.parseWriteRequest(req, &args.WriteRequest)
+
+	var out structs.GenericResponse
+	if err := s.agent.RPC("Reservation.Delete", &args, &out); err != nil {
+		return nil, err
+	}
+	setIndex(resp, out.Index)
+	return nil, nil
+}
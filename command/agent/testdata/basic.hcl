@@ -42,6 +42,7 @@ client {
   alloc_dir  = "/tmp/alloc"
   servers    = ["a.b.c:80", "127.0.0.1:1234"]
   node_class = "linux-medium-64bit"
+  node_pool  = "dev"
 
   meta {
     foo = "bar"
@@ -92,10 +93,26 @@ client {
   no_host_uuid             = false
   disable_remote_exec      = true
 
+  artifact_cache_max_size_mb = 512
+
+  artifact {
+    user                      = "artifacts"
+    timeout                   = "10m"
+    max_size_mb               = 1024
+    set_environment_variables = ["AWS_PROFILE"]
+  }
+
   host_volume "tmp" {
     path = "/tmp"
   }
 
+  log_sink "syslog" {
+    type    = "syslog"
+    address = "unixgram:///dev/log"
+  }
+
+  log_sink_allowed_addresses = ["logs.example.com:5000"]
+
   cni_path              = "/tmp/cni_path"
   bridge_network_name   = "custom_bridge_name"
   bridge_network_subnet = "custom_bridge_subnet"
@@ -0,0 +1,175 @@
+package agent
+
+import (
+	"bytes"
+	"fmt"
+	"net/http"
+	"strconv"
+	"strings"
+
+	"github.com/hashicorp/go-msgpack/codec"
+	"github.com/hashicorp/nomad/nomad/structs"
+)
+
+// VariablesListRequest performs a listing of variables using the
+// structs.VariablesListRequest RPC endpoint.
+func (s *HTTPServer) VariablesListRequest(resp http.ResponseWriter, req *http.Request) (interface{}, error) {
+
+	// The endpoint only supports GET requests.
+	if req.Method != http.MethodGet {
+		return nil, CodedError(http.StatusMethodNotAllowed, ErrInvalidMethod)
+	}
+
+	args := structs.VariablesListRequest{}
+	if s.parse(resp, req, &args.Region, &args.QueryOptions) {
+		return nil, nil
+	}
+
+	var reply structs.VariablesListResponse
+	if err := s.agent.RPC("Variables.List", &args, &reply); err != nil {
+		return nil, err
+	}
+	setMeta(resp, &reply.QueryMeta)
+
+	if reply.Data == nil {
+		reply.Data = make([]*structs.VariableMetadata, 0)
+	}
+	return reply.Data, nil
+}
+
+// VariableSpecificRequest is the entry point for the "/v1/var/<path>" HTTP
+// API requests.
+func (s *HTTPServer) VariableSpecificRequest(resp http.ResponseWriter, req *http.Request) (interface{}, error) {
+	path := strings.TrimPrefix(req.URL.Path, "/v1/var/")
+	if path == "" {
+		return nil, CodedError(http.StatusBadRequest, "missing variable path")
+	}
+
+	switch req.Method {
+	case http.MethodGet:
+		return s.variableQuery(resp, req, path)
+	case http.MethodPut, http.MethodPost:
+		return s.variableUpsert(resp, req, path)
+	case http.MethodDelete:
+		return s.variableDelete(resp, req, path)
+	default:
+		return nil, CodedError(http.StatusMethodNotAllowed, ErrInvalidMethod)
+	}
+}
+
+// variableQuery reads a single variable using the structs.VariablesReadRequest
+// RPC endpoint.
+func (s *HTTPServer) variableQuery(resp http.ResponseWriter, req *http.Request, path string) (interface{}, error) {
+	args := structs.VariablesReadRequest{Path: path}
+	if s.parse(resp, req, &args.Region, &args.QueryOptions) {
+		return nil, nil
+	}
+
+	var reply structs.VariablesReadResponse
+	if err := s.agent.RPC("Variables.Read", &args, &reply); err != nil {
+		return nil, err
+	}
+	setMeta(resp, &reply.QueryMeta)
+
+	if reply.Data == nil {
+		return nil, CodedError(http.StatusNotFound, "variable not found")
+	}
+	return reply.Data, nil
+}
+
+// variableUpsert creates or updates a variable. If the "cas" query parameter
+// is set, the write is only performed if the variable's modify index matches.
+func (s *HTTPServer) variableUpsert(resp http.ResponseWriter, req *http.Request, path string) (interface{}, error) {
+
+	// Parse the Variable
+	var variable structs.VariableDecrypted
+	if err := decodeBody(req, &variable); err != nil {
+		return nil, CodedError(http.StatusBadRequest, err.Error())
+	}
+	if len(variable.Items) == 0 {
+		return nil, CodedError(http.StatusBadRequest, "variable missing required Items object")
+	}
+	variable.Path = path
+
+	args := structs.VariablesApplyRequest{
+		Op:  structs.VarOpSet,
+		Var: &variable,
+	}
+	s.parseWriteRequest(req, &args.WriteRequest)
+
+	if isCAS, checkIndex, err := parseCAS(req); err != nil {
+		return nil, err
+	} else if isCAS {
+		args.Op = structs.VarOpCAS
+		args.Var.ModifyIndex = checkIndex
+	}
+
+	var reply structs.VariablesApplyResponse
+	if err := s.agent.RPC("Variables.Apply", &args, &reply); err != nil {
+		return nil, err
+	}
+	setIndex(resp, reply.Index)
+
+	if reply.IsConflict() {
+		return nil, s.writeVariableConflict(resp, reply.Conflict)
+	}
+	return reply.Output, nil
+}
+
+// variableDelete deletes a variable. If the "cas" query parameter is set, the
+// delete is only performed if the variable's modify index matches.
+func (s *HTTPServer) variableDelete(resp http.ResponseWriter, req *http.Request, path string) (interface{}, error) {
+	args := structs.VariablesApplyRequest{
+		Op: structs.VarOpDelete,
+		Var: &structs.VariableDecrypted{
+			VariableMetadata: structs.VariableMetadata{
+				Path: path,
+			},
+		},
+	}
+	s.parseWriteRequest(req, &args.WriteRequest)
+
+	if isCAS, checkIndex, err := parseCAS(req); err != nil {
+		return nil, err
+	} else if isCAS {
+		args.Op = structs.VarOpDeleteCAS
+		args.Var.ModifyIndex = checkIndex
+	}
+
+	var reply structs.VariablesApplyResponse
+	if err := s.agent.RPC("Variables.Apply", &args, &reply); err != nil {
+		return nil, err
+	}
+	setIndex(resp, reply.Index)
+
+	if reply.IsConflict() {
+		return nil, s.writeVariableConflict(resp, reply.Conflict)
+	}
+	return nil, nil
+}
+
+// writeVariableConflict writes the conflicting variable of a failed
+// check-and-set operation to the response with a 409 status code.
+func (s *HTTPServer) writeVariableConflict(resp http.ResponseWriter, conflict *structs.VariableDecrypted) error {
+	var buf bytes.Buffer
+	if err := codec.NewEncoder(&buf, structs.JsonHandleWithExtensions).Encode(conflict); err != nil {
+		return err
+	}
+	resp.Header().Set("Content-Type", "application/json")
+	resp.WriteHeader(http.StatusConflict)
+	_, err := resp.Write(buf.Bytes())
+	return err
+}
+
+// parseCAS parses the "cas" query parameter, returning whether it was set and
+// its value.
+func parseCAS(req *http.Request) (bool, uint64, error) {
+	if cq := req.URL.Query().Get("cas"); cq != "" {
+		ci, err := strconv.ParseUint(cq, 10, 64)
+		if err != nil {
+			return true, 0, CodedError(http.StatusBadRequest, fmt.Sprintf("failed to parse cas value %q: %v", cq, err))
+		}
+		return true, ci, nil
+	}
+	return false, 0, nil
+}
@@ -54,6 +54,10 @@ Alloc Status Options:
 
   -t
     Format and display allocation using a Go template.
+
+  -filter
+    Specifies an expression used to filter the allocations listed when no
+    allocation ID is given. Only used with -json or -t.
 `
 
 	return strings.TrimSpace(helpText)
@@ -70,6 +74,7 @@ func (c *AllocStatusCommand) AutocompleteFlags() complete.Flags {
 			"-verbose": complete.PredictNothing,
 			"-json":    complete.PredictNothing,
 			"-t":       complete.PredictAnything,
+			"-filter":  complete.PredictAnything,
 		})
 }
 
@@ -92,7 +97,7 @@ func (c *AllocStatusCommand) Name() string { return "alloc status" }
 
 func (c *AllocStatusCommand) Run(args []string) int {
 	var short, displayStats, verbose, json bool
-	var tmpl string
+	var tmpl, filter string
 
 	flags := c.Meta.FlagSet(c.Name(), FlagSetClient)
 	flags.Usage = func() { c.Ui.Output(c.Help()) }
@@ -101,6 +106,7 @@ func (c *AllocStatusCommand) Run(args []string) int {
 	flags.BoolVar(&displayStats, "stats", false, "")
 	flags.BoolVar(&json, "json", false, "")
 	flags.StringVar(&tmpl, "t", "", "")
+	flags.StringVar(&filter, "filter", "", "")
 
 	if err := flags.Parse(args); err != nil {
 		return 1
@@ -118,7 +124,7 @@ func (c *AllocStatusCommand) Run(args []string) int {
 
 	// If args not specified but output format is specified, format and output the allocations data list
 	if len(args) == 0 && (json || len(tmpl) > 0) {
-		allocs, _, err := client.Allocations().List(nil)
+		allocs, _, err := client.Allocations().List(&api.QueryOptions{Filter: filter})
 		if err != nil {
 			c.Ui.Error(fmt.Sprintf("Error querying allocations: %v", err))
 			return 1
@@ -255,6 +255,11 @@ func Commands(metaPtr *Meta, agentUi cli.Ui) map[string]cli.CommandFactory {
 				Meta: meta,
 			}, nil
 		},
+		"eval delete": func() (cli.Command, error) {
+			return &EvalDeleteCommand{
+				Meta: meta,
+			}, nil
+		},
 		"eval status": func() (cli.Command, error) {
 			return &EvalStatusCommand{
 				Meta: meta,
@@ -465,6 +470,41 @@ func Commands(metaPtr *Meta, agentUi cli.Ui) map[string]cli.CommandFactory {
 				Meta: meta,
 			}, nil
 		},
+		"node pool": func() (cli.Command, error) {
+			return &NodePoolCommand{
+				Meta: meta,
+			}, nil
+		},
+		"node pool apply": func() (cli.Command, error) {
+			return &NodePoolApplyCommand{
+				Meta: meta,
+			}, nil
+		},
+		"node pool delete": func() (cli.Command, error) {
+			return &NodePoolDeleteCommand{
+				Meta: meta,
+			}, nil
+		},
+		"node pool info": func() (cli.Command, error) {
+			return &NodePoolInfoCommand{
+				Meta: meta,
+			}, nil
+		},
+		"node pool jobs": func() (cli.Command, error) {
+			return &NodePoolJobsCommand{
+				Meta: meta,
+			}, nil
+		},
+		"node pool list": func() (cli.Command, error) {
+			return &NodePoolListCommand{
+				Meta: meta,
+			}, nil
+		},
+		"node pool nodes": func() (cli.Command, error) {
+			return &NodePoolNodesCommand{
+				Meta: meta,
+			}, nil
+		},
 		"node-status": func() (cli.Command, error) {
 			return &NodeStatusCommand{
 				Meta: meta,
@@ -551,6 +591,42 @@ func Commands(metaPtr *Meta, agentUi cli.Ui) map[string]cli.CommandFactory {
 			}, nil
 		},
 
+		"operator root": func() (cli.Command, error) {
+			return &OperatorRootCommand{
+				Meta: meta,
+			}, nil
+		},
+		"operator root keyring": func() (cli.Command, error) {
+			return &OperatorRootKeyringCommand{
+				Meta: meta,
+			}, nil
+		},
+		"operator root keyring list": func() (cli.Command, error) {
+			return &OperatorRootKeyringListCommand{
+				Meta: meta,
+			}, nil
+		},
+		"operator root keyring remove": func() (cli.Command, error) {
+			return &OperatorRootKeyringRemoveCommand{
+				Meta: meta,
+			}, nil
+		},
+		"operator root keyring rotate": func() (cli.Command, error) {
+			return &OperatorRootKeyringRotateCommand{
+				Meta: meta,
+			}, nil
+		},
+
+		"operator scheduler": func() (cli.Command, error) {
+			return &OperatorSchedulerCommand{
+				Meta: meta,
+			}, nil
+		},
+		"operator scheduler simulate": func() (cli.Command, error) {
+			return &OperatorSchedulerSimulateCommand{
+				Meta: meta,
+			}, nil
+		},
 		"operator snapshot": func() (cli.Command, error) {
 			return &OperatorSnapshotCommand{
 				Meta: meta,
@@ -663,6 +739,26 @@ func Commands(metaPtr *Meta, agentUi cli.Ui) map[string]cli.CommandFactory {
 			}, nil
 		},
 
+		"reservation": func() (cli.Command, error) {
+			return &ReservationCommand{
+				Meta: meta,
+			}, nil
+		},
+		"reservation create": func() (cli.Command, error) {
+			return &ReservationCreateCommand{
+				Meta: meta,
+			}, nil
+		},
+		"reservation delete": func() (cli.Command, error) {
+			return &ReservationDeleteCommand{
+				Meta: meta,
+			}, nil
+		},
+		"reservation list": func() (cli.Command, error) {
+			return &ReservationListCommand{
+				Meta: meta,
+			}, nil
+		},
 		"run": func() (cli.Command, error) {
 			return &JobRunCommand{
 				Meta: meta,
@@ -748,6 +844,26 @@ func Commands(metaPtr *Meta, agentUi cli.Ui) map[string]cli.CommandFactory {
 				Meta: meta,
 			}, nil
 		},
+		"service": func() (cli.Command, error) {
+			return &ServiceCommand{
+				Meta: meta,
+			}, nil
+		},
+		"service list": func() (cli.Command, error) {
+			return &ServiceListCommand{
+				Meta: meta,
+			}, nil
+		},
+		"service info": func() (cli.Command, error) {
+			return &ServiceInfoCommand{
+				Meta: meta,
+			}, nil
+		},
+		"service delete": func() (cli.Command, error) {
+			return &ServiceDeleteCommand{
+				Meta: meta,
+			}, nil
+		},
 		"status": func() (cli.Command, error) {
 			return &StatusCommand{
 				Meta: meta,
@@ -788,6 +904,31 @@ func Commands(metaPtr *Meta, agentUi cli.Ui) map[string]cli.CommandFactory {
 				Meta: meta,
 			}, nil
 		},
+		"var": func() (cli.Command, error) {
+			return &VarCommand{
+				Meta: meta,
+			}, nil
+		},
+		"var get": func() (cli.Command, error) {
+			return &VarGetCommand{
+				Meta: meta,
+			}, nil
+		},
+		"var list": func() (cli.Command, error) {
+			return &VarListCommand{
+				Meta: meta,
+			}, nil
+		},
+		"var purge": func() (cli.Command, error) {
+			return &VarPurgeCommand{
+				Meta: meta,
+			}, nil
+		},
+		"var put": func() (cli.Command, error) {
+			return &VarPutCommand{
+				Meta: meta,
+			}, nil
+		},
 		"version": func() (cli.Command, error) {
 			return &VersionCommand{
 				Version: version.GetVersion(),
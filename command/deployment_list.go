@@ -33,6 +33,12 @@ List Options:
   -t
     Format and display the deployments using a Go template.
 
+  -per-page
+    How many results to show per page.
+
+  -page-token
+    Where to start pagination.
+
   -verbose
     Display full information.
 `
@@ -42,9 +48,11 @@ List Options:
 func (c *DeploymentListCommand) AutocompleteFlags() complete.Flags {
 	return mergeAutocompleteFlags(c.Meta.AutocompleteFlags(FlagSetClient),
 		complete.Flags{
-			"-json":    complete.PredictNothing,
-			"-t":       complete.PredictAnything,
-			"-verbose": complete.PredictNothing,
+			"-json":       complete.PredictNothing,
+			"-t":          complete.PredictAnything,
+			"-verbose":    complete.PredictNothing,
+			"-per-page":   complete.PredictAnything,
+			"-page-token": complete.PredictAnything,
 		})
 }
 
@@ -60,13 +68,16 @@ func (c *DeploymentListCommand) Name() string { return "deployment list" }
 
 func (c *DeploymentListCommand) Run(args []string) int {
 	var json, verbose bool
-	var tmpl string
+	var tmpl, pageToken string
+	var perPage int
 
 	flags := c.Meta.FlagSet(c.Name(), FlagSetClient)
 	flags.Usage = func() { c.Ui.Output(c.Help()) }
 	flags.BoolVar(&verbose, "verbose", false, "")
 	flags.BoolVar(&json, "json", false, "")
 	flags.StringVar(&tmpl, "t", "", "")
+	flags.IntVar(&perPage, "per-page", 0, "")
+	flags.StringVar(&pageToken, "page-token", "", "")
 
 	if err := flags.Parse(args); err != nil {
 		return 1
@@ -93,7 +104,11 @@ func (c *DeploymentListCommand) Run(args []string) int {
 		return 1
 	}
 
-	deploys, _, err := client.Deployments().List(nil)
+	opts := &api.QueryOptions{
+		PerPage:   int32(perPage),
+		NextToken: pageToken,
+	}
+	deploys, qm, err := client.Deployments().List(opts)
 	if err != nil {
 		c.Ui.Error(fmt.Sprintf("Error retrieving deployments: %s", err))
 		return 1
@@ -111,6 +126,10 @@ func (c *DeploymentListCommand) Run(args []string) int {
 	}
 
 	c.Ui.Output(formatDeployments(deploys, length))
+
+	if qm.NextToken != "" {
+		c.Ui.Output(paginationHint(qm.NextToken))
+	}
 	return 0
 }
 
@@ -51,6 +51,17 @@ Status Options:
 
   -t
     Format and display deployment using a Go template.
+
+  -filter
+    Specifies an expression used to filter the deployments listed when no
+    deployment ID is given.
+
+  -per-page
+    How many results to show per page. Used only when no deployment ID is
+    given.
+
+  -page-token
+    Where to start pagination. Used only when no deployment ID is given.
 `
 	return strings.TrimSpace(helpText)
 }
@@ -62,10 +73,13 @@ func (c *DeploymentStatusCommand) Synopsis() string {
 func (c *DeploymentStatusCommand) AutocompleteFlags() complete.Flags {
 	return mergeAutocompleteFlags(c.Meta.AutocompleteFlags(FlagSetClient),
 		complete.Flags{
-			"-verbose": complete.PredictNothing,
-			"-json":    complete.PredictNothing,
-			"-monitor": complete.PredictNothing,
-			"-t":       complete.PredictAnything,
+			"-verbose":    complete.PredictNothing,
+			"-json":       complete.PredictNothing,
+			"-monitor":    complete.PredictNothing,
+			"-t":          complete.PredictAnything,
+			"-filter":     complete.PredictAnything,
+			"-per-page":   complete.PredictAnything,
+			"-page-token": complete.PredictAnything,
 		})
 }
 
@@ -88,7 +102,8 @@ func (c *DeploymentStatusCommand) Name() string { return "deployment status" }
 
 func (c *DeploymentStatusCommand) Run(args []string) int {
 	var json, verbose, monitor bool
-	var tmpl string
+	var tmpl, filter, pageToken string
+	var perPage int
 
 	flags := c.Meta.FlagSet(c.Name(), FlagSetClient)
 	flags.Usage = func() { c.Ui.Output(c.Help()) }
@@ -96,6 +111,9 @@ func (c *DeploymentStatusCommand) Run(args []string) int {
 	flags.BoolVar(&json, "json", false, "")
 	flags.BoolVar(&monitor, "monitor", false, "")
 	flags.StringVar(&tmpl, "t", "", "")
+	flags.StringVar(&filter, "filter", "", "")
+	flags.IntVar(&perPage, "per-page", 0, "")
+	flags.StringVar(&pageToken, "page-token", "", "")
 
 	if err := flags.Parse(args); err != nil {
 		return 1
@@ -130,13 +148,22 @@ func (c *DeploymentStatusCommand) Run(args []string) int {
 
 	// List if no arguments are provided
 	if len(args) == 0 {
-		deploys, _, err := client.Deployments().List(nil)
+		opts := &api.QueryOptions{
+			Filter:    filter,
+			PerPage:   int32(perPage),
+			NextToken: pageToken,
+		}
+		deploys, qm, err := client.Deployments().List(opts)
 		if err != nil {
 			c.Ui.Error(fmt.Sprintf("Error retrieving deployments: %s", err))
 			return 1
 		}
 
 		c.Ui.Output(formatDeployments(deploys, length))
+
+		if qm.NextToken != "" {
+			c.Ui.Output(paginationHint(qm.NextToken))
+		}
 		return 0
 	}
 
@@ -23,6 +23,10 @@ Usage: nomad eval <subcommand> [options] [args]
 
       $ nomad eval status <eval-id>
 
+  Delete evaluations matching a filter while the eval broker is paused:
+
+      $ nomad eval delete -filter 'Status == "pending"'
+
   Please see the individual subcommand help for detailed usage information.
 `
 
@@ -0,0 +1,165 @@
+package command
+
+import (
+	"fmt"
+	"strings"
+
+	"github.com/hashicorp/nomad/api"
+	"github.com/hashicorp/nomad/api/contexts"
+	"github.com/posener/complete"
+)
+
+type EvalDeleteCommand struct {
+	Meta
+}
+
+func (c *EvalDeleteCommand) Help() string {
+	helpText := `
+Usage: nomad eval delete [options] [<evaluation>]
+
+  Delete is used to delete evaluations, either a single evaluation by its full
+  ID or all evaluations matching a filter expression. It is intended for use
+  during outages, such as when a job creates a large number of redundant
+  evaluations. Evaluations can only be deleted once their job is stopped, dead,
+  or purged and none of their allocations are still running, unknown, or
+  failed without being rescheduled. Allocations are never deleted.
+
+  The eval broker must be paused before evaluations can be deleted, by setting
+  PauseEvalBroker in the scheduler configuration. Remember to resume the eval
+  broker once the evaluations are deleted.
+
+  If ACLs are enabled, this command requires a management token.
+
+General Options:
+
+  ` + generalOptionsUsage(usageOptsDefault|usageOptsNoNamespace) + `
+
+Eval Delete Options:
+
+  -filter
+    Specifies an expression used to select the evaluations to delete across
+    all namespaces. Cannot be used with an evaluation ID.
+
+  -dry-run
+    Only display the number of evaluations that would be deleted.
+
+  -yes
+    Automatic yes to prompts.
+`
+
+	return strings.TrimSpace(helpText)
+}
+
+func (c *EvalDeleteCommand) Synopsis() string {
+	return "Delete evaluations by ID or filter"
+}
+
+func (c *EvalDeleteCommand) AutocompleteFlags() complete.Flags {
+	return mergeAutocompleteFlags(c.Meta.AutocompleteFlags(FlagSetClient),
+		complete.Flags{
+			"-filter":  complete.PredictAnything,
+			"-dry-run": complete.PredictNothing,
+			"-yes":     complete.PredictNothing,
+		})
+}
+
+func (c *EvalDeleteCommand) AutocompleteArgs() complete.Predictor {
+	return complete.PredictFunc(func(a complete.Args) []string {
+		client, err := c.Meta.Client()
+		if err != nil {
+			return nil
+		}
+
+		resp, _, err := client.Search().PrefixSearch(a.Last, contexts.Evals, nil)
+		if err != nil {
+			return []string{}
+		}
+		return resp.Matches[contexts.Evals]
+	})
+}
+
+func (c *EvalDeleteCommand) Name() string { return "eval delete" }
+
+func (c *EvalDeleteCommand) Run(args []string) int {
+	var dryRun, autoYes bool
+	var filter string
+
+	flags := c.Meta.FlagSet(c.Name(), FlagSetClient)
+	flags.Usage = func() { c.Ui.Output(c.Help()) }
+	flags.StringVar(&filter, "filter", "", "")
+	flags.BoolVar(&dryRun, "dry-run", false, "")
+	flags.BoolVar(&autoYes, "yes", false, "")
+
+	if err := flags.Parse(args); err != nil {
+		return 1
+	}
+
+	// Check that we got either an evaluation ID or a filter, but not both
+	args = flags.Args()
+	switch {
+	case len(args) > 1:
+		c.Ui.Error("This command takes at most one argument: <evaluation>")
+		c.Ui.Error(commandErrorText(c))
+		return 1
+	case len(args) == 1 && filter != "":
+		c.Ui.Error("An evaluation ID cannot be used with the -filter flag")
+		c.Ui.Error(commandErrorText(c))
+		return 1
+	case len(args) == 0 && filter == "":
+		c.Ui.Error("Either an evaluation ID or the -filter flag must be specified")
+		c.Ui.Error(commandErrorText(c))
+		return 1
+	}
+
+	// Get the HTTP client
+	client, err := c.Meta.Client()
+	if err != nil {
+		c.Ui.Error(fmt.Sprintf("Error initializing client: %s", err))
+		return 1
+	}
+
+	req := &api.EvalDeleteRequest{Filter: filter}
+	count := 1
+	if len(args) == 1 {
+		req.Evals = args
+	} else {
+		resp, _, err := client.Evaluations().Count(&api.QueryOptions{Filter: filter})
+		if err != nil {
+			c.Ui.Error(fmt.Sprintf("Error counting evaluations: %s", err))
+			return 1
+		}
+		count = resp.Count
+	}
+
+	if dryRun {
+		c.Ui.Output(fmt.Sprintf("Dry run: %d evaluation(s) would be deleted", count))
+		return 0
+	}
+	if count == 0 {
+		c.Ui.Output("No evaluations match the filter")
+		return 0
+	}
+
+	// Confirm deleting evaluations selected by a filter
+	if filter != "" && !autoYes {
+		question := fmt.Sprintf("Are you sure you want to delete %d evaluation(s)? [y/N]", count)
+		answer, err := c.Ui.Ask(question)
+		if err != nil {
+			c.Ui.Error(fmt.Sprintf("Failed to parse answer: %v", err))
+			return 1
+		}
+		if answer != "y" {
+			c.Ui.Output("Cancelling eval delete")
+			return 0
+		}
+	}
+
+	resp, _, err := client.Evaluations().Delete(req, nil)
+	if err != nil {
+		c.Ui.Error(fmt.Sprintf("Error deleting evaluations: %s", err))
+		return 1
+	}
+
+	c.Ui.Output(fmt.Sprintf("Successfully deleted %d evaluation(s)", resp.Count))
+	return 0
+}
@@ -0,0 +1,48 @@
+package command
+
+import (
+	"testing"
+
+	"github.com/mitchellh/cli"
+	"github.com/stretchr/testify/require"
+)
+
+func TestEvalDeleteCommand_Implements(t *testing.T) {
+	t.Parallel()
+	var _ cli.Command = &EvalDeleteCommand{}
+}
+
+func TestEvalDeleteCommand_Fails(t *testing.T) {
+	t.Parallel()
+
+	cases := []struct {
+		name     string
+		args     []string
+		expected string
+	}{
+		{
+			name:     "too many args",
+			args:     []string{"foo", "bar"},
+			expected: "This command takes at most one argument",
+		},
+		{
+			name:     "no eval or filter",
+			args:     []string{},
+			expected: "Either an evaluation ID or the -filter flag must be specified",
+		},
+		{
+			name:     "eval and filter",
+			args:     []string{"-filter", `Status == "pending"`, "foo"},
+			expected: "An evaluation ID cannot be used with the -filter flag",
+		},
+	}
+
+	for _, tc := range cases {
+		t.Run(tc.name, func(t *testing.T) {
+			ui := cli.NewMockUi()
+			cmd := &EvalDeleteCommand{Meta: Meta{Ui: ui}}
+			require.Equal(t, 1, cmd.Run(tc.args))
+			require.Contains(t, ui.ErrorWriter.String(), tc.expected)
+		})
+	}
+}
@@ -40,6 +40,10 @@ Eval Status Options:
 
   -t
     Format and display evaluation using a Go template.
+
+  -filter
+    Specifies an expression used to filter the evaluations listed when no
+    evaluation ID is given. Only used with -json or -t.
 `
 
 	return strings.TrimSpace(helpText)
@@ -52,6 +56,7 @@ func (c *EvalStatusCommand) Synopsis() string {
 func (c *EvalStatusCommand) AutocompleteFlags() complete.Flags {
 	return mergeAutocompleteFlags(c.Meta.AutocompleteFlags(FlagSetClient),
 		complete.Flags{
+			"-filter":  complete.PredictAnything,
 			"-json":    complete.PredictNothing,
 			"-monitor": complete.PredictNothing,
 			"-t":       complete.PredictAnything,
@@ -82,7 +87,7 @@ func (c *EvalStatusCommand) Name() string { return "eval status" }
 
 func (c *EvalStatusCommand) Run(args []string) int {
 	var monitor, verbose, json bool
-	var tmpl string
+	var tmpl, filter string
 
 	flags := c.Meta.FlagSet(c.Name(), FlagSetClient)
 	flags.Usage = func() { c.Ui.Output(c.Help()) }
@@ -90,6 +95,7 @@ func (c *EvalStatusCommand) Run(args []string) int {
 	flags.BoolVar(&verbose, "verbose", false, "")
 	flags.BoolVar(&json, "json", false, "")
 	flags.StringVar(&tmpl, "t", "", "")
+	flags.StringVar(&filter, "filter", "", "")
 
 	if err := flags.Parse(args); err != nil {
 		return 1
@@ -107,7 +113,7 @@ func (c *EvalStatusCommand) Run(args []string) int {
 
 	// If args not specified but output format is specified, format and output the evaluations data list
 	if len(args) == 0 && (json || len(tmpl) > 0) {
-		evals, _, err := client.Evaluations().List(nil)
+		evals, _, err := client.Evaluations().List(&api.QueryOptions{Filter: filter})
 		if err != nil {
 			c.Ui.Error(fmt.Sprintf("Error querying evaluations: %v", err))
 			return 1
@@ -502,6 +502,37 @@ func commandErrorText(cmd NamedCommand) string {
 	return fmt.Sprintf("For additional help try 'nomad %s -help'", cmd.Name())
 }
 
+// paginationHint returns the message printed after a page of results, with
+// the command needed to fetch the next page.
+func paginationHint(nextToken string) string {
+	return fmt.Sprintf(`
+Results have been paginated. To get the next page run:
+
+%s -page-token %s`, argsWithoutPageToken(os.Args), nextToken)
+}
+
+// argsWithoutPageToken returns the command line used to run the command,
+// without any -page-token flag, so that it can be rerun to fetch another page.
+func argsWithoutPageToken(osArgs []string) string {
+	args := []string{}
+	for i := 0; i < len(osArgs); {
+		arg := osArgs[i]
+
+		if strings.HasPrefix(arg, "-") && strings.HasPrefix(strings.TrimLeft(arg, "-"), "page-token") {
+			if strings.Contains(arg, "=") {
+				i++
+			} else {
+				i += 2
+			}
+			continue
+		}
+
+		args = append(args, arg)
+		i++
+	}
+	return strings.Join(args, " ")
+}
+
 // uiErrorWriter is a io.Writer that wraps underlying ui.ErrorWriter().
 // ui.ErrorWriter expects full lines as inputs and it emits its own line breaks.
 //
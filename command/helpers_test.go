@@ -487,3 +487,31 @@ func TestUiErrorWriter(t *testing.T) {
 	expectedErr += "and thensome more\n"
 	require.Equal(t, expectedErr, errBuf.String())
 }
+
+func TestHelpers_argsWithoutPageToken(t *testing.T) {
+	cases := []struct {
+		args     []string
+		expected string
+	}{
+		{
+			args:     []string{"nomad", "job", "status"},
+			expected: "nomad job status",
+		},
+		{
+			args:     []string{"nomad", "job", "status", "-per-page", "3", "-page-token", "abc"},
+			expected: "nomad job status -per-page 3",
+		},
+		{
+			args:     []string{"nomad", "job", "status", "-page-token=abc", "-per-page=3"},
+			expected: "nomad job status -per-page=3",
+		},
+		{
+			args:     []string{"nomad", "node", "status", "--page-token", "abc", "-verbose"},
+			expected: "nomad node status -verbose",
+		},
+	}
+
+	for _, tc := range cases {
+		require.Equal(t, tc.expected, argsWithoutPageToken(tc.args))
+	}
+}
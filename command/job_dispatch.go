@@ -5,6 +5,7 @@ import (
 	"io/ioutil"
 	"os"
 	"strings"
+	"time"
 
 	"github.com/hashicorp/nomad/api"
 	flaghelper "github.com/hashicorp/nomad/helper/flags"
@@ -57,6 +58,10 @@ Dispatch Options:
     Optional identifier used to prevent more than one instance of the job from
     being dispatched.
 
+  -max-run-duration <duration>
+    Overrides the max_run_duration of every task group of the dispatched job.
+    Tasks running longer than the duration are killed and marked failed.
+
   -verbose
     Display full information.
 `
@@ -73,6 +78,7 @@ func (c *JobDispatchCommand) AutocompleteFlags() complete.Flags {
 			"-meta":              complete.PredictAnything,
 			"-detach":            complete.PredictNothing,
 			"-idempotency-token": complete.PredictAnything,
+			"-max-run-duration":  complete.PredictAnything,
 			"-verbose":           complete.PredictNothing,
 		})
 }
@@ -106,6 +112,7 @@ func (c *JobDispatchCommand) Name() string { return "job dispatch" }
 func (c *JobDispatchCommand) Run(args []string) int {
 	var detach, verbose bool
 	var idempotencyToken string
+	var maxRunDuration time.Duration
 	var meta []string
 
 	flags := c.Meta.FlagSet(c.Name(), FlagSetClient)
@@ -113,12 +120,18 @@ func (c *JobDispatchCommand) Run(args []string) int {
 	flags.BoolVar(&detach, "detach", false, "")
 	flags.BoolVar(&verbose, "verbose", false, "")
 	flags.StringVar(&idempotencyToken, "idempotency-token", "", "")
+	flags.DurationVar(&maxRunDuration, "max-run-duration", 0, "")
 	flags.Var((*flaghelper.StringFlag)(&meta), "meta", "")
 
 	if err := flags.Parse(args); err != nil {
 		return 1
 	}
 
+	if maxRunDuration < 0 {
+		c.Ui.Error("Max run duration must be a positive value")
+		return 1
+	}
+
 	// Truncate the id unless full length is requested
 	length := shortId
 	if verbose {
@@ -174,7 +187,12 @@ func (c *JobDispatchCommand) Run(args []string) int {
 	w := &api.WriteOptions{
 		IdempotencyToken: idempotencyToken,
 	}
-	resp, _, err := client.Jobs().Dispatch(job, metaMap, payload, w)
+	opts := &api.DispatchOptions{
+		Meta:           metaMap,
+		Payload:        payload,
+		MaxRunDuration: maxRunDuration,
+	}
+	resp, _, err := client.Jobs().DispatchOpts(job, opts, w)
 	if err != nil {
 		c.Ui.Error(fmt.Sprintf("Failed to dispatch job: %s", err))
 		return 1
@@ -47,6 +47,15 @@ func TestJobDispatchCommand_Fails(t *testing.T) {
 		t.Fatalf("expected failed query error, got: %s", out)
 	}
 	ui.ErrorWriter.Reset()
+
+	// Fails on negative max run duration
+	if code := cmd.Run([]string{"-max-run-duration=-1m", "foo"}); code != 1 {
+		t.Fatalf("expected exit code 1, got: %d", code)
+	}
+	if out := ui.ErrorWriter.String(); !strings.Contains(out, "Max run duration must be a positive value") {
+		t.Fatalf("expected max run duration error, got: %s", out)
+	}
+	ui.ErrorWriter.Reset()
 }
 
 func TestJobDispatchCommand_AutocompleteArgs(t *testing.T) {
@@ -25,6 +25,10 @@ potentially invalid.`
 	// preemptionDisplayThreshold is an upper bound used to limit and summarize
 	// the details of preempted jobs in the output
 	preemptionDisplayThreshold = 10
+
+	// explainDisplayThreshold is an upper bound used to limit the nodes
+	// explained per task group in the output unless verbose is set
+	explainDisplayThreshold = 10
 )
 
 type JobPlanCommand struct {
@@ -76,6 +80,13 @@ Plan Options:
     Determines whether the diff between the remote job and planned job is shown.
     Defaults to true.
 
+  -explain
+    Explains the placement of each task group. The nodes evaluated are
+    summarized by node class along with the most common reason nodes were
+    filtered, and the outcome of individual nodes is shown with the scores
+    given by each scoring step. Only the first few nodes are shown unless
+    -verbose is set.
+
   -hcl1
     Parses the job file as HCLv1.
 
@@ -89,7 +100,8 @@ Plan Options:
     Path to HCL2 file containing user variables.
 
   -verbose
-    Increase diff verbosity.
+    Increase diff verbosity, and show all explained nodes and their scores
+    when -explain is set.
 `
 	return strings.TrimSpace(helpText)
 }
@@ -102,6 +114,7 @@ func (c *JobPlanCommand) AutocompleteFlags() complete.Flags {
 	return mergeAutocompleteFlags(c.Meta.AutocompleteFlags(FlagSetClient),
 		complete.Flags{
 			"-diff":            complete.PredictNothing,
+			"-explain":         complete.PredictNothing,
 			"-policy-override": complete.PredictNothing,
 			"-verbose":         complete.PredictNothing,
 			"-hcl1":            complete.PredictNothing,
@@ -116,12 +129,13 @@ func (c *JobPlanCommand) AutocompleteArgs() complete.Predictor {
 
 func (c *JobPlanCommand) Name() string { return "job plan" }
 func (c *JobPlanCommand) Run(args []string) int {
-	var diff, policyOverride, verbose bool
+	var diff, explain, policyOverride, verbose bool
 	var varArgs, varFiles flaghelper.StringFlag
 
 	flagSet := c.Meta.FlagSet(c.Name(), FlagSetClient)
 	flagSet.Usage = func() { c.Ui.Output(c.Help()) }
 	flagSet.BoolVar(&diff, "diff", true, "")
+	flagSet.BoolVar(&explain, "explain", false, "")
 	flagSet.BoolVar(&policyOverride, "policy-override", false, "")
 	flagSet.BoolVar(&verbose, "verbose", false, "")
 	flagSet.BoolVar(&c.JobGetter.hcl1, "hcl1", false, "")
@@ -173,6 +187,9 @@ func (c *JobPlanCommand) Run(args []string) int {
 	if policyOverride {
 		opts.PolicyOverride = true
 	}
+	if explain {
+		opts.Explain = true
+	}
 
 	if job.IsMultiregion() {
 		return c.multiregionPlan(client, job, opts, diff, verbose)
@@ -247,9 +264,174 @@ func (c *JobPlanCommand) outputPlannedJob(job *api.Job, resp *api.JobPlanRespons
 		c.addPreemptions(resp)
 	}
 
+	// Print the placement explanations if the plan was explained
+	if len(resp.PlacedTGAllocs) > 0 || hasExplanations(resp.FailedTGAllocs) {
+		c.addExplanations(resp, verbose)
+	}
+
 	return getExitCode(resp)
 }
 
+// hasExplanations returns whether any of the metrics explain their placement.
+func hasExplanations(metrics map[string]*api.AllocationMetric) bool {
+	for _, m := range metrics {
+		if len(m.ClassExplanations) > 0 {
+			return true
+		}
+	}
+	return false
+}
+
+// addExplanations shows why nodes were or weren't selected for the placements
+// of each task group, summarized by node class.
+func (c *JobPlanCommand) addExplanations(resp *api.JobPlanResponse, verbose bool) {
+	c.Ui.Output(c.Colorize().Color("[bold]Placement explanation:[reset]"))
+
+	tgs := make(map[string]struct{})
+	for tg := range resp.FailedTGAllocs {
+		tgs[tg] = struct{}{}
+	}
+	for tg := range resp.PlacedTGAllocs {
+		tgs[tg] = struct{}{}
+	}
+	sorted := make([]string, 0, len(tgs))
+	for tg := range tgs {
+		sorted = append(sorted, tg)
+	}
+	sort.Strings(sorted)
+
+	for _, tg := range sorted {
+		// Prefer explaining the failure if the task group was partially placed
+		metrics, status := resp.FailedTGAllocs[tg], "failed"
+		if metrics == nil {
+			metrics, status = resp.PlacedTGAllocs[tg], "placed"
+		}
+
+		c.Ui.Output(fmt.Sprintf("  Task Group %q (%s):", tg, status))
+		c.Ui.Output(formatClassExplanations(metrics.ClassExplanations, "    "))
+		c.Ui.Output("")
+
+		if len(metrics.NodeExplanations) == 0 {
+			continue
+		}
+		c.Ui.Output(formatNodeExplanations(metrics.NodeExplanations, verbose, "    "))
+		c.Ui.Output("")
+	}
+}
+
+// formatClassExplanations produces a table of the nodes evaluated for a
+// placement by node class.
+func formatClassExplanations(classes map[string]*api.ClassExplanation, prefix string) string {
+	names := make([]string, 0, len(classes))
+	for name := range classes {
+		names = append(names, name)
+	}
+	sort.Strings(names)
+
+	out := make([]string, 0, len(classes)+1)
+	out = append(out, "Node Class|Evaluated|Filtered|Exhausted|Scored|Top Score|Top Reason")
+	for _, name := range names {
+		class := classes[name]
+
+		topScore := ""
+		if class.Scored > 0 {
+			topScore = fmt.Sprintf("%.3g", class.TopScore)
+		}
+
+		// Show the most common reason nodes of the class were rejected
+		topReason, topCount := "", 0
+		for reason, count := range class.Reasons {
+			if count > topCount || (count == topCount && reason < topReason) {
+				topReason, topCount = reason, count
+			}
+		}
+		if topCount > 0 {
+			topReason = fmt.Sprintf("%s (%d)", topReason, topCount)
+		}
+
+		out = append(out, fmt.Sprintf("%s|%d|%d|%d|%d|%s|%s",
+			name, class.Evaluated, class.Filtered, class.Exhausted, class.Scored, topScore, topReason))
+	}
+	return prefixLines(formatList(out), prefix)
+}
+
+// formatNodeExplanations produces a table of the outcome of each node
+// evaluated for a placement, best scoring nodes first. Unless verbose is set
+// the output is limited to explainDisplayThreshold nodes and the scores of
+// each scoring step are omitted.
+func formatNodeExplanations(nodes []*api.NodeExplanation, verbose bool, prefix string) string {
+	sorted := make([]*api.NodeExplanation, len(nodes))
+	copy(sorted, nodes)
+	sort.SliceStable(sorted, func(i, j int) bool {
+		a, b := sorted[i], sorted[j]
+		aScored := a.Filtered == "" && a.Exhausted == ""
+		bScored := b.Filtered == "" && b.Exhausted == ""
+		if aScored != bScored {
+			return aScored
+		}
+		return aScored && a.NormScore > b.NormScore
+	})
+
+	shown := sorted
+	if !verbose && len(shown) > explainDisplayThreshold {
+		shown = shown[:explainDisplayThreshold]
+	}
+
+	header := "Node ID|Node Name|Node Class|Result"
+	if verbose {
+		header += "|Scores"
+	}
+	out := make([]string, 0, len(shown)+1)
+	out = append(out, header)
+	for _, node := range shown {
+		var result string
+		switch {
+		case node.Filtered != "":
+			result = fmt.Sprintf("filtered: %s", node.Filtered)
+		case node.Exhausted != "":
+			result = fmt.Sprintf("exhausted: %s", node.Exhausted)
+		default:
+			result = fmt.Sprintf("score %.3g", node.NormScore)
+		}
+
+		line := fmt.Sprintf("%s|%s|%s|%s", limit(node.NodeID, 8), node.NodeName, node.NodeClass, result)
+		if verbose {
+			line += "|" + formatExplainedScores(node.Scores)
+		}
+		out = append(out, line)
+	}
+
+	formatted := prefixLines(formatList(out), prefix)
+	if hidden := len(sorted) - len(shown); hidden > 0 {
+		formatted += fmt.Sprintf("\n%s%d more nodes evaluated, use -verbose to show all", prefix, hidden)
+	}
+	return formatted
+}
+
+// formatExplainedScores formats the score given by each scoring step.
+func formatExplainedScores(scores map[string]float64) string {
+	names := make([]string, 0, len(scores))
+	for name := range scores {
+		names = append(names, name)
+	}
+	sort.Strings(names)
+
+	parts := make([]string, 0, len(names))
+	for _, name := range names {
+		parts = append(parts, fmt.Sprintf("%s=%.3g", name, scores[name]))
+	}
+	return strings.Join(parts, ", ")
+}
+
+// prefixLines prefixes each line of the text.
+func prefixLines(text, prefix string) string {
+	lines := strings.Split(text, "\n")
+	for i, line := range lines {
+		lines[i] = prefix + line
+	}
+	return strings.Join(lines, "\n")
+}
+
 // addPreemptions shows details about preempted allocations
 func (c *JobPlanCommand) addPreemptions(resp *api.JobPlanResponse) {
 	c.Ui.Output(c.Colorize().Color("[bold][yellow]Preemptions:\n[reset]"))
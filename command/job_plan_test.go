@@ -254,3 +254,59 @@ func TestPlanCommad_Preemptions(t *testing.T) {
 	require.Contains(out, "batch")
 	require.Contains(out, "service")
 }
+
+func TestPlanCommand_Explanations(t *testing.T) {
+	t.Parallel()
+	ui := cli.NewMockUi()
+	cmd := &JobPlanCommand{Meta: Meta{Ui: ui}}
+	require := require.New(t)
+
+	var nodes []*api.NodeExplanation
+	for i := 0; i < 12; i++ {
+		nodes = append(nodes, &api.NodeExplanation{
+			NodeID:    "node" + strconv.Itoa(i),
+			NodeName:  "name" + strconv.Itoa(i),
+			NodeClass: "large",
+			Scores:    map[string]float64{"binpack": 0.5},
+			NormScore: float64(i) / 100,
+		})
+	}
+	nodes[0].NodeClass = "gpu"
+	nodes[0].Filtered = "missing devices"
+
+	resp := &api.JobPlanResponse{
+		PlacedTGAllocs: map[string]*api.AllocationMetric{
+			"web": {
+				NodeExplanations: nodes,
+				ClassExplanations: map[string]*api.ClassExplanation{
+					"gpu": {
+						Evaluated: 1,
+						Filtered:  1,
+						Reasons:   map[string]int{"missing devices": 1},
+					},
+					"large": {
+						Evaluated: 11,
+						Scored:    11,
+						TopScore:  0.11,
+					},
+				},
+			},
+		},
+	}
+
+	cmd.addExplanations(resp, false)
+	out := ui.OutputWriter.String()
+	require.Contains(out, `Task Group "web" (placed)`)
+	require.Contains(out, "missing devices (1)")
+	require.Contains(out, "score 0.11")
+	require.Contains(out, "2 more nodes evaluated")
+	require.NotContains(out, "filtered: missing devices")
+	require.NotContains(out, "binpack=0.5")
+
+	ui.OutputWriter.Reset()
+	cmd.addExplanations(resp, true)
+	out = ui.OutputWriter.String()
+	require.Contains(out, "filtered: missing devices")
+	require.Contains(out, "binpack=0.5")
+	require.NotContains(out, "more nodes evaluated")
+}
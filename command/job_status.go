@@ -24,6 +24,9 @@ type JobStatusCommand struct {
 	evals     bool
 	allAllocs bool
 	verbose   bool
+	filter    string
+	perPage   int
+	pageToken string
 }
 
 func (c *JobStatusCommand) Help() string {
@@ -55,6 +58,16 @@ Status Options:
 
   -verbose
     Display full information.
+
+  -filter
+    Specifies an expression used to filter the jobs listed when no job ID
+    is given.
+
+  -per-page
+    How many results to show per page. Used only when no job ID is given.
+
+  -page-token
+    Where to start pagination. Used only when no job ID is given.
 `
 	return strings.TrimSpace(helpText)
 }
@@ -68,6 +81,9 @@ func (c *JobStatusCommand) AutocompleteFlags() complete.Flags {
 		complete.Flags{
 			"-all-allocs": complete.PredictNothing,
 			"-evals":      complete.PredictNothing,
+			"-filter":     complete.PredictAnything,
+			"-per-page":   complete.PredictAnything,
+			"-page-token": complete.PredictAnything,
 			"-short":      complete.PredictNothing,
 			"-verbose":    complete.PredictNothing,
 		})
@@ -99,6 +115,9 @@ func (c *JobStatusCommand) Run(args []string) int {
 	flags.BoolVar(&c.evals, "evals", false, "")
 	flags.BoolVar(&c.allAllocs, "all-allocs", false, "")
 	flags.BoolVar(&c.verbose, "verbose", false, "")
+	flags.StringVar(&c.filter, "filter", "", "")
+	flags.IntVar(&c.perPage, "per-page", 0, "")
+	flags.StringVar(&c.pageToken, "page-token", "", "")
 
 	if err := flags.Parse(args); err != nil {
 		return 1
@@ -129,7 +148,12 @@ func (c *JobStatusCommand) Run(args []string) int {
 
 	// Invoke list mode if no job ID.
 	if len(args) == 0 {
-		jobs, _, err := client.Jobs().List(nil)
+		opts := &api.QueryOptions{
+			Filter:    c.filter,
+			PerPage:   int32(c.perPage),
+			NextToken: c.pageToken,
+		}
+		jobs, qm, err := client.Jobs().List(opts)
 
 		if err != nil {
 			c.Ui.Error(fmt.Sprintf("Error querying jobs: %s", err))
@@ -142,6 +166,10 @@ func (c *JobStatusCommand) Run(args []string) int {
 		} else {
 			c.Ui.Output(createStatusListOutput(jobs, allNamespaces))
 		}
+
+		if qm.NextToken != "" {
+			c.Ui.Output(paginationHint(qm.NextToken))
+		}
 		return 0
 	}
 
@@ -359,6 +387,9 @@ func (c *JobStatusCommand) outputJobInfo(client *api.Client, job *api.Job) error
 	var latestFailedPlacement *api.Evaluation
 	blockedEval := false
 
+	// Determine the latest evaluation waiting on the dependencies of the job
+	var latestWaiting *api.Evaluation
+
 	// Format the evals
 	evals := make([]string, len(jobEvals)+1)
 	evals[0] = "ID|Priority|Triggered By|Status|Placement Failures"
@@ -376,6 +407,10 @@ func (c *JobStatusCommand) outputJobInfo(client *api.Client, job *api.Job) error
 			blockedEval = true
 		}
 
+		if eval.Status == "waiting" && (latestWaiting == nil || latestWaiting.CreateIndex < eval.CreateIndex) {
+			latestWaiting = eval
+		}
+
 		if len(eval.FailedTGAllocs) == 0 {
 			// Skip evals without failures
 			continue
@@ -395,6 +430,8 @@ func (c *JobStatusCommand) outputJobInfo(client *api.Client, job *api.Job) error
 		c.outputFailedPlacements(latestFailedPlacement)
 	}
 
+	c.outputDependencies(job, latestWaiting)
+
 	c.outputReschedulingEvals(client, job, jobAllocs, c.length)
 
 	if latestDeployment != nil {
@@ -540,7 +577,7 @@ func (c *JobStatusCommand) outputJobSummary(client *api.Client, job *api.Job) er
 	if !periodic && !parameterizedJob {
 		c.Ui.Output(c.Colorize().Color("\n[bold]Summary[reset]"))
 		summaries := make([]string, len(summary.Summary)+1)
-		summaries[0] = "Task Group|Queued|Starting|Running|Failed|Complete|Lost"
+		summaries[0] = "Task Group|Queued|Starting|Running|Failed|Complete|Lost|Unknown"
 		taskGroups := make([]string, 0, len(summary.Summary))
 		for taskGroup := range summary.Summary {
 			taskGroups = append(taskGroups, taskGroup)
@@ -548,10 +585,10 @@ func (c *JobStatusCommand) outputJobSummary(client *api.Client, job *api.Job) er
 		sort.Strings(taskGroups)
 		for idx, taskGroup := range taskGroups {
 			tgs := summary.Summary[taskGroup]
-			summaries[idx+1] = fmt.Sprintf("%s|%d|%d|%d|%d|%d|%d",
+			summaries[idx+1] = fmt.Sprintf("%s|%d|%d|%d|%d|%d|%d|%d",
 				taskGroup, tgs.Queued, tgs.Starting,
 				tgs.Running, tgs.Failed,
-				tgs.Complete, tgs.Lost,
+				tgs.Complete, tgs.Lost, tgs.Unknown,
 			)
 		}
 		c.Ui.Output(formatList(summaries))
@@ -641,6 +678,28 @@ func (c *JobStatusCommand) outputReschedulingEvals(client *api.Client, job *api.
 	return nil
 }
 
+// outputDependencies lists the dependencies of the job and, if the job is
+// waiting on them, the reason it is waiting.
+func (c *JobStatusCommand) outputDependencies(job *api.Job, waitingEval *api.Evaluation) {
+	if len(job.DependsOn) == 0 {
+		return
+	}
+
+	deps := make([]string, len(job.DependsOn)+1)
+	deps[0] = "Job|Condition"
+	for i, d := range job.DependsOn {
+		deps[i+1] = fmt.Sprintf("%s|%s", *d.JobID, *d.Condition)
+	}
+
+	c.Ui.Output(c.Colorize().Color("\n[bold]Dependencies[reset]"))
+	c.Ui.Output(formatList(deps))
+
+	if waitingEval != nil {
+		c.Ui.Output(fmt.Sprintf("\nEvaluation %q is %s",
+			limit(waitingEval.ID, c.length), waitingEval.StatusDescription))
+	}
+}
+
 func (c *JobStatusCommand) outputFailedPlacements(failedEval *api.Evaluation) {
 	if failedEval == nil || len(failedEval.FailedTGAllocs) == 0 {
 		return
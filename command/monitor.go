@@ -334,6 +334,9 @@ func formatAllocMetrics(metrics *api.AllocationMetric, scores bool, prefix strin
 	for cs, num := range metrics.ConstraintFiltered {
 		out += fmt.Sprintf("%s* Constraint %q: %d nodes excluded by filter\n", prefix, cs, num)
 	}
+	for attr, num := range metrics.SpreadSkewFiltered {
+		out += fmt.Sprintf("%s* Spread %q: %d nodes excluded by max_skew\n", prefix, attr, num)
+	}
 
 	// Print exhaustion info
 	if ne := metrics.NodesExhausted; ne > 0 {
@@ -346,6 +349,11 @@ func formatAllocMetrics(metrics *api.AllocationMetric, scores bool, prefix strin
 		out += fmt.Sprintf("%s* Dimension %q exhausted on %d nodes\n", prefix, dim, num)
 	}
 
+	// Print withheld placements info
+	if pw := metrics.PlacementsWithheld; pw > 0 {
+		out += fmt.Sprintf("%s* %d allocation(s) fit but were withheld because the group requires all_or_nothing placement\n", prefix, pw)
+	}
+
 	// Print quota info
 	for _, dim := range metrics.QuotaExhausted {
 		out += fmt.Sprintf("%s* Quota limit hit %q\n", prefix, dim)
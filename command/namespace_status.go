@@ -115,6 +115,7 @@ func formatNamespaceBasics(ns *api.Namespace) string {
 		fmt.Sprintf("Name|%s", ns.Name),
 		fmt.Sprintf("Description|%s", ns.Description),
 		fmt.Sprintf("Quota|%s", ns.Quota),
+		fmt.Sprintf("Weight|%d", ns.Weight),
 	}
 
 	return formatKV(basic)
@@ -0,0 +1,260 @@
+package command
+
+import (
+	"bytes"
+	"encoding/json"
+	"fmt"
+	"io/ioutil"
+	"os"
+	"strings"
+
+	multierror "github.com/hashicorp/go-multierror"
+	"github.com/hashicorp/hcl"
+	"github.com/hashicorp/hcl/hcl/ast"
+	"github.com/hashicorp/nomad/api"
+	"github.com/hashicorp/nomad/helper"
+	"github.com/mitchellh/mapstructure"
+	"github.com/posener/complete"
+)
+
+type NodePoolApplyCommand struct {
+	Meta
+}
+
+func (c *NodePoolApplyCommand) Help() string {
+	helpText := `
+Usage: nomad node pool apply [options] <input>
+
+  Apply is used to create or update a node pool. The specification file will be
+  read from stdin by specifying "-", otherwise a path to the file is expected.
+
+  A node pool specification has the following format:
+
+      node_pool "dev" {
+        description = "Development nodes"
+
+        meta {
+          team = "engineering"
+        }
+
+        scheduler_config {
+          scheduler_algorithm             = "spread"
+          memory_oversubscription_enabled = true
+        }
+      }
+
+  If ACLs are enabled, this command requires a token with the 'write'
+  capability in a 'node_pool' policy that matches the node pool being applied.
+
+General Options:
+
+  ` + generalOptionsUsage(usageOptsDefault|usageOptsNoNamespace) + `
+
+Apply Options:
+
+  -json
+    Parse the input as a JSON node pool specification.
+`
+	return strings.TrimSpace(helpText)
+}
+
+func (c *NodePoolApplyCommand) AutocompleteFlags() complete.Flags {
+	return mergeAutocompleteFlags(c.Meta.AutocompleteFlags(FlagSetClient),
+		complete.Flags{
+			"-json": complete.PredictNothing,
+		})
+}
+
+func (c *NodePoolApplyCommand) AutocompleteArgs() complete.Predictor {
+	return complete.PredictFiles("*")
+}
+
+func (c *NodePoolApplyCommand) Synopsis() string {
+	return "Create or update a node pool"
+}
+
+func (c *NodePoolApplyCommand) Name() string { return "node pool apply" }
+
+func (c *NodePoolApplyCommand) Run(args []string) int {
+	var jsonInput bool
+
+	flags := c.Meta.FlagSet(c.Name(), FlagSetClient)
+	flags.Usage = func() { c.Ui.Output(c.Help()) }
+	flags.BoolVar(&jsonInput, "json", false, "")
+
+	if err := flags.Parse(args); err != nil {
+		return 1
+	}
+
+	// Check that we get exactly one argument
+	args = flags.Args()
+	if l := len(args); l != 1 {
+		c.Ui.Error("This command takes one argument: <input>")
+		c.Ui.Error(commandErrorText(c))
+		return 1
+	}
+
+	// Read the file contents
+	file := args[0]
+	var rawPool []byte
+	var err error
+	if file == "-" {
+		rawPool, err = ioutil.ReadAll(os.Stdin)
+		if err != nil {
+			c.Ui.Error(fmt.Sprintf("Failed to read stdin: %v", err))
+			return 1
+		}
+	} else {
+		rawPool, err = ioutil.ReadFile(file)
+		if err != nil {
+			c.Ui.Error(fmt.Sprintf("Failed to read file: %v", err))
+			return 1
+		}
+	}
+
+	var pool *api.NodePool
+	if jsonInput {
+		var jsonPool api.NodePool
+		dec := json.NewDecoder(bytes.NewBuffer(rawPool))
+		if err := dec.Decode(&jsonPool); err != nil {
+			c.Ui.Error(fmt.Sprintf("Failed to parse node pool: %v", err))
+			return 1
+		}
+		pool = &jsonPool
+	} else {
+		hclPool, err := parseNodePoolSpec(rawPool)
+		if err != nil {
+			c.Ui.Error(fmt.Sprintf("Error parsing node pool specification: %s", err))
+			return 1
+		}
+		pool = hclPool
+	}
+
+	// Get the HTTP client
+	client, err := c.Meta.Client()
+	if err != nil {
+		c.Ui.Error(fmt.Sprintf("Error initializing client: %s", err))
+		return 1
+	}
+
+	_, err = client.NodePools().Register(pool, nil)
+	if err != nil {
+		c.Ui.Error(fmt.Sprintf("Error applying node pool: %s", err))
+		return 1
+	}
+
+	c.Ui.Output(fmt.Sprintf("Successfully applied node pool %q!", pool.Name))
+	return 0
+}
+
+// parseNodePoolSpec is used to parse the node pool specification from HCL
+func parseNodePoolSpec(input []byte) (*api.NodePool, error) {
+	root, err := hcl.ParseBytes(input)
+	if err != nil {
+		return nil, err
+	}
+
+	// Top-level item should be a list
+	list, ok := root.Node.(*ast.ObjectList)
+	if !ok {
+		return nil, fmt.Errorf("error parsing: root should be an object")
+	}
+
+	if err := helper.CheckHCLKeys(list, []string{"node_pool"}); err != nil {
+		return nil, err
+	}
+
+	matches := list.Filter("node_pool")
+	if len(matches.Items) != 1 {
+		return nil, fmt.Errorf("exactly one 'node_pool' block is required")
+	}
+	obj := matches.Items[0]
+	if len(obj.Keys) != 1 {
+		return nil, fmt.Errorf("'node_pool' block must have a name")
+	}
+
+	var poolList *ast.ObjectList
+	if ot, ok := obj.Val.(*ast.ObjectType); ok {
+		poolList = ot.List
+	} else {
+		return nil, fmt.Errorf("'node_pool' block should be an object")
+	}
+
+	pool := &api.NodePool{
+		Name: obj.Keys[0].Token.Value().(string),
+	}
+	if err := parseNodePoolSpecImpl(pool, poolList); err != nil {
+		return nil, multierror.Prefix(err, "node_pool ->")
+	}
+	return pool, nil
+}
+
+// parseNodePoolSpecImpl parses the node pool spec taking as input the AST tree
+func parseNodePoolSpecImpl(result *api.NodePool, list *ast.ObjectList) error {
+	// Check for invalid keys
+	valid := []string{
+		"description",
+		"meta",
+		"scheduler_config",
+	}
+	if err := helper.CheckHCLKeys(list, valid); err != nil {
+		return err
+	}
+
+	// Decode the full thing into a map[string]interface for ease
+	var m map[string]interface{}
+	if err := hcl.DecodeObject(&m, list); err != nil {
+		return err
+	}
+
+	// Manually parse
+	delete(m, "meta")
+	delete(m, "scheduler_config")
+
+	// Decode the rest
+	if err := mapstructure.WeakDecode(m, result); err != nil {
+		return err
+	}
+
+	// Parse meta
+	if o := list.Filter("meta"); len(o.Items) > 0 {
+		for _, o := range o.Elem().Items {
+			var m map[string]interface{}
+			if err := hcl.DecodeObject(&m, o.Val); err != nil {
+				return err
+			}
+			if err := mapstructure.WeakDecode(m, &result.Meta); err != nil {
+				return err
+			}
+		}
+	}
+
+	// Parse the scheduler configuration
+	if o := list.Filter("scheduler_config"); len(o.Items) > 0 {
+		if len(o.Items) > 1 {
+			return fmt.Errorf("only one 'scheduler_config' block allowed")
+		}
+		obj := o.Elem().Items[0]
+
+		valid := []string{
+			"scheduler_algorithm",
+			"memory_oversubscription_enabled",
+		}
+		if err := helper.CheckHCLKeys(obj.Val, valid); err != nil {
+			return multierror.Prefix(err, "scheduler_config ->")
+		}
+
+		var m map[string]interface{}
+		if err := hcl.DecodeObject(&m, obj.Val); err != nil {
+			return err
+		}
+
+		var config api.NodePoolSchedulerConfiguration
+		if err := mapstructure.WeakDecode(m, &config); err != nil {
+			return err
+		}
+		result.SchedulerConfiguration = &config
+	}
+
+	return nil
+}
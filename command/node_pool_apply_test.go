@@ -0,0 +1,132 @@
+package command
+
+import (
+	"io/ioutil"
+	"path/filepath"
+	"strings"
+	"testing"
+
+	"github.com/hashicorp/nomad/api"
+	"github.com/hashicorp/nomad/helper"
+	"github.com/mitchellh/cli"
+	"github.com/stretchr/testify/require"
+)
+
+func TestNodePoolApplyCommand_Implements(t *testing.T) {
+	t.Parallel()
+	var _ cli.Command = &NodePoolApplyCommand{}
+}
+
+func TestNodePoolApplyCommand_Fails(t *testing.T) {
+	t.Parallel()
+	ui := cli.NewMockUi()
+	cmd := &NodePoolApplyCommand{Meta: Meta{Ui: ui}}
+
+	// Fails on misuse
+	code := cmd.Run([]string{"some", "bad", "args"})
+	require.Equal(t, 1, code)
+	require.Contains(t, ui.ErrorWriter.String(), commandErrorText(cmd))
+	ui.ErrorWriter.Reset()
+
+	// Fails on a missing file
+	code = cmd.Run([]string{"-address=nope", "/does/not/exist.hcl"})
+	require.Equal(t, 1, code)
+	require.Contains(t, ui.ErrorWriter.String(), "Failed to read file")
+}
+
+func TestNodePoolApplyCommand_Good(t *testing.T) {
+	t.Parallel()
+
+	// Create a server
+	srv, client, url := testServer(t, true, nil)
+	defer srv.Shutdown()
+
+	ui := cli.NewMockUi()
+	cmd := &NodePoolApplyCommand{Meta: Meta{Ui: ui}}
+
+	spec := `
+node_pool "dev" {
+  description = "Development nodes"
+}
+`
+	file := filepath.Join(t.TempDir(), "pool.hcl")
+	require.NoError(t, ioutil.WriteFile(file, []byte(spec), 0600))
+
+	code := cmd.Run([]string{"-address=" + url, file})
+	require.Equal(t, 0, code, ui.ErrorWriter.String())
+	require.Contains(t, ui.OutputWriter.String(), `Successfully applied node pool "dev"!`)
+
+	pool, _, err := client.NodePools().Info("dev", nil)
+	require.NoError(t, err)
+	require.Equal(t, "Development nodes", pool.Description)
+}
+
+func TestParseNodePoolSpec(t *testing.T) {
+	t.Parallel()
+
+	cases := []struct {
+		name     string
+		input    string
+		expected *api.NodePool
+		err      string
+	}{
+		{
+			name: "full",
+			input: `
+node_pool "dev" {
+  description = "Development nodes"
+
+  meta {
+    team = "engineering"
+  }
+
+  scheduler_config {
+    scheduler_algorithm             = "spread"
+    memory_oversubscription_enabled = true
+  }
+}
+`,
+			expected: &api.NodePool{
+				Name:        "dev",
+				Description: "Development nodes",
+				Meta:        map[string]string{"team": "engineering"},
+				SchedulerConfiguration: &api.NodePoolSchedulerConfiguration{
+					SchedulerAlgorithm:            api.SchedulerAlgorithmSpread,
+					MemoryOversubscriptionEnabled: helper.BoolToPtr(true),
+				},
+			},
+		},
+		{
+			name:     "minimal",
+			input:    `node_pool "dev" {}`,
+			expected: &api.NodePool{Name: "dev"},
+		},
+		{
+			name:  "missing block",
+			input: `description = "nope"`,
+			err:   "invalid key",
+		},
+		{
+			name: "invalid key",
+			input: `
+node_pool "dev" {
+  nope = true
+}
+`,
+			err: "invalid key",
+		},
+	}
+
+	for _, tc := range cases {
+		t.Run(tc.name, func(t *testing.T) {
+			pool, err := parseNodePoolSpec([]byte(tc.input))
+			if tc.err != "" {
+				require.Error(t, err)
+				require.True(t, strings.Contains(err.Error(), tc.err), err.Error())
+				return
+			}
+			require.NoError(t, err)
+			require.Equal(t, tc.expected, pool)
+		})
+	}
+}
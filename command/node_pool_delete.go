@@ -0,0 +1,81 @@
+package command
+
+import (
+	"fmt"
+	"strings"
+
+	"github.com/posener/complete"
+)
+
+type NodePoolDeleteCommand struct {
+	Meta
+}
+
+func (c *NodePoolDeleteCommand) Help() string {
+	helpText := `
+Usage: nomad node pool delete [options] <node-pool>
+
+  Delete is used to remove a node pool. A node pool can only be deleted once
+  it no longer has any nodes or jobs. The built-in "all" and "default" node
+  pools cannot be deleted.
+
+  If ACLs are enabled, this command requires a token with the 'delete'
+  capability in a 'node_pool' policy that matches the node pool being
+  deleted.
+
+General Options:
+
+  ` + generalOptionsUsage(usageOptsDefault|usageOptsNoNamespace)
+
+	return strings.TrimSpace(helpText)
+}
+
+func (c *NodePoolDeleteCommand) AutocompleteFlags() complete.Flags {
+	return c.Meta.AutocompleteFlags(FlagSetClient)
+}
+
+func (c *NodePoolDeleteCommand) AutocompleteArgs() complete.Predictor {
+	filter := map[string]struct{}{"all": {}, "default": {}}
+	return NodePoolPredictor(c.Meta.Client, filter)
+}
+
+func (c *NodePoolDeleteCommand) Synopsis() string {
+	return "Delete a node pool"
+}
+
+func (c *NodePoolDeleteCommand) Name() string { return "node pool delete" }
+
+func (c *NodePoolDeleteCommand) Run(args []string) int {
+	flags := c.Meta.FlagSet(c.Name(), FlagSetClient)
+	flags.Usage = func() { c.Ui.Output(c.Help()) }
+
+	if err := flags.Parse(args); err != nil {
+		return 1
+	}
+
+	// Check that we got exactly one argument
+	args = flags.Args()
+	if l := len(args); l != 1 {
+		c.Ui.Error("This command takes one argument: <node-pool>")
+		c.Ui.Error(commandErrorText(c))
+		return 1
+	}
+
+	name := args[0]
+
+	// Get the HTTP client
+	client, err := c.Meta.Client()
+	if err != nil {
+		c.Ui.Error(fmt.Sprintf("Error initializing client: %s", err))
+		return 1
+	}
+
+	_, err = client.NodePools().Delete(name, nil)
+	if err != nil {
+		c.Ui.Error(fmt.Sprintf("Error deleting node pool: %s", err))
+		return 1
+	}
+
+	c.Ui.Output(fmt.Sprintf("Successfully deleted node pool %q!", name))
+	return 0
+}
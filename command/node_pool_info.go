@@ -0,0 +1,178 @@
+package command
+
+import (
+	"fmt"
+	"sort"
+	"strings"
+
+	"github.com/hashicorp/nomad/api"
+	"github.com/posener/complete"
+)
+
+type NodePoolInfoCommand struct {
+	Meta
+}
+
+func (c *NodePoolInfoCommand) Help() string {
+	helpText := `
+Usage: nomad node pool info [options] <node-pool>
+
+  Info is used to fetch information about an existing node pool. Prefix
+  matching on the node pool name is supported.
+
+  If ACLs are enabled, this command requires a token with the 'read'
+  capability in a 'node_pool' policy that matches the node pool being
+  targeted.
+
+General Options:
+
+  ` + generalOptionsUsage(usageOptsDefault|usageOptsNoNamespace) + `
+
+Info Options:
+
+  -json
+    Output the node pool in its JSON format.
+
+  -t
+    Format and display the node pool using a Go template.
+`
+	return strings.TrimSpace(helpText)
+}
+
+func (c *NodePoolInfoCommand) AutocompleteFlags() complete.Flags {
+	return mergeAutocompleteFlags(c.Meta.AutocompleteFlags(FlagSetClient),
+		complete.Flags{
+			"-json": complete.PredictNothing,
+			"-t":    complete.PredictAnything,
+		})
+}
+
+func (c *NodePoolInfoCommand) AutocompleteArgs() complete.Predictor {
+	return NodePoolPredictor(c.Meta.Client, nil)
+}
+
+func (c *NodePoolInfoCommand) Synopsis() string {
+	return "Fetch information about an existing node pool"
+}
+
+func (c *NodePoolInfoCommand) Name() string { return "node pool info" }
+
+func (c *NodePoolInfoCommand) Run(args []string) int {
+	var json bool
+	var tmpl string
+
+	flags := c.Meta.FlagSet(c.Name(), FlagSetClient)
+	flags.Usage = func() { c.Ui.Output(c.Help()) }
+	flags.BoolVar(&json, "json", false, "")
+	flags.StringVar(&tmpl, "t", "", "")
+
+	if err := flags.Parse(args); err != nil {
+		return 1
+	}
+
+	// Check that we got exactly one argument
+	args = flags.Args()
+	if l := len(args); l != 1 {
+		c.Ui.Error("This command takes one argument: <node-pool>")
+		c.Ui.Error(commandErrorText(c))
+		return 1
+	}
+
+	// Get the HTTP client
+	client, err := c.Meta.Client()
+	if err != nil {
+		c.Ui.Error(fmt.Sprintf("Error initializing client: %s", err))
+		return 1
+	}
+
+	pool, possible, err := getNodePool(client.NodePools(), args[0])
+	if err != nil {
+		c.Ui.Error(fmt.Sprintf("Error retrieving node pool: %s", err))
+		return 1
+	}
+	if len(possible) != 0 {
+		c.Ui.Error(fmt.Sprintf("Prefix matched multiple node pools\n\n%s", formatNodePools(possible)))
+		return 1
+	}
+
+	if json || len(tmpl) > 0 {
+		out, err := Format(json, tmpl, pool)
+		if err != nil {
+			c.Ui.Error(err.Error())
+			return 1
+		}
+
+		c.Ui.Output(out)
+		return 0
+	}
+
+	c.Ui.Output(c.Colorize().Color(formatNodePoolInfo(pool)))
+	return 0
+}
+
+func formatNodePoolInfo(pool *api.NodePool) string {
+	basic := []string{
+		fmt.Sprintf("Name|%s", pool.Name),
+		fmt.Sprintf("Description|%s", pool.Description),
+	}
+	out := formatKV(basic)
+
+	if len(pool.Meta) > 0 {
+		keys := make([]string, 0, len(pool.Meta))
+		for k := range pool.Meta {
+			keys = append(keys, k)
+		}
+		sort.Strings(keys)
+
+		meta := make([]string, 0, len(keys))
+		for _, k := range keys {
+			meta = append(meta, fmt.Sprintf("%s|%s", k, pool.Meta[k]))
+		}
+		out += "\n\n[bold]Metadata[reset]\n" + formatKV(meta)
+	}
+
+	if sc := pool.SchedulerConfiguration; sc != nil {
+		algorithm := "<inherited>"
+		if sc.SchedulerAlgorithm != "" {
+			algorithm = string(sc.SchedulerAlgorithm)
+		}
+		oversubscription := "<inherited>"
+		if sc.MemoryOversubscriptionEnabled != nil {
+			oversubscription = fmt.Sprintf("%v", *sc.MemoryOversubscriptionEnabled)
+		}
+
+		config := []string{
+			fmt.Sprintf("Scheduler Algorithm|%s", algorithm),
+			fmt.Sprintf("Memory Oversubscription Enabled|%s", oversubscription),
+		}
+		out += "\n\n[bold]Scheduler Configuration[reset]\n" + formatKV(config)
+	}
+
+	return out
+}
+
+// getNodePool returns the node pool that matches the given name or prefix.
+// If the prefix matches multiple node pools and none is an exact match, the
+// possible matches are returned instead.
+func getNodePool(client *api.NodePools, name string) (match *api.NodePool, possible []*api.NodePool, err error) {
+	// Do a prefix lookup
+	pools, _, err := client.PrefixList(name, nil)
+	if err != nil {
+		return nil, nil, err
+	}
+
+	switch len(pools) {
+	case 0:
+		return nil, nil, fmt.Errorf("No node pool with prefix %q found", name)
+	case 1:
+		return pools[0], nil, nil
+	default:
+		// Search for an exact match in the returned node pools
+		for _, pool := range pools {
+			if pool.Name == name {
+				return pool, nil, nil
+			}
+		}
+		return nil, pools, nil
+	}
+}
@@ -0,0 +1,127 @@
+package command
+
+import (
+	"fmt"
+	"strings"
+
+	"github.com/hashicorp/nomad/api"
+	"github.com/posener/complete"
+)
+
+type NodePoolJobsCommand struct {
+	Meta
+}
+
+func (c *NodePoolJobsCommand) Help() string {
+	helpText := `
+Usage: nomad node pool jobs [options] <node-pool>
+
+  Jobs is used to list the jobs that target a node pool. Prefix matching on
+  the node pool name is supported.
+
+  If ACLs are enabled, this command requires a token with the 'read'
+  capability in a 'node_pool' policy that matches the node pool being
+  targeted. Only jobs in namespaces the token has the 'list-jobs' capability
+  for are returned.
+
+General Options:
+
+  ` + generalOptionsUsage(usageOptsDefault|usageOptsNoNamespace) + `
+
+Jobs Options:
+
+  -filter
+    Specifies an expression used to filter jobs from the results.
+
+  -json
+    Output the jobs in a JSON format.
+
+  -t
+    Format and display the jobs using a Go template.
+`
+	return strings.TrimSpace(helpText)
+}
+
+func (c *NodePoolJobsCommand) AutocompleteFlags() complete.Flags {
+	return mergeAutocompleteFlags(c.Meta.AutocompleteFlags(FlagSetClient),
+		complete.Flags{
+			"-filter": complete.PredictAnything,
+			"-json":   complete.PredictNothing,
+			"-t":      complete.PredictAnything,
+		})
+}
+
+func (c *NodePoolJobsCommand) AutocompleteArgs() complete.Predictor {
+	return NodePoolPredictor(c.Meta.Client, nil)
+}
+
+func (c *NodePoolJobsCommand) Synopsis() string {
+	return "Fetch a list of jobs in a node pool"
+}
+
+func (c *NodePoolJobsCommand) Name() string { return "node pool jobs" }
+
+func (c *NodePoolJobsCommand) Run(args []string) int {
+	var json bool
+	var tmpl, filter string
+
+	flags := c.Meta.FlagSet(c.Name(), FlagSetClient)
+	flags.Usage = func() { c.Ui.Output(c.Help()) }
+	flags.BoolVar(&json, "json", false, "")
+	flags.StringVar(&tmpl, "t", "", "")
+	flags.StringVar(&filter, "filter", "", "")
+
+	if err := flags.Parse(args); err != nil {
+		return 1
+	}
+
+	// Check that we got exactly one argument
+	args = flags.Args()
+	if l := len(args); l != 1 {
+		c.Ui.Error("This command takes one argument: <node-pool>")
+		c.Ui.Error(commandErrorText(c))
+		return 1
+	}
+
+	// Get the HTTP client
+	client, err := c.Meta.Client()
+	if err != nil {
+		c.Ui.Error(fmt.Sprintf("Error initializing client: %s", err))
+		return 1
+	}
+
+	pool, possible, err := getNodePool(client.NodePools(), args[0])
+	if err != nil {
+		c.Ui.Error(fmt.Sprintf("Error retrieving node pool: %s", err))
+		return 1
+	}
+	if len(possible) != 0 {
+		c.Ui.Error(fmt.Sprintf("Prefix matched multiple node pools\n\n%s", formatNodePools(possible)))
+		return 1
+	}
+
+	jobs, _, err := client.NodePools().ListJobs(pool.Name, &api.QueryOptions{Filter: filter})
+	if err != nil {
+		c.Ui.Error(fmt.Sprintf("Error retrieving jobs: %s", err))
+		return 1
+	}
+
+	if json || len(tmpl) > 0 {
+		out, err := Format(json, tmpl, jobs)
+		if err != nil {
+			c.Ui.Error(err.Error())
+			return 1
+		}
+
+		c.Ui.Output(out)
+		return 0
+	}
+
+	if len(jobs) == 0 {
+		c.Ui.Output("No jobs")
+		return 0
+	}
+
+	c.Ui.Output(createStatusListOutput(jobs, true))
+	return 0
+}
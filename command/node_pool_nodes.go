@@ -0,0 +1,131 @@
+package command
+
+import (
+	"fmt"
+	"strings"
+
+	"github.com/hashicorp/nomad/api"
+	"github.com/posener/complete"
+)
+
+type NodePoolNodesCommand struct {
+	Meta
+}
+
+func (c *NodePoolNodesCommand) Help() string {
+	helpText := `
+Usage: nomad node pool nodes [options] <node-pool>
+
+  Nodes is used to list the client nodes that belong to a node pool. Prefix
+  matching on the node pool name is supported.
+
+  If ACLs are enabled, this command requires a token with the 'node:read'
+  capability and the 'read' capability in a 'node_pool' policy that matches
+  the node pool being targeted.
+
+General Options:
+
+  ` + generalOptionsUsage(usageOptsDefault|usageOptsNoNamespace) + `
+
+Nodes Options:
+
+  -filter
+    Specifies an expression used to filter nodes from the results.
+
+  -json
+    Output the nodes in a JSON format.
+
+  -t
+    Format and display the nodes using a Go template.
+
+  -verbose
+    Display full node IDs.
+`
+	return strings.TrimSpace(helpText)
+}
+
+func (c *NodePoolNodesCommand) AutocompleteFlags() complete.Flags {
+	return mergeAutocompleteFlags(c.Meta.AutocompleteFlags(FlagSetClient),
+		complete.Flags{
+			"-filter":  complete.PredictAnything,
+			"-json":    complete.PredictNothing,
+			"-t":       complete.PredictAnything,
+			"-verbose": complete.PredictNothing,
+		})
+}
+
+func (c *NodePoolNodesCommand) AutocompleteArgs() complete.Predictor {
+	return NodePoolPredictor(c.Meta.Client, nil)
+}
+
+func (c *NodePoolNodesCommand) Synopsis() string {
+	return "Fetch a list of nodes in a node pool"
+}
+
+func (c *NodePoolNodesCommand) Name() string { return "node pool nodes" }
+
+func (c *NodePoolNodesCommand) Run(args []string) int {
+	var json, verbose bool
+	var tmpl, filter string
+
+	flags := c.Meta.FlagSet(c.Name(), FlagSetClient)
+	flags.Usage = func() { c.Ui.Output(c.Help()) }
+	flags.BoolVar(&json, "json", false, "")
+	flags.BoolVar(&verbose, "verbose", false, "")
+	flags.StringVar(&tmpl, "t", "", "")
+	flags.StringVar(&filter, "filter", "", "")
+
+	if err := flags.Parse(args); err != nil {
+		return 1
+	}
+
+	// Check that we got exactly one argument
+	args = flags.Args()
+	if l := len(args); l != 1 {
+		c.Ui.Error("This command takes one argument: <node-pool>")
+		c.Ui.Error(commandErrorText(c))
+		return 1
+	}
+
+	// Get the HTTP client
+	client, err := c.Meta.Client()
+	if err != nil {
+		c.Ui.Error(fmt.Sprintf("Error initializing client: %s", err))
+		return 1
+	}
+
+	pool, possible, err := getNodePool(client.NodePools(), args[0])
+	if err != nil {
+		c.Ui.Error(fmt.Sprintf("Error retrieving node pool: %s", err))
+		return 1
+	}
+	if len(possible) != 0 {
+		c.Ui.Error(fmt.Sprintf("Prefix matched multiple node pools\n\n%s", formatNodePools(possible)))
+		return 1
+	}
+
+	nodes, _, err := client.NodePools().ListNodes(pool.Name, &api.QueryOptions{Filter: filter})
+	if err != nil {
+		c.Ui.Error(fmt.Sprintf("Error retrieving nodes: %s", err))
+		return 1
+	}
+
+	if json || len(tmpl) > 0 {
+		out, err := Format(json, tmpl, nodes)
+		if err != nil {
+			c.Ui.Error(err.Error())
+			return 1
+		}
+
+		c.Ui.Output(out)
+		return 0
+	}
+
+	if len(nodes) == 0 {
+		c.Ui.Output("No nodes")
+		return 0
+	}
+
+	c.Ui.Output(formatNodeStubList(nodes, verbose))
+	return 0
+}
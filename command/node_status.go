@@ -34,6 +34,9 @@ type NodeStatusCommand struct {
 	stats       bool
 	json        bool
 	tmpl        string
+	filter      string
+	perPage     int
+	pageToken   string
 }
 
 func (c *NodeStatusCommand) Help() string {
@@ -79,6 +82,16 @@ Node Status Options:
 
   -t
     Format and display node using a Go template.
+
+  -filter
+    Specifies an expression used to filter the nodes listed when no node ID
+    is given.
+
+  -per-page
+    How many results to show per page. Used only when no node ID is given.
+
+  -page-token
+    Where to start pagination. Used only when no node ID is given.
 `
 	return strings.TrimSpace(helpText)
 }
@@ -90,13 +103,16 @@ func (c *NodeStatusCommand) Synopsis() string {
 func (c *NodeStatusCommand) AutocompleteFlags() complete.Flags {
 	return mergeAutocompleteFlags(c.Meta.AutocompleteFlags(FlagSetClient),
 		complete.Flags{
-			"-allocs":  complete.PredictNothing,
-			"-json":    complete.PredictNothing,
-			"-self":    complete.PredictNothing,
-			"-short":   complete.PredictNothing,
-			"-stats":   complete.PredictNothing,
-			"-t":       complete.PredictAnything,
-			"-verbose": complete.PredictNothing,
+			"-allocs":     complete.PredictNothing,
+			"-filter":     complete.PredictAnything,
+			"-json":       complete.PredictNothing,
+			"-per-page":   complete.PredictAnything,
+			"-page-token": complete.PredictAnything,
+			"-self":       complete.PredictNothing,
+			"-short":      complete.PredictNothing,
+			"-stats":      complete.PredictNothing,
+			"-t":          complete.PredictAnything,
+			"-verbose":    complete.PredictNothing,
 		})
 }
 
@@ -128,6 +144,9 @@ func (c *NodeStatusCommand) Run(args []string) int {
 	flags.BoolVar(&c.stats, "stats", false, "")
 	flags.BoolVar(&c.json, "json", false, "")
 	flags.StringVar(&c.tmpl, "t", "", "")
+	flags.StringVar(&c.filter, "filter", "", "")
+	flags.IntVar(&c.perPage, "per-page", 0, "")
+	flags.StringVar(&c.pageToken, "page-token", "", "")
 
 	if err := flags.Parse(args); err != nil {
 		return 1
@@ -158,7 +177,12 @@ func (c *NodeStatusCommand) Run(args []string) int {
 	if len(args) == 0 && !c.self {
 
 		// Query the node info
-		nodes, _, err := client.Nodes().List(nil)
+		opts := &api.QueryOptions{
+			Filter:    c.filter,
+			PerPage:   int32(c.perPage),
+			NextToken: c.pageToken,
+		}
+		nodes, qm, err := client.Nodes().List(opts)
 		if err != nil {
 			c.Ui.Error(fmt.Sprintf("Error querying node status: %s", err))
 			return 1
@@ -224,6 +248,10 @@ func (c *NodeStatusCommand) Run(args []string) int {
 
 		// Dump the output
 		c.Ui.Output(formatList(out))
+
+		if qm.NextToken != "" {
+			c.Ui.Output(paginationHint(qm.NextToken))
+		}
 		return 0
 	}
 
@@ -391,6 +419,7 @@ func (c *NodeStatusCommand) formatNode(client *api.Client, node *api.Node) int {
 		fmt.Sprintf("Name|%s", node.Name),
 		fmt.Sprintf("Class|%s", node.NodeClass),
 		fmt.Sprintf("DC|%s", node.Datacenter),
+		fmt.Sprintf("Node Pool|%s", node.NodePool),
 		fmt.Sprintf("Drain|%v", formatDrain(node)),
 		fmt.Sprintf("Eligibility|%s", node.SchedulingEligibility),
 		fmt.Sprintf("Status|%s", node.Status),
@@ -0,0 +1,42 @@
+package command
+
+import (
+	"strings"
+
+	"github.com/mitchellh/cli"
+)
+
+type OperatorRootCommand struct {
+	Meta
+}
+
+func (c *OperatorRootCommand) Help() string {
+	helpText := `
+Usage: nomad operator root <subcommand> [options]
+
+  This command groups subcommands for interacting with the root keys that
+  Nomad servers use to encrypt sensitive data such as variables and ACL
+  token secrets.
+
+  List the root keys:
+
+      $ nomad operator root keyring list
+
+  Rotate the active root key:
+
+      $ nomad operator root keyring rotate
+
+  Please see the individual subcommand help for detailed usage information.
+`
+	return strings.TrimSpace(helpText)
+}
+
+func (c *OperatorRootCommand) Synopsis() string {
+	return "Provides access to the root encryption keys"
+}
+
+func (c *OperatorRootCommand) Name() string { return "operator root" }
+
+func (c *OperatorRootCommand) Run(args []string) int {
+	return cli.RunResultHelp
+}
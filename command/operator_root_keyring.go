@@ -0,0 +1,68 @@
+package command
+
+import (
+	"fmt"
+	"strings"
+
+	"github.com/hashicorp/nomad/api"
+	"github.com/mitchellh/cli"
+)
+
+// OperatorRootKeyringCommand is a Command implementation for the
+// "operator root keyring" command group.
+type OperatorRootKeyringCommand struct {
+	Meta
+}
+
+func (c *OperatorRootKeyringCommand) Help() string {
+	helpText := `
+Usage: nomad operator root keyring [options]
+
+  Manages the keyring of root keys that the Nomad servers use to encrypt
+  variables and ACL token secrets. The key material is replicated between
+  the servers and is never returned by the API; only the key metadata is.
+
+  This command requires a management token when ACLs are enabled.
+
+  List the root keys:
+
+      $ nomad operator root keyring list
+
+  Rotate the active root key:
+
+      $ nomad operator root keyring rotate
+
+  Remove an inactive root key:
+
+      $ nomad operator root keyring remove <key ID>
+
+  Please see the individual subcommand help for detailed usage information.
+`
+	return strings.TrimSpace(helpText)
+}
+
+func (c *OperatorRootKeyringCommand) Synopsis() string {
+	return "Manages root encryption keys"
+}
+
+func (c *OperatorRootKeyringCommand) Name() string { return "operator root keyring" }
+
+func (c *OperatorRootKeyringCommand) Run(args []string) int {
+	return cli.RunResultHelp
+}
+
+// renderRootKeys returns the root key metadata as a table.
+func renderRootKeys(keys []*api.RootKeyMeta, verbose bool) string {
+	length := fullId
+	if !verbose {
+		length = shortId
+	}
+
+	out := make([]string, len(keys)+1)
+	out[0] = "Key|State|Create Time"
+	for i, k := range keys {
+		out[i+1] = fmt.Sprintf("%s|%v|%s",
+			limit(k.KeyID, length), k.State, formatUnixNanoTime(k.CreateTime))
+	}
+	return formatList(out)
+}
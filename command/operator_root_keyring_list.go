@@ -0,0 +1,112 @@
+package command
+
+import (
+	"fmt"
+	"strings"
+
+	"github.com/posener/complete"
+)
+
+// OperatorRootKeyringListCommand is a Command implementation that lists the
+// root keys in the keyring.
+type OperatorRootKeyringListCommand struct {
+	Meta
+}
+
+func (c *OperatorRootKeyringListCommand) Help() string {
+	helpText := `
+Usage: nomad operator root keyring list [options]
+
+  List the metadata of the root keys in the keyring. The key material is
+  never displayed.
+
+  This command requires a management token when ACLs are enabled.
+
+General Options:
+
+  ` + generalOptionsUsage(usageOptsDefault|usageOptsNoNamespace) + `
+
+Keyring Options:
+
+  -json
+    Output the keys in JSON format.
+
+  -t
+    Format and display the keys using a Go template.
+
+  -verbose
+    Show full key IDs.
+`
+	return strings.TrimSpace(helpText)
+}
+
+func (c *OperatorRootKeyringListCommand) Synopsis() string {
+	return "Lists the root encryption keys"
+}
+
+func (c *OperatorRootKeyringListCommand) AutocompleteFlags() complete.Flags {
+	return mergeAutocompleteFlags(c.Meta.AutocompleteFlags(FlagSetClient),
+		complete.Flags{
+			"-json":    complete.PredictNothing,
+			"-t":       complete.PredictAnything,
+			"-verbose": complete.PredictNothing,
+		})
+}
+
+func (c *OperatorRootKeyringListCommand) AutocompleteArgs() complete.Predictor {
+	return complete.PredictNothing
+}
+
+func (c *OperatorRootKeyringListCommand) Name() string {
+	return "operator root keyring list"
+}
+
+func (c *OperatorRootKeyringListCommand) Run(args []string) int {
+	var (
+		json, verbose bool
+		tmpl          string
+	)
+
+	flags := c.Meta.FlagSet("root keyring list", FlagSetClient)
+	flags.Usage = func() { c.Ui.Output(c.Help()) }
+	flags.BoolVar(&json, "json", false, "")
+	flags.StringVar(&tmpl, "t", "", "")
+	flags.BoolVar(&verbose, "verbose", false, "")
+
+	if err := flags.Parse(args); err != nil {
+		return 1
+	}
+
+	// Check that we got no arguments
+	args = flags.Args()
+	if l := len(args); l != 0 {
+		c.Ui.Error("This command takes no arguments")
+		c.Ui.Error(commandErrorText(c))
+		return 1
+	}
+
+	client, err := c.Meta.Client()
+	if err != nil {
+		c.Ui.Error(fmt.Sprintf("Error creating nomad cli client: %s", err))
+		return 1
+	}
+
+	resp, _, err := client.Keyring().List(nil)
+	if err != nil {
+		c.Ui.Error(fmt.Sprintf("error: %s", err))
+		return 1
+	}
+
+	if json || len(tmpl) > 0 {
+		out, err := Format(json, tmpl, resp)
+		if err != nil {
+			c.Ui.Error(err.Error())
+			return 1
+		}
+		c.Ui.Output(out)
+		return 0
+	}
+
+	c.Ui.Output(renderRootKeys(resp, verbose))
+	return 0
+}
@@ -0,0 +1,80 @@
+package command
+
+import (
+	"fmt"
+	"strings"
+
+	"github.com/posener/complete"
+)
+
+// OperatorRootKeyringRemoveCommand is a Command implementation that removes
+// an inactive root key from the keyring.
+type OperatorRootKeyringRemoveCommand struct {
+	Meta
+}
+
+func (c *OperatorRootKeyringRemoveCommand) Help() string {
+	helpText := `
+Usage: nomad operator root keyring remove [options] <key ID>
+
+  Remove an inactive root key from the keyring. The active key cannot be
+  removed, nor can a key that is still used by any variable or ACL token. Run
+  "nomad operator root keyring rotate -full" to re-encrypt all variables and
+  ACL tokens with a new key first.
+
+  This command requires a management token when ACLs are enabled.
+
+General Options:
+
+  ` + generalOptionsUsage(usageOptsDefault|usageOptsNoNamespace)
+
+	return strings.TrimSpace(helpText)
+}
+
+func (c *OperatorRootKeyringRemoveCommand) Synopsis() string {
+	return "Removes a root encryption key"
+}
+
+func (c *OperatorRootKeyringRemoveCommand) AutocompleteFlags() complete.Flags {
+	return c.Meta.AutocompleteFlags(FlagSetClient)
+}
+
+func (c *OperatorRootKeyringRemoveCommand) AutocompleteArgs() complete.Predictor {
+	return complete.PredictAnything
+}
+
+func (c *OperatorRootKeyringRemoveCommand) Name() string {
+	return "operator root keyring remove"
+}
+
+func (c *OperatorRootKeyringRemoveCommand) Run(args []string) int {
+	flags := c.Meta.FlagSet("root keyring remove", FlagSetClient)
+	flags.Usage = func() { c.Ui.Output(c.Help()) }
+
+	if err := flags.Parse(args); err != nil {
+		return 1
+	}
+
+	args = flags.Args()
+	if len(args) != 1 {
+		c.Ui.Error("This command requires one argument: <key ID>")
+		c.Ui.Error(commandErrorText(c))
+		return 1
+	}
+	removeKey := args[0]
+
+	client, err := c.Meta.Client()
+	if err != nil {
+		c.Ui.Error(fmt.Sprintf("Error creating nomad cli client: %s", err))
+		return 1
+	}
+
+	_, err = client.Keyring().Delete(removeKey, nil)
+	if err != nil {
+		c.Ui.Error(fmt.Sprintf("error: %s", err))
+		return 1
+	}
+
+	c.Ui.Output(fmt.Sprintf("Removed root key %q", removeKey))
+	return 0
+}
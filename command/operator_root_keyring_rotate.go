@@ -0,0 +1,97 @@
+package command
+
+import (
+	"fmt"
+	"strings"
+
+	"github.com/hashicorp/nomad/api"
+	"github.com/posener/complete"
+)
+
+// OperatorRootKeyringRotateCommand is a Command implementation that
+// generates a new active root key.
+type OperatorRootKeyringRotateCommand struct {
+	Meta
+}
+
+func (c *OperatorRootKeyringRotateCommand) Help() string {
+	helpText := `
+Usage: nomad operator root keyring rotate [options]
+
+  Generate a new root key and make it the active key used to encrypt new
+  data. The previous keys remain in the keyring so that existing data can be
+  decrypted.
+
+  This command requires a management token when ACLs are enabled.
+
+General Options:
+
+  ` + generalOptionsUsage(usageOptsDefault|usageOptsNoNamespace) + `
+
+Keyring Options:
+
+  -full
+    Re-encrypt all existing variables and ACL token secrets with the new key,
+    so that the previous keys can be removed.
+
+  -verbose
+    Show full key ID.
+`
+	return strings.TrimSpace(helpText)
+}
+
+func (c *OperatorRootKeyringRotateCommand) Synopsis() string {
+	return "Rotates the root encryption key"
+}
+
+func (c *OperatorRootKeyringRotateCommand) AutocompleteFlags() complete.Flags {
+	return mergeAutocompleteFlags(c.Meta.AutocompleteFlags(FlagSetClient),
+		complete.Flags{
+			"-full":    complete.PredictNothing,
+			"-verbose": complete.PredictNothing,
+		})
+}
+
+func (c *OperatorRootKeyringRotateCommand) AutocompleteArgs() complete.Predictor {
+	return complete.PredictNothing
+}
+
+func (c *OperatorRootKeyringRotateCommand) Name() string {
+	return "operator root keyring rotate"
+}
+
+func (c *OperatorRootKeyringRotateCommand) Run(args []string) int {
+	var full, verbose bool
+
+	flags := c.Meta.FlagSet("root keyring rotate", FlagSetClient)
+	flags.Usage = func() { c.Ui.Output(c.Help()) }
+	flags.BoolVar(&full, "full", false, "")
+	flags.BoolVar(&verbose, "verbose", false, "")
+
+	if err := flags.Parse(args); err != nil {
+		return 1
+	}
+
+	// Check that we got no arguments
+	args = flags.Args()
+	if l := len(args); l != 0 {
+		c.Ui.Error("This command takes no arguments")
+		c.Ui.Error(commandErrorText(c))
+		return 1
+	}
+
+	client, err := c.Meta.Client()
+	if err != nil {
+		c.Ui.Error(fmt.Sprintf("Error creating nomad cli client: %s", err))
+		return 1
+	}
+
+	resp, _, err := client.Keyring().Rotate(&api.KeyringRotateOptions{Full: full}, nil)
+	if err != nil {
+		c.Ui.Error(fmt.Sprintf("error: %s", err))
+		return 1
+	}
+
+	c.Ui.Output(renderRootKeys([]*api.RootKeyMeta{resp}, verbose))
+	return 0
+}
@@ -0,0 +1,37 @@
+package command
+
+import (
+	"strings"
+
+	"github.com/mitchellh/cli"
+)
+
+type OperatorSchedulerCommand struct {
+	Meta
+}
+
+func (f *OperatorSchedulerCommand) Help() string {
+	helpText := `
+Usage: nomad operator scheduler <subcommand> [options]
+
+  This command groups subcommands for evaluating the behavior of the Nomad
+  schedulers.
+
+  Simulate scheduling the jobs of a snapshot with the spread algorithm:
+
+      $ nomad operator scheduler simulate -scheduler-algorithm=spread backup.snap
+
+  Please see the individual subcommand help for detailed usage information.
+`
+	return strings.TrimSpace(helpText)
+}
+
+func (f *OperatorSchedulerCommand) Synopsis() string {
+	return "Provides access to the scheduler simulator"
+}
+
+func (f *OperatorSchedulerCommand) Name() string { return "operator scheduler" }
+
+func (f *OperatorSchedulerCommand) Run(args []string) int {
+	return cli.RunResultHelp
+}
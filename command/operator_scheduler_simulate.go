@@ -0,0 +1,439 @@
+package command
+
+import (
+	"fmt"
+	"os"
+	"sort"
+	"strconv"
+	"strings"
+
+	"github.com/hashicorp/go-hclog"
+	"github.com/hashicorp/go-memdb"
+	"github.com/hashicorp/go-multierror"
+	"github.com/hashicorp/nomad/command/agent"
+	"github.com/hashicorp/nomad/helper/raftutil"
+	"github.com/hashicorp/nomad/nomad/state"
+	"github.com/hashicorp/nomad/nomad/structs"
+	"github.com/hashicorp/nomad/scheduler"
+	"github.com/posener/complete"
+)
+
+type OperatorSchedulerSimulateCommand struct {
+	Meta
+	JobGetter
+}
+
+func (c *OperatorSchedulerSimulateCommand) Help() string {
+	helpText := `
+Usage: nomad operator scheduler simulate [options] <file>
+
+  Runs the Nomad schedulers locally against the state stored in a snapshot
+  file created with "nomad operator snapshot save", without contacting the
+  cluster. The command reports the number of placed and failed allocations,
+  the utilization and fragmentation of each node class, and the task groups
+  that could not be placed.
+
+  By default every running job of the snapshot is scheduled again on empty
+  nodes, from the highest to the lowest priority. When a job file is given
+  with -job, the allocations of the snapshot are kept and only that job is
+  scheduled.
+
+  The simulation is run with the scheduler configuration of the snapshot and
+  then once for every alternate configuration built from the
+  -scheduler-algorithm and -memory-oversubscription options. Scheduler
+  configuration overrides set on node pools still apply.
+
+  To simulate the snapshot "backup.snap" with both scheduler algorithms:
+
+    $ nomad operator scheduler simulate -scheduler-algorithm=binpack,spread backup.snap
+
+Simulate Options:
+
+  -job <path>
+    Schedule only the job defined in the given job file against the
+    allocations of the snapshot.
+
+  -hcl1
+    Parses the job file as HCLv1.
+
+  -scheduler-algorithm <algorithms>
+    Comma separated list of scheduler algorithms to simulate. Valid values
+    are "binpack" and "spread".
+
+  -memory-oversubscription <values>
+    Comma separated list of memory oversubscription settings to simulate,
+    such as "true,false".
+`
+	return strings.TrimSpace(helpText)
+}
+
+func (c *OperatorSchedulerSimulateCommand) AutocompleteFlags() complete.Flags {
+	return complete.Flags{
+		"-job":                     complete.PredictFiles("*.nomad"),
+		"-hcl1":                    complete.PredictNothing,
+		"-scheduler-algorithm":     complete.PredictSet("binpack", "spread"),
+		"-memory-oversubscription": complete.PredictSet("true", "false"),
+	}
+}
+
+func (c *OperatorSchedulerSimulateCommand) AutocompleteArgs() complete.Predictor {
+	return complete.PredictFiles("*")
+}
+
+func (c *OperatorSchedulerSimulateCommand) Synopsis() string {
+	return "Simulates scheduling the jobs of a snapshot"
+}
+
+func (c *OperatorSchedulerSimulateCommand) Name() string { return "operator scheduler simulate" }
+
+func (c *OperatorSchedulerSimulateCommand) Run(args []string) int {
+	var jobPath, algorithms, oversubscription string
+
+	flags := c.Meta.FlagSet(c.Name(), FlagSetNone)
+	flags.Usage = func() { c.Ui.Output(c.Help()) }
+	flags.StringVar(&jobPath, "job", "", "")
+	flags.BoolVar(&c.JobGetter.hcl1, "hcl1", false, "")
+	flags.StringVar(&algorithms, "scheduler-algorithm", "", "")
+	flags.StringVar(&oversubscription, "memory-oversubscription", "", "")
+
+	if err := flags.Parse(args); err != nil {
+		return 1
+	}
+
+	// Check that we got exactly one snapshot file
+	args = flags.Args()
+	if len(args) != 1 {
+		c.Ui.Error("This command takes one argument: <file>")
+		c.Ui.Error(commandErrorText(c))
+		return 1
+	}
+	path := args[0]
+
+	var job *structs.Job
+	if jobPath != "" {
+		aj, err := c.JobGetter.ApiJob(jobPath)
+		if err != nil {
+			c.Ui.Error(fmt.Sprintf("Error getting job struct: %s", err))
+			return 1
+		}
+
+		aj.Canonicalize()
+		job = agent.ApiJobToStructJob(aj)
+		job.Canonicalize()
+		if err := job.Validate(); err != nil {
+			c.Ui.Error(fmt.Sprintf("Error validating job: %s", err))
+			return 1
+		}
+	}
+
+	// Read the scheduler configuration of the snapshot to build the
+	// alternate configurations from.
+	current, err := snapshotSchedulerConfig(path)
+	if err != nil {
+		c.Ui.Error(err.Error())
+		return 1
+	}
+
+	configs, err := simulationConfigs(current, algorithms, oversubscription)
+	if err != nil {
+		c.Ui.Error(err.Error())
+		return 1
+	}
+
+	for i, config := range configs {
+		if i > 0 {
+			c.Ui.Output("")
+		}
+
+		sim, err := simulate(path, job, config)
+		if err != nil {
+			c.Ui.Error(fmt.Sprintf("Error running simulation: %s", err))
+			return 1
+		}
+
+		if err := c.outputSimulation(sim, config, i == 0); err != nil {
+			c.Ui.Error(fmt.Sprintf("Error summarizing simulation: %s", err))
+			return 1
+		}
+	}
+
+	return 0
+}
+
+func (c *OperatorSchedulerSimulateCommand) outputSimulation(sim *scheduler.Simulator, config *structs.SchedulerConfiguration, current bool) error {
+	title := "Simulated Configuration"
+	if current {
+		title = "Current Configuration"
+	}
+	c.Ui.Output(c.Colorize().Color(fmt.Sprintf("[bold]%s[reset]", title)))
+
+	failures := sim.Failures()
+	failed := 0
+	for _, f := range failures {
+		failed += f.Count
+	}
+
+	c.Ui.Output(formatKV([]string{
+		fmt.Sprintf("Scheduler Algorithm|%s", config.EffectiveSchedulerAlgorithm()),
+		fmt.Sprintf("Memory Oversubscription|%v", config.MemoryOversubscriptionEnabled),
+		fmt.Sprintf("Placed|%d", sim.Placed),
+		fmt.Sprintf("Failed|%d", failed),
+	}))
+
+	classes, err := sim.Classes()
+	if err != nil {
+		return err
+	}
+
+	c.Ui.Output(c.Colorize().Color("\n[bold]Node Classes[reset]"))
+	out := make([]string, 0, len(classes)+1)
+	out = append(out, "Class|Nodes|Used|Allocs|CPU|Memory|Fragmentation|Exhausted")
+	for _, class := range classes {
+		out = append(out, fmt.Sprintf("%s|%d|%d|%d|%s|%s|%.1f%%|%d",
+			class.Class, class.Nodes, class.UsedNodes, class.Allocs,
+			formatUsedPercent(class.CPUUsed, class.CPUTotal),
+			formatUsedPercent(class.MemoryUsed, class.MemoryTotal),
+			class.Fragmentation(), class.Exhausted))
+	}
+	c.Ui.Output(formatList(out))
+
+	if len(failures) == 0 {
+		return nil
+	}
+
+	c.Ui.Output(c.Colorize().Color("\n[bold]Failed Placements[reset]"))
+	out = make([]string, 0, len(failures)+1)
+	out = append(out, "Job ID|Namespace|Task Group|Count")
+	for _, f := range failures {
+		out = append(out, fmt.Sprintf("%s|%s|%s|%d", f.JobID, f.Namespace, f.TaskGroup, f.Count))
+	}
+	c.Ui.Output(formatList(out))
+	return nil
+}
+
+// formatUsedPercent returns the used percentage of the total as a string.
+func formatUsedPercent(used, total int64) string {
+	if total <= 0 {
+		return "-"
+	}
+	return fmt.Sprintf("%.1f%%", float64(used)/float64(total)*100)
+}
+
+// simulationConfigs returns the current scheduler configuration followed by
+// every combination of the given comma separated scheduler algorithms and
+// memory oversubscription settings that differs from it.
+func simulationConfigs(current *structs.SchedulerConfiguration, algorithms, oversubscription string) ([]*structs.SchedulerConfiguration, error) {
+	configs := []*structs.SchedulerConfiguration{current}
+	if algorithms == "" && oversubscription == "" {
+		return configs, nil
+	}
+
+	algs := []structs.SchedulerAlgorithm{current.EffectiveSchedulerAlgorithm()}
+	if algorithms != "" {
+		algs = algs[:0]
+		for _, a := range strings.Split(algorithms, ",") {
+			alg := structs.SchedulerAlgorithm(strings.TrimSpace(a))
+			switch alg {
+			case structs.SchedulerAlgorithmBinpack, structs.SchedulerAlgorithmSpread:
+			default:
+				return nil, fmt.Errorf("Invalid scheduler algorithm %q", alg)
+			}
+			algs = append(algs, alg)
+		}
+	}
+
+	oversubs := []bool{current.MemoryOversubscriptionEnabled}
+	if oversubscription != "" {
+		oversubs = oversubs[:0]
+		for _, o := range strings.Split(oversubscription, ",") {
+			enabled, err := strconv.ParseBool(strings.TrimSpace(o))
+			if err != nil {
+				return nil, fmt.Errorf("Invalid memory oversubscription value %q", o)
+			}
+			oversubs = append(oversubs, enabled)
+		}
+	}
+
+	for _, alg := range algs {
+		for _, enabled := range oversubs {
+			if alg == current.EffectiveSchedulerAlgorithm() && enabled == current.MemoryOversubscriptionEnabled {
+				continue
+			}
+
+			config := *current
+			config.SchedulerAlgorithm = alg
+			config.MemoryOversubscriptionEnabled = enabled
+			configs = append(configs, &config)
+		}
+	}
+	return configs, nil
+}
+
+// restoreSnapshot returns the state store restored from the snapshot file.
+func restoreSnapshot(path string) (*state.StateStore, error) {
+	f, err := os.Open(path)
+	if err != nil {
+		return nil, fmt.Errorf("Error opening snapshot file: %s", err)
+	}
+	defer f.Close()
+
+	store, _, err := raftutil.RestoreFromArchive(f)
+	if err != nil {
+		return nil, fmt.Errorf("Error restoring snapshot: %s", err)
+	}
+	return store, nil
+}
+
+// snapshotSchedulerConfig returns the scheduler configuration stored in the
+// snapshot file, or the server default if none was ever set.
+func snapshotSchedulerConfig(path string) (*structs.SchedulerConfiguration, error) {
+	store, err := restoreSnapshot(path)
+	if err != nil {
+		return nil, err
+	}
+
+	_, config, err := store.SchedulerConfig()
+	if err != nil {
+		return nil, fmt.Errorf("Error reading scheduler configuration: %s", err)
+	}
+	if config == nil {
+		config = &structs.SchedulerConfiguration{
+			SchedulerAlgorithm: structs.SchedulerAlgorithmBinpack,
+			PreemptionConfig: structs.PreemptionConfig{
+				SystemSchedulerEnabled: true,
+			},
+		}
+	}
+	return config, nil
+}
+
+// simulate restores the snapshot file and schedules either the given job or
+// all of the jobs of the snapshot with the scheduler configuration.
+func simulate(path string, job *structs.Job, config *structs.SchedulerConfiguration) (*scheduler.Simulator, error) {
+	store, err := restoreSnapshot(path)
+	if err != nil {
+		return nil, err
+	}
+
+	var jobs []*structs.Job
+	if job != nil {
+		jobs = []*structs.Job{job.Copy()}
+	} else {
+		store, jobs, err = replayState(store)
+		if err != nil {
+			return nil, err
+		}
+	}
+
+	index, err := store.LatestIndex()
+	if err != nil {
+		return nil, err
+	}
+	if err := store.SchedulerSetConfig(index+1, config); err != nil {
+		return nil, err
+	}
+
+	sim, err := scheduler.NewSimulator(hclog.NewNullLogger(), store)
+	if err != nil {
+		return nil, err
+	}
+
+	var mErr multierror.Error
+	for _, job := range jobs {
+		if err := sim.Register(job); err != nil {
+			mErr.Errors = append(mErr.Errors, err)
+		}
+	}
+	return sim, mErr.ErrorOrNil()
+}
+
+// replayState returns a state store holding the namespaces, node pools, nodes
+// and reservations of the given state store but no allocations, along with
+// the running jobs to schedule sorted by descending priority.
+func replayState(src *state.StateStore) (*state.StateStore, []*structs.Job, error) {
+	store, err := state.NewStateStore(&state.StateStoreConfig{
+		Logger: hclog.NewNullLogger(),
+		Region: src.Config().Region,
+	})
+	if err != nil {
+		return nil, nil, err
+	}
+
+	ws := memdb.NewWatchSet()
+	index := uint64(1000)
+
+	iter, err := src.Namespaces(ws)
+	if err != nil {
+		return nil, nil, err
+	}
+	var namespaces []*structs.Namespace
+	for raw := iter.Next(); raw != nil; raw = iter.Next() {
+		namespaces = append(namespaces, raw.(*structs.Namespace).Copy())
+	}
+	if err := store.UpsertNamespaces(index, namespaces); err != nil {
+		return nil, nil, err
+	}
+
+	iter, err = src.NodePools(ws, state.SortDefault)
+	if err != nil {
+		return nil, nil, err
+	}
+	var pools []*structs.NodePool
+	for raw := iter.Next(); raw != nil; raw = iter.Next() {
+		pool := raw.(*structs.NodePool)
+		if !pool.IsBuiltIn() {
+			pools = append(pools, pool.Copy())
+		}
+	}
+	if err := store.UpsertNodePools(structs.NodePoolUpsertRequestType, index, pools); err != nil {
+		return nil, nil, err
+	}
+
+	iter, err = src.Nodes(ws)
+	if err != nil {
+		return nil, nil, err
+	}
+	for raw := iter.Next(); raw != nil; raw = iter.Next() {
+		index++
+		if err := store.UpsertNode(structs.NodeRegisterRequestType, index, raw.(*structs.Node).Copy()); err != nil {
+			return nil, nil, err
+		}
+	}
+
+	// Reservations hold capacity on the nodes they were placed on
+	iter, err = src.Reservations(ws)
+	if err != nil {
+		return nil, nil, err
+	}
+	for raw := iter.Next(); raw != nil; raw = iter.Next() {
+		index++
+		if err := store.UpsertReservation(structs.ReservationUpsertRequestType, index, raw.(*structs.Reservation).Copy()); err != nil {
+			return nil, nil, err
+		}
+	}
+
+	iter, err = src.Jobs(ws, state.SortDefault)
+	if err != nil {
+		return nil, nil, err
+	}
+	var jobs []*structs.Job
+	for raw := iter.Next(); raw != nil; raw = iter.Next() {
+		job := raw.(*structs.Job)
+		if job.Stopped() || job.Status == structs.JobStatusDead ||
+			job.IsPeriodic() || job.IsParameterized() {
+			continue
+		}
+		jobs = append(jobs, job.Copy())
+	}
+
+	// Schedule higher priority jobs first, then in submission order, so the
+	// simulation resembles how the cluster was filled.
+	sort.SliceStable(jobs, func(i, j int) bool {
+		if jobs[i].Priority != jobs[j].Priority {
+			return jobs[i].Priority > jobs[j].Priority
+		}
+		return jobs[i].CreateIndex < jobs[j].CreateIndex
+	})
+
+	return store, jobs, nil
+}
@@ -0,0 +1,84 @@
+package command
+
+import (
+	"testing"
+
+	"github.com/hashicorp/nomad/nomad/mock"
+	"github.com/hashicorp/nomad/nomad/state"
+	"github.com/hashicorp/nomad/nomad/structs"
+	"github.com/mitchellh/cli"
+	"github.com/stretchr/testify/require"
+)
+
+func TestOperatorSchedulerSimulateCommand_Implements(t *testing.T) {
+	t.Parallel()
+	var _ cli.Command = &OperatorSchedulerSimulateCommand{}
+}
+
+func TestOperatorSchedulerSimulate_Works(t *testing.T) {
+	t.Parallel()
+
+	snapPath := generateSnapshotFile(t, nil)
+
+	ui := cli.NewMockUi()
+	cmd := &OperatorSchedulerSimulateCommand{Meta: Meta{Ui: ui}}
+
+	code := cmd.Run([]string{"-scheduler-algorithm=spread", snapPath})
+	require.Zero(t, code, ui.ErrorWriter.String())
+
+	output := ui.OutputWriter.String()
+	require.Contains(t, output, "Current Configuration")
+	require.Contains(t, output, "Simulated Configuration")
+	require.Contains(t, output, "Scheduler Algorithm     = spread")
+}
+
+func TestOperatorSchedulerSimulate_Configs(t *testing.T) {
+	t.Parallel()
+
+	current := &structs.SchedulerConfiguration{
+		SchedulerAlgorithm: structs.SchedulerAlgorithmBinpack,
+	}
+
+	configs, err := simulationConfigs(current, "", "")
+	require.NoError(t, err)
+	require.Len(t, configs, 1)
+
+	configs, err = simulationConfigs(current, "binpack,spread", "true,false")
+	require.NoError(t, err)
+	require.Len(t, configs, 4)
+	require.Equal(t, current, configs[0])
+	require.Equal(t, structs.SchedulerAlgorithmBinpack, configs[1].SchedulerAlgorithm)
+	require.True(t, configs[1].MemoryOversubscriptionEnabled)
+	require.Equal(t, structs.SchedulerAlgorithmSpread, configs[2].SchedulerAlgorithm)
+	require.True(t, configs[2].MemoryOversubscriptionEnabled)
+	require.Equal(t, structs.SchedulerAlgorithmSpread, configs[3].SchedulerAlgorithm)
+	require.False(t, configs[3].MemoryOversubscriptionEnabled)
+
+	_, err = simulationConfigs(current, "random", "")
+	require.Error(t, err)
+	require.Contains(t, err.Error(), "Invalid scheduler algorithm")
+
+	_, err = simulationConfigs(current, "", "maybe")
+	require.Error(t, err)
+	require.Contains(t, err.Error(), "Invalid memory oversubscription")
+}
+
+func TestOperatorSchedulerSimulate_ReplayState(t *testing.T) {
+	t.Parallel()
+
+	src := state.TestStateStore(t)
+	node := mock.Node()
+	require.NoError(t, src.UpsertNode(structs.MsgTypeTestSetup, 1000, node))
+	reservation := mock.Reservation()
+	reservation.NodeIDs = []string{node.ID}
+	require.NoError(t, src.UpsertReservation(structs.MsgTypeTestSetup, 1001, reservation))
+
+	store, _, err := replayState(src)
+	require.NoError(t, err)
+
+	// Reservations keep holding capacity on their nodes in the replay
+	out, err := store.ReservationsByNode(nil, node.ID)
+	require.NoError(t, err)
+	require.Len(t, out, 1)
+	require.Equal(t, reservation.Name, out[0].Name)
+}
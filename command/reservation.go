@@ -0,0 +1,70 @@
+package command
+
+import (
+	"strings"
+
+	"github.com/mitchellh/cli"
+	"github.com/posener/complete"
+)
+
+type ReservationCommand struct {
+	Meta
+}
+
+func (c *ReservationCommand) Help() string {
+	helpText := `
+Usage: nomad reservation <subcommand> [options] [args]
+
+  This command groups subcommands for interacting with capacity reservations.
+  Reservations hold capacity on client nodes for jobs that will be submitted
+  later. Jobs claim the capacity by setting the "reservation" field to the
+  name of a reservation in their namespace.
+
+  Create a reservation:
+
+      $ nomad reservation create -datacenters dc1 -cpu 500 -count 3 <name>
+
+  List reservations:
+
+      $ nomad reservation list
+
+  Delete a reservation:
+
+      $ nomad reservation delete <name>
+
+  Please see the individual subcommand help for detailed usage information.
+`
+
+	return strings.TrimSpace(helpText)
+}
+
+func (c *ReservationCommand) Synopsis() string {
+	return "Interact with capacity reservations"
+}
+
+func (c *ReservationCommand) Name() string { return "reservation" }
+
+func (c *ReservationCommand) Run(args []string) int {
+	return cli.RunResultHelp
+}
+
+// ReservationPredictor returns a reservation predictor.
+func ReservationPredictor(factory ApiClientFactory) complete.Predictor {
+	return complete.PredictFunc(func(a complete.Args) []string {
+		client, err := factory()
+		if err != nil {
+			return nil
+		}
+
+		reservations, _, err := client.Reservations().PrefixList(a.Last, nil)
+		if err != nil {
+			return []string{}
+		}
+
+		names := make([]string, 0, len(reservations))
+		for _, reservation := range reservations {
+			names = append(names, reservation.Name)
+		}
+		return names
+	})
+}
@@ -0,0 +1,207 @@
+package command
+
+import (
+	"fmt"
+	"strings"
+	"time"
+
+	"github.com/hashicorp/nomad/api"
+	flaghelper "github.com/hashicorp/nomad/helper/flags"
+	"github.com/posener/complete"
+)
+
+type ReservationCreateCommand struct {
+	Meta
+}
+
+func (c *ReservationCreateCommand) Help() string {
+	helpText := `
+Usage: nomad reservation create [options] <name>
+
+  Create is used to reserve capacity on client nodes. The instances of the
+  reservation are placed on nodes when it is created and hold their resources
+  until they are claimed by the allocations of jobs that reference the
+  reservation, the reservation expires or it is deleted. Creation fails if
+  not all instances can be placed.
+
+  If ACLs are enabled, this command requires a token with the 'submit-job'
+  capability for the reservation's namespace.
+
+General Options:
+
+  ` + generalOptionsUsage(usageOptsDefault) + `
+
+Create Options:
+
+  -datacenters
+    Comma separated list of the datacenters the instances may be placed in.
+    Required.
+
+  -node-pool
+    The node pool the instances may be placed in. Defaults to "default".
+
+  -constraint
+    A constraint nodes must satisfy to hold instances, in the form
+    "<attribute> <operator> <value>", for example
+    "${attr.kernel.name} = linux". May be specified multiple times.
+
+  -cpu
+    The CPU in MHz held by each instance.
+
+  -memory
+    The memory in MB held by each instance.
+
+  -disk
+    The disk in MB held by each instance.
+
+  -count
+    The number of instances to reserve. Defaults to 1.
+
+  -ttl
+    The duration after which the reservation expires, for example "24h". By
+    default reservations don't expire.
+
+  -json
+    Output the created reservation in a JSON format.
+
+  -t
+    Format and display the created reservation using a Go template.
+`
+	return strings.TrimSpace(helpText)
+}
+
+func (c *ReservationCreateCommand) AutocompleteFlags() complete.Flags {
+	return mergeAutocompleteFlags(c.Meta.AutocompleteFlags(FlagSetClient),
+		complete.Flags{
+			"-datacenters": complete.PredictAnything,
+			"-node-pool":   NodePoolPredictor(c.Client, nil),
+			"-constraint":  complete.PredictAnything,
+			"-cpu":         complete.PredictAnything,
+			"-memory":      complete.PredictAnything,
+			"-disk":        complete.PredictAnything,
+			"-count":       complete.PredictAnything,
+			"-ttl":         complete.PredictAnything,
+			"-json":        complete.PredictNothing,
+			"-t":           complete.PredictAnything,
+		})
+}
+
+func (c *ReservationCreateCommand) AutocompleteArgs() complete.Predictor {
+	return complete.PredictNothing
+}
+
+func (c *ReservationCreateCommand) Synopsis() string {
+	return "Create a capacity reservation"
+}
+
+func (c *ReservationCreateCommand) Name() string { return "reservation create" }
+
+func (c *ReservationCreateCommand) Run(args []string) int {
+	var json bool
+	var tmpl, datacenters, nodePool string
+	var cpu, memory, disk, count int
+	var ttl time.Duration
+	var constraints []string
+
+	flags := c.Meta.FlagSet(c.Name(), FlagSetClient)
+	flags.Usage = func() { c.Ui.Output(c.Help()) }
+	flags.StringVar(&datacenters, "datacenters", "", "")
+	flags.StringVar(&nodePool, "node-pool", "", "")
+	flags.Var((*flaghelper.StringFlag)(&constraints), "constraint", "")
+	flags.IntVar(&cpu, "cpu", 0, "")
+	flags.IntVar(&memory, "memory", 0, "")
+	flags.IntVar(&disk, "disk", 0, "")
+	flags.IntVar(&count, "count", 1, "")
+	flags.DurationVar(&ttl, "ttl", 0, "")
+	flags.BoolVar(&json, "json", false, "")
+	flags.StringVar(&tmpl, "t", "", "")
+
+	if err := flags.Parse(args); err != nil {
+		return 1
+	}
+
+	// Check that we got exactly one argument
+	args = flags.Args()
+	if l := len(args); l != 1 {
+		c.Ui.Error("This command takes one argument: <name>")
+		c.Ui.Error(commandErrorText(c))
+		return 1
+	}
+
+	if datacenters == "" {
+		c.Ui.Error("The -datacenters flag is required")
+		c.Ui.Error(commandErrorText(c))
+		return 1
+	}
+	if ttl < 0 {
+		c.Ui.Error("The -ttl flag must not be negative")
+		return 1
+	}
+
+	reservation := &api.Reservation{
+		Name:        args[0],
+		Datacenters: strings.Split(datacenters, ","),
+		NodePool:    nodePool,
+		Resources: &api.ReservationResources{
+			CPU:      cpu,
+			MemoryMB: memory,
+			DiskMB:   disk,
+		},
+		Count: count,
+	}
+	if ttl > 0 {
+		reservation.ExpiresAt = time.Now().Add(ttl).UTC()
+	}
+	for _, raw := range constraints {
+		constraint, err := parseReservationConstraint(raw)
+		if err != nil {
+			c.Ui.Error(fmt.Sprintf("Error parsing constraint %q: %s", raw, err))
+			return 1
+		}
+		reservation.Constraints = append(reservation.Constraints, constraint)
+	}
+
+	// Get the HTTP client
+	client, err := c.Meta.Client()
+	if err != nil {
+		c.Ui.Error(fmt.Sprintf("Error initializing client: %s", err))
+		return 1
+	}
+
+	out, _, err := client.Reservations().Create(reservation, nil)
+	if err != nil {
+		c.Ui.Error(fmt.Sprintf("Error creating reservation: %s", err))
+		return 1
+	}
+
+	if json || len(tmpl) > 0 {
+		formatted, err := Format(json, tmpl, out)
+		if err != nil {
+			c.Ui.Error(err.Error())
+			return 1
+		}
+
+		c.Ui.Output(formatted)
+		return 0
+	}
+
+	c.Ui.Output(fmt.Sprintf("Successfully created reservation %q with %d instance(s)!",
+		out.Name, len(out.NodeIDs)))
+	return 0
+}
+
+// parseReservationConstraint parses a constraint in the form
+// "<attribute> <operator> <value>". The value may be omitted for operators
+// that don't take one, such as "is_set".
+func parseReservationConstraint(raw string) (*api.Constraint, error) {
+	parts := strings.SplitN(strings.TrimSpace(raw), " ", 3)
+	if len(parts) < 2 {
+		return nil, fmt.Errorf(`must be in the form "<attribute> <operator> <value>"`)
+	}
+
+	value := ""
+	if len(parts) == 3 {
+		value = strings.TrimSpace(parts[2])
+	}
+	return api.NewConstraint(parts[0], parts[1], value), nil
+}
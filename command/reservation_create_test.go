@@ -0,0 +1,106 @@
+package command
+
+import (
+	"fmt"
+	"testing"
+
+	"github.com/hashicorp/nomad/api"
+	"github.com/hashicorp/nomad/testutil"
+	"github.com/mitchellh/cli"
+	"github.com/stretchr/testify/require"
+)
+
+func TestReservationCreateCommand_Implements(t *testing.T) {
+	t.Parallel()
+	var _ cli.Command = &ReservationCreateCommand{}
+}
+
+func TestReservationCreateCommand_Fails(t *testing.T) {
+	t.Parallel()
+	ui := cli.NewMockUi()
+	cmd := &ReservationCreateCommand{Meta: Meta{Ui: ui}}
+
+	// Fails on misuse
+	code := cmd.Run([]string{"some", "bad", "args"})
+	require.Equal(t, 1, code)
+	require.Contains(t, ui.ErrorWriter.String(), commandErrorText(cmd))
+	ui.ErrorWriter.Reset()
+
+	// Fails without datacenters
+	code = cmd.Run([]string{"-address=nope", "-cpu=100", "failover"})
+	require.Equal(t, 1, code)
+	require.Contains(t, ui.ErrorWriter.String(), "-datacenters flag is required")
+	ui.ErrorWriter.Reset()
+
+	// Fails on invalid constraints
+	code = cmd.Run([]string{"-address=nope", "-datacenters=dc1", "-constraint=bad", "failover"})
+	require.Equal(t, 1, code)
+	require.Contains(t, ui.ErrorWriter.String(), "Error parsing constraint")
+}
+
+func TestReservationCreateCommand_Good(t *testing.T) {
+	t.Parallel()
+
+	// Create a server with a client to hold the reservation
+	srv, client, url := testServer(t, true, nil)
+	defer srv.Shutdown()
+
+	testutil.WaitForResult(func() (bool, error) {
+		nodes, _, err := client.Nodes().List(nil)
+		if err != nil {
+			return false, err
+		}
+		for _, node := range nodes {
+			if node.Status == api.NodeStatusReady {
+				return true, nil
+			}
+		}
+		return false, fmt.Errorf("no ready nodes")
+	}, func(err error) {
+		require.NoError(t, err)
+	})
+
+	ui := cli.NewMockUi()
+	cmd := &ReservationCreateCommand{Meta: Meta{Ui: ui}}
+
+	code := cmd.Run([]string{"-address=" + url,
+		"-datacenters=dc1", "-cpu=100", "-memory=64", "-count=2", "-ttl=1h",
+		"-constraint", "${attr.kernel.name} is_set",
+		"failover"})
+	require.Equal(t, 0, code, ui.ErrorWriter.String())
+	require.Contains(t, ui.OutputWriter.String(), `Successfully created reservation "failover" with 2 instance(s)!`)
+
+	reservation, _, err := client.Reservations().Info("failover", nil)
+	require.NoError(t, err)
+	require.Len(t, reservation.NodeIDs, 2)
+	require.Len(t, reservation.Constraints, 1)
+	require.False(t, reservation.ExpiresAt.IsZero())
+
+	// The reservation is listed and can be deleted
+	ui = cli.NewMockUi()
+	listCmd := &ReservationListCommand{Meta: Meta{Ui: ui}}
+	code = listCmd.Run([]string{"-address=" + url})
+	require.Equal(t, 0, code, ui.ErrorWriter.String())
+	require.Contains(t, ui.OutputWriter.String(), "failover")
+
+	ui = cli.NewMockUi()
+	deleteCmd := &ReservationDeleteCommand{Meta: Meta{Ui: ui}}
+	code = deleteCmd.Run([]string{"-address=" + url, "failover"})
+	require.Equal(t, 0, code, ui.ErrorWriter.String())
+	require.Contains(t, ui.OutputWriter.String(), `Successfully deleted reservation "failover"!`)
+}
+
+func TestParseReservationConstraint(t *testing.T) {
+	t.Parallel()
+
+	c, err := parseReservationConstraint("${node.class} = large instances")
+	require.NoError(t, err)
+	require.Equal(t, api.NewConstraint("${node.class}", "=", "large instances"), c)
+
+	c, err = parseReservationConstraint("${attr.kernel.name} is_set")
+	require.NoError(t, err)
+	require.Equal(t, api.NewConstraint("${attr.kernel.name}", "is_set", ""), c)
+
+	_, err = parseReservationConstraint("${node.class}")
+	require.Error(t, err)
+}
@@ -0,0 +1,136 @@
+package command
+
+import (
+	"fmt"
+	"sort"
+	"strings"
+	"time"
+
+	"github.com/hashicorp/nomad/api"
+	"github.com/posener/complete"
+)
+
+type ReservationListCommand struct {
+	Meta
+}
+
+func (c *ReservationListCommand) Help() string {
+	helpText := `
+Usage: nomad reservation list [options]
+
+  List is used to list the capacity reservations of a namespace.
+
+  If ACLs are enabled, this command requires a token with the 'read-job'
+  capability for the namespace.
+
+General Options:
+
+  ` + generalOptionsUsage(usageOptsDefault) + `
+
+List Options:
+
+  -json
+    Output the reservations in a JSON format.
+
+  -t
+    Format and display the reservations using a Go template.
+`
+	return strings.TrimSpace(helpText)
+}
+
+func (c *ReservationListCommand) AutocompleteFlags() complete.Flags {
+	return mergeAutocompleteFlags(c.Meta.AutocompleteFlags(FlagSetClient),
+		complete.Flags{
+			"-json": complete.PredictNothing,
+			"-t":    complete.PredictAnything,
+		})
+}
+
+func (c *ReservationListCommand) AutocompleteArgs() complete.Predictor {
+	return complete.PredictNothing
+}
+
+func (c *ReservationListCommand) Synopsis() string {
+	return "List capacity reservations"
+}
+
+func (c *ReservationListCommand) Name() string { return "reservation list" }
+
+func (c *ReservationListCommand) Run(args []string) int {
+	var json bool
+	var tmpl string
+
+	flags := c.Meta.FlagSet(c.Name(), FlagSetClient)
+	flags.Usage = func() { c.Ui.Output(c.Help()) }
+	flags.BoolVar(&json, "json", false, "")
+	flags.StringVar(&tmpl, "t", "", "")
+
+	if err := flags.Parse(args); err != nil {
+		return 1
+	}
+
+	// Check that we got no arguments
+	args = flags.Args()
+	if l := len(args); l != 0 {
+		c.Ui.Error("This command takes no arguments")
+		c.Ui.Error(commandErrorText(c))
+		return 1
+	}
+
+	// Get the HTTP client
+	client, err := c.Meta.Client()
+	if err != nil {
+		c.Ui.Error(fmt.Sprintf("Error initializing client: %s", err))
+		return 1
+	}
+
+	reservations, _, err := client.Reservations().List(nil)
+	if err != nil {
+		c.Ui.Error(fmt.Sprintf("Error retrieving reservations: %s", err))
+		return 1
+	}
+
+	if json || len(tmpl) > 0 {
+		out, err := Format(json, tmpl, reservations)
+		if err != nil {
+			c.Ui.Error(err.Error())
+			return 1
+		}
+
+		c.Ui.Output(out)
+		return 0
+	}
+
+	c.Ui.Output(formatReservations(reservations))
+	return 0
+}
+
+func formatReservations(reservations []*api.Reservation) string {
+	if len(reservations) == 0 {
+		return "No reservations found"
+	}
+
+	// Sort the output by reservation name
+	sort.Slice(reservations, func(i, j int) bool { return reservations[i].Name < reservations[j].Name })
+
+	rows := make([]string, len(reservations)+1)
+	rows[0] = "Name|Node Pool|Count|CPU|Memory MB|Disk MB|Expires"
+	for i, r := range reservations {
+		var cpu, memory, disk int
+		if r.Resources != nil {
+			cpu, memory, disk = r.Resources.CPU, r.Resources.MemoryMB, r.Resources.DiskMB
+		}
+
+		expires := "<none>"
+		if !r.ExpiresAt.IsZero() {
+			expires = formatTime(r.ExpiresAt)
+			if !time.Now().Before(r.ExpiresAt) {
+				expires += " (expired)"
+			}
+		}
+
+		rows[i+1] = fmt.Sprintf("%s|%s|%d|%d|%d|%d|%s",
+			r.Name, r.NodePool, r.Count, cpu, memory, disk, expires)
+	}
+	return formatList(rows)
+}
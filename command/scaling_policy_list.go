@@ -50,6 +50,12 @@ Policy Info Options:
 
   -t
     Format and display the scaling policy using a Go template.
+
+  -per-page
+    How many results to show per page.
+
+  -page-token
+    Where to start pagination.
 `
 	return strings.TrimSpace(helpText)
 }
@@ -62,11 +68,13 @@ func (s *ScalingPolicyListCommand) Synopsis() string {
 func (s *ScalingPolicyListCommand) AutocompleteFlags() complete.Flags {
 	return mergeAutocompleteFlags(s.Meta.AutocompleteFlags(FlagSetClient),
 		complete.Flags{
-			"-verbose": complete.PredictNothing,
-			"-job":     complete.PredictNothing,
-			"-type":    complete.PredictNothing,
-			"-json":    complete.PredictNothing,
-			"-t":       complete.PredictAnything,
+			"-verbose":    complete.PredictNothing,
+			"-job":        complete.PredictNothing,
+			"-type":       complete.PredictNothing,
+			"-json":       complete.PredictNothing,
+			"-t":          complete.PredictAnything,
+			"-per-page":   complete.PredictAnything,
+			"-page-token": complete.PredictAnything,
 		})
 }
 
@@ -76,7 +84,8 @@ func (s *ScalingPolicyListCommand) Name() string { return "scaling policy list"
 // Run satisfies the cli.Command Run function.
 func (s *ScalingPolicyListCommand) Run(args []string) int {
 	var json, verbose bool
-	var tmpl, policyType, job string
+	var tmpl, policyType, job, pageToken string
+	var perPage int
 
 	flags := s.Meta.FlagSet(s.Name(), FlagSetClient)
 	flags.Usage = func() { s.Ui.Output(s.Help()) }
@@ -85,6 +94,8 @@ func (s *ScalingPolicyListCommand) Run(args []string) int {
 	flags.StringVar(&tmpl, "t", "", "")
 	flags.StringVar(&policyType, "type", "", "")
 	flags.StringVar(&job, "job", "", "")
+	flags.IntVar(&perPage, "per-page", 0, "")
+	flags.StringVar(&pageToken, "page-token", "", "")
 	if err := flags.Parse(args); err != nil {
 		return 1
 	}
@@ -109,7 +120,9 @@ func (s *ScalingPolicyListCommand) Run(args []string) int {
 	}
 
 	q := &api.QueryOptions{
-		Params: map[string]string{},
+		Params:    map[string]string{},
+		PerPage:   int32(perPage),
+		NextToken: pageToken,
 	}
 	if policyType != "" {
 		q.Params["type"] = policyType
@@ -117,7 +130,7 @@ func (s *ScalingPolicyListCommand) Run(args []string) int {
 	if job != "" {
 		q.Params["job"] = job
 	}
-	policies, _, err := client.Scaling().ListPolicies(q)
+	policies, qm, err := client.Scaling().ListPolicies(q)
 	if err != nil {
 		s.Ui.Error(fmt.Sprintf("Error listing scaling policies: %s", err))
 		return 1
@@ -135,6 +148,10 @@ func (s *ScalingPolicyListCommand) Run(args []string) int {
 
 	output := formatScalingPolicies(policies, length)
 	s.Ui.Output(output)
+
+	if qm.NextToken != "" {
+		s.Ui.Output(paginationHint(qm.NextToken))
+	}
 	return 0
 }
 
@@ -0,0 +1,71 @@
+package command
+
+import (
+	"fmt"
+	"strings"
+
+	"github.com/posener/complete"
+)
+
+type ServiceDeleteCommand struct {
+	Meta
+}
+
+func (c *ServiceDeleteCommand) Help() string {
+	helpText := `
+Usage: nomad service delete [options] <service_name> <service_id>
+
+  Delete is used to deregister the specified service registration. It should be
+  used with caution and can only remove a single registration, via the service
+  name and service ID, at a time.
+
+  When ACLs are enabled, this command requires a token with the 'submit-job'
+  capability for the service registration namespace.
+
+General Options:
+
+  ` + generalOptionsUsage(usageOptsDefault)
+
+	return strings.TrimSpace(helpText)
+}
+
+func (c *ServiceDeleteCommand) Name() string { return "service delete" }
+
+func (c *ServiceDeleteCommand) Synopsis() string { return "Deregister a registered service" }
+
+func (c *ServiceDeleteCommand) AutocompleteFlags() complete.Flags {
+	return c.Meta.AutocompleteFlags(FlagSetClient)
+}
+
+func (c *ServiceDeleteCommand) AutocompleteArgs() complete.Predictor {
+	return complete.PredictNothing
+}
+
+func (c *ServiceDeleteCommand) Run(args []string) int {
+	flags := c.Meta.FlagSet(c.Name(), FlagSetClient)
+	flags.Usage = func() { c.Ui.Output(c.Help()) }
+	if err := flags.Parse(args); err != nil {
+		return 1
+	}
+
+	args = flags.Args()
+	if len(args) != 2 {
+		c.Ui.Error("This command takes two arguments: <service_name> and <service_id>")
+		c.Ui.Error(commandErrorText(c))
+		return 1
+	}
+
+	client, err := c.Meta.Client()
+	if err != nil {
+		c.Ui.Error(fmt.Sprintf("Error initializing client: %s", err))
+		return 1
+	}
+
+	if _, err := client.Services().Delete(args[0], args[1], nil); err != nil {
+		c.Ui.Error(fmt.Sprintf("Error deleting service registration: %s", err))
+		return 1
+	}
+
+	c.Ui.Output("Successfully deleted service registration")
+	return 0
+}
@@ -0,0 +1,28 @@
+package command
+
+import (
+	"testing"
+
+	"github.com/mitchellh/cli"
+	"github.com/stretchr/testify/require"
+)
+
+func TestServiceDeleteCommand_Implements(t *testing.T) {
+	t.Parallel()
+	var _ cli.Command = &ServiceDeleteCommand{}
+}
+
+func TestServiceDeleteCommand_Fails(t *testing.T) {
+	t.Parallel()
+	ui := cli.NewMockUi()
+	cmd := &ServiceDeleteCommand{Meta: Meta{Ui: ui}}
+
+	// Fails on misuse
+	require.Equal(t, 1, cmd.Run([]string{"only-one-arg"}))
+	require.Contains(t, ui.ErrorWriter.String(), commandErrorText(cmd))
+	ui.ErrorWriter.Reset()
+
+	// Fails on connection failure
+	require.Equal(t, 1, cmd.Run([]string{"-address=nope", "service", "id"}))
+	require.Contains(t, ui.ErrorWriter.String(), "Error deleting service registration")
+}
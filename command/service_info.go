@@ -0,0 +1,164 @@
+package command
+
+import (
+	"fmt"
+	"sort"
+	"strings"
+
+	"github.com/hashicorp/nomad/api"
+	"github.com/posener/complete"
+)
+
+type ServiceInfoCommand struct {
+	Meta
+}
+
+func (c *ServiceInfoCommand) Help() string {
+	helpText := `
+Usage: nomad service info [options] <service_name>
+
+  Info is used to read the services registered to a single service name.
+
+  If ACLs are enabled, this command requires a token with the 'read-job'
+  capability for the service namespace.
+
+General Options:
+
+  ` + generalOptionsUsage(usageOptsDefault) + `
+
+Service Info Options:
+
+  -json
+    Output the service in JSON format.
+
+  -t
+    Format and display the service using a Go template.
+
+  -verbose
+    Display full information including the full service and allocation IDs.
+`
+	return strings.TrimSpace(helpText)
+}
+
+func (c *ServiceInfoCommand) Synopsis() string {
+	return "Display an individual Nomad service registration"
+}
+
+func (c *ServiceInfoCommand) AutocompleteFlags() complete.Flags {
+	return mergeAutocompleteFlags(c.Meta.AutocompleteFlags(FlagSetClient),
+		complete.Flags{
+			"-json":    complete.PredictNothing,
+			"-t":       complete.PredictAnything,
+			"-verbose": complete.PredictNothing,
+		})
+}
+
+func (c *ServiceInfoCommand) AutocompleteArgs() complete.Predictor {
+	return complete.PredictNothing
+}
+
+func (c *ServiceInfoCommand) Name() string { return "service info" }
+
+func (c *ServiceInfoCommand) Run(args []string) int {
+	var (
+		json, verbose bool
+		tmpl          string
+	)
+
+	flags := c.Meta.FlagSet(c.Name(), FlagSetClient)
+	flags.Usage = func() { c.Ui.Output(c.Help()) }
+	flags.BoolVar(&json, "json", false, "")
+	flags.BoolVar(&verbose, "verbose", false, "")
+	flags.StringVar(&tmpl, "t", "", "")
+	if err := flags.Parse(args); err != nil {
+		return 1
+	}
+
+	// Check that we got exactly one argument
+	args = flags.Args()
+	if len(args) != 1 {
+		c.Ui.Error("This command takes one argument: <service_name>")
+		c.Ui.Error(commandErrorText(c))
+		return 1
+	}
+
+	client, err := c.Meta.Client()
+	if err != nil {
+		c.Ui.Error(fmt.Sprintf("Error initializing client: %s", err))
+		return 1
+	}
+
+	serviceInfo, _, err := client.Services().Get(args[0], nil)
+	if err != nil {
+		c.Ui.Error(fmt.Sprintf("Error listing service registrations: %s", err))
+		return 1
+	}
+
+	if len(serviceInfo) == 0 {
+		c.Ui.Output("No service registrations found")
+		return 0
+	}
+
+	if json || len(tmpl) > 0 {
+		out, err := Format(json, tmpl, serviceInfo)
+		if err != nil {
+			c.Ui.Error(err.Error())
+			return 1
+		}
+		c.Ui.Output(out)
+		return 0
+	}
+
+	// It is possible for multiple registrations to exist for a single service
+	// name, so sort them by allocation to ensure a consistent output.
+	sort.Slice(serviceInfo, func(i, j int) bool {
+		return serviceInfo[i].AllocID < serviceInfo[j].AllocID
+	})
+
+	if verbose {
+		c.formatVerboseOutput(serviceInfo)
+	} else {
+		c.formatOutput(serviceInfo)
+	}
+	return 0
+}
+
+// formatOutput produces the non-verbose output of service registration info
+// for a specific service by its name.
+func (c *ServiceInfoCommand) formatOutput(regs []*api.ServiceRegistration) {
+	length := shortId
+	out := make([]string, len(regs)+1)
+	out[0] = "Job ID|Address|Tags|Node ID|Alloc ID"
+	for i, reg := range regs {
+		out[i+1] = fmt.Sprintf("%s|%s|[%s]|%s|%s",
+			reg.JobID,
+			fmt.Sprintf("%s:%v", reg.Address, reg.Port),
+			strings.Join(reg.Tags, ","),
+			limit(reg.NodeID, length),
+			limit(reg.AllocID, length),
+		)
+	}
+	c.Ui.Output(formatList(out))
+}
+
+// formatVerboseOutput produces the verbose output of service registration info
+// for a specific service by its name.
+func (c *ServiceInfoCommand) formatVerboseOutput(regs []*api.ServiceRegistration) {
+	for i, reg := range regs {
+		out := []string{
+			fmt.Sprintf("ID|%s", reg.ID),
+			fmt.Sprintf("Service Name|%s", reg.ServiceName),
+			fmt.Sprintf("Namespace|%s", reg.Namespace),
+			fmt.Sprintf("Job ID|%s", reg.JobID),
+			fmt.Sprintf("Alloc ID|%s", reg.AllocID),
+			fmt.Sprintf("Node ID|%s", reg.NodeID),
+			fmt.Sprintf("Datacenter|%s", reg.Datacenter),
+			fmt.Sprintf("Address|%s", fmt.Sprintf("%s:%v", reg.Address, reg.Port)),
+			fmt.Sprintf("Tags|[%s]", strings.Join(reg.Tags, ",")),
+		}
+		c.Ui.Output(formatKV(out))
+		if i < len(regs)-1 {
+			c.Ui.Output("")
+		}
+	}
+}
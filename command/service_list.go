@@ -0,0 +1,143 @@
+package command
+
+import (
+	"fmt"
+	"sort"
+	"strings"
+
+	"github.com/hashicorp/nomad/api"
+	"github.com/posener/complete"
+)
+
+type ServiceListCommand struct {
+	Meta
+}
+
+func (c *ServiceListCommand) Help() string {
+	helpText := `
+Usage: nomad service list [options]
+
+  List is used to list the currently registered services.
+
+  If ACLs are enabled, this command requires a token with the 'read-job'
+  capabilities for the namespace of all services. Any namespaces that the token
+  does not have access to will have its services filtered from the results.
+
+General Options:
+
+  ` + generalOptionsUsage(usageOptsDefault) + `
+
+Service List Options:
+
+  -json
+    Output the services in JSON format.
+
+  -t
+    Format and display the services using a Go template.
+`
+	return strings.TrimSpace(helpText)
+}
+
+func (c *ServiceListCommand) Synopsis() string {
+	return "Display all registered Nomad services"
+}
+
+func (c *ServiceListCommand) AutocompleteFlags() complete.Flags {
+	return mergeAutocompleteFlags(c.Meta.AutocompleteFlags(FlagSetClient),
+		complete.Flags{
+			"-json": complete.PredictNothing,
+			"-t":    complete.PredictAnything,
+		})
+}
+
+func (c *ServiceListCommand) AutocompleteArgs() complete.Predictor {
+	return complete.PredictNothing
+}
+
+func (c *ServiceListCommand) Name() string { return "service list" }
+
+func (c *ServiceListCommand) Run(args []string) int {
+	var (
+		json bool
+		tmpl string
+	)
+
+	flags := c.Meta.FlagSet(c.Name(), FlagSetClient)
+	flags.Usage = func() { c.Ui.Output(c.Help()) }
+	flags.BoolVar(&json, "json", false, "")
+	flags.StringVar(&tmpl, "t", "", "")
+	if err := flags.Parse(args); err != nil {
+		return 1
+	}
+
+	if len(flags.Args()) != 0 {
+		c.Ui.Error("This command takes no arguments")
+		c.Ui.Error(commandErrorText(c))
+		return 1
+	}
+
+	client, err := c.Meta.Client()
+	if err != nil {
+		c.Ui.Error(fmt.Sprintf("Error initializing client: %s", err))
+		return 1
+	}
+
+	list, _, err := client.Services().List(nil)
+	if err != nil {
+		c.Ui.Error(fmt.Sprintf("Error retrieving services: %s", err))
+		return 1
+	}
+
+	if json || len(tmpl) > 0 {
+		out, err := Format(json, tmpl, list)
+		if err != nil {
+			c.Ui.Error(err.Error())
+			return 1
+		}
+		c.Ui.Output(out)
+		return 0
+	}
+
+	c.Ui.Output(formatServiceListOutput(list))
+	return 0
+}
+
+// formatServiceListOutput flattens the namespaced service stubs into a
+// tabular output sorted by namespace and then service name.
+func formatServiceListOutput(list []*api.ServiceRegistrationListStub) string {
+	type serviceRow struct {
+		namespace string
+		name      string
+		tags      []string
+	}
+
+	var rows []serviceRow
+	for _, nsServices := range list {
+		for _, service := range nsServices.Services {
+			rows = append(rows, serviceRow{
+				namespace: nsServices.Namespace,
+				name:      service.ServiceName,
+				tags:      service.Tags,
+			})
+		}
+	}
+
+	if len(rows) == 0 {
+		return "No services found"
+	}
+
+	sort.Slice(rows, func(i, j int) bool {
+		if rows[i].namespace != rows[j].namespace {
+			return rows[i].namespace < rows[j].namespace
+		}
+		return rows[i].name < rows[j].name
+	})
+
+	out := make([]string, len(rows)+1)
+	out[0] = "Service Name|Namespace|Tags"
+	for i, row := range rows {
+		out[i+1] = fmt.Sprintf("%s|%s|%s",
+			row.name, row.namespace, strings.Join(row.tags, ","))
+	}
+	return formatList(out)
+}
@@ -0,0 +1,53 @@
+package command
+
+import (
+	"testing"
+
+	"github.com/hashicorp/nomad/api"
+	"github.com/mitchellh/cli"
+	"github.com/stretchr/testify/require"
+)
+
+func TestServiceListCommand_Implements(t *testing.T) {
+	t.Parallel()
+	var _ cli.Command = &ServiceListCommand{}
+}
+
+func TestServiceListCommand_Fails(t *testing.T) {
+	t.Parallel()
+	ui := cli.NewMockUi()
+	cmd := &ServiceListCommand{Meta: Meta{Ui: ui}}
+
+	// Fails on misuse
+	require.Equal(t, 1, cmd.Run([]string{"some", "bad", "args"}))
+	require.Contains(t, ui.ErrorWriter.String(), commandErrorText(cmd))
+	ui.ErrorWriter.Reset()
+
+	// Fails on connection failure
+	require.Equal(t, 1, cmd.Run([]string{"-address=nope"}))
+	require.Contains(t, ui.ErrorWriter.String(), "Error retrieving services")
+}
+
+func TestServiceListCommand_formatServiceListOutput(t *testing.T) {
+	t.Parallel()
+
+	require.Equal(t, "No services found", formatServiceListOutput(nil))
+
+	out := formatServiceListOutput([]*api.ServiceRegistrationListStub{
+		{
+			Namespace: "platform",
+			Services: []*api.ServiceRegistrationStub{
+				{ServiceName: "countdash-api", Tags: []string{"bar"}},
+			},
+		},
+		{
+			Namespace: "default",
+			Services: []*api.ServiceRegistrationStub{
+				{ServiceName: "example-cache", Tags: []string{"foo", "baz"}},
+			},
+		},
+	})
+	require.Contains(t, out, "Service Name")
+	require.Regexp(t, `example-cache\s+default\s+foo,baz`, out)
+	require.Regexp(t, `countdash-api\s+platform\s+bar`, out)
+}
@@ -0,0 +1,52 @@
+package command
+
+import (
+	"strings"
+
+	"github.com/mitchellh/cli"
+)
+
+type VarCommand struct {
+	Meta
+}
+
+func (c *VarCommand) Help() string {
+	helpText := `
+Usage: nomad var <subcommand> [options] [args]
+
+  This command groups subcommands for interacting with variables. Variables
+  allow operators to provide credentials and otherwise sensitive material to
+  Nomad jobs at runtime via the template block or directly through the Nomad
+  API and CLI. The items of a variable are encrypted by the servers before
+  being written to storage.
+
+  Users can create new variables; list, inspect, and delete existing
+  variables, and more. For a full guide please see:
+  https://www.nomadproject.io/docs/commands/var
+
+  Create or update the variable at path "secret/creds":
+
+      $ nomad var put secret/creds username=admin password=hunter2
+
+  Read the variable at path "secret/creds":
+
+      $ nomad var get secret/creds
+
+  List variables under the "secret" prefix:
+
+      $ nomad var list secret
+
+  Purge the variable at path "secret/creds":
+
+      $ nomad var purge secret/creds
+
+  Please see the individual subcommand help for detailed usage information.
+`
+	return strings.TrimSpace(helpText)
+}
+
+func (c *VarCommand) Name() string { return "var" }
+
+func (c *VarCommand) Synopsis() string { return "Interact with variables" }
+
+func (c *VarCommand) Run(_ []string) int { return cli.RunResultHelp }
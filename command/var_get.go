@@ -0,0 +1,160 @@
+package command
+
+import (
+	"fmt"
+	"sort"
+	"strings"
+
+	"github.com/hashicorp/nomad/api"
+	"github.com/posener/complete"
+)
+
+type VarGetCommand struct {
+	Meta
+}
+
+func (c *VarGetCommand) Help() string {
+	helpText := `
+Usage: nomad var get [options] <path>
+
+  Get is used to read the contents of an existing variable.
+
+  If ACLs are enabled, this command requires a token with the 'variables-read'
+  capability for the target variable's namespace.
+
+General Options:
+
+  ` + generalOptionsUsage(usageOptsDefault) + `
+
+Get Options:
+
+  -item <key>
+    Print only the value of the given item, without a trailing newline.
+
+  -json
+    Output the variable in JSON format.
+
+  -t
+    Format and display the variable using a Go template.
+`
+	return strings.TrimSpace(helpText)
+}
+
+func (c *VarGetCommand) Synopsis() string {
+	return "Read a variable"
+}
+
+func (c *VarGetCommand) AutocompleteFlags() complete.Flags {
+	return mergeAutocompleteFlags(c.Meta.AutocompleteFlags(FlagSetClient),
+		complete.Flags{
+			"-item": complete.PredictAnything,
+			"-json": complete.PredictNothing,
+			"-t":    complete.PredictAnything,
+		})
+}
+
+func (c *VarGetCommand) AutocompleteArgs() complete.Predictor {
+	return complete.PredictNothing
+}
+
+func (c *VarGetCommand) Name() string { return "var get" }
+
+func (c *VarGetCommand) Run(args []string) int {
+	var (
+		json bool
+		tmpl string
+		item string
+	)
+
+	flags := c.Meta.FlagSet(c.Name(), FlagSetClient)
+	flags.Usage = func() { c.Ui.Output(c.Help()) }
+	flags.BoolVar(&json, "json", false, "")
+	flags.StringVar(&tmpl, "t", "", "")
+	flags.StringVar(&item, "item", "", "")
+	if err := flags.Parse(args); err != nil {
+		return 1
+	}
+
+	args = flags.Args()
+	if len(args) != 1 {
+		c.Ui.Error("This command takes one argument: <path>")
+		c.Ui.Error(commandErrorText(c))
+		return 1
+	}
+
+	if item != "" && (json || tmpl != "") {
+		c.Ui.Error("The -item flag can not be used with the -json or -t flags")
+		c.Ui.Error(commandErrorText(c))
+		return 1
+	}
+
+	client, err := c.Meta.Client()
+	if err != nil {
+		c.Ui.Error(fmt.Sprintf("Error initializing client: %s", err))
+		return 1
+	}
+
+	variable, _, err := client.Variables().Read(args[0], nil)
+	if err != nil {
+		if err == api.ErrVariableNotFound {
+			c.Ui.Error(fmt.Sprintf("Variable %q not found", args[0]))
+		} else {
+			c.Ui.Error(fmt.Sprintf("Error retrieving variable: %s", err))
+		}
+		return 1
+	}
+
+	if item != "" {
+		value, ok := variable.Items[item]
+		if !ok {
+			c.Ui.Error(fmt.Sprintf("Variable %q has no item %q", args[0], item))
+			return 1
+		}
+		// Use Output so that the value can be piped without any
+		// decoration.
+		c.Ui.Output(value)
+		return 0
+	}
+
+	if json || len(tmpl) > 0 {
+		out, err := Format(json, tmpl, variable)
+		if err != nil {
+			c.Ui.Error(err.Error())
+			return 1
+		}
+		c.Ui.Output(out)
+		return 0
+	}
+
+	c.Ui.Output(c.Colorize().Color(formatVariable(variable)))
+	return 0
+}
+
+// formatVariable returns the metadata and items of the variable in a
+// human-readable format.
+func formatVariable(v *api.Variable) string {
+	basic := []string{
+		fmt.Sprintf("Namespace|%s", v.Namespace),
+		fmt.Sprintf("Path|%s", v.Path),
+		fmt.Sprintf("Create Time|%s", formatUnixNanoTime(v.CreateTime)),
+	}
+	if v.ModifyTime != v.CreateTime {
+		basic = append(basic, fmt.Sprintf("Modify Time|%s", formatUnixNanoTime(v.ModifyTime)))
+	}
+	basic = append(basic,
+		fmt.Sprintf("Check Index|%d", v.ModifyIndex),
+	)
+
+	keys := make([]string, 0, len(v.Items))
+	for k := range v.Items {
+		keys = append(keys, k)
+	}
+	sort.Strings(keys)
+
+	items := make([]string, 0, len(keys))
+	for _, k := range keys {
+		items = append(items, fmt.Sprintf("%s|%s", k, v.Items[k]))
+	}
+
+	return formatKV(basic) + "\n\n[bold]Items[reset]\n" + formatKV(items)
+}
@@ -0,0 +1,126 @@
+package command
+
+import (
+	"fmt"
+	"strings"
+
+	"github.com/hashicorp/nomad/api"
+	"github.com/posener/complete"
+)
+
+type VarListCommand struct {
+	Meta
+}
+
+func (c *VarListCommand) Help() string {
+	helpText := `
+Usage: nomad var list [options] [<prefix>]
+
+  List is used to list the variables whose path begins with the given prefix,
+  or all variables if no prefix is given. Only the metadata of the variables is
+  returned.
+
+  If ACLs are enabled, this command requires a token with the 'variables-read'
+  capability for the namespace of the variables. When listing with the
+  wildcard namespace, any namespaces that the token does not have access to
+  will have their variables filtered from the results.
+
+General Options:
+
+  ` + generalOptionsUsage(usageOptsDefault) + `
+
+List Options:
+
+  -json
+    Output the variables in JSON format.
+
+  -t
+    Format and display the variables using a Go template.
+`
+	return strings.TrimSpace(helpText)
+}
+
+func (c *VarListCommand) Synopsis() string {
+	return "List variable metadata"
+}
+
+func (c *VarListCommand) AutocompleteFlags() complete.Flags {
+	return mergeAutocompleteFlags(c.Meta.AutocompleteFlags(FlagSetClient),
+		complete.Flags{
+			"-json": complete.PredictNothing,
+			"-t":    complete.PredictAnything,
+		})
+}
+
+func (c *VarListCommand) AutocompleteArgs() complete.Predictor {
+	return complete.PredictNothing
+}
+
+func (c *VarListCommand) Name() string { return "var list" }
+
+func (c *VarListCommand) Run(args []string) int {
+	var (
+		json bool
+		tmpl string
+	)
+
+	flags := c.Meta.FlagSet(c.Name(), FlagSetClient)
+	flags.Usage = func() { c.Ui.Output(c.Help()) }
+	flags.BoolVar(&json, "json", false, "")
+	flags.StringVar(&tmpl, "t", "", "")
+	if err := flags.Parse(args); err != nil {
+		return 1
+	}
+
+	args = flags.Args()
+	if len(args) > 1 {
+		c.Ui.Error("This command takes zero or one argument: [<prefix>]")
+		c.Ui.Error(commandErrorText(c))
+		return 1
+	}
+
+	var prefix string
+	if len(args) == 1 {
+		prefix = args[0]
+	}
+
+	client, err := c.Meta.Client()
+	if err != nil {
+		c.Ui.Error(fmt.Sprintf("Error initializing client: %s", err))
+		return 1
+	}
+
+	vars, _, err := client.Variables().PrefixList(prefix, nil)
+	if err != nil {
+		c.Ui.Error(fmt.Sprintf("Error retrieving variables: %s", err))
+		return 1
+	}
+
+	if json || len(tmpl) > 0 {
+		out, err := Format(json, tmpl, vars)
+		if err != nil {
+			c.Ui.Error(err.Error())
+			return 1
+		}
+		c.Ui.Output(out)
+		return 0
+	}
+
+	c.Ui.Output(formatVariableList(vars))
+	return 0
+}
+
+// formatVariableList returns the variable metadata as a table.
+func formatVariableList(vars []*api.VariableMetadata) string {
+	if len(vars) == 0 {
+		return "No variables found"
+	}
+
+	out := make([]string, len(vars)+1)
+	out[0] = "Namespace|Path|Last Updated"
+	for i, v := range vars {
+		out[i+1] = fmt.Sprintf("%s|%s|%s",
+			v.Namespace, v.Path, formatUnixNanoTime(v.ModifyTime))
+	}
+	return formatList(out)
+}
@@ -0,0 +1,106 @@
+package command
+
+import (
+	"errors"
+	"flag"
+	"fmt"
+	"strings"
+
+	"github.com/hashicorp/nomad/api"
+	"github.com/posener/complete"
+)
+
+type VarPurgeCommand struct {
+	Meta
+}
+
+func (c *VarPurgeCommand) Help() string {
+	helpText := `
+Usage: nomad var purge [options] <path>
+
+  Purge is used to permanently delete an existing variable.
+
+  If ACLs are enabled, this command requires a token with the 'variables-write'
+  capability for the target variable's namespace.
+
+General Options:
+
+  ` + generalOptionsUsage(usageOptsDefault) + `
+
+Purge Options:
+
+  -check-index
+    If set, the variable is only purged if the server side version's modify
+    index matches the provided value.
+`
+	return strings.TrimSpace(helpText)
+}
+
+func (c *VarPurgeCommand) Synopsis() string {
+	return "Purge a variable"
+}
+
+func (c *VarPurgeCommand) AutocompleteFlags() complete.Flags {
+	return mergeAutocompleteFlags(c.Meta.AutocompleteFlags(FlagSetClient),
+		complete.Flags{
+			"-check-index": complete.PredictAnything,
+		})
+}
+
+func (c *VarPurgeCommand) AutocompleteArgs() complete.Predictor {
+	return complete.PredictNothing
+}
+
+func (c *VarPurgeCommand) Name() string { return "var purge" }
+
+func (c *VarPurgeCommand) Run(args []string) int {
+	var checkIndex uint64
+
+	flags := c.Meta.FlagSet(c.Name(), FlagSetClient)
+	flags.Usage = func() { c.Ui.Output(c.Help()) }
+	flags.Uint64Var(&checkIndex, "check-index", 0, "")
+	if err := flags.Parse(args); err != nil {
+		return 1
+	}
+
+	// Track whether the flag was set, since 0 is a meaningful index.
+	var isCAS bool
+	flags.Visit(func(f *flag.Flag) {
+		if f.Name == "check-index" {
+			isCAS = true
+		}
+	})
+
+	args = flags.Args()
+	if len(args) != 1 {
+		c.Ui.Error("This command takes one argument: <path>")
+		c.Ui.Error(commandErrorText(c))
+		return 1
+	}
+	path := args[0]
+
+	client, err := c.Meta.Client()
+	if err != nil {
+		c.Ui.Error(fmt.Sprintf("Error initializing client: %s", err))
+		return 1
+	}
+
+	if isCAS {
+		_, err = client.Variables().CheckedDelete(path, checkIndex, nil)
+	} else {
+		_, err = client.Variables().Delete(path, nil)
+	}
+	if err != nil {
+		var casErr api.ErrCASConflict
+		if errors.As(err, &casErr) {
+			c.Ui.Error(fmt.Sprintf("Check-and-Set conflict: the variable was modified at index %d",
+				casErr.Conflict.ModifyIndex))
+			return 1
+		}
+		c.Ui.Error(fmt.Sprintf("Error purging variable: %s", err))
+		return 1
+	}
+
+	c.Ui.Output(fmt.Sprintf("Successfully purged variable %q", path))
+	return 0
+}
@@ -0,0 +1,154 @@
+package command
+
+import (
+	"errors"
+	"flag"
+	"fmt"
+	"strings"
+
+	"github.com/hashicorp/nomad/api"
+	"github.com/posener/complete"
+)
+
+type VarPutCommand struct {
+	Meta
+}
+
+func (c *VarPutCommand) Help() string {
+	helpText := `
+Usage: nomad var put [options] <path> <key>=<value> [<key>=<value>...]
+
+  Put is used to create or update a variable. The items given replace all of
+  the existing items of the variable.
+
+  If ACLs are enabled, this command requires a token with the 'variables-write'
+  capability for the target variable's namespace.
+
+General Options:
+
+  ` + generalOptionsUsage(usageOptsDefault) + `
+
+Put Options:
+
+  -check-index
+    If set, the variable is only written if the server side version's modify
+    index matches the provided value. A value of 0 only writes the variable
+    if it does not already exist.
+
+  -json
+    Output the written variable in JSON format.
+
+  -t
+    Format and display the written variable using a Go template.
+`
+	return strings.TrimSpace(helpText)
+}
+
+func (c *VarPutCommand) Synopsis() string {
+	return "Create or update a variable"
+}
+
+func (c *VarPutCommand) AutocompleteFlags() complete.Flags {
+	return mergeAutocompleteFlags(c.Meta.AutocompleteFlags(FlagSetClient),
+		complete.Flags{
+			"-check-index": complete.PredictAnything,
+			"-json":        complete.PredictNothing,
+			"-t":           complete.PredictAnything,
+		})
+}
+
+func (c *VarPutCommand) AutocompleteArgs() complete.Predictor {
+	return complete.PredictNothing
+}
+
+func (c *VarPutCommand) Name() string { return "var put" }
+
+func (c *VarPutCommand) Run(args []string) int {
+	var (
+		json       bool
+		tmpl       string
+		checkIndex uint64
+	)
+
+	flags := c.Meta.FlagSet(c.Name(), FlagSetClient)
+	flags.Usage = func() { c.Ui.Output(c.Help()) }
+	flags.BoolVar(&json, "json", false, "")
+	flags.StringVar(&tmpl, "t", "", "")
+	flags.Uint64Var(&checkIndex, "check-index", 0, "")
+	if err := flags.Parse(args); err != nil {
+		return 1
+	}
+
+	// Track whether the flag was set, since 0 is a meaningful index.
+	var isCAS bool
+	flags.Visit(func(f *flag.Flag) {
+		if f.Name == "check-index" {
+			isCAS = true
+		}
+	})
+
+	args = flags.Args()
+	if len(args) < 2 {
+		c.Ui.Error("This command takes at least two arguments: <path> and <key>=<value>")
+		c.Ui.Error(commandErrorText(c))
+		return 1
+	}
+
+	variable := api.NewVariable(args[0])
+	for _, arg := range args[1:] {
+		key, value, err := parseVarItem(arg)
+		if err != nil {
+			c.Ui.Error(err.Error())
+			c.Ui.Error(commandErrorText(c))
+			return 1
+		}
+		variable.Items[key] = value
+	}
+
+	client, err := c.Meta.Client()
+	if err != nil {
+		c.Ui.Error(fmt.Sprintf("Error initializing client: %s", err))
+		return 1
+	}
+
+	var out *api.Variable
+	if isCAS {
+		variable.ModifyIndex = checkIndex
+		out, _, err = client.Variables().CheckedUpdate(variable, nil)
+	} else {
+		out, _, err = client.Variables().Create(variable, nil)
+	}
+	if err != nil {
+		var casErr api.ErrCASConflict
+		if errors.As(err, &casErr) {
+			c.Ui.Error(fmt.Sprintf("Check-and-Set conflict: the variable was modified at index %d",
+				casErr.Conflict.ModifyIndex))
+			return 1
+		}
+		c.Ui.Error(fmt.Sprintf("Error creating variable: %s", err))
+		return 1
+	}
+
+	if json || len(tmpl) > 0 {
+		formatted, err := Format(json, tmpl, out)
+		if err != nil {
+			c.Ui.Error(err.Error())
+			return 1
+		}
+		c.Ui.Output(formatted)
+		return 0
+	}
+
+	c.Ui.Output(fmt.Sprintf("Successfully wrote variable %q at index %d", out.Path, out.ModifyIndex))
+	return 0
+}
+
+// parseVarItem splits a "key=value" argument into its key and value. Only
+// the first "=" is used as the separator, so values may contain "=".
+func parseVarItem(arg string) (string, string, error) {
+	parts := strings.SplitN(arg, "=", 2)
+	if len(parts) != 2 || parts[0] == "" {
+		return "", "", fmt.Errorf("Invalid item %q: items must be given as <key>=<value>", arg)
+	}
+	return parts[0], parts[1], nil
+}
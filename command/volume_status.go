@@ -15,6 +15,7 @@ type VolumeStatusCommand struct {
 	verbose  bool
 	json     bool
 	template string
+	filter   string
 }
 
 func (c *VolumeStatusCommand) Help() string {
@@ -49,6 +50,10 @@ Status Options:
 
   -t
     Format and display allocation using a Go template.
+
+  -filter
+    Specifies an expression used to filter the volumes listed when no volume
+    ID is given.
 `
 	return strings.TrimSpace(helpText)
 }
@@ -65,6 +70,7 @@ func (c *VolumeStatusCommand) AutocompleteFlags() complete.Flags {
 			"-verbose": complete.PredictNothing,
 			"-json":    complete.PredictNothing,
 			"-t":       complete.PredictAnything,
+			"-filter":  complete.PredictAnything,
 		})
 }
 
@@ -95,6 +101,7 @@ func (c *VolumeStatusCommand) Run(args []string) int {
 	flags.BoolVar(&c.verbose, "verbose", false, "")
 	flags.BoolVar(&c.json, "json", false, "")
 	flags.StringVar(&c.template, "t", "", "")
+	flags.StringVar(&c.filter, "filter", "", "")
 
 	if err := flags.Parse(args); err != nil {
 		c.Ui.Error(fmt.Sprintf("Error parsing arguments %s", err))
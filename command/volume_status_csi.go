@@ -64,7 +64,7 @@ func (c *VolumeStatusCommand) csiStatus(client *api.Client, id string) int {
 func (c *VolumeStatusCommand) listVolumes(client *api.Client) int {
 
 	c.csiBanner()
-	vols, _, err := client.CSIVolumes().List(nil)
+	vols, _, err := client.CSIVolumes().List(&api.QueryOptions{Filter: c.filter})
 	if err != nil {
 		c.Ui.Error(fmt.Sprintf("Error querying volumes: %s", err))
 		return 1
@@ -2,12 +2,15 @@ package raftutil
 
 import (
 	"fmt"
+	"io"
 	"os"
 	"path/filepath"
 
 	"github.com/hashicorp/go-hclog"
 	"github.com/hashicorp/go-memdb"
+	"github.com/hashicorp/nomad/helper/snapshot"
 	"github.com/hashicorp/nomad/nomad"
+	"github.com/hashicorp/nomad/nomad/state"
 	"github.com/hashicorp/raft"
 )
 
@@ -26,22 +29,7 @@ func FSMState(p string, plastIdx int64) (interface{}, error) {
 
 	logger := hclog.L()
 
-	// use dummy non-enabled FSM dependencies
-	periodicDispatch := nomad.NewPeriodicDispatch(logger, nil)
-	blockedEvals := nomad.NewBlockedEvals(nil, logger)
-	evalBroker, err := nomad.NewEvalBroker(1, 1, 1, 1)
-	if err != nil {
-		return nil, err
-	}
-	fsmConfig := &nomad.FSMConfig{
-		EvalBroker: evalBroker,
-		Periodic:   periodicDispatch,
-		Blocked:    blockedEvals,
-		Logger:     logger,
-		Region:     "default",
-	}
-
-	fsm, err := nomad.NewFSM(fsmConfig)
+	fsm, err := dummyFSM(logger)
 	if err != nil {
 		return nil, err
 	}
@@ -72,32 +60,92 @@ func FSMState(p string, plastIdx int64) (interface{}, error) {
 		}
 	}
 
-	state := fsm.State()
+	fsmState := fsm.State()
 	result := map[string][]interface{}{
-		"ACLPolicies":      toArray(state.ACLPolicies(nil)),
-		"ACLTokens":        toArray(state.ACLTokens(nil)),
-		"Allocs":           toArray(state.Allocs(nil)),
-		"CSIPlugins":       toArray(state.CSIPlugins(nil)),
-		"CSIVolumes":       toArray(state.CSIVolumes(nil)),
-		"Deployments":      toArray(state.Deployments(nil)),
-		"Evals":            toArray(state.Evals(nil)),
-		"Indexes":          toArray(state.Indexes()),
-		"JobSummaries":     toArray(state.JobSummaries(nil)),
-		"JobVersions":      toArray(state.JobVersions(nil)),
-		"Jobs":             toArray(state.Jobs(nil)),
-		"Nodes":            toArray(state.Nodes(nil)),
-		"PeriodicLaunches": toArray(state.PeriodicLaunches(nil)),
-		"SITokenAccessors": toArray(state.SITokenAccessors(nil)),
-		"ScalingEvents":    toArray(state.ScalingEvents(nil)),
-		"ScalingPolicies":  toArray(state.ScalingPolicies(nil)),
-		"VaultAccessors":   toArray(state.VaultAccessors(nil)),
-	}
-
-	insertEnterpriseState(result, state)
+		"ACLPolicies":      toArray(fsmState.ACLPolicies(nil)),
+		"ACLTokens":        toArray(fsmState.ACLTokens(nil, state.SortDefault)),
+		"Allocs":           toArray(fsmState.Allocs(nil, state.SortDefault)),
+		"CSIPlugins":       toArray(fsmState.CSIPlugins(nil)),
+		"CSIVolumes":       toArray(fsmState.CSIVolumes(nil)),
+		"Deployments":      toArray(fsmState.Deployments(nil)),
+		"Evals":            toArray(fsmState.Evals(nil, state.SortDefault)),
+		"Indexes":          toArray(fsmState.Indexes()),
+		"JobSummaries":     toArray(fsmState.JobSummaries(nil)),
+		"JobVersions":      toArray(fsmState.JobVersions(nil)),
+		"Jobs":             toArray(fsmState.Jobs(nil, state.SortDefault)),
+		"Nodes":            toArray(fsmState.Nodes(nil)),
+		"PeriodicLaunches": toArray(fsmState.PeriodicLaunches(nil)),
+		"SITokenAccessors": toArray(fsmState.SITokenAccessors(nil)),
+		"ScalingEvents":    toArray(fsmState.ScalingEvents(nil)),
+		"ScalingPolicies":  toArray(fsmState.ScalingPolicies(nil, state.SortDefault)),
+		"VaultAccessors":   toArray(fsmState.VaultAccessors(nil)),
+	}
+
+	insertEnterpriseState(result, fsmState)
 
 	return result, nil
 }
 
+// RestoreFromArchive returns the state store obtained by restoring the
+// snapshot archive written by "nomad operator snapshot save".
+func RestoreFromArchive(archive io.Reader) (*state.StateStore, *raft.SnapshotMeta, error) {
+	logger := hclog.L()
+
+	fsm, err := dummyFSM(logger)
+	if err != nil {
+		return nil, nil, err
+	}
+
+	// Stream the raft snapshot data out of the archive into the FSM. The
+	// FSM closes the reader when it's done, which unblocks the writer if
+	// the restore fails early.
+	r, w := io.Pipe()
+	var meta *raft.SnapshotMeta
+	errCh := make(chan error, 1)
+	go func() {
+		var err error
+		meta, err = snapshot.CopySnapshot(archive, w)
+		w.CloseWithError(err)
+		errCh <- err
+	}()
+
+	if err := fsm.Restore(r); err != nil {
+		return nil, nil, fmt.Errorf("failed to restore snapshot: %v", err)
+	}
+	if err := <-errCh; err != nil {
+		return nil, nil, err
+	}
+
+	return fsm.State(), meta, nil
+}
+
+// nomadFSM is the subset of the server FSM used to inspect state.
+type nomadFSM interface {
+	raft.FSM
+	State() *state.StateStore
+}
+
+// dummyFSM returns a server FSM with non-enabled dependencies.
+func dummyFSM(logger hclog.Logger) (nomadFSM, error) {
+	periodicDispatch := nomad.NewPeriodicDispatch(logger, nil)
+	blockedEvals := nomad.NewBlockedEvals(nil, logger)
+	waitingEvals := nomad.NewWaitingEvals(nil, logger)
+	evalBroker, err := nomad.NewEvalBroker(1, 1, 1, 1)
+	if err != nil {
+		return nil, err
+	}
+	fsmConfig := &nomad.FSMConfig{
+		EvalBroker: evalBroker,
+		Periodic:   periodicDispatch,
+		Blocked:    blockedEvals,
+		Waiting:    waitingEvals,
+		Logger:     logger,
+		Region:     "default",
+	}
+
+	return nomad.NewFSM(fsmConfig)
+}
+
 func restoreFromSnapshot(fsm raft.FSM, snaps raft.SnapshotStore, logger hclog.Logger) (uint64, error) {
 	logger = logger.Named("restoreFromSnapshot")
 	snapshots, err := snaps.List()
@@ -160,6 +160,29 @@ func Verify(in io.Reader) (*raft.SnapshotMeta, error) {
 	return &metadata, nil
 }
 
+// CopySnapshot takes the snapshot from the reader, verifies its contents and
+// writes the raft snapshot data it contains to the writer.
+func CopySnapshot(in io.Reader, dest io.Writer) (*raft.SnapshotMeta, error) {
+	// Wrap the reader in a gzip decompressor.
+	decomp, err := gzip.NewReader(in)
+	if err != nil {
+		return nil, fmt.Errorf("failed to decompress snapshot: %v", err)
+	}
+	defer decomp.Close()
+
+	// Read the archive.
+	var metadata raft.SnapshotMeta
+	if err := read(decomp, &metadata, dest); err != nil {
+		return nil, fmt.Errorf("failed to read snapshot file: %v", err)
+	}
+
+	if err := concludeGzipRead(decomp); err != nil {
+		return nil, err
+	}
+
+	return &metadata, nil
+}
+
 // concludeGzipRead should be invoked after you think you've consumed all of
 // the data from the gzip stream. It will error if the stream was corrupt.
 //
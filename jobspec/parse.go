@@ -298,6 +298,36 @@ func parseAffinities(result *[]*api.Affinity, list *ast.ObjectList) error {
 	return nil
 }
 
+func parseAllocAffinities(result *[]*api.AllocAffinity, list *ast.ObjectList) error {
+	for _, o := range list.Elem().Items {
+		// Check for invalid keys
+		valid := []string{
+			"job",
+			"group",
+			"weight",
+			"hard",
+		}
+		if err := checkHCLKeys(o.Val, valid); err != nil {
+			return err
+		}
+
+		var m map[string]interface{}
+		if err := hcl.DecodeObject(&m, o.Val); err != nil {
+			return err
+		}
+
+		// Build the alloc affinity
+		var a api.AllocAffinity
+		if err := mapstructure.WeakDecode(m, &a); err != nil {
+			return err
+		}
+
+		*result = append(*result, &a)
+	}
+
+	return nil
+}
+
 func parseSpread(result *[]*api.Spread, list *ast.ObjectList) error {
 	for _, o := range list.Elem().Items {
 		// Check for invalid keys
@@ -305,6 +335,8 @@ func parseSpread(result *[]*api.Spread, list *ast.ObjectList) error {
 			"attribute",
 			"weight",
 			"target",
+			"max_skew",
+			"hard",
 		}
 		if err := checkHCLKeys(o.Val, valid); err != nil {
 			return err
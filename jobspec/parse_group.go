@@ -41,6 +41,7 @@ func parseGroups(result *api.Job, list *ast.ObjectList) error {
 			"count",
 			"constraint",
 			"affinity",
+			"alloc_affinity",
 			"restart",
 			"meta",
 			"task",
@@ -56,6 +57,9 @@ func parseGroups(result *api.Job, list *ast.ObjectList) error {
 			"volume",
 			"scaling",
 			"stop_after_client_disconnect",
+			"max_client_disconnect",
+			"placement",
+			"max_run_duration",
 		}
 		if err := checkHCLKeys(listVal, valid); err != nil {
 			return multierror.Prefix(err, fmt.Sprintf("'%s' ->", n))
@@ -68,6 +72,7 @@ func parseGroups(result *api.Job, list *ast.ObjectList) error {
 
 		delete(m, "constraint")
 		delete(m, "affinity")
+		delete(m, "alloc_affinity")
 		delete(m, "meta")
 		delete(m, "task")
 		delete(m, "restart")
@@ -111,6 +116,13 @@ func parseGroups(result *api.Job, list *ast.ObjectList) error {
 			}
 		}
 
+		// Parse alloc affinities
+		if o := listVal.Filter("alloc_affinity"); len(o.Items) > 0 {
+			if err := parseAllocAffinities(&g.AllocAffinities, o); err != nil {
+				return multierror.Prefix(err, fmt.Sprintf("'%s', alloc_affinity ->", n))
+			}
+		}
+
 		// Parse restart policy
 		if o := listVal.Filter("restart"); len(o.Items) > 0 {
 			if err := parseRestartPolicy(&g.RestartPolicy, o); err != nil {
@@ -29,6 +29,7 @@ func parseJob(result *api.Job, list *ast.ObjectList) error {
 	}
 	delete(m, "constraint")
 	delete(m, "affinity")
+	delete(m, "alloc_affinity")
 	delete(m, "meta")
 	delete(m, "migrate")
 	delete(m, "parameterized")
@@ -38,6 +39,7 @@ func parseJob(result *api.Job, list *ast.ObjectList) error {
 	delete(m, "vault")
 	delete(m, "spread")
 	delete(m, "multiregion")
+	delete(m, "depends_on")
 
 	// Set the ID and name to the object key
 	result.ID = stringToPtr(obj.Keys[0].Token.Value().(string))
@@ -61,6 +63,7 @@ func parseJob(result *api.Job, list *ast.ObjectList) error {
 		"all_at_once",
 		"constraint",
 		"affinity",
+		"alloc_affinity",
 		"spread",
 		"datacenters",
 		"group",
@@ -69,6 +72,8 @@ func parseJob(result *api.Job, list *ast.ObjectList) error {
 		"migrate",
 		"name",
 		"namespace",
+		"node_pool",
+		"reservation",
 		"parameterized",
 		"periodic",
 		"priority",
@@ -81,6 +86,7 @@ func parseJob(result *api.Job, list *ast.ObjectList) error {
 		"vault_token",
 		"consul_token",
 		"multiregion",
+		"depends_on",
 	}
 	if err := checkHCLKeys(listVal, valid); err != nil {
 		return multierror.Prefix(err, "job:")
@@ -100,6 +106,13 @@ func parseJob(result *api.Job, list *ast.ObjectList) error {
 		}
 	}
 
+	// Parse alloc affinities
+	if o := listVal.Filter("alloc_affinity"); len(o.Items) > 0 {
+		if err := parseAllocAffinities(&result.AllocAffinities, o); err != nil {
+			return multierror.Prefix(err, "alloc_affinity ->")
+		}
+	}
+
 	// If we have an update strategy, then parse that
 	if o := listVal.Filter("update"); len(o.Items) > 0 {
 		if err := parseUpdate(&result.Update, o); err != nil {
@@ -121,6 +134,13 @@ func parseJob(result *api.Job, list *ast.ObjectList) error {
 		}
 	}
 
+	// Parse dependencies
+	if o := listVal.Filter("depends_on"); len(o.Items) > 0 {
+		if err := parseJobDependencies(&result.DependsOn, o); err != nil {
+			return multierror.Prefix(err, "depends_on ->")
+		}
+	}
+
 	// If we have a parameterized definition, then parse that
 	if o := listVal.Filter("parameterized"); len(o.Items) > 0 {
 		if err := parseParameterizedJob(&result.ParameterizedJob, o); err != nil {
@@ -260,6 +280,35 @@ func parsePeriodic(result **api.PeriodicConfig, list *ast.ObjectList) error {
 	return nil
 }
 
+func parseJobDependencies(result *[]*api.JobDependency, list *ast.ObjectList) error {
+	for _, o := range list.Elem().Items {
+		// Check for invalid keys
+		valid := []string{
+			"job",
+			"child",
+			"condition",
+		}
+		if err := checkHCLKeys(o.Val, valid); err != nil {
+			return err
+		}
+
+		var m map[string]interface{}
+		if err := hcl.DecodeObject(&m, o.Val); err != nil {
+			return err
+		}
+
+		// Build the dependency
+		var d api.JobDependency
+		if err := mapstructure.WeakDecode(m, &d); err != nil {
+			return err
+		}
+
+		*result = append(*result, &d)
+	}
+
+	return nil
+}
+
 func parseParameterizedJob(result **api.ParameterizedJobConfig, list *ast.ObjectList) error {
 	list = list.Elem()
 	if len(list.Items) > 1 {
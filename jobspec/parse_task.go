@@ -32,6 +32,7 @@ var (
 		"constraint",
 		"affinity",
 		"dispatch_payload",
+		"identity",
 		"lifecycle",
 		"leader",
 		"restart",
@@ -101,6 +102,7 @@ func parseTask(item *ast.ObjectItem, keys []string) (*api.Task, error) {
 	delete(m, "constraint")
 	delete(m, "affinity")
 	delete(m, "dispatch_payload")
+	delete(m, "identity")
 	delete(m, "lifecycle")
 	delete(m, "env")
 	delete(m, "logs")
@@ -248,19 +250,62 @@ func parseTask(item *ast.ObjectItem, keys []string) (*api.Task, error) {
 		valid := []string{
 			"max_files",
 			"max_file_size",
+			"log_sink",
+			"rate_limit",
+			"compress_rotated",
 		}
 		if err := checkHCLKeys(logsBlock.Val, valid); err != nil {
 			return nil, multierror.Prefix(err, "logs ->")
 		}
 
+		var rateLimit *api.LogRateLimit
+		if ot, ok := logsBlock.Val.(*ast.ObjectType); ok {
+			for _, sink := range ot.List.Filter("log_sink").Items {
+				valid := []string{
+					"type",
+					"address",
+					"path",
+					"buffer_size",
+				}
+				if err := checkHCLKeys(sink.Val, valid); err != nil {
+					return nil, multierror.Prefix(err, "logs -> log_sink ->")
+				}
+			}
+
+			rateLimits := ot.List.Filter("rate_limit")
+			if len(rateLimits.Items) > 1 {
+				return nil, fmt.Errorf("only one 'rate_limit' block allowed per logs block")
+			}
+			for _, o := range rateLimits.Items {
+				valid := []string{
+					"lines_per_second",
+					"bytes_per_second",
+				}
+				if err := checkHCLKeys(o.Val, valid); err != nil {
+					return nil, multierror.Prefix(err, "logs -> rate_limit ->")
+				}
+
+				var rm map[string]interface{}
+				if err := hcl.DecodeObject(&rm, o.Val); err != nil {
+					return nil, err
+				}
+				rateLimit = &api.LogRateLimit{}
+				if err := mapstructure.WeakDecode(rm, rateLimit); err != nil {
+					return nil, err
+				}
+			}
+		}
+
 		if err := hcl.DecodeObject(&m, logsBlock.Val); err != nil {
 			return nil, err
 		}
+		delete(m, "rate_limit")
 
 		var log api.LogConfig
 		if err := mapstructure.WeakDecode(m, &log); err != nil {
 			return nil, err
 		}
+		log.RateLimit = rateLimit
 
 		t.LogConfig = &log
 	}
@@ -326,6 +371,33 @@ func parseTask(item *ast.ObjectItem, keys []string) (*api.Task, error) {
 		}
 	}
 
+	// If we have an identity block parse that
+	if o := listVal.Filter("identity"); len(o.Items) > 0 {
+		if len(o.Items) > 1 {
+			return nil, fmt.Errorf("only one identity block is allowed in a task. Number of identity blocks found: %d", len(o.Items))
+		}
+		var m map[string]interface{}
+		identityBlock := o.Items[0]
+
+		// Check for invalid keys
+		valid := []string{
+			"env",
+			"file",
+		}
+		if err := checkHCLKeys(identityBlock.Val, valid); err != nil {
+			return nil, multierror.Prefix(err, "identity ->")
+		}
+
+		if err := hcl.DecodeObject(&m, identityBlock.Val); err != nil {
+			return nil, err
+		}
+
+		t.Identity = &api.WorkloadIdentity{}
+		if err := mapstructure.WeakDecode(m, t.Identity); err != nil {
+			return nil, err
+		}
+	}
+
 	// If we have a lifecycle block parse that
 	if o := listVal.Filter("lifecycle"); len(o.Items) > 0 {
 		if len(o.Items) > 1 {
@@ -428,6 +500,7 @@ func parseTemplates(result *[]*api.Template, list *ast.ObjectList) error {
 		// Check for invalid keys
 		valid := []string{
 			"change_mode",
+			"change_script",
 			"change_signal",
 			"data",
 			"destination",
@@ -447,6 +520,7 @@ func parseTemplates(result *[]*api.Template, list *ast.ObjectList) error {
 		if err := hcl.DecodeObject(&m, o.Val); err != nil {
 			return err
 		}
+		delete(m, "change_script")
 
 		templ := &api.Template{
 			ChangeMode: stringToPtr("restart"),
@@ -466,12 +540,63 @@ func parseTemplates(result *[]*api.Template, list *ast.ObjectList) error {
 			return err
 		}
 
+		// If we have a change script, then parse it
+		if ot, ok := o.Val.(*ast.ObjectType); ok {
+			if csList := ot.List.Filter("change_script"); len(csList.Items) > 0 {
+				if err := parseChangeScript(&templ.ChangeScript, csList); err != nil {
+					return multierror.Prefix(err, "change_script ->")
+				}
+			}
+		}
+
 		*result = append(*result, templ)
 	}
 
 	return nil
 }
 
+func parseChangeScript(final **api.ChangeScript, list *ast.ObjectList) error {
+	list = list.Elem()
+	if len(list.Items) > 1 {
+		return fmt.Errorf("only one 'change_script' block allowed")
+	}
+
+	// Get our change script object
+	obj := list.Items[0]
+
+	// Check for invalid keys
+	valid := []string{
+		"command",
+		"args",
+		"timeout",
+		"fail_on_error",
+	}
+	if err := checkHCLKeys(obj.Val, valid); err != nil {
+		return err
+	}
+
+	var m map[string]interface{}
+	if err := hcl.DecodeObject(&m, obj.Val); err != nil {
+		return err
+	}
+
+	var result api.ChangeScript
+	dec, err := mapstructure.NewDecoder(&mapstructure.DecoderConfig{
+		DecodeHook:       mapstructure.StringToTimeDurationHookFunc(),
+		WeaklyTypedInput: true,
+		Result:           &result,
+	})
+	if err != nil {
+		return err
+	}
+	if err := dec.Decode(m); err != nil {
+		return err
+	}
+
+	*final = &result
+	return nil
+}
+
 func parseTaskScalingPolicies(result *[]*api.ScalingPolicy, list *ast.ObjectList) error {
 	if len(list.Items) == 0 {
 		return nil
@@ -552,6 +677,7 @@ func parseResources(result *api.Resources, list *ast.ObjectList) error {
 		"network",
 		"device",
 		"cores",
+		"numa",
 	}
 	if err := checkHCLKeys(listVal, valid); err != nil {
 		return multierror.Prefix(err, "resources ->")
@@ -563,6 +689,7 @@ func parseResources(result *api.Resources, list *ast.ObjectList) error {
 	}
 	delete(m, "network")
 	delete(m, "device")
+	delete(m, "numa")
 
 	if err := mapstructure.WeakDecode(m, result); err != nil {
 		return err
@@ -641,6 +768,28 @@ func parseResources(result *api.Resources, list *ast.ObjectList) error {
 		}
 	}
 
+	// Parse the NUMA block
+	if o := listVal.Filter("numa"); len(o.Items) > 0 {
+		if len(o.Items) > 1 {
+			return fmt.Errorf("only one 'numa' block allowed per resources block")
+		}
+		no := o.Items[0]
+		if err := checkHCLKeys(no.Val, []string{"affinity"}); err != nil {
+			return multierror.Prefix(err, "resources, numa ->")
+		}
+
+		var m map[string]interface{}
+		if err := hcl.DecodeObject(&m, no.Val); err != nil {
+			return err
+		}
+
+		var numa api.NUMAResource
+		if err := mapstructure.WeakDecode(m, &numa); err != nil {
+			return err
+		}
+		result.NUMA = &numa
+	}
+
 	return nil
 }
 
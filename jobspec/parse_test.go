@@ -38,6 +38,7 @@ func TestParse(t *testing.T) {
 				Priority:    intToPtr(52),
 				AllAtOnce:   boolToPtr(true),
 				Datacenters: []string{"us2", "eu1"},
+				NodePool:    stringToPtr("dev"),
 				Region:      stringToPtr("fooregion"),
 				Namespace:   stringToPtr("foonamespace"),
 				ConsulToken: stringToPtr("abc"),
@@ -326,8 +327,24 @@ func TestParse(t *testing.T) {
 								KillTimeout:   timeToPtr(22 * time.Second),
 								ShutdownDelay: 11 * time.Second,
 								LogConfig: &api.LogConfig{
-									MaxFiles:      intToPtr(14),
-									MaxFileSizeMB: intToPtr(101),
+									MaxFiles:        intToPtr(14),
+									MaxFileSizeMB:   intToPtr(101),
+									CompressRotated: boolToPtr(true),
+									RateLimit: &api.LogRateLimit{
+										LinesPerSecond: 1000,
+										BytesPerSecond: 65536,
+									},
+									Sinks: []*api.LogSink{
+										{
+											Type:    "syslog",
+											Address: "tcp://127.0.0.1:514",
+										},
+										{
+											Type:       "file",
+											Path:       "binstore.json",
+											BufferSize: 2048,
+										},
+									},
 								},
 								Artifacts: []*api.TaskArtifact{
 									{
@@ -371,6 +388,19 @@ func TestParse(t *testing.T) {
 										LeftDelim:  stringToPtr("--"),
 										RightDelim: stringToPtr("__"),
 									},
+									{
+										SourcePath: stringToPtr("baz"),
+										DestPath:   stringToPtr("baz"),
+										ChangeMode: stringToPtr("script"),
+										ChangeScript: &api.ChangeScript{
+											Command:     stringToPtr("/bin/reload"),
+											Args:        []string{"-c", "baz"},
+											Timeout:     timeToPtr(30 * time.Second),
+											FailOnError: boolToPtr(true),
+										},
+										Splay: timeToPtr(5 * time.Second),
+										Perms: stringToPtr("0644"),
+									},
 								},
 								Leader:     true,
 								KillSignal: "",
@@ -1415,6 +1445,21 @@ func TestParse(t *testing.T) {
 			},
 			false,
 		},
+		{
+			"tg-service-provider-nomad.hcl",
+			&api.Job{
+				ID:   stringToPtr("service_provider"),
+				Name: stringToPtr("service_provider"),
+				TaskGroups: []*api.TaskGroup{{
+					Name: stringToPtr("group"),
+					Services: []*api.Service{{
+						Name:     "example",
+						Provider: "nomad",
+					}},
+				}},
+			},
+			false,
+		},
 		{
 			"tg-scaling-policy.hcl",
 			&api.Job{
@@ -1723,6 +1768,106 @@ func TestParse(t *testing.T) {
 			},
 			false,
 		},
+		{
+			"resources-numa.hcl",
+			&api.Job{
+				ID:   stringToPtr("numa-test"),
+				Name: stringToPtr("numa-test"),
+				TaskGroups: []*api.TaskGroup{
+					{
+						Name: stringToPtr("group"),
+						Tasks: []*api.Task{
+							{
+								Name:   "task",
+								Driver: "docker",
+								Resources: &api.Resources{
+									Cores:    intToPtr(4),
+									MemoryMB: intToPtr(128),
+									NUMA: &api.NUMAResource{
+										Affinity: "require",
+									},
+								},
+							},
+						},
+					},
+				},
+			},
+			false,
+		},
+		{
+			"spread-hard.hcl",
+			&api.Job{
+				ID:   stringToPtr("spread-hard"),
+				Name: stringToPtr("spread-hard"),
+				TaskGroups: []*api.TaskGroup{
+					{
+						Name: stringToPtr("group"),
+						Spreads: []*api.Spread{
+							{
+								Attribute: "${node.datacenter}",
+								MaxSkew:   2,
+								Hard:      true,
+							},
+						},
+					},
+				},
+			},
+			false,
+		},
+		{
+			"alloc-affinity.hcl",
+			&api.Job{
+				ID:   stringToPtr("alloc-affinity"),
+				Name: stringToPtr("alloc-affinity"),
+				AllocAffinities: []*api.AllocAffinity{
+					{
+						Job:    "cache",
+						Weight: int8ToPtr(100),
+					},
+				},
+				TaskGroups: []*api.TaskGroup{
+					{
+						Name: stringToPtr("group"),
+						AllocAffinities: []*api.AllocAffinity{
+							{
+								Job:       "db",
+								TaskGroup: "primary",
+								Weight:    int8ToPtr(-50),
+								Hard:      true,
+							},
+						},
+					},
+				},
+			},
+			false,
+		},
+		{
+			"depends-on.hcl",
+			&api.Job{
+				ID:   stringToPtr("depends-on"),
+				Name: stringToPtr("depends-on"),
+				Type: stringToPtr("batch"),
+				DependsOn: []*api.JobDependency{
+					{
+						JobID: stringToPtr("extract"),
+					},
+					{
+						JobID:     stringToPtr("cleanup"),
+						Condition: stringToPtr("failed"),
+					},
+					{
+						JobID: stringToPtr("load"),
+						Child: stringToPtr("load/dispatch-1234"),
+					},
+				},
+				TaskGroups: []*api.TaskGroup{
+					{
+						Name: stringToPtr("group"),
+					},
+				},
+			},
+			false,
+		},
 	}
 
 	for _, tc := range cases {
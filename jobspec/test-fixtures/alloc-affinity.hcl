@@ -0,0 +1,15 @@
+job "alloc-affinity" {
+  alloc_affinity {
+    job    = "cache"
+    weight = 100
+  }
+
+  group "group" {
+    alloc_affinity {
+      job    = "db"
+      group  = "primary"
+      weight = -50
+      hard   = true
+    }
+  }
+}
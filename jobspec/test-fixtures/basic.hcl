@@ -5,6 +5,7 @@ job "binstore-storagelocker" {
   priority     = 52
   all_at_once  = true
   datacenters  = ["us2", "eu1"]
+  node_pool    = "dev"
   consul_token = "abc"
   vault_token  = "foo"
 
@@ -190,8 +191,25 @@ job "binstore-storagelocker" {
       }
 
       logs {
-        max_files     = 14
-        max_file_size = 101
+        max_files        = 14
+        max_file_size    = 101
+        compress_rotated = true
+
+        rate_limit {
+          lines_per_second = 1000
+          bytes_per_second = 65536
+        }
+
+        log_sink {
+          type    = "syslog"
+          address = "tcp://127.0.0.1:514"
+        }
+
+        log_sink {
+          type        = "file"
+          path        = "binstore.json"
+          buffer_size = 2048
+        }
       }
 
       env {
@@ -320,6 +338,19 @@ job "binstore-storagelocker" {
         left_delimiter  = "--"
         right_delimiter = "__"
       }
+
+      template {
+        source      = "baz"
+        destination = "baz"
+        change_mode = "script"
+
+        change_script {
+          command       = "/bin/reload"
+          args          = ["-c", "baz"]
+          timeout       = "30s"
+          fail_on_error = true
+        }
+      }
     }
 
     task "storagelocker" {
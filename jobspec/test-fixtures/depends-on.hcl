@@ -0,0 +1,20 @@
+job "depends-on" {
+  type = "batch"
+
+  depends_on {
+    job = "extract"
+  }
+
+  depends_on {
+    job       = "cleanup"
+    condition = "failed"
+  }
+
+  depends_on {
+    job   = "load"
+    child = "load/dispatch-1234"
+  }
+
+  group "group" {
+  }
+}
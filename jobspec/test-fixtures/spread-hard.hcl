@@ -0,0 +1,9 @@
+job "spread-hard" {
+  group "group" {
+    spread {
+      attribute = "${node.datacenter}"
+      max_skew  = 2
+      hard      = true
+    }
+  }
+}
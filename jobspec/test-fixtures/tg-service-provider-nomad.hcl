@@ -0,0 +1,8 @@
+job "service_provider" {
+  group "group" {
+    service {
+      name     = "example"
+      provider = "nomad"
+    }
+  }
+}
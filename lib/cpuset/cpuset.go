@@ -101,6 +101,18 @@ func (c CPUSet) Difference(other CPUSet) CPUSet {
 
 }
 
+// Intersection returns a new set that is the intersection of this CPUSet and the supplied other.
+// [0,1,2,3].Intersection([2,3,4]) = [2,3]
+func (c CPUSet) Intersection(other CPUSet) CPUSet {
+	s := New()
+	for k := range c.cpus {
+		if _, ok := other.cpus[k]; ok {
+			s.cpus[k] = struct{}{}
+		}
+	}
+	return s
+}
+
 // IsSubsetOf returns true if all cpus of the this CPUSet are present in the other CPUSet.
 func (s CPUSet) IsSubsetOf(other CPUSet) bool {
 	for cpu := range s.cpus {
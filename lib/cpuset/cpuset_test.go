@@ -108,6 +108,28 @@ func TestCPUSet_Difference(t *testing.T) {
 	}
 }
 
+func TestCPUSet_Intersection(t *testing.T) {
+	cases := []struct {
+		a        CPUSet
+		b        CPUSet
+		expected CPUSet
+	}{
+		{New(), New(), New()},
+
+		{New(), New(0), New()},
+		{New(0), New(), New()},
+		{New(0), New(0), New(0)},
+
+		{New(0, 1), New(0, 1, 2, 3), New(0, 1)},
+		{New(2, 3), New(4, 5), New()},
+		{New(3, 4), New(0, 1, 2, 3), New(3)},
+	}
+
+	for _, c := range cases {
+		require.Exactly(t, c.expected.ToSlice(), c.a.Intersection(c.b).ToSlice())
+	}
+}
+
 func TestCPUSet_IsSubsetOf(t *testing.T) {
 	cases := []struct {
 		a        CPUSet
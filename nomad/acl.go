@@ -1,6 +1,8 @@
 package nomad
 
 import (
+	"fmt"
+	"strings"
 	"time"
 
 	metrics "github.com/armon/go-metrics"
@@ -31,10 +33,72 @@ func (s *Server) ResolveToken(secretID string) (*acl.ACL, error) {
 		return nil, err
 	}
 
+	// Workload identities are signed by the keyring rather than stored in
+	// the state store.
+	if isWorkloadIdentity(secretID) {
+		claims, err := s.resolveClaimsFromSnapshot(snap, secretID)
+		if err != nil {
+			return nil, err
+		}
+		return resolveClaimsFromCache(s.aclCache, claims)
+	}
+
 	// Resolve the ACL
 	return resolveTokenFromSnapshotCache(snap, s.aclCache, secretID)
 }
 
+// ResolveClaims verifies a workload identity and returns its claims. It
+// returns nil if the secret is not a workload identity, and ErrTokenNotFound
+// if the identity is invalid or its allocation is no longer running.
+func (s *Server) ResolveClaims(secretID string) (*structs.IdentityClaims, error) {
+	if !isWorkloadIdentity(secretID) {
+		return nil, nil
+	}
+
+	snap, err := s.fsm.State().Snapshot()
+	if err != nil {
+		return nil, err
+	}
+	return s.resolveClaimsFromSnapshot(snap, secretID)
+}
+
+// resolveClaimsFromSnapshot verifies the signature of a workload identity
+// and that the allocation it identifies is still running.
+func (s *Server) resolveClaimsFromSnapshot(snap *state.StateSnapshot, secretID string) (*structs.IdentityClaims, error) {
+	claims, err := s.encrypter.VerifyClaim(secretID)
+	if err != nil {
+		s.logger.Named("acl").Debug("failed to verify workload identity", "error", err)
+		return nil, structs.ErrTokenNotFound
+	}
+
+	alloc, err := snap.AllocByID(nil, claims.AllocationID)
+	if err != nil {
+		return nil, err
+	}
+	if alloc == nil || alloc.ClientTerminalStatus() {
+		return nil, structs.ErrTokenNotFound
+	}
+	return claims, nil
+}
+
+// isWorkloadIdentity returns whether the secret is a JWT rather than the
+// UUID secret ID of an ACL token.
+func isWorkloadIdentity(secretID string) bool {
+	return strings.Count(secretID, ".") == 2
+}
+
+// resolveClaimsFromCache compiles the ACL object granted to a workload
+// identity. Workloads may read the jobs of their own namespace; access to
+// variables is checked against the claims by the Variables endpoint.
+func resolveClaimsFromCache(cache *lru.TwoQueueCache, claims *structs.IdentityClaims) (*acl.ACL, error) {
+	policy := &structs.ACLPolicy{
+		Name: "_workload_identity:" + claims.Namespace,
+		Rules: fmt.Sprintf(`namespace %q { capabilities = ["%s", "%s"] }`,
+			claims.Namespace, acl.NamespaceCapabilityListJobs, acl.NamespaceCapabilityReadJob),
+	}
+	return structs.CompileACLObject(cache, []*structs.ACLPolicy{policy})
+}
+
 // resolveTokenFromSnapshotCache is used to resolve an ACL object from a snapshot of state,
 // using a cache to avoid parsing and ACL construction when possible. It is split from resolveToken
 // to simplify testing.
@@ -396,8 +396,19 @@ func (a *ACL) Bootstrap(args *structs.ACLTokenBootstrapRequest, reply *structs.A
 	}
 	args.Token.SetHash()
 
+	// Encrypt the secret so that it is not written to Raft in the clear
+	applyArgs := *args
+	encrypted, err := a.srv.encrypter.encryptACLToken(args.Token)
+	if err != nil {
+		return err
+	}
+	if encrypted != nil {
+		applyArgs.Token = nil
+		applyArgs.EncryptedToken = encrypted
+	}
+
 	// Update via Raft
-	_, index, err := a.srv.raftApply(structs.ACLTokenBootstrapRequestType, args)
+	_, index, err := a.srv.raftApply(structs.ACLTokenBootstrapRequestType, &applyArgs)
 	if err != nil {
 		return err
 	}
@@ -412,6 +423,10 @@ func (a *ACL) Bootstrap(args *structs.ACLTokenBootstrapRequest, reply *structs.A
 	if err != nil {
 		return structs.NewErrRPCCodedf(400, "token lookup failed: %v", err)
 	}
+	out, err = a.srv.encrypter.decryptACLToken(out)
+	if err != nil {
+		return err
+	}
 	reply.Tokens = append(reply.Tokens, out)
 
 	// Update the index
@@ -529,8 +544,14 @@ func (a *ACL) UpsertTokens(args *structs.ACLTokenUpsertRequest, reply *structs.A
 		token.SetHash()
 	}
 
+	// Encrypt the secrets so that they are not written to Raft in the clear
+	applyArgs, err := a.srv.encrypter.encryptACLTokenUpsert(args)
+	if err != nil {
+		return err
+	}
+
 	// Update via Raft
-	_, index, err := a.srv.raftApply(structs.ACLTokenUpsertRequestType, args)
+	_, index, err := a.srv.raftApply(structs.ACLTokenUpsertRequestType, applyArgs)
 	if err != nil {
 		return err
 	}
@@ -546,6 +567,10 @@ func (a *ACL) UpsertTokens(args *structs.ACLTokenUpsertRequest, reply *structs.A
 		if err != nil {
 			return structs.NewErrRPCCodedf(400, "token lookup failed: %v", err)
 		}
+		out, err = a.srv.encrypter.decryptACLToken(out)
+		if err != nil {
+			return err
+		}
 		reply.Tokens = append(reply.Tokens, out)
 	}
 
@@ -650,21 +675,22 @@ func (a *ACL) ListTokens(args *structs.ACLTokenListRequest, reply *structs.ACLTo
 	} else if acl == nil || !acl.IsManagement() {
 		return structs.ErrPermissionDenied
 	}
+	sort := state.SortOption(args.Reverse)
 
 	// Setup the blocking query
 	opts := blockingOptions{
 		queryOpts: &args.QueryOptions,
 		queryMeta: &reply.QueryMeta,
-		run: func(ws memdb.WatchSet, state *state.StateStore) error {
+		run: func(ws memdb.WatchSet, store *state.StateStore) error {
 			// Iterate over all the tokens
 			var err error
 			var iter memdb.ResultIterator
 			if prefix := args.QueryOptions.Prefix; prefix != "" {
-				iter, err = state.ACLTokenByAccessorIDPrefix(ws, prefix)
+				iter, err = store.ACLTokenByAccessorIDPrefix(ws, prefix, sort)
 			} else if args.GlobalOnly {
-				iter, err = state.ACLTokensByGlobal(ws, true)
+				iter, err = store.ACLTokensByGlobal(ws, true, sort)
 			} else {
-				iter, err = state.ACLTokens(ws)
+				iter, err = store.ACLTokens(ws, sort)
 			}
 			if err != nil {
 				return err
@@ -672,17 +698,21 @@ func (a *ACL) ListTokens(args *structs.ACLTokenListRequest, reply *structs.ACLTo
 
 			// Convert all the tokens to a list stub
 			reply.Tokens = nil
-			for {
-				raw := iter.Next()
-				if raw == nil {
-					break
-				}
-				token := raw.(*structs.ACLToken)
-				reply.Tokens = append(reply.Tokens, token.Stub())
+			paginator := state.NewPaginator(iter, state.IDTokenizer{}, args.QueryOptions,
+				func(raw interface{}) (bool, error) {
+					token := raw.(*structs.ACLToken)
+					reply.Tokens = append(reply.Tokens, token.Stub())
+					return true, nil
+				})
+
+			nextToken, err := paginator.Page()
+			if err != nil {
+				return err
 			}
+			reply.QueryMeta.NextToken = nextToken
 
 			// Use the last index that affected the token table
-			index, err := state.Index("acl_token")
+			index, err := store.Index("acl_token")
 			if err != nil {
 				return err
 			}
@@ -732,12 +762,15 @@ func (a *ACL) GetToken(args *structs.ACLTokenSpecificRequest, reply *structs.Sin
 
 				// Check management level permissions or that the secret ID matches the
 				// accessor ID
-			} else if !acl.IsManagement() && out.SecretID != args.AuthToken {
+			} else if !acl.IsManagement() && out.SecretHash != structs.ACLTokenSecretHash(args.AuthToken) {
 				return structs.ErrPermissionDenied
 			}
 
 			// Setup the output
-			reply.Token = out
+			reply.Token, err = a.srv.encrypter.decryptACLToken(out)
+			if err != nil {
+				return err
+			}
 			if out != nil {
 				reply.Index = out.ModifyIndex
 			} else {
@@ -785,6 +818,10 @@ func (a *ACL) GetTokens(args *structs.ACLTokenSetRequest, reply *structs.ACLToke
 					return err
 				}
 				if out != nil {
+					out, err = a.srv.encrypter.decryptACLToken(out)
+					if err != nil {
+						return err
+					}
 					reply.Tokens[out.AccessorID] = out
 				}
 			}
@@ -824,6 +861,10 @@ func (a *ACL) ResolveToken(args *structs.ResolveACLTokenRequest, reply *structs.
 	if err != nil {
 		return err
 	}
+	out, err = a.srv.encrypter.decryptACLToken(out)
+	if err != nil {
+		return err
+	}
 
 	// Setup the output
 	reply.Token = out
@@ -933,6 +974,10 @@ func (a *ACL) ExchangeOneTimeToken(args *structs.OneTimeTokenExchangeRequest, re
 	if aclToken == nil {
 		return structs.ErrPermissionDenied
 	}
+	aclToken, err = a.srv.encrypter.decryptACLToken(aclToken)
+	if err != nil {
+		return err
+	}
 
 	// Expire token via raft; because this is the only write in the RPC the
 	// caller can safely retry with the same token if the raft write fails
@@ -928,12 +928,24 @@ func TestACLEndpoint_ListTokens_Blocking(t *testing.T) {
 	codec := rpcClient(t, s1)
 	testutil.WaitForLeader(t, s1.RPC)
 
+	// Wait for the leader to encrypt the bootstrap token, so that its write
+	// does not unblock the queries
+	testutil.WaitForResult(func() (bool, error) {
+		out, err := state.ACLTokenByAccessorID(nil, root.AccessorID)
+		if err != nil {
+			return false, err
+		}
+		return out.SecretKeyID != "", fmt.Errorf("bootstrap token not encrypted")
+	}, func(err error) {
+		t.Fatalf("err: %v", err)
+	})
+
 	// Create the token
 	token := mock.ACLToken()
 
 	// Upsert eval triggers watches
 	time.AfterFunc(100*time.Millisecond, func() {
-		if err := state.UpsertACLTokens(structs.MsgTypeTestSetup, 3, []*structs.ACLToken{token}); err != nil {
+		if err := state.UpsertACLTokens(structs.MsgTypeTestSetup, 150, []*structs.ACLToken{token}); err != nil {
 			t.Fatalf("err: %v", err)
 		}
 	})
@@ -941,7 +953,7 @@ func TestACLEndpoint_ListTokens_Blocking(t *testing.T) {
 	req := &structs.ACLTokenListRequest{
 		QueryOptions: structs.QueryOptions{
 			Region:        "global",
-			MinQueryIndex: 2,
+			MinQueryIndex: 100,
 			AuthToken:     root.SecretID,
 		},
 	}
@@ -954,19 +966,19 @@ func TestACLEndpoint_ListTokens_Blocking(t *testing.T) {
 	if elapsed := time.Since(start); elapsed < 100*time.Millisecond {
 		t.Fatalf("should block (returned in %s) %#v", elapsed, resp)
 	}
-	assert.Equal(t, uint64(3), resp.Index)
+	assert.Equal(t, uint64(150), resp.Index)
 	if len(resp.Tokens) != 2 {
 		t.Fatalf("bad: %#v", resp.Tokens)
 	}
 
 	// Eval deletion triggers watches
 	time.AfterFunc(100*time.Millisecond, func() {
-		if err := state.DeleteACLTokens(structs.MsgTypeTestSetup, 4, []string{token.AccessorID}); err != nil {
+		if err := state.DeleteACLTokens(structs.MsgTypeTestSetup, 250, []string{token.AccessorID}); err != nil {
 			t.Fatalf("err: %v", err)
 		}
 	})
 
-	req.MinQueryIndex = 3
+	req.MinQueryIndex = 150
 	start = time.Now()
 	var resp2 structs.ACLTokenListResponse
 	if err := msgpackrpc.CallWithCodec(codec, "ACL.ListTokens", req, &resp2); err != nil {
@@ -976,7 +988,7 @@ func TestACLEndpoint_ListTokens_Blocking(t *testing.T) {
 	if elapsed := time.Since(start); elapsed < 100*time.Millisecond {
 		t.Fatalf("should block (returned in %s) %#v", elapsed, resp2)
 	}
-	assert.Equal(t, uint64(4), resp2.Index)
+	assert.Equal(t, uint64(250), resp2.Index)
 	assert.Equal(t, 1, len(resp2.Tokens))
 }
 
@@ -1066,6 +1078,8 @@ func TestACLEndpoint_Bootstrap(t *testing.T) {
 	// Check we created the token
 	out, err := s1.fsm.State().ACLTokenByAccessorID(nil, created.AccessorID)
 	assert.Nil(t, err)
+	out, err = s1.encrypter.decryptACLToken(out)
+	assert.Nil(t, err)
 	assert.Equal(t, created, out)
 }
 
@@ -1123,6 +1137,8 @@ func TestACLEndpoint_Bootstrap_Reset(t *testing.T) {
 	// Check we created the token
 	out, err := s1.fsm.State().ACLTokenByAccessorID(nil, created.AccessorID)
 	assert.Nil(t, err)
+	out, err = s1.encrypter.decryptACLToken(out)
+	assert.Nil(t, err)
 	assert.Equal(t, created, out)
 
 	// Try again, should fail
@@ -1169,6 +1185,8 @@ func TestACLEndpoint_UpsertTokens(t *testing.T) {
 	// Check we created the token
 	out, err := s1.fsm.State().ACLTokenByAccessorID(nil, created.AccessorID)
 	assert.Nil(t, err)
+	out, err = s1.encrypter.decryptACLToken(out)
+	assert.Nil(t, err)
 	assert.Equal(t, created, out)
 
 	// Update the token type
@@ -1185,6 +1203,8 @@ func TestACLEndpoint_UpsertTokens(t *testing.T) {
 	// Check we modified the token
 	out, err = s1.fsm.State().ACLTokenByAccessorID(nil, created.AccessorID)
 	assert.Nil(t, err)
+	out, err = s1.encrypter.decryptACLToken(out)
+	assert.Nil(t, err)
 	assert.Equal(t, created, out)
 }
 
@@ -2,6 +2,7 @@ package nomad
 
 import (
 	"testing"
+	"time"
 
 	lru "github.com/hashicorp/golang-lru"
 	"github.com/hashicorp/nomad/acl"
@@ -131,3 +132,54 @@ func TestResolveSecretToken(t *testing.T) {
 	}
 
 }
+
+func TestResolveClaims(t *testing.T) {
+	t.Parallel()
+
+	s1, _, cleanupS1 := TestACLServer(t, nil)
+	defer cleanupS1()
+	testutil.WaitForLeader(t, s1.RPC)
+	waitForKeyring(t, s1)
+
+	alloc := mock.Alloc()
+	identity, err := s1.encrypter.SignClaims(structs.NewIdentityClaims(alloc, "web", time.Now()))
+	assert.Nil(t, err)
+
+	// Identities of unknown allocations are rejected.
+	_, err = s1.ResolveClaims(identity)
+	assert.Equal(t, structs.ErrTokenNotFound, err)
+
+	state := s1.State()
+	assert.Nil(t, state.UpsertJobSummary(100, mock.JobSummary(alloc.JobID)))
+	assert.Nil(t, state.UpsertAllocs(structs.MsgTypeTestSetup, 110, []*structs.Allocation{alloc}))
+
+	claims, err := s1.ResolveClaims(identity)
+	assert.Nil(t, err)
+	if assert.NotNil(t, claims) {
+		assert.Equal(t, alloc.ID, claims.AllocationID)
+		assert.Equal(t, alloc.JobID, claims.JobID)
+	}
+
+	// The identity may read jobs in its own namespace only.
+	aclObj, err := s1.ResolveToken(identity)
+	assert.Nil(t, err)
+	if assert.NotNil(t, aclObj) {
+		assert.False(t, aclObj.IsManagement())
+		assert.True(t, aclObj.AllowNsOp(alloc.Namespace, acl.NamespaceCapabilityReadJob))
+		assert.False(t, aclObj.AllowNsOp("other", acl.NamespaceCapabilityReadJob))
+		assert.False(t, aclObj.AllowNsOp(alloc.Namespace, acl.NamespaceCapabilitySubmitJob))
+	}
+
+	// ACL token secrets are not treated as identities.
+	claims, err = s1.ResolveClaims(uuid.Generate())
+	assert.Nil(t, err)
+	assert.Nil(t, claims)
+
+	// Identities of stopped allocations are rejected.
+	stopped := alloc.Copy()
+	stopped.ClientStatus = structs.AllocClientStatusComplete
+	assert.Nil(t, state.UpdateAllocsFromClient(structs.MsgTypeTestSetup, 120, []*structs.Allocation{stopped}))
+
+	_, err = s1.ResolveClaims(identity)
+	assert.Equal(t, structs.ErrTokenNotFound, err)
+}
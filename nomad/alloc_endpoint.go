@@ -41,38 +41,52 @@ func (a *Alloc) List(args *structs.AllocListRequest, reply *structs.AllocListRes
 		return structs.ErrPermissionDenied
 	}
 
+	filter, err := newListFilter(&args.QueryOptions, (*structs.AllocListStub)(nil))
+	if err != nil {
+		return err
+	}
+	sort := state.SortOption(args.Reverse)
+
 	// Setup the blocking query
 	opts := blockingOptions{
 		queryOpts: &args.QueryOptions,
 		queryMeta: &reply.QueryMeta,
-		run: func(ws memdb.WatchSet, state *state.StateStore) error {
+		run: func(ws memdb.WatchSet, store *state.StateStore) error {
 			// Capture all the allocations
 			var err error
 			var iter memdb.ResultIterator
 
 			prefix := args.QueryOptions.Prefix
 			if prefix != "" {
-				iter, err = state.AllocsByIDPrefix(ws, args.RequestNamespace(), prefix)
+				iter, err = store.AllocsByIDPrefix(ws, args.RequestNamespace(), prefix, sort)
 			} else {
-				iter, err = state.AllocsByNamespace(ws, args.RequestNamespace())
+				iter, err = store.AllocsByNamespace(ws, args.RequestNamespace(), sort)
 			}
 			if err != nil {
 				return err
 			}
 
 			var allocs []*structs.AllocListStub
-			for {
-				raw := iter.Next()
-				if raw == nil {
-					break
-				}
-				alloc := raw.(*structs.Allocation)
-				allocs = append(allocs, alloc.Stub(args.Fields))
+			paginator := state.NewPaginator(iter, state.IDTokenizer{}, args.QueryOptions,
+				func(raw interface{}) (bool, error) {
+					alloc := raw.(*structs.Allocation)
+					stub := alloc.Stub(args.Fields)
+					if match, err := filter.Match(stub); err != nil || !match {
+						return false, err
+					}
+					allocs = append(allocs, stub)
+					return true, nil
+				})
+
+			nextToken, err := paginator.Page()
+			if err != nil {
+				return err
 			}
+			reply.QueryMeta.NextToken = nextToken
 			reply.Allocations = allocs
 
 			// Use the last index that affected the jobs table
-			index, err := state.Index("allocs")
+			index, err := store.Index("allocs")
 			if err != nil {
 				return err
 			}
@@ -97,13 +111,19 @@ func (a *Alloc) listAllNamespaces(args *structs.AllocListRequest, reply *structs
 		return aclObj.AllowNsOp(ns, acl.NamespaceCapabilityReadJob)
 	}
 
+	filter, err := newListFilter(&args.QueryOptions, (*structs.AllocListStub)(nil))
+	if err != nil {
+		return err
+	}
+	sort := state.SortOption(args.Reverse)
+
 	// Setup the blocking query
 	opts := blockingOptions{
 		queryOpts: &args.QueryOptions,
 		queryMeta: &reply.QueryMeta,
-		run: func(ws memdb.WatchSet, state *state.StateStore) error {
+		run: func(ws memdb.WatchSet, store *state.StateStore) error {
 			// get list of accessible namespaces
-			allowedNSes, err := allowedNSes(aclObj, state, allow)
+			allowedNSes, err := allowedNSes(aclObj, store, allow)
 			if err == structs.ErrPermissionDenied {
 				// return empty allocations if token isn't authorized for any
 				// namespace, matching other endpoints
@@ -114,27 +134,39 @@ func (a *Alloc) listAllNamespaces(args *structs.AllocListRequest, reply *structs
 				var iter memdb.ResultIterator
 				var err error
 				if prefix != "" {
-					iter, err = state.AllocsByIDPrefixAllNSs(ws, prefix)
+					iter, err = store.AllocsByIDPrefixAllNSs(ws, prefix, sort)
 				} else {
-					iter, err = state.Allocs(ws)
+					iter, err = store.Allocs(ws, sort)
 				}
 				if err != nil {
 					return err
 				}
 
 				var allocs []*structs.AllocListStub
-				for raw := iter.Next(); raw != nil; raw = iter.Next() {
-					alloc := raw.(*structs.Allocation)
-					if allowedNSes != nil && !allowedNSes[alloc.Namespace] {
-						continue
-					}
-					allocs = append(allocs, alloc.Stub(args.Fields))
+				paginator := state.NewPaginator(iter, state.IDTokenizer{}, args.QueryOptions,
+					func(raw interface{}) (bool, error) {
+						alloc := raw.(*structs.Allocation)
+						if allowedNSes != nil && !allowedNSes[alloc.Namespace] {
+							return false, nil
+						}
+						stub := alloc.Stub(args.Fields)
+						if match, err := filter.Match(stub); err != nil || !match {
+							return false, err
+						}
+						allocs = append(allocs, stub)
+						return true, nil
+					})
+
+				nextToken, err := paginator.Page()
+				if err != nil {
+					return err
 				}
+				reply.QueryMeta.NextToken = nextToken
 				reply.Allocations = allocs
 			}
 
 			// Use the last index that affected the jobs table
-			index, err := state.Index("allocs")
+			index, err := store.Index("allocs")
 			if err != nil {
 				return err
 			}
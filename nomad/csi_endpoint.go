@@ -110,6 +110,11 @@ func (v *CSIVolume) List(args *structs.CSIVolumeListRequest, reply *structs.CSIV
 
 	defer metrics.MeasureSince([]string{"nomad", "volume", "list"}, time.Now())
 
+	filter, err := newListFilter(&args.QueryOptions, (*structs.CSIVolListStub)(nil))
+	if err != nil {
+		return err
+	}
+
 	ns := args.RequestNamespace()
 	opts := blockingOptions{
 		queryOpts: &args.QueryOptions,
@@ -164,7 +169,13 @@ func (v *CSIVolume) List(args *structs.CSIVolumeListRequest, reply *structs.CSIV
 					return err
 				}
 
-				vs = append(vs, vol.Stub())
+				stub := vol.Stub()
+				if match, err := filter.Match(stub); err != nil {
+					return err
+				} else if !match {
+					continue
+				}
+				vs = append(vs, stub)
 			}
 			reply.Volumes = vs
 			return v.srv.replySetIndex(csiVolumeTable, &reply.QueryMeta)
@@ -396,36 +396,49 @@ func (d *Deployment) List(args *structs.DeploymentListRequest, reply *structs.De
 		return structs.ErrPermissionDenied
 	}
 
+	filter, err := newListFilter(&args.QueryOptions, (*structs.Deployment)(nil))
+	if err != nil {
+		return err
+	}
+	sort := state.SortOption(args.Reverse)
+
 	// Setup the blocking query
 	opts := blockingOptions{
 		queryOpts: &args.QueryOptions,
 		queryMeta: &reply.QueryMeta,
-		run: func(ws memdb.WatchSet, state *state.StateStore) error {
+		run: func(ws memdb.WatchSet, store *state.StateStore) error {
 			// Capture all the deployments
 			var err error
 			var iter memdb.ResultIterator
 			if prefix := args.QueryOptions.Prefix; prefix != "" {
-				iter, err = state.DeploymentsByIDPrefix(ws, args.RequestNamespace(), prefix)
+				iter, err = store.DeploymentsByIDPrefix(ws, args.RequestNamespace(), prefix, sort)
 			} else {
-				iter, err = state.DeploymentsByNamespace(ws, args.RequestNamespace())
+				iter, err = store.DeploymentsByNamespace(ws, args.RequestNamespace(), sort)
 			}
 			if err != nil {
 				return err
 			}
 
 			var deploys []*structs.Deployment
-			for {
-				raw := iter.Next()
-				if raw == nil {
-					break
-				}
-				deploy := raw.(*structs.Deployment)
-				deploys = append(deploys, deploy)
+			paginator := state.NewPaginator(iter, state.IDTokenizer{}, args.QueryOptions,
+				func(raw interface{}) (bool, error) {
+					deploy := raw.(*structs.Deployment)
+					if match, err := filter.Match(deploy); err != nil || !match {
+						return false, err
+					}
+					deploys = append(deploys, deploy)
+					return true, nil
+				})
+
+			nextToken, err := paginator.Page()
+			if err != nil {
+				return err
 			}
+			reply.QueryMeta.NextToken = nextToken
 			reply.Deployments = deploys
 
 			// Use the last index that affected the deployment table
-			index, err := state.Index("deployment")
+			index, err := store.Index("deployment")
 			if err != nil {
 				return err
 			}
@@ -0,0 +1,668 @@
+package nomad
+
+import (
+	"context"
+	"crypto/aes"
+	"crypto/cipher"
+	"crypto/ed25519"
+	"crypto/rand"
+	"crypto/sha256"
+	"encoding/json"
+	"fmt"
+	"io"
+	"io/ioutil"
+	"os"
+	"path/filepath"
+	"strings"
+	"sync"
+	"time"
+
+	log "github.com/hashicorp/go-hclog"
+	"golang.org/x/crypto/hkdf"
+	"golang.org/x/time/rate"
+	jose "gopkg.in/square/go-jose.v2"
+	"gopkg.in/square/go-jose.v2/jwt"
+
+	"github.com/hashicorp/nomad/helper"
+	"github.com/hashicorp/nomad/nomad/state"
+	"github.com/hashicorp/nomad/nomad/structs"
+)
+
+const (
+	// keystoreDir is the directory, relative to the server data directory,
+	// in which each server persists the key material of the root keys.
+	keystoreDir = "keystore"
+
+	// keystoreExtension is the file extension of the root key files in the
+	// keystore.
+	keystoreExtension = ".nks.json"
+
+	// keyFetchTimeout is the maximum amount of time to wait to fetch a root
+	// key this server is missing from the other servers.
+	keyFetchTimeout = 30 * time.Second
+
+	// rekeyACLTokensBatchSize is the number of re-encrypted ACL tokens
+	// written to Raft in a single request.
+	rekeyACLTokensBatchSize = 256
+
+	// workloadIdentitySigningKeyInfo is the HKDF context used to derive the
+	// workload identity signing key from a root key.
+	workloadIdentitySigningKeyInfo = "nomad workload identity signing key"
+)
+
+// Encrypter is the keyring for encrypting and decrypting sensitive data with
+// the cluster's root keys. Key metadata is replicated via Raft, while the key
+// material is kept in memory and persisted to each server's keystore.
+type Encrypter struct {
+	srv          *Server
+	keystorePath string
+
+	keyring map[string]*keyset
+	lock    sync.RWMutex
+}
+
+// keyset is a root key and the AEAD cipher and signing key derived from it.
+type keyset struct {
+	rootKey    *structs.RootKey
+	cipher     cipher.AEAD
+	privateKey ed25519.PrivateKey
+}
+
+// NewEncrypter loads or creates a new local keystore and returns an
+// encryption keyring with the keys it finds. An empty keystorePath means the
+// keys are only held in memory, which is used for dev mode.
+func NewEncrypter(srv *Server, keystorePath string) (*Encrypter, error) {
+	encrypter := &Encrypter{
+		srv:          srv,
+		keystorePath: keystorePath,
+		keyring:      make(map[string]*keyset),
+	}
+
+	if keystorePath == "" {
+		return encrypter, nil
+	}
+
+	if err := ensurePath(keystorePath, true); err != nil {
+		return nil, err
+	}
+	if err := encrypter.loadKeystore(); err != nil {
+		return nil, err
+	}
+	return encrypter, nil
+}
+
+// loadKeystore adds every root key found in the keystore to the keyring.
+func (e *Encrypter) loadKeystore() error {
+	return filepath.Walk(e.keystorePath, func(path string, info os.FileInfo, err error) error {
+		if err != nil {
+			return fmt.Errorf("could not read path %s from keystore: %v", path, err)
+		}
+
+		// skip over subdirectories and non-key files; they shouldn't be here
+		// but there's no reason to fail startup for it if the administrator
+		// has left something there
+		if path != e.keystorePath && info.IsDir() {
+			return filepath.SkipDir
+		}
+		if !strings.HasSuffix(path, keystoreExtension) {
+			return nil
+		}
+
+		id := strings.TrimSuffix(filepath.Base(path), keystoreExtension)
+		if !helper.IsUUID(id) {
+			return nil
+		}
+
+		key, err := e.loadKeyFromStore(path)
+		if err != nil {
+			return fmt.Errorf("could not load key file %s from keystore: %v", path, err)
+		}
+		if key.Meta.KeyID != id {
+			return fmt.Errorf("root key ID %s must match key file %s", key.Meta.KeyID, path)
+		}
+
+		return e.addCipher(key)
+	})
+}
+
+// Encrypt encrypts the cleartext with the active root key and returns the
+// ciphertext, prefixed with its nonce, and the ID of the key used.
+func (e *Encrypter) Encrypt(cleartext []byte) ([]byte, string, error) {
+	keyset, err := e.activeKeySet()
+	if err != nil {
+		return nil, "", err
+	}
+
+	nonce := make([]byte, keyset.cipher.NonceSize())
+	if _, err := rand.Read(nonce); err != nil {
+		return nil, "", fmt.Errorf("failed to generate nonce: %v", err)
+	}
+
+	// The nonce is prepended to the ciphertext so that it is available at
+	// decryption time.
+	ciphertext := keyset.cipher.Seal(nonce, nonce, cleartext, nil)
+	return ciphertext, keyset.rootKey.Meta.KeyID, nil
+}
+
+// Decrypt decrypts the ciphertext using the root key with the given ID.
+func (e *Encrypter) Decrypt(ciphertext []byte, keyID string) ([]byte, error) {
+	e.lock.RLock()
+	defer e.lock.RUnlock()
+
+	keyset, err := e.keysetByIDLocked(keyID)
+	if err != nil {
+		return nil, err
+	}
+
+	nonceSize := keyset.cipher.NonceSize()
+	if len(ciphertext) < nonceSize {
+		return nil, fmt.Errorf("ciphertext is too short")
+	}
+	nonce, data := ciphertext[:nonceSize], ciphertext[nonceSize:]
+
+	return keyset.cipher.Open(nil, nonce, data, nil)
+}
+
+// SignClaims signs the identity claims with the active root key and returns
+// the serialized JWT.
+func (e *Encrypter) SignClaims(claims *structs.IdentityClaims) (string, error) {
+	keyset, err := e.activeKeySet()
+	if err != nil {
+		return "", err
+	}
+
+	opts := (&jose.SignerOptions{}).
+		WithType("JWT").
+		WithHeader("kid", keyset.rootKey.Meta.KeyID)
+	signer, err := jose.NewSigner(jose.SigningKey{
+		Algorithm: jose.EdDSA,
+		Key:       keyset.privateKey,
+	}, opts)
+	if err != nil {
+		return "", err
+	}
+
+	return jwt.Signed(signer).Claims(claims).CompactSerialize()
+}
+
+// VerifyClaim verifies the signature of a workload identity JWT and returns
+// its claims.
+func (e *Encrypter) VerifyClaim(token string) (*structs.IdentityClaims, error) {
+	parsed, err := jwt.ParseSigned(token)
+	if err != nil {
+		return nil, fmt.Errorf("failed to parse signed token: %v", err)
+	}
+	if len(parsed.Headers) != 1 {
+		return nil, fmt.Errorf("expected one signature, found %d", len(parsed.Headers))
+	}
+	keyID := parsed.Headers[0].KeyID
+
+	e.lock.RLock()
+	keyset, err := e.keysetByIDLocked(keyID)
+	e.lock.RUnlock()
+	if err != nil {
+		return nil, err
+	}
+
+	claims := &structs.IdentityClaims{}
+	if err := parsed.Claims(keyset.privateKey.Public(), claims); err != nil {
+		return nil, fmt.Errorf("invalid signature: %v", err)
+	}
+	if err := claims.Validate(jwt.Expected{Time: time.Now()}); err != nil {
+		return nil, fmt.Errorf("invalid claims: %v", err)
+	}
+	return claims, nil
+}
+
+// GetPublicKey returns the public key used to verify the workload identities
+// signed with the given root key.
+func (e *Encrypter) GetPublicKey(keyID string) (ed25519.PublicKey, error) {
+	e.lock.RLock()
+	defer e.lock.RUnlock()
+
+	keyset, err := e.keysetByIDLocked(keyID)
+	if err != nil {
+		return nil, err
+	}
+	return keyset.privateKey.Public().(ed25519.PublicKey), nil
+}
+
+// AddKey stores the key in the keystore and adds it to the keyring.
+func (e *Encrypter) AddKey(rootKey *structs.RootKey) error {
+	if err := e.addCipher(rootKey); err != nil {
+		return err
+	}
+	return e.saveKeyToStore(rootKey)
+}
+
+// GetKey retrieves the key material by ID from the keyring.
+func (e *Encrypter) GetKey(keyID string) (*structs.RootKey, error) {
+	e.lock.RLock()
+	defer e.lock.RUnlock()
+
+	keyset, err := e.keysetByIDLocked(keyID)
+	if err != nil {
+		return nil, err
+	}
+	return keyset.rootKey.Copy(), nil
+}
+
+// hasKey returns whether the key material for the given key ID is in the
+// keyring.
+func (e *Encrypter) hasKey(keyID string) bool {
+	e.lock.RLock()
+	defer e.lock.RUnlock()
+	_, ok := e.keyring[keyID]
+	return ok
+}
+
+// RemoveKey removes the key from the keyring and deletes it from the
+// keystore.
+func (e *Encrypter) RemoveKey(keyID string) error {
+	e.lock.Lock()
+	delete(e.keyring, keyID)
+	e.lock.Unlock()
+
+	if e.keystorePath == "" {
+		return nil
+	}
+	path := filepath.Join(e.keystorePath, keyID+keystoreExtension)
+	if err := os.Remove(path); err != nil && !os.IsNotExist(err) {
+		return err
+	}
+	return nil
+}
+
+// fetchKey fetches the key material for a single root key, first from the
+// leader and then from any other server in the region, and adds it to the
+// keyring.
+func (e *Encrypter) fetchKey(ctx context.Context, keyID string) error {
+	srv := e.srv
+	getReq := &structs.KeyringGetRootKeyRequest{
+		KeyID: keyID,
+		QueryOptions: structs.QueryOptions{
+			Region:     srv.config.Region,
+			AllowStale: true,
+		},
+	}
+	getResp := &structs.KeyringGetRootKeyResponse{}
+
+	// Raft is not yet available while the FSM restores its snapshot at
+	// startup, in which case only the peers can be asked.
+	if srv.raft != nil {
+		isLeader, leader := srv.getLeader()
+		if !isLeader && leader != nil {
+			if err := srv.forwardServer(leader, "Keyring.Get", getReq, getResp); err != nil {
+				srv.logger.Named("keyring").Warn("failed to fetch key from leader, trying peers", "key_id", keyID, "error", err)
+			}
+		}
+	}
+
+	if getResp.Key == nil {
+		srv.peerLock.RLock()
+		peers := make([]*serverParts, 0, len(srv.localPeers))
+		for _, peer := range srv.localPeers {
+			peers = append(peers, peer)
+		}
+		srv.peerLock.RUnlock()
+
+		for _, peer := range peers {
+			if peer.ID == srv.config.NodeID {
+				continue
+			}
+			if err := ctx.Err(); err != nil {
+				return err
+			}
+			if err := srv.forwardServer(peer, "Keyring.Get", getReq, getResp); err == nil && getResp.Key != nil {
+				break
+			}
+		}
+	}
+
+	if getResp.Key == nil {
+		return fmt.Errorf("failed to fetch key %q from any peer", keyID)
+	}
+
+	if err := e.AddKey(getResp.Key); err != nil {
+		return fmt.Errorf("failed to add key to keyring: %v", err)
+	}
+	return nil
+}
+
+// decryptOrFetch decrypts the ciphertext, first fetching the root key from
+// the other servers if it has not yet been replicated to this server. It
+// must not be called from the FSM, as it may block on the network.
+func (e *Encrypter) decryptOrFetch(ciphertext []byte, keyID string) ([]byte, error) {
+	if !e.hasKey(keyID) {
+		ctx, cancel := context.WithTimeout(e.srv.shutdownCtx, keyFetchTimeout)
+		defer cancel()
+		if err := e.fetchKey(ctx, keyID); err != nil {
+			return nil, err
+		}
+	}
+	return e.Decrypt(ciphertext, keyID)
+}
+
+// encryptACLToken returns the token with its SecretID encrypted with the
+// active root key. It returns nil if the keyring has not been initialized
+// yet, in which case the token is written in the clear.
+func (e *Encrypter) encryptACLToken(token *structs.ACLToken) (*structs.ACLTokenEncrypted, error) {
+	keyMeta, err := e.srv.fsm.State().GetActiveRootKeyMeta(nil)
+	if err != nil {
+		return nil, err
+	}
+	if keyMeta == nil {
+		return nil, nil
+	}
+
+	secretID, keyID, err := e.Encrypt([]byte(token.SecretID))
+	if err != nil {
+		return nil, err
+	}
+
+	out := token.Copy()
+	out.SecretHash = structs.ACLTokenSecretHash(token.SecretID)
+	out.SecretID = ""
+	out.EncryptedSecretID = nil
+	out.SecretKeyID = ""
+	return &structs.ACLTokenEncrypted{
+		Token:    out,
+		SecretID: secretID,
+		KeyID:    keyID,
+	}, nil
+}
+
+// decryptACLToken returns a copy of an ACL token read from the state store
+// with its SecretID decrypted, or the token itself if its secret is not
+// encrypted.
+func (e *Encrypter) decryptACLToken(token *structs.ACLToken) (*structs.ACLToken, error) {
+	if token == nil || token.SecretKeyID == "" {
+		return token, nil
+	}
+
+	secretID, err := e.decryptOrFetch(token.EncryptedSecretID, token.SecretKeyID)
+	if err != nil {
+		return nil, fmt.Errorf("failed to decrypt ACL token %s: %v", token.AccessorID, err)
+	}
+	out := token.Copy()
+	out.SecretID = string(secretID)
+	out.EncryptedSecretID = nil
+	out.SecretKeyID = ""
+	return out, nil
+}
+
+// encryptACLTokenUpsert returns a copy of the request with the secrets of
+// its tokens encrypted, or the request itself if the keyring has not been
+// initialized yet.
+func (e *Encrypter) encryptACLTokenUpsert(args *structs.ACLTokenUpsertRequest) (*structs.ACLTokenUpsertRequest, error) {
+	encrypted := make([]*structs.ACLTokenEncrypted, 0, len(args.Tokens))
+	for _, token := range args.Tokens {
+		et, err := e.encryptACLToken(token)
+		if err != nil {
+			return nil, err
+		}
+		if et == nil {
+			return args, nil
+		}
+		encrypted = append(encrypted, et)
+	}
+
+	out := *args
+	out.Tokens = nil
+	out.EncryptedTokens = encrypted
+	return &out, nil
+}
+
+// rekeyACLTokens re-encrypts the secret of every ACL token not encrypted with
+// the given key, including tokens written before the keyring was initialized,
+// so that the previous keys can be removed.
+func (e *Encrypter) rekeyACLTokens(keyID string) error {
+	iter, err := e.srv.fsm.State().ACLTokens(nil, state.SortDefault)
+	if err != nil {
+		return err
+	}
+
+	var batch []*structs.ACLTokenEncrypted
+	apply := func() error {
+		if len(batch) == 0 {
+			return nil
+		}
+		req := &structs.ACLTokenUpsertRequest{
+			EncryptedTokens: batch,
+			Rekey:           true,
+		}
+		batch = nil
+		_, _, err := e.srv.raftApply(structs.ACLTokenUpsertRequestType, req)
+		return err
+	}
+
+	for raw := iter.Next(); raw != nil; raw = iter.Next() {
+		token := raw.(*structs.ACLToken)
+		if token.SecretKeyID == keyID {
+			continue
+		}
+
+		token, err := e.decryptACLToken(token)
+		if err != nil {
+			return err
+		}
+		encrypted, err := e.encryptACLToken(token)
+		if err != nil {
+			return fmt.Errorf("failed to encrypt ACL token %s: %v", token.AccessorID, err)
+		}
+		if encrypted == nil {
+			return fmt.Errorf("keyring is not initialized")
+		}
+
+		batch = append(batch, encrypted)
+		if len(batch) == rekeyACLTokensBatchSize {
+			if err := apply(); err != nil {
+				return err
+			}
+		}
+	}
+	return apply()
+}
+
+// addCipher creates a new cipher for the key and adds it to the keyring.
+func (e *Encrypter) addCipher(rootKey *structs.RootKey) error {
+	if rootKey == nil || rootKey.Meta == nil {
+		return fmt.Errorf("missing root key metadata")
+	}
+
+	var aead cipher.AEAD
+	switch rootKey.Meta.Algorithm {
+	case structs.EncryptionAlgorithmAES256GCM:
+		block, err := aes.NewCipher(rootKey.Key)
+		if err != nil {
+			return fmt.Errorf("could not create cipher: %v", err)
+		}
+		aead, err = cipher.NewGCM(block)
+		if err != nil {
+			return fmt.Errorf("could not create cipher: %v", err)
+		}
+	default:
+		return fmt.Errorf("invalid algorithm %s", rootKey.Meta.Algorithm)
+	}
+
+	// The key used to sign workload identities is derived from the root key
+	// rather than reusing the encryption key material directly.
+	seed := make([]byte, ed25519.SeedSize)
+	kdf := hkdf.New(sha256.New, rootKey.Key, []byte(rootKey.Meta.KeyID), []byte(workloadIdentitySigningKeyInfo))
+	if _, err := io.ReadFull(kdf, seed); err != nil {
+		return fmt.Errorf("could not derive signing key: %v", err)
+	}
+	privateKey := ed25519.NewKeyFromSeed(seed)
+
+	e.lock.Lock()
+	defer e.lock.Unlock()
+	e.keyring[rootKey.Meta.KeyID] = &keyset{
+		rootKey:    rootKey,
+		cipher:     aead,
+		privateKey: privateKey,
+	}
+	return nil
+}
+
+// activeKeySet returns the keyset for the key currently marked as active in
+// the state store.
+func (e *Encrypter) activeKeySet() (*keyset, error) {
+	keyMeta, err := e.srv.fsm.State().GetActiveRootKeyMeta(nil)
+	if err != nil {
+		return nil, err
+	}
+	if keyMeta == nil {
+		return nil, fmt.Errorf("keyring has not been initialized yet")
+	}
+
+	e.lock.RLock()
+	defer e.lock.RUnlock()
+	return e.keysetByIDLocked(keyMeta.KeyID)
+}
+
+// keysetByIDLocked returns the keyset for the given key ID. The caller must
+// hold the lock.
+func (e *Encrypter) keysetByIDLocked(keyID string) (*keyset, error) {
+	keyset, ok := e.keyring[keyID]
+	if !ok {
+		return nil, fmt.Errorf("no such key %q in keyring", keyID)
+	}
+	return keyset, nil
+}
+
+// saveKeyToStore serializes a root key to the on-disk keystore.
+func (e *Encrypter) saveKeyToStore(rootKey *structs.RootKey) error {
+	if e.keystorePath == "" {
+		return nil
+	}
+
+	buf, err := json.Marshal(rootKey)
+	if err != nil {
+		return err
+	}
+	path := filepath.Join(e.keystorePath, rootKey.Meta.KeyID+keystoreExtension)
+	return ioutil.WriteFile(path, buf, 0600)
+}
+
+// loadKeyFromStore deserializes a root key from disk.
+func (e *Encrypter) loadKeyFromStore(path string) (*structs.RootKey, error) {
+	raw, err := ioutil.ReadFile(path)
+	if err != nil {
+		return nil, err
+	}
+
+	var rootKey structs.RootKey
+	if err := json.Unmarshal(raw, &rootKey); err != nil {
+		return nil, err
+	}
+	if err := rootKey.Meta.Validate(); err != nil {
+		return nil, err
+	}
+	return &rootKey, nil
+}
+
+// KeyringReplicator runs on every server and fetches the key material of any
+// root key whose metadata is in the state store but which is missing from
+// the local keyring.
+type KeyringReplicator struct {
+	srv       *Server
+	encrypter *Encrypter
+	logger    log.Logger
+}
+
+// NewKeyringReplicator returns a replicator which runs until the context is
+// cancelled.
+func NewKeyringReplicator(ctx context.Context, srv *Server, e *Encrypter) *KeyringReplicator {
+	repl := &KeyringReplicator{
+		srv:       srv,
+		encrypter: e,
+		logger:    srv.logger.Named("keyring.replicator"),
+	}
+	go repl.run(ctx)
+	return repl
+}
+
+func (krr *KeyringReplicator) run(ctx context.Context) {
+	limiter := rate.NewLimiter(replicationRateLimit, int(replicationRateLimit))
+	krr.logger.Debug("starting encryption key replication")
+	defer krr.logger.Debug("exiting key replication")
+
+	retryErrTimer := time.NewTimer(time.Second)
+	defer retryErrTimer.Stop()
+
+START:
+	store := krr.srv.fsm.State()
+
+	for {
+		if err := limiter.Wait(ctx); err != nil {
+			return
+		}
+
+		ws := store.NewWatchSet()
+		iter, err := store.RootKeyMetas(ws)
+		if err != nil {
+			krr.logger.Error("failed to fetch keyring", "error", err)
+			goto ERR_WAIT
+		}
+
+		for {
+			raw := iter.Next()
+			if raw == nil {
+				break
+			}
+			keyMeta := raw.(*structs.RootKeyMeta)
+			if krr.encrypter.hasKey(keyMeta.KeyID) {
+				continue
+			}
+			if err := krr.replicateKey(ctx, keyMeta); err != nil {
+				// don't break the loop on an error, as we want to make
+				// sure we've replicated any keys we can. the rate
+				// limiter will prevent this case from sending excessive
+				// requests
+				krr.logger.Error("failed to replicate key", "key_id", keyMeta.KeyID, "error", err)
+				goto ERR_WAIT
+			}
+		}
+
+		// Wait for the keyring to change, or for the state store to be
+		// replaced by a snapshot restore.
+		if err := ws.WatchCtx(ctx); err != nil {
+			return
+		}
+		if store != krr.srv.fsm.State() {
+			goto START
+		}
+	}
+
+ERR_WAIT:
+	retryErrTimer.Reset(time.Second)
+	select {
+	case <-retryErrTimer.C:
+		goto START
+	case <-ctx.Done():
+		return
+	}
+}
+
+// replicateKey fetches the key material for a single root key and adds it
+// to the keyring.
+func (krr *KeyringReplicator) replicateKey(ctx context.Context, keyMeta *structs.RootKeyMeta) error {
+	keyID := keyMeta.KeyID
+	krr.logger.Debug("replicating new key", "id", keyID)
+
+	if err := krr.encrypter.fetchKey(ctx, keyID); err != nil {
+		return err
+	}
+
+	krr.logger.Info("added key", "key_id", keyID)
+	return nil
+}
+
+// keystorePathFor returns the keystore path used by a server with the given
+// data directory, or the empty string in dev mode.
+func keystorePathFor(config *Config) string {
+	if config.DevMode {
+		return ""
+	}
+	return filepath.Join(config.DataDir, keystoreDir)
+}
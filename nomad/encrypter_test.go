@@ -0,0 +1,338 @@
+package nomad
+
+import (
+	"bytes"
+	"crypto/ed25519"
+	"fmt"
+	"io/ioutil"
+	"os"
+	"testing"
+	"time"
+
+	msgpackrpc "github.com/hashicorp/net-rpc-msgpackrpc"
+	"github.com/hashicorp/nomad/nomad/mock"
+	"github.com/hashicorp/nomad/nomad/structs"
+	"github.com/hashicorp/nomad/testutil"
+	"github.com/stretchr/testify/require"
+)
+
+// waitForKeyring waits until the leader has initialized the keyring.
+func waitForKeyring(t *testing.T, s *Server) {
+	testutil.WaitForResult(func() (bool, error) {
+		keyMeta, err := s.fsm.State().GetActiveRootKeyMeta(nil)
+		if err != nil {
+			return false, err
+		}
+		if keyMeta == nil {
+			return false, fmt.Errorf("keyring not initialized")
+		}
+		if !s.encrypter.hasKey(keyMeta.KeyID) {
+			return false, fmt.Errorf("active key missing from keyring")
+		}
+		return true, nil
+	}, func(err error) {
+		t.Fatalf("err: %v", err)
+	})
+}
+
+func TestEncrypter_LoadSave(t *testing.T) {
+	t.Parallel()
+	tmpDir, err := ioutil.TempDir("", "nomadtest-keystore-")
+	require.NoError(t, err)
+	defer os.RemoveAll(tmpDir)
+
+	encrypter, err := NewEncrypter(&Server{}, tmpDir)
+	require.NoError(t, err)
+
+	key, err := structs.NewRootKey(structs.EncryptionAlgorithmAES256GCM)
+	require.NoError(t, err)
+	require.NoError(t, encrypter.AddKey(key))
+
+	// A new encrypter using the same keystore loads the saved key.
+	reloaded, err := NewEncrypter(&Server{}, tmpDir)
+	require.NoError(t, err)
+
+	got, err := reloaded.GetKey(key.Meta.KeyID)
+	require.NoError(t, err)
+	require.Equal(t, key.Key, got.Key)
+	require.Equal(t, key.Meta.Algorithm, got.Meta.Algorithm)
+}
+
+func TestEncrypter_EncryptDecrypt(t *testing.T) {
+	t.Parallel()
+	srv, cleanupSrv := TestServer(t, nil)
+	defer cleanupSrv()
+	testutil.WaitForLeader(t, srv.RPC)
+	waitForKeyring(t, srv)
+
+	cleartext := []byte("the quick brown fox")
+	ciphertext, keyID, err := srv.encrypter.Encrypt(cleartext)
+	require.NoError(t, err)
+	require.NotEqual(t, cleartext, ciphertext)
+	require.NotEmpty(t, keyID)
+
+	got, err := srv.encrypter.Decrypt(ciphertext, keyID)
+	require.NoError(t, err)
+	require.Equal(t, cleartext, got)
+
+	// Tampering with the ciphertext causes decryption to fail.
+	ciphertext[len(ciphertext)-1] ^= 0xff
+	_, err = srv.encrypter.Decrypt(ciphertext, keyID)
+	require.Error(t, err)
+
+	// Decrypting with an unknown key fails.
+	_, err = srv.encrypter.Decrypt(ciphertext, "unknown")
+	require.Error(t, err)
+}
+
+func TestEncrypter_ACLTokens(t *testing.T) {
+	t.Parallel()
+	srv, _, cleanupSrv := TestACLServer(t, nil)
+	defer cleanupSrv()
+	testutil.WaitForLeader(t, srv.RPC)
+	waitForKeyring(t, srv)
+
+	token := mock.ACLToken()
+	args := &structs.ACLTokenUpsertRequest{Tokens: []*structs.ACLToken{token}}
+
+	// The secrets of the tokens written to Raft are encrypted.
+	applyArgs, err := srv.encrypter.encryptACLTokenUpsert(args)
+	require.NoError(t, err)
+	require.Empty(t, applyArgs.Tokens)
+	require.Len(t, applyArgs.EncryptedTokens, 1)
+	require.Empty(t, applyArgs.EncryptedTokens[0].Token.SecretID)
+	require.NotContains(t, string(applyArgs.EncryptedTokens[0].SecretID), token.SecretID)
+	require.Equal(t, token.SecretID, args.Tokens[0].SecretID)
+
+	// Applying the request stores only the encrypted secret, which can
+	// still be looked up by the secret.
+	_, _, err = srv.raftApply(structs.ACLTokenUpsertRequestType, applyArgs)
+	require.NoError(t, err)
+
+	out, err := srv.fsm.State().ACLTokenBySecretID(nil, token.SecretID)
+	require.NoError(t, err)
+	require.NotNil(t, out)
+	require.Equal(t, token.AccessorID, out.AccessorID)
+	require.Empty(t, out.SecretID)
+	require.NotEmpty(t, out.SecretKeyID)
+
+	decrypted, err := srv.encrypter.decryptACLToken(out)
+	require.NoError(t, err)
+	require.Equal(t, token.SecretID, decrypted.SecretID)
+	require.Empty(t, decrypted.EncryptedSecretID)
+
+	// Snapshots only contain the encrypted secret, and can be restored
+	// without the keyring.
+	snap, err := srv.fsm.Snapshot()
+	require.NoError(t, err)
+	defer snap.Release()
+
+	buf := bytes.NewBuffer(nil)
+	sink := &MockSink{buf, false}
+	require.NoError(t, snap.Persist(sink))
+	require.NotContains(t, buf.String(), token.SecretID)
+
+	fsm := testFSM(t)
+	require.NoError(t, fsm.Restore(sink))
+	out, err = fsm.State().ACLTokenBySecretID(nil, token.SecretID)
+	require.NoError(t, err)
+	require.NotNil(t, out)
+	require.Equal(t, token.AccessorID, out.AccessorID)
+	require.Empty(t, out.SecretID)
+}
+
+func TestEncrypter_RekeyACLTokens(t *testing.T) {
+	t.Parallel()
+	srv, root, cleanupSrv := TestACLServer(t, nil)
+	defer cleanupSrv()
+	testutil.WaitForLeader(t, srv.RPC)
+	waitForKeyring(t, srv)
+
+	// A token written before the keyring was initialized is stored in the
+	// clear.
+	token := mock.ACLToken()
+	store := srv.fsm.State()
+	require.NoError(t, store.UpsertACLTokens(structs.MsgTypeTestSetup, 1000, []*structs.ACLToken{token}))
+
+	oldKey, err := store.GetActiveRootKeyMeta(nil)
+	require.NoError(t, err)
+	require.NoError(t, srv.encrypter.rekeyACLTokens(oldKey.KeyID))
+
+	out, err := store.ACLTokenBySecretID(nil, token.SecretID)
+	require.NoError(t, err)
+	require.Empty(t, out.SecretID)
+	require.Equal(t, oldKey.KeyID, out.SecretKeyID)
+
+	rootOut, err := store.ACLTokenBySecretID(nil, root.SecretID)
+	require.NoError(t, err)
+	require.Equal(t, oldKey.KeyID, rootOut.SecretKeyID)
+
+	// A full rotation re-encrypts the tokens with the new key.
+	codec := rpcClient(t, srv)
+	rotateReq := &structs.KeyringRotateRootKeyRequest{
+		Full: true,
+		WriteRequest: structs.WriteRequest{
+			Region:    "global",
+			AuthToken: root.SecretID,
+		},
+	}
+	var rotateResp structs.KeyringRotateRootKeyResponse
+	require.NoError(t, msgpackrpc.CallWithCodec(codec, "Keyring.Rotate", rotateReq, &rotateResp))
+
+	for _, secretID := range []string{token.SecretID, root.SecretID} {
+		out, err := store.ACLTokenBySecretID(nil, secretID)
+		require.NoError(t, err)
+		require.Equal(t, rotateResp.Key.KeyID, out.SecretKeyID)
+
+		decrypted, err := srv.encrypter.decryptACLToken(out)
+		require.NoError(t, err)
+		require.Equal(t, secretID, decrypted.SecretID)
+	}
+
+	// Rekeying skips tokens deleted in the meantime.
+	require.NoError(t, store.DeleteACLTokens(structs.MsgTypeTestSetup, 2000, []string{token.AccessorID}))
+	encrypted, err := srv.encrypter.encryptACLToken(token)
+	require.NoError(t, err)
+	_, _, err = srv.raftApply(structs.ACLTokenUpsertRequestType, &structs.ACLTokenUpsertRequest{
+		EncryptedTokens: []*structs.ACLTokenEncrypted{encrypted},
+		Rekey:           true,
+	})
+	require.NoError(t, err)
+	out, err = store.ACLTokenByAccessorID(nil, token.AccessorID)
+	require.NoError(t, err)
+	require.Nil(t, out)
+}
+
+func TestEncrypter_SignVerifyClaims(t *testing.T) {
+	t.Parallel()
+	srv, cleanupSrv := TestServer(t, nil)
+	defer cleanupSrv()
+	testutil.WaitForLeader(t, srv.RPC)
+	waitForKeyring(t, srv)
+
+	alloc := mock.Alloc()
+	claims := structs.NewIdentityClaims(alloc, "web", time.Now())
+
+	token, err := srv.encrypter.SignClaims(claims)
+	require.NoError(t, err)
+
+	got, err := srv.encrypter.VerifyClaim(token)
+	require.NoError(t, err)
+	require.Equal(t, alloc.Namespace, got.Namespace)
+	require.Equal(t, alloc.JobID, got.JobID)
+	require.Equal(t, alloc.ID, got.AllocationID)
+	require.Equal(t, "web", got.TaskName)
+
+	// The public key used to verify the token is published.
+	keyMeta, err := srv.fsm.State().GetActiveRootKeyMeta(nil)
+	require.NoError(t, err)
+	pubKey, err := srv.encrypter.GetPublicKey(keyMeta.KeyID)
+	require.NoError(t, err)
+	require.NotEmpty(t, pubKey)
+
+	// The signing key is derived from, but not the same as, the root key.
+	rootKey, err := srv.encrypter.GetKey(keyMeta.KeyID)
+	require.NoError(t, err)
+	require.NotEqual(t, ed25519.NewKeyFromSeed(rootKey.Key).Public(), pubKey)
+
+	// Expired identities are rejected.
+	expired, err := srv.encrypter.SignClaims(structs.NewIdentityClaims(alloc, "web",
+		time.Now().Add(-structs.WorkloadIdentityTTL-time.Minute)))
+	require.NoError(t, err)
+	_, err = srv.encrypter.VerifyClaim(expired)
+	require.Error(t, err)
+
+	// Tampering with the signature causes verification to fail.
+	tampered := token[:len(token)-2] + "AA"
+	if tampered == token {
+		tampered = token[:len(token)-2] + "BB"
+	}
+	_, err = srv.encrypter.VerifyClaim(tampered)
+	require.Error(t, err)
+}
+
+// TestEncrypter_Replication_LeaderFailover asserts that root keys are
+// replicated between servers without TLS, so that a new leader can still
+// sign workload identities after a failover.
+func TestEncrypter_Replication_LeaderFailover(t *testing.T) {
+	s1, cleanupS1 := TestServer(t, func(c *Config) {
+		c.BootstrapExpect = 3
+	})
+	defer cleanupS1()
+	s2, cleanupS2 := TestServer(t, func(c *Config) {
+		c.BootstrapExpect = 3
+	})
+	defer cleanupS2()
+	s3, cleanupS3 := TestServer(t, func(c *Config) {
+		c.BootstrapExpect = 3
+	})
+	defer cleanupS3()
+	servers := []*Server{s1, s2, s3}
+	TestJoin(t, s1, s2, s3)
+
+	leader := waitForStableLeadership(t, servers)
+	for _, s := range servers {
+		waitForKeyring(t, s)
+	}
+
+	leader.Leave()
+	leader.Shutdown()
+
+	var remaining []*Server
+	for _, s := range servers {
+		if s != leader {
+			remaining = append(remaining, s)
+		}
+	}
+	testutil.WaitForResult(func() (bool, error) {
+		for _, s := range remaining {
+			if s.IsLeader() {
+				leader = s
+				return true, nil
+			}
+		}
+		return false, fmt.Errorf("no new leader")
+	}, func(err error) {
+		t.Fatalf("err: %v", err)
+	})
+	codec := rpcClient(t, leader)
+
+	// Register a node and a job, and wait for the plan placing the job to be
+	// applied by the new leader.
+	node := mock.Node()
+	nodeReq := &structs.NodeRegisterRequest{
+		Node:         node,
+		WriteRequest: structs.WriteRequest{Region: "global"},
+	}
+	var nodeResp structs.GenericResponse
+	require.NoError(t, msgpackrpc.CallWithCodec(codec, "Node.Register", nodeReq, &nodeResp))
+
+	job := mock.Job()
+	job.TaskGroups[0].Count = 1
+	jobReq := &structs.JobRegisterRequest{
+		Job: job,
+		WriteRequest: structs.WriteRequest{
+			Region:    "global",
+			Namespace: job.Namespace,
+		},
+	}
+	var jobResp structs.JobRegisterResponse
+	require.NoError(t, msgpackrpc.CallWithCodec(codec, "Job.Register", jobReq, &jobResp))
+
+	var allocs []*structs.Allocation
+	testutil.WaitForResult(func() (bool, error) {
+		var err error
+		allocs, err = leader.fsm.State().AllocsByJob(nil, job.Namespace, job.ID, false)
+		if err != nil {
+			return false, err
+		}
+		return len(allocs) == 1, fmt.Errorf("expected 1 alloc, got %d", len(allocs))
+	}, func(err error) {
+		t.Fatalf("err: %v", err)
+	})
+
+	taskName := job.TaskGroups[0].Tasks[0].Name
+	require.Contains(t, allocs[0].SignedIdentities, taskName)
+	_, err := leader.encrypter.VerifyClaim(allocs[0].SignedIdentities[taskName])
+	require.NoError(t, err)
+}
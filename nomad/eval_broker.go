@@ -62,9 +62,32 @@ type EvalBroker struct {
 	// blocked tracks the blocked evaluations by JobID in a priority queue
 	blocked map[structs.NamespacedID]PendingEvaluations
 
+	// cancelable is the set of evaluations that were blocked behind a newer
+	// evaluation for the same job and are redundant. They are canceled by
+	// the leader in batches.
+	cancelable []*structs.Evaluation
+
+	// cancelableCh is used to signal that evaluations were added to the
+	// cancelable set.
+	cancelableCh chan struct{}
+
 	// ready tracks the ready jobs by scheduler in a priority queue
 	ready map[string]PendingEvaluations
 
+	// fairShare enables weighted fair-share dequeueing of evaluations across
+	// namespaces. When enabled, the namespace to dequeue from is picked by
+	// weighted deficit round-robin and priority only orders the evaluations
+	// within a namespace.
+	fairShare bool
+
+	// namespaceWeight returns the fair-share weight of a namespace. If nil,
+	// all namespaces have the same weight.
+	namespaceWeight func(namespace string) int
+
+	// fairShareReady tracks the ready jobs by scheduler when fair-share
+	// dequeueing is enabled. It replaces ready for all but the failed queue.
+	fairShareReady map[string]*fairShareQueue
+
 	// unack is a map of evalID to an un-acknowledged evaluation
 	unack map[string]*unackEval
 
@@ -135,7 +158,9 @@ func NewEvalBroker(timeout, initialNackDelay, subsequentNackDelay time.Duration,
 		evals:                make(map[string]int),
 		jobEvals:             make(map[structs.NamespacedID]string),
 		blocked:              make(map[structs.NamespacedID]PendingEvaluations),
+		cancelableCh:         make(chan struct{}, 1),
 		ready:                make(map[string]PendingEvaluations),
+		fairShareReady:       make(map[string]*fairShareQueue),
 		unack:                make(map[string]*unackEval),
 		waiting:              make(map[string]chan struct{}),
 		requeue:              make(map[string]*structs.Evaluation),
@@ -146,6 +171,7 @@ func NewEvalBroker(timeout, initialNackDelay, subsequentNackDelay time.Duration,
 		delayedEvalsUpdateCh: make(chan struct{}, 1),
 	}
 	b.stats.ByScheduler = make(map[string]*SchedulerStats)
+	b.stats.ByNamespace = make(map[string]*NamespaceStats)
 
 	return b, nil
 }
@@ -177,6 +203,43 @@ func (b *EvalBroker) SetEnabled(enabled bool) {
 	}
 }
 
+// SetFairShare is used to enable or disable weighted fair-share dequeueing of
+// evaluations across namespaces. The weight function returns the weight of a
+// namespace and may be nil, in which case all namespaces weigh the same. It's
+// called on every round-robin turn, so namespace weight changes apply to
+// evaluations that are already queued.
+func (b *EvalBroker) SetFairShare(enabled bool, weight func(namespace string) int) {
+	b.l.Lock()
+	defer b.l.Unlock()
+
+	b.namespaceWeight = weight
+
+	switch {
+	case enabled && !b.fairShare:
+		// Move the ready evaluations into the fair-share queues
+		b.fairShare = true
+		for sched, pending := range b.ready {
+			if sched == failedQueue {
+				continue
+			}
+			for _, eval := range pending {
+				b.pushReadyLocked(sched, eval)
+			}
+			delete(b.ready, sched)
+		}
+
+	case !enabled && b.fairShare:
+		// Move the ready evaluations back into the priority queues
+		b.fairShare = false
+		for sched, q := range b.fairShareReady {
+			for q.Len() != 0 {
+				b.pushReadyLocked(sched, q.Pop())
+			}
+		}
+		b.fairShareReady = make(map[string]*fairShareQueue)
+	}
+}
+
 // Enqueue is used to enqueue a new evaluation
 func (b *EvalBroker) Enqueue(eval *structs.Evaluation) {
 	b.l.Lock()
@@ -296,18 +359,11 @@ func (b *EvalBroker) enqueueLocked(eval *structs.Evaluation, queue string) {
 		return
 	}
 
-	// Find the pending by scheduler class
-	pending, ok := b.ready[queue]
-	if !ok {
-		pending = make([]*structs.Evaluation, 0, 16)
-		if _, ok := b.waiting[queue]; !ok {
-			b.waiting[queue] = make(chan struct{}, 1)
-		}
+	// Push onto the ready queue of the scheduler class
+	if _, ok := b.waiting[queue]; !ok {
+		b.waiting[queue] = make(chan struct{}, 1)
 	}
-
-	// Push onto the heap
-	heap.Push(&pending, eval)
-	b.ready[queue] = pending
+	b.pushReadyLocked(queue, eval)
 
 	// Update the stats
 	b.stats.TotalReady += 1
@@ -317,6 +373,7 @@ func (b *EvalBroker) enqueueLocked(eval *structs.Evaluation, queue string) {
 		b.stats.ByScheduler[queue] = bySched
 	}
 	bySched.Ready += 1
+	b.namespaceStatsLocked(eval.Namespace).Ready += 1
 
 	// Unblock any blocked dequeues
 	select {
@@ -380,14 +437,8 @@ func (b *EvalBroker) scanForSchedulers(schedulers []string) (*structs.Evaluation
 	var eligibleSched []string
 	var eligiblePriority int
 	for _, sched := range schedulers {
-		// Get the pending queue
-		pending, ok := b.ready[sched]
-		if !ok {
-			continue
-		}
-
 		// Peek at the next item
-		ready := pending.Peek()
+		ready := b.peekReadyLocked(sched)
 		if ready == nil {
 			continue
 		}
@@ -426,11 +477,8 @@ func (b *EvalBroker) scanForSchedulers(schedulers []string) (*structs.Evaluation
 // dequeueForSched is used to dequeue the next work item for a given scheduler.
 // This assumes locks are held and that this scheduler has work
 func (b *EvalBroker) dequeueForSched(sched string) (*structs.Evaluation, string, error) {
-	// Get the pending queue
-	pending := b.ready[sched]
-	raw := heap.Pop(&pending)
-	b.ready[sched] = pending
-	eval := raw.(*structs.Evaluation)
+	// Get the next evaluation from the ready queue
+	eval := b.popReadyLocked(sched)
 
 	// Generate a UUID for the token
 	token := uuid.Generate()
@@ -456,10 +504,89 @@ func (b *EvalBroker) dequeueForSched(sched string) (*structs.Evaluation, string,
 	bySched := b.stats.ByScheduler[sched]
 	bySched.Ready -= 1
 	bySched.Unacked += 1
+	byNamespace := b.namespaceStatsLocked(eval.Namespace)
+	byNamespace.Ready -= 1
+	byNamespace.Unacked += 1
 
 	return eval, token, nil
 }
 
+// usesFairShareLocked returns whether the ready evaluations of the scheduler
+// are dequeued fair-share. This assumes locks are held.
+func (b *EvalBroker) usesFairShareLocked(sched string) bool {
+	return b.fairShare && sched != failedQueue
+}
+
+// pushReadyLocked adds the evaluation to the ready queue of the scheduler.
+// This assumes locks are held.
+func (b *EvalBroker) pushReadyLocked(sched string, eval *structs.Evaluation) {
+	if b.usesFairShareLocked(sched) {
+		q, ok := b.fairShareReady[sched]
+		if !ok {
+			q = newFairShareQueue(b.weightLocked)
+			b.fairShareReady[sched] = q
+		}
+		q.Push(eval)
+		return
+	}
+
+	pending, ok := b.ready[sched]
+	if !ok {
+		pending = make([]*structs.Evaluation, 0, 16)
+	}
+	heap.Push(&pending, eval)
+	b.ready[sched] = pending
+}
+
+// peekReadyLocked returns the next evaluation in the ready queue of the
+// scheduler, or nil if it's empty. This assumes locks are held.
+func (b *EvalBroker) peekReadyLocked(sched string) *structs.Evaluation {
+	if b.usesFairShareLocked(sched) {
+		q, ok := b.fairShareReady[sched]
+		if !ok {
+			return nil
+		}
+		return q.Peek()
+	}
+	return b.ready[sched].Peek()
+}
+
+// popReadyLocked removes the next evaluation from the ready queue of the
+// scheduler. This assumes locks are held and that the queue is not empty.
+func (b *EvalBroker) popReadyLocked(sched string) *structs.Evaluation {
+	if b.usesFairShareLocked(sched) {
+		return b.fairShareReady[sched].Pop()
+	}
+
+	pending := b.ready[sched]
+	eval := heap.Pop(&pending).(*structs.Evaluation)
+	b.ready[sched] = pending
+	return eval
+}
+
+// weightLocked returns the fair-share weight of a namespace. This assumes
+// locks are held.
+func (b *EvalBroker) weightLocked(namespace string) int {
+	if b.namespaceWeight == nil {
+		return 1
+	}
+	if w := b.namespaceWeight(namespace); w > 0 {
+		return w
+	}
+	return 1
+}
+
+// namespaceStatsLocked returns the stats of a namespace, creating them if
+// needed. This assumes locks are held.
+func (b *EvalBroker) namespaceStatsLocked(namespace string) *NamespaceStats {
+	byNamespace, ok := b.stats.ByNamespace[namespace]
+	if !ok {
+		byNamespace = &NamespaceStats{}
+		b.stats.ByNamespace[namespace] = byNamespace
+	}
+	return byNamespace
+}
+
 // waitForSchedulers is used to wait for work on any of the scheduler or until a timeout.
 // Returns if there is work waiting potentially.
 func (b *EvalBroker) waitForSchedulers(schedulers []string, timeoutCh <-chan time.Time) bool {
@@ -563,6 +690,7 @@ func (b *EvalBroker) Ack(evalID, token string) error {
 	}
 	bySched := b.stats.ByScheduler[queue]
 	bySched.Unacked -= 1
+	b.namespaceStatsLocked(unack.Eval.Namespace).Unacked -= 1
 
 	// Cleanup
 	delete(b.unack, evalID)
@@ -574,16 +702,15 @@ func (b *EvalBroker) Ack(evalID, token string) error {
 	}
 	delete(b.jobEvals, namespacedID)
 
-	// Check if there are any blocked evaluations
+	// Check if there are any blocked evaluations. Only the newest one needs
+	// to be processed since the scheduler always works from the latest state
+	// of the job, so the older ones are marked for cancelation.
 	if blocked := b.blocked[namespacedID]; len(blocked) != 0 {
-		raw := heap.Pop(&blocked)
-		if len(blocked) > 0 {
-			b.blocked[namespacedID] = blocked
-		} else {
-			delete(b.blocked, namespacedID)
-		}
-		eval := raw.(*structs.Evaluation)
-		b.stats.TotalBlocked -= 1
+		delete(b.blocked, namespacedID)
+		b.stats.TotalBlocked -= len(blocked)
+
+		eval, cancelable := blocked.MarkForCancel()
+		b.markCancelableLocked(cancelable)
 		b.enqueueLocked(eval, eval.Type)
 	}
 
@@ -595,6 +722,89 @@ func (b *EvalBroker) Ack(evalID, token string) error {
 	return nil
 }
 
+// markCancelableLocked adds the evaluations to the cancelable set. It must be
+// called with the lock held.
+func (b *EvalBroker) markCancelableLocked(evals []*structs.Evaluation) {
+	if len(evals) == 0 {
+		return
+	}
+
+	for _, eval := range evals {
+		delete(b.evals, eval.ID)
+	}
+	b.cancelable = append(b.cancelable, evals...)
+	b.stats.TotalCancelable = len(b.cancelable)
+	b.stats.TotalCanceled += uint64(len(evals))
+	metrics.IncrCounter([]string{"nomad", "broker", "eval_canceled"}, float32(len(evals)))
+
+	select {
+	case b.cancelableCh <- struct{}{}:
+	default:
+	}
+}
+
+// RequeueCancelable adds evaluations returned by Cancelable back to the
+// cancelable set, so that they are canceled again after failing to be
+// canceled. They are dropped if the broker was disabled in the meantime, as
+// the broker of the next leader restores them from the state store.
+func (b *EvalBroker) RequeueCancelable(evals []*structs.Evaluation) {
+	b.l.Lock()
+	defer b.l.Unlock()
+
+	if !b.enabled || len(evals) == 0 {
+		return
+	}
+
+	// The evaluations were already counted as canceled when they were first
+	// added to the cancelable set
+	b.cancelable = append(b.cancelable, evals...)
+	b.stats.TotalCancelable = len(b.cancelable)
+
+	select {
+	case b.cancelableCh <- struct{}{}:
+	default:
+	}
+}
+
+// Cancelable returns up to batchSize evaluations that are redundant and should
+// be canceled, removing them from the cancelable set. It blocks until there
+// are cancelable evaluations or the timeout is reached.
+func (b *EvalBroker) Cancelable(batchSize int, timeout time.Duration) []*structs.Evaluation {
+	var timeoutTimer *time.Timer
+	var timeoutCh <-chan time.Time
+SCAN:
+	b.l.Lock()
+	if len(b.cancelable) != 0 {
+		if batchSize > len(b.cancelable) {
+			batchSize = len(b.cancelable)
+		}
+		cancelable := b.cancelable[:batchSize]
+		b.cancelable = b.cancelable[batchSize:]
+		b.stats.TotalCancelable = len(b.cancelable)
+		b.l.Unlock()
+		return cancelable
+	}
+
+	// Capture the chan inside the lock to prevent a race with it getting
+	// reset in flush
+	cancelableCh := b.cancelableCh
+	b.l.Unlock()
+
+	// Create the timer
+	if timeoutTimer == nil && timeout != 0 {
+		timeoutTimer = time.NewTimer(timeout)
+		timeoutCh = timeoutTimer.C
+		defer timeoutTimer.Stop()
+	}
+
+	select {
+	case <-timeoutCh:
+		return nil
+	case <-cancelableCh:
+		goto SCAN
+	}
+}
+
 // Nack is used to negatively acknowledge handling an evaluation
 func (b *EvalBroker) Nack(evalID, token string) error {
 	b.l.Lock()
@@ -623,6 +833,7 @@ func (b *EvalBroker) Nack(evalID, token string) error {
 	b.stats.TotalUnacked -= 1
 	bySched := b.stats.ByScheduler[unack.Eval.Type]
 	bySched.Unacked -= 1
+	b.namespaceStatsLocked(unack.Eval.Namespace).Unacked -= 1
 
 	// Check if we've hit the delivery limit, and re-enqueue
 	// in the failedQueue
@@ -718,16 +929,24 @@ func (b *EvalBroker) flush() {
 	// Clear out the update channel for delayed evaluations
 	b.delayedEvalsUpdateCh = make(chan struct{}, 1)
 
+	// Unblock any waiters for cancelable evaluations
+	close(b.cancelableCh)
+	b.cancelableCh = make(chan struct{}, 1)
+
 	// Reset the broker
 	b.stats.TotalReady = 0
 	b.stats.TotalUnacked = 0
 	b.stats.TotalBlocked = 0
 	b.stats.TotalWaiting = 0
+	b.stats.TotalCancelable = 0
 	b.stats.ByScheduler = make(map[string]*SchedulerStats)
+	b.stats.ByNamespace = make(map[string]*NamespaceStats)
 	b.evals = make(map[string]int)
 	b.jobEvals = make(map[structs.NamespacedID]string)
 	b.blocked = make(map[structs.NamespacedID]PendingEvaluations)
+	b.cancelable = nil
 	b.ready = make(map[string]PendingEvaluations)
+	b.fairShareReady = make(map[string]*fairShareQueue)
 	b.unack = make(map[string]*unackEval)
 	b.timeWait = make(map[string]*time.Timer)
 	b.delayHeap = delayheap.NewDelayHeap()
@@ -808,6 +1027,7 @@ func (b *EvalBroker) Stats() *BrokerStats {
 	// Allocate a new stats struct
 	stats := new(BrokerStats)
 	stats.ByScheduler = make(map[string]*SchedulerStats)
+	stats.ByNamespace = make(map[string]*NamespaceStats)
 
 	b.l.RLock()
 	defer b.l.RUnlock()
@@ -817,11 +1037,18 @@ func (b *EvalBroker) Stats() *BrokerStats {
 	stats.TotalUnacked = b.stats.TotalUnacked
 	stats.TotalBlocked = b.stats.TotalBlocked
 	stats.TotalWaiting = b.stats.TotalWaiting
+	stats.TotalCancelable = b.stats.TotalCancelable
+	stats.TotalCanceled = b.stats.TotalCanceled
 	for sched, subStat := range b.stats.ByScheduler {
 		subStatCopy := new(SchedulerStats)
 		*subStatCopy = *subStat
 		stats.ByScheduler[sched] = subStatCopy
 	}
+	for ns, subStat := range b.stats.ByNamespace {
+		subStatCopy := new(NamespaceStats)
+		*subStatCopy = *subStat
+		stats.ByNamespace[ns] = subStatCopy
+	}
 	return stats
 }
 
@@ -835,10 +1062,16 @@ func (b *EvalBroker) EmitStats(period time.Duration, stopCh <-chan struct{}) {
 			metrics.SetGauge([]string{"nomad", "broker", "total_unacked"}, float32(stats.TotalUnacked))
 			metrics.SetGauge([]string{"nomad", "broker", "total_blocked"}, float32(stats.TotalBlocked))
 			metrics.SetGauge([]string{"nomad", "broker", "total_waiting"}, float32(stats.TotalWaiting))
+			metrics.SetGauge([]string{"nomad", "broker", "total_cancelable"}, float32(stats.TotalCancelable))
 			for sched, schedStats := range stats.ByScheduler {
 				metrics.SetGauge([]string{"nomad", "broker", sched, "ready"}, float32(schedStats.Ready))
 				metrics.SetGauge([]string{"nomad", "broker", sched, "unacked"}, float32(schedStats.Unacked))
 			}
+			for ns, nsStats := range stats.ByNamespace {
+				labels := []metrics.Label{{Name: "namespace", Value: ns}}
+				metrics.SetGaugeWithLabels([]string{"nomad", "broker", "namespace", "ready"}, float32(nsStats.Ready), labels)
+				metrics.SetGaugeWithLabels([]string{"nomad", "broker", "namespace", "unacked"}, float32(nsStats.Unacked), labels)
+			}
 
 		case <-stopCh:
 			return
@@ -852,7 +1085,18 @@ type BrokerStats struct {
 	TotalUnacked int
 	TotalBlocked int
 	TotalWaiting int
-	ByScheduler  map[string]*SchedulerStats
+
+	// TotalCancelable is the number of redundant evaluations waiting to be
+	// canceled by the leader.
+	TotalCancelable int
+
+	// TotalCanceled is the cumulative number of redundant evaluations the
+	// broker has marked for cancelation. Unlike the other stats it isn't
+	// reset when the broker is flushed.
+	TotalCanceled uint64
+
+	ByScheduler map[string]*SchedulerStats
+	ByNamespace map[string]*NamespaceStats
 }
 
 // SchedulerStats returns the stats per scheduler
@@ -861,6 +1105,12 @@ type SchedulerStats struct {
 	Unacked int
 }
 
+// NamespaceStats returns the stats per namespace
+type NamespaceStats struct {
+	Ready   int
+	Unacked int
+}
+
 // Len is for the sorting interface
 func (p PendingEvaluations) Len() int {
 	return len(p)
@@ -895,6 +1145,33 @@ func (p *PendingEvaluations) Pop() interface{} {
 	return e
 }
 
+// MarkForCancel returns the newest evaluation, which is the one that should be
+// processed, along with the remaining evaluations, which are redundant and
+// can be canceled. It must only be called on the blocked evaluations of a
+// single job.
+func (p PendingEvaluations) MarkForCancel() (*structs.Evaluation, []*structs.Evaluation) {
+	if len(p) == 0 {
+		return nil, nil
+	}
+
+	newest := 0
+	for i, eval := range p {
+		n := p[newest]
+		if eval.ModifyIndex > n.ModifyIndex ||
+			(eval.ModifyIndex == n.ModifyIndex && eval.CreateIndex > n.CreateIndex) {
+			newest = i
+		}
+	}
+
+	cancelable := make([]*structs.Evaluation, 0, len(p)-1)
+	for i, eval := range p {
+		if i != newest {
+			cancelable = append(cancelable, eval)
+		}
+	}
+	return p[newest], cancelable
+}
+
 // Peek is used to peek at the next element that would be popped
 func (p PendingEvaluations) Peek() *structs.Evaluation {
 	n := len(p)
@@ -903,3 +1180,106 @@ func (p PendingEvaluations) Peek() *structs.Evaluation {
 	}
 	return p[n-1]
 }
+
+// fairShareQueue holds the ready evaluations of a scheduler in a priority
+// queue per namespace. Namespaces are served by weighted deficit round-robin:
+// when a namespace's turn comes it's credited its current weight, and it's
+// served until it runs out of credit or evaluations.
+type fairShareQueue struct {
+	// pending holds the ready evaluations of each namespace
+	pending map[string]PendingEvaluations
+
+	// weight returns the weight of a namespace. It's called on every turn,
+	// so weight changes apply from the next round on.
+	weight func(namespace string) int
+
+	// deficits hold the remaining credit of each namespace with ready
+	// evaluations
+	deficits map[string]int
+
+	// ring holds the namespaces with ready evaluations in round-robin order
+	// and current is the index of the namespace being served
+	ring    []string
+	current int
+
+	// advance is set when the namespace being served was removed from the
+	// ring, so the next one must be credited before it's served
+	advance bool
+
+	len int
+}
+
+func newFairShareQueue(weight func(namespace string) int) *fairShareQueue {
+	return &fairShareQueue{
+		pending:  make(map[string]PendingEvaluations),
+		weight:   weight,
+		deficits: make(map[string]int),
+		current:  -1,
+	}
+}
+
+// Len returns the number of ready evaluations.
+func (q *fairShareQueue) Len() int {
+	return q.len
+}
+
+// Push adds an evaluation.
+func (q *fairShareQueue) Push(eval *structs.Evaluation) {
+	ns := eval.Namespace
+	pending, ok := q.pending[ns]
+	if !ok {
+		q.ring = append(q.ring, ns)
+	}
+	heap.Push(&pending, eval)
+	q.pending[ns] = pending
+	q.len++
+}
+
+// Peek returns the evaluation that will be popped next, or nil if the queue
+// is empty.
+func (q *fairShareQueue) Peek() *structs.Evaluation {
+	if q.len == 0 {
+		return nil
+	}
+	return q.pending[q.serving()][0]
+}
+
+// Pop removes the next evaluation. The queue must not be empty.
+func (q *fairShareQueue) Pop() *structs.Evaluation {
+	ns := q.serving()
+	pending := q.pending[ns]
+	eval := heap.Pop(&pending).(*structs.Evaluation)
+	q.deficits[ns]--
+	q.len--
+
+	if len(pending) != 0 {
+		q.pending[ns] = pending
+		return eval
+	}
+
+	// The namespace has no ready evaluations left so it loses its turn and
+	// any remaining credit
+	delete(q.pending, ns)
+	delete(q.deficits, ns)
+	q.ring = append(q.ring[:q.current], q.ring[q.current+1:]...)
+	q.current--
+	q.advance = true
+	if len(q.ring) == 0 {
+		q.current = -1
+		q.advance = false
+	}
+	return eval
+}
+
+// serving returns the namespace to serve next, moving on to the next
+// namespace in the ring and crediting it with its current weight if the
+// current one used up its credit. The queue must not be empty.
+func (q *fairShareQueue) serving() string {
+	for q.advance || q.current < 0 || q.deficits[q.ring[q.current]] <= 0 {
+		q.advance = false
+		q.current = (q.current + 1) % len(q.ring)
+		ns := q.ring[q.current]
+		q.deficits[ns] += q.weight(ns)
+	}
+	return q.ring[q.current]
+}
@@ -455,7 +455,8 @@ func TestEvalBroker_Serialize_DuplicateJobID(t *testing.T) {
 		t.Fatalf("err: %v", err)
 	}
 
-	// Check the stats
+	// Check the stats. Only the newest blocked eval for the job in ns1 is
+	// enqueued and the older one is marked for cancelation.
 	stats = b.Stats()
 	if stats.TotalReady != 2 {
 		t.Fatalf("bad: %#v", stats)
@@ -463,46 +464,13 @@ func TestEvalBroker_Serialize_DuplicateJobID(t *testing.T) {
 	if stats.TotalUnacked != 0 {
 		t.Fatalf("bad: %#v", stats)
 	}
-	if stats.TotalBlocked != 2 {
-		t.Fatalf("bad: %#v", stats)
-	}
-
-	// Dequeue should work
-	out, token, err = b.Dequeue(defaultSched, time.Second)
-	if err != nil {
-		t.Fatalf("err: %v", err)
-	}
-	if out != eval2 {
-		t.Fatalf("bad : %#v", out)
-	}
-
-	// Check the stats
-	stats = b.Stats()
-	if stats.TotalReady != 1 {
+	if stats.TotalBlocked != 1 {
 		t.Fatalf("bad: %#v", stats)
 	}
-	if stats.TotalUnacked != 1 {
+	if stats.TotalCancelable != 1 {
 		t.Fatalf("bad: %#v", stats)
 	}
-	if stats.TotalBlocked != 2 {
-		t.Fatalf("bad: %#v", stats)
-	}
-
-	// Ack out
-	err = b.Ack(eval2.ID, token)
-	if err != nil {
-		t.Fatalf("err: %v", err)
-	}
-
-	// Check the stats
-	stats = b.Stats()
-	if stats.TotalReady != 2 {
-		t.Fatalf("bad: %#v", stats)
-	}
-	if stats.TotalUnacked != 0 {
-		t.Fatalf("bad: %#v", stats)
-	}
-	if stats.TotalBlocked != 1 {
+	if stats.TotalCanceled != 1 {
 		t.Fatalf("bad: %#v", stats)
 	}
 
@@ -624,6 +592,61 @@ func TestEvalBroker_Serialize_DuplicateJobID(t *testing.T) {
 	}
 }
 
+// TestEvalBroker_Serialize_DuplicateJobID_Cancelable asserts that redundant
+// blocked evals are handed out for cancelation and counted.
+func TestEvalBroker_Serialize_DuplicateJobID_Cancelable(t *testing.T) {
+	t.Parallel()
+	b := testBroker(t, 0)
+	b.SetEnabled(true)
+
+	eval := mock.Eval()
+	b.Enqueue(eval)
+
+	var blocked []*structs.Evaluation
+	for i := 1; i <= 3; i++ {
+		e := mock.Eval()
+		e.JobID = eval.JobID
+		e.CreateIndex = eval.CreateIndex + uint64(i)
+		b.Enqueue(e)
+		blocked = append(blocked, e)
+	}
+
+	out, token, err := b.Dequeue(defaultSched, time.Second)
+	require.NoError(t, err)
+	require.Equal(t, eval, out)
+	require.NoError(t, b.Ack(eval.ID, token))
+
+	// All but the newest blocked eval are cancelable
+	stats := b.Stats()
+	require.Equal(t, 1, stats.TotalReady)
+	require.Equal(t, 0, stats.TotalBlocked)
+	require.Equal(t, 2, stats.TotalCancelable)
+	require.Equal(t, uint64(2), stats.TotalCanceled)
+
+	cancelable := b.Cancelable(1, 0)
+	require.Equal(t, []*structs.Evaluation{blocked[0]}, cancelable)
+	cancelable = b.Cancelable(10, 0)
+	require.Equal(t, []*structs.Evaluation{blocked[1]}, cancelable)
+
+	// The cumulative count remains after the evals are handed out
+	stats = b.Stats()
+	require.Equal(t, 0, stats.TotalCancelable)
+	require.Equal(t, uint64(2), stats.TotalCanceled)
+
+	out, token, err = b.Dequeue(defaultSched, time.Second)
+	require.NoError(t, err)
+	require.Equal(t, blocked[2], out)
+	require.NoError(t, b.Ack(out.ID, token))
+
+	// Nothing more is canceled once the job has no blocked evals
+	b.Enqueue(mock.Eval())
+	require.Equal(t, uint64(2), b.Stats().TotalCanceled)
+
+	// Flushing the broker doesn't reset the cumulative count
+	b.SetEnabled(false)
+	require.Equal(t, uint64(2), b.Stats().TotalCanceled)
+}
+
 func TestEvalBroker_Enqueue_Disable(t *testing.T) {
 	t.Parallel()
 	b := testBroker(t, 0)
@@ -805,6 +828,163 @@ func TestEvalBroker_Dequeue_Priority(t *testing.T) {
 	}
 }
 
+func TestEvalBroker_Dequeue_FairShare(t *testing.T) {
+	t.Parallel()
+	b := testBroker(t, 0)
+	b.SetEnabled(true)
+
+	weights := map[string]int{"noisy": 1, "quiet": 2}
+	b.SetFairShare(true, func(ns string) int { return weights[ns] })
+
+	// The noisy namespace enqueues more evaluations with a higher priority
+	for i := 0; i < 6; i++ {
+		eval := mock.Eval()
+		eval.Namespace = "noisy"
+		eval.Priority = 80
+		b.Enqueue(eval)
+	}
+	for i := 0; i < 4; i++ {
+		eval := mock.Eval()
+		eval.Namespace = "quiet"
+		b.Enqueue(eval)
+	}
+
+	stats := b.Stats()
+	require.Equal(t, 6, stats.ByNamespace["noisy"].Ready)
+	require.Equal(t, 4, stats.ByNamespace["quiet"].Ready)
+
+	// The quiet namespace gets twice the share of the noisy namespace
+	dequeued := map[string]int{}
+	for i := 0; i < 6; i++ {
+		out, _, err := b.Dequeue(defaultSched, time.Second)
+		require.NoError(t, err)
+		require.NotNil(t, out)
+		dequeued[out.Namespace]++
+	}
+	require.Equal(t, map[string]int{"noisy": 2, "quiet": 4}, dequeued)
+
+	// Once the quiet namespace is drained the noisy one gets everything
+	for i := 0; i < 4; i++ {
+		out, _, err := b.Dequeue(defaultSched, time.Second)
+		require.NoError(t, err)
+		require.NotNil(t, out)
+		require.Equal(t, "noisy", out.Namespace)
+	}
+
+	stats = b.Stats()
+	require.Zero(t, stats.ByNamespace["noisy"].Ready)
+	require.Equal(t, 6, stats.ByNamespace["noisy"].Unacked)
+	require.Equal(t, 4, stats.ByNamespace["quiet"].Unacked)
+}
+
+// TestEvalBroker_Dequeue_FairShare_WeightChange asserts that changing the
+// weight of a namespace applies to its evaluations that are already queued.
+func TestEvalBroker_Dequeue_FairShare_WeightChange(t *testing.T) {
+	t.Parallel()
+	b := testBroker(t, 0)
+	b.SetEnabled(true)
+
+	weights := map[string]int{"a": 1, "b": 1}
+	b.SetFairShare(true, func(ns string) int { return weights[ns] })
+
+	for _, ns := range []string{"a", "a", "a", "a", "a", "a", "b", "b", "b", "b"} {
+		eval := mock.Eval()
+		eval.Namespace = ns
+		b.Enqueue(eval)
+	}
+
+	dequeue := func(n int) []string {
+		var order []string
+		for i := 0; i < n; i++ {
+			out, _, err := b.Dequeue(defaultSched, time.Second)
+			require.NoError(t, err)
+			require.NotNil(t, out)
+			order = append(order, out.Namespace)
+		}
+		return order
+	}
+
+	// The namespaces weigh the same, so they alternate
+	require.Equal(t, []string{"a", "b", "a", "b"}, dequeue(4))
+
+	// Once the weight of a is raised while its evaluations are queued, it
+	// gets three evaluations for each of b from its next turn on
+	weights["a"] = 3
+	require.Equal(t, []string{"a", "a", "a", "b", "a", "b"}, dequeue(6))
+}
+
+// TestEvalBroker_Dequeue_FairShare_Toggle asserts that ready evaluations are
+// kept when fair-share dequeueing is enabled and disabled.
+func TestEvalBroker_Dequeue_FairShare_Toggle(t *testing.T) {
+	t.Parallel()
+	b := testBroker(t, 0)
+	b.SetEnabled(true)
+
+	var evals []*structs.Evaluation
+	for i, ns := range []string{"a", "a", "a", "b"} {
+		eval := mock.Eval()
+		eval.Namespace = ns
+		eval.Priority = 50 + i
+		b.Enqueue(eval)
+		evals = append(evals, eval)
+	}
+
+	// With fair-share enabled the namespaces take turns, ignoring priority
+	// across namespaces
+	b.SetFairShare(true, nil)
+	require.Equal(t, 4, b.Stats().TotalReady)
+
+	out, _, err := b.Dequeue(defaultSched, time.Second)
+	require.NoError(t, err)
+	require.Equal(t, evals[3], out)
+
+	out, _, err = b.Dequeue(defaultSched, time.Second)
+	require.NoError(t, err)
+	require.Equal(t, evals[2], out)
+
+	// With fair-share disabled the remaining evals are dequeued by priority
+	b.SetFairShare(false, nil)
+	require.Equal(t, 2, b.Stats().TotalReady)
+
+	out, _, err = b.Dequeue(defaultSched, time.Second)
+	require.NoError(t, err)
+	require.Equal(t, evals[1], out)
+
+	out, _, err = b.Dequeue(defaultSched, time.Second)
+	require.NoError(t, err)
+	require.Equal(t, evals[0], out)
+}
+
+func TestFairShareQueue(t *testing.T) {
+	t.Parallel()
+
+	weights := map[string]int{"a": 1, "b": 1, "c": 3}
+	weight := func(ns string) int { return weights[ns] }
+
+	q := newFairShareQueue(weight)
+	require.Nil(t, q.Peek())
+
+	for _, ns := range []string{"a", "a", "a", "b", "c", "c", "c", "c", "c"} {
+		eval := mock.Eval()
+		eval.Namespace = ns
+		q.Push(eval)
+	}
+	require.Equal(t, 9, q.Len())
+
+	var order []string
+	for q.Len() != 0 {
+		next := q.Peek()
+		out := q.Pop()
+		require.Equal(t, next, out)
+		order = append(order, out.Namespace)
+	}
+
+	// Each namespace gets its weight's worth of evals per round, and drained
+	// namespaces drop out of the round-robin
+	require.Equal(t, []string{"a", "b", "c", "c", "c", "a", "c", "c", "a"}, order)
+	require.Nil(t, q.Peek())
+}
+
 // Ensure FIFO at fixed priority
 func TestEvalBroker_Dequeue_FIFO(t *testing.T) {
 	t.Parallel()
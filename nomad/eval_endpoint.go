@@ -2,6 +2,7 @@ package nomad
 
 import (
 	"fmt"
+	"net/http"
 	"time"
 
 	metrics "github.com/armon/go-metrics"
@@ -10,6 +11,7 @@ import (
 	multierror "github.com/hashicorp/go-multierror"
 
 	"github.com/hashicorp/nomad/acl"
+	"github.com/hashicorp/nomad/helper"
 	"github.com/hashicorp/nomad/nomad/state"
 	"github.com/hashicorp/nomad/nomad/structs"
 	"github.com/hashicorp/nomad/scheduler"
@@ -330,6 +332,202 @@ func (e *Eval) Reap(args *structs.EvalDeleteRequest,
 	return nil
 }
 
+// Delete is used by operators to delete evaluations, for example when a
+// misbehaving job floods the eval broker. Unlike Reap, it requires a
+// management token, the eval broker must be paused, and evaluations that
+// are not safe to delete are refused. Allocations are never deleted.
+func (e *Eval) Delete(args *structs.EvalDeleteRequest, reply *structs.EvalDeleteResponse) error {
+	if done, err := e.srv.forward("Eval.Delete", args, args, reply); done {
+		return err
+	}
+	defer metrics.MeasureSince([]string{"nomad", "eval", "delete"}, time.Now())
+
+	// This action requires a management token
+	if aclObj, err := e.srv.ResolveToken(args.AuthToken); err != nil {
+		return err
+	} else if aclObj != nil && !aclObj.IsManagement() {
+		return structs.ErrPermissionDenied
+	}
+
+	switch {
+	case len(args.Evals) == 0 && args.Filter == "":
+		return structs.NewErrRPCCoded(http.StatusBadRequest, "evals or filter must be specified")
+	case len(args.Evals) > 0 && args.Filter != "":
+		return structs.NewErrRPCCoded(http.StatusBadRequest, "evals and filter cannot be used together")
+	case len(args.Allocs) > 0:
+		return structs.NewErrRPCCoded(http.StatusBadRequest, "allocs cannot be specified")
+	}
+
+	// Deleting evaluations while the broker is running would race with the
+	// schedulers processing them.
+	if e.srv.evalBroker.Enabled() {
+		return structs.NewErrRPCCoded(http.StatusBadRequest,
+			"eval broker is enabled; eval broker must be paused to delete evals")
+	}
+
+	store := e.srv.fsm.State()
+	evals, err := e.evalsToDelete(store, args)
+	if err != nil {
+		return err
+	}
+
+	// Refuse to delete evaluations whose job or allocations may still need
+	// them. Allocations are left in place so the reconciler and reschedule
+	// tracking still see them.
+	evalIDs := make([]string, 0, len(evals))
+	for _, eval := range evals {
+		safe, err := evalDeleteSafe(store, eval)
+		if err != nil {
+			return err
+		}
+		if !safe {
+			return structs.NewErrRPCCodedf(http.StatusBadRequest,
+				"eval %s is not safe to delete", eval.ID)
+		}
+		evalIDs = append(evalIDs, eval.ID)
+	}
+
+	// Delete the evaluations in batches small enough to keep the Raft
+	// entries a reasonable size.
+	var index uint64
+	for _, ids := range partitionAll(maxIdsPerReap, evalIDs) {
+		req := structs.EvalDeleteRequest{Evals: ids, WriteRequest: args.WriteRequest}
+		if _, index, err = e.srv.raftApply(structs.EvalDeleteRequestType, req); err != nil {
+			return err
+		}
+	}
+
+	reply.Count = len(evalIDs)
+	reply.Index = index
+	return nil
+}
+
+// evalDeleteSafe returns whether the evaluation can be deleted without
+// affecting its job. The job must be deleted, stopped or dead, and none of
+// the evaluation's allocations may be running, unknown, or failed without
+// having been rescheduled.
+func evalDeleteSafe(store *state.StateStore, eval *structs.Evaluation) (bool, error) {
+	job, err := store.JobByID(nil, eval.Namespace, eval.JobID)
+	if err != nil {
+		return false, err
+	}
+	if job != nil && !job.Stop && job.Status != structs.JobStatusDead {
+		return false, nil
+	}
+
+	allocs, err := store.AllocsByEval(nil, eval.ID)
+	if err != nil {
+		return false, err
+	}
+	for _, alloc := range allocs {
+		switch alloc.ClientStatus {
+		case structs.AllocClientStatusRunning, structs.AllocClientStatusUnknown:
+			return false, nil
+		case structs.AllocClientStatusFailed:
+			if alloc.NextAllocation == "" {
+				return false, nil
+			}
+		}
+	}
+	return true, nil
+}
+
+// evalsToDelete returns the evaluations selected by the IDs or filter
+// expression of the delete request.
+func (e *Eval) evalsToDelete(store *state.StateStore, args *structs.EvalDeleteRequest) ([]*structs.Evaluation, error) {
+	var evals []*structs.Evaluation
+
+	if len(args.Evals) > 0 {
+		for _, id := range args.Evals {
+			eval, err := store.EvalByID(nil, id)
+			if err != nil {
+				return nil, err
+			}
+			if eval == nil {
+				return nil, structs.NewErrRPCCodedf(http.StatusNotFound, "eval %s not found", id)
+			}
+			evals = append(evals, eval)
+		}
+		return evals, nil
+	}
+
+	filter, err := newFilter(args.Filter, (*structs.Evaluation)(nil))
+	if err != nil {
+		return nil, err
+	}
+	iter, err := store.Evals(nil, state.SortDefault)
+	if err != nil {
+		return nil, err
+	}
+	for raw := iter.Next(); raw != nil; raw = iter.Next() {
+		eval := raw.(*structs.Evaluation)
+		if match, err := filter.Match(eval); err != nil {
+			return nil, err
+		} else if match {
+			evals = append(evals, eval)
+		}
+	}
+	return evals, nil
+}
+
+// Count is used to count the evaluations across all namespaces that match
+// the filter expression of the query. It allows operators to preview the
+// effect of deleting evaluations by filter.
+func (e *Eval) Count(args *structs.EvalCountRequest, reply *structs.EvalCountResponse) error {
+	if done, err := e.srv.forward("Eval.Count", args, args, reply); done {
+		return err
+	}
+	defer metrics.MeasureSince([]string{"nomad", "eval", "count"}, time.Now())
+
+	// Only count evaluations in namespaces the token can read jobs in
+	aclObj, err := e.srv.ResolveToken(args.AuthToken)
+	if err != nil {
+		return err
+	}
+
+	filter, err := newListFilter(&args.QueryOptions, (*structs.Evaluation)(nil))
+	if err != nil {
+		return err
+	}
+
+	// Setup the blocking query
+	opts := blockingOptions{
+		queryOpts: &args.QueryOptions,
+		queryMeta: &reply.QueryMeta,
+		run: func(ws memdb.WatchSet, store *state.StateStore) error {
+			iter, err := store.Evals(ws, state.SortDefault)
+			if err != nil {
+				return err
+			}
+
+			count := 0
+			for raw := iter.Next(); raw != nil; raw = iter.Next() {
+				eval := raw.(*structs.Evaluation)
+				if aclObj != nil && !aclObj.AllowNsOp(eval.Namespace, acl.NamespaceCapabilityReadJob) {
+					continue
+				}
+				if match, err := filter.Match(eval); err != nil {
+					return err
+				} else if match {
+					count++
+				}
+			}
+			reply.Count = count
+
+			// Use the last index that affected the evals table
+			index, err := store.Index("evals")
+			if err != nil {
+				return err
+			}
+			reply.Index = helper.Uint64Max(1, index)
+
+			// Set the query response
+			e.srv.setQueryMeta(&reply.QueryMeta)
+			return nil
+		}}
+	return e.srv.blockingRPC(&opts)
+}
+
 // List is used to get a list of the evaluations in the system
 func (e *Eval) List(args *structs.EvalListRequest,
 	reply *structs.EvalListResponse) error {
@@ -345,36 +543,49 @@ func (e *Eval) List(args *structs.EvalListRequest,
 		return structs.ErrPermissionDenied
 	}
 
+	filter, err := newListFilter(&args.QueryOptions, (*structs.Evaluation)(nil))
+	if err != nil {
+		return err
+	}
+	sort := state.SortOption(args.Reverse)
+
 	// Setup the blocking query
 	opts := blockingOptions{
 		queryOpts: &args.QueryOptions,
 		queryMeta: &reply.QueryMeta,
-		run: func(ws memdb.WatchSet, state *state.StateStore) error {
+		run: func(ws memdb.WatchSet, store *state.StateStore) error {
 			// Scan all the evaluations
 			var err error
 			var iter memdb.ResultIterator
 			if prefix := args.QueryOptions.Prefix; prefix != "" {
-				iter, err = state.EvalsByIDPrefix(ws, args.RequestNamespace(), prefix)
+				iter, err = store.EvalsByIDPrefix(ws, args.RequestNamespace(), prefix, sort)
 			} else {
-				iter, err = state.EvalsByNamespace(ws, args.RequestNamespace())
+				iter, err = store.EvalsByNamespace(ws, args.RequestNamespace(), sort)
 			}
 			if err != nil {
 				return err
 			}
 
 			var evals []*structs.Evaluation
-			for {
-				raw := iter.Next()
-				if raw == nil {
-					break
-				}
-				eval := raw.(*structs.Evaluation)
-				evals = append(evals, eval)
+			paginator := state.NewPaginator(iter, state.IDTokenizer{}, args.QueryOptions,
+				func(raw interface{}) (bool, error) {
+					eval := raw.(*structs.Evaluation)
+					if match, err := filter.Match(eval); err != nil || !match {
+						return false, err
+					}
+					evals = append(evals, eval)
+					return true, nil
+				})
+
+			nextToken, err := paginator.Page()
+			if err != nil {
+				return err
 			}
+			reply.QueryMeta.NextToken = nextToken
 			reply.Evaluations = evals
 
 			// Use the last index that affected the jobs table
-			index, err := state.Index("evals")
+			index, err := store.Index("evals")
 			if err != nil {
 				return err
 			}
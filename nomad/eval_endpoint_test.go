@@ -662,6 +662,144 @@ func TestEvalEndpoint_Reap(t *testing.T) {
 	}
 }
 
+func TestEvalEndpoint_Delete(t *testing.T) {
+	t.Parallel()
+
+	s1, cleanupS1 := TestServer(t, nil)
+	defer cleanupS1()
+	codec := rpcClient(t, s1)
+	testutil.WaitForLeader(t, s1.RPC)
+
+	store := s1.fsm.State()
+	eval1, eval2, eval3 := mock.Eval(), mock.Eval(), mock.Eval()
+	eval1.JobID = "flood"
+	eval2.JobID = "flood"
+	require.NoError(t, store.UpsertEvals(structs.MsgTypeTestSetup, 1000,
+		[]*structs.Evaluation{eval1, eval2, eval3}))
+
+	// eval3 has a running allocation so it is not safe to delete.
+	alloc := mock.Alloc()
+	alloc.EvalID = eval3.ID
+	alloc.ClientStatus = structs.AllocClientStatusRunning
+	require.NoError(t, store.UpsertAllocs(structs.MsgTypeTestSetup, 1001, []*structs.Allocation{alloc}))
+
+	// Deleting evals requires the eval broker to be paused.
+	req := &structs.EvalDeleteRequest{
+		Filter:       `JobID == "flood"`,
+		WriteRequest: structs.WriteRequest{Region: "global"},
+	}
+	var resp structs.EvalDeleteResponse
+	err := msgpackrpc.CallWithCodec(codec, "Eval.Delete", req, &resp)
+	require.Error(t, err)
+	require.Contains(t, err.Error(), "eval broker must be paused")
+
+	schedReq := &structs.SchedulerSetConfigRequest{
+		Config:       structs.SchedulerConfiguration{PauseEvalBroker: true},
+		WriteRequest: structs.WriteRequest{Region: "global"},
+	}
+	var schedResp structs.SchedulerSetConfigurationResponse
+	require.NoError(t, msgpackrpc.CallWithCodec(codec, "Operator.SchedulerSetConfiguration", schedReq, &schedResp))
+	require.False(t, s1.evalBroker.Enabled())
+
+	// The dry-run count matches the evals selected by the filter.
+	countReq := &structs.EvalCountRequest{
+		QueryOptions: structs.QueryOptions{Region: "global", Filter: `JobID == "flood"`},
+	}
+	var countResp structs.EvalCountResponse
+	require.NoError(t, msgpackrpc.CallWithCodec(codec, "Eval.Count", countReq, &countResp))
+	require.Equal(t, 2, countResp.Count)
+
+	require.NoError(t, msgpackrpc.CallWithCodec(codec, "Eval.Delete", req, &resp))
+	require.Equal(t, 2, resp.Count)
+	require.NotZero(t, resp.Index)
+
+	for _, id := range []string{eval1.ID, eval2.ID} {
+		out, err := store.EvalByID(nil, id)
+		require.NoError(t, err)
+		require.Nil(t, out)
+	}
+
+	// Evals with non-terminal allocations are refused.
+	req = &structs.EvalDeleteRequest{
+		Evals:        []string{eval3.ID},
+		WriteRequest: structs.WriteRequest{Region: "global"},
+	}
+	err = msgpackrpc.CallWithCodec(codec, "Eval.Delete", req, &resp)
+	require.Error(t, err)
+	require.Contains(t, err.Error(), "not safe to delete")
+
+	// Evals of live jobs are refused even if their allocations are complete,
+	// and deleting evals of stopped jobs leaves their allocations in place.
+	job := mock.BatchJob()
+	require.NoError(t, store.UpsertJob(structs.MsgTypeTestSetup, 1002, job))
+	eval4 := mock.Eval()
+	eval4.JobID = job.ID
+	require.NoError(t, store.UpsertEvals(structs.MsgTypeTestSetup, 1003, []*structs.Evaluation{eval4}))
+	completeAlloc := mock.Alloc()
+	completeAlloc.JobID = job.ID
+	completeAlloc.EvalID = eval4.ID
+	completeAlloc.ClientStatus = structs.AllocClientStatusComplete
+	require.NoError(t, store.UpsertAllocs(structs.MsgTypeTestSetup, 1004, []*structs.Allocation{completeAlloc}))
+
+	req.Evals = []string{eval4.ID}
+	err = msgpackrpc.CallWithCodec(codec, "Eval.Delete", req, &resp)
+	require.Error(t, err)
+	require.Contains(t, err.Error(), "not safe to delete")
+
+	job = job.Copy()
+	job.Stop = true
+	require.NoError(t, store.UpsertJob(structs.MsgTypeTestSetup, 1005, job))
+	require.NoError(t, msgpackrpc.CallWithCodec(codec, "Eval.Delete", req, &resp))
+	require.Equal(t, 1, resp.Count)
+
+	out, err := store.EvalByID(nil, eval4.ID)
+	require.NoError(t, err)
+	require.Nil(t, out)
+	outAlloc, err := store.AllocByID(nil, completeAlloc.ID)
+	require.NoError(t, err)
+	require.NotNil(t, outAlloc)
+
+	// Resuming the broker enables it and the blocked evals tracker again.
+	schedReq.Config.PauseEvalBroker = false
+	require.NoError(t, msgpackrpc.CallWithCodec(codec, "Operator.SchedulerSetConfiguration", schedReq, &schedResp))
+	require.True(t, s1.evalBroker.Enabled())
+	require.True(t, s1.blockedEvals.Enabled())
+}
+
+func TestEvalEndpoint_Delete_ACL(t *testing.T) {
+	t.Parallel()
+
+	s1, root, cleanupS1 := TestACLServer(t, func(c *Config) {
+		c.DefaultSchedulerConfig.PauseEvalBroker = true
+	})
+	defer cleanupS1()
+	codec := rpcClient(t, s1)
+	testutil.WaitForLeader(t, s1.RPC)
+	require.False(t, s1.evalBroker.Enabled())
+
+	store := s1.fsm.State()
+	eval := mock.Eval()
+	require.NoError(t, store.UpsertEvals(structs.MsgTypeTestSetup, 1000, []*structs.Evaluation{eval}))
+
+	token := mock.CreatePolicyAndToken(t, store, 1001, "read-job",
+		mock.NamespacePolicy(structs.DefaultNamespace, "", []string{acl.NamespaceCapabilityReadJob}))
+
+	req := &structs.EvalDeleteRequest{
+		Evals: []string{eval.ID},
+		WriteRequest: structs.WriteRequest{
+			Region:    "global",
+			AuthToken: token.SecretID,
+		},
+	}
+	var resp structs.EvalDeleteResponse
+	err := msgpackrpc.CallWithCodec(codec, "Eval.Delete", req, &resp)
+	require.EqualError(t, err, structs.ErrPermissionDenied.Error())
+
+	req.AuthToken = root.SecretID
+	require.NoError(t, msgpackrpc.CallWithCodec(codec, "Eval.Delete", req, &resp))
+	require.Equal(t, 1, resp.Count)
+}
+
 func TestEvalEndpoint_List(t *testing.T) {
 	t.Parallel()
 
@@ -718,6 +856,115 @@ func TestEvalEndpoint_List(t *testing.T) {
 
 }
 
+func TestEvalEndpoint_List_Pagination(t *testing.T) {
+	t.Parallel()
+
+	s1, cleanupS1 := TestServer(t, nil)
+	defer cleanupS1()
+	codec := rpcClient(t, s1)
+	testutil.WaitForLeader(t, s1.RPC)
+
+	ids := []string{
+		"aaaa1111-3350-4b4b-d185-0e1992ed43e9",
+		"aaaaaa22-3350-4b4b-d185-0e1992ed43e9",
+		"aaaaaa33-3350-4b4b-d185-0e1992ed43e9",
+		"aaaaaaaa-3350-4b4b-d185-0e1992ed43e9",
+		"aaaaaabb-3350-4b4b-d185-0e1992ed43e9",
+	}
+	evals := []*structs.Evaluation{}
+	for _, id := range ids {
+		eval := mock.Eval()
+		eval.ID = id
+		evals = append(evals, eval)
+	}
+	evals[2].Status = structs.EvalStatusComplete
+	require.NoError(t, s1.fsm.State().UpsertEvals(structs.MsgTypeTestSetup, 1000, evals))
+
+	cases := []struct {
+		name              string
+		prefix            string
+		filter            string
+		nextToken         string
+		reverse           bool
+		pageSize          int32
+		expectedNextToken string
+		expectedIDs       []string
+	}{
+		{
+			name:              "first page",
+			pageSize:          2,
+			expectedNextToken: ids[2],
+			expectedIDs:       ids[:2],
+		},
+		{
+			name:        "last page",
+			pageSize:    3,
+			nextToken:   ids[2],
+			expectedIDs: ids[2:],
+		},
+		{
+			name:              "first page with prefix",
+			prefix:            "aaaaaa",
+			pageSize:          2,
+			expectedNextToken: ids[3],
+			expectedIDs:       ids[1:3],
+		},
+		{
+			name:              "first page with filter",
+			filter:            `Status == "pending"`,
+			pageSize:          2,
+			expectedNextToken: ids[2],
+			expectedIDs:       []string{ids[0], ids[1]},
+		},
+		{
+			name:        "second page with filter",
+			filter:      `Status == "pending"`,
+			pageSize:    2,
+			nextToken:   ids[2],
+			expectedIDs: []string{ids[3], ids[4]},
+		},
+		{
+			name:              "first page reversed",
+			reverse:           true,
+			pageSize:          2,
+			expectedNextToken: ids[2],
+			expectedIDs:       []string{ids[4], ids[3]},
+		},
+		{
+			name:        "last page reversed",
+			reverse:     true,
+			pageSize:    3,
+			nextToken:   ids[2],
+			expectedIDs: []string{ids[2], ids[1], ids[0]},
+		},
+	}
+
+	for _, tc := range cases {
+		t.Run(tc.name, func(t *testing.T) {
+			req := &structs.EvalListRequest{
+				QueryOptions: structs.QueryOptions{
+					Region:    "global",
+					Namespace: structs.DefaultNamespace,
+					Prefix:    tc.prefix,
+					Filter:    tc.filter,
+					PerPage:   tc.pageSize,
+					NextToken: tc.nextToken,
+					Reverse:   tc.reverse,
+				},
+			}
+			var resp structs.EvalListResponse
+			require.NoError(t, msgpackrpc.CallWithCodec(codec, "Eval.List", req, &resp))
+
+			gotIDs := []string{}
+			for _, eval := range resp.Evaluations {
+				gotIDs = append(gotIDs, eval.ID)
+			}
+			require.Equal(t, tc.expectedIDs, gotIDs)
+			require.Equal(t, tc.expectedNextToken, resp.QueryMeta.NextToken)
+		})
+	}
+}
+
 func TestEvalEndpoint_List_ACL(t *testing.T) {
 	t.Parallel()
 
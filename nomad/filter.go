@@ -0,0 +1,157 @@
+package nomad
+
+import (
+	"fmt"
+	"net/http"
+	"reflect"
+	"strconv"
+	"strings"
+
+	"github.com/hashicorp/go-bexpr"
+	"github.com/hashicorp/go-bexpr/grammar"
+	"github.com/hashicorp/nomad/nomad/structs"
+)
+
+// filterTagName is the struct tag bexpr uses to rename selectable fields.
+const filterTagName = "bexpr"
+
+// listFilter evaluates the filter expression of a list query against the
+// objects returned by the query. A nil listFilter matches every object.
+type listFilter struct {
+	evaluator *bexpr.Evaluator
+}
+
+// newListFilter returns the filter for the query, or nil if the query has no
+// filter expression. obj is a value of the type the filter is evaluated
+// against, typically a nil pointer, and is used to validate the expression's
+// selectors. Invalid expressions are returned as a coded error so they are
+// reported to HTTP clients as a bad request.
+func newListFilter(opts *structs.QueryOptions, obj interface{}) (*listFilter, error) {
+	return newFilter(opts.Filter, obj)
+}
+
+// newFilter returns the filter for the given expression, or nil if the
+// expression is empty. The expression is validated against the type of obj
+// so that invalid selectors are rejected even if there are no objects to
+// evaluate the filter against.
+func newFilter(expr string, obj interface{}) (*listFilter, error) {
+	if expr == "" {
+		return nil, nil
+	}
+
+	ast, err := grammar.Parse("", []byte(expr))
+	if err != nil {
+		return nil, structs.NewErrRPCCodedf(http.StatusBadRequest,
+			"failed to read filter expression: %v", err)
+	}
+	if err := validateFilter(ast.(grammar.Expression), reflect.TypeOf(obj)); err != nil {
+		return nil, structs.NewErrRPCCodedf(http.StatusBadRequest,
+			"failed to read filter expression: %v", err)
+	}
+
+	evaluator, err := bexpr.CreateEvaluator(expr)
+	if err != nil {
+		return nil, structs.NewErrRPCCodedf(http.StatusBadRequest,
+			"failed to read filter expression: %v", err)
+	}
+	return &listFilter{evaluator: evaluator}, nil
+}
+
+// Match returns whether the object matches the filter expression. Errors
+// evaluating the expression, such as selectors which do not exist on the
+// object, are returned as a coded error.
+func (f *listFilter) Match(obj interface{}) (bool, error) {
+	if f == nil {
+		return true, nil
+	}
+
+	match, err := f.evaluator.Evaluate(obj)
+	if err != nil {
+		return false, structs.NewErrRPCCodedf(http.StatusBadRequest,
+			"failed to evaluate filter expression: %v", err)
+	}
+	return match, nil
+}
+
+// validateFilter checks every selector of the expression against typ. A nil
+// typ skips validation.
+func validateFilter(expr grammar.Expression, typ reflect.Type) error {
+	if typ == nil {
+		return nil
+	}
+
+	switch e := expr.(type) {
+	case *grammar.UnaryExpression:
+		return validateFilter(e.Operand, typ)
+	case *grammar.BinaryExpression:
+		if err := validateFilter(e.Left, typ); err != nil {
+			return err
+		}
+		return validateFilter(e.Right, typ)
+	case *grammar.MatchExpression:
+		return validateSelector(e.Selector, typ)
+	}
+	return nil
+}
+
+// validateSelector walks the selector's path through typ the same way bexpr
+// walks values during evaluation. Map keys and slice lengths are only known
+// at evaluation time, so only the shape of the path is checked for them.
+func validateSelector(sel grammar.Selector, typ reflect.Type) error {
+	for _, part := range sel.Path {
+		for typ.Kind() == reflect.Ptr {
+			typ = typ.Elem()
+		}
+
+		switch typ.Kind() {
+		case reflect.Interface:
+			// The concrete type is only known at evaluation time
+			return nil
+		case reflect.Map:
+			typ = typ.Elem()
+		case reflect.Slice, reflect.Array:
+			if _, err := strconv.Atoi(part); err != nil {
+				return fmt.Errorf("selector %q: %q is not a valid index", sel, part)
+			}
+			typ = typ.Elem()
+		case reflect.Struct:
+			field, ok := filterField(typ, part)
+			if !ok {
+				return fmt.Errorf("selector %q: unknown field %q", sel, part)
+			}
+			typ = field.Type
+		default:
+			return fmt.Errorf("selector %q: cannot select %q from a %s", sel, part, typ.Kind())
+		}
+	}
+	return nil
+}
+
+// filterField returns the exported field of the struct type selected by
+// name, honoring the bexpr struct tag.
+func filterField(typ reflect.Type, name string) (reflect.StructField, bool) {
+	var found reflect.StructField
+	var ok bool
+	for i := 0; i < typ.NumField(); i++ {
+		field := typ.Field(i)
+		if field.PkgPath != "" {
+			continue
+		}
+
+		tag := field.Tag.Get(filterTagName)
+		if idx := strings.Index(tag, ","); idx != -1 {
+			tag = tag[:idx]
+		}
+		switch {
+		case tag == "-":
+			continue
+		case tag != "":
+			if tag == name {
+				return field, true
+			}
+		case field.Name == name:
+			found, ok = field, true
+		}
+	}
+	return found, ok
+}
@@ -53,6 +53,12 @@ const (
 	CSIVolumeSnapshot                    SnapshotType = 18
 	ScalingEventsSnapshot                SnapshotType = 19
 	EventSinkSnapshot                    SnapshotType = 20
+	ServiceRegistrationSnapshot          SnapshotType = 21
+	VariablesSnapshot                    SnapshotType = 22
+	RootKeyMetaSnapshot                  SnapshotType = 23
+	ACLTokenEncryptedSnapshot            SnapshotType = 24
+	NodePoolSnapshot                     SnapshotType = 25
+	ReservationSnapshot                  SnapshotType = 26
 	// Namespace appliers were moved from enterprise and therefore start at 64
 	NamespaceSnapshot SnapshotType = 64
 )
@@ -77,6 +83,7 @@ type SnapshotRestorers map[SnapshotType]SnapshotRestorer
 type nomadFSM struct {
 	evalBroker         *EvalBroker
 	blockedEvals       *BlockedEvals
+	waitingEvals       *WaitingEvals
 	periodicDispatcher *PeriodicDispatch
 	logger             log.Logger
 	state              *state.StateStore
@@ -123,6 +130,10 @@ type FSMConfig struct {
 	// be added to.
 	Blocked *BlockedEvals
 
+	// Waiting is the waiting eval tracker that evaluations waiting on the
+	// dependencies of their job should be added to.
+	Waiting *WaitingEvals
+
 	// Logger is the logger used by the FSM
 	Logger log.Logger
 
@@ -135,6 +146,11 @@ type FSMConfig struct {
 
 	// EventBufferSize is the amount of messages to hold in memory
 	EventBufferSize int64
+
+	// Encrypter is the keyring from which root keys are removed once they
+	// are deleted. It may be nil in tests. The FSM never decrypts data with
+	// it, so that applying the log never depends on the keyring.
+	Encrypter *Encrypter
 }
 
 // NewFSM is used to construct a new FSM with a blank state.
@@ -155,6 +171,7 @@ func NewFSM(config *FSMConfig) (*nomadFSM, error) {
 		evalBroker:          config.EvalBroker,
 		periodicDispatcher:  config.Periodic,
 		blockedEvals:        config.Blocked,
+		waitingEvals:        config.Waiting,
 		logger:              config.Logger.Named("fsm"),
 		config:              config,
 		state:               state,
@@ -305,6 +322,26 @@ func (n *nomadFSM) Apply(log *raft.Log) interface{} {
 		return n.applyOneTimeTokenDelete(msgType, buf[1:], log.Index)
 	case structs.OneTimeTokenExpireRequestType:
 		return n.applyOneTimeTokenExpire(msgType, buf[1:], log.Index)
+	case structs.ServiceRegistrationUpsertRequestType:
+		return n.applyUpsertServiceRegistrations(msgType, buf[1:], log.Index)
+	case structs.ServiceRegistrationDeleteByIDRequestType:
+		return n.applyDeleteServiceRegistrationByID(msgType, buf[1:], log.Index)
+	case structs.ServiceRegistrationDeleteByNodeIDRequestType:
+		return n.applyDeleteServiceRegistrationByNodeID(msgType, buf[1:], log.Index)
+	case structs.VarApplyStateRequestType:
+		return n.applyVariableOperation(msgType, buf[1:], log.Index)
+	case structs.RootKeyMetaUpsertRequestType:
+		return n.applyRootKeyMetaUpsert(msgType, buf[1:], log.Index)
+	case structs.RootKeyMetaDeleteRequestType:
+		return n.applyRootKeyMetaDelete(msgType, buf[1:], log.Index)
+	case structs.NodePoolUpsertRequestType:
+		return n.applyNodePoolUpsert(msgType, buf[1:], log.Index)
+	case structs.NodePoolDeleteRequestType:
+		return n.applyNodePoolDelete(msgType, buf[1:], log.Index)
+	case structs.ReservationUpsertRequestType:
+		return n.applyReservationUpsert(msgType, buf[1:], log.Index)
+	case structs.ReservationDeleteRequestType:
+		return n.applyReservationDelete(msgType, buf[1:], log.Index)
 	}
 
 	// Check enterprise only message types.
@@ -746,6 +783,8 @@ func (n *nomadFSM) handleUpsertedEval(eval *structs.Evaluation) {
 		n.evalBroker.Enqueue(eval)
 	} else if eval.ShouldBlock() {
 		n.blockedEvals.Block(eval)
+	} else if eval.Status == structs.EvalStatusWaiting {
+		n.waitingEvals.Track(eval)
 	} else if eval.Status == structs.EvalStatusComplete &&
 		len(eval.FailedTGAllocs) == 0 {
 		// If we have a successful evaluation for a node, untrack any
@@ -1115,7 +1154,31 @@ func (n *nomadFSM) applyACLTokenUpsert(msgType structs.MessageType, buf []byte,
 		panic(fmt.Errorf("failed to decode request: %v", err))
 	}
 
-	if err := n.state.UpsertACLTokens(msgType, index, req.Tokens); err != nil {
+	// Encrypted tokens are stored as is, the FSM never decrypts them
+	tokens := req.Tokens
+	if len(req.EncryptedTokens) > 0 {
+		tokens = make([]*structs.ACLToken, 0, len(req.EncryptedTokens))
+		for _, encrypted := range req.EncryptedTokens {
+			token := encrypted.ACLToken()
+			if req.Rekey {
+				existing, err := n.state.ACLTokenByAccessorID(nil, token.AccessorID)
+				if err != nil {
+					n.logger.Error("UpsertACLTokens failed", "error", err)
+					return err
+				}
+				if existing == nil || existing.SecretHash != token.SecretHash {
+					continue
+				}
+				rekeyed := existing.Copy()
+				rekeyed.EncryptedSecretID = token.EncryptedSecretID
+				rekeyed.SecretKeyID = token.SecretKeyID
+				token = rekeyed
+			}
+			tokens = append(tokens, token)
+		}
+	}
+
+	if err := n.state.UpsertACLTokens(msgType, index, tokens); err != nil {
 		n.logger.Error("UpsertACLTokens failed", "error", err)
 		return err
 	}
@@ -1145,7 +1208,12 @@ func (n *nomadFSM) applyACLTokenBootstrap(msgType structs.MessageType, buf []byt
 		panic(fmt.Errorf("failed to decode request: %v", err))
 	}
 
-	if err := n.state.BootstrapACLTokens(msgType, index, req.ResetIndex, req.Token); err != nil {
+	token := req.Token
+	if req.EncryptedToken != nil {
+		token = req.EncryptedToken.ACLToken()
+	}
+
+	if err := n.state.BootstrapACLTokens(msgType, index, req.ResetIndex, token); err != nil {
 		n.logger.Error("BootstrapACLToken failed", "error", err)
 		return err
 	}
@@ -1197,6 +1265,164 @@ func (n *nomadFSM) applyOneTimeTokenExpire(msgType structs.MessageType, buf []by
 	return nil
 }
 
+func (n *nomadFSM) applyUpsertServiceRegistrations(msgType structs.MessageType, buf []byte, index uint64) interface{} {
+	defer metrics.MeasureSince([]string{"nomad", "fsm", "apply_service_registration_upsert"}, time.Now())
+	var req structs.ServiceRegistrationUpsertRequest
+	if err := structs.Decode(buf, &req); err != nil {
+		panic(fmt.Errorf("failed to decode request: %v", err))
+	}
+
+	if err := n.state.UpsertServiceRegistrations(msgType, index, req.Services); err != nil {
+		n.logger.Error("UpsertServiceRegistrations failed", "error", err)
+		return err
+	}
+	return nil
+}
+
+func (n *nomadFSM) applyDeleteServiceRegistrationByID(msgType structs.MessageType, buf []byte, index uint64) interface{} {
+	defer metrics.MeasureSince([]string{"nomad", "fsm", "apply_service_registration_delete_id"}, time.Now())
+	var req structs.ServiceRegistrationDeleteByIDRequest
+	if err := structs.Decode(buf, &req); err != nil {
+		panic(fmt.Errorf("failed to decode request: %v", err))
+	}
+
+	if err := n.state.DeleteServiceRegistrationByID(msgType, index, req.RequestNamespace(), req.ID); err != nil {
+		n.logger.Error("DeleteServiceRegistrationByID failed", "error", err)
+		return err
+	}
+	return nil
+}
+
+func (n *nomadFSM) applyDeleteServiceRegistrationByNodeID(msgType structs.MessageType, buf []byte, index uint64) interface{} {
+	defer metrics.MeasureSince([]string{"nomad", "fsm", "apply_service_registration_delete_node_id"}, time.Now())
+	var req structs.ServiceRegistrationDeleteByNodeIDRequest
+	if err := structs.Decode(buf, &req); err != nil {
+		panic(fmt.Errorf("failed to decode request: %v", err))
+	}
+
+	if err := n.state.DeleteServiceRegistrationByNodeID(msgType, index, req.NodeID); err != nil {
+		n.logger.Error("DeleteServiceRegistrationByNodeID failed", "error", err)
+		return err
+	}
+	return nil
+}
+
+func (n *nomadFSM) applyVariableOperation(msgType structs.MessageType, buf []byte, index uint64) interface{} {
+	var req structs.VarApplyStateRequest
+	if err := structs.Decode(buf, &req); err != nil {
+		panic(fmt.Errorf("failed to decode request: %v", err))
+	}
+	defer metrics.MeasureSinceWithLabels([]string{"nomad", "fsm", "apply_variable_op"}, time.Now(),
+		[]metrics.Label{{Name: "op", Value: string(req.Op)}})
+
+	switch req.Op {
+	case structs.VarOpSet:
+		return n.state.VarSet(msgType, index, &req)
+	case structs.VarOpDelete:
+		return n.state.VarDelete(msgType, index, &req)
+	case structs.VarOpDeleteCAS:
+		return n.state.VarDeleteCAS(msgType, index, &req)
+	case structs.VarOpCAS:
+		return n.state.VarSetCAS(msgType, index, &req)
+	default:
+		err := fmt.Errorf("Invalid variable operation '%s'", req.Op)
+		n.logger.Warn("Invalid variable operation", "operation", req.Op)
+		return err
+	}
+}
+
+func (n *nomadFSM) applyRootKeyMetaUpsert(msgType structs.MessageType, buf []byte, index uint64) interface{} {
+	defer metrics.MeasureSince([]string{"nomad", "fsm", "apply_root_key_meta_upsert"}, time.Now())
+	var req structs.KeyringUpdateRootKeyMetaRequest
+	if err := structs.Decode(buf, &req); err != nil {
+		panic(fmt.Errorf("failed to decode request: %v", err))
+	}
+
+	if err := n.state.UpsertRootKeyMeta(msgType, index, req.RootKeyMeta); err != nil {
+		n.logger.Error("UpsertRootKeyMeta failed", "error", err)
+		return err
+	}
+	return nil
+}
+
+func (n *nomadFSM) applyNodePoolUpsert(msgType structs.MessageType, buf []byte, index uint64) interface{} {
+	defer metrics.MeasureSince([]string{"nomad", "fsm", "apply_node_pool_upsert"}, time.Now())
+	var req structs.NodePoolUpsertRequest
+	if err := structs.Decode(buf, &req); err != nil {
+		panic(fmt.Errorf("failed to decode request: %v", err))
+	}
+
+	if err := n.state.UpsertNodePools(msgType, index, req.NodePools); err != nil {
+		n.logger.Error("UpsertNodePools failed", "error", err)
+		return err
+	}
+	return nil
+}
+
+func (n *nomadFSM) applyNodePoolDelete(msgType structs.MessageType, buf []byte, index uint64) interface{} {
+	defer metrics.MeasureSince([]string{"nomad", "fsm", "apply_node_pool_delete"}, time.Now())
+	var req structs.NodePoolDeleteRequest
+	if err := structs.Decode(buf, &req); err != nil {
+		panic(fmt.Errorf("failed to decode request: %v", err))
+	}
+
+	if err := n.state.DeleteNodePools(msgType, index, req.Names); err != nil {
+		n.logger.Error("DeleteNodePools failed", "error", err)
+		return err
+	}
+	return nil
+}
+
+func (n *nomadFSM) applyReservationUpsert(msgType structs.MessageType, buf []byte, index uint64) interface{} {
+	defer metrics.MeasureSince([]string{"nomad", "fsm", "apply_reservation_upsert"}, time.Now())
+	var req structs.ReservationUpsertRequest
+	if err := structs.Decode(buf, &req); err != nil {
+		panic(fmt.Errorf("failed to decode request: %v", err))
+	}
+
+	if err := n.state.UpsertReservation(msgType, index, req.Reservation); err != nil {
+		n.logger.Error("UpsertReservation failed", "error", err)
+		return err
+	}
+	return nil
+}
+
+func (n *nomadFSM) applyReservationDelete(msgType structs.MessageType, buf []byte, index uint64) interface{} {
+	defer metrics.MeasureSince([]string{"nomad", "fsm", "apply_reservation_delete"}, time.Now())
+	var req structs.ReservationDeleteRequest
+	if err := structs.Decode(buf, &req); err != nil {
+		panic(fmt.Errorf("failed to decode request: %v", err))
+	}
+
+	if err := n.state.DeleteReservation(msgType, index, req.RequestNamespace(), req.Name); err != nil {
+		n.logger.Error("DeleteReservation failed", "error", err)
+		return err
+	}
+	return nil
+}
+
+func (n *nomadFSM) applyRootKeyMetaDelete(msgType structs.MessageType, buf []byte, index uint64) interface{} {
+	defer metrics.MeasureSince([]string{"nomad", "fsm", "apply_root_key_meta_delete"}, time.Now())
+	var req structs.KeyringDeleteRootKeyRequest
+	if err := structs.Decode(buf, &req); err != nil {
+		panic(fmt.Errorf("failed to decode request: %v", err))
+	}
+
+	if err := n.state.DeleteRootKeyMeta(msgType, index, req.KeyID); err != nil {
+		n.logger.Error("DeleteRootKeyMeta failed", "error", err)
+		return err
+	}
+
+	// Remove the key material from this server's keyring now that nothing
+	// can refer to it anymore.
+	if n.config.Encrypter != nil {
+		if err := n.config.Encrypter.RemoveKey(req.KeyID); err != nil {
+			n.logger.Error("failed to remove root key from keystore", "key_id", req.KeyID, "error", err)
+		}
+	}
+	return nil
+}
+
 func (n *nomadFSM) applyAutopilotUpdate(buf []byte, index uint64) interface{} {
 	var req structs.AutopilotSetConfigRequest
 	if err := structs.Decode(buf, &req); err != nil {
@@ -1568,6 +1794,15 @@ func (n *nomadFSM) Restore(old io.ReadCloser) error {
 				return err
 			}
 
+		case ACLTokenEncryptedSnapshot:
+			encrypted := new(structs.ACLTokenEncrypted)
+			if err := dec.Decode(encrypted); err != nil {
+				return err
+			}
+			if err := restore.ACLTokenRestore(encrypted.ACLToken()); err != nil {
+				return err
+			}
+
 		case SchedulerConfigSnapshot:
 			schedConfig := new(structs.SchedulerConfiguration)
 			if err := dec.Decode(schedConfig); err != nil {
@@ -1640,6 +1875,51 @@ func (n *nomadFSM) Restore(old io.ReadCloser) error {
 				return err
 			}
 
+		case ServiceRegistrationSnapshot:
+			serviceRegistration := new(structs.ServiceRegistration)
+			if err := dec.Decode(serviceRegistration); err != nil {
+				return err
+			}
+			if err := restore.ServiceRegistrationRestore(serviceRegistration); err != nil {
+				return err
+			}
+
+		case VariablesSnapshot:
+			variable := new(structs.VariableEncrypted)
+			if err := dec.Decode(variable); err != nil {
+				return err
+			}
+			if err := restore.VariablesRestore(variable); err != nil {
+				return err
+			}
+
+		case RootKeyMetaSnapshot:
+			keyMeta := new(structs.RootKeyMeta)
+			if err := dec.Decode(keyMeta); err != nil {
+				return err
+			}
+			if err := restore.RootKeyMetaRestore(keyMeta); err != nil {
+				return err
+			}
+
+		case NodePoolSnapshot:
+			pool := new(structs.NodePool)
+			if err := dec.Decode(pool); err != nil {
+				return err
+			}
+			if err := restore.NodePoolRestore(pool); err != nil {
+				return err
+			}
+
+		case ReservationSnapshot:
+			reservation := new(structs.Reservation)
+			if err := dec.Decode(reservation); err != nil {
+				return err
+			}
+			if err := restore.ReservationRestore(reservation); err != nil {
+				return err
+			}
+
 		// COMPAT(1.0): Allow 1.0-beta clusterers to gracefully handle
 		case EventSinkSnapshot:
 			return nil
@@ -1740,7 +2020,7 @@ func (n *nomadFSM) failLeakedDeployments(state *state.StateStore) error {
 func (n *nomadFSM) reconcileQueuedAllocations(index uint64) error {
 	// Get all the jobs
 	ws := memdb.NewWatchSet()
-	iter, err := n.state.Jobs(ws)
+	iter, err := n.state.Jobs(ws, state.SortDefault)
 	if err != nil {
 		return err
 	}
@@ -1949,6 +2229,26 @@ func (s *nomadSnapshot) Persist(sink raft.SnapshotSink) error {
 		sink.Cancel()
 		return err
 	}
+	if err := s.persistServiceRegistrations(sink, encoder); err != nil {
+		sink.Cancel()
+		return err
+	}
+	if err := s.persistVariables(sink, encoder); err != nil {
+		sink.Cancel()
+		return err
+	}
+	if err := s.persistRootKeyMeta(sink, encoder); err != nil {
+		sink.Cancel()
+		return err
+	}
+	if err := s.persistNodePools(sink, encoder); err != nil {
+		sink.Cancel()
+		return err
+	}
+	if err := s.persistReservations(sink, encoder); err != nil {
+		sink.Cancel()
+		return err
+	}
 	if err := s.persistEnterpriseTables(sink, encoder); err != nil {
 		sink.Cancel()
 		return err
@@ -2023,7 +2323,7 @@ func (s *nomadSnapshot) persistJobs(sink raft.SnapshotSink,
 	encoder *codec.Encoder) error {
 	// Get all the jobs
 	ws := memdb.NewWatchSet()
-	jobs, err := s.snap.Jobs(ws)
+	jobs, err := s.snap.Jobs(ws, state.SortDefault)
 	if err != nil {
 		return err
 	}
@@ -2051,7 +2351,7 @@ func (s *nomadSnapshot) persistEvals(sink raft.SnapshotSink,
 	encoder *codec.Encoder) error {
 	// Get all the evaluations
 	ws := memdb.NewWatchSet()
-	evals, err := s.snap.Evals(ws)
+	evals, err := s.snap.Evals(ws, state.SortDefault)
 	if err != nil {
 		return err
 	}
@@ -2079,7 +2379,7 @@ func (s *nomadSnapshot) persistAllocs(sink raft.SnapshotSink,
 	encoder *codec.Encoder) error {
 	// Get all the allocations
 	ws := memdb.NewWatchSet()
-	allocs, err := s.snap.Allocs(ws)
+	allocs, err := s.snap.Allocs(ws, state.SortDefault)
 	if err != nil {
 		return err
 	}
@@ -2286,7 +2586,7 @@ func (s *nomadSnapshot) persistACLTokens(sink raft.SnapshotSink,
 	encoder *codec.Encoder) error {
 	// Get all the policies
 	ws := memdb.NewWatchSet()
-	tokens, err := s.snap.ACLTokens(ws)
+	tokens, err := s.snap.ACLTokens(ws, state.SortDefault)
 	if err != nil {
 		return err
 	}
@@ -2298,7 +2598,8 @@ func (s *nomadSnapshot) persistACLTokens(sink raft.SnapshotSink,
 			break
 		}
 
-		// Prepare the request struct
+		// Prepare the request struct. Tokens written once the keyring has
+		// been initialized only carry their encrypted secret.
 		token := raw.(*structs.ACLToken)
 
 		// Write out a token registration
@@ -2338,6 +2639,128 @@ func (s *nomadSnapshot) persistNamespaces(sink raft.SnapshotSink, encoder *codec
 	return nil
 }
 
+// persistServiceRegistrations persists all the Nomad native service
+// registrations.
+func (s *nomadSnapshot) persistServiceRegistrations(sink raft.SnapshotSink,
+	encoder *codec.Encoder) error {
+
+	ws := memdb.NewWatchSet()
+	servicesIter, err := s.snap.GetServiceRegistrations(ws)
+	if err != nil {
+		return err
+	}
+
+	for {
+		raw := servicesIter.Next()
+		if raw == nil {
+			break
+		}
+
+		serviceRegistration := raw.(*structs.ServiceRegistration)
+
+		sink.Write([]byte{byte(ServiceRegistrationSnapshot)})
+		if err := encoder.Encode(serviceRegistration); err != nil {
+			return err
+		}
+	}
+	return nil
+}
+
+// persistVariables persists all the encrypted variables.
+func (s *nomadSnapshot) persistVariables(sink raft.SnapshotSink,
+	encoder *codec.Encoder) error {
+
+	ws := memdb.NewWatchSet()
+	variables, err := s.snap.Variables(ws)
+	if err != nil {
+		return err
+	}
+
+	for {
+		raw := variables.Next()
+		if raw == nil {
+			break
+		}
+		variable := raw.(*structs.VariableEncrypted)
+		sink.Write([]byte{byte(VariablesSnapshot)})
+		if err := encoder.Encode(variable); err != nil {
+			return err
+		}
+	}
+	return nil
+}
+
+// persistRootKeyMeta persists the metadata of all root keys. The key
+// material is kept in each server's keystore and is never written to Raft.
+func (s *nomadSnapshot) persistRootKeyMeta(sink raft.SnapshotSink,
+	encoder *codec.Encoder) error {
+
+	ws := memdb.NewWatchSet()
+	keys, err := s.snap.RootKeyMetas(ws)
+	if err != nil {
+		return err
+	}
+
+	for {
+		raw := keys.Next()
+		if raw == nil {
+			break
+		}
+		key := raw.(*structs.RootKeyMeta)
+		sink.Write([]byte{byte(RootKeyMetaSnapshot)})
+		if err := encoder.Encode(key); err != nil {
+			return err
+		}
+	}
+	return nil
+}
+
+func (s *nomadSnapshot) persistNodePools(sink raft.SnapshotSink,
+	encoder *codec.Encoder) error {
+
+	ws := memdb.NewWatchSet()
+	pools, err := s.snap.NodePools(ws, state.SortDefault)
+	if err != nil {
+		return err
+	}
+
+	for {
+		raw := pools.Next()
+		if raw == nil {
+			break
+		}
+		pool := raw.(*structs.NodePool)
+		sink.Write([]byte{byte(NodePoolSnapshot)})
+		if err := encoder.Encode(pool); err != nil {
+			return err
+		}
+	}
+	return nil
+}
+
+func (s *nomadSnapshot) persistReservations(sink raft.SnapshotSink,
+	encoder *codec.Encoder) error {
+
+	ws := memdb.NewWatchSet()
+	reservations, err := s.snap.Reservations(ws)
+	if err != nil {
+		return err
+	}
+
+	for {
+		raw := reservations.Next()
+		if raw == nil {
+			break
+		}
+		reservation := raw.(*structs.Reservation)
+		sink.Write([]byte{byte(ReservationSnapshot)})
+		if err := encoder.Encode(reservation); err != nil {
+			return err
+		}
+	}
+	return nil
+}
+
 func (s *nomadSnapshot) persistSchedulerConfig(sink raft.SnapshotSink,
 	encoder *codec.Encoder) error {
 	// Get scheduler config
@@ -2383,7 +2806,7 @@ func (s *nomadSnapshot) persistScalingPolicies(sink raft.SnapshotSink,
 
 	// Get all the scaling policies
 	ws := memdb.NewWatchSet()
-	scalingPolicies, err := s.snap.ScalingPolicies(ws)
+	scalingPolicies, err := s.snap.ScalingPolicies(ws, state.SortDefault)
 	if err != nil {
 		return err
 	}
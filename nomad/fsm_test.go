@@ -56,6 +56,7 @@ func testFSM(t *testing.T) *nomadFSM {
 		EvalBroker:        broker,
 		Periodic:          dispatcher,
 		Blocked:           NewBlockedEvals(broker, logger),
+		Waiting:           NewWaitingEvals(nil, logger),
 		Logger:            logger,
 		Region:            "global",
 		EnableEventBroker: true,
@@ -3259,6 +3260,116 @@ func TestFSM_SnapshotRestore_Namespaces(t *testing.T) {
 	}
 }
 
+func TestFSM_UpsertNodePools(t *testing.T) {
+	t.Parallel()
+	fsm := testFSM(t)
+
+	pool := mock.NodePool()
+	req := structs.NodePoolUpsertRequest{
+		NodePools: []*structs.NodePool{pool},
+	}
+	buf, err := structs.Encode(structs.NodePoolUpsertRequestType, req)
+	require.NoError(t, err)
+	require.Nil(t, fsm.Apply(makeLog(buf)))
+
+	out, err := fsm.State().NodePoolByName(nil, pool.Name)
+	require.NoError(t, err)
+	require.NotNil(t, out)
+	require.Equal(t, pool.Description, out.Description)
+}
+
+func TestFSM_DeleteNodePools(t *testing.T) {
+	t.Parallel()
+	fsm := testFSM(t)
+
+	pool := mock.NodePool()
+	require.NoError(t, fsm.State().UpsertNodePools(structs.MsgTypeTestSetup, 1000, []*structs.NodePool{pool}))
+
+	req := structs.NodePoolDeleteRequest{
+		Names: []string{pool.Name},
+	}
+	buf, err := structs.Encode(structs.NodePoolDeleteRequestType, req)
+	require.NoError(t, err)
+	require.Nil(t, fsm.Apply(makeLog(buf)))
+
+	out, err := fsm.State().NodePoolByName(nil, pool.Name)
+	require.NoError(t, err)
+	require.Nil(t, out)
+}
+
+func TestFSM_SnapshotRestore_NodePools(t *testing.T) {
+	t.Parallel()
+	fsm := testFSM(t)
+
+	pool := mock.NodePool()
+	require.NoError(t, fsm.State().UpsertNodePools(structs.MsgTypeTestSetup, 1000, []*structs.NodePool{pool}))
+
+	fsm2 := testSnapshotRestore(t, fsm)
+	out, err := fsm2.State().NodePoolByName(nil, pool.Name)
+	require.NoError(t, err)
+	require.Equal(t, pool, out)
+
+	// The built-in node pools are always present after a restore.
+	out, err = fsm2.State().NodePoolByName(nil, structs.NodePoolDefault)
+	require.NoError(t, err)
+	require.NotNil(t, out)
+}
+
+func TestFSM_UpsertReservation(t *testing.T) {
+	t.Parallel()
+	fsm := testFSM(t)
+
+	reservation := mock.Reservation()
+	reservation.NodeIDs = []string{uuid.Generate()}
+	req := structs.ReservationUpsertRequest{
+		Reservation: reservation,
+	}
+	buf, err := structs.Encode(structs.ReservationUpsertRequestType, req)
+	require.NoError(t, err)
+	require.Nil(t, fsm.Apply(makeLog(buf)))
+
+	out, err := fsm.State().ReservationByName(nil, reservation.Namespace, reservation.Name)
+	require.NoError(t, err)
+	require.NotNil(t, out)
+	require.Equal(t, reservation.NodeIDs, out.NodeIDs)
+}
+
+func TestFSM_DeleteReservation(t *testing.T) {
+	t.Parallel()
+	fsm := testFSM(t)
+
+	reservation := mock.Reservation()
+	require.NoError(t, fsm.State().UpsertReservation(structs.MsgTypeTestSetup, 1000, reservation))
+
+	req := structs.ReservationDeleteRequest{
+		Name: reservation.Name,
+		WriteRequest: structs.WriteRequest{
+			Namespace: reservation.Namespace,
+		},
+	}
+	buf, err := structs.Encode(structs.ReservationDeleteRequestType, req)
+	require.NoError(t, err)
+	require.Nil(t, fsm.Apply(makeLog(buf)))
+
+	out, err := fsm.State().ReservationByName(nil, reservation.Namespace, reservation.Name)
+	require.NoError(t, err)
+	require.Nil(t, out)
+}
+
+func TestFSM_SnapshotRestore_Reservations(t *testing.T) {
+	t.Parallel()
+	fsm := testFSM(t)
+
+	reservation := mock.Reservation()
+	reservation.NodeIDs = []string{uuid.Generate()}
+	require.NoError(t, fsm.State().UpsertReservation(structs.MsgTypeTestSetup, 1000, reservation))
+
+	fsm2 := testSnapshotRestore(t, fsm)
+	out, err := fsm2.State().ReservationByName(nil, reservation.Namespace, reservation.Name)
+	require.NoError(t, err)
+	require.Equal(t, reservation, out)
+}
+
 func TestFSM_ACLEvents(t *testing.T) {
 	t.Parallel()
 
@@ -152,10 +152,17 @@ func (h *nodeHeartbeater) invalidateHeartbeat(id string) {
 
 	h.logger.Warn("node TTL expired", "node_id", id)
 
+	// Nodes running allocations that tolerate disconnected clients are
+	// marked disconnected instead of down.
+	status := structs.NodeStatusDown
+	if h.disconnectState(id) {
+		status = structs.NodeStatusDisconnected
+	}
+
 	// Make a request to update the node status
 	req := structs.NodeUpdateStatusRequest{
 		NodeID:    id,
-		Status:    structs.NodeStatusDown,
+		Status:    status,
 		NodeEvent: structs.NewNodeEvent().SetSubsystem(structs.NodeEventSubsystemCluster).SetMessage(NodeHeartbeatEventMissed),
 		WriteRequest: structs.WriteRequest{
 			Region: h.config.Region,
@@ -167,6 +174,38 @@ func (h *nodeHeartbeater) invalidateHeartbeat(id string) {
 	}
 }
 
+// disconnectState returns true if the node has allocations that support
+// disconnected clients and haven't yet exceeded their max_client_disconnect,
+// in which case the node should be marked disconnected instead of down.
+func (h *nodeHeartbeater) disconnectState(id string) bool {
+	allocs, err := h.State().AllocsByNode(nil, id)
+	if err != nil {
+		h.logger.Error("error retrieving allocs by node", "node_id", id, "error", err)
+		return false
+	}
+
+	now := time.Now().UTC()
+	for _, alloc := range allocs {
+		if alloc.ServerTerminalStatus() || alloc.ClientTerminalStatus() {
+			continue
+		}
+		if !alloc.SupportsDisconnectedClients() {
+			continue
+		}
+
+		switch alloc.ClientStatus {
+		case structs.AllocClientStatusRunning:
+			return true
+		case structs.AllocClientStatusUnknown:
+			if !alloc.Expired(now) {
+				return true
+			}
+		}
+	}
+
+	return false
+}
+
 // clearHeartbeatTimer is used to clear the heartbeat time for
 // a single heartbeat. This is used when a heartbeat is destroyed
 // explicitly and no longer needed.
@@ -7,6 +7,7 @@ import (
 
 	memdb "github.com/hashicorp/go-memdb"
 	msgpackrpc "github.com/hashicorp/net-rpc-msgpackrpc"
+	"github.com/hashicorp/nomad/helper"
 	"github.com/hashicorp/nomad/nomad/mock"
 	"github.com/hashicorp/nomad/nomad/structs"
 	"github.com/hashicorp/nomad/testutil"
@@ -167,6 +168,51 @@ func TestHeartbeat_InvalidateHeartbeat(t *testing.T) {
 	require.Equal(NodeHeartbeatEventMissed, out.Events[1].Message)
 }
 
+func TestHeartbeat_InvalidateHeartbeat_DisconnectedClient(t *testing.T) {
+	t.Parallel()
+	require := require.New(t)
+
+	s1, cleanupS1 := TestServer(t, nil)
+	defer cleanupS1()
+	testutil.WaitForLeader(t, s1.RPC)
+
+	// Create a node with a running alloc that supports disconnected clients
+	node := mock.Node()
+	state := s1.fsm.State()
+	require.NoError(state.UpsertNode(structs.MsgTypeTestSetup, 1, node))
+
+	alloc := mock.Alloc()
+	alloc.NodeID = node.ID
+	alloc.ClientStatus = structs.AllocClientStatusRunning
+	alloc.Job.TaskGroups[0].MaxClientDisconnect = helper.TimeToPtr(5 * time.Minute)
+	require.NoError(state.UpsertJob(structs.MsgTypeTestSetup, 2, alloc.Job))
+	require.NoError(state.UpsertAllocs(structs.MsgTypeTestSetup, 3, []*structs.Allocation{alloc}))
+
+	// This should mark the node as disconnected instead of down
+	s1.invalidateHeartbeat(node.ID)
+
+	out, err := state.NodeByID(nil, node.ID)
+	require.NoError(err)
+	require.Equal(structs.NodeStatusDisconnected, out.Status)
+	require.False(out.TerminalStatus())
+
+	// Once the alloc has been disconnected for too long the node goes down
+	unknown := alloc.Copy()
+	unknown.ClientStatus = structs.AllocClientStatusUnknown
+	unknown.AllocStates = []*structs.AllocState{{
+		Field: structs.AllocStateFieldClientStatus,
+		Value: structs.AllocClientStatusUnknown,
+		Time:  time.Now().Add(-10 * time.Minute),
+	}}
+	require.NoError(state.UpsertAllocs(structs.MsgTypeTestSetup, 4, []*structs.Allocation{unknown}))
+
+	s1.invalidateHeartbeat(node.ID)
+
+	out, err = state.NodeByID(nil, node.ID)
+	require.NoError(err)
+	require.Equal(structs.NodeStatusDown, out.Status)
+}
+
 func TestHeartbeat_ClearHeartbeatTimer(t *testing.T) {
 	t.Parallel()
 
@@ -121,6 +121,14 @@ func (j *Job) Register(args *structs.JobRegisterRequest, reply *structs.JobRegis
 			return structs.ErrPermissionDenied
 		}
 
+		// Validate the token is allowed to use the job's node pool. Jobs in
+		// the default node pool are always allowed so existing tokens keep
+		// working.
+		if args.Job.NodePool != structs.NodePoolDefault &&
+			!aclObj.AllowNodePoolOperation(args.Job.NodePool, acl.NodePoolCapabilityRead) {
+			return structs.ErrPermissionDenied
+		}
+
 		// Validate Volume Permissions
 		for _, tg := range args.Job.TaskGroups {
 			for _, vol := range tg.Volumes {
@@ -206,6 +214,20 @@ func (j *Job) Register(args *structs.JobRegisterRequest, reply *structs.JobRegis
 		return err
 	}
 
+	// Ensure the job's node pool exists
+	pool, err := snap.NodePoolByName(ws, args.Job.NodePool)
+	if err != nil {
+		return err
+	}
+	if pool == nil {
+		return fmt.Errorf("job %q is in nonexistent node pool %q", args.Job.ID, args.Job.NodePool)
+	}
+
+	// Ensure the job's dependencies don't form a cycle
+	if err := validateJobDependencies(snap, args.Job); err != nil {
+		return err
+	}
+
 	// Ensure that all scaling policies have an appropriate ID
 	if err := propagateScalingPolicyIDs(existingJob, args.Job); err != nil {
 		return err
@@ -1323,44 +1345,59 @@ func (j *Job) List(args *structs.JobListRequest, reply *structs.JobListResponse)
 		return structs.ErrPermissionDenied
 	}
 
+	filter, err := newListFilter(&args.QueryOptions, (*structs.JobListStub)(nil))
+	if err != nil {
+		return err
+	}
+	sort := state.SortOption(args.Reverse)
+
 	// Setup the blocking query
 	opts := blockingOptions{
 		queryOpts: &args.QueryOptions,
 		queryMeta: &reply.QueryMeta,
-		run: func(ws memdb.WatchSet, state *state.StateStore) error {
+		run: func(ws memdb.WatchSet, store *state.StateStore) error {
 			// Capture all the jobs
 			var err error
 			var iter memdb.ResultIterator
 			if prefix := args.QueryOptions.Prefix; prefix != "" {
-				iter, err = state.JobsByIDPrefix(ws, args.RequestNamespace(), prefix)
+				iter, err = store.JobsByIDPrefix(ws, args.RequestNamespace(), prefix, sort)
 			} else {
-				iter, err = state.JobsByNamespace(ws, args.RequestNamespace())
+				iter, err = store.JobsByNamespace(ws, args.RequestNamespace(), sort)
 			}
 			if err != nil {
 				return err
 			}
 
 			var jobs []*structs.JobListStub
-			for {
-				raw := iter.Next()
-				if raw == nil {
-					break
-				}
-				job := raw.(*structs.Job)
-				summary, err := state.JobSummaryByID(ws, args.RequestNamespace(), job.ID)
-				if err != nil {
-					return fmt.Errorf("unable to look up summary for job: %v", job.ID)
-				}
-				jobs = append(jobs, job.Stub(summary))
+			paginator := state.NewPaginator(iter, state.IDTokenizer{}, args.QueryOptions,
+				func(raw interface{}) (bool, error) {
+					job := raw.(*structs.Job)
+					summary, err := store.JobSummaryByID(ws, args.RequestNamespace(), job.ID)
+					if err != nil {
+						return false, fmt.Errorf("unable to look up summary for job: %v", job.ID)
+					}
+
+					stub := job.Stub(summary)
+					if match, err := filter.Match(stub); err != nil || !match {
+						return false, err
+					}
+					jobs = append(jobs, stub)
+					return true, nil
+				})
+
+			nextToken, err := paginator.Page()
+			if err != nil {
+				return err
 			}
+			reply.QueryMeta.NextToken = nextToken
 			reply.Jobs = jobs
 
 			// Use the last index that affected the jobs table or summary
-			jindex, err := state.Index("jobs")
+			jindex, err := store.Index("jobs")
 			if err != nil {
 				return err
 			}
-			sindex, err := state.Index("job_summary")
+			sindex, err := store.Index("job_summary")
 			if err != nil {
 				return err
 			}
@@ -1385,13 +1422,19 @@ func (j *Job) listAllNamespaces(args *structs.JobListRequest, reply *structs.Job
 		return aclObj.AllowNsOp(ns, acl.NamespaceCapabilityListJobs)
 	}
 
+	filter, err := newListFilter(&args.QueryOptions, (*structs.JobListStub)(nil))
+	if err != nil {
+		return err
+	}
+	sort := state.SortOption(args.Reverse)
+
 	// Setup the blocking query
 	opts := blockingOptions{
 		queryOpts: &args.QueryOptions,
 		queryMeta: &reply.QueryMeta,
-		run: func(ws memdb.WatchSet, state *state.StateStore) error {
+		run: func(ws memdb.WatchSet, store *state.StateStore) error {
 			// check if user has permission to all namespaces
-			allowedNSes, err := allowedNSes(aclObj, state, allow)
+			allowedNSes, err := allowedNSes(aclObj, store, allow)
 			if err == structs.ErrPermissionDenied {
 				// return empty jobs if token isn't authorized for any
 				// namespace, matching other endpoints
@@ -1402,42 +1445,50 @@ func (j *Job) listAllNamespaces(args *structs.JobListRequest, reply *structs.Job
 			}
 
 			// Capture all the jobs
-			iter, err := state.Jobs(ws)
+			iter, err := store.Jobs(ws, sort)
 
 			if err != nil {
 				return err
 			}
 
+			// Jobs are ordered by namespace and then ID across namespaces
 			var jobs []*structs.JobListStub
-			for {
-				raw := iter.Next()
-				if raw == nil {
-					break
-				}
-				job := raw.(*structs.Job)
-				if allowedNSes != nil && !allowedNSes[job.Namespace] {
-					// not permitted to this name namespace
-					continue
-				}
-				if prefix != "" && !strings.HasPrefix(job.ID, prefix) {
-					continue
-				}
-				summary, err := state.JobSummaryByID(ws, job.Namespace, job.ID)
-				if err != nil {
-					return fmt.Errorf("unable to look up summary for job: %v", job.ID)
-				}
+			paginator := state.NewPaginator(iter, state.NamespaceIDTokenizer{}, args.QueryOptions,
+				func(raw interface{}) (bool, error) {
+					job := raw.(*structs.Job)
+					if allowedNSes != nil && !allowedNSes[job.Namespace] {
+						// not permitted to this name namespace
+						return false, nil
+					}
+					if prefix != "" && !strings.HasPrefix(job.ID, prefix) {
+						return false, nil
+					}
+					summary, err := store.JobSummaryByID(ws, job.Namespace, job.ID)
+					if err != nil {
+						return false, fmt.Errorf("unable to look up summary for job: %v", job.ID)
+					}
 
-				stub := job.Stub(summary)
-				jobs = append(jobs, stub)
+					stub := job.Stub(summary)
+					if match, err := filter.Match(stub); err != nil || !match {
+						return false, err
+					}
+					jobs = append(jobs, stub)
+					return true, nil
+				})
+
+			nextToken, err := paginator.Page()
+			if err != nil {
+				return err
 			}
+			reply.QueryMeta.NextToken = nextToken
 			reply.Jobs = jobs
 
 			// Use the last index that affected the jobs table or summary
-			jindex, err := state.Index("jobs")
+			jindex, err := store.Index("jobs")
 			if err != nil {
 				return err
 			}
-			sindex, err := state.Index("job_summary")
+			sindex, err := store.Index("job_summary")
 			if err != nil {
 				return err
 			}
@@ -1744,6 +1795,7 @@ func (j *Job) Plan(args *structs.JobPlanRequest, reply *structs.JobPlanResponse)
 		JobModifyIndex: updatedIndex,
 		Status:         structs.EvalStatusPending,
 		AnnotatePlan:   true,
+		ExplainPlan:    args.Explain,
 		// Timestamps are added for consistency but this eval is never persisted
 		CreateTime: now,
 		ModifyTime: now,
@@ -1800,6 +1852,9 @@ func (j *Job) Plan(args *structs.JobPlanRequest, reply *structs.JobPlanResponse)
 	}
 
 	reply.FailedTGAllocs = updatedEval.FailedTGAllocs
+	if args.Explain {
+		reply.PlacedTGAllocs = placedTGAllocMetrics(planner.Plans[0])
+	}
 	reply.JobModifyIndex = index
 	reply.Annotations = annotations
 	reply.CreatedEvals = planner.CreateEvals
@@ -1807,6 +1862,31 @@ func (j *Job) Plan(args *structs.JobPlanRequest, reply *structs.JobPlanResponse)
 	return nil
 }
 
+// placedTGAllocMetrics returns the metrics of the placement with the lowest
+// index of each task group in the plan.
+func placedTGAllocMetrics(plan *structs.Plan) map[string]*structs.AllocMetric {
+	placed := make(map[string]*structs.Allocation)
+	for _, allocs := range plan.NodeAllocation {
+		for _, alloc := range allocs {
+			if alloc.Metrics == nil {
+				continue
+			}
+			if existing, ok := placed[alloc.TaskGroup]; !ok || alloc.Index() < existing.Index() {
+				placed[alloc.TaskGroup] = alloc
+			}
+		}
+	}
+
+	if len(placed) == 0 {
+		return nil
+	}
+	metrics := make(map[string]*structs.AllocMetric, len(placed))
+	for tg, alloc := range placed {
+		metrics[tg] = alloc.Metrics
+	}
+	return metrics
+}
+
 // validateJobUpdate ensures updates to a job are valid.
 func validateJobUpdate(old, new *structs.Job) error {
 	// Validate Dispatch not set on new Jobs
@@ -1845,6 +1925,42 @@ func validateJobUpdate(old, new *structs.Job) error {
 	return nil
 }
 
+// validateJobDependencies returns an error if the dependencies of the job,
+// followed through the dependencies of the upstream jobs in state, lead back
+// to the job.
+func validateJobDependencies(snap *state.StateSnapshot, job *structs.Job) error {
+	visited := make(map[string]struct{})
+
+	var walk func(deps []*structs.JobDependency, path []string) error
+	walk = func(deps []*structs.JobDependency, path []string) error {
+		for _, dep := range deps {
+			cur := append(path[:len(path):len(path)], dep.JobID)
+			if dep.JobID == job.ID {
+				return structs.NewErrRPCCodedf(400,
+					"job dependencies form a cycle: %s", strings.Join(cur, " -> "))
+			}
+			if _, ok := visited[dep.JobID]; ok {
+				continue
+			}
+			visited[dep.JobID] = struct{}{}
+
+			upstream, err := snap.JobByID(nil, job.Namespace, dep.JobID)
+			if err != nil {
+				return err
+			}
+			if upstream == nil {
+				continue
+			}
+			if err := walk(upstream.DependsOn, cur); err != nil {
+				return err
+			}
+		}
+		return nil
+	}
+
+	return walk(job.DependsOn, []string{job.ID})
+}
+
 // Dispatch a parameterized job.
 func (j *Job) Dispatch(args *structs.JobDispatchRequest, reply *structs.JobDispatchResponse) error {
 	if done, err := j.srv.forward("Job.Dispatch", args, args, reply); done {
@@ -1893,7 +2009,7 @@ func (j *Job) Dispatch(args *structs.JobDispatchRequest, reply *structs.JobDispa
 	// Avoid creating new dispatched jobs for retry requests, by using the idempotency token
 	if args.IdempotencyToken != "" {
 		// Fetch all jobs that match the parameterized job ID prefix
-		iter, err := snap.JobsByIDPrefix(ws, parameterizedJob.Namespace, parameterizedJob.ID)
+		iter, err := snap.JobsByIDPrefix(ws, parameterizedJob.Namespace, parameterizedJob.ID, state.SortDefault)
 		if err != nil {
 			errMsg := "failed to retrieve jobs for idempotency check"
 			j.logger.Error(errMsg, "error", err)
@@ -1946,6 +2062,14 @@ func (j *Job) Dispatch(args *structs.JobDispatchRequest, reply *structs.JobDispa
 		dispatchJob.Meta[k] = v
 	}
 
+	// Override the max run duration of the task groups
+	if args.MaxRunDuration > 0 {
+		for _, tg := range dispatchJob.TaskGroups {
+			maxRun := args.MaxRunDuration
+			tg.MaxRunDuration = &maxRun
+		}
+	}
+
 	// Compress the payload
 	dispatchJob.Payload = snappy.Encode(nil, args.Payload)
 
@@ -2022,6 +2146,10 @@ func validateDispatchRequest(req *structs.JobDispatchRequest, job *structs.Job)
 		return fmt.Errorf("Payload exceeds maximum size; %d > %d", l, DispatchPayloadSizeLimit)
 	}
 
+	if req.MaxRunDuration < 0 {
+		return fmt.Errorf("Max run duration must be a positive value")
+	}
+
 	// Check if the metadata is a set
 	keys := make(map[string]struct{}, len(req.Meta))
 	for k := range req.Meta {
@@ -906,6 +906,60 @@ func TestJobEndpoint_Register_InvalidNamespace(t *testing.T) {
 	}
 }
 
+func TestJobEndpoint_Register_NodePool(t *testing.T) {
+	t.Parallel()
+
+	s1, root, cleanupS1 := TestACLServer(t, func(c *Config) {
+		c.NumSchedulers = 0 // Prevent automatic dequeue
+	})
+	defer cleanupS1()
+	codec := rpcClient(t, s1)
+	testutil.WaitForLeader(t, s1.RPC)
+
+	pool := mock.NodePool()
+	require.NoError(t, s1.fsm.State().UpsertNodePools(structs.MsgTypeTestSetup, 1000, []*structs.NodePool{pool}))
+
+	submitJobPolicy := mock.NamespacePolicy(structs.DefaultNamespace, "", []string{acl.NamespaceCapabilitySubmitJob})
+	submitJobToken := mock.CreatePolicyAndToken(t, s1.State(), 1001, "test-submit-job", submitJobPolicy)
+	poolToken := mock.CreatePolicyAndToken(t, s1.State(), 1002, "test-submit-job-pool",
+		submitJobPolicy+mock.NodePoolPolicy(pool.Name, "read", nil))
+
+	register := func(job *structs.Job, token string) error {
+		req := &structs.JobRegisterRequest{
+			Job: job,
+			WriteRequest: structs.WriteRequest{
+				Region:    "global",
+				Namespace: job.Namespace,
+				AuthToken: token,
+			},
+		}
+		var resp structs.JobRegisterResponse
+		return msgpackrpc.CallWithCodec(codec, "Job.Register", req, &resp)
+	}
+
+	// Jobs in the default node pool don't need node pool permissions.
+	require.NoError(t, register(mock.Job(), submitJobToken.SecretID))
+
+	// Jobs in other node pools require read permission on the pool.
+	job := mock.Job()
+	job.NodePool = pool.Name
+	err := register(job, submitJobToken.SecretID)
+	require.Error(t, err)
+	require.Contains(t, err.Error(), structs.ErrPermissionDenied.Error())
+	require.NoError(t, register(job, poolToken.SecretID))
+
+	out, err := s1.fsm.State().JobByID(nil, job.Namespace, job.ID)
+	require.NoError(t, err)
+	require.Equal(t, pool.Name, out.NodePool)
+
+	// Jobs cannot target node pools that don't exist.
+	job = mock.Job()
+	job.NodePool = "does-not-exist"
+	err = register(job, root.SecretID)
+	require.Error(t, err)
+	require.Contains(t, err.Error(), "nonexistent node pool")
+}
+
 func TestJobEndpoint_Register_Payload(t *testing.T) {
 	t.Parallel()
 
@@ -1194,6 +1248,65 @@ func TestJobEndpoint_Register_Dispatched(t *testing.T) {
 	require.Contains(err.Error(), "job can't be submitted with 'Dispatched'")
 }
 
+func TestJobEndpoint_Register_DependencyCycle(t *testing.T) {
+	t.Parallel()
+	require := require.New(t)
+
+	s1, cleanupS1 := TestServer(t, func(c *Config) {
+		c.NumSchedulers = 0 // Prevent automatic dequeue
+	})
+	defer cleanupS1()
+	codec := rpcClient(t, s1)
+	testutil.WaitForLeader(t, s1.RPC)
+
+	register := func(job *structs.Job) error {
+		req := &structs.JobRegisterRequest{
+			Job: job,
+			WriteRequest: structs.WriteRequest{
+				Region:    "global",
+				Namespace: job.Namespace,
+			},
+		}
+		var resp structs.JobRegisterResponse
+		return msgpackrpc.CallWithCodec(codec, "Job.Register", req, &resp)
+	}
+
+	// Register a chain of jobs a -> b -> c
+	a := mock.BatchJob()
+	a.ID = "a"
+	a.DependsOn = []*structs.JobDependency{{JobID: "b", Condition: structs.JobDependencyConditionComplete}}
+	require.NoError(register(a))
+
+	b := mock.BatchJob()
+	b.ID = "b"
+	b.DependsOn = []*structs.JobDependency{{JobID: "c", Condition: structs.JobDependencyConditionComplete}}
+	require.NoError(register(b))
+
+	// Closing the cycle is rejected
+	c := mock.BatchJob()
+	c.ID = "c"
+	c.DependsOn = []*structs.JobDependency{{JobID: "a", Condition: structs.JobDependencyConditionComplete}}
+	err := register(c)
+	require.Error(err)
+	require.Contains(err.Error(), "job dependencies form a cycle: c -> a -> b -> c")
+
+	// Updating a job in the chain to depend on its own downstream job is
+	// rejected as well
+	b.DependsOn = []*structs.JobDependency{{JobID: "a", Condition: structs.JobDependencyConditionComplete}}
+	err = register(b)
+	require.Error(err)
+	require.Contains(err.Error(), "job dependencies form a cycle: b -> a -> b")
+
+	// Jobs without a cycle can still be registered
+	d := mock.BatchJob()
+	d.ID = "d"
+	d.DependsOn = []*structs.JobDependency{
+		{JobID: "a", Condition: structs.JobDependencyConditionComplete},
+		{JobID: "b", Condition: structs.JobDependencyConditionComplete},
+	}
+	require.NoError(register(d))
+}
+
 func TestJobEndpoint_Register_EnforceIndex(t *testing.T) {
 	t.Parallel()
 
@@ -4650,6 +4763,104 @@ func TestJobEndpoint_ListJobs(t *testing.T) {
 	require.Equal(t, job.Namespace, resp3.Jobs[0].Namespace)
 }
 
+func TestJobEndpoint_ListJobs_Filter(t *testing.T) {
+	t.Parallel()
+
+	s1, cleanupS1 := TestServer(t, nil)
+	defer cleanupS1()
+	codec := rpcClient(t, s1)
+	testutil.WaitForLeader(t, s1.RPC)
+
+	// Create a service and a batch job
+	service := mock.Job()
+	batch := mock.BatchJob()
+	state := s1.fsm.State()
+	require.NoError(t, state.UpsertJob(structs.MsgTypeTestSetup, 1000, service))
+	require.NoError(t, state.UpsertJob(structs.MsgTypeTestSetup, 1001, batch))
+
+	cases := []struct {
+		name        string
+		namespace   string
+		filter      string
+		expectedIDs []string
+		expectedErr string
+	}{
+		{
+			name:        "no filter",
+			expectedIDs: []string{service.ID, batch.ID},
+		},
+		{
+			name:        "match type",
+			filter:      `Type == "batch"`,
+			expectedIDs: []string{batch.ID},
+		},
+		{
+			name:        "match none",
+			filter:      `Name == "nonexistent"`,
+			expectedIDs: []string{},
+		},
+		{
+			name:        "invalid expression",
+			filter:      `Type ==`,
+			expectedErr: "failed to read filter expression",
+		},
+		{
+			name:        "match summary",
+			filter:      `JobSummary.Summary.web.Running == 0 and Type == "service"`,
+			expectedIDs: []string{service.ID},
+		},
+		{
+			name:        "unknown field",
+			filter:      `NotAField == "batch"`,
+			expectedErr: "failed to read filter expression",
+		},
+		{
+			name:        "unknown field without jobs",
+			namespace:   "empty",
+			filter:      `NotAField == "batch"`,
+			expectedErr: "failed to read filter expression",
+		},
+		{
+			name:        "invalid index",
+			filter:      `Datacenters.first == "dc1"`,
+			expectedErr: "failed to read filter expression",
+		},
+	}
+
+	for _, tc := range cases {
+		t.Run(tc.name, func(t *testing.T) {
+			namespace := tc.namespace
+			if namespace == "" {
+				namespace = structs.DefaultNamespace
+			}
+			get := &structs.JobListRequest{
+				QueryOptions: structs.QueryOptions{
+					Region:    "global",
+					Namespace: namespace,
+					Filter:    tc.filter,
+				},
+			}
+			var resp structs.JobListResponse
+			err := msgpackrpc.CallWithCodec(codec, "Job.List", get, &resp)
+			if tc.expectedErr != "" {
+				require.Error(t, err)
+				require.Contains(t, err.Error(), tc.expectedErr)
+				code, _, ok := structs.CodeFromRPCCodedErr(err)
+				require.True(t, ok)
+				require.Equal(t, 400, code)
+				return
+			}
+			require.NoError(t, err)
+
+			ids := []string{}
+			for _, job := range resp.Jobs {
+				ids = append(ids, job.ID)
+			}
+			require.ElementsMatch(t, tc.expectedIDs, ids)
+		})
+	}
+}
+
 // TestJobEndpoint_ListJobs_AllNamespaces_OSS asserts that server
 // returns all jobs across namespace.
 //
@@ -5642,6 +5853,70 @@ func TestJobEndpoint_Plan_NoDiff(t *testing.T) {
 
 // TestJobEndpoint_Plan_Scaling asserts that the plan endpoint handles
 // jobs with scaling stanza
+func TestJobEndpoint_Plan_Explain(t *testing.T) {
+	t.Parallel()
+	require := require.New(t)
+
+	s1, cleanupS1 := TestServer(t, func(c *Config) {
+		c.NumSchedulers = 0 // Prevent automatic dequeue
+	})
+	defer cleanupS1()
+	codec := rpcClient(t, s1)
+	testutil.WaitForLeader(t, s1.RPC)
+	state := s1.fsm.State()
+
+	// Create a node that fits the job and one failing its constraint
+	node := mock.Node()
+	require.NoError(state.UpsertNode(structs.MsgTypeTestSetup, 1000, node))
+
+	windows := mock.Node()
+	windows.NodeClass = "windows"
+	windows.Attributes["kernel.name"] = "windows"
+	windows.ComputeClass()
+	require.NoError(state.UpsertNode(structs.MsgTypeTestSetup, 1001, windows))
+
+	job := mock.Job()
+	job.TaskGroups[0].Count = 1
+
+	// Create an explained plan request
+	planReq := &structs.JobPlanRequest{
+		Job:     job,
+		Explain: true,
+		WriteRequest: structs.WriteRequest{
+			Region:    "global",
+			Namespace: job.Namespace,
+		},
+	}
+
+	var planResp structs.JobPlanResponse
+	require.NoError(msgpackrpc.CallWithCodec(codec, "Job.Plan", planReq, &planResp))
+	require.Empty(planResp.FailedTGAllocs)
+
+	metrics := planResp.PlacedTGAllocs["web"]
+	require.NotNil(metrics)
+	require.Len(metrics.NodeExplanations, 2)
+
+	explained := make(map[string]*structs.NodeExplanation)
+	for _, e := range metrics.NodeExplanations {
+		explained[e.NodeID] = e
+	}
+	require.Empty(explained[node.ID].Filtered)
+	require.NotZero(explained[node.ID].NormScore)
+	require.Contains(explained[node.ID].Scores, "binpack")
+	require.Equal("${attr.kernel.name} = linux", explained[windows.ID].Filtered)
+
+	require.Len(metrics.ClassExplanations, 2)
+	require.Equal(1, metrics.ClassExplanations[node.NodeClass].Scored)
+	require.Equal(1, metrics.ClassExplanations["windows"].Filtered)
+	require.Equal(1, metrics.ClassExplanations["windows"].Reasons["${attr.kernel.name} = linux"])
+
+	// Plans are not explained unless requested
+	planReq.Explain = false
+	planResp = structs.JobPlanResponse{}
+	require.NoError(msgpackrpc.CallWithCodec(codec, "Job.Plan", planReq, &planResp))
+	require.Nil(planResp.PlacedTGAllocs)
+}
+
 func TestJobEndpoint_Plan_Scaling(t *testing.T) {
 	t.Parallel()
 
@@ -6411,6 +6686,69 @@ func TestJobEndpoint_Dispatch(t *testing.T) {
 	}
 }
 
+// TestJobEndpoint_Dispatch_MaxRunDuration asserts that a dispatch request can
+// override the max run duration of the dispatched job.
+func TestJobEndpoint_Dispatch_MaxRunDuration(t *testing.T) {
+	t.Parallel()
+
+	s1, cleanupS1 := TestServer(t, func(c *Config) {
+		c.NumSchedulers = 0 // Prevent automatic dequeue
+	})
+	defer cleanupS1()
+	codec := rpcClient(t, s1)
+	testutil.WaitForLeader(t, s1.RPC)
+
+	job := mock.BatchJob()
+	job.ParameterizedJob = &structs.ParameterizedJobConfig{}
+	maxRun := time.Hour
+	job.TaskGroups[0].MaxRunDuration = &maxRun
+
+	regReq := &structs.JobRegisterRequest{
+		Job: job,
+		WriteRequest: structs.WriteRequest{
+			Region:    "global",
+			Namespace: job.Namespace,
+		},
+	}
+	var regResp structs.JobRegisterResponse
+	require.NoError(t, msgpackrpc.CallWithCodec(codec, "Job.Register", regReq, &regResp))
+
+	dispatch := func(maxRun time.Duration) (*structs.Job, error) {
+		req := &structs.JobDispatchRequest{
+			JobID:          job.ID,
+			MaxRunDuration: maxRun,
+			WriteRequest: structs.WriteRequest{
+				Region:    "global",
+				Namespace: job.Namespace,
+			},
+		}
+		var resp structs.JobDispatchResponse
+		if err := msgpackrpc.CallWithCodec(codec, "Job.Dispatch", req, &resp); err != nil {
+			return nil, err
+		}
+		return s1.fsm.State().JobByID(nil, job.Namespace, resp.DispatchedJobID)
+	}
+
+	// Without an override the parameterized job's duration is kept
+	out, err := dispatch(0)
+	require.NoError(t, err)
+	require.Equal(t, time.Hour, *out.TaskGroups[0].MaxRunDuration)
+
+	// The override applies to every task group of the dispatched job
+	out, err = dispatch(10 * time.Minute)
+	require.NoError(t, err)
+	require.Equal(t, 10*time.Minute, *out.TaskGroups[0].MaxRunDuration)
+
+	// The parameterized job is left untouched
+	parent, err := s1.fsm.State().JobByID(nil, job.Namespace, job.ID)
+	require.NoError(t, err)
+	require.Equal(t, time.Hour, *parent.TaskGroups[0].MaxRunDuration)
+
+	_, err = dispatch(-time.Minute)
+	require.Error(t, err)
+	require.Contains(t, err.Error(), "Max run duration must be a positive value")
+}
+
 // TestJobEndpoint_Dispatch_JobChildrenSummary asserts that the job summary is updated
 // appropriately as its dispatched/children jobs status are updated.
 func TestJobEndpoint_Dispatch_JobChildrenSummary(t *testing.T) {
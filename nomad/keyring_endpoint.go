@@ -0,0 +1,303 @@
+package nomad
+
+import (
+	"fmt"
+	"time"
+
+	metrics "github.com/armon/go-metrics"
+	log "github.com/hashicorp/go-hclog"
+	memdb "github.com/hashicorp/go-memdb"
+
+	"github.com/hashicorp/nomad/nomad/state"
+	"github.com/hashicorp/nomad/nomad/structs"
+)
+
+// Keyring endpoint serves RPCs for the root keys used to encrypt sensitive
+// data.
+type Keyring struct {
+	srv       *Server
+	ctx       *RPCContext
+	logger    log.Logger
+	encrypter *Encrypter
+}
+
+// Rotate generates a new root key and makes it the active key used for
+// encryption. If Full is set, all existing variables and ACL tokens are
+// re-encrypted with the new key.
+func (k *Keyring) Rotate(args *structs.KeyringRotateRootKeyRequest, reply *structs.KeyringRotateRootKeyResponse) error {
+	if done, err := k.srv.forward("Keyring.Rotate", args, args, reply); done {
+		return err
+	}
+	defer metrics.MeasureSince([]string{"nomad", "keyring", "rotate"}, time.Now())
+
+	// Check management permissions
+	if aclObj, err := k.srv.ResolveToken(args.AuthToken); err != nil {
+		return err
+	} else if aclObj != nil && !aclObj.IsManagement() {
+		return structs.ErrPermissionDenied
+	}
+
+	if args.Algorithm == "" {
+		args.Algorithm = structs.EncryptionAlgorithmAES256GCM
+	}
+
+	rootKey, err := structs.NewRootKey(args.Algorithm)
+	if err != nil {
+		return err
+	}
+	rootKey.Meta.SetActive()
+
+	// Add the key to the leader's keyring before writing the metadata, so
+	// that the key is available to followers as soon as they learn of it.
+	if err := k.encrypter.AddKey(rootKey); err != nil {
+		return err
+	}
+
+	req := structs.KeyringUpdateRootKeyMetaRequest{
+		RootKeyMeta:  rootKey.Meta,
+		WriteRequest: args.WriteRequest,
+	}
+	out, index, err := k.srv.raftApply(structs.RootKeyMetaUpsertRequestType, req)
+	if err != nil {
+		return err
+	}
+	if err, ok := out.(error); ok && err != nil {
+		return err
+	}
+
+	if args.Full {
+		if err := k.rekeyVariables(rootKey.Meta.KeyID); err != nil {
+			return fmt.Errorf("root key rotated but failed to re-encrypt variables: %v", err)
+		}
+		if err := k.encrypter.rekeyACLTokens(rootKey.Meta.KeyID); err != nil {
+			return fmt.Errorf("root key rotated but failed to re-encrypt ACL tokens: %v", err)
+		}
+	}
+
+	reply.Key = rootKey.Meta
+	reply.Index = index
+	return nil
+}
+
+// rekeyVariables re-encrypts every variable not encrypted with the given key.
+// Variables which are modified concurrently are skipped, since their new
+// value has already been encrypted with the active key.
+func (k *Keyring) rekeyVariables(keyID string) error {
+	iter, err := k.srv.fsm.State().Variables(nil)
+	if err != nil {
+		return err
+	}
+
+	for raw := iter.Next(); raw != nil; raw = iter.Next() {
+		v := raw.(*structs.VariableEncrypted)
+		if v.KeyID == keyID {
+			continue
+		}
+
+		cleartext, err := k.encrypter.Decrypt(v.Data, v.KeyID)
+		if err != nil {
+			return fmt.Errorf("failed to decrypt variable %q: %v", v.Path, err)
+		}
+		rekeyed := v.Copy()
+		rekeyed.Data, rekeyed.KeyID, err = k.encrypter.Encrypt(cleartext)
+		if err != nil {
+			return fmt.Errorf("failed to encrypt variable %q: %v", v.Path, err)
+		}
+
+		req := &structs.VarApplyStateRequest{
+			Op:  structs.VarOpCAS,
+			Var: &rekeyed,
+			WriteRequest: structs.WriteRequest{
+				Region:    k.srv.config.Region,
+				Namespace: v.Namespace,
+			},
+		}
+		out, _, err := k.srv.raftApply(structs.VarApplyStateRequestType, req)
+		if err != nil {
+			return err
+		}
+		if resp, ok := out.(*structs.VarApplyStateResponse); ok && resp.IsError() {
+			return resp.Error
+		}
+	}
+	return nil
+}
+
+// List returns the metadata of all root keys in the keyring.
+func (k *Keyring) List(args *structs.KeyringListRootKeyMetaRequest, reply *structs.KeyringListRootKeyMetaResponse) error {
+	if done, err := k.srv.forward("Keyring.List", args, args, reply); done {
+		return err
+	}
+	defer metrics.MeasureSince([]string{"nomad", "keyring", "list"}, time.Now())
+
+	// Check management permissions
+	if aclObj, err := k.srv.ResolveToken(args.AuthToken); err != nil {
+		return err
+	} else if aclObj != nil && !aclObj.IsManagement() {
+		return structs.ErrPermissionDenied
+	}
+
+	opts := blockingOptions{
+		queryOpts: &args.QueryOptions,
+		queryMeta: &reply.QueryMeta,
+		run: func(ws memdb.WatchSet, s *state.StateStore) error {
+			iter, err := s.RootKeyMetas(ws)
+			if err != nil {
+				return err
+			}
+
+			keys := []*structs.RootKeyMeta{}
+			for raw := iter.Next(); raw != nil; raw = iter.Next() {
+				keys = append(keys, raw.(*structs.RootKeyMeta))
+			}
+			reply.Keys = keys
+
+			index, err := s.Index(state.TableRootKeyMeta)
+			if err != nil {
+				return err
+			}
+			reply.Index = index
+			k.srv.setQueryMeta(&reply.QueryMeta)
+			return nil
+		},
+	}
+	return k.srv.blockingRPC(&opts)
+}
+
+// Delete removes an inactive root key from the keyring. The key cannot be
+// deleted while any variable or ACL token is still encrypted with it.
+func (k *Keyring) Delete(args *structs.KeyringDeleteRootKeyRequest, reply *structs.KeyringDeleteRootKeyResponse) error {
+	if done, err := k.srv.forward("Keyring.Delete", args, args, reply); done {
+		return err
+	}
+	defer metrics.MeasureSince([]string{"nomad", "keyring", "delete"}, time.Now())
+
+	// Check management permissions
+	if aclObj, err := k.srv.ResolveToken(args.AuthToken); err != nil {
+		return err
+	} else if aclObj != nil && !aclObj.IsManagement() {
+		return structs.ErrPermissionDenied
+	}
+
+	if args.KeyID == "" {
+		return structs.NewErrRPCCoded(400, "root key ID is required")
+	}
+
+	// Check the key here to return a useful error code; the state store
+	// re-verifies this.
+	keyMeta, err := k.srv.fsm.State().RootKeyMetaByID(nil, args.KeyID)
+	if err != nil {
+		return err
+	}
+	if keyMeta == nil {
+		return structs.NewErrRPCCodedf(404, "root key %q not found", args.KeyID)
+	}
+	if keyMeta.Active() {
+		return structs.NewErrRPCCoded(400, "active root key cannot be deleted; rotate the keyring first")
+	}
+	if err := k.srv.fsm.State().RootKeyInUse(args.KeyID); err != nil {
+		return structs.NewErrRPCCodedf(400, "%v; rotate the keyring with full re-encryption first", err)
+	}
+
+	out, index, err := k.srv.raftApply(structs.RootKeyMetaDeleteRequestType, args)
+	if err != nil {
+		return err
+	}
+	if err, ok := out.(error); ok && err != nil {
+		return err
+	}
+
+	reply.Index = index
+	return nil
+}
+
+// ListPublic returns the public keys used to verify workload identities. It
+// does not require authentication, since third parties use these keys to
+// verify the identities presented to them.
+func (k *Keyring) ListPublic(args *structs.GenericRequest, reply *structs.KeyringListPublicResponse) error {
+	if done, err := k.srv.forward("Keyring.ListPublic", args, args, reply); done {
+		return err
+	}
+	defer metrics.MeasureSince([]string{"nomad", "keyring", "list_public"}, time.Now())
+
+	opts := blockingOptions{
+		queryOpts: &args.QueryOptions,
+		queryMeta: &reply.QueryMeta,
+		run: func(ws memdb.WatchSet, s *state.StateStore) error {
+			iter, err := s.RootKeyMetas(ws)
+			if err != nil {
+				return err
+			}
+
+			pubKeys := []*structs.KeyringPublicKey{}
+			for raw := iter.Next(); raw != nil; raw = iter.Next() {
+				keyMeta := raw.(*structs.RootKeyMeta)
+				pubKey, err := k.encrypter.GetPublicKey(keyMeta.KeyID)
+				if err != nil {
+					// The key has not been replicated to this server
+					// yet; it will be listed once it has been.
+					continue
+				}
+				pubKeys = append(pubKeys, &structs.KeyringPublicKey{
+					KeyID:      keyMeta.KeyID,
+					PublicKey:  pubKey,
+					Algorithm:  structs.PubKeyAlgEdDSA,
+					Use:        structs.PubKeyUseSig,
+					CreateTime: keyMeta.CreateTime,
+				})
+			}
+			reply.PublicKeys = pubKeys
+
+			index, err := s.Index(state.TableRootKeyMeta)
+			if err != nil {
+				return err
+			}
+			reply.Index = index
+			k.srv.setQueryMeta(&reply.QueryMeta)
+			return nil
+		},
+	}
+	return k.srv.blockingRPC(&opts)
+}
+
+// Get is used by other servers to fetch the key material of a root key from
+// this server's keyring. It is only available to servers, and is never
+// forwarded, since each server answers from its own keystore. As with Raft
+// connections, the caller's server certificate is only checked when mTLS is
+// enforced.
+func (k *Keyring) Get(args *structs.KeyringGetRootKeyRequest, reply *structs.KeyringGetRootKeyResponse) error {
+
+	// ensure that only another server can make this request
+	if err := k.srv.validateRaftTLS(k.ctx); err != nil {
+		return err
+	}
+	defer metrics.MeasureSince([]string{"nomad", "keyring", "get"}, time.Now())
+
+	if args.KeyID == "" {
+		return fmt.Errorf("root key ID is required")
+	}
+
+	keyMeta, err := k.srv.fsm.State().RootKeyMetaByID(nil, args.KeyID)
+	if err != nil {
+		return err
+	}
+	if keyMeta == nil {
+		return nil
+	}
+
+	// retrieve the key material from the keyring
+	rootKey, err := k.encrypter.GetKey(keyMeta.KeyID)
+	if err != nil {
+		return err
+	}
+	rootKey.Meta = keyMeta.Copy()
+	reply.Key = rootKey
+
+	index, err := k.srv.fsm.State().Index(state.TableRootKeyMeta)
+	if err != nil {
+		return err
+	}
+	reply.Index = index
+	k.srv.setQueryMeta(&reply.QueryMeta)
+	return nil
+}
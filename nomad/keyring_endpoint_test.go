@@ -0,0 +1,118 @@
+package nomad
+
+import (
+	"testing"
+
+	msgpackrpc "github.com/hashicorp/net-rpc-msgpackrpc"
+	"github.com/hashicorp/nomad/nomad/mock"
+	"github.com/hashicorp/nomad/nomad/structs"
+	"github.com/hashicorp/nomad/testutil"
+	"github.com/stretchr/testify/require"
+)
+
+func TestKeyringEndpoint_RotateListDelete(t *testing.T) {
+	t.Parallel()
+	s1, rootToken, cleanupS1 := TestACLServer(t, nil)
+	defer cleanupS1()
+	codec := rpcClient(t, s1)
+	testutil.WaitForLeader(t, s1.RPC)
+	waitForKeyring(t, s1)
+
+	initialKey, err := s1.fsm.State().GetActiveRootKeyMeta(nil)
+	require.NoError(t, err)
+
+	// Write a variable encrypted with the initial key.
+	varReq := &structs.VariablesApplyRequest{
+		Op: structs.VarOpSet,
+		Var: &structs.VariableDecrypted{
+			VariableMetadata: structs.VariableMetadata{Path: "foo"},
+			Items:            structs.VariableItems{"bar": "baz"},
+		},
+		WriteRequest: structs.WriteRequest{
+			Region:    DefaultRegion,
+			Namespace: structs.DefaultNamespace,
+			AuthToken: rootToken.SecretID,
+		},
+	}
+	var varResp structs.VariablesApplyResponse
+	require.NoError(t, msgpackrpc.CallWithCodec(codec, "Variables.Apply", varReq, &varResp))
+
+	// Rotating requires a management token.
+	token := mock.CreatePolicyAndToken(t, s1.fsm.State(), 1000, "test-valid",
+		mock.NamespacePolicy(structs.DefaultNamespace, "write", nil))
+
+	rotateReq := &structs.KeyringRotateRootKeyRequest{
+		WriteRequest: structs.WriteRequest{
+			Region:    DefaultRegion,
+			AuthToken: token.SecretID,
+		},
+	}
+	var rotateResp structs.KeyringRotateRootKeyResponse
+	err = msgpackrpc.CallWithCodec(codec, "Keyring.Rotate", rotateReq, &rotateResp)
+	require.EqualError(t, err, structs.ErrPermissionDenied.Error())
+
+	rotateReq.AuthToken = rootToken.SecretID
+	require.NoError(t, msgpackrpc.CallWithCodec(codec, "Keyring.Rotate", rotateReq, &rotateResp))
+	require.NotNil(t, rotateResp.Key)
+	require.True(t, rotateResp.Key.Active())
+	newKeyID := rotateResp.Key.KeyID
+
+	listReq := &structs.KeyringListRootKeyMetaRequest{
+		QueryOptions: structs.QueryOptions{
+			Region:    DefaultRegion,
+			AuthToken: rootToken.SecretID,
+		},
+	}
+	var listResp structs.KeyringListRootKeyMetaResponse
+	require.NoError(t, msgpackrpc.CallWithCodec(codec, "Keyring.List", listReq, &listResp))
+	require.Len(t, listResp.Keys, 2)
+	for _, key := range listResp.Keys {
+		require.Equal(t, key.KeyID == newKeyID, key.Active())
+	}
+
+	// The active key cannot be deleted.
+	deleteReq := &structs.KeyringDeleteRootKeyRequest{
+		KeyID: newKeyID,
+		WriteRequest: structs.WriteRequest{
+			Region:    DefaultRegion,
+			AuthToken: rootToken.SecretID,
+		},
+	}
+	var deleteResp structs.KeyringDeleteRootKeyResponse
+	err = msgpackrpc.CallWithCodec(codec, "Keyring.Delete", deleteReq, &deleteResp)
+	require.Error(t, err)
+
+	// The initial key is still used by the variable.
+	deleteReq.KeyID = initialKey.KeyID
+	err = msgpackrpc.CallWithCodec(codec, "Keyring.Delete", deleteReq, &deleteResp)
+	require.Error(t, err)
+	require.Contains(t, err.Error(), "in use")
+
+	// A full rotation re-encrypts the variable, after which the older keys
+	// can be deleted.
+	rotateReq.Full = true
+	require.NoError(t, msgpackrpc.CallWithCodec(codec, "Keyring.Rotate", rotateReq, &rotateResp))
+
+	stored, err := s1.fsm.State().GetVariable(nil, structs.DefaultNamespace, "foo")
+	require.NoError(t, err)
+	require.Equal(t, rotateResp.Key.KeyID, stored.KeyID)
+
+	require.NoError(t, msgpackrpc.CallWithCodec(codec, "Keyring.Delete", deleteReq, &deleteResp))
+	require.False(t, s1.encrypter.hasKey(initialKey.KeyID))
+
+	require.NoError(t, msgpackrpc.CallWithCodec(codec, "Keyring.List", listReq, &listResp))
+	require.Len(t, listResp.Keys, 2)
+
+	// The variable can still be read.
+	readReq := &structs.VariablesReadRequest{
+		Path: "foo",
+		QueryOptions: structs.QueryOptions{
+			Region:    DefaultRegion,
+			Namespace: structs.DefaultNamespace,
+			AuthToken: rootToken.SecretID,
+		},
+	}
+	var readResp structs.VariablesReadResponse
+	require.NoError(t, msgpackrpc.CallWithCodec(codec, "Variables.Read", readReq, &readResp))
+	require.Equal(t, "baz", readResp.Data.Items["bar"])
+}
@@ -30,6 +30,16 @@ const (
 	// high contention when the schedulers plan does not make progress.
 	failedEvalUnblockInterval = 1 * time.Minute
 
+	// maxEvalsPerCancel is the maximum number of redundant evaluations
+	// canceled in a single Raft entry, to keep the entry reasonably sized.
+	maxEvalsPerCancel = 728
+
+	// cancelRetryBaseInterval and cancelRetryMaxInterval bound the backoff
+	// between attempts to cancel redundant evaluations after failing to
+	// cancel them.
+	cancelRetryBaseInterval = 1 * time.Second
+	cancelRetryMaxInterval  = 30 * time.Second
+
 	// replicationRateLimit is used to rate limit how often data is replicated
 	// between the authoritative region and the local region
 	replicationRateLimit rate.Limit = 10.0
@@ -50,6 +60,8 @@ var minJobRegisterAtomicEvalVersion = version.Must(version.NewVersion("0.12.1"))
 
 var minOneTimeAuthenticationTokenVersion = version.Must(version.NewVersion("1.1.0"))
 
+var minVariablesVersion = version.Must(version.NewVersion("1.2.0"))
+
 // monitorLeadership is used to monitor if we acquire or lose our role
 // as the leader in the Raft cluster. There is some work the leader is
 // expected to do, so we must react to changes
@@ -239,23 +251,24 @@ func (s *Server) establishLeadership(stopCh chan struct{}) error {
 	s.autopilot.Start()
 
 	// Initialize scheduler configuration
-	s.getOrCreateSchedulerConfig()
+	schedConfig := s.getOrCreateSchedulerConfig()
 
 	// Initialize the ClusterID
 	_, _ = s.ClusterID()
 	// todo: use cluster ID for stuff, later!
 
+	// Initialize the keyring used to encrypt variables
+	go s.initializeKeyring(stopCh)
+
 	// Enable the plan queue, since we are now the leader
 	s.planQueue.SetEnabled(true)
 
 	// Start the plan evaluator
 	go s.planApply()
 
-	// Enable the eval broker, since we are now the leader
-	s.evalBroker.SetEnabled(true)
-
-	// Enable the blocked eval tracker, since we are now the leader
-	s.blockedEvals.SetEnabled(true)
+	// Enable the eval broker and blocked eval tracker, since we are now the
+	// leader, unless the operator has paused them
+	s.handleEvalBrokerStateChange(schedConfig)
 	s.blockedEvals.SetTimetable(s.fsm.TimeTable())
 
 	// Enable the deployment watcher, since we are now the leader
@@ -267,6 +280,9 @@ func (s *Server) establishLeadership(stopCh chan struct{}) error {
 	// Enable the volume watcher, since we are now the leader
 	s.volumeWatcher.SetEnabled(true, s.State())
 
+	// Enable the waiting eval tracker, since we are now the leader
+	s.waitingEvals.SetEnabled(true, s.State)
+
 	// Restore the eval broker state
 	if err := s.restoreEvals(); err != nil {
 		return err
@@ -299,6 +315,9 @@ func (s *Server) establishLeadership(stopCh chan struct{}) error {
 	// Reap any duplicate blocked evaluations
 	go s.reapDupBlockedEvaluations(stopCh)
 
+	// Cancel any redundant evaluations coalesced by the eval broker
+	go s.reapCancelableEvaluations(stopCh, s.raftApply)
+
 	// Periodically unblock failed allocations
 	go s.periodicUnblockFailedEvals(stopCh)
 
@@ -495,7 +514,7 @@ func diffNamespaces(state *state.StateStore, minIndex uint64, remoteList []*stru
 func (s *Server) restoreEvals() error {
 	// Get an iterator over every evaluation
 	ws := memdb.NewWatchSet()
-	iter, err := s.fsm.State().Evals(ws)
+	iter, err := s.fsm.State().Evals(ws, state.SortDefault)
 	if err != nil {
 		return fmt.Errorf("failed to get evaluations: %v", err)
 	}
@@ -511,6 +530,8 @@ func (s *Server) restoreEvals() error {
 			s.evalBroker.Enqueue(eval)
 		} else if eval.ShouldBlock() {
 			s.blockedEvals.Block(eval)
+		} else if eval.Status == structs.EvalStatusWaiting {
+			s.waitingEvals.Track(eval)
 		}
 	}
 	return nil
@@ -861,6 +882,61 @@ func (s *Server) reapDupBlockedEvaluations(stopCh chan struct{}) {
 	}
 }
 
+// reapCancelableEvaluations is used to cancel the evaluations the eval broker
+// found to be redundant because a newer evaluation for the same job was
+// enqueued. Updating them via Raft also publishes them on the event stream.
+// Evaluations that fail to be canceled are added back to the eval broker and
+// retried with backoff, so they don't remain pending.
+func (s *Server) reapCancelableEvaluations(stopCh chan struct{}, apply raftApplyFn) {
+	var backoff time.Duration
+	for {
+		select {
+		case <-stopCh:
+			return
+		default:
+			// Scan for cancelable evals.
+			evals := s.evalBroker.Cancelable(maxEvalsPerCancel, time.Second)
+			if evals == nil {
+				continue
+			}
+
+			cancel := make([]*structs.Evaluation, len(evals))
+			for i, eval := range evals {
+				// Update the status to cancelled
+				newEval := eval.Copy()
+				newEval.Status = structs.EvalStatusCancelled
+				newEval.StatusDescription = fmt.Sprintf("canceled after more recent evaluation was enqueued for job %q", newEval.JobID)
+				newEval.UpdateModifyTime()
+				cancel[i] = newEval
+			}
+
+			// Update via Raft
+			req := structs.EvalUpdateRequest{
+				Evals: cancel,
+			}
+			if _, _, err := apply(structs.EvalUpdateRequestType, &req); err != nil {
+				s.evalBroker.RequeueCancelable(evals)
+
+				backoff *= 2
+				if backoff == 0 {
+					backoff = cancelRetryBaseInterval
+				} else if backoff > cancelRetryMaxInterval {
+					backoff = cancelRetryMaxInterval
+				}
+				s.logger.Error("failed to cancel redundant evals", "num_evals", len(cancel), "retry", backoff, "error", err)
+
+				select {
+				case <-stopCh:
+					return
+				case <-time.After(backoff):
+				}
+				continue
+			}
+			backoff = 0
+		}
+	}
+}
+
 // periodicUnblockFailedEvals periodically unblocks failed, blocked evaluations.
 func (s *Server) periodicUnblockFailedEvals(stopCh chan struct{}) {
 	ticker := time.NewTicker(failedEvalUnblockInterval)
@@ -986,13 +1062,13 @@ func (s *Server) publishJobStatusMetrics(stopCh chan struct{}) {
 			return
 		case <-timer.C:
 			timer.Reset(s.config.StatsCollectionInterval)
-			state, err := s.State().Snapshot()
+			snap, err := s.State().Snapshot()
 			if err != nil {
 				s.logger.Error("failed to get state", "error", err)
 				continue
 			}
 			ws := memdb.NewWatchSet()
-			iter, err := state.Jobs(ws)
+			iter, err := snap.Jobs(ws, state.SortDefault)
 			if err != nil {
 				s.logger.Error("failed to get job statuses", "error", err)
 				continue
@@ -1068,6 +1144,9 @@ func (s *Server) revokeLeadership() error {
 	// Disable the volume watcher
 	s.volumeWatcher.SetEnabled(false, nil)
 
+	// Disable the waiting eval tracker
+	s.waitingEvals.SetEnabled(false, nil)
+
 	// Disable any enterprise systems required.
 	if err := s.revokeEnterpriseLeadership(); err != nil {
 		return err
@@ -1493,7 +1572,12 @@ START:
 				args := &structs.ACLTokenUpsertRequest{
 					Tokens: fetched,
 				}
-				_, _, err := s.raftApply(structs.ACLTokenUpsertRequestType, args)
+				applyArgs, err := s.encrypter.encryptACLTokenUpsert(args)
+				if err != nil {
+					s.logger.Error("failed to encrypt tokens", "error", err)
+					goto ERR_WAIT
+				}
+				_, _, err = s.raftApply(structs.ACLTokenUpsertRequestType, applyArgs)
 				if err != nil {
 					s.logger.Error("failed to update tokens", "error", err)
 					goto ERR_WAIT
@@ -1518,13 +1602,13 @@ ERR_WAIT:
 // diffACLTokens is used to perform a two-way diff between the local
 // tokens and the remote tokens to determine which tokens need to
 // be deleted or updated.
-func diffACLTokens(state *state.StateStore, minIndex uint64, remoteList []*structs.ACLTokenListStub) (delete []string, update []string) {
+func diffACLTokens(store *state.StateStore, minIndex uint64, remoteList []*structs.ACLTokenListStub) (delete []string, update []string) {
 	// Construct a set of the local and remote policies
 	local := make(map[string][]byte)
 	remote := make(map[string]struct{})
 
 	// Add all the local global tokens
-	iter, err := state.ACLTokensByGlobal(nil, true)
+	iter, err := store.ACLTokensByGlobal(nil, true, state.SortDefault)
 	if err != nil {
 		panic("failed to iterate local tokens")
 	}
@@ -1610,7 +1694,117 @@ func (s *Server) getOrCreateSchedulerConfig() *structs.SchedulerConfiguration {
 		return nil
 	}
 
-	return config
+	return &req.Config
+}
+
+// handleEvalBrokerStateChange pauses or resumes the eval broker and blocked
+// eval tracker, and configures fair-share dequeueing, to match the given
+// scheduler configuration. It returns true if the broker was resumed, in
+// which case the caller must restore the pending and blocked evaluations from
+// state.
+func (s *Server) handleEvalBrokerStateChange(schedConfig *structs.SchedulerConfiguration) bool {
+	fairShare := schedConfig != nil && schedConfig.EvalBrokerFairShare
+	s.evalBroker.SetFairShare(fairShare, s.namespaceWeight)
+
+	paused := schedConfig != nil && schedConfig.PauseEvalBroker
+	enabled := s.evalBroker.Enabled()
+
+	switch {
+	case paused && enabled:
+		s.logger.Info("pausing eval broker")
+		s.evalBroker.SetEnabled(false)
+		s.blockedEvals.SetEnabled(false)
+		return false
+	case !paused && !enabled:
+		s.logger.Info("enabling eval broker")
+		s.evalBroker.SetEnabled(true)
+		s.blockedEvals.SetEnabled(true)
+		return true
+	default:
+		return false
+	}
+}
+
+// namespaceWeight returns the fair-share weight of a namespace used by the
+// eval broker.
+func (s *Server) namespaceWeight(namespace string) int {
+	ns, err := s.fsm.State().NamespaceByName(nil, namespace)
+	if err != nil || ns == nil {
+		return 1
+	}
+	return ns.EffectiveWeight()
+}
+
+// initializeKeyring creates the first root key if the leader doesn't have
+// one. The leader will reconcile the keyring with the state store in case the
+// keyring was created but the leader was not able to write the metadata to
+// Raft. Servers that are not yet running a version that supports the keyring
+// will block initialization until they are upgraded.
+func (s *Server) initializeKeyring(stopCh <-chan struct{}) {
+	logger := s.logger.Named("keyring")
+
+	keyMeta, err := s.fsm.State().GetActiveRootKeyMeta(nil)
+	if err != nil {
+		logger.Error("failed to get active key", "error", err)
+		return
+	}
+	if keyMeta != nil {
+		return
+	}
+
+	logger.Trace("verifying cluster is ready to initialize keyring")
+	for {
+		select {
+		case <-stopCh:
+			return
+		default:
+		}
+
+		if ServersMeetMinimumVersion(s.serf.Members(), minVariablesVersion, true) {
+			break
+		}
+
+		select {
+		case <-stopCh:
+			return
+		case <-time.After(time.Minute):
+		}
+	}
+
+	// we might have lost leadership during the version check
+	if !s.IsLeader() {
+		return
+	}
+
+	logger.Trace("initializing keyring")
+
+	rootKey, err := structs.NewRootKey(structs.EncryptionAlgorithmAES256GCM)
+	if err != nil {
+		logger.Error("could not initialize keyring", "error", err)
+		return
+	}
+	rootKey.Meta.SetActive()
+
+	if err := s.encrypter.AddKey(rootKey); err != nil {
+		logger.Error("could not add initial key to keyring", "error", err)
+		return
+	}
+
+	req := structs.KeyringUpdateRootKeyMetaRequest{
+		RootKeyMeta: rootKey.Meta,
+	}
+	if _, _, err = s.raftApply(structs.RootKeyMetaUpsertRequestType, req); err != nil {
+		logger.Error("could not initialize keyring", "error", err)
+		return
+	}
+
+	logger.Info("initialized keyring", "id", rootKey.Meta.KeyID)
+
+	// Encrypt the secrets of any ACL tokens written before the keyring was
+	// initialized
+	if err := s.encrypter.rekeyACLTokens(rootKey.Meta.KeyID); err != nil {
+		logger.Error("could not encrypt ACL tokens", "error", err)
+	}
 }
 
 func (s *Server) generateClusterID() (string, error) {
@@ -5,6 +5,7 @@ import (
 	"fmt"
 	"sort"
 	"strconv"
+	"sync/atomic"
 	"testing"
 	"time"
 
@@ -13,6 +14,7 @@ import (
 	memdb "github.com/hashicorp/go-memdb"
 	"github.com/hashicorp/go-version"
 	"github.com/hashicorp/nomad/helper"
+	"github.com/hashicorp/nomad/helper/testlog"
 	"github.com/hashicorp/nomad/nomad/mock"
 	"github.com/hashicorp/nomad/nomad/state"
 	"github.com/hashicorp/nomad/nomad/structs"
@@ -606,6 +608,99 @@ func TestLeader_ReapDuplicateEval(t *testing.T) {
 	})
 }
 
+func TestLeader_ReapCancelableEvals(t *testing.T) {
+	s1, cleanupS1 := TestServer(t, func(c *Config) {
+		c.NumSchedulers = 0
+	})
+	defer cleanupS1()
+	testutil.WaitForLeader(t, s1.RPC)
+
+	// Create several pending evals for the same job
+	eval := mock.Eval()
+	eval2 := mock.Eval()
+	eval2.JobID = eval.JobID
+	eval3 := mock.Eval()
+	eval3.JobID = eval.JobID
+
+	state := s1.fsm.State()
+	require.NoError(t, state.UpsertEvals(structs.MsgTypeTestSetup, 100, []*structs.Evaluation{eval}))
+	require.NoError(t, state.UpsertEvals(structs.MsgTypeTestSetup, 101, []*structs.Evaluation{eval2}))
+	require.NoError(t, state.UpsertEvals(structs.MsgTypeTestSetup, 102, []*structs.Evaluation{eval3}))
+	for _, e := range []*structs.Evaluation{eval, eval2, eval3} {
+		out, err := state.EvalByID(nil, e.ID)
+		require.NoError(t, err)
+		s1.evalBroker.Enqueue(out)
+	}
+
+	// Processing the first eval leaves only the newest one pending
+	out, token, err := s1.evalBroker.Dequeue(defaultSched, time.Second)
+	require.NoError(t, err)
+	require.Equal(t, eval.ID, out.ID)
+	require.NoError(t, s1.evalBroker.Ack(out.ID, token))
+
+	out, _, err = s1.evalBroker.Dequeue(defaultSched, time.Second)
+	require.NoError(t, err)
+	require.Equal(t, eval3.ID, out.ID)
+
+	// Wait for the redundant evaluation to be marked as cancelled
+	testutil.WaitForResult(func() (bool, error) {
+		out, err := state.EvalByID(nil, eval2.ID)
+		if err != nil {
+			return false, err
+		}
+		return out != nil && out.Status == structs.EvalStatusCancelled, nil
+	}, func(err error) {
+		t.Fatalf("err: %v", err)
+	})
+	require.Zero(t, s1.evalBroker.Stats().TotalCancelable)
+}
+
+// TestLeader_ReapCancelableEvals_ApplyFailure asserts that redundant evals
+// that fail to be canceled are returned to the eval broker and canceled on a
+// later attempt.
+func TestLeader_ReapCancelableEvals_ApplyFailure(t *testing.T) {
+	t.Parallel()
+
+	broker := testBroker(t, 0)
+	broker.SetEnabled(true)
+	s := &Server{evalBroker: broker, logger: testlog.HCLogger(t)}
+
+	evals := []*structs.Evaluation{mock.Eval(), mock.Eval()}
+	broker.l.Lock()
+	broker.markCancelableLocked(evals)
+	broker.l.Unlock()
+
+	var calls int32
+	canceledCh := make(chan []*structs.Evaluation, 1)
+	apply := func(_ structs.MessageType, msg interface{}) (interface{}, uint64, error) {
+		if atomic.AddInt32(&calls, 1) == 1 {
+			return nil, 0, errors.New("raft failure")
+		}
+		canceledCh <- msg.(*structs.EvalUpdateRequest).Evals
+		return nil, 1, nil
+	}
+
+	stopCh := make(chan struct{})
+	defer close(stopCh)
+	go s.reapCancelableEvaluations(stopCh, apply)
+
+	select {
+	case canceled := <-canceledCh:
+		require.Len(t, canceled, 2)
+		for i, eval := range canceled {
+			require.Equal(t, evals[i].ID, eval.ID)
+			require.Equal(t, structs.EvalStatusCancelled, eval.Status)
+		}
+	case <-time.After(5 * time.Second):
+		t.Fatal("redundant evals were not canceled after the failure")
+	}
+	require.Equal(t, int32(2), atomic.LoadInt32(&calls))
+
+	stats := broker.Stats()
+	require.Zero(t, stats.TotalCancelable)
+	require.Equal(t, uint64(2), stats.TotalCanceled)
+}
+
 func TestLeader_revokeVaultAccessorsOnRestore(t *testing.T) {
 	s1, cleanupS1 := TestServer(t, func(c *Config) {
 		c.NumSchedulers = 0
@@ -16,6 +16,7 @@ func Node() *structs.Node {
 		ID:         uuid.Generate(),
 		SecretID:   uuid.Generate(),
 		Datacenter: "dc1",
+		NodePool:   structs.NodePoolDefault,
 		Name:       "foobar",
 		Drivers: map[string]*structs.DriverInfo{
 			"exec": {
@@ -1907,3 +1908,56 @@ func Namespace() *structs.Namespace {
 	ns.SetHash()
 	return ns
 }
+
+func NodePool() *structs.NodePool {
+	return &structs.NodePool{
+		Name:        fmt.Sprintf("pool-%s", uuid.Short()),
+		Description: "test node pool",
+		Meta:        map[string]string{"team": "test"},
+	}
+}
+
+func Reservation() *structs.Reservation {
+	return &structs.Reservation{
+		Name:        fmt.Sprintf("reservation-%s", uuid.Short()),
+		Namespace:   structs.DefaultNamespace,
+		Datacenters: []string{"dc1"},
+		NodePool:    structs.NodePoolDefault,
+		Resources: &structs.ReservationResources{
+			CPU:      500,
+			MemoryMB: 256,
+		},
+		Count: 1,
+	}
+}
+
+// ServiceRegistrations generates an array containing two unique service
+// registrations.
+func ServiceRegistrations() []*structs.ServiceRegistration {
+	return []*structs.ServiceRegistration{
+		{
+			ID:          "_nomad-task-2873cf75-42e5-7c45-ca1c-415f3e18be3d-group-cache-example-cache-db",
+			ServiceName: "example-cache",
+			Namespace:   "default",
+			NodeID:      "17a6d1c0-811e-2ca9-ded0-3d5d6a54904c",
+			Datacenter:  "dc1",
+			JobID:       "example",
+			AllocID:     "2873cf75-42e5-7c45-ca1c-415f3e18be3d",
+			Tags:        []string{"foo"},
+			Address:     "192.168.10.1",
+			Port:        23000,
+		},
+		{
+			ID:          "_nomad-task-ca60e901-675a-0ab2-2e57-2f3b05fdc540-group-api-countdash-api-http",
+			ServiceName: "countdash-api",
+			Namespace:   "platform",
+			NodeID:      "ba991c17-7ce5-9c20-78b7-311e63578583",
+			Datacenter:  "dc2",
+			JobID:       "countdash-api",
+			AllocID:     "ca60e901-675a-0ab2-2e57-2f3b05fdc540",
+			Tags:        []string{"bar"},
+			Address:     "192.168.200.200",
+			Port:        29000,
+		},
+	}
+}
@@ -48,6 +48,10 @@ const (
 	// NodeHeartbeatEventReregistered is the message used when the node becomes
 	// reregistered by the heartbeat.
 	NodeHeartbeatEventReregistered = "Node reregistered by heartbeat"
+
+	// NodeHeartbeatEventReconnected is the message used when a disconnected
+	// node reconnects by heartbeat.
+	NodeHeartbeatEventReconnected = "Node reconnected by heartbeat"
 )
 
 // Node endpoint is used for client interactions
@@ -112,6 +116,14 @@ func (n *Node) Register(args *structs.NodeRegisterRequest, reply *structs.NodeUp
 	if args.Node.SecretID == "" {
 		return fmt.Errorf("missing node secret ID for client registration")
 	}
+	if args.Node.NodePool != "" {
+		if err := structs.ValidateNodePoolName(args.Node.NodePool); err != nil {
+			return fmt.Errorf("invalid node pool: %v", err)
+		}
+		if args.Node.NodePool == structs.NodePoolAll {
+			return fmt.Errorf("node is not allowed to register in node pool %q", structs.NodePoolAll)
+		}
+	}
 
 	// Default the status if none is given
 	if args.Node.Status == "" {
@@ -481,6 +493,14 @@ func (n *Node) UpdateStatus(args *structs.NodeUpdateStatusRequest, reply *struct
 				SetMessage(NodeHeartbeatEventReregistered)
 		}
 
+		// Attach an event if the node is reconnecting after being
+		// disconnected
+		if node.Status == structs.NodeStatusDisconnected && args.NodeEvent == nil {
+			args.NodeEvent = structs.NewNodeEvent().
+				SetSubsystem(structs.NodeEventSubsystemCluster).
+				SetMessage(NodeHeartbeatEventReconnected)
+		}
+
 		_, index, err = n.srv.raftApply(structs.NodeUpdateStatusRequestType, args)
 		if err != nil {
 			n.logger.Error("status update failed", "error", err)
@@ -489,9 +509,13 @@ func (n *Node) UpdateStatus(args *structs.NodeUpdateStatusRequest, reply *struct
 		reply.NodeModifyIndex = index
 	}
 
-	// Check if we should trigger evaluations
+	// Check if we should trigger evaluations. A node that remains
+	// disconnected doesn't need new evaluations since its allocations were
+	// already handled when it first disconnected.
 	transitionToReady := transitionedToReady(args.Status, node.Status)
-	if structs.ShouldDrainNode(args.Status) || transitionToReady {
+	stillDisconnected := node.Status == structs.NodeStatusDisconnected &&
+		args.Status == structs.NodeStatusDisconnected
+	if (structs.ShouldDrainNode(args.Status) && !stillDisconnected) || transitionToReady {
 		evalIDs, evalIndex, err := n.createNodeEvals(args.NodeID, index)
 		if err != nil {
 			n.logger.Error("eval creation failed", "error", err)
@@ -524,6 +548,23 @@ func (n *Node) UpdateStatus(args *structs.NodeUpdateStatusRequest, reply *struct
 			n.logger.Debug("revoking SI accessors on node due to down state", "num_accessors", l, "node_id", args.NodeID)
 			_ = n.srv.consulACLs.RevokeTokens(context.Background(), accessors, true)
 		}
+
+		// Identify the service registrations current placed on the downed
+		// node. Only perform the delete when there are registrations, to
+		// avoid unnecessary Raft writes.
+		if serviceRegistrations, err := n.srv.State().GetServiceRegistrationsByNodeID(ws, args.NodeID); err != nil {
+			n.logger.Error("looking up service registrations for node failed", "node_id", args.NodeID, "error", err)
+			return err
+		} else if len(serviceRegistrations) > 0 {
+			delReq := structs.ServiceRegistrationDeleteByNodeIDRequest{
+				NodeID:       args.NodeID,
+				WriteRequest: structs.WriteRequest{Region: args.Region},
+			}
+			if _, _, err := n.srv.raftApply(structs.ServiceRegistrationDeleteByNodeIDRequestType, &delReq); err != nil {
+				n.logger.Error("removing service registrations for node failed", "node_id", args.NodeID, "error", err)
+				return err
+			}
+		}
 	default:
 		ttl, err := n.srv.resetHeartbeatTimer(args.NodeID)
 		if err != nil {
@@ -550,7 +591,8 @@ func (n *Node) UpdateStatus(args *structs.NodeUpdateStatusRequest, reply *struct
 func transitionedToReady(newStatus, oldStatus string) bool {
 	initToReady := oldStatus == structs.NodeStatusInit && newStatus == structs.NodeStatusReady
 	terminalToReady := oldStatus == structs.NodeStatusDown && newStatus == structs.NodeStatusReady
-	return initToReady || terminalToReady
+	disconnectedToReady := oldStatus == structs.NodeStatusDisconnected && newStatus == structs.NodeStatusReady
+	return initToReady || terminalToReady || disconnectedToReady
 }
 
 // UpdateDrain is used to update the drain mode of a client node
@@ -1121,12 +1163,16 @@ func (n *Node) UpdateAlloc(args *structs.AllocUpdateRequest, reply *structs.Gene
 	for _, allocToUpdate := range args.Alloc {
 		allocToUpdate.ModifyTime = now.UTC().UnixNano()
 
-		if !allocToUpdate.TerminalStatus() {
+		alloc, _ := n.srv.State().AllocByID(nil, allocToUpdate.ID)
+		if alloc == nil {
 			continue
 		}
 
-		alloc, _ := n.srv.State().AllocByID(nil, allocToUpdate.ID)
-		if alloc == nil {
+		// Only terminal allocations and allocations reconnecting after
+		// their client was disconnected may need an evaluation.
+		reconnecting := alloc.ClientStatus == structs.AllocClientStatusUnknown &&
+			allocToUpdate.ClientStatus != structs.AllocClientStatusUnknown
+		if !allocToUpdate.TerminalStatus() && !reconnecting {
 			continue
 		}
 
@@ -1160,6 +1206,22 @@ func (n *Node) UpdateAlloc(args *structs.AllocUpdateRequest, reply *structs.Gene
 				ModifyTime:  now.UTC().UnixNano(),
 			}
 			evals = append(evals, eval)
+		} else if reconnecting {
+			// The client of the allocation reconnected, so the scheduler
+			// needs to reconcile it with any replacement placed while it
+			// was disconnected.
+			eval := &structs.Evaluation{
+				ID:          uuid.Generate(),
+				Namespace:   alloc.Namespace,
+				TriggeredBy: structs.EvalTriggerReconnect,
+				JobID:       alloc.JobID,
+				Type:        job.Type,
+				Priority:    job.Priority,
+				Status:      structs.EvalStatusPending,
+				CreateTime:  now.UTC().UnixNano(),
+				ModifyTime:  now.UTC().UnixNano(),
+			}
+			evals = append(evals, eval)
 		}
 	}
 
@@ -1311,36 +1373,44 @@ func (n *Node) List(args *structs.NodeListRequest,
 		return structs.ErrPermissionDenied
 	}
 
+	filter, err := newListFilter(&args.QueryOptions, (*structs.NodeListStub)(nil))
+	if err != nil {
+		return err
+	}
+	sort := state.SortOption(args.Reverse)
+
 	// Setup the blocking query
 	opts := blockingOptions{
 		queryOpts: &args.QueryOptions,
 		queryMeta: &reply.QueryMeta,
-		run: func(ws memdb.WatchSet, state *state.StateStore) error {
-			// Capture all the nodes
-			var err error
-			var iter memdb.ResultIterator
-			if prefix := args.QueryOptions.Prefix; prefix != "" {
-				iter, err = state.NodesByIDPrefix(ws, prefix)
-			} else {
-				iter, err = state.Nodes(ws)
-			}
+		run: func(ws memdb.WatchSet, store *state.StateStore) error {
+			// Capture all the nodes. An empty prefix matches every node.
+			iter, err := store.NodesByIDPrefix(ws, args.QueryOptions.Prefix, sort)
 			if err != nil {
 				return err
 			}
 
 			var nodes []*structs.NodeListStub
-			for {
-				raw := iter.Next()
-				if raw == nil {
-					break
-				}
-				node := raw.(*structs.Node)
-				nodes = append(nodes, node.Stub(args.Fields))
+			paginator := state.NewPaginator(iter, state.IDTokenizer{}, args.QueryOptions,
+				func(raw interface{}) (bool, error) {
+					node := raw.(*structs.Node)
+					stub := node.Stub(args.Fields)
+					if match, err := filter.Match(stub); err != nil || !match {
+						return false, err
+					}
+					nodes = append(nodes, stub)
+					return true, nil
+				})
+
+			nextToken, err := paginator.Page()
+			if err != nil {
+				return err
 			}
+			reply.QueryMeta.NextToken = nextToken
 			reply.Nodes = nodes
 
 			// Use the last index that affected the jobs table
-			index, err := state.Index("nodes")
+			index, err := store.Index("nodes")
 			if err != nil {
 				return err
 			}
@@ -1914,6 +1984,80 @@ func taskUsesConnect(task *structs.Task) bool {
 	return task.UsesConnect()
 }
 
+// SignIdentities is used by clients to renew the signed workload identities
+// of the tasks of an allocation running on the node before they expire.
+func (n *Node) SignIdentities(args *structs.SignIdentitiesRequest, reply *structs.SignIdentitiesResponse) error {
+	if done, err := n.srv.forward("Node.SignIdentities", args, args, reply); done {
+		return err
+	}
+	defer metrics.MeasureSince([]string{"nomad", "client", "sign_identities"}, time.Now())
+
+	// Verify the arguments
+	if args.NodeID == "" {
+		return fmt.Errorf("missing node ID")
+	}
+	if args.SecretID == "" {
+		return fmt.Errorf("missing node SecretID")
+	}
+	if args.AllocID == "" {
+		return fmt.Errorf("missing allocation ID")
+	}
+	if len(args.Tasks) == 0 {
+		return fmt.Errorf("no tasks specified")
+	}
+
+	// Verify the node exists with the correct SecretID and that the
+	// allocation is still running on it
+	snap, err := n.srv.fsm.State().Snapshot()
+	if err != nil {
+		return err
+	}
+	node, err := snap.NodeByID(nil, args.NodeID)
+	if err != nil {
+		return err
+	}
+	if node == nil {
+		return fmt.Errorf("Node %q does not exist", args.NodeID)
+	}
+	if node.SecretID != args.SecretID {
+		return fmt.Errorf("SecretID mismatch")
+	}
+
+	alloc, err := snap.AllocByID(nil, args.AllocID)
+	if err != nil {
+		return err
+	}
+	if alloc == nil {
+		return fmt.Errorf("Allocation %q does not exist", args.AllocID)
+	}
+	if alloc.NodeID != args.NodeID {
+		return fmt.Errorf("Allocation %q not running on Node %q", args.AllocID, args.NodeID)
+	}
+	if alloc.TerminalStatus() {
+		return fmt.Errorf("Can't sign identities for terminal allocation")
+	}
+	tg := alloc.Job.LookupTaskGroup(alloc.TaskGroup)
+	if tg == nil {
+		return fmt.Errorf("Task group %q not found in job", alloc.TaskGroup)
+	}
+
+	now := time.Now()
+	reply.SignedIdentities = make(map[string]string, len(args.Tasks))
+	for _, task := range args.Tasks {
+		if tg.LookupTask(task) == nil {
+			return fmt.Errorf("Task %q not found in task group", task)
+		}
+		token, err := n.srv.encrypter.SignClaims(structs.NewIdentityClaims(alloc, task, now))
+		if err != nil {
+			return fmt.Errorf("failed to sign workload identity for task %q: %v", task, err)
+		}
+		reply.SignedIdentities[task] = token
+	}
+
+	n.srv.setQueryMeta(&reply.QueryMeta)
+	return nil
+}
+
 func (n *Node) EmitEvents(args *structs.EmitNodeEventsRequest, reply *structs.EmitNodeEventsResponse) error {
 	if done, err := n.srv.forward("Node.EmitEvents", args, args, reply); done {
 		return err
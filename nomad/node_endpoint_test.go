@@ -2065,11 +2065,11 @@ func TestClientEndpoint_GetClientAllocs_Blocking(t *testing.T) {
 	alloc := mock.Alloc()
 	alloc.NodeID = node.ID
 	alloc.ModifyTime = now
-	state := s1.fsm.State()
-	state.UpsertJobSummary(99, mock.JobSummary(alloc.JobID))
+	store := s1.fsm.State()
+	store.UpsertJobSummary(99, mock.JobSummary(alloc.JobID))
 	start := time.Now()
 	time.AfterFunc(100*time.Millisecond, func() {
-		err := state.UpsertAllocs(structs.MsgTypeTestSetup, 100, []*structs.Allocation{alloc})
+		err := store.UpsertAllocs(structs.MsgTypeTestSetup, 100, []*structs.Allocation{alloc})
 		if err != nil {
 			t.Fatalf("err: %v", err)
 		}
@@ -2103,7 +2103,7 @@ func TestClientEndpoint_GetClientAllocs_Blocking(t *testing.T) {
 		t.Fatalf("bad: %#v", resp2.Allocs)
 	}
 
-	iter, err := state.AllocsByIDPrefix(nil, structs.DefaultNamespace, alloc.ID)
+	iter, err := store.AllocsByIDPrefix(nil, structs.DefaultNamespace, alloc.ID, state.SortDefault)
 	if err != nil {
 		t.Fatalf("err: %v", err)
 	}
@@ -2135,8 +2135,8 @@ func TestClientEndpoint_GetClientAllocs_Blocking(t *testing.T) {
 		allocUpdate.NodeID = alloc.NodeID
 		allocUpdate.ID = alloc.ID
 		allocUpdate.ClientStatus = structs.AllocClientStatusRunning
-		state.UpsertJobSummary(199, mock.JobSummary(allocUpdate.JobID))
-		err := state.UpsertAllocs(structs.MsgTypeTestSetup, 200, []*structs.Allocation{allocUpdate})
+		store.UpsertJobSummary(199, mock.JobSummary(allocUpdate.JobID))
+		err := store.UpsertAllocs(structs.MsgTypeTestSetup, 200, []*structs.Allocation{allocUpdate})
 		if err != nil {
 			t.Fatalf("err: %v", err)
 		}
@@ -2480,6 +2480,61 @@ func TestClientEndpoint_UpdateAlloc(t *testing.T) {
 
 }
 
+func TestClientEndpoint_UpdateAlloc_Reconnect(t *testing.T) {
+	t.Parallel()
+
+	s1, cleanupS1 := TestServer(t, func(c *Config) {
+		// Disabling scheduling in this test so that we can
+		// ensure that the state store doesn't accumulate more evals
+		// than what we expect the unit test to add
+		c.NumSchedulers = 0
+	})
+	defer cleanupS1()
+	codec := rpcClient(t, s1)
+	testutil.WaitForLeader(t, s1.RPC)
+	require := require.New(t)
+
+	node := mock.Node()
+	state := s1.fsm.State()
+	require.NoError(state.UpsertNode(structs.MsgTypeTestSetup, 100, node))
+
+	job := mock.Job()
+	job.TaskGroups[0].MaxClientDisconnect = helper.TimeToPtr(5 * time.Minute)
+	require.NoError(state.UpsertJob(structs.MsgTypeTestSetup, 101, job))
+
+	// Inject an alloc marked unknown while its client was disconnected
+	alloc := mock.Alloc()
+	alloc.Job = job
+	alloc.JobID = job.ID
+	alloc.NodeID = node.ID
+	alloc.ClientStatus = structs.AllocClientStatusUnknown
+	alloc.AppendState(structs.AllocStateFieldClientStatus, structs.AllocClientStatusUnknown)
+	require.NoError(state.UpsertAllocs(structs.MsgTypeTestSetup, 102, []*structs.Allocation{alloc}))
+
+	// The client reconnects and reports the alloc as running
+	clientAlloc := new(structs.Allocation)
+	*clientAlloc = *alloc
+	clientAlloc.ClientStatus = structs.AllocClientStatusRunning
+
+	update := &structs.AllocUpdateRequest{
+		Alloc:        []*structs.Allocation{clientAlloc},
+		WriteRequest: structs.WriteRequest{Region: "global"},
+	}
+	var resp structs.NodeAllocsResponse
+	require.NoError(msgpackrpc.CallWithCodec(codec, "Node.UpdateAlloc", update, &resp))
+
+	out, err := state.AllocByID(nil, alloc.ID)
+	require.NoError(err)
+	require.Equal(structs.AllocClientStatusRunning, out.ClientStatus)
+	require.True(out.NeedsToReconcile())
+
+	// An eval is created to reconcile the reconnected alloc
+	evals, err := state.EvalsByJob(nil, job.Namespace, job.ID)
+	require.NoError(err)
+	require.Len(evals, 1)
+	require.Equal(structs.EvalTriggerReconnect, evals[0].TriggeredBy)
+}
+
 func TestClientEndpoint_BatchUpdate(t *testing.T) {
 	t.Parallel()
 
@@ -3524,6 +3579,73 @@ func mutateConnectJob(t *testing.T, job *structs.Job) {
 	require.NoError(t, err)
 }
 
+func TestClientEndpoint_SignIdentities(t *testing.T) {
+	t.Parallel()
+
+	s1, cleanupS1 := TestServer(t, nil)
+	defer cleanupS1()
+	state := s1.fsm.State()
+	codec := rpcClient(t, s1)
+	testutil.WaitForLeader(t, s1.RPC)
+	waitForKeyring(t, s1)
+
+	node := mock.Node()
+	require.NoError(t, state.UpsertNode(structs.MsgTypeTestSetup, 2, node))
+
+	alloc := mock.Alloc()
+	task := alloc.Job.TaskGroups[0].Tasks[0]
+	require.NoError(t, state.UpsertAllocs(structs.MsgTypeTestSetup, 3, []*structs.Allocation{alloc}))
+
+	req := &structs.SignIdentitiesRequest{
+		NodeID:   node.ID,
+		SecretID: uuid.Generate(),
+		AllocID:  alloc.ID,
+		Tasks:    []string{task.Name},
+		QueryOptions: structs.QueryOptions{
+			Region: "global",
+		},
+	}
+
+	// A bad SecretID is rejected
+	var resp structs.SignIdentitiesResponse
+	err := msgpackrpc.CallWithCodec(codec, "Node.SignIdentities", req, &resp)
+	require.EqualError(t, err, "SecretID mismatch")
+
+	// Allocations on other nodes are rejected
+	req.SecretID = node.SecretID
+	err = msgpackrpc.CallWithCodec(codec, "Node.SignIdentities", req, &resp)
+	require.Error(t, err)
+	require.Contains(t, err.Error(), "not running on Node")
+
+	alloc = alloc.Copy()
+	alloc.NodeID = node.ID
+	require.NoError(t, state.UpsertAllocs(structs.MsgTypeTestSetup, 4, []*structs.Allocation{alloc}))
+
+	// Unknown tasks are rejected
+	req.Tasks = []string{"unknown"}
+	err = msgpackrpc.CallWithCodec(codec, "Node.SignIdentities", req, &resp)
+	require.Error(t, err)
+	require.Contains(t, err.Error(), "not found in task group")
+
+	req.Tasks = []string{task.Name}
+	require.NoError(t, msgpackrpc.CallWithCodec(codec, "Node.SignIdentities", req, &resp))
+	require.Len(t, resp.SignedIdentities, 1)
+
+	claims, err := s1.encrypter.VerifyClaim(resp.SignedIdentities[task.Name])
+	require.NoError(t, err)
+	require.Equal(t, alloc.ID, claims.AllocationID)
+	require.Equal(t, task.Name, claims.TaskName)
+	require.NotNil(t, claims.Expiry)
+
+	// Terminal allocations are rejected
+	alloc = alloc.Copy()
+	alloc.DesiredStatus = structs.AllocDesiredStatusStop
+	require.NoError(t, state.UpsertAllocs(structs.MsgTypeTestSetup, 5, []*structs.Allocation{alloc}))
+	err = msgpackrpc.CallWithCodec(codec, "Node.SignIdentities", req, &resp)
+	require.Error(t, err)
+	require.Contains(t, err.Error(), "terminal allocation")
+}
+
 func TestClientEndpoint_DeriveSIToken(t *testing.T) {
 	t.Parallel()
 	r := require.New(t)
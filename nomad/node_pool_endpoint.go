@@ -0,0 +1,364 @@
+package nomad
+
+import (
+	"fmt"
+	"time"
+
+	metrics "github.com/armon/go-metrics"
+	log "github.com/hashicorp/go-hclog"
+	memdb "github.com/hashicorp/go-memdb"
+
+	"github.com/hashicorp/nomad/acl"
+	"github.com/hashicorp/nomad/helper"
+	"github.com/hashicorp/nomad/nomad/state"
+	"github.com/hashicorp/nomad/nomad/structs"
+)
+
+// NodePool endpoint is used for manipulating node pools.
+type NodePool struct {
+	srv    *Server
+	logger log.Logger
+}
+
+// List is used to list all the node pools the token is allowed to see.
+func (n *NodePool) List(args *structs.NodePoolListRequest, reply *structs.NodePoolListResponse) error {
+	if done, err := n.srv.forward("NodePool.List", args, args, reply); done {
+		return err
+	}
+	defer metrics.MeasureSince([]string{"nomad", "node_pool", "list"}, time.Now())
+
+	// Resolve token to ACL to filter node pools list
+	aclObj, err := n.srv.ResolveToken(args.AuthToken)
+	if err != nil {
+		return err
+	}
+
+	filter, err := newListFilter(&args.QueryOptions, (*structs.NodePool)(nil))
+	if err != nil {
+		return err
+	}
+	sort := state.SortOption(args.Reverse)
+
+	// Setup blocking query
+	opts := blockingOptions{
+		queryOpts: &args.QueryOptions,
+		queryMeta: &reply.QueryMeta,
+		run: func(ws memdb.WatchSet, store *state.StateStore) error {
+			var err error
+			var iter memdb.ResultIterator
+			if prefix := args.QueryOptions.Prefix; prefix != "" {
+				iter, err = store.NodePoolsByNamePrefix(ws, prefix, sort)
+			} else {
+				iter, err = store.NodePools(ws, sort)
+			}
+			if err != nil {
+				return err
+			}
+
+			var pools []*structs.NodePool
+			paginator := state.NewPaginator(iter, state.NameTokenizer{}, args.QueryOptions,
+				func(raw interface{}) (bool, error) {
+					pool := raw.(*structs.NodePool)
+
+					// Only return node pools allowed by the ACL
+					if aclObj != nil && !aclObj.AllowNodePool(pool.Name) {
+						return false, nil
+					}
+					if match, err := filter.Match(pool); err != nil || !match {
+						return false, err
+					}
+					pools = append(pools, pool)
+					return true, nil
+				})
+
+			nextToken, err := paginator.Page()
+			if err != nil {
+				return err
+			}
+			reply.QueryMeta.NextToken = nextToken
+			reply.NodePools = pools
+
+			// Use the last index that affected the node pools table
+			index, err := store.Index(state.TableNodePools)
+			if err != nil {
+				return err
+			}
+			reply.Index = helper.Uint64Max(1, index)
+			return nil
+		}}
+	return n.srv.blockingRPC(&opts)
+}
+
+// GetNodePool returns the specific node pool requested or nil if the node
+// pool doesn't exist.
+func (n *NodePool) GetNodePool(args *structs.NodePoolSpecificRequest, reply *structs.SingleNodePoolResponse) error {
+	if done, err := n.srv.forward("NodePool.GetNodePool", args, args, reply); done {
+		return err
+	}
+	defer metrics.MeasureSince([]string{"nomad", "node_pool", "get_node_pool"}, time.Now())
+
+	// Check node pool read permissions
+	if aclObj, err := n.srv.ResolveToken(args.AuthToken); err != nil {
+		return err
+	} else if aclObj != nil && !aclObj.AllowNodePoolOperation(args.Name, acl.NodePoolCapabilityRead) {
+		return structs.ErrPermissionDenied
+	}
+
+	// Setup the blocking query
+	opts := blockingOptions{
+		queryOpts: &args.QueryOptions,
+		queryMeta: &reply.QueryMeta,
+		run: func(ws memdb.WatchSet, store *state.StateStore) error {
+			// Look for the node pool
+			pool, err := store.NodePoolByName(ws, args.Name)
+			if err != nil {
+				return err
+			}
+
+			reply.NodePool = pool
+			if pool != nil {
+				reply.Index = pool.ModifyIndex
+			} else {
+				// Use the last index that affected the node pools table
+				index, err := store.Index(state.TableNodePools)
+				if err != nil {
+					return err
+				}
+				reply.Index = helper.Uint64Max(1, index)
+			}
+			return nil
+		}}
+	return n.srv.blockingRPC(&opts)
+}
+
+// UpsertNodePools creates or updates the given node pools. Built-in node
+// pools cannot be modified.
+func (n *NodePool) UpsertNodePools(args *structs.NodePoolUpsertRequest, reply *structs.GenericResponse) error {
+	if done, err := n.srv.forward("NodePool.UpsertNodePools", args, args, reply); done {
+		return err
+	}
+	defer metrics.MeasureSince([]string{"nomad", "node_pool", "upsert_node_pools"}, time.Now())
+
+	// Validate there is at least one node pool
+	if len(args.NodePools) == 0 {
+		return fmt.Errorf("must specify at least one node pool")
+	}
+
+	// Check node pool write permissions
+	aclObj, err := n.srv.ResolveToken(args.AuthToken)
+	if err != nil {
+		return err
+	}
+	for _, pool := range args.NodePools {
+		if aclObj != nil && !aclObj.AllowNodePoolOperation(pool.Name, acl.NodePoolCapabilityWrite) {
+			return structs.ErrPermissionDenied
+		}
+	}
+
+	// Validate the node pools
+	for _, pool := range args.NodePools {
+		if err := pool.Validate(); err != nil {
+			return fmt.Errorf("invalid node pool %q: %v", pool.Name, err)
+		}
+		if pool.IsBuiltIn() {
+			return fmt.Errorf("modifying node pool %q is not allowed", pool.Name)
+		}
+	}
+
+	// Update via Raft
+	out, index, err := n.srv.raftApply(structs.NodePoolUpsertRequestType, args)
+	if err != nil {
+		return err
+	}
+
+	// Check if there was an error when applying.
+	if err, ok := out.(error); ok && err != nil {
+		return err
+	}
+
+	reply.Index = index
+	return nil
+}
+
+// DeleteNodePools deletes the given node pools. Built-in node pools and node
+// pools that still have nodes or jobs cannot be deleted.
+func (n *NodePool) DeleteNodePools(args *structs.NodePoolDeleteRequest, reply *structs.GenericResponse) error {
+	if done, err := n.srv.forward("NodePool.DeleteNodePools", args, args, reply); done {
+		return err
+	}
+	defer metrics.MeasureSince([]string{"nomad", "node_pool", "delete_node_pools"}, time.Now())
+
+	// Validate there is at least one node pool
+	if len(args.Names) == 0 {
+		return fmt.Errorf("must specify at least one node pool to delete")
+	}
+
+	// Check node pool delete permissions
+	aclObj, err := n.srv.ResolveToken(args.AuthToken)
+	if err != nil {
+		return err
+	}
+	for _, name := range args.Names {
+		if aclObj != nil && !aclObj.AllowNodePoolOperation(name, acl.NodePoolCapabilityDelete) {
+			return structs.ErrPermissionDenied
+		}
+	}
+
+	for _, name := range args.Names {
+		if name == structs.NodePoolAll || name == structs.NodePoolDefault {
+			return fmt.Errorf("deleting built-in node pool %q is not allowed", name)
+		}
+	}
+
+	// Update via Raft
+	out, index, err := n.srv.raftApply(structs.NodePoolDeleteRequestType, args)
+	if err != nil {
+		return err
+	}
+
+	// Check if there was an error when applying.
+	if err, ok := out.(error); ok && err != nil {
+		return err
+	}
+
+	reply.Index = index
+	return nil
+}
+
+// ListNodes is used to list the nodes that belong to a node pool.
+func (n *NodePool) ListNodes(args *structs.NodePoolNodesRequest, reply *structs.NodePoolNodesResponse) error {
+	if done, err := n.srv.forward("NodePool.ListNodes", args, args, reply); done {
+		return err
+	}
+	defer metrics.MeasureSince([]string{"nomad", "node_pool", "list_nodes"}, time.Now())
+
+	// Check node read and node pool read permissions
+	if aclObj, err := n.srv.ResolveToken(args.AuthToken); err != nil {
+		return err
+	} else if aclObj != nil {
+		if !aclObj.AllowNodeRead() || !aclObj.AllowNodePoolOperation(args.Name, acl.NodePoolCapabilityRead) {
+			return structs.ErrPermissionDenied
+		}
+	}
+
+	filter, err := newListFilter(&args.QueryOptions, (*structs.Node)(nil))
+	if err != nil {
+		return err
+	}
+
+	// Setup blocking query
+	opts := blockingOptions{
+		queryOpts: &args.QueryOptions,
+		queryMeta: &reply.QueryMeta,
+		run: func(ws memdb.WatchSet, store *state.StateStore) error {
+			pool, err := store.NodePoolByName(ws, args.Name)
+			if err != nil {
+				return err
+			}
+
+			reply.Nodes = nil
+			if pool != nil {
+				iter, err := store.NodesByNodePool(ws, pool.Name)
+				if err != nil {
+					return err
+				}
+
+				var nodes []*structs.NodeListStub
+				paginator := state.NewPaginator(iter, state.IDTokenizer{}, args.QueryOptions,
+					func(raw interface{}) (bool, error) {
+						node := raw.(*structs.Node)
+						if match, err := filter.Match(node); err != nil || !match {
+							return false, err
+						}
+						nodes = append(nodes, node.Stub(args.Fields))
+						return true, nil
+					})
+
+				nextToken, err := paginator.Page()
+				if err != nil {
+					return err
+				}
+				reply.QueryMeta.NextToken = nextToken
+				reply.Nodes = nodes
+			}
+
+			// Use the last index that affected the nodes table
+			index, err := store.Index("nodes")
+			if err != nil {
+				return err
+			}
+			reply.Index = helper.Uint64Max(1, index)
+			return nil
+		}}
+	return n.srv.blockingRPC(&opts)
+}
+
+// ListJobs is used to list the jobs that target a node pool. Only jobs in
+// namespaces the token is allowed to list jobs in are returned.
+func (n *NodePool) ListJobs(args *structs.NodePoolJobsRequest, reply *structs.NodePoolJobsResponse) error {
+	if done, err := n.srv.forward("NodePool.ListJobs", args, args, reply); done {
+		return err
+	}
+	defer metrics.MeasureSince([]string{"nomad", "node_pool", "list_jobs"}, time.Now())
+
+	// Check node pool read permissions
+	aclObj, err := n.srv.ResolveToken(args.AuthToken)
+	if err != nil {
+		return err
+	} else if aclObj != nil && !aclObj.AllowNodePoolOperation(args.Name, acl.NodePoolCapabilityRead) {
+		return structs.ErrPermissionDenied
+	}
+
+	filter, err := newListFilter(&args.QueryOptions, (*structs.Job)(nil))
+	if err != nil {
+		return err
+	}
+
+	// Setup blocking query
+	opts := blockingOptions{
+		queryOpts: &args.QueryOptions,
+		queryMeta: &reply.QueryMeta,
+		run: func(ws memdb.WatchSet, store *state.StateStore) error {
+			iter, err := store.JobsByNodePool(ws, args.Name)
+			if err != nil {
+				return err
+			}
+
+			var jobs []*structs.JobListStub
+			paginator := state.NewPaginator(iter, state.NamespaceIDTokenizer{}, args.QueryOptions,
+				func(raw interface{}) (bool, error) {
+					job := raw.(*structs.Job)
+
+					// Only return jobs in namespaces allowed by the ACL
+					if aclObj != nil && !aclObj.AllowNsOp(job.Namespace, acl.NamespaceCapabilityListJobs) {
+						return false, nil
+					}
+					if match, err := filter.Match(job); err != nil || !match {
+						return false, err
+					}
+
+					summary, err := store.JobSummaryByID(ws, job.Namespace, job.ID)
+					if err != nil {
+						return false, fmt.Errorf("unable to look up summary for job: %v", job.ID)
+					}
+					jobs = append(jobs, job.Stub(summary))
+					return true, nil
+				})
+
+			nextToken, err := paginator.Page()
+			if err != nil {
+				return err
+			}
+			reply.QueryMeta.NextToken = nextToken
+			reply.Jobs = jobs
+
+			// Use the last index that affected the jobs table
+			index, err := store.Index("jobs")
+			if err != nil {
+				return err
+			}
+			reply.Index = helper.Uint64Max(1, index)
+			return nil
+		}}
+	return n.srv.blockingRPC(&opts)
+}
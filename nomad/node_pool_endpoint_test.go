@@ -0,0 +1,176 @@
+package nomad
+
+import (
+	"testing"
+
+	msgpackrpc "github.com/hashicorp/net-rpc-msgpackrpc"
+	"github.com/hashicorp/nomad/acl"
+	"github.com/hashicorp/nomad/nomad/mock"
+	"github.com/hashicorp/nomad/nomad/structs"
+	"github.com/hashicorp/nomad/testutil"
+	"github.com/stretchr/testify/require"
+)
+
+func TestNodePoolEndpoint_UpsertGetDelete(t *testing.T) {
+	t.Parallel()
+	s1, cleanupS1 := TestServer(t, nil)
+	defer cleanupS1()
+	codec := rpcClient(t, s1)
+	testutil.WaitForLeader(t, s1.RPC)
+
+	pool := mock.NodePool()
+	upsertReq := &structs.NodePoolUpsertRequest{
+		NodePools:    []*structs.NodePool{pool},
+		WriteRequest: structs.WriteRequest{Region: "global"},
+	}
+	var upsertResp structs.GenericResponse
+	require.NoError(t, msgpackrpc.CallWithCodec(codec, "NodePool.UpsertNodePools", upsertReq, &upsertResp))
+	require.NotZero(t, upsertResp.Index)
+
+	getReq := &structs.NodePoolSpecificRequest{
+		Name:         pool.Name,
+		QueryOptions: structs.QueryOptions{Region: "global"},
+	}
+	var getResp structs.SingleNodePoolResponse
+	require.NoError(t, msgpackrpc.CallWithCodec(codec, "NodePool.GetNodePool", getReq, &getResp))
+	require.NotNil(t, getResp.NodePool)
+	require.Equal(t, pool.Description, getResp.NodePool.Description)
+	require.Equal(t, upsertResp.Index, getResp.Index)
+
+	// Built-in node pools cannot be modified or deleted.
+	upsertReq.NodePools = []*structs.NodePool{{Name: structs.NodePoolDefault}}
+	err := msgpackrpc.CallWithCodec(codec, "NodePool.UpsertNodePools", upsertReq, &upsertResp)
+	require.Error(t, err)
+	require.Contains(t, err.Error(), "not allowed")
+
+	deleteReq := &structs.NodePoolDeleteRequest{
+		Names:        []string{structs.NodePoolAll},
+		WriteRequest: structs.WriteRequest{Region: "global"},
+	}
+	var deleteResp structs.GenericResponse
+	err = msgpackrpc.CallWithCodec(codec, "NodePool.DeleteNodePools", deleteReq, &deleteResp)
+	require.Error(t, err)
+	require.Contains(t, err.Error(), "not allowed")
+
+	deleteReq.Names = []string{pool.Name}
+	require.NoError(t, msgpackrpc.CallWithCodec(codec, "NodePool.DeleteNodePools", deleteReq, &deleteResp))
+
+	getResp = structs.SingleNodePoolResponse{}
+	require.NoError(t, msgpackrpc.CallWithCodec(codec, "NodePool.GetNodePool", getReq, &getResp))
+	require.Nil(t, getResp.NodePool)
+}
+
+func TestNodePoolEndpoint_List_ACL(t *testing.T) {
+	t.Parallel()
+	s1, root, cleanupS1 := TestACLServer(t, nil)
+	defer cleanupS1()
+	codec := rpcClient(t, s1)
+	testutil.WaitForLeader(t, s1.RPC)
+
+	store := s1.fsm.State()
+	devPool := &structs.NodePool{Name: "dev-1"}
+	prodPool := &structs.NodePool{Name: "prod-1"}
+	require.NoError(t, store.UpsertNodePools(structs.MsgTypeTestSetup, 1000, []*structs.NodePool{devPool, prodPool}))
+
+	devToken := mock.CreatePolicyAndToken(t, store, 1001, "dev-pools",
+		mock.NodePoolPolicy("dev-*", "read", nil))
+
+	cases := []struct {
+		name     string
+		token    string
+		expected []string
+	}{
+		{
+			name:     "management token",
+			token:    root.SecretID,
+			expected: []string{structs.NodePoolAll, structs.NodePoolDefault, devPool.Name, prodPool.Name},
+		},
+		{
+			name:     "restricted token",
+			token:    devToken.SecretID,
+			expected: []string{devPool.Name},
+		},
+	}
+
+	for _, tc := range cases {
+		t.Run(tc.name, func(t *testing.T) {
+			req := &structs.NodePoolListRequest{
+				QueryOptions: structs.QueryOptions{
+					Region:    "global",
+					AuthToken: tc.token,
+				},
+			}
+			var resp structs.NodePoolListResponse
+			require.NoError(t, msgpackrpc.CallWithCodec(codec, "NodePool.List", req, &resp))
+
+			var names []string
+			for _, pool := range resp.NodePools {
+				names = append(names, pool.Name)
+			}
+			require.ElementsMatch(t, tc.expected, names)
+		})
+	}
+
+	// Writing a node pool requires the write capability.
+	upsertReq := &structs.NodePoolUpsertRequest{
+		NodePools: []*structs.NodePool{{Name: "dev-2"}},
+		WriteRequest: structs.WriteRequest{
+			Region:    "global",
+			AuthToken: devToken.SecretID,
+		},
+	}
+	var upsertResp structs.GenericResponse
+	err := msgpackrpc.CallWithCodec(codec, "NodePool.UpsertNodePools", upsertReq, &upsertResp)
+	require.EqualError(t, err, structs.ErrPermissionDenied.Error())
+
+	writeToken := mock.CreatePolicyAndToken(t, store, 1002, "dev-pools-write",
+		mock.NodePoolPolicy("dev-*", "", []string{acl.NodePoolCapabilityWrite}))
+	upsertReq.AuthToken = writeToken.SecretID
+	require.NoError(t, msgpackrpc.CallWithCodec(codec, "NodePool.UpsertNodePools", upsertReq, &upsertResp))
+}
+
+func TestNodePoolEndpoint_ListNodesAndJobs(t *testing.T) {
+	t.Parallel()
+	s1, cleanupS1 := TestServer(t, nil)
+	defer cleanupS1()
+	codec := rpcClient(t, s1)
+	testutil.WaitForLeader(t, s1.RPC)
+
+	store := s1.fsm.State()
+	pool := mock.NodePool()
+	require.NoError(t, store.UpsertNodePools(structs.MsgTypeTestSetup, 1000, []*structs.NodePool{pool}))
+
+	node := mock.Node()
+	node.NodePool = pool.Name
+	require.NoError(t, store.UpsertNode(structs.MsgTypeTestSetup, 1001, node))
+	otherNode := mock.Node()
+	otherNode.NodePool = structs.NodePoolDefault
+	require.NoError(t, store.UpsertNode(structs.MsgTypeTestSetup, 1002, otherNode))
+
+	job := mock.Job()
+	job.NodePool = pool.Name
+	require.NoError(t, store.UpsertJob(structs.MsgTypeTestSetup, 1003, job))
+
+	nodesReq := &structs.NodePoolNodesRequest{
+		Name:         pool.Name,
+		QueryOptions: structs.QueryOptions{Region: "global"},
+	}
+	var nodesResp structs.NodePoolNodesResponse
+	require.NoError(t, msgpackrpc.CallWithCodec(codec, "NodePool.ListNodes", nodesReq, &nodesResp))
+	require.Len(t, nodesResp.Nodes, 1)
+	require.Equal(t, node.ID, nodesResp.Nodes[0].ID)
+
+	// The "all" node pool includes every node.
+	nodesReq.Name = structs.NodePoolAll
+	require.NoError(t, msgpackrpc.CallWithCodec(codec, "NodePool.ListNodes", nodesReq, &nodesResp))
+	require.Len(t, nodesResp.Nodes, 2)
+
+	jobsReq := &structs.NodePoolJobsRequest{
+		Name:         pool.Name,
+		QueryOptions: structs.QueryOptions{Region: "global"},
+	}
+	var jobsResp structs.NodePoolJobsResponse
+	require.NoError(t, msgpackrpc.CallWithCodec(codec, "NodePool.ListJobs", jobsReq, &jobsResp))
+	require.Len(t, jobsResp.Jobs, 1)
+	require.Equal(t, job.ID, jobsResp.Jobs[0].ID)
+}
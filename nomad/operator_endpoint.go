@@ -335,6 +335,17 @@ func (op *Operator) SchedulerSetConfiguration(args *structs.SchedulerSetConfigRe
 		reply.Updated = respBool
 	}
 	reply.Index = index
+
+	// Pause or resume the eval broker if the configuration changed it. When
+	// resuming, the broker is empty and must be restored from state.
+	if reply.Updated && op.srv.IsLeader() {
+		if op.srv.handleEvalBrokerStateChange(&args.Config) {
+			if err := op.srv.restoreEvals(); err != nil {
+				op.logger.Error("failed to restore evals after resuming eval broker", "error", err)
+				return err
+			}
+		}
+	}
 	return nil
 }
 
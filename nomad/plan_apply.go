@@ -112,6 +112,26 @@ func (p *planner) planApply() {
 		default:
 		}
 
+		// Reservations are committed before any other plan is evaluated, so
+		// that the following plans account for the capacity they hold.
+		if pending.plan.Reservation != nil {
+			if planIndexCh != nil {
+				idx := <-planIndexCh
+				prevPlanResultIndex = max(prevPlanResultIndex, idx)
+				planIndexCh = nil
+			}
+			snap = nil
+
+			index, err := p.applyReservation(pending.plan, prevPlanResultIndex)
+			if err != nil {
+				pending.respond(nil, err)
+				continue
+			}
+			prevPlanResultIndex = max(prevPlanResultIndex, index)
+			pending.respond(&structs.PlanResult{AllocIndex: index}, nil)
+			continue
+		}
+
 		if snap != nil {
 			// If snapshot doesn't contain the previous plan
 			// result's index and the current plan's snapshot it,
@@ -237,6 +257,9 @@ func (p *planner) applyPlan(plan *structs.Plan, result *structs.PlanResult, snap
 		// to approximate the scheduling time.
 		updateAllocTimestamps(req.AllocsUpdated, now)
 
+		// Sign the workload identities of the tasks of new allocations
+		p.signAllocIdentities(plan.Job, req.AllocsUpdated, time.Unix(0, now))
+
 		for _, preemptions := range result.NodePreemptions {
 			for _, preemptedAlloc := range preemptions {
 				req.AllocsPreempted = append(req.AllocsPreempted, normalizePreemptedAlloc(preemptedAlloc, now))
@@ -317,6 +340,93 @@ func (p *planner) applyPlan(plan *structs.Plan, result *structs.PlanResult, snap
 	return future, nil
 }
 
+// applyReservation verifies the reservation of the plan against the latest
+// state and commits it via Raft, returning the index it was committed at.
+func (p *planner) applyReservation(plan *structs.Plan, prevPlanResultIndex uint64) (uint64, error) {
+	snap, err := p.snapshotMinIndex(prevPlanResultIndex, plan.SnapshotIndex)
+	if err != nil {
+		p.logger.Error("failed to snapshot state", "error", err)
+		return 0, err
+	}
+
+	reservation := plan.Reservation
+	if err := evaluateReservationPlan(snap, reservation); err != nil {
+		return 0, err
+	}
+
+	req := &structs.ReservationUpsertRequest{
+		Reservation: reservation,
+		WriteRequest: structs.WriteRequest{
+			Region:    p.config.Region,
+			Namespace: reservation.Namespace,
+		},
+	}
+	out, index, err := p.raftApply(structs.ReservationUpsertRequestType, req)
+	if err != nil {
+		p.logger.Error("failed to apply reservation", "error", err)
+		return 0, err
+	}
+	if err, ok := out.(error); ok && err != nil {
+		return 0, err
+	}
+	return index, nil
+}
+
+// evaluateReservationPlan returns an error if the reservation already exists
+// or if its instances don't fit on their nodes alongside the allocations and
+// the other reservations on them.
+func evaluateReservationPlan(snap *state.StateSnapshot, reservation *structs.Reservation) error {
+	ws := memdb.NewWatchSet()
+	existing, err := snap.ReservationByName(ws, reservation.Namespace, reservation.Name)
+	if err != nil {
+		return err
+	}
+	if existing != nil {
+		return fmt.Errorf("reservation %q already exists", reservation.Name)
+	}
+
+	now := time.Now()
+	seen := make(map[string]struct{}, len(reservation.NodeIDs))
+	for _, nodeID := range reservation.NodeIDs {
+		if _, ok := seen[nodeID]; ok {
+			continue
+		}
+		seen[nodeID] = struct{}{}
+
+		node, err := snap.NodeByID(ws, nodeID)
+		if err != nil {
+			return fmt.Errorf("failed to get node '%s': %v", nodeID, err)
+		}
+		if node == nil {
+			return fmt.Errorf("node %s does not exist", nodeID)
+		} else if node.Status != structs.NodeStatusReady {
+			return fmt.Errorf("node %s is not ready for placements", nodeID)
+		} else if node.SchedulingEligibility == structs.NodeSchedulingIneligible {
+			return fmt.Errorf("node %s is not eligible", nodeID)
+		}
+
+		existingAlloc, err := snap.AllocsByNodeTerminal(ws, nodeID, false)
+		if err != nil {
+			return fmt.Errorf("failed to get existing allocations for '%s': %v", nodeID, err)
+		}
+		reservations, err := snap.ReservationsByNode(ws, nodeID)
+		if err != nil {
+			return fmt.Errorf("failed to get reservations for '%s': %v", nodeID, err)
+		}
+		reservations = append(reservations, reservation)
+
+		proposed := append(existingAlloc, structs.ReservationAllocs(nodeID, reservations, existingAlloc, nil, now)...)
+		fit, reason, _, err := structs.AllocsFit(node, proposed, nil, true)
+		if err != nil {
+			return err
+		}
+		if !fit {
+			return fmt.Errorf("reservation %q no longer fits on node %s: %s", reservation.Name, nodeID, reason)
+		}
+	}
+	return nil
+}
+
 // normalizePreemptedAlloc removes redundant fields from a preempted allocation and
 // returns AllocationDiff. Since a preempted allocation is always an existing allocation,
 // the struct returned by this method contains only the differential, which can be
@@ -362,6 +472,52 @@ func updateAllocTimestamps(allocations []*structs.Allocation, timestamp int64) {
 	}
 }
 
+// signAllocIdentities signs a workload identity for every task of the
+// allocations which does not have one yet. Identities are not signed until
+// the keyring has been initialized. Failing to sign identities doesn't fail
+// the plan: the allocations are committed without them, and clients request
+// the missing identities with Node.SignIdentities.
+func (p *planner) signAllocIdentities(job *structs.Job, allocations []*structs.Allocation, now time.Time) {
+	keyMeta, err := p.State().GetActiveRootKeyMeta(nil)
+	if err != nil {
+		p.log.Error("failed to look up active root key to sign workload identities", "error", err)
+		return
+	}
+	if keyMeta == nil {
+		return
+	}
+
+	for _, alloc := range allocations {
+		allocJob := alloc.Job
+		if allocJob == nil {
+			allocJob = job
+		}
+		if allocJob == nil {
+			continue
+		}
+		tg := allocJob.LookupTaskGroup(alloc.TaskGroup)
+		if tg == nil {
+			continue
+		}
+
+		for _, task := range tg.Tasks {
+			if _, ok := alloc.SignedIdentities[task.Name]; ok {
+				continue
+			}
+			token, err := p.encrypter.SignClaims(structs.NewIdentityClaims(alloc, task.Name, now))
+			if err != nil {
+				p.log.Warn("failed to sign workload identity, committing allocation without it",
+					"alloc_id", alloc.ID, "task", task.Name, "error", err)
+				break
+			}
+			if alloc.SignedIdentities == nil {
+				alloc.SignedIdentities = make(map[string]string, len(tg.Tasks))
+			}
+			alloc.SignedIdentities[task.Name] = token
+		}
+	}
+}
+
 // asyncPlanWait is used to apply and respond to a plan async. On successful
 // commit the plan's index will be sent on the chan. On error the chan will be
 // closed.
@@ -580,6 +736,10 @@ OUTER:
 			mErr.Errors = append(mErr.Errors, err)
 		}
 
+		// If there was a partial commit, all_or_nothing task groups must not
+		// be left partially placed
+		correctAllOrNothingPlacements(plan, result)
+
 		// If there was a partial commit and we are operating within a
 		// deployment correct for any canary that may have been desired to be
 		// placed but wasn't actually placed
@@ -588,6 +748,110 @@ OUTER:
 	return result, mErr.ErrorOrNil()
 }
 
+// correctAllOrNothingPlacements ensures that the plan result doesn't contain
+// a subset of the allocations of a task group with all_or_nothing placement.
+// This could happen if the plan had a partial commit, in which case all of
+// the group's allocations are removed from the result, along with the stops
+// of the allocations they would have replaced.
+func correctAllOrNothingPlacements(plan *structs.Plan, result *structs.PlanResult) {
+	// Hot path
+	if plan.Job == nil || len(plan.NodeAllocation) == 0 {
+		return
+	}
+
+	allOrNothing := make(map[string]struct{})
+	for _, tg := range plan.Job.TaskGroups {
+		if tg.IsAllOrNothing() {
+			allOrNothing[tg.Name] = struct{}{}
+		}
+	}
+	if len(allOrNothing) == 0 {
+		return
+	}
+
+	// Count the allocations of each all_or_nothing task group in the plan
+	// and in the result
+	planned := make(map[string]int)
+	for _, allocs := range plan.NodeAllocation {
+		for _, alloc := range allocs {
+			if _, ok := allOrNothing[alloc.TaskGroup]; ok {
+				planned[alloc.TaskGroup]++
+			}
+		}
+	}
+	placed := make(map[string]int)
+	for _, allocs := range result.NodeAllocation {
+		for _, alloc := range allocs {
+			if _, ok := allOrNothing[alloc.TaskGroup]; ok {
+				placed[alloc.TaskGroup]++
+			}
+		}
+	}
+
+	rejected := make(map[string]struct{})
+	for tg, n := range planned {
+		if placed[tg] < n {
+			rejected[tg] = struct{}{}
+		}
+	}
+	if len(rejected) == 0 {
+		return
+	}
+
+	// Remove the allocations of the rejected task groups, along with the
+	// allocations they replace or preempt
+	removed := make(map[string]struct{})
+	replaced := make(map[string]struct{})
+	for nodeID, allocs := range result.NodeAllocation {
+		var filtered []*structs.Allocation
+		for _, alloc := range allocs {
+			if _, ok := rejected[alloc.TaskGroup]; ok {
+				removed[alloc.ID] = struct{}{}
+				if alloc.PreviousAllocation != "" {
+					replaced[alloc.PreviousAllocation] = struct{}{}
+				}
+				continue
+			}
+			filtered = append(filtered, alloc)
+		}
+		if len(filtered) > 0 {
+			result.NodeAllocation[nodeID] = filtered
+		} else {
+			delete(result.NodeAllocation, nodeID)
+		}
+	}
+	for nodeID, updates := range result.NodeUpdate {
+		var filtered []*structs.Allocation
+		for _, alloc := range updates {
+			if _, ok := replaced[alloc.ID]; ok {
+				continue
+			}
+			if _, ok := rejected[alloc.TaskGroup]; ok {
+				continue
+			}
+			filtered = append(filtered, alloc)
+		}
+		if len(filtered) > 0 {
+			result.NodeUpdate[nodeID] = filtered
+		} else {
+			delete(result.NodeUpdate, nodeID)
+		}
+	}
+	for nodeID, preemptions := range result.NodePreemptions {
+		var filtered []*structs.Allocation
+		for _, alloc := range preemptions {
+			if _, ok := removed[alloc.PreemptedByAllocation]; !ok {
+				filtered = append(filtered, alloc)
+			}
+		}
+		if len(filtered) > 0 {
+			result.NodePreemptions[nodeID] = filtered
+		} else {
+			delete(result.NodePreemptions, nodeID)
+		}
+	}
+}
+
 // correctDeploymentCanaries ensures that the deployment object doesn't list any
 // canaries as placed if they didn't actually get placed. This could happen if
 // the plan had a partial commit.
@@ -646,6 +910,12 @@ func evaluateNodePlan(snap *state.StateSnapshot, plan *structs.Plan, nodeID stri
 	// the Raft commit happens.
 	if node == nil {
 		return false, "node does not exist", nil
+	} else if node.Status == structs.NodeStatusDisconnected {
+		// Allocations on a disconnected node can only be marked unknown.
+		if isValidForDisconnectedNode(plan, nodeID) {
+			return true, "", nil
+		}
+		return false, "node is disconnected and contains invalid updates", nil
 	} else if node.Status != structs.NodeStatusReady {
 		return false, "node is not ready for placements", nil
 	} else if node.SchedulingEligibility == structs.NodeSchedulingIneligible {
@@ -676,11 +946,30 @@ func evaluateNodePlan(snap *state.StateSnapshot, plan *structs.Plan, nodeID stri
 	proposed := structs.RemoveAllocs(existingAlloc, remove)
 	proposed = append(proposed, plan.NodeAllocation[nodeID]...)
 
+	// Hold the capacity of the reservations on the node that aren't claimed
+	reservations, err := snap.ReservationsByNode(ws, nodeID)
+	if err != nil {
+		return false, "", fmt.Errorf("failed to get reservations for '%s': %v", nodeID, err)
+	}
+	proposed = append(proposed, structs.ReservationAllocs(nodeID, reservations, proposed, plan.Job, time.Now())...)
+
 	// Check if these allocations fit
 	fit, reason, _, err := structs.AllocsFit(node, proposed, nil, true)
 	return fit, reason, err
 }
 
+// isValidForDisconnectedNode ensures that the plan only marks the
+// allocations of a disconnected node as unknown.
+func isValidForDisconnectedNode(plan *structs.Plan, nodeID string) bool {
+	for _, alloc := range plan.NodeAllocation[nodeID] {
+		if alloc.ClientStatus != structs.AllocClientStatusUnknown {
+			return false
+		}
+	}
+
+	return true
+}
+
 func max(a, b uint64) uint64 {
 	if a > b {
 		return a
@@ -236,6 +236,54 @@ func TestPlanApply_applyPlan(t *testing.T) {
 	assert.Equal(index, evalOut.ModifyIndex)
 }
 
+// TestPlanApply_applyPlan_SignIdentityFailure asserts that failing to sign
+// workload identities doesn't fail the plan, and that the allocations are
+// committed without them.
+func TestPlanApply_applyPlan_SignIdentityFailure(t *testing.T) {
+	t.Parallel()
+
+	s1, cleanupS1 := TestServer(t, nil)
+	defer cleanupS1()
+	testutil.WaitForLeader(t, s1.RPC)
+
+	node := mock.Node()
+	testRegisterNode(t, s1, node)
+
+	// Activate a root key whose material is missing from the keyring
+	keyMeta := structs.NewRootKeyMeta()
+	keyMeta.SetActive()
+	require.NoError(t, s1.State().UpsertRootKeyMeta(structs.MsgTypeTestSetup, 1000, keyMeta))
+
+	alloc := mock.Alloc()
+	require.NoError(t, s1.State().UpsertJobSummary(1001, mock.JobSummary(alloc.JobID)))
+	eval := mock.Eval()
+	eval.JobID = alloc.JobID
+	require.NoError(t, s1.State().UpsertEvals(structs.MsgTypeTestSetup, 1002, []*structs.Evaluation{eval}))
+
+	planRes := &structs.PlanResult{
+		NodeAllocation: map[string][]*structs.Allocation{
+			node.ID: {alloc},
+		},
+	}
+	plan := &structs.Plan{
+		Job:    alloc.Job,
+		EvalID: eval.ID,
+	}
+
+	snap, err := s1.State().Snapshot()
+	require.NoError(t, err)
+	future, err := s1.applyPlan(plan, planRes, snap)
+	require.NoError(t, err)
+	index, err := planWaitFuture(future)
+	require.NoError(t, err)
+	require.NotZero(t, index)
+
+	out, err := s1.fsm.State().AllocByID(nil, alloc.ID)
+	require.NoError(t, err)
+	require.NotNil(t, out)
+	require.Empty(t, out.SignedIdentities)
+}
+
 // Verifies that applyPlan properly updates the constituent objects in MemDB,
 // when the plan contains normalized allocs.
 func TestPlanApply_applyPlanWithNormalizedAllocs(t *testing.T) {
@@ -656,6 +704,132 @@ func TestPlanApply_EvalPlan_Partial_AllAtOnce(t *testing.T) {
 	}
 }
 
+func TestPlanApply_EvalPlan_Partial_AllOrNothing(t *testing.T) {
+	t.Parallel()
+	state := testStateStore(t)
+	node := mock.Node()
+	state.UpsertNode(structs.MsgTypeTestSetup, 1000, node)
+	node2 := mock.Node()
+	state.UpsertNode(structs.MsgTypeTestSetup, 1001, node2)
+	node3 := mock.Node()
+	state.UpsertNode(structs.MsgTypeTestSetup, 1002, node3)
+	snap, _ := state.Snapshot()
+
+	job := mock.BatchJob()
+	job.TaskGroups[0].Placement = structs.TaskGroupPlacementAllOrNothing
+
+	// alloc2 does not fit, so alloc must not be placed either
+	alloc := mock.BatchAlloc()
+	alloc.Job = job
+	alloc.TaskGroup = job.TaskGroups[0].Name
+	alloc2 := mock.BatchAlloc()
+	alloc2.Job = job
+	alloc2.TaskGroup = job.TaskGroups[0].Name
+	alloc2.AllocatedResources = structs.NodeResourcesToAllocatedResources(node2.NodeResources)
+
+	// alloc3 belongs to another task group and is placed
+	otherTG := job.TaskGroups[0].Copy()
+	otherTG.Name = "other"
+	otherTG.Placement = ""
+	job.TaskGroups = append(job.TaskGroups, otherTG)
+	alloc3 := mock.BatchAlloc()
+	alloc3.Job = job
+	alloc3.TaskGroup = otherTG.Name
+
+	plan := &structs.Plan{
+		Job: job,
+		NodeAllocation: map[string][]*structs.Allocation{
+			node.ID:  {alloc},
+			node2.ID: {alloc2},
+			node3.ID: {alloc3},
+		},
+	}
+
+	pool := NewEvaluatePool(workerPoolSize, workerPoolBufferSize)
+	defer pool.Shutdown()
+
+	result, err := evaluatePlan(pool, snap, plan, testlog.HCLogger(t))
+	require.NoError(t, err)
+	require.NotNil(t, result)
+
+	require.NotContains(t, result.NodeAllocation, node.ID)
+	require.NotContains(t, result.NodeAllocation, node2.ID)
+	require.Contains(t, result.NodeAllocation, node3.ID)
+	require.Equal(t, uint64(1002), result.RefreshIndex)
+}
+
+func TestPlanApply_EvalPlan_Partial_AllOrNothing_DestructiveUpdate(t *testing.T) {
+	t.Parallel()
+	state := testStateStore(t)
+	node := mock.Node()
+	node2 := mock.Node()
+	require.NoError(t, state.UpsertNode(structs.MsgTypeTestSetup, 1000, node))
+	require.NoError(t, state.UpsertNode(structs.MsgTypeTestSetup, 1001, node2))
+
+	job := mock.BatchJob()
+	job.TaskGroups[0].Placement = structs.TaskGroupPlacementAllOrNothing
+
+	// Two running allocations of the previous version of the job
+	var old []*structs.Allocation
+	for _, n := range []*structs.Node{node, node2} {
+		alloc := mock.BatchAlloc()
+		alloc.Job = job
+		alloc.JobID = job.ID
+		alloc.TaskGroup = job.TaskGroups[0].Name
+		alloc.NodeID = n.ID
+		alloc.ClientStatus = structs.AllocClientStatusRunning
+		old = append(old, alloc)
+	}
+	require.NoError(t, state.UpsertJobSummary(1002, mock.JobSummary(job.ID)))
+	require.NoError(t, state.UpsertAllocs(structs.MsgTypeTestSetup, 1003, old))
+	snap, err := state.Snapshot()
+	require.NoError(t, err)
+
+	// The destructive update replaces both; the replacement on node2 does
+	// not fit
+	var stops, replacements []*structs.Allocation
+	for _, alloc := range old {
+		stop := alloc.Copy()
+		stop.DesiredStatus = structs.AllocDesiredStatusStop
+		stops = append(stops, stop)
+
+		replacement := mock.BatchAlloc()
+		replacement.Job = job
+		replacement.JobID = job.ID
+		replacement.TaskGroup = job.TaskGroups[0].Name
+		replacement.NodeID = alloc.NodeID
+		replacement.PreviousAllocation = alloc.ID
+		replacements = append(replacements, replacement)
+	}
+	replacements[1].AllocatedResources = structs.NodeResourcesToAllocatedResources(node2.NodeResources)
+
+	plan := &structs.Plan{
+		Job: job,
+		NodeUpdate: map[string][]*structs.Allocation{
+			node.ID:  {stops[0]},
+			node2.ID: {stops[1]},
+		},
+		NodeAllocation: map[string][]*structs.Allocation{
+			node.ID:  {replacements[0]},
+			node2.ID: {replacements[1]},
+		},
+	}
+
+	pool := NewEvaluatePool(workerPoolSize, workerPoolBufferSize)
+	defer pool.Shutdown()
+
+	result, err := evaluatePlan(pool, snap, plan, testlog.HCLogger(t))
+	require.NoError(t, err)
+	require.NotNil(t, result)
+
+	// Neither the replacements nor the stops of the allocations they
+	// replace are applied, so the old allocations keep running
+	require.Empty(t, result.NodeAllocation)
+	require.Empty(t, result.NodeUpdate)
+	require.Empty(t, result.NodePreemptions)
+	require.NotZero(t, result.RefreshIndex)
+}
+
 func TestPlanApply_EvalNodePlan_Simple(t *testing.T) {
 	t.Parallel()
 	state := testStateStore(t)
@@ -711,6 +885,41 @@ func TestPlanApply_EvalNodePlan_NodeNotReady(t *testing.T) {
 	}
 }
 
+func TestPlanApply_EvalNodePlan_NodeDisconnected(t *testing.T) {
+	t.Parallel()
+	state := testStateStore(t)
+	node := mock.Node()
+	node.Status = structs.NodeStatusDisconnected
+	require.NoError(t, state.UpsertNode(structs.MsgTypeTestSetup, 1000, node))
+	snap, _ := state.Snapshot()
+
+	// Marking allocations unknown on a disconnected node is allowed
+	unknown := mock.Alloc()
+	unknown.NodeID = node.ID
+	unknown.ClientStatus = structs.AllocClientStatusUnknown
+	plan := &structs.Plan{
+		Job: unknown.Job,
+		NodeAllocation: map[string][]*structs.Allocation{
+			node.ID: {unknown},
+		},
+	}
+
+	fit, reason, err := evaluateNodePlan(snap, plan, node.ID)
+	require.NoError(t, err)
+	require.True(t, fit)
+	require.Empty(t, reason)
+
+	// Placing new allocations is not
+	alloc := mock.Alloc()
+	alloc.NodeID = node.ID
+	plan.NodeAllocation[node.ID] = append(plan.NodeAllocation[node.ID], alloc)
+
+	fit, reason, err = evaluateNodePlan(snap, plan, node.ID)
+	require.NoError(t, err)
+	require.False(t, fit)
+	require.Contains(t, reason, "disconnected")
+}
+
 func TestPlanApply_EvalNodePlan_NodeDrain(t *testing.T) {
 	t.Parallel()
 	state := testStateStore(t)
@@ -991,3 +1200,84 @@ func TestPlanApply_EvalNodePlan_NodeDown_EvictOnly(t *testing.T) {
 		t.Fatalf("bad")
 	}
 }
+
+// TestPlanApply_EvalNodePlan_Reservation asserts that placements of the plan,
+// which don't carry their job, claim the reservations of the plan's job.
+func TestPlanApply_EvalNodePlan_Reservation(t *testing.T) {
+	t.Parallel()
+	state := testStateStore(t)
+	node := mock.Node()
+	node.ReservedResources = nil
+	require.NoError(t, state.UpsertNode(structs.MsgTypeTestSetup, 1000, node))
+
+	// Hold all of the node's capacity with the reservation
+	reservation := mock.Reservation()
+	reservation.Resources = &structs.ReservationResources{
+		CPU:      int(node.NodeResources.Cpu.CpuShares),
+		MemoryMB: int(node.NodeResources.Memory.MemoryMB),
+	}
+	reservation.NodeIDs = []string{node.ID}
+	require.NoError(t, state.UpsertReservation(structs.MsgTypeTestSetup, 1001, reservation))
+
+	alloc := mock.Alloc()
+	alloc.NodeID = node.ID
+	job := alloc.Job
+	alloc.Job = nil
+	plan := &structs.Plan{
+		Job: job,
+		NodeAllocation: map[string][]*structs.Allocation{
+			node.ID: {alloc},
+		},
+	}
+
+	// The node is full for jobs not claiming the reservation
+	snap, err := state.Snapshot()
+	require.NoError(t, err)
+	fit, reason, err := evaluateNodePlan(snap, plan, node.ID)
+	require.NoError(t, err)
+	require.False(t, fit)
+	require.NotEmpty(t, reason)
+
+	// The placement claims the reservation of its job
+	job.Reservation = reservation.Name
+	fit, reason, err = evaluateNodePlan(snap, plan, node.ID)
+	require.NoError(t, err)
+	require.True(t, fit, reason)
+}
+
+func TestPlanApply_EvalReservationPlan(t *testing.T) {
+	t.Parallel()
+	state := testStateStore(t)
+	node := mock.Node()
+	node.ReservedResources = nil
+	require.NoError(t, state.UpsertNode(structs.MsgTypeTestSetup, 1000, node))
+
+	// Hold most of the node's CPU with another reservation
+	existing := mock.Reservation()
+	existing.Resources.CPU = int(node.NodeResources.Cpu.CpuShares) - 100
+	existing.NodeIDs = []string{node.ID}
+	require.NoError(t, state.UpsertReservation(structs.MsgTypeTestSetup, 1001, existing))
+
+	reservation := mock.Reservation()
+	reservation.NodeIDs = []string{node.ID}
+
+	snap, err := state.Snapshot()
+	require.NoError(t, err)
+	err = evaluateReservationPlan(snap, reservation)
+	require.Error(t, err)
+	require.Contains(t, err.Error(), "no longer fits")
+
+	// The reservation fits once the other one is deleted
+	require.NoError(t, state.DeleteReservation(structs.MsgTypeTestSetup, 1002, existing.Namespace, existing.Name))
+	snap, err = state.Snapshot()
+	require.NoError(t, err)
+	require.NoError(t, evaluateReservationPlan(snap, reservation))
+
+	// Reservations can't be created twice
+	require.NoError(t, state.UpsertReservation(structs.MsgTypeTestSetup, 1003, reservation.Copy()))
+	snap, err = state.Snapshot()
+	require.NoError(t, err)
+	err = evaluateReservationPlan(snap, reservation)
+	require.Error(t, err)
+	require.Contains(t, err.Error(), "already exists")
+}
@@ -0,0 +1,213 @@
+package nomad
+
+import (
+	"fmt"
+	"time"
+
+	metrics "github.com/armon/go-metrics"
+	log "github.com/hashicorp/go-hclog"
+	memdb "github.com/hashicorp/go-memdb"
+
+	"github.com/hashicorp/nomad/acl"
+	"github.com/hashicorp/nomad/helper"
+	"github.com/hashicorp/nomad/nomad/state"
+	"github.com/hashicorp/nomad/nomad/structs"
+	"github.com/hashicorp/nomad/scheduler"
+)
+
+// Reservation endpoint is used for manipulating capacity reservations.
+type Reservation struct {
+	srv    *Server
+	logger log.Logger
+}
+
+// Create places the instances of the given reservation on nodes and stores
+// it. Reservations are immutable, so creating a reservation that already
+// exists is an error.
+func (r *Reservation) Create(args *structs.ReservationUpsertRequest, reply *structs.SingleReservationResponse) error {
+	if done, err := r.srv.forward("Reservation.Create", args, args, reply); done {
+		return err
+	}
+	defer metrics.MeasureSince([]string{"nomad", "reservation", "create"}, time.Now())
+
+	if args.Reservation == nil {
+		return fmt.Errorf("missing reservation for creation")
+	}
+	reservation := args.Reservation
+	reservation.Namespace = args.RequestNamespace()
+	reservation.Canonicalize()
+
+	// Check namespace submit-job permissions
+	if aclObj, err := r.srv.ResolveToken(args.AuthToken); err != nil {
+		return err
+	} else if aclObj != nil && !aclObj.AllowNsOp(reservation.Namespace, acl.NamespaceCapabilitySubmitJob) {
+		return structs.ErrPermissionDenied
+	}
+
+	if err := reservation.Validate(); err != nil {
+		return fmt.Errorf("invalid reservation %q: %v", reservation.Name, err)
+	}
+
+	snap, err := r.srv.State().Snapshot()
+	if err != nil {
+		return err
+	}
+	existing, err := snap.ReservationByName(nil, reservation.Namespace, reservation.Name)
+	if err != nil {
+		return err
+	}
+	if existing != nil {
+		return fmt.Errorf("reservation %q already exists", reservation.Name)
+	}
+
+	// Place the instances against the current state. The plan applier
+	// accounts for them from then on, so they can't be overcommitted by later
+	// placements.
+	nodeIDs, err := scheduler.PlaceReservation(snap, reservation, r.logger)
+	if err != nil {
+		return fmt.Errorf("failed to place reservation %q: %v", reservation.Name, err)
+	}
+	reservation.NodeIDs = nodeIDs
+
+	snapIndex, err := snap.LatestIndex()
+	if err != nil {
+		return err
+	}
+
+	// Submit the reservation to the plan queue, which verifies the instances
+	// still fit against the plans applied since the snapshot was taken
+	// before committing it.
+	future, err := r.srv.planQueue.Enqueue(&structs.Plan{
+		Priority:      structs.JobDefaultPriority,
+		Reservation:   reservation,
+		SnapshotIndex: snapIndex,
+	})
+	if err != nil {
+		return err
+	}
+	result, err := future.Wait()
+	if err != nil {
+		return fmt.Errorf("failed to create reservation %q: %v", reservation.Name, err)
+	}
+	index := result.AllocIndex
+
+	reservation.CreateIndex = index
+	reservation.ModifyIndex = index
+	reply.Reservation = reservation
+	reply.Index = index
+	return nil
+}
+
+// List is used to list the reservations of a namespace.
+func (r *Reservation) List(args *structs.ReservationListRequest, reply *structs.ReservationListResponse) error {
+	if done, err := r.srv.forward("Reservation.List", args, args, reply); done {
+		return err
+	}
+	defer metrics.MeasureSince([]string{"nomad", "reservation", "list"}, time.Now())
+
+	// Check namespace read-job permissions
+	if aclObj, err := r.srv.ResolveToken(args.AuthToken); err != nil {
+		return err
+	} else if aclObj != nil && !aclObj.AllowNsOp(args.RequestNamespace(), acl.NamespaceCapabilityReadJob) {
+		return structs.ErrPermissionDenied
+	}
+
+	// Setup blocking query
+	opts := blockingOptions{
+		queryOpts: &args.QueryOptions,
+		queryMeta: &reply.QueryMeta,
+		run: func(ws memdb.WatchSet, store *state.StateStore) error {
+			iter, err := store.ReservationsByNamespace(ws, args.RequestNamespace(), args.Prefix)
+			if err != nil {
+				return err
+			}
+
+			reservations := []*structs.Reservation{}
+			for raw := iter.Next(); raw != nil; raw = iter.Next() {
+				reservations = append(reservations, raw.(*structs.Reservation))
+			}
+			reply.Reservations = reservations
+
+			// Use the last index that affected the reservations table
+			index, err := store.Index(state.TableReservations)
+			if err != nil {
+				return err
+			}
+			reply.Index = helper.Uint64Max(1, index)
+			return nil
+		}}
+	return r.srv.blockingRPC(&opts)
+}
+
+// GetReservation returns the specific reservation requested or nil if the
+// reservation doesn't exist.
+func (r *Reservation) GetReservation(args *structs.ReservationSpecificRequest, reply *structs.SingleReservationResponse) error {
+	if done, err := r.srv.forward("Reservation.GetReservation", args, args, reply); done {
+		return err
+	}
+	defer metrics.MeasureSince([]string{"nomad", "reservation", "get_reservation"}, time.Now())
+
+	// Check namespace read-job permissions
+	if aclObj, err := r.srv.ResolveToken(args.AuthToken); err != nil {
+		return err
+	} else if aclObj != nil && !aclObj.AllowNsOp(args.RequestNamespace(), acl.NamespaceCapabilityReadJob) {
+		return structs.ErrPermissionDenied
+	}
+
+	// Setup the blocking query
+	opts := blockingOptions{
+		queryOpts: &args.QueryOptions,
+		queryMeta: &reply.QueryMeta,
+		run: func(ws memdb.WatchSet, store *state.StateStore) error {
+			reservation, err := store.ReservationByName(ws, args.RequestNamespace(), args.Name)
+			if err != nil {
+				return err
+			}
+
+			reply.Reservation = reservation
+			if reservation != nil {
+				reply.Index = reservation.ModifyIndex
+			} else {
+				// Use the last index that affected the reservations table
+				index, err := store.Index(state.TableReservations)
+				if err != nil {
+					return err
+				}
+				reply.Index = helper.Uint64Max(1, index)
+			}
+			return nil
+		}}
+	return r.srv.blockingRPC(&opts)
+}
+
+// Delete deletes the given reservation, releasing the capacity its unclaimed
+// instances hold.
+func (r *Reservation) Delete(args *structs.ReservationDeleteRequest, reply *structs.GenericResponse) error {
+	if done, err := r.srv.forward("Reservation.Delete", args, args, reply); done {
+		return err
+	}
+	defer metrics.MeasureSince([]string{"nomad", "reservation", "delete"}, time.Now())
+
+	if args.Name == "" {
+		return fmt.Errorf("missing reservation name for deletion")
+	}
+
+	// Check namespace submit-job permissions
+	if aclObj, err := r.srv.ResolveToken(args.AuthToken); err != nil {
+		return err
+	} else if aclObj != nil && !aclObj.AllowNsOp(args.RequestNamespace(), acl.NamespaceCapabilitySubmitJob) {
+		return structs.ErrPermissionDenied
+	}
+
+	// Update via Raft
+	out, index, err := r.srv.raftApply(structs.ReservationDeleteRequestType, args)
+	if err != nil {
+		return err
+	}
+	if err, ok := out.(error); ok && err != nil {
+		return err
+	}
+
+	reply.Index = index
+	return nil
+}
@@ -0,0 +1,85 @@
+package nomad
+
+import (
+	"testing"
+
+	msgpackrpc "github.com/hashicorp/net-rpc-msgpackrpc"
+	"github.com/hashicorp/nomad/nomad/mock"
+	"github.com/hashicorp/nomad/nomad/structs"
+	"github.com/hashicorp/nomad/testutil"
+	"github.com/stretchr/testify/require"
+)
+
+func TestReservationEndpoint_CreateGetListDelete(t *testing.T) {
+	t.Parallel()
+	s1, cleanupS1 := TestServer(t, nil)
+	defer cleanupS1()
+	codec := rpcClient(t, s1)
+	testutil.WaitForLeader(t, s1.RPC)
+
+	node := mock.Node()
+	require.NoError(t, s1.State().UpsertNode(structs.MsgTypeTestSetup, 1000, node))
+
+	reservation := mock.Reservation()
+	reservation.Count = 2
+	createReq := &structs.ReservationUpsertRequest{
+		Reservation: reservation,
+		WriteRequest: structs.WriteRequest{
+			Region:    "global",
+			Namespace: structs.DefaultNamespace,
+		},
+	}
+	var createResp structs.SingleReservationResponse
+	require.NoError(t, msgpackrpc.CallWithCodec(codec, "Reservation.Create", createReq, &createResp))
+	require.NotZero(t, createResp.Index)
+	require.Equal(t, []string{node.ID, node.ID}, createResp.Reservation.NodeIDs)
+
+	// Reservations can't be recreated
+	err := msgpackrpc.CallWithCodec(codec, "Reservation.Create", createReq, &createResp)
+	require.Error(t, err)
+	require.Contains(t, err.Error(), "already exists")
+
+	// Reservations that don't fit are rejected
+	tooLarge := mock.Reservation()
+	tooLarge.Resources.CPU = 100000
+	createReq.Reservation = tooLarge
+	err = msgpackrpc.CallWithCodec(codec, "Reservation.Create", createReq, &createResp)
+	require.Error(t, err)
+	require.Contains(t, err.Error(), "failed to place reservation")
+
+	getReq := &structs.ReservationSpecificRequest{
+		Name: reservation.Name,
+		QueryOptions: structs.QueryOptions{
+			Region:    "global",
+			Namespace: structs.DefaultNamespace,
+		},
+	}
+	var getResp structs.SingleReservationResponse
+	require.NoError(t, msgpackrpc.CallWithCodec(codec, "Reservation.GetReservation", getReq, &getResp))
+	require.NotNil(t, getResp.Reservation)
+	require.Equal(t, []string{node.ID, node.ID}, getResp.Reservation.NodeIDs)
+
+	listReq := &structs.ReservationListRequest{
+		QueryOptions: structs.QueryOptions{
+			Region:    "global",
+			Namespace: structs.DefaultNamespace,
+		},
+	}
+	var listResp structs.ReservationListResponse
+	require.NoError(t, msgpackrpc.CallWithCodec(codec, "Reservation.List", listReq, &listResp))
+	require.Len(t, listResp.Reservations, 1)
+
+	deleteReq := &structs.ReservationDeleteRequest{
+		Name: reservation.Name,
+		WriteRequest: structs.WriteRequest{
+			Region:    "global",
+			Namespace: structs.DefaultNamespace,
+		},
+	}
+	var deleteResp structs.GenericResponse
+	require.NoError(t, msgpackrpc.CallWithCodec(codec, "Reservation.Delete", deleteReq, &deleteResp))
+
+	getResp = structs.SingleReservationResponse{}
+	require.NoError(t, msgpackrpc.CallWithCodec(codec, "Reservation.GetReservation", getReq, &getResp))
+	require.Nil(t, getResp.Reservation)
+}
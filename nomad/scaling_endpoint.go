@@ -42,21 +42,23 @@ func (p *Scaling) ListPolicies(args *structs.ScalingPolicyListRequest, reply *st
 			return structs.ErrPermissionDenied
 		}
 	}
+	sort := state.SortOption(args.Reverse)
 
 	// Setup the blocking query
 	opts := blockingOptions{
 		queryOpts: &args.QueryOptions,
 		queryMeta: &reply.QueryMeta,
-		run: func(ws memdb.WatchSet, state *state.StateStore) error {
-			// Iterate over all the policies
+		run: func(ws memdb.WatchSet, store *state.StateStore) error {
+			// Iterate over all the policies, in ID order so they can be
+			// paginated
 			var err error
 			var iter memdb.ResultIterator
 			if prefix := args.QueryOptions.Prefix; prefix != "" {
-				iter, err = state.ScalingPoliciesByIDPrefix(ws, args.RequestNamespace(), prefix)
+				iter, err = store.ScalingPoliciesByIDPrefix(ws, args.RequestNamespace(), prefix, sort)
 			} else if job := args.Job; job != "" {
-				iter, err = state.ScalingPoliciesByJob(ws, args.RequestNamespace(), job, args.Type)
+				iter, err = store.ScalingPoliciesByJobSorted(ws, args.RequestNamespace(), job, args.Type, sort)
 			} else {
-				iter, err = state.ScalingPoliciesByNamespace(ws, args.Namespace, args.Type)
+				iter, err = store.ScalingPoliciesByNamespace(ws, args.RequestNamespace(), args.Type, sort)
 			}
 
 			if err != nil {
@@ -65,13 +67,21 @@ func (p *Scaling) ListPolicies(args *structs.ScalingPolicyListRequest, reply *st
 
 			// Convert all the policies to a list stub
 			reply.Policies = nil
-			for raw := iter.Next(); raw != nil; raw = iter.Next() {
-				policy := raw.(*structs.ScalingPolicy)
-				reply.Policies = append(reply.Policies, policy.Stub())
+			paginator := state.NewPaginator(iter, state.IDTokenizer{}, args.QueryOptions,
+				func(raw interface{}) (bool, error) {
+					policy := raw.(*structs.ScalingPolicy)
+					reply.Policies = append(reply.Policies, policy.Stub())
+					return true, nil
+				})
+
+			nextToken, err := paginator.Page()
+			if err != nil {
+				return err
 			}
+			reply.QueryMeta.NextToken = nextToken
 
 			// Use the last index that affected the policy table
-			index, err := state.Index("scaling_policy")
+			index, err := store.Index("scaling_policy")
 			if err != nil {
 				return err
 			}
@@ -148,14 +158,15 @@ func (j *Scaling) listAllNamespaces(args *structs.ScalingPolicyListRequest, repl
 		return aclObj.AllowNsOp(ns, acl.NamespaceCapabilityListScalingPolicies) ||
 			(aclObj.AllowNsOp(ns, acl.NamespaceCapabilityListJobs) && aclObj.AllowNsOp(ns, acl.NamespaceCapabilityReadJob))
 	}
+	sort := state.SortOption(args.Reverse)
 
 	// Setup the blocking query
 	opts := blockingOptions{
 		queryOpts: &args.QueryOptions,
 		queryMeta: &reply.QueryMeta,
-		run: func(ws memdb.WatchSet, state *state.StateStore) error {
+		run: func(ws memdb.WatchSet, store *state.StateStore) error {
 			// check if user has permission to all namespaces
-			allowedNSes, err := allowedNSes(aclObj, state, allow)
+			allowedNSes, err := allowedNSes(aclObj, store, allow)
 			if err == structs.ErrPermissionDenied {
 				// return empty if token isn't authorized for any namespace
 				reply.Policies = []*structs.ScalingPolicyListStub{}
@@ -164,33 +175,39 @@ func (j *Scaling) listAllNamespaces(args *structs.ScalingPolicyListRequest, repl
 				return err
 			}
 
-			// Capture all the policies
-			var iter memdb.ResultIterator
-			if args.Type != "" {
-				iter, err = state.ScalingPoliciesByTypePrefix(ws, args.Type)
-			} else {
-				iter, err = state.ScalingPolicies(ws)
-			}
+			// Capture all the policies in ID order
+			iter, err := store.ScalingPolicies(ws, sort)
 			if err != nil {
 				return err
 			}
 
 			var policies []*structs.ScalingPolicyListStub
-			for raw := iter.Next(); raw != nil; raw = iter.Next() {
-				policy := raw.(*structs.ScalingPolicy)
-				if allowedNSes != nil && !allowedNSes[policy.Target[structs.ScalingTargetNamespace]] {
-					// not permitted to this name namespace
-					continue
-				}
-				if prefix != "" && !strings.HasPrefix(policy.ID, prefix) {
-					continue
-				}
-				policies = append(policies, policy.Stub())
+			paginator := state.NewPaginator(iter, state.IDTokenizer{}, args.QueryOptions,
+				func(raw interface{}) (bool, error) {
+					policy := raw.(*structs.ScalingPolicy)
+					if allowedNSes != nil && !allowedNSes[policy.Target[structs.ScalingTargetNamespace]] {
+						// not permitted to this name namespace
+						return false, nil
+					}
+					if prefix != "" && !strings.HasPrefix(policy.ID, prefix) {
+						return false, nil
+					}
+					if args.Type != "" && !strings.HasPrefix(policy.Type, args.Type) {
+						return false, nil
+					}
+					policies = append(policies, policy.Stub())
+					return true, nil
+				})
+
+			nextToken, err := paginator.Page()
+			if err != nil {
+				return err
 			}
+			reply.QueryMeta.NextToken = nextToken
 			reply.Policies = policies
 
 			// Use the last index that affected the policies table or summary
-			index, err := state.Index("scaling_policy")
+			index, err := store.Index("scaling_policy")
 			if err != nil {
 				return err
 			}
@@ -201,6 +201,17 @@ func TestScalingEndpoint_ListPolicies(t *testing.T) {
 			Type:     "horizontal",
 			Expected: []*structs.ScalingPolicy{j1polH},
 		},
+		{
+			Label:    "job and type prefix",
+			Job:      j1.ID,
+			Type:     "vertical",
+			Expected: []*structs.ScalingPolicy{j1polV},
+		},
+		{
+			Label:    "type prefix",
+			Type:     "vertical",
+			Expected: []*structs.ScalingPolicy{j1polV},
+		},
 	}
 
 	for _, tc := range cases {
@@ -188,6 +188,10 @@ type Server struct {
 	// capacity changes.
 	blockedEvals *BlockedEvals
 
+	// waitingEvals is used to manage evaluations that are waiting on the
+	// dependencies of their job.
+	waitingEvals *WaitingEvals
+
 	// deploymentWatcher is used to watch deployments and their allocations and
 	// make the required calls to continue to transition the deployment.
 	deploymentWatcher *deploymentwatcher.Watcher
@@ -244,6 +248,10 @@ type Server struct {
 	// Nomad router.
 	statsFetcher *StatsFetcher
 
+	// encrypter is the keyring used to encrypt and decrypt sensitive data
+	// with the cluster's root keys.
+	encrypter *Encrypter
+
 	// EnterpriseState is used to fill in state for Pro/Ent builds
 	EnterpriseState
 
@@ -278,6 +286,19 @@ type endpoints struct {
 	Event      *Event
 	Namespace  *Namespace
 
+	// ServiceRegistration is the endpoint for Nomad native service
+	// registrations.
+	ServiceRegistration *ServiceRegistration
+
+	// Variables is the endpoint for the encrypted variables store.
+	Variables *Variables
+
+	// NodePool is the endpoint for node pools.
+	NodePool *NodePool
+
+	// Reservation is the endpoint for capacity reservations.
+	Reservation *Reservation
+
 	// Client endpoints
 	ClientStats       *ClientStats
 	FileSystem        *FileSystem
@@ -363,12 +384,22 @@ func NewServer(config *Config, consulCatalog consul.CatalogAPI, consulConfigEntr
 	// Create the periodic dispatcher for launching periodic jobs.
 	s.periodicDispatcher = NewPeriodicDispatch(s.logger, s)
 
+	// Create the tracker of evaluations waiting on job dependencies
+	s.waitingEvals = NewWaitingEvals(s.raftApply, s.logger)
+
 	// Initialize the stats fetcher that autopilot will use.
 	s.statsFetcher = NewStatsFetcher(s.logger, s.connPool, s.config.Region)
 
 	// Setup Consul (more)
 	s.setupConsul(consulConfigEntries, consulACLs)
 
+	// Set up the keyring used to encrypt sensitive data
+	encrypter, err := NewEncrypter(s, keystorePathFor(s.config))
+	if err != nil {
+		return nil, err
+	}
+	s.encrypter = encrypter
+
 	// Setup Vault
 	if err := s.setupVaultClient(); err != nil {
 		s.Shutdown()
@@ -431,6 +462,9 @@ func NewServer(config *Config, consulCatalog consul.CatalogAPI, consulConfigEntr
 		return nil, err
 	}
 
+	// Replicate root keys from other servers
+	NewKeyringReplicator(s.shutdownCtx, s, s.encrypter)
+
 	// Monitor leadership changes
 	go s.monitorLeadership()
 
@@ -1155,6 +1189,10 @@ func (s *Server) setupRpcServer(server *rpc.Server, ctx *RPCContext) {
 		s.staticEndpoints.System = &System{srv: s, logger: s.logger.Named("system")}
 		s.staticEndpoints.Search = &Search{srv: s, logger: s.logger.Named("search")}
 		s.staticEndpoints.Namespace = &Namespace{srv: s}
+		s.staticEndpoints.ServiceRegistration = &ServiceRegistration{srv: s, logger: s.logger.Named("service_registration")}
+		s.staticEndpoints.Variables = &Variables{srv: s, logger: s.logger.Named("variables"), encrypter: s.encrypter}
+		s.staticEndpoints.NodePool = &NodePool{srv: s, logger: s.logger.Named("node_pool")}
+		s.staticEndpoints.Reservation = &Reservation{srv: s, logger: s.logger.Named("reservation")}
 		s.staticEndpoints.Enterprise = NewEnterpriseEndpoints(s)
 
 		// Client endpoints
@@ -1198,12 +1236,18 @@ func (s *Server) setupRpcServer(server *rpc.Server, ctx *RPCContext) {
 	server.Register(s.staticEndpoints.FileSystem)
 	server.Register(s.staticEndpoints.Agent)
 	server.Register(s.staticEndpoints.Namespace)
+	server.Register(s.staticEndpoints.ServiceRegistration)
+	server.Register(s.staticEndpoints.Variables)
+	server.Register(s.staticEndpoints.NodePool)
+	server.Register(s.staticEndpoints.Reservation)
 
 	// Create new dynamic endpoints and add them to the RPC server.
 	node := &Node{srv: s, ctx: ctx, logger: s.logger.Named("client")}
+	keyring := &Keyring{srv: s, ctx: ctx, logger: s.logger.Named("keyring"), encrypter: s.encrypter}
 
 	// Register the dynamic endpoints
 	server.Register(node)
+	server.Register(keyring)
 }
 
 // setupRaft is used to setup and initialize Raft
@@ -1222,10 +1266,12 @@ func (s *Server) setupRaft() error {
 		EvalBroker:        s.evalBroker,
 		Periodic:          s.periodicDispatcher,
 		Blocked:           s.blockedEvals,
+		Waiting:           s.waitingEvals,
 		Logger:            s.logger,
 		Region:            s.Region(),
 		EnableEventBroker: s.config.EnableEventBroker,
 		EventBufferSize:   s.config.EventBufferSize,
+		Encrypter:         s.encrypter,
 	}
 	var err error
 	s.fsm, err = NewFSM(fsmConfig)
@@ -0,0 +1,374 @@
+package nomad
+
+import (
+	"fmt"
+	"sort"
+	"time"
+
+	metrics "github.com/armon/go-metrics"
+	log "github.com/hashicorp/go-hclog"
+	memdb "github.com/hashicorp/go-memdb"
+	multierror "github.com/hashicorp/go-multierror"
+
+	"github.com/hashicorp/nomad/acl"
+	"github.com/hashicorp/nomad/helper"
+	"github.com/hashicorp/nomad/nomad/state"
+	"github.com/hashicorp/nomad/nomad/structs"
+)
+
+// ServiceRegistration encapsulates the service registrations RPC endpoint
+// which is callable via the ServiceRegistration RPCs and externally via the
+// "/v1/service{s}" HTTP API.
+type ServiceRegistration struct {
+	srv    *Server
+	logger log.Logger
+}
+
+// Upsert creates or updates service registrations held within Nomad. This RPC
+// is only callable by Nomad nodes.
+func (s *ServiceRegistration) Upsert(
+	args *structs.ServiceRegistrationUpsertRequest,
+	reply *structs.ServiceRegistrationUpsertResponse) error {
+
+	if done, err := s.srv.forward("ServiceRegistration.Upsert", args, args, reply); done {
+		return err
+	}
+	defer metrics.MeasureSince([]string{"nomad", "service_registration", "upsert"}, time.Now())
+
+	// This endpoint is only callable by nodes in the cluster. Therefore,
+	// perform a node lookup using the secret ID to confirm the caller is a
+	// known node. Requests without a token can't come from a node.
+	var node *structs.Node
+	if args.AuthToken != "" {
+		var err error
+		node, err = s.srv.fsm.State().NodeBySecretID(nil, args.AuthToken)
+		if err != nil {
+			return err
+		}
+	}
+	if s.srv.config.ACLEnabled && node == nil {
+		return structs.ErrPermissionDenied
+	}
+
+	// Use a multierror, so we can capture all validation errors and pass this
+	// back so they can be addressed by the caller in a single pass.
+	var mErr multierror.Error
+
+	for _, service := range args.Services {
+		if err := service.Validate(); err != nil {
+			mErr.Errors = append(mErr.Errors, err)
+			continue
+		}
+
+		// Nodes may only register services which are running on themselves.
+		if node != nil && service.NodeID != node.ID {
+			mErr.Errors = append(mErr.Errors,
+				fmt.Errorf("service %q node ID does not match calling node", service.ServiceName))
+		}
+	}
+	if err := mErr.ErrorOrNil(); err != nil {
+		return err
+	}
+
+	// Update via Raft.
+	_, index, err := s.srv.raftApply(structs.ServiceRegistrationUpsertRequestType, args)
+	if err != nil {
+		return err
+	}
+
+	// Update the index. There is no need to floor this as we are writing to
+	// state and therefore will get a non-zero index response.
+	reply.Index = index
+	return nil
+}
+
+// DeleteByID removes a single service registration, as specified by its ID
+// from Nomad. This is typically called by Nomad nodes, however, in extreme
+// situations can be used via the CLI and API by operators.
+func (s *ServiceRegistration) DeleteByID(
+	args *structs.ServiceRegistrationDeleteByIDRequest,
+	reply *structs.ServiceRegistrationDeleteByIDResponse) error {
+
+	if done, err := s.srv.forward("ServiceRegistration.DeleteByID", args, args, reply); done {
+		return err
+	}
+	defer metrics.MeasureSince([]string{"nomad", "service_registration", "delete_id"}, time.Now())
+
+	// Perform the ACL token resolution. If the caller does not hold a token
+	// with submit-job permissions, fall back to checking whether the caller
+	// is a node in the cluster.
+	if aclObj, err := s.srv.ResolveToken(args.AuthToken); err != nil {
+		// If ResolveToken had an unexpected error return that
+		if err != structs.ErrTokenNotFound {
+			return err
+		}
+
+		// Attempt to lookup AuthToken as a Node.SecretID since nodes call
+		// this endpoint and don't have an ACL token.
+		node, stateErr := s.srv.fsm.State().NodeBySecretID(nil, args.AuthToken)
+		if stateErr != nil {
+			// Return the original ResolveToken error with this err
+			var merr multierror.Error
+			merr.Errors = append(merr.Errors, err, stateErr)
+			return merr.ErrorOrNil()
+		}
+
+		// Not a node or a valid ACL token
+		if node == nil {
+			return structs.ErrTokenNotFound
+		}
+	} else if aclObj != nil && !aclObj.AllowNsOp(args.RequestNamespace(), acl.NamespaceCapabilitySubmitJob) {
+		return structs.ErrPermissionDenied
+	}
+
+	// Update via Raft.
+	_, index, err := s.srv.raftApply(structs.ServiceRegistrationDeleteByIDRequestType, args)
+	if err != nil {
+		return err
+	}
+
+	// Update the index. There is no need to floor this as we are writing to
+	// state and therefore will get a non-zero index response.
+	reply.Index = index
+	return nil
+}
+
+// List is used to list service registration held within state. It supports
+// single and wildcard namespace listings.
+func (s *ServiceRegistration) List(
+	args *structs.ServiceRegistrationListRequest,
+	reply *structs.ServiceRegistrationListResponse) error {
+
+	if done, err := s.srv.forward("ServiceRegistration.List", args, args, reply); done {
+		return err
+	}
+	defer metrics.MeasureSince([]string{"nomad", "service_registration", "list"}, time.Now())
+
+	// If the caller has requested to list services across all namespaces, use
+	// the custom function to perform this.
+	if args.RequestNamespace() == structs.AllNamespacesSentinel {
+		return s.listAllServiceRegistrations(args, reply)
+	}
+
+	// Check the caller has permission to read services within the requested
+	// namespace.
+	if aclObj, err := s.srv.ResolveToken(args.AuthToken); err != nil {
+		return err
+	} else if aclObj != nil && !aclObj.AllowNsOp(args.RequestNamespace(), acl.NamespaceCapabilityReadJob) {
+		return structs.ErrPermissionDenied
+	}
+
+	// Set up and return the blocking query.
+	return s.srv.blockingRPC(&blockingOptions{
+		queryOpts: &args.QueryOptions,
+		queryMeta: &reply.QueryMeta,
+		run: func(ws memdb.WatchSet, stateStore *state.StateStore) error {
+
+			// Perform the state query to get an iterator.
+			iter, err := stateStore.GetServiceRegistrationsByNamespace(ws, args.RequestNamespace())
+			if err != nil {
+				return err
+			}
+
+			// Track the unique tags found per service registration name.
+			serviceTags := make(map[string]map[string]struct{})
+
+			for raw := iter.Next(); raw != nil; raw = iter.Next() {
+				serviceReg := raw.(*structs.ServiceRegistration)
+				addServiceTags(serviceTags, serviceReg)
+			}
+
+			// Set the reply.
+			reply.Services = []*structs.ServiceRegistrationListStub{{
+				Namespace: args.RequestNamespace(),
+				Services:  serviceTagsToStubs(serviceTags),
+			}}
+
+			return s.setReplyQueryMeta(stateStore, &reply.QueryMeta)
+		},
+	})
+}
+
+// listAllServiceRegistrations is used to list service registration held
+// within state where the caller has used the namespace wildcard identifier.
+func (s *ServiceRegistration) listAllServiceRegistrations(
+	args *structs.ServiceRegistrationListRequest,
+	reply *structs.ServiceRegistrationListResponse) error {
+
+	// Perform token resolution. The request already goes through forwarding
+	// and metrics setup before being called.
+	aclObj, err := s.srv.ResolveToken(args.AuthToken)
+	if err != nil {
+		return err
+	}
+
+	// allowFunc checks whether the caller has the read-job capability on the
+	// passed namespace.
+	allowFunc := func(ns string) bool {
+		return aclObj.AllowNsOp(ns, acl.NamespaceCapabilityReadJob)
+	}
+
+	// Set up and return the blocking query.
+	return s.srv.blockingRPC(&blockingOptions{
+		queryOpts: &args.QueryOptions,
+		queryMeta: &reply.QueryMeta,
+		run: func(ws memdb.WatchSet, stateStore *state.StateStore) error {
+
+			// Identify which namespaces the caller has access to. If they do
+			// not have access to any, send them an empty response. Otherwise,
+			// handle any error in a traditional manner.
+			allowedNSes, err := allowedNSes(aclObj, stateStore, allowFunc)
+			switch err {
+			case structs.ErrPermissionDenied:
+				reply.Services = make([]*structs.ServiceRegistrationListStub, 0)
+				return nil
+			case nil:
+				// Fallthrough.
+			default:
+				return err
+			}
+
+			// Get all the service registrations stored within state.
+			iter, err := stateStore.GetServiceRegistrations(ws)
+			if err != nil {
+				return err
+			}
+
+			// Track the unique tags found per namespace per service
+			// registration name.
+			namespacedServices := make(map[string]map[string]map[string]struct{})
+
+			for raw := iter.Next(); raw != nil; raw = iter.Next() {
+				serviceReg := raw.(*structs.ServiceRegistration)
+
+				// Check whether the service registration is within a namespace
+				// the caller is permitted to view. nil allowedNSes means the
+				// caller can view all namespaces.
+				if allowedNSes != nil && !allowedNSes[serviceReg.Namespace] {
+					continue
+				}
+
+				if _, ok := namespacedServices[serviceReg.Namespace]; !ok {
+					namespacedServices[serviceReg.Namespace] = make(map[string]map[string]struct{})
+				}
+				addServiceTags(namespacedServices[serviceReg.Namespace], serviceReg)
+			}
+
+			// Build the reply, keeping the namespaces in a stable order.
+			namespaces := make([]string, 0, len(namespacedServices))
+			for ns := range namespacedServices {
+				namespaces = append(namespaces, ns)
+			}
+			sort.Strings(namespaces)
+
+			var stubs []*structs.ServiceRegistrationListStub
+			for _, ns := range namespaces {
+				stubs = append(stubs, &structs.ServiceRegistrationListStub{
+					Namespace: ns,
+					Services:  serviceTagsToStubs(namespacedServices[ns]),
+				})
+			}
+			reply.Services = stubs
+
+			return s.setReplyQueryMeta(stateStore, &reply.QueryMeta)
+		},
+	})
+}
+
+// GetService is used to get all services registrations corresponding to a
+// single name.
+func (s *ServiceRegistration) GetService(
+	args *structs.ServiceRegistrationByNameRequest,
+	reply *structs.ServiceRegistrationByNameResponse) error {
+
+	if done, err := s.srv.forward("ServiceRegistration.GetService", args, args, reply); done {
+		return err
+	}
+	defer metrics.MeasureSince([]string{"nomad", "service_registration", "get_service"}, time.Now())
+
+	// Check the caller has permission to read services within the requested
+	// namespace.
+	if aclObj, err := s.srv.ResolveToken(args.AuthToken); err != nil {
+		return err
+	} else if aclObj != nil && !aclObj.AllowNsOp(args.RequestNamespace(), acl.NamespaceCapabilityReadJob) {
+		return structs.ErrPermissionDenied
+	}
+
+	// Set up the blocking query.
+	return s.srv.blockingRPC(&blockingOptions{
+		queryOpts: &args.QueryOptions,
+		queryMeta: &reply.QueryMeta,
+		run: func(ws memdb.WatchSet, stateStore *state.StateStore) error {
+
+			// Perform the state query to get an iterator.
+			iter, err := stateStore.GetServiceRegistrationByName(ws, args.RequestNamespace(), args.ServiceName)
+			if err != nil {
+				return err
+			}
+
+			// Set up our output after we have checked the error.
+			services := make([]*structs.ServiceRegistration, 0)
+
+			// Iterate the iterator, appending all service registrations
+			// returned to the reply.
+			for raw := iter.Next(); raw != nil; raw = iter.Next() {
+				services = append(services, raw.(*structs.ServiceRegistration))
+			}
+			reply.Services = services
+
+			return s.setReplyQueryMeta(stateStore, &reply.QueryMeta)
+		},
+	})
+}
+
+// setReplyQueryMeta is an RPC helper function to set the QueryMeta fields
+// using the service registrations table index.
+func (s *ServiceRegistration) setReplyQueryMeta(stateStore *state.StateStore, queryMeta *structs.QueryMeta) error {
+
+	// Use the index table to populate the query meta as we have no way of
+	// tracking the max index on deletes.
+	index, err := stateStore.Index(state.TableServiceRegistrations)
+	if err != nil {
+		return err
+	}
+	queryMeta.Index = helper.Uint64Max(1, index)
+
+	// Set the query response.
+	s.srv.setQueryMeta(queryMeta)
+	return nil
+}
+
+// addServiceTags records the tags of the passed service registration against
+// its service name within the tracking map.
+func addServiceTags(tracker map[string]map[string]struct{}, serviceReg *structs.ServiceRegistration) {
+	if _, ok := tracker[serviceReg.ServiceName]; !ok {
+		tracker[serviceReg.ServiceName] = make(map[string]struct{})
+	}
+	for _, tag := range serviceReg.Tags {
+		tracker[serviceReg.ServiceName][tag] = struct{}{}
+	}
+}
+
+// serviceTagsToStubs converts the tracking map of service names and their
+// tags into a sorted list of stubs.
+func serviceTagsToStubs(tracker map[string]map[string]struct{}) []*structs.ServiceRegistrationStub {
+	names := make([]string, 0, len(tracker))
+	for name := range tracker {
+		names = append(names, name)
+	}
+	sort.Strings(names)
+
+	stubs := make([]*structs.ServiceRegistrationStub, 0, len(names))
+	for _, name := range names {
+		tags := make([]string, 0, len(tracker[name]))
+		for tag := range tracker[name] {
+			tags = append(tags, tag)
+		}
+		sort.Strings(tags)
+		stubs = append(stubs, &structs.ServiceRegistrationStub{
+			ServiceName: name,
+			Tags:        tags,
+		})
+	}
+	return stubs
+}
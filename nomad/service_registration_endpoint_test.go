@@ -0,0 +1,161 @@
+package nomad
+
+import (
+	"testing"
+
+	msgpackrpc "github.com/hashicorp/net-rpc-msgpackrpc"
+	"github.com/hashicorp/nomad/acl"
+	"github.com/hashicorp/nomad/nomad/mock"
+	"github.com/hashicorp/nomad/nomad/structs"
+	"github.com/hashicorp/nomad/testutil"
+	"github.com/stretchr/testify/require"
+)
+
+func TestServiceRegistration_Upsert(t *testing.T) {
+	t.Parallel()
+	s1, _, cleanupS1 := TestACLServer(t, nil)
+	defer cleanupS1()
+	codec := rpcClient(t, s1)
+	testutil.WaitForLeader(t, s1.RPC)
+
+	// Register a node which will own the service registrations.
+	node := mock.Node()
+	require.NoError(t, s1.fsm.State().UpsertNode(structs.MsgTypeTestSetup, 10, node))
+
+	services := mock.ServiceRegistrations()
+	for _, service := range services {
+		service.NodeID = node.ID
+	}
+
+	// Attempt the upsert without a node secret, which should fail.
+	serviceRegReq := &structs.ServiceRegistrationUpsertRequest{
+		Services: services,
+		WriteRequest: structs.WriteRequest{
+			Region:    DefaultRegion,
+			Namespace: "default",
+		},
+	}
+	var serviceRegResp structs.ServiceRegistrationUpsertResponse
+	err := msgpackrpc.CallWithCodec(codec, "ServiceRegistration.Upsert", serviceRegReq, &serviceRegResp)
+	require.EqualError(t, err, structs.ErrPermissionDenied.Error())
+
+	// Attempt the upsert using the node secret ID.
+	serviceRegReq.AuthToken = node.SecretID
+	err = msgpackrpc.CallWithCodec(codec, "ServiceRegistration.Upsert", serviceRegReq, &serviceRegResp)
+	require.NoError(t, err)
+	require.Greater(t, serviceRegResp.Index, uint64(1))
+
+	// A node attempting to register services of another node should fail.
+	otherNode := mock.Node()
+	require.NoError(t, s1.fsm.State().UpsertNode(structs.MsgTypeTestSetup, 20, otherNode))
+	serviceRegReq.AuthToken = otherNode.SecretID
+	err = msgpackrpc.CallWithCodec(codec, "ServiceRegistration.Upsert", serviceRegReq, &serviceRegResp)
+	require.Error(t, err)
+	require.Contains(t, err.Error(), "node ID does not match calling node")
+}
+
+func TestServiceRegistration_DeleteByID(t *testing.T) {
+	t.Parallel()
+	s1, rootToken, cleanupS1 := TestACLServer(t, nil)
+	defer cleanupS1()
+	codec := rpcClient(t, s1)
+	testutil.WaitForLeader(t, s1.RPC)
+
+	// Generate and upsert some service registrations.
+	services := mock.ServiceRegistrations()
+	require.NoError(t, s1.fsm.State().UpsertServiceRegistrations(structs.MsgTypeTestSetup, 10, services))
+
+	// Create a token which cannot submit jobs in the default namespace.
+	policy := mock.NamespacePolicy(structs.DefaultNamespace, "", []string{acl.NamespaceCapabilityReadJob})
+	readToken := mock.CreatePolicyAndToken(t, s1.fsm.State(), 20, "test-read-job", policy)
+
+	serviceRegReq := &structs.ServiceRegistrationDeleteByIDRequest{
+		ID: services[0].ID,
+		WriteRequest: structs.WriteRequest{
+			Region:    DefaultRegion,
+			Namespace: services[0].Namespace,
+			AuthToken: readToken.SecretID,
+		},
+	}
+	var serviceRegResp structs.ServiceRegistrationDeleteByIDResponse
+	err := msgpackrpc.CallWithCodec(codec, "ServiceRegistration.DeleteByID", serviceRegReq, &serviceRegResp)
+	require.EqualError(t, err, structs.ErrPermissionDenied.Error())
+
+	// Use the root token to perform the deletion.
+	serviceRegReq.AuthToken = rootToken.SecretID
+	err = msgpackrpc.CallWithCodec(codec, "ServiceRegistration.DeleteByID", serviceRegReq, &serviceRegResp)
+	require.NoError(t, err)
+
+	out, err := s1.fsm.State().GetServiceRegistrationByID(nil, services[0].Namespace, services[0].ID)
+	require.NoError(t, err)
+	require.Nil(t, out)
+}
+
+func TestServiceRegistration_List(t *testing.T) {
+	t.Parallel()
+	s1, cleanupS1 := TestServer(t, nil)
+	defer cleanupS1()
+	codec := rpcClient(t, s1)
+	testutil.WaitForLeader(t, s1.RPC)
+
+	// Generate and upsert some service registrations.
+	services := mock.ServiceRegistrations()
+	require.NoError(t, s1.fsm.State().UpsertServiceRegistrations(structs.MsgTypeTestSetup, 10, services))
+
+	// List the services in the default namespace.
+	serviceRegReq := &structs.ServiceRegistrationListRequest{
+		QueryOptions: structs.QueryOptions{
+			Namespace: structs.DefaultNamespace,
+			Region:    DefaultRegion,
+		},
+	}
+	var serviceRegResp structs.ServiceRegistrationListResponse
+	err := msgpackrpc.CallWithCodec(codec, "ServiceRegistration.List", serviceRegReq, &serviceRegResp)
+	require.NoError(t, err)
+	require.Equal(t, uint64(10), serviceRegResp.Index)
+	require.ElementsMatch(t, []*structs.ServiceRegistrationListStub{
+		{
+			Namespace: structs.DefaultNamespace,
+			Services: []*structs.ServiceRegistrationStub{
+				{
+					ServiceName: "example-cache",
+					Tags:        []string{"foo"},
+				},
+			},
+		},
+	}, serviceRegResp.Services)
+
+	// List the services across all namespaces.
+	serviceRegReq.Namespace = structs.AllNamespacesSentinel
+	err = msgpackrpc.CallWithCodec(codec, "ServiceRegistration.List", serviceRegReq, &serviceRegResp)
+	require.NoError(t, err)
+	require.Len(t, serviceRegResp.Services, 2)
+	require.Equal(t, structs.DefaultNamespace, serviceRegResp.Services[0].Namespace)
+	require.Equal(t, "platform", serviceRegResp.Services[1].Namespace)
+}
+
+func TestServiceRegistration_GetService(t *testing.T) {
+	t.Parallel()
+	s1, cleanupS1 := TestServer(t, nil)
+	defer cleanupS1()
+	codec := rpcClient(t, s1)
+	testutil.WaitForLeader(t, s1.RPC)
+
+	// Generate and upsert some service registrations.
+	services := mock.ServiceRegistrations()
+	require.NoError(t, s1.fsm.State().UpsertServiceRegistrations(structs.MsgTypeTestSetup, 10, services))
+
+	serviceRegReq := &structs.ServiceRegistrationByNameRequest{
+		ServiceName: services[1].ServiceName,
+		QueryOptions: structs.QueryOptions{
+			Namespace: services[1].Namespace,
+			Region:    DefaultRegion,
+		},
+	}
+	var serviceRegResp structs.ServiceRegistrationByNameResponse
+	err := msgpackrpc.CallWithCodec(codec, "ServiceRegistration.GetService", serviceRegReq, &serviceRegResp)
+	require.NoError(t, err)
+	require.Equal(t, uint64(10), serviceRegResp.Index)
+	require.Len(t, serviceRegResp.Services, 1)
+	require.Equal(t, services[1].ID, serviceRegResp.Services[0].ID)
+}
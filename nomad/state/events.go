@@ -6,28 +6,31 @@ import (
 )
 
 var MsgTypeEvents = map[structs.MessageType]string{
-	structs.NodeRegisterRequestType:                 structs.TypeNodeRegistration,
-	structs.NodeDeregisterRequestType:               structs.TypeNodeDeregistration,
-	structs.UpsertNodeEventsType:                    structs.TypeNodeEvent,
-	structs.EvalUpdateRequestType:                   structs.TypeEvalUpdated,
-	structs.AllocClientUpdateRequestType:            structs.TypeAllocationUpdated,
-	structs.JobRegisterRequestType:                  structs.TypeJobRegistered,
-	structs.AllocUpdateRequestType:                  structs.TypeAllocationUpdated,
-	structs.NodeUpdateStatusRequestType:             structs.TypeNodeEvent,
-	structs.JobDeregisterRequestType:                structs.TypeJobDeregistered,
-	structs.JobBatchDeregisterRequestType:           structs.TypeJobBatchDeregistered,
-	structs.AllocUpdateDesiredTransitionRequestType: structs.TypeAllocationUpdateDesiredStatus,
-	structs.NodeUpdateEligibilityRequestType:        structs.TypeNodeDrain,
-	structs.NodeUpdateDrainRequestType:              structs.TypeNodeDrain,
-	structs.BatchNodeUpdateDrainRequestType:         structs.TypeNodeDrain,
-	structs.DeploymentStatusUpdateRequestType:       structs.TypeDeploymentUpdate,
-	structs.DeploymentPromoteRequestType:            structs.TypeDeploymentPromotion,
-	structs.DeploymentAllocHealthRequestType:        structs.TypeDeploymentAllocHealth,
-	structs.ApplyPlanResultsRequestType:             structs.TypePlanResult,
-	structs.ACLTokenDeleteRequestType:               structs.TypeACLTokenDeleted,
-	structs.ACLTokenUpsertRequestType:               structs.TypeACLTokenUpserted,
-	structs.ACLPolicyDeleteRequestType:              structs.TypeACLPolicyDeleted,
-	structs.ACLPolicyUpsertRequestType:              structs.TypeACLPolicyUpserted,
+	structs.NodeRegisterRequestType:                      structs.TypeNodeRegistration,
+	structs.NodeDeregisterRequestType:                    structs.TypeNodeDeregistration,
+	structs.UpsertNodeEventsType:                         structs.TypeNodeEvent,
+	structs.EvalUpdateRequestType:                        structs.TypeEvalUpdated,
+	structs.AllocClientUpdateRequestType:                 structs.TypeAllocationUpdated,
+	structs.JobRegisterRequestType:                       structs.TypeJobRegistered,
+	structs.AllocUpdateRequestType:                       structs.TypeAllocationUpdated,
+	structs.NodeUpdateStatusRequestType:                  structs.TypeNodeEvent,
+	structs.JobDeregisterRequestType:                     structs.TypeJobDeregistered,
+	structs.JobBatchDeregisterRequestType:                structs.TypeJobBatchDeregistered,
+	structs.AllocUpdateDesiredTransitionRequestType:      structs.TypeAllocationUpdateDesiredStatus,
+	structs.NodeUpdateEligibilityRequestType:             structs.TypeNodeDrain,
+	structs.NodeUpdateDrainRequestType:                   structs.TypeNodeDrain,
+	structs.BatchNodeUpdateDrainRequestType:              structs.TypeNodeDrain,
+	structs.DeploymentStatusUpdateRequestType:            structs.TypeDeploymentUpdate,
+	structs.DeploymentPromoteRequestType:                 structs.TypeDeploymentPromotion,
+	structs.DeploymentAllocHealthRequestType:             structs.TypeDeploymentAllocHealth,
+	structs.ApplyPlanResultsRequestType:                  structs.TypePlanResult,
+	structs.ACLTokenDeleteRequestType:                    structs.TypeACLTokenDeleted,
+	structs.ACLTokenUpsertRequestType:                    structs.TypeACLTokenUpserted,
+	structs.ACLPolicyDeleteRequestType:                   structs.TypeACLPolicyDeleted,
+	structs.ACLPolicyUpsertRequestType:                   structs.TypeACLPolicyUpserted,
+	structs.ServiceRegistrationUpsertRequestType:         structs.TypeServiceRegistration,
+	structs.ServiceRegistrationDeleteByIDRequestType:     structs.TypeServiceDeregistration,
+	structs.ServiceRegistrationDeleteByNodeIDRequestType: structs.TypeServiceDeregistration,
 }
 
 func eventsFromChanges(tx ReadTxn, changes Changes) *structs.Events {
@@ -39,7 +42,12 @@ func eventsFromChanges(tx ReadTxn, changes Changes) *structs.Events {
 	var events []structs.Event
 	for _, change := range changes.Changes {
 		if event, ok := eventFromChange(change); ok {
-			event.Type = eventType
+			// Service registrations can be removed as a side effect of
+			// other writes, such as allocation and node updates, and so
+			// set their own type.
+			if event.Type == "" {
+				event.Type = eventType
+			}
 			event.Index = changes.Index
 			events = append(events, event)
 		}
@@ -88,6 +96,24 @@ func eventFromChange(change memdb.Change) (structs.Event, bool) {
 					Node: before,
 				},
 			}, true
+		case TableServiceRegistrations:
+			before, ok := change.Before.(*structs.ServiceRegistration)
+			if !ok {
+				return structs.Event{}, false
+			}
+			return structs.Event{
+				Topic:     structs.TopicService,
+				Type:      structs.TypeServiceDeregistration,
+				Key:       before.ID,
+				Namespace: before.Namespace,
+				FilterKeys: []string{
+					before.JobID,
+					before.ServiceName,
+				},
+				Payload: &structs.ServiceRegistrationStreamEvent{
+					Service: before,
+				},
+			}, true
 		}
 		return structs.Event{}, false
 	}
@@ -198,6 +224,24 @@ func eventFromChange(change memdb.Change) (structs.Event, bool) {
 				Deployment: after,
 			},
 		}, true
+	case TableServiceRegistrations:
+		after, ok := change.After.(*structs.ServiceRegistration)
+		if !ok {
+			return structs.Event{}, false
+		}
+		return structs.Event{
+			Topic:     structs.TopicService,
+			Type:      structs.TypeServiceRegistration,
+			Key:       after.ID,
+			Namespace: after.Namespace,
+			FilterKeys: []string{
+				after.JobID,
+				after.ServiceName,
+			},
+			Payload: &structs.ServiceRegistrationStreamEvent{
+				Service: after,
+			},
+		}, true
 	}
 
 	return structs.Event{}, false
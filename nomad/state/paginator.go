@@ -0,0 +1,184 @@
+package state
+
+import (
+	"strings"
+
+	memdb "github.com/hashicorp/go-memdb"
+	"github.com/hashicorp/nomad/nomad/structs"
+)
+
+// SortOption represents how the results of a list query are sorted.
+type SortOption bool
+
+const (
+	// SortDefault returns results in the iteration order of the index.
+	SortDefault SortOption = false
+
+	// SortReverse returns results in the reverse iteration order of the
+	// index.
+	SortReverse SortOption = true
+)
+
+// getSorted returns an iterator over the table's index in the requested
+// order.
+func getSorted(txn *txn, sort SortOption, table, index string, args ...interface{}) (memdb.ResultIterator, error) {
+	if sort == SortReverse {
+		return txn.GetReverse(table, index, args...)
+	}
+	return txn.Get(table, index, args...)
+}
+
+// Iterator is the interface that must be implemented to use the Paginator.
+type Iterator interface {
+	// Next returns the next element to be considered for pagination, or nil
+	// if there are no more elements.
+	Next() interface{}
+}
+
+// Tokenizer returns the pagination tokens of objects, and defines how they
+// are ordered. The order must match the order of the iterator the
+// Paginator is built on.
+type Tokenizer interface {
+	// GetToken returns the pagination token of the object.
+	GetToken(raw interface{}) string
+
+	// CompareTokens returns an integer comparing two tokens in iteration
+	// order. The result is 0 if a == b, -1 if a < b, and +1 if a > b.
+	CompareTokens(a, b string) int
+}
+
+// idGetter is implemented by objects which can be paginated by ID.
+type idGetter interface {
+	GetID() string
+}
+
+// namespaceGetter is implemented by namespaced objects which can be
+// paginated across namespaces.
+type namespaceGetter interface {
+	GetNamespace() string
+}
+
+// IDTokenizer tokenizes objects by their ID, for iterators over indexes
+// which are ordered by ID.
+type IDTokenizer struct{}
+
+func (IDTokenizer) GetToken(raw interface{}) string {
+	return raw.(idGetter).GetID()
+}
+
+func (IDTokenizer) CompareTokens(a, b string) int {
+	return strings.Compare(a, b)
+}
+
+// nameGetter is implemented by objects which are identified by name, such
+// as node pools.
+type nameGetter interface {
+	GetName() string
+}
+
+// NameTokenizer tokenizes objects by their name, for iterators over indexes
+// which are ordered by name.
+type NameTokenizer struct{}
+
+func (NameTokenizer) GetToken(raw interface{}) string {
+	return raw.(nameGetter).GetName()
+}
+
+func (NameTokenizer) CompareTokens(a, b string) int {
+	return strings.Compare(a, b)
+}
+
+// NamespaceIDTokenizer tokenizes objects by their namespace and ID, for
+// iterators over indexes which are ordered by namespace and then ID. Tokens
+// are of the form "<namespace>.<id>"; namespaces cannot contain periods.
+type NamespaceIDTokenizer struct{}
+
+func (NamespaceIDTokenizer) GetToken(raw interface{}) string {
+	return raw.(namespaceGetter).GetNamespace() + "." + raw.(idGetter).GetID()
+}
+
+func (NamespaceIDTokenizer) CompareTokens(a, b string) int {
+	aNS, aID := splitNamespaceToken(a)
+	bNS, bID := splitNamespaceToken(b)
+	if c := strings.Compare(aNS, bNS); c != 0 {
+		return c
+	}
+	return strings.Compare(aID, bID)
+}
+
+func splitNamespaceToken(token string) (string, string) {
+	parts := strings.SplitN(token, ".", 2)
+	if len(parts) != 2 {
+		return parts[0], ""
+	}
+	return parts[0], parts[1]
+}
+
+// Paginator wraps an iterator and returns only the objects of the requested
+// page. Pages start at the object with the requested token, or the first
+// object that sorts after it if that object no longer exists.
+type Paginator struct {
+	iter         Iterator
+	tokenizer    Tokenizer
+	perPage      int32
+	seekingToken string
+	reverse      bool
+	itemCount    int32
+
+	// appendFunc is called with each object of the page, and returns
+	// whether the object was included in the page. Objects excluded by a
+	// filter do not count towards the size of the page.
+	appendFunc func(interface{}) (bool, error)
+}
+
+// NewPaginator returns a Paginator over the iterator for the pagination
+// options of the query. The iterator must have been created with the
+// SortOption of the query.
+func NewPaginator(iter Iterator, tokenizer Tokenizer, opts structs.QueryOptions,
+	appendFunc func(interface{}) (bool, error)) *Paginator {
+
+	return &Paginator{
+		iter:         iter,
+		tokenizer:    tokenizer,
+		perPage:      opts.PerPage,
+		seekingToken: opts.NextToken,
+		reverse:      opts.Reverse,
+		appendFunc:   appendFunc,
+	}
+}
+
+// Page appends the objects of the page, and returns the token of the first
+// object of the next page. The token is empty if this is the last page.
+func (p *Paginator) Page() (string, error) {
+	for raw := p.iter.Next(); raw != nil; raw = p.iter.Next() {
+		token := p.tokenizer.GetToken(raw)
+		if p.seeking(token) {
+			continue
+		}
+
+		if p.perPage > 0 && p.itemCount >= p.perPage {
+			return token, nil
+		}
+
+		included, err := p.appendFunc(raw)
+		if err != nil {
+			return "", err
+		}
+		if included {
+			p.itemCount++
+		}
+	}
+	return "", nil
+}
+
+// seeking returns whether the token sorts before the start of the page.
+func (p *Paginator) seeking(token string) bool {
+	if p.seekingToken == "" {
+		return false
+	}
+	cmp := p.tokenizer.CompareTokens(token, p.seekingToken)
+	if p.reverse {
+		return cmp > 0
+	}
+	return cmp < 0
+}
@@ -0,0 +1,160 @@
+package state
+
+import (
+	"errors"
+	"testing"
+
+	"github.com/hashicorp/nomad/nomad/structs"
+	"github.com/stretchr/testify/require"
+)
+
+func TestPaginator(t *testing.T) {
+	t.Parallel()
+	ids := []string{"0", "1", "2", "3", "4"}
+
+	cases := []struct {
+		name              string
+		perPage           int32
+		nextToken         string
+		reverse           bool
+		filtered          string
+		expected          []string
+		expectedNextToken string
+		expectedError     string
+	}{
+		{
+			name:              "size-3 page-1",
+			perPage:           3,
+			expected:          []string{"0", "1", "2"},
+			expectedNextToken: "3",
+		},
+		{
+			name:     "size-5 page-1 stop before next",
+			perPage:  5,
+			expected: []string{"0", "1", "2", "3", "4"},
+		},
+		{
+			name:      "size-3 page-2 stop before next",
+			perPage:   3,
+			nextToken: "3",
+			expected:  []string{"3", "4"},
+		},
+		{
+			name:      "missing token starts at the next object",
+			perPage:   2,
+			nextToken: "25",
+			expected:  []string{"3", "4"},
+		},
+		{
+			name:     "no pagination",
+			expected: []string{"0", "1", "2", "3", "4"},
+		},
+		{
+			name:              "reverse size-3 page-1",
+			perPage:           3,
+			reverse:           true,
+			expected:          []string{"4", "3", "2"},
+			expectedNextToken: "1",
+		},
+		{
+			name:      "reverse size-3 page-2",
+			perPage:   3,
+			nextToken: "1",
+			reverse:   true,
+			expected:  []string{"1", "0"},
+		},
+		{
+			name:              "filtered objects are not counted",
+			perPage:           2,
+			filtered:          "1",
+			expected:          []string{"0", "2"},
+			expectedNextToken: "3",
+		},
+		{
+			name:          "error during append",
+			expectedError: "failed to append",
+		},
+	}
+
+	for _, tc := range cases {
+		t.Run(tc.name, func(t *testing.T) {
+			iterIDs := ids
+			if tc.reverse {
+				iterIDs = []string{"4", "3", "2", "1", "0"}
+			}
+			iter := newTestIterator(iterIDs)
+
+			results := []string{}
+			opts := structs.QueryOptions{
+				PerPage:   tc.perPage,
+				NextToken: tc.nextToken,
+				Reverse:   tc.reverse,
+			}
+			paginator := NewPaginator(iter, IDTokenizer{}, opts,
+				func(raw interface{}) (bool, error) {
+					if tc.expectedError != "" {
+						return false, errors.New(tc.expectedError)
+					}
+
+					id := raw.(*mockObject).GetID()
+					if id == tc.filtered {
+						return false, nil
+					}
+					results = append(results, id)
+					return true, nil
+				})
+
+			nextToken, err := paginator.Page()
+			if tc.expectedError != "" {
+				require.EqualError(t, err, tc.expectedError)
+				return
+			}
+			require.NoError(t, err)
+			require.Equal(t, tc.expected, results)
+			require.Equal(t, tc.expectedNextToken, nextToken)
+		})
+	}
+}
+
+func TestNamespaceIDTokenizer(t *testing.T) {
+	t.Parallel()
+
+	tokenizer := NamespaceIDTokenizer{}
+	job := &structs.Job{Namespace: "default", ID: "web.v1"}
+	require.Equal(t, "default.web.v1", tokenizer.GetToken(job))
+
+	require.Equal(t, 0, tokenizer.CompareTokens("default.web.v1", "default.web.v1"))
+	require.Equal(t, -1, tokenizer.CompareTokens("default.web", "prod.api"))
+	require.Equal(t, 1, tokenizer.CompareTokens("prod.api", "default.web"))
+	require.Equal(t, -1, tokenizer.CompareTokens("default.api", "default.web"))
+}
+
+// testIterator is an iterator over a fixed list of objects.
+type testIterator struct {
+	items []interface{}
+}
+
+func newTestIterator(ids []string) *testIterator {
+	iter := &testIterator{}
+	for _, id := range ids {
+		iter.items = append(iter.items, &mockObject{id: id})
+	}
+	return iter
+}
+
+func (i *testIterator) Next() interface{} {
+	if len(i.items) == 0 {
+		return nil
+	}
+	raw := i.items[0]
+	i.items = i.items[1:]
+	return raw
+}
+
+type mockObject struct {
+	id string
+}
+
+func (m *mockObject) GetID() string {
+	return m.id
+}
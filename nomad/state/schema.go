@@ -10,7 +10,12 @@ import (
 )
 
 const (
-	TableNamespaces = "namespaces"
+	TableNamespaces           = "namespaces"
+	TableServiceRegistrations = "service_registrations"
+	TableVariables            = "variables"
+	TableRootKeyMeta          = "root_key_meta"
+	TableNodePools            = "node_pools"
+	TableReservations         = "reservations"
 )
 
 var (
@@ -58,6 +63,11 @@ func init() {
 		scalingPolicyTableSchema,
 		scalingEventTableSchema,
 		namespaceTableSchema,
+		serviceRegistrationsTableSchema,
+		variablesTableSchema,
+		rootKeyMetaTableSchema,
+		nodePoolTableSchema,
+		reservationTableSchema,
 	}...)
 }
 
@@ -122,6 +132,14 @@ func nodeTableSchema() *memdb.TableSchema {
 					Field: "SecretID",
 				},
 			},
+			"node_pool": {
+				Name:         "node_pool",
+				AllowMissing: true,
+				Unique:       false,
+				Indexer: &memdb.StringFieldIndex{
+					Field: "NodePool",
+				},
+			},
 		},
 	}
 }
@@ -179,6 +197,14 @@ func jobTableSchema() *memdb.TableSchema {
 					Conditional: jobIsPeriodic,
 				},
 			},
+			"node_pool": {
+				Name:         "node_pool",
+				AllowMissing: true,
+				Unique:       false,
+				Indexer: &memdb.StringFieldIndex{
+					Field: "NodePool",
+				},
+			},
 		},
 	}
 }
@@ -639,8 +665,8 @@ func aclTokenTableSchema() *memdb.TableSchema {
 				Name:         "secret",
 				AllowMissing: false,
 				Unique:       true,
-				Indexer: &memdb.UUIDFieldIndex{
-					Field: "SecretID",
+				Indexer: &memdb.StringFieldIndex{
+					Field: "SecretHash",
 				},
 			},
 			"global": {
@@ -651,6 +677,16 @@ func aclTokenTableSchema() *memdb.TableSchema {
 					Field: "Global",
 				},
 			},
+			// The keyid index allows finding all tokens whose secret is
+			// encrypted with a given root key.
+			"keyid": {
+				Name:         "keyid",
+				AllowMissing: true,
+				Unique:       false,
+				Indexer: &memdb.StringFieldIndex{
+					Field: "SecretKeyID",
+				},
+			},
 		},
 	}
 }
@@ -960,3 +996,204 @@ func namespaceTableSchema() *memdb.TableSchema {
 		},
 	}
 }
+
+// serviceRegistrationsTableSchema returns the MemDB schema for Nomad native
+// service registrations.
+func serviceRegistrationsTableSchema() *memdb.TableSchema {
+	return &memdb.TableSchema{
+		Name: TableServiceRegistrations,
+		Indexes: map[string]*memdb.IndexSchema{
+			// The serviceID in combination with namespace forms a unique
+			// identifier for a service registration. This is used to look up
+			// and delete services in individual isolation.
+			"id": {
+				Name:         "id",
+				AllowMissing: false,
+				Unique:       true,
+				Indexer: &memdb.CompoundIndex{
+					Indexes: []memdb.Indexer{
+						&memdb.StringFieldIndex{
+							Field: "Namespace",
+						},
+						&memdb.StringFieldIndex{
+							Field: "ID",
+						},
+					},
+				},
+			},
+			"service_name": {
+				Name:         "service_name",
+				AllowMissing: false,
+				Unique:       false,
+				Indexer: &memdb.CompoundIndex{
+					Indexes: []memdb.Indexer{
+						&memdb.StringFieldIndex{
+							Field: "Namespace",
+						},
+						&memdb.StringFieldIndex{
+							Field: "ServiceName",
+						},
+					},
+				},
+			},
+			"job": {
+				Name:         "job",
+				AllowMissing: false,
+				Unique:       false,
+				Indexer: &memdb.CompoundIndex{
+					Indexes: []memdb.Indexer{
+						&memdb.StringFieldIndex{
+							Field: "Namespace",
+						},
+						&memdb.StringFieldIndex{
+							Field: "JobID",
+						},
+					},
+				},
+			},
+
+			// The node_id index allows lookups and deletions to be performed
+			// for an entire node. This is primarily used when a node becomes
+			// lost.
+			"node_id": {
+				Name:         "node_id",
+				AllowMissing: false,
+				Unique:       false,
+				Indexer: &memdb.StringFieldIndex{
+					Field: "NodeID",
+				},
+			},
+			"alloc_id": {
+				Name:         "alloc_id",
+				AllowMissing: false,
+				Unique:       false,
+				Indexer: &memdb.StringFieldIndex{
+					Field: "AllocID",
+				},
+			},
+		},
+	}
+}
+
+// variablesTableSchema returns the MemDB schema for Nomad variables. Only the
+// encrypted form of a variable is stored.
+func variablesTableSchema() *memdb.TableSchema {
+	return &memdb.TableSchema{
+		Name: TableVariables,
+		Indexes: map[string]*memdb.IndexSchema{
+			// The path in combination with namespace forms a unique
+			// identifier for a variable. The compound index also allows for
+			// prefix lookups of paths within a namespace.
+			"id": {
+				Name:         "id",
+				AllowMissing: false,
+				Unique:       true,
+				Indexer: &memdb.CompoundIndex{
+					Indexes: []memdb.Indexer{
+						&memdb.StringFieldIndex{
+							Field: "Namespace",
+						},
+						&memdb.StringFieldIndex{
+							Field: "Path",
+						},
+					},
+				},
+			},
+
+			// The keyid index allows finding all variables encrypted with a
+			// given root key.
+			"keyid": {
+				Name:         "keyid",
+				AllowMissing: false,
+				Unique:       false,
+				Indexer: &memdb.StringFieldIndex{
+					Field: "KeyID",
+				},
+			},
+		},
+	}
+}
+
+// rootKeyMetaTableSchema returns the MemDB schema for the metadata of the
+// root keys used to encrypt sensitive data. The key material itself is never
+// stored in state.
+func rootKeyMetaTableSchema() *memdb.TableSchema {
+	return &memdb.TableSchema{
+		Name: TableRootKeyMeta,
+		Indexes: map[string]*memdb.IndexSchema{
+			"id": {
+				Name:         "id",
+				AllowMissing: false,
+				Unique:       true,
+				Indexer: &memdb.StringFieldIndex{
+					Field:     "KeyID",
+					Lowercase: true,
+				},
+			},
+			"state": {
+				Name:         "state",
+				AllowMissing: false,
+				Unique:       false,
+				Indexer: &memdb.StringFieldIndex{
+					Field: "State",
+				},
+			},
+		},
+	}
+}
+
+// nodePoolTableSchema returns the MemDB schema for the node pools table.
+// This table is used to store the node pools used to partition the client
+// nodes of the cluster.
+func nodePoolTableSchema() *memdb.TableSchema {
+	return &memdb.TableSchema{
+		Name: TableNodePools,
+		Indexes: map[string]*memdb.IndexSchema{
+			"id": {
+				Name:         "id",
+				AllowMissing: false,
+				Unique:       true,
+				Indexer: &memdb.StringFieldIndex{
+					Field: "Name",
+				},
+			},
+		},
+	}
+}
+
+// reservationTableSchema returns the MemDB schema for the reservations table.
+// This table is used to store the capacity held on nodes for workloads that
+// will be submitted later.
+func reservationTableSchema() *memdb.TableSchema {
+	return &memdb.TableSchema{
+		Name: TableReservations,
+		Indexes: map[string]*memdb.IndexSchema{
+			"id": {
+				Name:         "id",
+				AllowMissing: false,
+				Unique:       true,
+				Indexer: &memdb.CompoundIndex{
+					Indexes: []memdb.Indexer{
+						&memdb.StringFieldIndex{
+							Field: "Namespace",
+						},
+						&memdb.StringFieldIndex{
+							Field: "Name",
+						},
+					},
+				},
+			},
+
+			// The node index allows finding the reservations with instances
+			// placed on a node.
+			"node": {
+				Name:         "node",
+				AllowMissing: true,
+				Unique:       false,
+				Indexer: &memdb.StringSliceFieldIndex{
+					Field: "NodeIDs",
+				},
+			},
+		},
+	}
+}
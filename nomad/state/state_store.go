@@ -122,6 +122,11 @@ func NewStateStore(config *StateStoreConfig) (*StateStore, error) {
 		return nil, fmt.Errorf("enterprise state store initialization failed: %v", err)
 	}
 
+	// Initialize the state store with the built-in node pools.
+	if err := s.nodePoolInit(); err != nil {
+		return nil, fmt.Errorf("node pool state store initialization failed: %v", err)
+	}
+
 	return s, nil
 }
 
@@ -549,11 +554,11 @@ func (s *StateStore) Deployments(ws memdb.WatchSet) (memdb.ResultIterator, error
 	return iter, nil
 }
 
-func (s *StateStore) DeploymentsByNamespace(ws memdb.WatchSet, namespace string) (memdb.ResultIterator, error) {
+func (s *StateStore) DeploymentsByNamespace(ws memdb.WatchSet, namespace string, sort SortOption) (memdb.ResultIterator, error) {
 	txn := s.db.ReadTxn()
 
 	// Walk the entire deployments table
-	iter, err := txn.Get("deployment", "namespace", namespace)
+	iter, err := getSorted(txn, sort, "deployment", "namespace", namespace)
 	if err != nil {
 		return nil, err
 	}
@@ -562,11 +567,11 @@ func (s *StateStore) DeploymentsByNamespace(ws memdb.WatchSet, namespace string)
 	return iter, nil
 }
 
-func (s *StateStore) DeploymentsByIDPrefix(ws memdb.WatchSet, namespace, deploymentID string) (memdb.ResultIterator, error) {
+func (s *StateStore) DeploymentsByIDPrefix(ws memdb.WatchSet, namespace, deploymentID string, sort SortOption) (memdb.ResultIterator, error) {
 	txn := s.db.ReadTxn()
 
 	// Walk the entire deployments table
-	iter, err := txn.Get("deployment", "id_prefix", deploymentID)
+	iter, err := getSorted(txn, sort, "deployment", "id_prefix", deploymentID)
 	if err != nil {
 		return nil, err
 	}
@@ -846,6 +851,13 @@ func upsertNodeTxn(txn *txn, index uint64, node *structs.Node) error {
 		node.ModifyIndex = index
 	}
 
+	// Create the node pool if the node joins one that doesn't exist yet
+	if node.NodePool != "" {
+		if _, err := fetchOrCreateNodePoolTxn(txn, index, node.NodePool); err != nil {
+			return fmt.Errorf("node pool upsert failed: %v", err)
+		}
+	}
+
 	// Insert the node
 	if err := txn.Insert("nodes", node); err != nil {
 		return fmt.Errorf("node insert failed: %v", err)
@@ -895,6 +907,10 @@ func deleteNodeTxn(txn *txn, index uint64, nodes []string) error {
 		if err := deleteNodeCSIPlugins(txn, node, index); err != nil {
 			return fmt.Errorf("csi plugin delete failed: %v", err)
 		}
+
+		if err := deleteServiceRegistrationsTxn(index, txn, "node_id", nodeID); err != nil {
+			return err
+		}
 	}
 
 	if err := txn.Insert("index", &IndexEntry{"nodes", index}); err != nil {
@@ -1468,10 +1484,10 @@ func (s *StateStore) NodeByID(ws memdb.WatchSet, nodeID string) (*structs.Node,
 }
 
 // NodesByIDPrefix is used to lookup nodes by prefix
-func (s *StateStore) NodesByIDPrefix(ws memdb.WatchSet, nodeID string) (memdb.ResultIterator, error) {
+func (s *StateStore) NodesByIDPrefix(ws memdb.WatchSet, nodeID string, sort SortOption) (memdb.ResultIterator, error) {
 	txn := s.db.ReadTxn()
 
-	iter, err := txn.Get("nodes", "id_prefix", nodeID)
+	iter, err := getSorted(txn, sort, "nodes", "id_prefix", nodeID)
 	if err != nil {
 		return nil, fmt.Errorf("node lookup failed: %v", err)
 	}
@@ -1876,10 +1892,10 @@ func (s *StateStore) JobByIDTxn(ws memdb.WatchSet, namespace, id string, txn Txn
 }
 
 // JobsByIDPrefix is used to lookup a job by prefix
-func (s *StateStore) JobsByIDPrefix(ws memdb.WatchSet, namespace, id string) (memdb.ResultIterator, error) {
+func (s *StateStore) JobsByIDPrefix(ws memdb.WatchSet, namespace, id string, sort SortOption) (memdb.ResultIterator, error) {
 	txn := s.db.ReadTxn()
 
-	iter, err := txn.Get("jobs", "id_prefix", namespace, id)
+	iter, err := getSorted(txn, sort, "jobs", "id_prefix", namespace, id)
 	if err != nil {
 		return nil, fmt.Errorf("job lookup failed: %v", err)
 	}
@@ -1973,11 +1989,11 @@ func (s *StateStore) JobVersions(ws memdb.WatchSet) (memdb.ResultIterator, error
 }
 
 // Jobs returns an iterator over all the jobs
-func (s *StateStore) Jobs(ws memdb.WatchSet) (memdb.ResultIterator, error) {
+func (s *StateStore) Jobs(ws memdb.WatchSet, sort SortOption) (memdb.ResultIterator, error) {
 	txn := s.db.ReadTxn()
 
 	// Walk the entire jobs table
-	iter, err := txn.Get("jobs", "id")
+	iter, err := getSorted(txn, sort, "jobs", "id")
 	if err != nil {
 		return nil, err
 	}
@@ -1988,15 +2004,15 @@ func (s *StateStore) Jobs(ws memdb.WatchSet) (memdb.ResultIterator, error) {
 }
 
 // JobsByNamespace returns an iterator over all the jobs for the given namespace
-func (s *StateStore) JobsByNamespace(ws memdb.WatchSet, namespace string) (memdb.ResultIterator, error) {
+func (s *StateStore) JobsByNamespace(ws memdb.WatchSet, namespace string, sort SortOption) (memdb.ResultIterator, error) {
 	txn := s.db.ReadTxn()
-	return s.jobsByNamespaceImpl(ws, namespace, txn)
+	return s.jobsByNamespaceImpl(ws, namespace, txn, sort)
 }
 
 // jobsByNamespaceImpl returns an iterator over all the jobs for the given namespace
-func (s *StateStore) jobsByNamespaceImpl(ws memdb.WatchSet, namespace string, txn *txn) (memdb.ResultIterator, error) {
+func (s *StateStore) jobsByNamespaceImpl(ws memdb.WatchSet, namespace string, txn *txn, sort SortOption) (memdb.ResultIterator, error) {
 	// Walk the entire jobs table
-	iter, err := txn.Get("jobs", "id_prefix", namespace, "")
+	iter, err := getSorted(txn, sort, "jobs", "id_prefix", namespace, "")
 	if err != nil {
 		return nil, err
 	}
@@ -2070,6 +2086,85 @@ func (s *StateStore) JobSummaryByID(ws memdb.WatchSet, namespace, jobID string)
 	return nil, nil
 }
 
+// JobDependenciesMet returns whether every dependency of the job is met. If
+// not, the returned string describes the first unmet dependency.
+func (s *StateStore) JobDependenciesMet(ws memdb.WatchSet, job *structs.Job) (bool, string, error) {
+	for _, dep := range job.DependsOn {
+		upstream, err := s.JobByID(ws, job.Namespace, dep.JobID)
+		if err != nil {
+			return false, "", err
+		}
+		if upstream == nil {
+			return false, fmt.Sprintf("waiting on %s: job not found", dep), nil
+		}
+
+		met, err := s.jobDependencyMet(ws, upstream, dep)
+		if err != nil {
+			return false, "", err
+		}
+		if !met {
+			return false, fmt.Sprintf("waiting on %s", dep), nil
+		}
+	}
+	return true, "", nil
+}
+
+// jobDependencyMet returns whether the upstream job meets the dependency.
+// Periodic and parameterized jobs meet it once they have children and all of
+// them meet it, or once the child selected by the dependency meets it.
+func (s *StateStore) jobDependencyMet(ws memdb.WatchSet, upstream *structs.Job, dep *structs.JobDependency) (bool, error) {
+	if dep.Child != "" {
+		child, err := s.JobByID(ws, upstream.Namespace, dep.Child)
+		if err != nil {
+			return false, err
+		}
+		if child == nil || child.ParentID != upstream.ID {
+			return false, nil
+		}
+
+		summary, err := s.JobSummaryByID(ws, child.Namespace, child.ID)
+		if err != nil {
+			return false, err
+		}
+		return dep.Met(child, summary), nil
+	}
+
+	if !upstream.IsPeriodic() && !upstream.IsParameterized() {
+		summary, err := s.JobSummaryByID(ws, upstream.Namespace, upstream.ID)
+		if err != nil {
+			return false, err
+		}
+		return dep.Met(upstream, summary), nil
+	}
+
+	iter, err := s.JobsByIDPrefix(ws, upstream.Namespace, upstream.ID, SortDefault)
+	if err != nil {
+		return false, err
+	}
+
+	children := 0
+	for {
+		raw := iter.Next()
+		if raw == nil {
+			break
+		}
+		child := raw.(*structs.Job)
+		if child.ParentID != upstream.ID {
+			continue
+		}
+		children++
+
+		summary, err := s.JobSummaryByID(ws, child.Namespace, child.ID)
+		if err != nil {
+			return false, err
+		}
+		if !dep.Met(child, summary) {
+			return false, nil
+		}
+	}
+	return children > 0, nil
+}
+
 // JobSummaries walks the entire job summary table and returns all the job
 // summary objects
 func (s *StateStore) JobSummaries(ws memdb.WatchSet) (memdb.ResultIterator, error) {
@@ -3026,11 +3121,11 @@ func (s *StateStore) EvalByID(ws memdb.WatchSet, id string) (*structs.Evaluation
 
 // EvalsByIDPrefix is used to lookup evaluations by prefix in a particular
 // namespace
-func (s *StateStore) EvalsByIDPrefix(ws memdb.WatchSet, namespace, id string) (memdb.ResultIterator, error) {
+func (s *StateStore) EvalsByIDPrefix(ws memdb.WatchSet, namespace, id string, sort SortOption) (memdb.ResultIterator, error) {
 	txn := s.db.ReadTxn()
 
 	// Get an iterator over all evals by the id prefix
-	iter, err := txn.Get("evals", "id_prefix", id)
+	iter, err := getSorted(txn, sort, "evals", "id_prefix", id)
 	if err != nil {
 		return nil, fmt.Errorf("eval lookup failed: %v", err)
 	}
@@ -3087,11 +3182,11 @@ func (s *StateStore) EvalsByJob(ws memdb.WatchSet, namespace, jobID string) ([]*
 }
 
 // Evals returns an iterator over all the evaluations
-func (s *StateStore) Evals(ws memdb.WatchSet) (memdb.ResultIterator, error) {
+func (s *StateStore) Evals(ws memdb.WatchSet, sort SortOption) (memdb.ResultIterator, error) {
 	txn := s.db.ReadTxn()
 
 	// Walk the entire table
-	iter, err := txn.Get("evals", "id")
+	iter, err := getSorted(txn, sort, "evals", "id")
 	if err != nil {
 		return nil, err
 	}
@@ -3103,11 +3198,11 @@ func (s *StateStore) Evals(ws memdb.WatchSet) (memdb.ResultIterator, error) {
 
 // EvalsByNamespace returns an iterator over all the evaluations in the given
 // namespace
-func (s *StateStore) EvalsByNamespace(ws memdb.WatchSet, namespace string) (memdb.ResultIterator, error) {
+func (s *StateStore) EvalsByNamespace(ws memdb.WatchSet, namespace string, sort SortOption) (memdb.ResultIterator, error) {
 	txn := s.db.ReadTxn()
 
 	// Walk the entire table
-	iter, err := txn.Get("evals", "namespace", namespace)
+	iter, err := getSorted(txn, sort, "evals", "namespace", namespace)
 	if err != nil {
 		return nil, err
 	}
@@ -3206,6 +3301,14 @@ func (s *StateStore) nestedUpdateAllocFromClient(txn *txn, index uint64, alloc *
 		return err
 	}
 
+	// Remove any Nomad service registrations belonging to the allocation once
+	// the client reports it as terminal; they will never be valid again.
+	if copyAlloc.ClientTerminalStatus() {
+		if err := s.deleteServiceRegistrationByAllocIDTxn(txn, index, copyAlloc.ID); err != nil {
+			return err
+		}
+	}
+
 	// Update the allocation
 	if err := txn.Insert("allocs", copyAlloc); err != nil {
 		return fmt.Errorf("alloc insert failed: %v", err)
@@ -3282,9 +3385,10 @@ func (s *StateStore) upsertAllocsImpl(index uint64, allocs []*structs.Allocation
 			// Keep the clients task states
 			alloc.TaskStates = exist.TaskStates
 
-			// If the scheduler is marking this allocation as lost we do not
-			// want to reuse the status of the existing allocation.
-			if alloc.ClientStatus != structs.AllocClientStatusLost {
+			// If the scheduler is marking this allocation as lost or unknown
+			// we do not want to reuse the status of the existing allocation.
+			if alloc.ClientStatus != structs.AllocClientStatusLost &&
+				alloc.ClientStatus != structs.AllocClientStatusUnknown {
 				alloc.ClientStatus = exist.ClientStatus
 				alloc.ClientDescription = exist.ClientDescription
 			}
@@ -3450,10 +3554,10 @@ func (s *StateStore) allocByIDImpl(txn Txn, ws memdb.WatchSet, id string) (*stru
 }
 
 // AllocsByIDPrefix is used to lookup allocs by prefix
-func (s *StateStore) AllocsByIDPrefix(ws memdb.WatchSet, namespace, id string) (memdb.ResultIterator, error) {
+func (s *StateStore) AllocsByIDPrefix(ws memdb.WatchSet, namespace, id string, sort SortOption) (memdb.ResultIterator, error) {
 	txn := s.db.ReadTxn()
 
-	iter, err := txn.Get("allocs", "id_prefix", id)
+	iter, err := getSorted(txn, sort, "allocs", "id_prefix", id)
 	if err != nil {
 		return nil, fmt.Errorf("alloc lookup failed: %v", err)
 	}
@@ -3479,10 +3583,10 @@ func allocNamespaceFilter(namespace string) func(interface{}) bool {
 }
 
 // AllocsByIDPrefixAllNSs is used to lookup allocs by prefix.
-func (s *StateStore) AllocsByIDPrefixAllNSs(ws memdb.WatchSet, prefix string) (memdb.ResultIterator, error) {
+func (s *StateStore) AllocsByIDPrefixAllNSs(ws memdb.WatchSet, prefix string, sort SortOption) (memdb.ResultIterator, error) {
 	txn := s.db.ReadTxn()
 
-	iter, err := txn.Get("allocs", "id_prefix", prefix)
+	iter, err := getSorted(txn, sort, "allocs", "id_prefix", prefix)
 	if err != nil {
 		return nil, fmt.Errorf("alloc lookup failed: %v", err)
 	}
@@ -3632,11 +3736,11 @@ func (s *StateStore) AllocsByDeployment(ws memdb.WatchSet, deploymentID string)
 }
 
 // Allocs returns an iterator over all the evaluations
-func (s *StateStore) Allocs(ws memdb.WatchSet) (memdb.ResultIterator, error) {
+func (s *StateStore) Allocs(ws memdb.WatchSet, sort SortOption) (memdb.ResultIterator, error) {
 	txn := s.db.ReadTxn()
 
 	// Walk the entire table
-	iter, err := txn.Get("allocs", "id")
+	iter, err := getSorted(txn, sort, "allocs", "id")
 	if err != nil {
 		return nil, err
 	}
@@ -3648,16 +3752,16 @@ func (s *StateStore) Allocs(ws memdb.WatchSet) (memdb.ResultIterator, error) {
 
 // AllocsByNamespace returns an iterator over all the allocations in the
 // namespace
-func (s *StateStore) AllocsByNamespace(ws memdb.WatchSet, namespace string) (memdb.ResultIterator, error) {
+func (s *StateStore) AllocsByNamespace(ws memdb.WatchSet, namespace string, sort SortOption) (memdb.ResultIterator, error) {
 	txn := s.db.ReadTxn()
-	return s.allocsByNamespaceImpl(ws, txn, namespace)
+	return s.allocsByNamespaceImpl(ws, txn, namespace, sort)
 }
 
 // allocsByNamespaceImpl returns an iterator over all the allocations in the
 // namespace
-func (s *StateStore) allocsByNamespaceImpl(ws memdb.WatchSet, txn *txn, namespace string) (memdb.ResultIterator, error) {
+func (s *StateStore) allocsByNamespaceImpl(ws memdb.WatchSet, txn *txn, namespace string, sort SortOption) (memdb.ResultIterator, error) {
 	// Walk the entire table
-	iter, err := txn.Get("allocs", "namespace", namespace)
+	iter, err := getSorted(txn, sort, "allocs", "namespace", namespace)
 	if err != nil {
 		return nil, err
 	}
@@ -4440,6 +4544,8 @@ func (s *StateStore) ReconcileJobSummaries(index uint64) error {
 				tg.Failed += 1
 			case structs.AllocClientStatusLost:
 				tg.Lost += 1
+			case structs.AllocClientStatusUnknown:
+				tg.Unknown += 1
 			case structs.AllocClientStatusComplete:
 				tg.Complete += 1
 			case structs.AllocClientStatusRunning:
@@ -4997,6 +5103,8 @@ func (s *StateStore) updateSummaryWithAlloc(index uint64, alloc *structs.Allocat
 			tgSummary.Complete += 1
 		case structs.AllocClientStatusLost:
 			tgSummary.Lost += 1
+		case structs.AllocClientStatusUnknown:
+			tgSummary.Unknown += 1
 		}
 
 		// Decrementing the count of the bin of the last state
@@ -5013,6 +5121,10 @@ func (s *StateStore) updateSummaryWithAlloc(index uint64, alloc *structs.Allocat
 			if tgSummary.Lost > 0 {
 				tgSummary.Lost -= 1
 			}
+		case structs.AllocClientStatusUnknown:
+			if tgSummary.Unknown > 0 {
+				tgSummary.Unknown -= 1
+			}
 		case structs.AllocClientStatusFailed, structs.AllocClientStatusComplete:
 		default:
 			s.logger.Error("invalid old client status for allocation",
@@ -5221,6 +5333,7 @@ func (s *StateStore) UpsertACLTokens(msgType structs.MessageType, index uint64,
 		if len(token.Hash) == 0 {
 			token.SetHash()
 		}
+		token.SetSecretHash()
 
 		// Check if the token already exists
 		existing, err := txn.First("acl_token", "id", token.AccessorID)
@@ -5234,8 +5347,16 @@ func (s *StateStore) UpsertACLTokens(msgType structs.MessageType, index uint64,
 			token.CreateIndex = existTK.CreateIndex
 			token.ModifyIndex = index
 
-			// Do not allow SecretID or create time to change
-			token.SecretID = existTK.SecretID
+			// Do not allow SecretID or create time to change. The secret may
+			// only be replaced by an encryption of the same secret.
+			if token.SecretKeyID != "" && token.SecretHash == existTK.SecretHash {
+				token.SecretID = ""
+			} else {
+				token.SecretID = existTK.SecretID
+				token.EncryptedSecretID = existTK.EncryptedSecretID
+				token.SecretKeyID = existTK.SecretKeyID
+			}
+			token.SecretHash = existTK.SecretHash
 			token.CreateTime = existTK.CreateTime
 
 		} else {
@@ -5301,7 +5422,7 @@ func (s *StateStore) ACLTokenBySecretID(ws memdb.WatchSet, secretID string) (*st
 
 	txn := s.db.ReadTxn()
 
-	watchCh, existing, err := txn.FirstWatch("acl_token", "secret", secretID)
+	watchCh, existing, err := txn.FirstWatch("acl_token", "secret", structs.ACLTokenSecretHash(secretID))
 	if err != nil {
 		return nil, fmt.Errorf("acl token lookup failed: %v", err)
 	}
@@ -5314,10 +5435,10 @@ func (s *StateStore) ACLTokenBySecretID(ws memdb.WatchSet, secretID string) (*st
 }
 
 // ACLTokenByAccessorIDPrefix is used to lookup tokens by prefix
-func (s *StateStore) ACLTokenByAccessorIDPrefix(ws memdb.WatchSet, prefix string) (memdb.ResultIterator, error) {
+func (s *StateStore) ACLTokenByAccessorIDPrefix(ws memdb.WatchSet, prefix string, sort SortOption) (memdb.ResultIterator, error) {
 	txn := s.db.ReadTxn()
 
-	iter, err := txn.Get("acl_token", "id_prefix", prefix)
+	iter, err := getSorted(txn, sort, "acl_token", "id_prefix", prefix)
 	if err != nil {
 		return nil, fmt.Errorf("acl token lookup failed: %v", err)
 	}
@@ -5326,11 +5447,11 @@ func (s *StateStore) ACLTokenByAccessorIDPrefix(ws memdb.WatchSet, prefix string
 }
 
 // ACLTokens returns an iterator over all the tokens
-func (s *StateStore) ACLTokens(ws memdb.WatchSet) (memdb.ResultIterator, error) {
+func (s *StateStore) ACLTokens(ws memdb.WatchSet, sort SortOption) (memdb.ResultIterator, error) {
 	txn := s.db.ReadTxn()
 
 	// Walk the entire table
-	iter, err := txn.Get("acl_token", "id")
+	iter, err := getSorted(txn, sort, "acl_token", "id")
 	if err != nil {
 		return nil, err
 	}
@@ -5339,11 +5460,11 @@ func (s *StateStore) ACLTokens(ws memdb.WatchSet) (memdb.ResultIterator, error)
 }
 
 // ACLTokensByGlobal returns an iterator over all the tokens filtered by global value
-func (s *StateStore) ACLTokensByGlobal(ws memdb.WatchSet, globalVal bool) (memdb.ResultIterator, error) {
+func (s *StateStore) ACLTokensByGlobal(ws memdb.WatchSet, globalVal bool, sort SortOption) (memdb.ResultIterator, error) {
 	txn := s.db.ReadTxn()
 
 	// Walk the entire table
-	iter, err := txn.Get("acl_token", "global", globalVal)
+	iter, err := getSorted(txn, sort, "acl_token", "global", globalVal)
 	if err != nil {
 		return nil, err
 	}
@@ -5391,6 +5512,7 @@ func (s *StateStore) BootstrapACLTokens(msgType structs.MessageType, index uint6
 	// Update the Create/Modify time
 	token.CreateIndex = index
 	token.ModifyIndex = index
+	token.SetSecretHash()
 
 	// Insert the token
 	if err := txn.Insert("acl_token", token); err != nil {
@@ -5920,7 +6042,7 @@ func (s *StateStore) DeleteNamespaces(index uint64, names []string) error {
 		}
 
 		// Ensure that the namespace doesn't have any non-terminal jobs
-		iter, err := s.jobsByNamespaceImpl(nil, name, txn)
+		iter, err := s.jobsByNamespaceImpl(nil, name, txn, SortDefault)
 		if err != nil {
 			return err
 		}
@@ -5993,11 +6115,11 @@ func (s *StateStore) DeleteScalingPoliciesTxn(index uint64, ids []string, txn *t
 }
 
 // ScalingPolicies returns an iterator over all the scaling policies
-func (s *StateStore) ScalingPolicies(ws memdb.WatchSet) (memdb.ResultIterator, error) {
+func (s *StateStore) ScalingPolicies(ws memdb.WatchSet, sort SortOption) (memdb.ResultIterator, error) {
 	txn := s.db.ReadTxn()
 
 	// Walk the entire scaling_policy table
-	iter, err := txn.Get("scaling_policy", "id")
+	iter, err := getSorted(txn, sort, "scaling_policy", "id")
 	if err != nil {
 		return nil, err
 	}
@@ -6020,10 +6142,12 @@ func (s *StateStore) ScalingPoliciesByTypePrefix(ws memdb.WatchSet, t string) (m
 	return iter, nil
 }
 
-func (s *StateStore) ScalingPoliciesByNamespace(ws memdb.WatchSet, namespace, typ string) (memdb.ResultIterator, error) {
+// ScalingPoliciesByNamespace returns an iterator over the scaling policies
+// of the namespace, ordered by ID so that they can be paginated.
+func (s *StateStore) ScalingPoliciesByNamespace(ws memdb.WatchSet, namespace, typ string, sort SortOption) (memdb.ResultIterator, error) {
 	txn := s.db.ReadTxn()
 
-	iter, err := txn.Get("scaling_policy", "target_prefix", namespace)
+	iter, err := getSorted(txn, sort, "scaling_policy", "id")
 	if err != nil {
 		return nil, err
 	}
@@ -6070,6 +6194,37 @@ func (s *StateStore) ScalingPoliciesByJob(ws memdb.WatchSet, namespace, jobID, p
 	return memdb.NewFilterIterator(iter, filter), nil
 }
 
+// ScalingPoliciesByJobSorted returns an iterator over the scaling policies of
+// the job whose type has the given prefix. The policies are looked up by
+// their target and ordered by ID so that they can be paginated.
+func (s *StateStore) ScalingPoliciesByJobSorted(ws memdb.WatchSet, namespace, jobID, typ string, sortOpt SortOption) (memdb.ResultIterator, error) {
+	txn := s.db.ReadTxn()
+	iter, err := s.ScalingPoliciesByJobTxn(ws, namespace, jobID, txn)
+	if err != nil {
+		return nil, err
+	}
+
+	var policies []*structs.ScalingPolicy
+	for raw := iter.Next(); raw != nil; raw = iter.Next() {
+		p := raw.(*structs.ScalingPolicy)
+		if strings.HasPrefix(p.Type, typ) {
+			policies = append(policies, p)
+		}
+	}
+	sort.Slice(policies, func(i, j int) bool {
+		if sortOpt == SortReverse {
+			return policies[i].ID > policies[j].ID
+		}
+		return policies[i].ID < policies[j].ID
+	})
+
+	sorted := NewSliceIterator()
+	for _, p := range policies {
+		sorted.Add(p)
+	}
+	return sorted, nil
+}
+
 func (s *StateStore) ScalingPoliciesByJobTxn(ws memdb.WatchSet, namespace, jobID string,
 	txn *txn) (memdb.ResultIterator, error) {
 
@@ -6145,10 +6300,10 @@ func (s *StateStore) ScalingPolicyByTargetAndType(ws memdb.WatchSet, target map[
 	return nil, nil
 }
 
-func (s *StateStore) ScalingPoliciesByIDPrefix(ws memdb.WatchSet, namespace string, prefix string) (memdb.ResultIterator, error) {
+func (s *StateStore) ScalingPoliciesByIDPrefix(ws memdb.WatchSet, namespace string, prefix string, sort SortOption) (memdb.ResultIterator, error) {
 	txn := s.db.ReadTxn()
 
-	iter, err := txn.Get("scaling_policy", "id_prefix", prefix)
+	iter, err := getSorted(txn, sort, "scaling_policy", "id_prefix", prefix)
 	if err != nil {
 		return nil, fmt.Errorf("scaling policy lookup failed: %v", err)
 	}
@@ -6376,6 +6531,7 @@ func (r *StateRestore) ACLPolicyRestore(policy *structs.ACLPolicy) error {
 
 // ACLTokenRestore is used to restore an ACL token
 func (r *StateRestore) ACLTokenRestore(token *structs.ACLToken) error {
+	token.SetSecretHash()
 	if err := r.txn.Insert("acl_token", token); err != nil {
 		return fmt.Errorf("inserting acl token failed: %v", err)
 	}
@@ -6443,3 +6599,46 @@ func (r *StateRestore) NamespaceRestore(ns *structs.Namespace) error {
 	}
 	return nil
 }
+
+// ServiceRegistrationRestore is used to restore a single service registration
+// into the service_registrations table.
+func (r *StateRestore) ServiceRegistrationRestore(service *structs.ServiceRegistration) error {
+	if err := r.txn.Insert(TableServiceRegistrations, service); err != nil {
+		return fmt.Errorf("service registration insert failed: %v", err)
+	}
+	return nil
+}
+
+// VariablesRestore is used to restore a single encrypted variable into the
+// variables table.
+func (r *StateRestore) VariablesRestore(variable *structs.VariableEncrypted) error {
+	if err := r.txn.Insert(TableVariables, variable); err != nil {
+		return fmt.Errorf("variable insert failed: %v", err)
+	}
+	return nil
+}
+
+// RootKeyMetaRestore is used to restore a single root key meta into the
+// root_key_meta table.
+func (r *StateRestore) RootKeyMetaRestore(rootKeyMeta *structs.RootKeyMeta) error {
+	if err := r.txn.Insert(TableRootKeyMeta, rootKeyMeta); err != nil {
+		return fmt.Errorf("root key metadata insert failed: %v", err)
+	}
+	return nil
+}
+
+// NodePoolRestore is used to restore a node pool
+func (r *StateRestore) NodePoolRestore(pool *structs.NodePool) error {
+	if err := r.txn.Insert(TableNodePools, pool); err != nil {
+		return fmt.Errorf("node pool insert failed: %v", err)
+	}
+	return nil
+}
+
+// ReservationRestore is used to restore a reservation
+func (r *StateRestore) ReservationRestore(reservation *structs.Reservation) error {
+	if err := r.txn.Insert(TableReservations, reservation); err != nil {
+		return fmt.Errorf("reservation insert failed: %v", err)
+	}
+	return nil
+}
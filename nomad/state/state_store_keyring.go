@@ -0,0 +1,184 @@
+package state
+
+import (
+	"fmt"
+
+	"github.com/hashicorp/go-memdb"
+	"github.com/hashicorp/nomad/nomad/structs"
+)
+
+// UpsertRootKeyMeta saves root key meta or updates it in-place. If the key
+// is being made active, any other active key is marked inactive so that
+// there is never more than one active key.
+func (s *StateStore) UpsertRootKeyMeta(
+	msgType structs.MessageType, index uint64, rootKeyMeta *structs.RootKeyMeta) error {
+
+	txn := s.db.WriteTxnMsgT(msgType, index)
+	defer txn.Abort()
+
+	if err := s.upsertRootKeyMetaTxn(index, txn, rootKeyMeta); err != nil {
+		return err
+	}
+	return txn.Commit()
+}
+
+func (s *StateStore) upsertRootKeyMetaTxn(
+	index uint64, txn *txn, rootKeyMeta *structs.RootKeyMeta) error {
+
+	raw, err := txn.First(TableRootKeyMeta, "id", rootKeyMeta.KeyID)
+	if err != nil {
+		return fmt.Errorf("root key metadata lookup failed: %v", err)
+	}
+
+	if raw != nil {
+		existing := raw.(*structs.RootKeyMeta)
+		rootKeyMeta.CreateIndex = existing.CreateIndex
+		rootKeyMeta.CreateTime = existing.CreateTime
+	} else {
+		rootKeyMeta.CreateIndex = index
+	}
+	rootKeyMeta.ModifyIndex = index
+
+	if rootKeyMeta.Active() {
+		iter, err := txn.Get(TableRootKeyMeta, "state", string(structs.RootKeyStateActive))
+		if err != nil {
+			return fmt.Errorf("root key metadata lookup failed: %v", err)
+		}
+
+		// Collect the keys to update first, since modifying the table while
+		// iterating over it is not safe.
+		var wasActive []*structs.RootKeyMeta
+		for {
+			raw := iter.Next()
+			if raw == nil {
+				break
+			}
+			key := raw.(*structs.RootKeyMeta)
+			if key.KeyID != rootKeyMeta.KeyID {
+				wasActive = append(wasActive, key)
+			}
+		}
+
+		for _, key := range wasActive {
+			key = key.Copy()
+			key.SetInactive()
+			key.ModifyIndex = index
+			if err := txn.Insert(TableRootKeyMeta, key); err != nil {
+				return fmt.Errorf("root key metadata insert failed: %v", err)
+			}
+		}
+	}
+
+	if err := txn.Insert(TableRootKeyMeta, rootKeyMeta); err != nil {
+		return fmt.Errorf("root key metadata insert failed: %v", err)
+	}
+	if err := txn.Insert("index", &IndexEntry{TableRootKeyMeta, index}); err != nil {
+		return fmt.Errorf("index update failed: %v", err)
+	}
+	return nil
+}
+
+// DeleteRootKeyMeta deletes a single root key's metadata. The active key and
+// any key still in use by a variable or ACL token cannot be deleted.
+func (s *StateStore) DeleteRootKeyMeta(
+	msgType structs.MessageType, index uint64, keyID string) error {
+
+	txn := s.db.WriteTxnMsgT(msgType, index)
+	defer txn.Abort()
+
+	existing, err := txn.First(TableRootKeyMeta, "id", keyID)
+	if err != nil {
+		return fmt.Errorf("root key metadata lookup failed: %v", err)
+	}
+	if existing == nil {
+		return fmt.Errorf("root key %q not found", keyID)
+	}
+	if existing.(*structs.RootKeyMeta).Active() {
+		return fmt.Errorf("root key %q is active and cannot be deleted", keyID)
+	}
+
+	if err := rootKeyInUse(txn, keyID); err != nil {
+		return err
+	}
+
+	if err := txn.Delete(TableRootKeyMeta, existing); err != nil {
+		return fmt.Errorf("root key metadata delete failed: %v", err)
+	}
+	if err := txn.Insert("index", &IndexEntry{TableRootKeyMeta, index}); err != nil {
+		return fmt.Errorf("index update failed: %v", err)
+	}
+	return txn.Commit()
+}
+
+// RootKeyInUse returns an error if any variable or ACL token is encrypted
+// with the root key.
+func (s *StateStore) RootKeyInUse(keyID string) error {
+	txn := s.db.ReadTxn()
+	return rootKeyInUse(txn, keyID)
+}
+
+func rootKeyInUse(txn ReadTxn, keyID string) error {
+	inUse, err := txn.First(TableVariables, "keyid", keyID)
+	if err != nil {
+		return fmt.Errorf("variable lookup failed: %v", err)
+	}
+	if inUse != nil {
+		return fmt.Errorf("root key %q is in use by at least one variable", keyID)
+	}
+
+	inUse, err = txn.First("acl_token", "keyid", keyID)
+	if err != nil {
+		return fmt.Errorf("acl token lookup failed: %v", err)
+	}
+	if inUse != nil {
+		return fmt.Errorf("root key %q is in use by at least one ACL token", keyID)
+	}
+	return nil
+}
+
+// RootKeyMetas returns an iterator over all root key metadata.
+func (s *StateStore) RootKeyMetas(ws memdb.WatchSet) (memdb.ResultIterator, error) {
+	txn := s.db.ReadTxn()
+
+	iter, err := txn.Get(TableRootKeyMeta, "id")
+	if err != nil {
+		return nil, fmt.Errorf("root key metadata lookup failed: %v", err)
+	}
+	ws.Add(iter.WatchCh())
+
+	return iter, nil
+}
+
+// RootKeyMetaByID returns a specific root key meta. The result will be nil if
+// no matching entry was found.
+func (s *StateStore) RootKeyMetaByID(ws memdb.WatchSet, id string) (*structs.RootKeyMeta, error) {
+	txn := s.db.ReadTxn()
+
+	watchCh, raw, err := txn.FirstWatch(TableRootKeyMeta, "id", id)
+	if err != nil {
+		return nil, fmt.Errorf("root key metadata lookup failed: %v", err)
+	}
+	ws.Add(watchCh)
+
+	if raw != nil {
+		return raw.(*structs.RootKeyMeta), nil
+	}
+	return nil, nil
+}
+
+// GetActiveRootKeyMeta returns the metadata for the currently active root
+// key. The result will be nil if the keyring has not yet been initialized.
+func (s *StateStore) GetActiveRootKeyMeta(ws memdb.WatchSet) (*structs.RootKeyMeta, error) {
+	txn := s.db.ReadTxn()
+
+	watchCh, raw, err := txn.FirstWatch(TableRootKeyMeta, "state", string(structs.RootKeyStateActive))
+	if err != nil {
+		return nil, fmt.Errorf("root key metadata lookup failed: %v", err)
+	}
+	ws.Add(watchCh)
+
+	if raw != nil {
+		return raw.(*structs.RootKeyMeta), nil
+	}
+	return nil, nil
+}
@@ -0,0 +1,104 @@
+package state
+
+import (
+	"fmt"
+	"testing"
+
+	"github.com/hashicorp/nomad/nomad/mock"
+	"github.com/hashicorp/nomad/nomad/structs"
+	"github.com/stretchr/testify/require"
+)
+
+func TestStateStore_UpsertRootKeyMeta(t *testing.T) {
+	t.Parallel()
+	testState := testStateStore(t)
+
+	key1 := structs.NewRootKeyMeta()
+	key1.SetActive()
+	require.NoError(t, testState.UpsertRootKeyMeta(structs.MsgTypeTestSetup, 10, key1))
+
+	active, err := testState.GetActiveRootKeyMeta(nil)
+	require.NoError(t, err)
+	require.Equal(t, key1.KeyID, active.KeyID)
+
+	// Activating a second key marks the first one inactive.
+	key2 := structs.NewRootKeyMeta()
+	key2.SetActive()
+	require.NoError(t, testState.UpsertRootKeyMeta(structs.MsgTypeTestSetup, 20, key2))
+
+	active, err = testState.GetActiveRootKeyMeta(nil)
+	require.NoError(t, err)
+	require.Equal(t, key2.KeyID, active.KeyID)
+
+	out, err := testState.RootKeyMetaByID(nil, key1.KeyID)
+	require.NoError(t, err)
+	require.False(t, out.Active())
+	require.Equal(t, uint64(10), out.CreateIndex)
+	require.Equal(t, uint64(20), out.ModifyIndex)
+}
+
+func TestStateStore_DeleteRootKeyMeta(t *testing.T) {
+	t.Parallel()
+	testState := testStateStore(t)
+
+	key1 := structs.NewRootKeyMeta()
+	key1.SetActive()
+	require.NoError(t, testState.UpsertRootKeyMeta(structs.MsgTypeTestSetup, 10, key1))
+
+	// The active key cannot be deleted.
+	err := testState.DeleteRootKeyMeta(structs.MsgTypeTestSetup, 20, key1.KeyID)
+	require.EqualError(t, err, fmt.Sprintf("root key %q is active and cannot be deleted", key1.KeyID))
+
+	key2 := structs.NewRootKeyMeta()
+	key2.SetActive()
+	require.NoError(t, testState.UpsertRootKeyMeta(structs.MsgTypeTestSetup, 30, key2))
+
+	// A key used by a variable cannot be deleted.
+	resp := testState.VarSet(structs.MsgTypeTestSetup, 40, &structs.VarApplyStateRequest{
+		Op:  structs.VarOpSet,
+		Var: testVariable(structs.DefaultNamespace, "foo/bar", key1.KeyID),
+	})
+	require.True(t, resp.IsOk())
+
+	err = testState.DeleteRootKeyMeta(structs.MsgTypeTestSetup, 50, key1.KeyID)
+	require.EqualError(t, err, fmt.Sprintf("root key %q is in use by at least one variable", key1.KeyID))
+
+	// Once the variable has been re-encrypted the key can be deleted.
+	resp = testState.VarSet(structs.MsgTypeTestSetup, 60, &structs.VarApplyStateRequest{
+		Op:  structs.VarOpSet,
+		Var: testVariable(structs.DefaultNamespace, "foo/bar", key2.KeyID),
+	})
+	require.True(t, resp.IsOk())
+
+	// A key used by an ACL token cannot be deleted.
+	token := mock.ACLToken()
+	token.SecretHash = structs.ACLTokenSecretHash(token.SecretID)
+	token.SecretID = ""
+	token.EncryptedSecretID = []byte("encrypted")
+	token.SecretKeyID = key1.KeyID
+	require.NoError(t, testState.UpsertACLTokens(structs.MsgTypeTestSetup, 62, []*structs.ACLToken{token}))
+
+	err = testState.DeleteRootKeyMeta(structs.MsgTypeTestSetup, 64, key1.KeyID)
+	require.EqualError(t, err, fmt.Sprintf("root key %q is in use by at least one ACL token", key1.KeyID))
+	require.EqualError(t, testState.RootKeyInUse(key1.KeyID),
+		fmt.Sprintf("root key %q is in use by at least one ACL token", key1.KeyID))
+
+	// Once the token has been re-encrypted the key can be deleted.
+	rekeyed := token.Copy()
+	rekeyed.SecretKeyID = key2.KeyID
+	require.NoError(t, testState.UpsertACLTokens(structs.MsgTypeTestSetup, 66, []*structs.ACLToken{rekeyed}))
+	require.NoError(t, testState.RootKeyInUse(key1.KeyID))
+	require.NoError(t, testState.DeleteRootKeyMeta(structs.MsgTypeTestSetup, 70, key1.KeyID))
+
+	out, err := testState.RootKeyMetaByID(nil, key1.KeyID)
+	require.NoError(t, err)
+	require.Nil(t, out)
+
+	tableIndex, err := testState.Index(TableRootKeyMeta)
+	require.NoError(t, err)
+	require.Equal(t, uint64(70), tableIndex)
+
+	// Deleting a missing key is an error.
+	err = testState.DeleteRootKeyMeta(structs.MsgTypeTestSetup, 80, key1.KeyID)
+	require.EqualError(t, err, fmt.Sprintf("root key %q not found", key1.KeyID))
+}
@@ -0,0 +1,266 @@
+package state
+
+import (
+	"fmt"
+
+	"github.com/hashicorp/go-memdb"
+	"github.com/hashicorp/nomad/nomad/structs"
+)
+
+// nodePoolInit creates the built-in node pools. This is safe to do every
+// time the state store is created, since the built-in pools cannot be
+// modified and are overwritten by the restore code path.
+func (s *StateStore) nodePoolInit() error {
+	allNodePool := &structs.NodePool{
+		Name:        structs.NodePoolAll,
+		Description: structs.NodePoolAllDescription,
+	}
+
+	defaultNodePool := &structs.NodePool{
+		Name:        structs.NodePoolDefault,
+		Description: structs.NodePoolDefaultDescription,
+	}
+
+	txn := s.db.WriteTxn(1)
+	defer txn.Abort()
+
+	for _, pool := range []*structs.NodePool{allNodePool, defaultNodePool} {
+		pool.CreateIndex = 1
+		pool.ModifyIndex = 1
+		if err := txn.Insert(TableNodePools, pool); err != nil {
+			return fmt.Errorf("inserting node pool %q failed: %v", pool.Name, err)
+		}
+	}
+	if err := txn.Insert("index", &IndexEntry{TableNodePools, 1}); err != nil {
+		return fmt.Errorf("index update failed: %v", err)
+	}
+
+	return txn.Commit()
+}
+
+// NodePools returns an iterator over all node pools.
+func (s *StateStore) NodePools(ws memdb.WatchSet, sort SortOption) (memdb.ResultIterator, error) {
+	txn := s.db.ReadTxn()
+
+	var iter memdb.ResultIterator
+	var err error
+
+	switch sort {
+	case SortReverse:
+		iter, err = txn.GetReverse(TableNodePools, "id")
+	default:
+		iter, err = txn.Get(TableNodePools, "id")
+	}
+	if err != nil {
+		return nil, fmt.Errorf("node pools lookup failed: %v", err)
+	}
+
+	ws.Add(iter.WatchCh())
+	return iter, nil
+}
+
+// NodePoolByName returns the node pool that matches the given name or nil if
+// there is no match.
+func (s *StateStore) NodePoolByName(ws memdb.WatchSet, name string) (*structs.NodePool, error) {
+	txn := s.db.ReadTxn()
+	return nodePoolByNameTxn(ws, txn, name)
+}
+
+func nodePoolByNameTxn(ws memdb.WatchSet, txn ReadTxn, name string) (*structs.NodePool, error) {
+	watchCh, existing, err := txn.FirstWatch(TableNodePools, "id", name)
+	if err != nil {
+		return nil, fmt.Errorf("node pool lookup failed: %v", err)
+	}
+	ws.Add(watchCh)
+
+	if existing == nil {
+		return nil, nil
+	}
+	return existing.(*structs.NodePool), nil
+}
+
+// NodePoolsByNamePrefix returns an iterator over all node pools that match
+// the given name prefix.
+func (s *StateStore) NodePoolsByNamePrefix(ws memdb.WatchSet, namePrefix string, sort SortOption) (memdb.ResultIterator, error) {
+	txn := s.db.ReadTxn()
+
+	var iter memdb.ResultIterator
+	var err error
+
+	switch sort {
+	case SortReverse:
+		iter, err = txn.GetReverse(TableNodePools, "id_prefix", namePrefix)
+	default:
+		iter, err = txn.Get(TableNodePools, "id_prefix", namePrefix)
+	}
+	if err != nil {
+		return nil, fmt.Errorf("node pools prefix lookup failed: %v", err)
+	}
+
+	ws.Add(iter.WatchCh())
+	return iter, nil
+}
+
+// NodesByNodePool returns an iterator over all nodes that are part of the
+// given node pool. The built-in "all" pool returns every node.
+func (s *StateStore) NodesByNodePool(ws memdb.WatchSet, pool string) (memdb.ResultIterator, error) {
+	txn := s.db.ReadTxn()
+
+	var iter memdb.ResultIterator
+	var err error
+
+	if pool == structs.NodePoolAll {
+		iter, err = txn.Get("nodes", "id")
+	} else {
+		iter, err = txn.Get("nodes", "node_pool", pool)
+	}
+	if err != nil {
+		return nil, fmt.Errorf("nodes lookup failed: %v", err)
+	}
+
+	ws.Add(iter.WatchCh())
+	return iter, nil
+}
+
+// JobsByNodePool returns an iterator over all jobs that target the given
+// node pool, across all namespaces.
+func (s *StateStore) JobsByNodePool(ws memdb.WatchSet, pool string) (memdb.ResultIterator, error) {
+	txn := s.db.ReadTxn()
+
+	iter, err := txn.Get("jobs", "node_pool", pool)
+	if err != nil {
+		return nil, fmt.Errorf("jobs lookup failed: %v", err)
+	}
+
+	ws.Add(iter.WatchCh())
+	return iter, nil
+}
+
+// UpsertNodePools inserts or updates the given set of node pools.
+func (s *StateStore) UpsertNodePools(msgType structs.MessageType, index uint64, pools []*structs.NodePool) error {
+	txn := s.db.WriteTxnMsgT(msgType, index)
+	defer txn.Abort()
+
+	for _, pool := range pools {
+		if err := upsertNodePoolTxn(txn, index, pool); err != nil {
+			return err
+		}
+	}
+
+	if err := txn.Insert("index", &IndexEntry{TableNodePools, index}); err != nil {
+		return fmt.Errorf("index update failed: %v", err)
+	}
+
+	return txn.Commit()
+}
+
+func upsertNodePoolTxn(txn *txn, index uint64, pool *structs.NodePool) error {
+	if pool == nil {
+		return nil
+	}
+
+	existing, err := txn.First(TableNodePools, "id", pool.Name)
+	if err != nil {
+		return fmt.Errorf("node pool lookup failed: %v", err)
+	}
+
+	if existing != nil {
+		// Prevent changes to built-in node pools.
+		if pool.IsBuiltIn() {
+			return fmt.Errorf("modifying node pool %q is not allowed", pool.Name)
+		}
+
+		exist := existing.(*structs.NodePool)
+		pool.CreateIndex = exist.CreateIndex
+		pool.ModifyIndex = index
+	} else {
+		pool.CreateIndex = index
+		pool.ModifyIndex = index
+	}
+
+	if err := txn.Insert(TableNodePools, pool); err != nil {
+		return fmt.Errorf("node pool insert failed: %v", err)
+	}
+	return nil
+}
+
+// fetchOrCreateNodePoolTxn returns an existing node pool with the given name
+// or creates a new one if it doesn't exist. Nodes may join a pool that was
+// never explicitly created, in which case the pool is created for them.
+func fetchOrCreateNodePoolTxn(txn *txn, index uint64, name string) (*structs.NodePool, error) {
+	pool, err := nodePoolByNameTxn(nil, txn, name)
+	if err != nil {
+		return nil, err
+	}
+
+	if pool == nil {
+		pool = &structs.NodePool{Name: name}
+		if err := upsertNodePoolTxn(txn, index, pool); err != nil {
+			return nil, err
+		}
+		if err := txn.Insert("index", &IndexEntry{TableNodePools, index}); err != nil {
+			return nil, fmt.Errorf("index update failed: %v", err)
+		}
+	}
+
+	return pool, nil
+}
+
+// DeleteNodePools removes the given set of node pools. Built-in pools and
+// pools that still have nodes or jobs cannot be deleted.
+func (s *StateStore) DeleteNodePools(msgType structs.MessageType, index uint64, names []string) error {
+	txn := s.db.WriteTxnMsgT(msgType, index)
+	defer txn.Abort()
+
+	for _, name := range names {
+		if err := deleteNodePoolTxn(txn, name); err != nil {
+			return err
+		}
+	}
+
+	if err := txn.Insert("index", &IndexEntry{TableNodePools, index}); err != nil {
+		return fmt.Errorf("index update failed: %v", err)
+	}
+
+	return txn.Commit()
+}
+
+func deleteNodePoolTxn(txn *txn, name string) error {
+	existing, err := txn.First(TableNodePools, "id", name)
+	if err != nil {
+		return fmt.Errorf("node pool lookup failed: %v", err)
+	}
+	if existing == nil {
+		return fmt.Errorf("node pool %q not found", name)
+	}
+
+	pool := existing.(*structs.NodePool)
+	if pool.IsBuiltIn() {
+		return fmt.Errorf("deleting built-in node pool %q is not allowed", pool.Name)
+	}
+
+	node, err := txn.First("nodes", "node_pool", name)
+	if err != nil {
+		return fmt.Errorf("nodes lookup failed: %v", err)
+	}
+	if node != nil {
+		return fmt.Errorf("node pool %q has at least one node %q. "+
+			"All nodes must be removed from the node pool before it can be deleted",
+			name, node.(*structs.Node).ID)
+	}
+
+	job, err := txn.First("jobs", "node_pool", name)
+	if err != nil {
+		return fmt.Errorf("jobs lookup failed: %v", err)
+	}
+	if job != nil {
+		return fmt.Errorf("node pool %q has at least one job %q. "+
+			"All jobs must be moved out of the node pool before it can be deleted",
+			name, job.(*structs.Job).ID)
+	}
+
+	if err := txn.Delete(TableNodePools, pool); err != nil {
+		return fmt.Errorf("node pool deletion failed: %v", err)
+	}
+	return nil
+}
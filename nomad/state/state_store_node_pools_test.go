@@ -0,0 +1,123 @@
+package state
+
+import (
+	"testing"
+
+	"github.com/hashicorp/nomad/nomad/mock"
+	"github.com/hashicorp/nomad/nomad/structs"
+	"github.com/stretchr/testify/require"
+)
+
+func TestStateStore_NodePools_BuiltIn(t *testing.T) {
+	t.Parallel()
+	testState := testStateStore(t)
+
+	for _, name := range []string{structs.NodePoolAll, structs.NodePoolDefault} {
+		pool, err := testState.NodePoolByName(nil, name)
+		require.NoError(t, err)
+		require.NotNil(t, pool)
+		require.True(t, pool.IsBuiltIn())
+	}
+
+	// Built-in node pools cannot be modified or deleted.
+	err := testState.UpsertNodePools(structs.MsgTypeTestSetup, 10,
+		[]*structs.NodePool{{Name: structs.NodePoolDefault, Description: "changed"}})
+	require.Error(t, err)
+	require.Contains(t, err.Error(), "not allowed")
+
+	err = testState.DeleteNodePools(structs.MsgTypeTestSetup, 10, []string{structs.NodePoolAll})
+	require.Error(t, err)
+	require.Contains(t, err.Error(), "not allowed")
+}
+
+func TestStateStore_UpsertNodePools(t *testing.T) {
+	t.Parallel()
+	testState := testStateStore(t)
+
+	pool := mock.NodePool()
+	require.NoError(t, testState.UpsertNodePools(structs.MsgTypeTestSetup, 10, []*structs.NodePool{pool}))
+
+	out, err := testState.NodePoolByName(nil, pool.Name)
+	require.NoError(t, err)
+	require.Equal(t, uint64(10), out.CreateIndex)
+	require.Equal(t, uint64(10), out.ModifyIndex)
+
+	// Updating the pool keeps its create index.
+	updated := pool.Copy()
+	updated.Description = "updated"
+	require.NoError(t, testState.UpsertNodePools(structs.MsgTypeTestSetup, 20, []*structs.NodePool{updated}))
+
+	out, err = testState.NodePoolByName(nil, pool.Name)
+	require.NoError(t, err)
+	require.Equal(t, "updated", out.Description)
+	require.Equal(t, uint64(10), out.CreateIndex)
+	require.Equal(t, uint64(20), out.ModifyIndex)
+
+	index, err := testState.Index(TableNodePools)
+	require.NoError(t, err)
+	require.Equal(t, uint64(20), index)
+
+	iter, err := testState.NodePools(nil, SortDefault)
+	require.NoError(t, err)
+	count := 0
+	for raw := iter.Next(); raw != nil; raw = iter.Next() {
+		count++
+	}
+	require.Equal(t, 3, count)
+}
+
+func TestStateStore_UpsertNode_CreatesNodePool(t *testing.T) {
+	t.Parallel()
+	testState := testStateStore(t)
+
+	node := mock.Node()
+	node.NodePool = "dev"
+	require.NoError(t, testState.UpsertNode(structs.MsgTypeTestSetup, 10, node))
+
+	pool, err := testState.NodePoolByName(nil, "dev")
+	require.NoError(t, err)
+	require.NotNil(t, pool)
+	require.Equal(t, uint64(10), pool.CreateIndex)
+
+	iter, err := testState.NodesByNodePool(nil, "dev")
+	require.NoError(t, err)
+	raw := iter.Next()
+	require.NotNil(t, raw)
+	require.Equal(t, node.ID, raw.(*structs.Node).ID)
+	require.Nil(t, iter.Next())
+}
+
+func TestStateStore_DeleteNodePools(t *testing.T) {
+	t.Parallel()
+	testState := testStateStore(t)
+
+	pool := mock.NodePool()
+	require.NoError(t, testState.UpsertNodePools(structs.MsgTypeTestSetup, 10, []*structs.NodePool{pool}))
+
+	// A pool with nodes cannot be deleted.
+	node := mock.Node()
+	node.NodePool = pool.Name
+	require.NoError(t, testState.UpsertNode(structs.MsgTypeTestSetup, 20, node))
+	err := testState.DeleteNodePools(structs.MsgTypeTestSetup, 30, []string{pool.Name})
+	require.Error(t, err)
+	require.Contains(t, err.Error(), "has at least one node")
+	require.NoError(t, testState.DeleteNode(structs.MsgTypeTestSetup, 40, []string{node.ID}))
+
+	// A pool with jobs cannot be deleted.
+	job := mock.Job()
+	job.NodePool = pool.Name
+	require.NoError(t, testState.UpsertJob(structs.MsgTypeTestSetup, 50, job))
+	err = testState.DeleteNodePools(structs.MsgTypeTestSetup, 60, []string{pool.Name})
+	require.Error(t, err)
+	require.Contains(t, err.Error(), "has at least one job")
+	require.NoError(t, testState.DeleteJob(70, job.Namespace, job.ID))
+
+	require.NoError(t, testState.DeleteNodePools(structs.MsgTypeTestSetup, 80, []string{pool.Name}))
+	out, err := testState.NodePoolByName(nil, pool.Name)
+	require.NoError(t, err)
+	require.Nil(t, out)
+
+	err = testState.DeleteNodePools(structs.MsgTypeTestSetup, 90, []string{pool.Name})
+	require.Error(t, err)
+	require.Contains(t, err.Error(), "not found")
+}
@@ -0,0 +1,122 @@
+package state
+
+import (
+	"fmt"
+
+	"github.com/hashicorp/go-memdb"
+	"github.com/hashicorp/nomad/nomad/structs"
+)
+
+// Reservations returns an iterator over all reservations.
+func (s *StateStore) Reservations(ws memdb.WatchSet) (memdb.ResultIterator, error) {
+	txn := s.db.ReadTxn()
+
+	iter, err := txn.Get(TableReservations, "id")
+	if err != nil {
+		return nil, fmt.Errorf("reservations lookup failed: %v", err)
+	}
+
+	ws.Add(iter.WatchCh())
+	return iter, nil
+}
+
+// ReservationsByNamespace returns an iterator over the reservations of the
+// given namespace whose name starts with the prefix.
+func (s *StateStore) ReservationsByNamespace(ws memdb.WatchSet, namespace, prefix string) (memdb.ResultIterator, error) {
+	txn := s.db.ReadTxn()
+
+	iter, err := txn.Get(TableReservations, "id_prefix", namespace, prefix)
+	if err != nil {
+		return nil, fmt.Errorf("reservations lookup failed: %v", err)
+	}
+
+	ws.Add(iter.WatchCh())
+	return iter, nil
+}
+
+// ReservationByName returns the reservation with the given namespace and
+// name or nil if there is no match.
+func (s *StateStore) ReservationByName(ws memdb.WatchSet, namespace, name string) (*structs.Reservation, error) {
+	txn := s.db.ReadTxn()
+
+	watchCh, existing, err := txn.FirstWatch(TableReservations, "id", namespace, name)
+	if err != nil {
+		return nil, fmt.Errorf("reservation lookup failed: %v", err)
+	}
+	ws.Add(watchCh)
+
+	if existing == nil {
+		return nil, nil
+	}
+	return existing.(*structs.Reservation), nil
+}
+
+// ReservationsByNode returns the reservations with instances placed on the
+// given node.
+func (s *StateStore) ReservationsByNode(ws memdb.WatchSet, nodeID string) ([]*structs.Reservation, error) {
+	txn := s.db.ReadTxn()
+
+	iter, err := txn.Get(TableReservations, "node", nodeID)
+	if err != nil {
+		return nil, fmt.Errorf("reservations lookup failed: %v", err)
+	}
+	ws.Add(iter.WatchCh())
+
+	var out []*structs.Reservation
+	for raw := iter.Next(); raw != nil; raw = iter.Next() {
+		out = append(out, raw.(*structs.Reservation))
+	}
+	return out, nil
+}
+
+// UpsertReservation inserts or updates the given reservation.
+func (s *StateStore) UpsertReservation(msgType structs.MessageType, index uint64, reservation *structs.Reservation) error {
+	txn := s.db.WriteTxnMsgT(msgType, index)
+	defer txn.Abort()
+
+	existing, err := txn.First(TableReservations, "id", reservation.Namespace, reservation.Name)
+	if err != nil {
+		return fmt.Errorf("reservation lookup failed: %v", err)
+	}
+
+	if existing != nil {
+		reservation.CreateIndex = existing.(*structs.Reservation).CreateIndex
+		reservation.ModifyIndex = index
+	} else {
+		reservation.CreateIndex = index
+		reservation.ModifyIndex = index
+	}
+
+	if err := txn.Insert(TableReservations, reservation); err != nil {
+		return fmt.Errorf("reservation insert failed: %v", err)
+	}
+	if err := txn.Insert("index", &IndexEntry{TableReservations, index}); err != nil {
+		return fmt.Errorf("index update failed: %v", err)
+	}
+
+	return txn.Commit()
+}
+
+// DeleteReservation removes the reservation with the given namespace and
+// name.
+func (s *StateStore) DeleteReservation(msgType structs.MessageType, index uint64, namespace, name string) error {
+	txn := s.db.WriteTxnMsgT(msgType, index)
+	defer txn.Abort()
+
+	existing, err := txn.First(TableReservations, "id", namespace, name)
+	if err != nil {
+		return fmt.Errorf("reservation lookup failed: %v", err)
+	}
+	if existing == nil {
+		return fmt.Errorf("reservation %q not found", name)
+	}
+
+	if err := txn.Delete(TableReservations, existing); err != nil {
+		return fmt.Errorf("reservation deletion failed: %v", err)
+	}
+	if err := txn.Insert("index", &IndexEntry{TableReservations, index}); err != nil {
+		return fmt.Errorf("index update failed: %v", err)
+	}
+
+	return txn.Commit()
+}
@@ -0,0 +1,71 @@
+package state
+
+import (
+	"testing"
+
+	"github.com/hashicorp/nomad/nomad/mock"
+	"github.com/hashicorp/nomad/nomad/structs"
+	"github.com/stretchr/testify/require"
+)
+
+func TestStateStore_UpsertReservation(t *testing.T) {
+	t.Parallel()
+	testState := testStateStore(t)
+
+	reservation := mock.Reservation()
+	reservation.Count = 2
+	reservation.NodeIDs = []string{"node1", "node2"}
+	require.NoError(t, testState.UpsertReservation(structs.MsgTypeTestSetup, 10, reservation))
+
+	out, err := testState.ReservationByName(nil, reservation.Namespace, reservation.Name)
+	require.NoError(t, err)
+	require.Equal(t, reservation, out)
+	require.Equal(t, uint64(10), out.CreateIndex)
+	require.Equal(t, uint64(10), out.ModifyIndex)
+
+	// Reservations are found by the nodes of their instances
+	byNode, err := testState.ReservationsByNode(nil, "node2")
+	require.NoError(t, err)
+	require.Len(t, byNode, 1)
+	require.Equal(t, reservation.Name, byNode[0].Name)
+
+	byNode, err = testState.ReservationsByNode(nil, "node3")
+	require.NoError(t, err)
+	require.Empty(t, byNode)
+
+	// Reservations are only listed in their namespace
+	iter, err := testState.ReservationsByNamespace(nil, structs.DefaultNamespace, "")
+	require.NoError(t, err)
+	require.NotNil(t, iter.Next())
+	require.Nil(t, iter.Next())
+
+	iter, err = testState.ReservationsByNamespace(nil, "other", "")
+	require.NoError(t, err)
+	require.Nil(t, iter.Next())
+
+	index, err := testState.Index(TableReservations)
+	require.NoError(t, err)
+	require.Equal(t, uint64(10), index)
+}
+
+func TestStateStore_DeleteReservation(t *testing.T) {
+	t.Parallel()
+	testState := testStateStore(t)
+
+	reservation := mock.Reservation()
+	require.NoError(t, testState.UpsertReservation(structs.MsgTypeTestSetup, 10, reservation))
+	require.NoError(t, testState.DeleteReservation(structs.MsgTypeTestSetup, 20, reservation.Namespace, reservation.Name))
+
+	out, err := testState.ReservationByName(nil, reservation.Namespace, reservation.Name)
+	require.NoError(t, err)
+	require.Nil(t, out)
+
+	index, err := testState.Index(TableReservations)
+	require.NoError(t, err)
+	require.Equal(t, uint64(20), index)
+
+	// Deleting a missing reservation fails
+	err = testState.DeleteReservation(structs.MsgTypeTestSetup, 30, reservation.Namespace, reservation.Name)
+	require.Error(t, err)
+	require.Contains(t, err.Error(), "not found")
+}
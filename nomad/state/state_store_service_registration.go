@@ -0,0 +1,277 @@
+package state
+
+import (
+	"fmt"
+
+	"github.com/hashicorp/go-memdb"
+	"github.com/hashicorp/nomad/nomad/structs"
+)
+
+// UpsertServiceRegistrations is used to insert a number of service
+// registrations into the state store. It uses a single write transaction for
+// efficiency, however, any error means no entries will be committed.
+func (s *StateStore) UpsertServiceRegistrations(
+	msgType structs.MessageType, index uint64, services []*structs.ServiceRegistration) error {
+
+	txn := s.db.WriteTxnMsgT(msgType, index)
+	defer txn.Abort()
+
+	// updated tracks whether any inserts have been made. This allows us to
+	// skip updating the index table if we do not need to.
+	var updated bool
+
+	for _, service := range services {
+		inserted, err := s.upsertServiceRegistrationTxn(index, txn, service)
+		if err != nil {
+			return err
+		}
+		if inserted {
+			updated = true
+		}
+	}
+
+	if updated {
+		if err := txn.Insert("index", &IndexEntry{TableServiceRegistrations, index}); err != nil {
+			return fmt.Errorf("index update failed: %v", err)
+		}
+	}
+
+	return txn.Commit()
+}
+
+// upsertServiceRegistrationTxn inserts or updates a single service
+// registration. The returned boolean indicates whether the object was inserted
+// into state; an unchanged registration does not trigger a write.
+func (s *StateStore) upsertServiceRegistrationTxn(
+	index uint64, txn *txn, service *structs.ServiceRegistration) (bool, error) {
+
+	existing, err := txn.First(TableServiceRegistrations, "id", service.Namespace, service.ID)
+	if err != nil {
+		return false, fmt.Errorf("service registration lookup failed: %v", err)
+	}
+
+	// Set up the indexes correctly to ensure existing indexes are maintained.
+	if existing != nil {
+		exist := existing.(*structs.ServiceRegistration)
+		if exist.Equals(service) {
+			return false, nil
+		}
+		service.CreateIndex = exist.CreateIndex
+		service.ModifyIndex = index
+	} else {
+		service.CreateIndex = index
+		service.ModifyIndex = index
+	}
+
+	if err := txn.Insert(TableServiceRegistrations, service); err != nil {
+		return false, fmt.Errorf("service registration insert failed: %v", err)
+	}
+	return true, nil
+}
+
+// DeleteServiceRegistrationByID is responsible for deleting a single service
+// registration based on it's ID and namespace. If the service registration is
+// not found within state, an error will be returned.
+func (s *StateStore) DeleteServiceRegistrationByID(
+	msgType structs.MessageType, index uint64, namespace, serviceID string) error {
+
+	txn := s.db.WriteTxnMsgT(msgType, index)
+	defer txn.Abort()
+
+	if err := s.deleteServiceRegistrationByIDTxn(index, txn, namespace, serviceID); err != nil {
+		return err
+	}
+	return txn.Commit()
+}
+
+func (s *StateStore) deleteServiceRegistrationByIDTxn(
+	index uint64, txn *txn, namespace, serviceID string) error {
+
+	// Lookup the service registration by its ID and namespace. This is a
+	// unique index and therefore there will be a maximum of one result.
+	existing, err := txn.First(TableServiceRegistrations, "id", namespace, serviceID)
+	if err != nil {
+		return fmt.Errorf("service registration lookup failed: %v", err)
+	}
+	if existing == nil {
+		return fmt.Errorf("service registration not found")
+	}
+
+	if err := txn.Delete(TableServiceRegistrations, existing); err != nil {
+		return fmt.Errorf("service registration deletion failed: %v", err)
+	}
+
+	if err := txn.Insert("index", &IndexEntry{TableServiceRegistrations, index}); err != nil {
+		return fmt.Errorf("index update failed: %v", err)
+	}
+	return nil
+}
+
+// DeleteServiceRegistrationByNodeID deletes all service registrations that
+// belong on a single node. If there are no registrations tied to the nodeID,
+// the call will noop without an error.
+func (s *StateStore) DeleteServiceRegistrationByNodeID(
+	msgType structs.MessageType, index uint64, nodeID string) error {
+
+	txn := s.db.WriteTxnMsgT(msgType, index)
+	defer txn.Abort()
+
+	if err := deleteServiceRegistrationsTxn(index, txn, "node_id", nodeID); err != nil {
+		return err
+	}
+	return txn.Commit()
+}
+
+// deleteServiceRegistrationByAllocIDTxn deletes all service registrations
+// that belong to a single allocation. It is called when the allocation reaches
+// a client terminal status and so the registrations are no longer valid.
+func (s *StateStore) deleteServiceRegistrationByAllocIDTxn(
+	txn *txn, index uint64, allocID string) error {
+	return deleteServiceRegistrationsTxn(index, txn, "alloc_id", allocID)
+}
+
+// deleteServiceRegistrationsTxn deletes all service registrations matching
+// the passed single value index. The index table is only updated when at least
+// one registration was removed.
+func deleteServiceRegistrationsTxn(index uint64, txn *txn, indexName, value string) error {
+	num, err := txn.DeleteAll(TableServiceRegistrations, indexName, value)
+	if err != nil {
+		return fmt.Errorf("deleting service registrations failed: %v", err)
+	}
+
+	if num > 0 {
+		if err := txn.Insert("index", &IndexEntry{TableServiceRegistrations, index}); err != nil {
+			return fmt.Errorf("index update failed: %v", err)
+		}
+	}
+	return nil
+}
+
+// GetServiceRegistrations returns an iterator that contains all service
+// registrations stored within state. This is primarily useful when performing
+// listings which use the namespace wildcard operator. The caller is
+// responsible for ensuring ACL access is confirmed, or filtering is performed
+// before responding.
+func (s *StateStore) GetServiceRegistrations(ws memdb.WatchSet) (memdb.ResultIterator, error) {
+	txn := s.db.ReadTxn()
+
+	// Walk the entire table.
+	iter, err := txn.Get(TableServiceRegistrations, "id")
+	if err != nil {
+		return nil, fmt.Errorf("service registration lookup failed: %v", err)
+	}
+	ws.Add(iter.WatchCh())
+	return iter, nil
+}
+
+// GetServiceRegistrationsByNamespace returns an iterator that contains all
+// registrations belonging to the provided namespace.
+func (s *StateStore) GetServiceRegistrationsByNamespace(
+	ws memdb.WatchSet, namespace string) (memdb.ResultIterator, error) {
+	txn := s.db.ReadTxn()
+
+	// Walk the entire table.
+	iter, err := txn.Get(TableServiceRegistrations, "id_prefix", namespace, "")
+	if err != nil {
+		return nil, fmt.Errorf("service registration lookup failed: %v", err)
+	}
+	ws.Add(iter.WatchCh())
+
+	return iter, nil
+}
+
+// GetServiceRegistrationByName returns an iterator that contains all service
+// registrations whose namespace and name match the input parameters. This func
+// therefore represents how to identify a single, collection of services that
+// are logically grouped together.
+func (s *StateStore) GetServiceRegistrationByName(
+	ws memdb.WatchSet, namespace, name string) (memdb.ResultIterator, error) {
+
+	txn := s.db.ReadTxn()
+
+	iter, err := txn.Get(TableServiceRegistrations, "service_name", namespace, name)
+	if err != nil {
+		return nil, fmt.Errorf("service registration lookup failed: %v", err)
+	}
+	ws.Add(iter.WatchCh())
+
+	return iter, nil
+}
+
+// GetServiceRegistrationByID returns a single registration. The registration
+// will be nil, if no matching entry was found; it is the responsibility of the
+// caller to check for this.
+func (s *StateStore) GetServiceRegistrationByID(
+	ws memdb.WatchSet, namespace, id string) (*structs.ServiceRegistration, error) {
+
+	txn := s.db.ReadTxn()
+
+	watchCh, obj, err := txn.FirstWatch(TableServiceRegistrations, "id", namespace, id)
+	if err != nil {
+		return nil, fmt.Errorf("service registration lookup failed: %v", err)
+	}
+	ws.Add(watchCh)
+
+	if obj != nil {
+		return obj.(*structs.ServiceRegistration), nil
+	}
+	return nil, nil
+}
+
+// GetServiceRegistrationsByAllocID returns an iterator containing all the
+// service registrations corresponding to a single allocation.
+func (s *StateStore) GetServiceRegistrationsByAllocID(
+	ws memdb.WatchSet, allocID string) (memdb.ResultIterator, error) {
+
+	txn := s.db.ReadTxn()
+
+	iter, err := txn.Get(TableServiceRegistrations, "alloc_id", allocID)
+	if err != nil {
+		return nil, fmt.Errorf("service registration lookup failed: %v", err)
+	}
+	ws.Add(iter.WatchCh())
+
+	return iter, nil
+}
+
+// GetServiceRegistrationsByJobID returns an iterator containing all the
+// service registrations corresponding to a single job.
+func (s *StateStore) GetServiceRegistrationsByJobID(
+	ws memdb.WatchSet, namespace, jobID string) (memdb.ResultIterator, error) {
+
+	txn := s.db.ReadTxn()
+
+	iter, err := txn.Get(TableServiceRegistrations, "job", namespace, jobID)
+	if err != nil {
+		return nil, fmt.Errorf("service registration lookup failed: %v", err)
+	}
+	ws.Add(iter.WatchCh())
+
+	return iter, nil
+}
+
+// GetServiceRegistrationsByNodeID identifies all service registrations tied to
+// the specified nodeID. This is useful for performing an in-memory lookup in
+// order to avoid calling DeleteServiceRegistrationByNodeID via a Raft message.
+func (s *StateStore) GetServiceRegistrationsByNodeID(
+	ws memdb.WatchSet, nodeID string) ([]*structs.ServiceRegistration, error) {
+
+	txn := s.db.ReadTxn()
+
+	iter, err := txn.Get(TableServiceRegistrations, "node_id", nodeID)
+	if err != nil {
+		return nil, fmt.Errorf("service registration lookup failed: %v", err)
+	}
+	ws.Add(iter.WatchCh())
+
+	var result []*structs.ServiceRegistration
+	for {
+		raw := iter.Next()
+		if raw == nil {
+			break
+		}
+		result = append(result, raw.(*structs.ServiceRegistration))
+	}
+
+	return result, nil
+}
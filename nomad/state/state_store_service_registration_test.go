@@ -0,0 +1,234 @@
+package state
+
+import (
+	"testing"
+
+	"github.com/hashicorp/go-memdb"
+	"github.com/hashicorp/nomad/nomad/mock"
+	"github.com/hashicorp/nomad/nomad/structs"
+	"github.com/stretchr/testify/require"
+)
+
+func TestStateStore_UpsertServiceRegistrations(t *testing.T) {
+	t.Parallel()
+	testState := testStateStore(t)
+
+	// SubTest Marker: This ensures new service registrations are inserted as
+	// expected with their correct indexes, along with an update to the index
+	// table.
+	services := mock.ServiceRegistrations()
+	insertIndex := uint64(20)
+
+	// Perform the initial upsert of service registrations.
+	err := testState.UpsertServiceRegistrations(structs.MsgTypeTestSetup, insertIndex, services)
+	require.NoError(t, err)
+
+	// Check that the index for the table was modified as expected.
+	initialIndex, err := testState.Index(TableServiceRegistrations)
+	require.NoError(t, err)
+	require.Equal(t, insertIndex, initialIndex)
+
+	// List all the service registrations in the table, so we can perform a
+	// number of tests on the return array.
+	ws := memdb.NewWatchSet()
+	iter, err := testState.GetServiceRegistrations(ws)
+	require.NoError(t, err)
+
+	// Count how many table entries we have, to ensure it is the expected
+	// number.
+	var count int
+	for raw := iter.Next(); raw != nil; raw = iter.Next() {
+		count++
+
+		// Ensure the create and modify indexes are populated correctly.
+		serviceReg := raw.(*structs.ServiceRegistration)
+		require.Equal(t, insertIndex, serviceReg.CreateIndex, "incorrect create index", serviceReg.ID)
+		require.Equal(t, insertIndex, serviceReg.ModifyIndex, "incorrect modify index", serviceReg.ID)
+	}
+	require.Equal(t, 2, count, "incorrect number of service registrations found")
+
+	// SubTest Marker: This section attempts to upsert the exact same service
+	// registrations without any modification. In this case, the index table
+	// should not be updated, indicating no write actually happened due to
+	// equality checking.
+	reInsertIndex := uint64(30)
+	require.NoError(t, testState.UpsertServiceRegistrations(structs.MsgTypeTestSetup, reInsertIndex, services))
+	reInsertActualIndex, err := testState.Index(TableServiceRegistrations)
+	require.NoError(t, err)
+	require.Equal(t, insertIndex, reInsertActualIndex, "index should not have changed")
+
+	// SubTest Marker: This section modifies a single one of the previously
+	// inserted service registrations and performs an upsert. This ensures the
+	// index table is modified correctly and that each service registration is
+	// updated, or not, as expected.
+	service1Update := services[0].Copy()
+	service1Update.Tags = []string{"modified"}
+	services1Updated := []*structs.ServiceRegistration{service1Update}
+
+	updateIndex := uint64(40)
+	require.NoError(t, testState.UpsertServiceRegistrations(structs.MsgTypeTestSetup, updateIndex, services1Updated))
+
+	// Check that the index for the table was modified as expected.
+	updateActualIndex, err := testState.Index(TableServiceRegistrations)
+	require.NoError(t, err)
+	require.Equal(t, updateIndex, updateActualIndex, "index should have changed")
+
+	// Get the service registrations from the table.
+	iter, err = testState.GetServiceRegistrations(ws)
+	require.NoError(t, err)
+
+	// Iterate all the stored registrations and assert they are as expected.
+	for raw := iter.Next(); raw != nil; raw = iter.Next() {
+		serviceReg := raw.(*structs.ServiceRegistration)
+
+		var expectedModifyIndex uint64
+
+		switch serviceReg.ID {
+		case service1Update.ID:
+			expectedModifyIndex = updateIndex
+		case services[1].ID:
+			expectedModifyIndex = insertIndex
+		default:
+			t.Errorf("unknown service registration found: %s", serviceReg.ID)
+			continue
+		}
+		require.Equal(t, insertIndex, serviceReg.CreateIndex, "incorrect create index", serviceReg.ID)
+		require.Equal(t, expectedModifyIndex, serviceReg.ModifyIndex, "incorrect modify index", serviceReg.ID)
+	}
+}
+
+func TestStateStore_DeleteServiceRegistrationByID(t *testing.T) {
+	t.Parallel()
+	testState := testStateStore(t)
+
+	// Generate some test services that we will use and modify throughout.
+	services := mock.ServiceRegistrations()
+
+	// Attempt to delete a service registration that does not exist.
+	err := testState.DeleteServiceRegistrationByID(
+		structs.MsgTypeTestSetup, 10, services[0].Namespace, services[0].ID)
+	require.EqualError(t, err, "service registration not found")
+
+	// Upsert the service registrations.
+	require.NoError(t, testState.UpsertServiceRegistrations(structs.MsgTypeTestSetup, 10, services))
+
+	// Delete the first service.
+	require.NoError(t, testState.DeleteServiceRegistrationByID(
+		structs.MsgTypeTestSetup, 20, services[0].Namespace, services[0].ID))
+
+	// Check that the index for the table was modified as expected.
+	index, err := testState.Index(TableServiceRegistrations)
+	require.NoError(t, err)
+	require.Equal(t, uint64(20), index)
+
+	// Ensure only the second service remains.
+	ws := memdb.NewWatchSet()
+	out, err := testState.GetServiceRegistrationByID(ws, services[0].Namespace, services[0].ID)
+	require.NoError(t, err)
+	require.Nil(t, out)
+
+	out, err = testState.GetServiceRegistrationByID(ws, services[1].Namespace, services[1].ID)
+	require.NoError(t, err)
+	require.Equal(t, services[1], out)
+}
+
+func TestStateStore_DeleteServiceRegistrationByNodeID(t *testing.T) {
+	t.Parallel()
+	testState := testStateStore(t)
+
+	// Generate some test services that we will use and modify throughout.
+	services := mock.ServiceRegistrations()
+
+	// Attempt to delete a set of service registrations for a node that has
+	// none. This should noop without error.
+	require.NoError(t, testState.DeleteServiceRegistrationByNodeID(
+		structs.MsgTypeTestSetup, 10, services[0].NodeID))
+
+	// Upsert the service registrations.
+	require.NoError(t, testState.UpsertServiceRegistrations(structs.MsgTypeTestSetup, 20, services))
+
+	// Delete the registrations of the first node.
+	require.NoError(t, testState.DeleteServiceRegistrationByNodeID(
+		structs.MsgTypeTestSetup, 30, services[0].NodeID))
+
+	ws := memdb.NewWatchSet()
+	out, err := testState.GetServiceRegistrationsByNodeID(ws, services[0].NodeID)
+	require.NoError(t, err)
+	require.Empty(t, out)
+
+	out, err = testState.GetServiceRegistrationsByNodeID(ws, services[1].NodeID)
+	require.NoError(t, err)
+	require.Len(t, out, 1)
+
+	index, err := testState.Index(TableServiceRegistrations)
+	require.NoError(t, err)
+	require.Equal(t, uint64(30), index)
+}
+
+func TestStateStore_ServiceRegistrations_AllocTerminal(t *testing.T) {
+	t.Parallel()
+	testState := testStateStore(t)
+
+	// Create an allocation and a service registration which belongs to it.
+	alloc := mock.Alloc()
+	require.NoError(t, testState.UpsertJob(structs.MsgTypeTestSetup, 10, alloc.Job))
+	require.NoError(t, testState.UpsertAllocs(structs.MsgTypeTestSetup, 20, []*structs.Allocation{alloc}))
+
+	service := mock.ServiceRegistrations()[0]
+	service.AllocID = alloc.ID
+	service.JobID = alloc.JobID
+	service.Namespace = alloc.Namespace
+	require.NoError(t, testState.UpsertServiceRegistrations(
+		structs.MsgTypeTestSetup, 30, []*structs.ServiceRegistration{service}))
+
+	// Update the allocation to a running client status, which should not
+	// remove the registration.
+	update := alloc.Copy()
+	update.ClientStatus = structs.AllocClientStatusRunning
+	require.NoError(t, testState.UpdateAllocsFromClient(
+		structs.MsgTypeTestSetup, 40, []*structs.Allocation{update}))
+
+	ws := memdb.NewWatchSet()
+	iter, err := testState.GetServiceRegistrationsByAllocID(ws, alloc.ID)
+	require.NoError(t, err)
+	require.NotNil(t, iter.Next())
+
+	// Mark the allocation as complete, which should remove the registration.
+	update = alloc.Copy()
+	update.ClientStatus = structs.AllocClientStatusComplete
+	require.NoError(t, testState.UpdateAllocsFromClient(
+		structs.MsgTypeTestSetup, 50, []*structs.Allocation{update}))
+
+	iter, err = testState.GetServiceRegistrationsByAllocID(ws, alloc.ID)
+	require.NoError(t, err)
+	require.Nil(t, iter.Next())
+
+	index, err := testState.Index(TableServiceRegistrations)
+	require.NoError(t, err)
+	require.Equal(t, uint64(50), index)
+}
+
+func TestStateStore_GetServiceRegistrationByName(t *testing.T) {
+	t.Parallel()
+	testState := testStateStore(t)
+
+	services := mock.ServiceRegistrations()
+	require.NoError(t, testState.UpsertServiceRegistrations(structs.MsgTypeTestSetup, 10, services))
+
+	ws := memdb.NewWatchSet()
+
+	// Perform a lookup using the correct namespace and name.
+	iter, err := testState.GetServiceRegistrationByName(ws, services[0].Namespace, services[0].ServiceName)
+	require.NoError(t, err)
+
+	var found []*structs.ServiceRegistration
+	for raw := iter.Next(); raw != nil; raw = iter.Next() {
+		found = append(found, raw.(*structs.ServiceRegistration))
+	}
+	require.Equal(t, []*structs.ServiceRegistration{services[0]}, found)
+
+	// Perform a lookup using the wrong namespace, which should find nothing.
+	iter, err = testState.GetServiceRegistrationByName(ws, services[1].Namespace, services[0].ServiceName)
+	require.NoError(t, err)
+	require.Nil(t, iter.Next())
+}
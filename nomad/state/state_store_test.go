@@ -715,7 +715,7 @@ func TestStateStore_DeploymentsByIDPrefix(t *testing.T) {
 
 	// Create a watchset so we can test that getters don't cause it to fire
 	ws := memdb.NewWatchSet()
-	iter, err := state.DeploymentsByIDPrefix(ws, deploy.Namespace, deploy.ID)
+	iter, err := state.DeploymentsByIDPrefix(ws, deploy.Namespace, deploy.ID, SortDefault)
 	if err != nil {
 		t.Fatalf("err: %v", err)
 	}
@@ -742,7 +742,7 @@ func TestStateStore_DeploymentsByIDPrefix(t *testing.T) {
 		t.Fatalf("bad")
 	}
 
-	iter, err = state.DeploymentsByIDPrefix(ws, deploy.Namespace, "11")
+	iter, err = state.DeploymentsByIDPrefix(ws, deploy.Namespace, "11", SortDefault)
 	if err != nil {
 		t.Fatalf("err: %v", err)
 	}
@@ -764,7 +764,7 @@ func TestStateStore_DeploymentsByIDPrefix(t *testing.T) {
 	}
 
 	ws = memdb.NewWatchSet()
-	iter, err = state.DeploymentsByIDPrefix(ws, deploy.Namespace, "11")
+	iter, err = state.DeploymentsByIDPrefix(ws, deploy.Namespace, "11", SortDefault)
 	if err != nil {
 		t.Fatalf("err: %v", err)
 	}
@@ -774,7 +774,7 @@ func TestStateStore_DeploymentsByIDPrefix(t *testing.T) {
 		t.Fatalf("err: %v", err)
 	}
 
-	iter, err = state.DeploymentsByIDPrefix(ws, deploy.Namespace, "1111")
+	iter, err = state.DeploymentsByIDPrefix(ws, deploy.Namespace, "1111", SortDefault)
 	if err != nil {
 		t.Fatalf("err: %v", err)
 	}
@@ -1284,7 +1284,7 @@ func TestStateStore_NodesByIDPrefix(t *testing.T) {
 
 	// Create a watchset so we can test that getters don't cause it to fire
 	ws := memdb.NewWatchSet()
-	iter, err := state.NodesByIDPrefix(ws, node.ID)
+	iter, err := state.NodesByIDPrefix(ws, node.ID, SortDefault)
 	if err != nil {
 		t.Fatalf("err: %v", err)
 	}
@@ -1311,7 +1311,7 @@ func TestStateStore_NodesByIDPrefix(t *testing.T) {
 		t.Fatalf("bad")
 	}
 
-	iter, err = state.NodesByIDPrefix(ws, "11")
+	iter, err = state.NodesByIDPrefix(ws, "11", SortDefault)
 	if err != nil {
 		t.Fatalf("err: %v", err)
 	}
@@ -1333,7 +1333,7 @@ func TestStateStore_NodesByIDPrefix(t *testing.T) {
 	}
 
 	ws = memdb.NewWatchSet()
-	iter, err = state.NodesByIDPrefix(ws, "11")
+	iter, err = state.NodesByIDPrefix(ws, "11", SortDefault)
 	if err != nil {
 		t.Fatalf("err: %v", err)
 	}
@@ -1343,7 +1343,7 @@ func TestStateStore_NodesByIDPrefix(t *testing.T) {
 		t.Fatalf("err: %v", err)
 	}
 
-	iter, err = state.NodesByIDPrefix(ws, "1111")
+	iter, err = state.NodesByIDPrefix(ws, "1111", SortDefault)
 	if err != nil {
 		t.Fatalf("err: %v", err)
 	}
@@ -2062,7 +2062,7 @@ func TestStateStore_Jobs(t *testing.T) {
 	}
 
 	ws := memdb.NewWatchSet()
-	iter, err := state.Jobs(ws)
+	iter, err := state.Jobs(ws, SortDefault)
 	if err != nil {
 		t.Fatalf("err: %v", err)
 	}
@@ -2142,7 +2142,7 @@ func TestStateStore_JobsByIDPrefix(t *testing.T) {
 	}
 
 	ws := memdb.NewWatchSet()
-	iter, err := state.JobsByIDPrefix(ws, job.Namespace, job.ID)
+	iter, err := state.JobsByIDPrefix(ws, job.Namespace, job.ID, SortDefault)
 	if err != nil {
 		t.Fatalf("err: %v", err)
 	}
@@ -2164,7 +2164,7 @@ func TestStateStore_JobsByIDPrefix(t *testing.T) {
 		t.Fatalf("err: %v", err)
 	}
 
-	iter, err = state.JobsByIDPrefix(ws, job.Namespace, "re")
+	iter, err = state.JobsByIDPrefix(ws, job.Namespace, "re", SortDefault)
 	if err != nil {
 		t.Fatalf("err: %v", err)
 	}
@@ -2189,7 +2189,7 @@ func TestStateStore_JobsByIDPrefix(t *testing.T) {
 	}
 
 	ws = memdb.NewWatchSet()
-	iter, err = state.JobsByIDPrefix(ws, job.Namespace, "r")
+	iter, err = state.JobsByIDPrefix(ws, job.Namespace, "r", SortDefault)
 	if err != nil {
 		t.Fatalf("err: %v", err)
 	}
@@ -2199,7 +2199,7 @@ func TestStateStore_JobsByIDPrefix(t *testing.T) {
 		t.Fatalf("err: %v", err)
 	}
 
-	iter, err = state.JobsByIDPrefix(ws, job.Namespace, "ri")
+	iter, err = state.JobsByIDPrefix(ws, job.Namespace, "ri", SortDefault)
 	if err != nil {
 		t.Fatalf("err: %v", err)
 	}
@@ -4369,7 +4369,7 @@ func TestStateStore_Evals(t *testing.T) {
 	}
 
 	ws := memdb.NewWatchSet()
-	iter, err := state.Evals(ws)
+	iter, err := state.Evals(ws, SortDefault)
 	if err != nil {
 		t.Fatalf("err: %v", err)
 	}
@@ -4424,7 +4424,7 @@ func TestStateStore_EvalsByIDPrefix(t *testing.T) {
 	}
 
 	ws := memdb.NewWatchSet()
-	iter, err := state.EvalsByIDPrefix(ws, structs.DefaultNamespace, "aaaa")
+	iter, err := state.EvalsByIDPrefix(ws, structs.DefaultNamespace, "aaaa", SortDefault)
 	if err != nil {
 		t.Fatalf("err: %v", err)
 	}
@@ -4454,7 +4454,7 @@ func TestStateStore_EvalsByIDPrefix(t *testing.T) {
 		}
 	}
 
-	iter, err = state.EvalsByIDPrefix(ws, structs.DefaultNamespace, "b-a7bfb")
+	iter, err = state.EvalsByIDPrefix(ws, structs.DefaultNamespace, "b-a7bfb", SortDefault)
 	if err != nil {
 		t.Fatalf("err: %v", err)
 	}
@@ -5314,6 +5314,131 @@ func TestStateStore_UpdateAllocDesiredTransition(t *testing.T) {
 	require.Nil(state.UpdateAllocsDesiredTransitions(structs.MsgTypeTestSetup, 1003, m, evals))
 }
 
+func TestStateStore_JobDependenciesMet(t *testing.T) {
+	t.Parallel()
+
+	state := testStateStore(t)
+
+	upstream := mock.BatchJob()
+	upstream.TaskGroups[0].Count = 1
+	require.NoError(t, state.UpsertJob(structs.MsgTypeTestSetup, 1000, upstream))
+
+	job := mock.BatchJob()
+	job.DependsOn = []*structs.JobDependency{{
+		JobID:     upstream.ID,
+		Condition: structs.JobDependencyConditionComplete,
+	}}
+
+	// The upstream job hasn't run yet
+	met, reason, err := state.JobDependenciesMet(nil, job)
+	require.NoError(t, err)
+	require.False(t, met)
+	require.Contains(t, reason, upstream.ID)
+
+	// Complete the allocation of the upstream job
+	alloc := mock.Alloc()
+	alloc.Job = upstream
+	alloc.JobID = upstream.ID
+	alloc.TaskGroup = upstream.TaskGroups[0].Name
+	require.NoError(t, state.UpsertAllocs(structs.MsgTypeTestSetup, 1001, []*structs.Allocation{alloc}))
+
+	update := alloc.Copy()
+	update.ClientStatus = structs.AllocClientStatusComplete
+	require.NoError(t, state.UpdateAllocsFromClient(structs.MsgTypeTestSetup, 1002, []*structs.Allocation{update}))
+
+	met, _, err = state.JobDependenciesMet(nil, job)
+	require.NoError(t, err)
+	require.True(t, met)
+
+	// The upstream job didn't fail
+	job.DependsOn[0].Condition = structs.JobDependencyConditionFailed
+	met, _, err = state.JobDependenciesMet(nil, job)
+	require.NoError(t, err)
+	require.False(t, met)
+
+	// Dependencies on missing jobs are never met
+	job.DependsOn[0].JobID = "missing"
+	met, reason, err = state.JobDependenciesMet(nil, job)
+	require.NoError(t, err)
+	require.False(t, met)
+	require.Contains(t, reason, "job not found")
+}
+
+func TestStateStore_JobDependenciesMet_Children(t *testing.T) {
+	t.Parallel()
+
+	state := testStateStore(t)
+
+	parent := mock.BatchJob()
+	parent.ParameterizedJob = &structs.ParameterizedJobConfig{}
+	require.NoError(t, state.UpsertJob(structs.MsgTypeTestSetup, 1000, parent))
+
+	job := mock.BatchJob()
+	job.DependsOn = []*structs.JobDependency{{
+		JobID:     parent.ID,
+		Condition: structs.JobDependencyConditionDead,
+	}}
+
+	// Parameterized jobs without children don't meet dependencies
+	met, _, err := state.JobDependenciesMet(nil, job)
+	require.NoError(t, err)
+	require.False(t, met)
+
+	// Dispatch a child and fail its allocation
+	child := parent.Copy()
+	child.ID = structs.DispatchedID(parent.ID, time.Now())
+	child.ParentID = parent.ID
+	child.Dispatched = true
+	require.NoError(t, state.UpsertJob(structs.MsgTypeTestSetup, 1001, child))
+
+	alloc := mock.Alloc()
+	alloc.Job = child
+	alloc.JobID = child.ID
+	alloc.TaskGroup = child.TaskGroups[0].Name
+	require.NoError(t, state.UpsertAllocs(structs.MsgTypeTestSetup, 1002, []*structs.Allocation{alloc}))
+
+	met, _, err = state.JobDependenciesMet(nil, job)
+	require.NoError(t, err)
+	require.False(t, met)
+
+	update := alloc.Copy()
+	update.ClientStatus = structs.AllocClientStatusFailed
+	require.NoError(t, state.UpdateAllocsFromClient(structs.MsgTypeTestSetup, 1003, []*structs.Allocation{update}))
+
+	met, _, err = state.JobDependenciesMet(nil, job)
+	require.NoError(t, err)
+	require.True(t, met)
+
+	// Dispatch a second child that is still pending
+	child2 := parent.Copy()
+	child2.ID = structs.DispatchedID(parent.ID, time.Now().Add(time.Second))
+	child2.ParentID = parent.ID
+	child2.Dispatched = true
+	require.NoError(t, state.UpsertJob(structs.MsgTypeTestSetup, 1004, child2))
+
+	met, _, err = state.JobDependenciesMet(nil, job)
+	require.NoError(t, err)
+	require.False(t, met)
+
+	// Selecting a child only waits on that child
+	job.DependsOn[0].Child = child.ID
+	met, _, err = state.JobDependenciesMet(nil, job)
+	require.NoError(t, err)
+	require.True(t, met)
+
+	job.DependsOn[0].Child = child2.ID
+	met, reason, err := state.JobDependenciesMet(nil, job)
+	require.NoError(t, err)
+	require.False(t, met)
+	require.Contains(t, reason, child2.ID)
+
+	// Unknown children don't meet dependencies
+	job.DependsOn[0].Child = parent.ID + "/dispatch-unknown"
+	met, _, err = state.JobDependenciesMet(nil, job)
+	require.NoError(t, err)
+	require.False(t, met)
+}
+
 func TestStateStore_JobSummary(t *testing.T) {
 	t.Parallel()
 
@@ -5944,7 +6069,7 @@ func TestStateStore_AllocsByIDPrefix(t *testing.T) {
 	}
 
 	ws := memdb.NewWatchSet()
-	iter, err := state.AllocsByIDPrefix(ws, structs.DefaultNamespace, "aaaa")
+	iter, err := state.AllocsByIDPrefix(ws, structs.DefaultNamespace, "aaaa", SortDefault)
 	if err != nil {
 		t.Fatalf("err: %v", err)
 	}
@@ -5974,7 +6099,7 @@ func TestStateStore_AllocsByIDPrefix(t *testing.T) {
 		}
 	}
 
-	iter, err = state.AllocsByIDPrefix(ws, structs.DefaultNamespace, "b-a7bfb")
+	iter, err = state.AllocsByIDPrefix(ws, structs.DefaultNamespace, "b-a7bfb", SortDefault)
 	if err != nil {
 		t.Fatalf("err: %v", err)
 	}
@@ -6009,7 +6134,7 @@ func TestStateStore_Allocs(t *testing.T) {
 	}
 
 	ws := memdb.NewWatchSet()
-	iter, err := state.Allocs(ws)
+	iter, err := state.Allocs(ws, SortDefault)
 	if err != nil {
 		t.Fatalf("err: %v", err)
 	}
@@ -6057,7 +6182,7 @@ func TestStateStore_Allocs_PrevAlloc(t *testing.T) {
 	require.Nil(err)
 
 	ws := memdb.NewWatchSet()
-	iter, err := state.Allocs(ws)
+	iter, err := state.Allocs(ws, SortDefault)
 	require.Nil(err)
 
 	var out []*structs.Allocation
@@ -8174,7 +8299,7 @@ func TestStateStore_BootstrapACLTokens(t *testing.T) {
 		t.Fatalf("expected error")
 	}
 
-	iter, err := state.ACLTokens(nil)
+	iter, err := state.ACLTokens(nil, SortDefault)
 	if err != nil {
 		t.Fatalf("err: %v", err)
 	}
@@ -8268,7 +8393,7 @@ func TestStateStore_UpsertACLTokens(t *testing.T) {
 	assert.Equal(t, nil, err)
 	assert.Equal(t, tk2, out)
 
-	iter, err := state.ACLTokens(ws)
+	iter, err := state.ACLTokens(ws, SortDefault)
 	if err != nil {
 		t.Fatalf("err: %v", err)
 	}
@@ -8335,7 +8460,7 @@ func TestStateStore_DeleteACLTokens(t *testing.T) {
 		t.Fatalf("bad: %#v", out)
 	}
 
-	iter, err := state.ACLTokens(ws)
+	iter, err := state.ACLTokens(ws, SortDefault)
 	if err != nil {
 		t.Fatalf("err: %v", err)
 	}
@@ -8390,7 +8515,7 @@ func TestStateStore_ACLTokenByAccessorIDPrefix(t *testing.T) {
 	}
 
 	// Scan by prefix
-	iter, err := state.ACLTokenByAccessorIDPrefix(nil, "aa")
+	iter, err := state.ACLTokenByAccessorIDPrefix(nil, "aa", SortDefault)
 	if err != nil {
 		t.Fatalf("err: %v", err)
 	}
@@ -8454,7 +8579,7 @@ func TestStateStore_ACLTokensByGlobal(t *testing.T) {
 		t.Fatalf("err: %v", err)
 	}
 
-	iter, err := state.ACLTokensByGlobal(nil, true)
+	iter, err := state.ACLTokensByGlobal(nil, true, SortDefault)
 	if err != nil {
 		t.Fatalf("err: %v", err)
 	}
@@ -8728,7 +8853,7 @@ func TestStateStore_UpsertScalingPolicy(t *testing.T) {
 	policy2 := mock.ScalingPolicy()
 
 	wsAll := memdb.NewWatchSet()
-	all, err := state.ScalingPolicies(wsAll)
+	all, err := state.ScalingPolicies(wsAll, SortDefault)
 	require.NoError(err)
 	require.Nil(all.Next())
 
@@ -8757,7 +8882,7 @@ func TestStateStore_UpsertScalingPolicy(t *testing.T) {
 
 	// Ensure we see both policies
 	countPolicies := func() (n int, err error) {
-		iter, err := state.ScalingPolicies(ws)
+		iter, err := state.ScalingPolicies(ws, SortDefault)
 		if err != nil {
 			return
 		}
@@ -8813,12 +8938,12 @@ func TestStateStore_UpsertScalingPolicy_Namespace(t *testing.T) {
 	policy2.Target[structs.ScalingTargetNamespace] = otherNamespace
 
 	ws1 := memdb.NewWatchSet()
-	iter, err := state.ScalingPoliciesByNamespace(ws1, structs.DefaultNamespace, "")
+	iter, err := state.ScalingPoliciesByNamespace(ws1, structs.DefaultNamespace, "", SortDefault)
 	require.NoError(err)
 	require.Nil(iter.Next())
 
 	ws2 := memdb.NewWatchSet()
-	iter, err = state.ScalingPoliciesByNamespace(ws2, otherNamespace, "")
+	iter, err = state.ScalingPoliciesByNamespace(ws2, otherNamespace, "", SortDefault)
 	require.NoError(err)
 	require.Nil(iter.Next())
 
@@ -8827,7 +8952,7 @@ func TestStateStore_UpsertScalingPolicy_Namespace(t *testing.T) {
 	require.True(watchFired(ws1))
 	require.True(watchFired(ws2))
 
-	iter, err = state.ScalingPoliciesByNamespace(nil, structs.DefaultNamespace, "")
+	iter, err = state.ScalingPoliciesByNamespace(nil, structs.DefaultNamespace, "", SortDefault)
 	require.NoError(err)
 	policiesInDefaultNamespace := []string{}
 	for {
@@ -8839,7 +8964,7 @@ func TestStateStore_UpsertScalingPolicy_Namespace(t *testing.T) {
 	}
 	require.ElementsMatch([]string{policy.ID}, policiesInDefaultNamespace)
 
-	iter, err = state.ScalingPoliciesByNamespace(nil, otherNamespace, "")
+	iter, err = state.ScalingPoliciesByNamespace(nil, otherNamespace, "", SortDefault)
 	require.NoError(err)
 	policiesInOtherNamespace := []string{}
 	for {
@@ -8865,12 +8990,12 @@ func TestStateStore_UpsertScalingPolicy_Namespace_PrefixBug(t *testing.T) {
 	policy2.Target[structs.ScalingTargetNamespace] = ns2
 
 	ws1 := memdb.NewWatchSet()
-	iter, err := state.ScalingPoliciesByNamespace(ws1, ns1, "")
+	iter, err := state.ScalingPoliciesByNamespace(ws1, ns1, "", SortDefault)
 	require.NoError(err)
 	require.Nil(iter.Next())
 
 	ws2 := memdb.NewWatchSet()
-	iter, err = state.ScalingPoliciesByNamespace(ws2, ns2, "")
+	iter, err = state.ScalingPoliciesByNamespace(ws2, ns2, "", SortDefault)
 	require.NoError(err)
 	require.Nil(iter.Next())
 
@@ -8879,7 +9004,7 @@ func TestStateStore_UpsertScalingPolicy_Namespace_PrefixBug(t *testing.T) {
 	require.True(watchFired(ws1))
 	require.True(watchFired(ws2))
 
-	iter, err = state.ScalingPoliciesByNamespace(nil, ns1, "")
+	iter, err = state.ScalingPoliciesByNamespace(nil, ns1, "", SortDefault)
 	require.NoError(err)
 	policiesInNS1 := []string{}
 	for {
@@ -8891,7 +9016,7 @@ func TestStateStore_UpsertScalingPolicy_Namespace_PrefixBug(t *testing.T) {
 	}
 	require.ElementsMatch([]string{policy1.ID}, policiesInNS1)
 
-	iter, err = state.ScalingPoliciesByNamespace(nil, ns2, "")
+	iter, err = state.ScalingPoliciesByNamespace(nil, ns2, "", SortDefault)
 	require.NoError(err)
 	policiesInNS2 := []string{}
 	for {
@@ -9033,7 +9158,7 @@ func TestStateStore_DeleteScalingPolicies(t *testing.T) {
 	require.Nil(out)
 
 	// Ensure we see both policies
-	iter, err := state.ScalingPoliciesByNamespace(ws, policy.Target[structs.ScalingTargetNamespace], "")
+	iter, err := state.ScalingPoliciesByNamespace(ws, policy.Target[structs.ScalingTargetNamespace], "", SortDefault)
 	require.NoError(err)
 	count := 0
 	for {
@@ -9074,7 +9199,7 @@ func TestStateStore_StopJob_DeleteScalingPolicies(t *testing.T) {
 	require.NoError(err)
 	require.NotNil(out)
 	wsList := memdb.NewWatchSet()
-	_, err = state.ScalingPolicies(wsList)
+	_, err = state.ScalingPolicies(wsList, SortDefault)
 	require.NoError(err)
 
 	// Stop the job
@@ -9110,7 +9235,7 @@ func TestStateStore_UnstopJob_UpsertScalingPolicies(t *testing.T) {
 
 	// establish watcher, verify there are no scaling policies yet
 	ws := memdb.NewWatchSet()
-	list, err := state.ScalingPolicies(ws)
+	list, err := state.ScalingPolicies(ws, SortDefault)
 	require.NoError(err)
 	require.Nil(list.Next())
 
@@ -9118,13 +9243,13 @@ func TestStateStore_UnstopJob_UpsertScalingPolicies(t *testing.T) {
 	err = state.UpsertJob(structs.MsgTypeTestSetup, 1000, job)
 	require.NoError(err)
 	require.True(watchFired(ws))
-	list, err = state.ScalingPolicies(ws)
+	list, err = state.ScalingPolicies(ws, SortDefault)
 	require.NoError(err)
 	require.NotNil(list.Next())
 
 	// Establish a new watchset
 	ws = memdb.NewWatchSet()
-	_, err = state.ScalingPolicies(ws)
+	_, err = state.ScalingPolicies(ws, SortDefault)
 	require.NoError(err)
 	// Unstop this job, say you'll run it again...
 	job.Stop = false
@@ -9428,6 +9553,49 @@ func TestStateStore_ScalingPoliciesByJob(t *testing.T) {
 	require.Equal(expect, found)
 }
 
+func TestStateStore_ScalingPoliciesByJobSorted(t *testing.T) {
+	t.Parallel()
+
+	state := testStateStore(t)
+	job := mock.Job()
+	var policies []*structs.ScalingPolicy
+	for i, typ := range []string{"horizontal", "vertical-cpu", "vertical-mem"} {
+		p := mock.ScalingPolicy()
+		p.Type = typ
+		p.TargetTaskGroup(job, job.TaskGroups[0])
+		p.Target[structs.ScalingTargetGroup] = fmt.Sprintf("group-%d", i)
+		policies = append(policies, p)
+	}
+	other := mock.ScalingPolicy()
+	require.NoError(t, state.UpsertScalingPolicies(1000, append(policies, other)))
+
+	ids := func(iter memdb.ResultIterator) []string {
+		var out []string
+		for raw := iter.Next(); raw != nil; raw = iter.Next() {
+			out = append(out, raw.(*structs.ScalingPolicy).ID)
+		}
+		return out
+	}
+
+	// Policies of the job are ordered by ID
+	expected := []string{policies[0].ID, policies[1].ID, policies[2].ID}
+	sort.Strings(expected)
+	iter, err := state.ScalingPoliciesByJobSorted(nil, job.Namespace, job.ID, "", SortDefault)
+	require.NoError(t, err)
+	require.Equal(t, expected, ids(iter))
+
+	iter, err = state.ScalingPoliciesByJobSorted(nil, job.Namespace, job.ID, "", SortReverse)
+	require.NoError(t, err)
+	require.Equal(t, []string{expected[2], expected[1], expected[0]}, ids(iter))
+
+	// The type is matched by prefix
+	expected = []string{policies[1].ID, policies[2].ID}
+	sort.Strings(expected)
+	iter, err = state.ScalingPoliciesByJobSorted(nil, job.Namespace, job.ID, "vertical", SortDefault)
+	require.NoError(t, err)
+	require.Equal(t, expected, ids(iter))
+}
+
 func TestStateStore_ScalingPoliciesByJob_PrefixBug(t *testing.T) {
 	t.Parallel()
 
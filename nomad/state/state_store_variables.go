@@ -0,0 +1,293 @@
+package state
+
+import (
+	"fmt"
+	"time"
+
+	"github.com/hashicorp/go-memdb"
+	"github.com/hashicorp/nomad/nomad/structs"
+)
+
+// Variables queries all the variables and is used only for snapshot/restore
+// and key rotation.
+func (s *StateStore) Variables(ws memdb.WatchSet) (memdb.ResultIterator, error) {
+	txn := s.db.ReadTxn()
+
+	iter, err := txn.Get(TableVariables, "id")
+	if err != nil {
+		return nil, fmt.Errorf("variable lookup failed: %v", err)
+	}
+	ws.Add(iter.WatchCh())
+
+	return iter, nil
+}
+
+// GetVariablesByNamespace returns an iterator that contains all variables
+// belonging to the provided namespace.
+func (s *StateStore) GetVariablesByNamespace(
+	ws memdb.WatchSet, namespace string) (memdb.ResultIterator, error) {
+	return s.GetVariablesByNamespaceAndPrefix(ws, namespace, "")
+}
+
+// GetVariablesByNamespaceAndPrefix returns an iterator that contains all
+// variables belonging to the provided namespace whose path starts with the
+// prefix.
+func (s *StateStore) GetVariablesByNamespaceAndPrefix(
+	ws memdb.WatchSet, namespace, prefix string) (memdb.ResultIterator, error) {
+
+	txn := s.db.ReadTxn()
+
+	iter, err := txn.Get(TableVariables, "id_prefix", namespace, prefix)
+	if err != nil {
+		return nil, fmt.Errorf("variable lookup failed: %v", err)
+	}
+	ws.Add(iter.WatchCh())
+
+	return iter, nil
+}
+
+// GetVariablesByKeyID returns an iterator that contains all variables that
+// were encrypted with the root key identified by keyID.
+func (s *StateStore) GetVariablesByKeyID(
+	ws memdb.WatchSet, keyID string) (memdb.ResultIterator, error) {
+
+	txn := s.db.ReadTxn()
+
+	iter, err := txn.Get(TableVariables, "keyid", keyID)
+	if err != nil {
+		return nil, fmt.Errorf("variable lookup failed: %v", err)
+	}
+	ws.Add(iter.WatchCh())
+
+	return iter, nil
+}
+
+// GetVariable returns a single variable at a given namespace and path. The
+// variable will be nil if no matching entry was found; it is the
+// responsibility of the caller to check for this.
+func (s *StateStore) GetVariable(
+	ws memdb.WatchSet, namespace, path string) (*structs.VariableEncrypted, error) {
+
+	txn := s.db.ReadTxn()
+
+	watchCh, raw, err := txn.FirstWatch(TableVariables, "id", namespace, path)
+	if err != nil {
+		return nil, fmt.Errorf("variable lookup failed: %v", err)
+	}
+	ws.Add(watchCh)
+
+	if raw != nil {
+		return raw.(*structs.VariableEncrypted), nil
+	}
+	return nil, nil
+}
+
+// VarSet is used to store a variable object.
+func (s *StateStore) VarSet(
+	msgType structs.MessageType, index uint64, req *structs.VarApplyStateRequest) *structs.VarApplyStateResponse {
+	tx := s.db.WriteTxnMsgT(msgType, index)
+	defer tx.Abort()
+
+	resp := s.varSetTxn(tx, index, req)
+	if resp.IsError() {
+		return resp
+	}
+
+	if err := tx.Commit(); err != nil {
+		return req.ErrorResponse(index, err)
+	}
+	return resp
+}
+
+// VarSetCAS is used to do a check-and-set operation on a variable. The
+// ModifyIndex in the provided entry is used to determine if we should write
+// the entry to the state store or not. A ModifyIndex of zero requires that no
+// variable exists at the path.
+func (s *StateStore) VarSetCAS(
+	msgType structs.MessageType, index uint64, req *structs.VarApplyStateRequest) *structs.VarApplyStateResponse {
+	tx := s.db.WriteTxnMsgT(msgType, index)
+	defer tx.Abort()
+
+	resp := s.varSetCASTxn(tx, index, req)
+	if resp.IsError() || resp.IsConflict() {
+		return resp
+	}
+
+	if err := tx.Commit(); err != nil {
+		return req.ErrorResponse(index, err)
+	}
+	return resp
+}
+
+// varSetCASTxn is the inner method used to do a CAS inside an existing
+// transaction.
+func (s *StateStore) varSetCASTxn(tx *txn, index uint64, req *structs.VarApplyStateRequest) *structs.VarApplyStateResponse {
+	sv := req.Var
+	raw, err := tx.First(TableVariables, "id", sv.Namespace, sv.Path)
+	if err != nil {
+		return req.ErrorResponse(index, fmt.Errorf("failed variable lookup: %s", err))
+	}
+	svEx, ok := raw.(*structs.VariableEncrypted)
+
+	// ModifyIndex of 0 means that we are doing a set-if-not-exists.
+	if sv.ModifyIndex == 0 && raw != nil {
+		return req.ConflictResponse(index, svEx)
+	}
+
+	// If the ModifyIndex is set but the variable doesn't exist, return a
+	// plausible zero value as the conflict
+	if sv.ModifyIndex != 0 && raw == nil {
+		zeroVal := &structs.VariableEncrypted{
+			VariableMetadata: structs.VariableMetadata{
+				Namespace: sv.Namespace,
+				Path:      sv.Path,
+			},
+		}
+		return req.ConflictResponse(index, zeroVal)
+	}
+
+	// If the existing index does not match the provided CAS index arg, then
+	// we shouldn't update anything and can safely return early here.
+	if ok && sv.ModifyIndex != svEx.ModifyIndex {
+		return req.ConflictResponse(index, svEx)
+	}
+
+	// If we made it this far, we should perform the set.
+	return s.varSetTxn(tx, index, req)
+}
+
+// varSetTxn is used to insert or update a variable in the state store. It is
+// the inner method used and handles only the actual storage.
+func (s *StateStore) varSetTxn(tx *txn, idx uint64, req *structs.VarApplyStateRequest) *structs.VarApplyStateResponse {
+	sv := req.Var
+	existingRaw, err := tx.First(TableVariables, "id", sv.Namespace, sv.Path)
+	if err != nil {
+		return req.ErrorResponse(idx, fmt.Errorf("failed variable lookup: %s", err))
+	}
+	existing, _ := existingRaw.(*structs.VariableEncrypted)
+
+	// Set up the indexes and times correctly to ensure existing values are
+	// maintained.
+	now := time.Now().UnixNano()
+	if existing != nil {
+		if existing.Equals(*sv) {
+			// Return the existing entry since it is unchanged.
+			sv.CreateIndex = existing.CreateIndex
+			sv.CreateTime = existing.CreateTime
+			sv.ModifyIndex = existing.ModifyIndex
+			sv.ModifyTime = existing.ModifyTime
+			return req.SuccessResponse(idx, &sv.VariableMetadata)
+		}
+		sv.CreateIndex = existing.CreateIndex
+		sv.CreateTime = existing.CreateTime
+		sv.ModifyIndex = idx
+		sv.ModifyTime = now
+	} else {
+		sv.CreateIndex = idx
+		sv.CreateTime = now
+		sv.ModifyIndex = idx
+		sv.ModifyTime = now
+	}
+
+	if err := tx.Insert(TableVariables, sv); err != nil {
+		return req.ErrorResponse(idx, fmt.Errorf("failed inserting variable: %s", err))
+	}
+	if err := tx.Insert("index", &IndexEntry{TableVariables, idx}); err != nil {
+		return req.ErrorResponse(idx, fmt.Errorf("failed updating index: %s", err))
+	}
+
+	return req.SuccessResponse(idx, &sv.VariableMetadata)
+}
+
+// VarDelete is used to delete a single variable in the state store.
+func (s *StateStore) VarDelete(
+	msgType structs.MessageType, idx uint64, req *structs.VarApplyStateRequest) *structs.VarApplyStateResponse {
+	tx := s.db.WriteTxnMsgT(msgType, idx)
+	defer tx.Abort()
+
+	resp := s.varDeleteTxn(tx, idx, req)
+	if resp.IsError() {
+		return resp
+	}
+
+	if err := tx.Commit(); err != nil {
+		return req.ErrorResponse(idx, err)
+	}
+	return resp
+}
+
+// VarDeleteCAS is used to conditionally delete a variable if and only if it
+// has a given modify index. If the CAS index specified does not match the
+// last observed modify index of the variable, then the call is a noop and the
+// conflicting variable is returned.
+func (s *StateStore) VarDeleteCAS(
+	msgType structs.MessageType, idx uint64, req *structs.VarApplyStateRequest) *structs.VarApplyStateResponse {
+	tx := s.db.WriteTxnMsgT(msgType, idx)
+	defer tx.Abort()
+
+	resp := s.varDeleteCASTxn(tx, idx, req)
+	if resp.IsError() || resp.IsConflict() {
+		return resp
+	}
+
+	if err := tx.Commit(); err != nil {
+		return req.ErrorResponse(idx, err)
+	}
+	return resp
+}
+
+// varDeleteCASTxn is an inner method used to check the existing value before
+// calling varDeleteTxn.
+func (s *StateStore) varDeleteCASTxn(tx *txn, idx uint64, req *structs.VarApplyStateRequest) *structs.VarApplyStateResponse {
+	sv := req.Var
+	raw, err := tx.First(TableVariables, "id", sv.Namespace, sv.Path)
+	if err != nil {
+		return req.ErrorResponse(idx, fmt.Errorf("failed variable lookup: %s", err))
+	}
+
+	// If the variable is not found, there is no conflict if the CAS index
+	// was zero, since we are trying to delete something that doesn't exist.
+	if raw == nil {
+		if sv.ModifyIndex == 0 {
+			return req.SuccessResponse(idx, nil)
+		}
+		zeroVal := &structs.VariableEncrypted{
+			VariableMetadata: structs.VariableMetadata{
+				Namespace: sv.Namespace,
+				Path:      sv.Path,
+			},
+		}
+		return req.ConflictResponse(idx, zeroVal)
+	}
+
+	// If the existing index does not match the provided CAS index arg, then
+	// we shouldn't update anything and can safely return early here.
+	existing := raw.(*structs.VariableEncrypted)
+	if sv.ModifyIndex != existing.ModifyIndex {
+		return req.ConflictResponse(idx, existing)
+	}
+
+	// Call the actual deletion if the above passed.
+	return s.varDeleteTxn(tx, idx, req)
+}
+
+// varDeleteTxn is the inner method used to perform the actual deletion of a
+// variable. Deleting a variable that does not exist is not an error.
+func (s *StateStore) varDeleteTxn(tx *txn, idx uint64, req *structs.VarApplyStateRequest) *structs.VarApplyStateResponse {
+	existingRaw, err := tx.First(TableVariables, "id", req.Var.Namespace, req.Var.Path)
+	if err != nil {
+		return req.ErrorResponse(idx, fmt.Errorf("failed variable lookup: %s", err))
+	}
+	if existingRaw == nil {
+		return req.SuccessResponse(idx, nil)
+	}
+
+	if err := tx.Delete(TableVariables, existingRaw); err != nil {
+		return req.ErrorResponse(idx, fmt.Errorf("failed deleting variable: %s", err))
+	}
+	if err := tx.Insert("index", &IndexEntry{TableVariables, idx}); err != nil {
+		return req.ErrorResponse(idx, fmt.Errorf("failed updating index: %s", err))
+	}
+
+	return req.SuccessResponse(idx, nil)
+}
@@ -0,0 +1,181 @@
+package state
+
+import (
+	"testing"
+
+	"github.com/hashicorp/go-memdb"
+	"github.com/hashicorp/nomad/nomad/structs"
+	"github.com/stretchr/testify/require"
+)
+
+func testVariable(namespace, path, keyID string) *structs.VariableEncrypted {
+	return &structs.VariableEncrypted{
+		VariableMetadata: structs.VariableMetadata{
+			Namespace: namespace,
+			Path:      path,
+		},
+		VariableData: structs.VariableData{
+			Data:  []byte("ciphertext of " + path),
+			KeyID: keyID,
+		},
+	}
+}
+
+func TestStateStore_VarSet(t *testing.T) {
+	t.Parallel()
+	testState := testStateStore(t)
+
+	// Insert a new variable and check the indexes.
+	req := &structs.VarApplyStateRequest{
+		Op:  structs.VarOpSet,
+		Var: testVariable(structs.DefaultNamespace, "foo/bar", "key1"),
+	}
+	resp := testState.VarSet(structs.MsgTypeTestSetup, 10, req)
+	require.True(t, resp.IsOk())
+	require.Equal(t, uint64(10), resp.WrittenSVMeta.CreateIndex)
+	require.Equal(t, uint64(10), resp.WrittenSVMeta.ModifyIndex)
+
+	tableIndex, err := testState.Index(TableVariables)
+	require.NoError(t, err)
+	require.Equal(t, uint64(10), tableIndex)
+
+	// Update the variable and ensure the create index is maintained.
+	update := testVariable(structs.DefaultNamespace, "foo/bar", "key1")
+	update.Data = []byte("new ciphertext")
+	resp = testState.VarSet(structs.MsgTypeTestSetup, 20, &structs.VarApplyStateRequest{
+		Op:  structs.VarOpSet,
+		Var: update,
+	})
+	require.True(t, resp.IsOk())
+
+	out, err := testState.GetVariable(memdb.NewWatchSet(), structs.DefaultNamespace, "foo/bar")
+	require.NoError(t, err)
+	require.NotNil(t, out)
+	require.Equal(t, uint64(10), out.CreateIndex)
+	require.Equal(t, uint64(20), out.ModifyIndex)
+	require.Equal(t, []byte("new ciphertext"), out.Data)
+}
+
+func TestStateStore_VarSetCAS(t *testing.T) {
+	t.Parallel()
+	testState := testStateStore(t)
+
+	// A CAS with a zero index creates the variable when it does not exist.
+	resp := testState.VarSetCAS(structs.MsgTypeTestSetup, 10, &structs.VarApplyStateRequest{
+		Op:  structs.VarOpCAS,
+		Var: testVariable(structs.DefaultNamespace, "foo", "key1"),
+	})
+	require.True(t, resp.IsOk())
+
+	// A second CAS with a zero index conflicts with the existing variable.
+	resp = testState.VarSetCAS(structs.MsgTypeTestSetup, 20, &structs.VarApplyStateRequest{
+		Op:  structs.VarOpCAS,
+		Var: testVariable(structs.DefaultNamespace, "foo", "key1"),
+	})
+	require.True(t, resp.IsConflict())
+	require.Equal(t, uint64(10), resp.Conflict.ModifyIndex)
+
+	// A CAS with a stale index conflicts.
+	stale := testVariable(structs.DefaultNamespace, "foo", "key1")
+	stale.ModifyIndex = 5
+	resp = testState.VarSetCAS(structs.MsgTypeTestSetup, 30, &structs.VarApplyStateRequest{
+		Op:  structs.VarOpCAS,
+		Var: stale,
+	})
+	require.True(t, resp.IsConflict())
+
+	// A CAS with the current index succeeds.
+	current := testVariable(structs.DefaultNamespace, "foo", "key1")
+	current.Data = []byte("updated")
+	current.ModifyIndex = 10
+	resp = testState.VarSetCAS(structs.MsgTypeTestSetup, 40, &structs.VarApplyStateRequest{
+		Op:  structs.VarOpCAS,
+		Var: current,
+	})
+	require.True(t, resp.IsOk())
+	require.Equal(t, uint64(40), resp.WrittenSVMeta.ModifyIndex)
+
+	// A CAS with a non-zero index on a missing variable conflicts.
+	missing := testVariable(structs.DefaultNamespace, "missing", "key1")
+	missing.ModifyIndex = 10
+	resp = testState.VarSetCAS(structs.MsgTypeTestSetup, 50, &structs.VarApplyStateRequest{
+		Op:  structs.VarOpCAS,
+		Var: missing,
+	})
+	require.True(t, resp.IsConflict())
+	require.Equal(t, uint64(0), resp.Conflict.ModifyIndex)
+}
+
+func TestStateStore_VarDeleteCAS(t *testing.T) {
+	t.Parallel()
+	testState := testStateStore(t)
+
+	resp := testState.VarSet(structs.MsgTypeTestSetup, 10, &structs.VarApplyStateRequest{
+		Op:  structs.VarOpSet,
+		Var: testVariable(structs.DefaultNamespace, "foo", "key1"),
+	})
+	require.True(t, resp.IsOk())
+
+	// A delete with a stale index conflicts and leaves the variable intact.
+	stale := testVariable(structs.DefaultNamespace, "foo", "")
+	stale.ModifyIndex = 5
+	resp = testState.VarDeleteCAS(structs.MsgTypeTestSetup, 20, &structs.VarApplyStateRequest{
+		Op:  structs.VarOpDeleteCAS,
+		Var: stale,
+	})
+	require.True(t, resp.IsConflict())
+
+	out, err := testState.GetVariable(nil, structs.DefaultNamespace, "foo")
+	require.NoError(t, err)
+	require.NotNil(t, out)
+
+	// A delete with the current index removes the variable.
+	current := testVariable(structs.DefaultNamespace, "foo", "")
+	current.ModifyIndex = 10
+	resp = testState.VarDeleteCAS(structs.MsgTypeTestSetup, 30, &structs.VarApplyStateRequest{
+		Op:  structs.VarOpDeleteCAS,
+		Var: current,
+	})
+	require.True(t, resp.IsOk())
+
+	out, err = testState.GetVariable(nil, structs.DefaultNamespace, "foo")
+	require.NoError(t, err)
+	require.Nil(t, out)
+
+	tableIndex, err := testState.Index(TableVariables)
+	require.NoError(t, err)
+	require.Equal(t, uint64(30), tableIndex)
+}
+
+func TestStateStore_GetVariablesByNamespaceAndPrefix(t *testing.T) {
+	t.Parallel()
+	testState := testStateStore(t)
+
+	for i, v := range []*structs.VariableEncrypted{
+		testVariable(structs.DefaultNamespace, "a/one", "key1"),
+		testVariable(structs.DefaultNamespace, "a/two", "key1"),
+		testVariable(structs.DefaultNamespace, "b/one", "key1"),
+		testVariable("platform", "a/one", "key1"),
+	} {
+		resp := testState.VarSet(structs.MsgTypeTestSetup, uint64(10+i), &structs.VarApplyStateRequest{
+			Op:  structs.VarOpSet,
+			Var: v,
+		})
+		require.True(t, resp.IsOk())
+	}
+
+	countPaths := func(namespace, prefix string) []string {
+		iter, err := testState.GetVariablesByNamespaceAndPrefix(memdb.NewWatchSet(), namespace, prefix)
+		require.NoError(t, err)
+		var paths []string
+		for raw := iter.Next(); raw != nil; raw = iter.Next() {
+			paths = append(paths, raw.(*structs.VariableEncrypted).Path)
+		}
+		return paths
+	}
+
+	require.Equal(t, []string{"a/one", "a/two", "b/one"}, countPaths(structs.DefaultNamespace, ""))
+	require.Equal(t, []string{"a/one", "a/two"}, countPaths(structs.DefaultNamespace, "a/"))
+	require.Equal(t, []string{"a/one"}, countPaths("platform", ""))
+	require.Nil(t, countPaths("unknown", ""))
+}
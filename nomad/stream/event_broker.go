@@ -189,7 +189,15 @@ func (e *EventBroker) handleACLUpdates(ctx context.Context) {
 		case update := <-e.aclCh:
 			switch payload := update.Payload.(type) {
 			case *structs.ACLTokenEvent:
+				// Tokens whose secret is stored encrypted are matched to
+				// subscriptions by the hash of their secret
 				tokenSecretID := payload.SecretID()
+				if tokenSecretID == "" {
+					tokenSecretID = e.subscriptions.tokenBySecretHash(payload.SecretHash())
+					if tokenSecretID == "" {
+						continue
+					}
+				}
 
 				// Token was deleted
 				if update.Type == structs.TypeACLTokenDeleted {
@@ -299,7 +307,8 @@ func aclAllowsSubscription(aclObj *acl.ACL, subReq *SubscribeRequest) bool {
 		case structs.TopicDeployment,
 			structs.TopicEvaluation,
 			structs.TopicAllocation,
-			structs.TopicJob:
+			structs.TopicJob,
+			structs.TopicService:
 			if ok := aclObj.AllowNsOp(subReq.Namespace, acl.NamespaceCapabilityReadJob); !ok {
 				return false
 			}
@@ -348,6 +357,20 @@ func (s *subscriptions) add(req *SubscribeRequest, sub *Subscription) {
 	subsByToken[req] = sub
 }
 
+// tokenBySecretHash returns the token of the subscriptions whose hash
+// matches the given hash, or an empty string if there are none.
+func (s *subscriptions) tokenBySecretHash(hash string) string {
+	s.mu.RLock()
+	defer s.mu.RUnlock()
+
+	for token := range s.byToken {
+		if token != "" && structs.ACLTokenSecretHash(token) == hash {
+			return token
+		}
+	}
+	return ""
+}
+
 func (s *subscriptions) closeSubscriptionsForTokens(tokenSecretIDs []string) {
 	s.mu.RLock()
 	defer s.mu.RUnlock()
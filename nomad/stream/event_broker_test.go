@@ -152,6 +152,49 @@ func TestEventBroker_handleACLUpdates_TokenDeleted(t *testing.T) {
 	require.Equal(t, structs.Events{}, out)
 }
 
+// TestEventBroker_handleACLUpdates_EncryptedTokenDeleted asserts that
+// subscriptions are closed when a token whose secret is stored encrypted is
+// deleted.
+func TestEventBroker_handleACLUpdates_EncryptedTokenDeleted(t *testing.T) {
+	ctx, cancel := context.WithCancel(context.Background())
+	t.Cleanup(cancel)
+
+	publisher, err := NewEventBroker(ctx, nil, EventBrokerCfg{})
+	require.NoError(t, err)
+
+	sub1, err := publisher.Subscribe(&SubscribeRequest{
+		Topics: map[structs.Topic][]string{
+			"*": {"*"},
+		},
+		Token: "foo",
+	})
+	require.NoError(t, err)
+	defer sub1.Unsubscribe()
+
+	aclEvent := structs.Event{
+		Topic: structs.TopicACLToken,
+		Type:  structs.TypeACLTokenDeleted,
+		Payload: structs.NewACLTokenEvent(&structs.ACLToken{
+			SecretHash:        structs.ACLTokenSecretHash("foo"),
+			EncryptedSecretID: []byte("encrypted"),
+			SecretKeyID:       "key",
+		}),
+	}
+
+	publisher.Publish(&structs.Events{Index: 100, Events: []structs.Event{aclEvent}})
+	for {
+		_, err := sub1.Next(ctx)
+		if err == ErrSubscriptionClosed {
+			break
+		}
+	}
+
+	out, err := sub1.Next(ctx)
+	require.Error(t, err)
+	require.Equal(t, ErrSubscriptionClosed, err)
+	require.Equal(t, structs.Events{}, out)
+}
+
 type fakeACLDelegate struct {
 	tokenProvider ACLTokenProvider
 }
@@ -55,7 +55,8 @@ func (cu *ConsulUsage) Used() bool {
 
 // ConsulUsages returns a map from Consul namespace to things that will use Consul,
 // including ConsulConnect TaskKinds, Consul Services from groups and tasks, and
-// a boolean indicating if Consul KV is in use.
+// a boolean indicating if Consul KV is in use. Services using the Nomad service
+// provider are not included.
 func (j *Job) ConsulUsages() map[string]*ConsulUsage {
 	m := make(map[string]*ConsulUsage)
 
@@ -70,12 +71,18 @@ func (j *Job) ConsulUsages() map[string]*ConsulUsage {
 
 		// Gather group services
 		for _, service := range tg.Services {
+			if service.Provider == ServiceProviderNomad {
+				continue
+			}
 			m[namespace].Services = append(m[namespace].Services, service.Name)
 		}
 
 		// Gather task services and KV usage
 		for _, task := range tg.Tasks {
 			for _, service := range task.Services {
+				if service.Provider == ServiceProviderNomad {
+					continue
+				}
 				m[namespace].Services = append(m[namespace].Services, service.Name)
 			}
 			if len(task.Templates) > 0 {
@@ -115,6 +115,17 @@ func (j *Job) Diff(other *Job, contextual bool) (*JobDiff, error) {
 		diff.Objects = append(diff.Objects, affinitiesDiff...)
 	}
 
+	// Alloc affinities diff
+	allocAffinitiesDiff := primitiveObjectSetDiff(
+		interfaceSlice(j.AllocAffinities),
+		interfaceSlice(other.AllocAffinities),
+		nil,
+		"AllocAffinity",
+		contextual)
+	if allocAffinitiesDiff != nil {
+		diff.Objects = append(diff.Objects, allocAffinitiesDiff...)
+	}
+
 	// Task groups diff
 	tgs, err := taskGroupDiffs(j.TaskGroups, other.TaskGroups, contextual)
 	if err != nil {
@@ -257,6 +268,34 @@ func (tg *TaskGroup) Diff(other *TaskGroup, contextual bool) (*TaskGroupDiff, er
 		}
 	}
 
+	// MaxClientDisconnect diff
+	if oldPrimitiveFlat != nil && newPrimitiveFlat != nil {
+		if tg.MaxClientDisconnect == nil {
+			oldPrimitiveFlat["MaxClientDisconnect"] = ""
+		} else {
+			oldPrimitiveFlat["MaxClientDisconnect"] = fmt.Sprintf("%d", *tg.MaxClientDisconnect)
+		}
+		if other.MaxClientDisconnect == nil {
+			newPrimitiveFlat["MaxClientDisconnect"] = ""
+		} else {
+			newPrimitiveFlat["MaxClientDisconnect"] = fmt.Sprintf("%d", *other.MaxClientDisconnect)
+		}
+	}
+
+	// MaxRunDuration diff
+	if oldPrimitiveFlat != nil && newPrimitiveFlat != nil {
+		if tg.MaxRunDuration == nil {
+			oldPrimitiveFlat["MaxRunDuration"] = ""
+		} else {
+			oldPrimitiveFlat["MaxRunDuration"] = fmt.Sprintf("%d", *tg.MaxRunDuration)
+		}
+		if other.MaxRunDuration == nil {
+			newPrimitiveFlat["MaxRunDuration"] = ""
+		} else {
+			newPrimitiveFlat["MaxRunDuration"] = fmt.Sprintf("%d", *other.MaxRunDuration)
+		}
+	}
+
 	// Diff the primitive fields.
 	diff.Fields = fieldDiffs(oldPrimitiveFlat, newPrimitiveFlat, false)
 
@@ -282,6 +321,17 @@ func (tg *TaskGroup) Diff(other *TaskGroup, contextual bool) (*TaskGroupDiff, er
 		diff.Objects = append(diff.Objects, affinitiesDiff...)
 	}
 
+	// Alloc affinities diff
+	allocAffinitiesDiff := primitiveObjectSetDiff(
+		interfaceSlice(tg.AllocAffinities),
+		interfaceSlice(other.AllocAffinities),
+		nil,
+		"AllocAffinity",
+		contextual)
+	if allocAffinitiesDiff != nil {
+		diff.Objects = append(diff.Objects, allocAffinitiesDiff...)
+	}
+
 	// Restart policy diff
 	rDiff := primitiveObjectDiff(tg.RestartPolicy, other.RestartPolicy, nil, "RestartPolicy", contextual)
 	if rDiff != nil {
@@ -483,7 +533,7 @@ func (t *Task) Diff(other *Task, contextual bool) (*TaskDiff, error) {
 	}
 
 	// LogConfig diff
-	lDiff := primitiveObjectDiff(t.LogConfig, other.LogConfig, nil, "LogConfig", contextual)
+	lDiff := logConfigDiff(t.LogConfig, other.LogConfig, contextual)
 	if lDiff != nil {
 		diff.Objects = append(diff.Objects, lDiff)
 	}
@@ -494,6 +544,12 @@ func (t *Task) Diff(other *Task, contextual bool) (*TaskDiff, error) {
 		diff.Objects = append(diff.Objects, dDiff)
 	}
 
+	// Identity diff
+	iDiff := primitiveObjectDiff(t.Identity, other.Identity, nil, "Identity", contextual)
+	if iDiff != nil {
+		diff.Objects = append(diff.Objects, iDiff)
+	}
+
 	// Artifacts diff
 	diffs := primitiveObjectSetDiff(
 		interfaceSlice(t.Artifacts),
@@ -517,12 +573,7 @@ func (t *Task) Diff(other *Task, contextual bool) (*TaskDiff, error) {
 	}
 
 	// Template diff
-	tmplDiffs := primitiveObjectSetDiff(
-		interfaceSlice(t.Templates),
-		interfaceSlice(other.Templates),
-		nil,
-		"Template",
-		contextual)
+	tmplDiffs := templateDiffs(t.Templates, other.Templates, contextual)
 	if tmplDiffs != nil {
 		diff.Objects = append(diff.Objects, tmplDiffs...)
 	}
@@ -1418,7 +1469,7 @@ func sidecarTaskDiff(old, new *SidecarTask, contextual bool) *ObjectDiff {
 	}
 
 	// LogConfig diff
-	lDiff := primitiveObjectDiff(old.LogConfig, new.LogConfig, nil, "LogConfig", contextual)
+	lDiff := logConfigDiff(old.LogConfig, new.LogConfig, contextual)
 	if lDiff != nil {
 		diff.Objects = append(diff.Objects, lDiff)
 	}
@@ -1599,6 +1650,130 @@ func vaultDiff(old, new *Vault, contextual bool) *ObjectDiff {
 	return diff
 }
 
+// logConfigDiff returns the diff of two LogConfig objects, including the
+// diff of their sinks.
+func logConfigDiff(old, new *LogConfig, contextual bool) *ObjectDiff {
+	diff := primitiveObjectDiff(old, new, nil, "LogConfig", contextual)
+
+	var oldSinks, newSinks []*LogSink
+	var oldRateLimit, newRateLimit *LogRateLimit
+	if old != nil {
+		oldSinks = old.Sinks
+		oldRateLimit = old.RateLimit
+	}
+	if new != nil {
+		newSinks = new.Sinks
+		newRateLimit = new.RateLimit
+	}
+
+	var objDiffs []*ObjectDiff
+	if rDiff := primitiveObjectDiff(oldRateLimit, newRateLimit, nil, "RateLimit", contextual); rDiff != nil {
+		objDiffs = append(objDiffs, rDiff)
+	}
+	objDiffs = append(objDiffs, primitiveObjectSetDiff(
+		interfaceSlice(oldSinks),
+		interfaceSlice(newSinks),
+		nil, "LogSink", contextual)...)
+	if len(objDiffs) == 0 {
+		return diff
+	}
+
+	if diff == nil {
+		diff = &ObjectDiff{Type: DiffTypeEdited, Name: "LogConfig"}
+	} else if diff.Type == DiffTypeNone {
+		diff.Type = DiffTypeEdited
+	}
+	diff.Objects = append(diff.Objects, objDiffs...)
+	return diff
+}
+
+// templateDiffs diffs a set of templates, including their change scripts.
+func templateDiffs(old, new []*Template, contextual bool) []*ObjectDiff {
+	makeSet := func(tmpls []*Template) map[string]*Template {
+		tmplMap := make(map[string]*Template, len(tmpls))
+		for _, tmpl := range tmpls {
+			hash, err := hashstructure.Hash(tmpl, nil)
+			if err != nil {
+				panic(err)
+			}
+			tmplMap[fmt.Sprintf("%d", hash)] = tmpl
+		}
+
+		return tmplMap
+	}
+
+	oldSet := makeSet(old)
+	newSet := makeSet(new)
+
+	var diffs []*ObjectDiff
+	for k, v := range oldSet {
+		// Deleted
+		if _, ok := newSet[k]; !ok {
+			diffs = append(diffs, templateDiff(v, nil, contextual))
+		}
+	}
+	for k, v := range newSet {
+		// Added
+		if _, ok := oldSet[k]; !ok {
+			diffs = append(diffs, templateDiff(nil, v, contextual))
+		}
+	}
+
+	sort.Sort(ObjectDiffs(diffs))
+	return diffs
+}
+
+// templateDiff returns the diff of two templates, including the diff of their
+// change scripts.
+func templateDiff(old, new *Template, contextual bool) *ObjectDiff {
+	diff := primitiveObjectDiff(old, new, nil, "Template", contextual)
+
+	var oldScript, newScript *ChangeScript
+	if old != nil {
+		oldScript = old.ChangeScript
+	}
+	if new != nil {
+		newScript = new.ChangeScript
+	}
+	if csDiff := changeScriptDiff(oldScript, newScript, contextual); csDiff != nil {
+		diff.Objects = append(diff.Objects, csDiff)
+	}
+
+	return diff
+}
+
+// changeScriptDiff returns the diff of two template change scripts.
+func changeScriptDiff(old, new *ChangeScript, contextual bool) *ObjectDiff {
+	diff := &ObjectDiff{Type: DiffTypeNone, Name: "ChangeScript"}
+	var oldPrimitiveFlat, newPrimitiveFlat map[string]string
+
+	if reflect.DeepEqual(old, new) {
+		return nil
+	} else if old == nil {
+		old = &ChangeScript{}
+		diff.Type = DiffTypeAdded
+		newPrimitiveFlat = flatmap.Flatten(new, nil, true)
+	} else if new == nil {
+		new = &ChangeScript{}
+		diff.Type = DiffTypeDeleted
+		oldPrimitiveFlat = flatmap.Flatten(old, nil, true)
+	} else {
+		diff.Type = DiffTypeEdited
+		oldPrimitiveFlat = flatmap.Flatten(old, nil, true)
+		newPrimitiveFlat = flatmap.Flatten(new, nil, true)
+	}
+
+	// Diff the primitive fields.
+	diff.Fields = fieldDiffs(oldPrimitiveFlat, newPrimitiveFlat, contextual)
+
+	// Args diffs
+	if argsDiff := stringSetDiff(old.Args, new.Args, "Args", contextual); argsDiff != nil {
+		diff.Objects = append(diff.Objects, argsDiff)
+	}
+
+	return diff
+}
+
 // parameterizedJobDiff returns the diff of two parameterized job objects. If
 // contextual diff is enabled, all fields will be returned, even if no diff
 // occurred.
@@ -1888,6 +2063,11 @@ func (r *Resources) Diff(other *Resources, contextual bool) *ObjectDiff {
 		diff.Objects = append(diff.Objects, nDiffs...)
 	}
 
+	// NUMA diff
+	if numaDiff := primitiveObjectDiff(r.NUMA, other.NUMA, nil, "NUMA", contextual); numaDiff != nil {
+		diff.Objects = append(diff.Objects, numaDiff)
+	}
+
 	return diff
 }
 
@@ -2872,6 +2872,12 @@ func TestTaskGroupDiff(t *testing.T) {
 								Old:  "",
 								New:  "",
 							},
+							{
+								Type: DiffTypeNone,
+								Name: "Provider",
+								Old:  "",
+								New:  "",
+							},
 							{
 								Type: DiffTypeEdited,
 								Name: "TaskName",
@@ -4305,6 +4311,12 @@ func TestTaskDiff(t *testing.T) {
 						Type: DiffTypeAdded,
 						Name: "LogConfig",
 						Fields: []*FieldDiff{
+							{
+								Type: DiffTypeAdded,
+								Name: "CompressRotated",
+								Old:  "",
+								New:  "false",
+							},
 							{
 								Type: DiffTypeAdded,
 								Name: "MaxFileSizeMB",
@@ -4338,6 +4350,12 @@ func TestTaskDiff(t *testing.T) {
 						Type: DiffTypeDeleted,
 						Name: "LogConfig",
 						Fields: []*FieldDiff{
+							{
+								Type: DiffTypeDeleted,
+								Name: "CompressRotated",
+								Old:  "false",
+								New:  "",
+							},
 							{
 								Type: DiffTypeDeleted,
 								Name: "MaxFileSizeMB",
@@ -4415,6 +4433,12 @@ func TestTaskDiff(t *testing.T) {
 						Type: DiffTypeEdited,
 						Name: "LogConfig",
 						Fields: []*FieldDiff{
+							{
+								Type: DiffTypeNone,
+								Name: "CompressRotated",
+								Old:  "false",
+								New:  "false",
+							},
 							{
 								Type: DiffTypeEdited,
 								Name: "MaxFileSizeMB",
@@ -4432,6 +4456,118 @@ func TestTaskDiff(t *testing.T) {
 				},
 			},
 		},
+		{
+			Name: "LogConfig sink added",
+			Old: &Task{
+				LogConfig: &LogConfig{
+					MaxFiles:      1,
+					MaxFileSizeMB: 10,
+				},
+			},
+			New: &Task{
+				LogConfig: &LogConfig{
+					MaxFiles:      1,
+					MaxFileSizeMB: 10,
+					Sinks: []*LogSink{
+						{
+							Type:    LogSinkTypeTCP,
+							Address: "127.0.0.1:5000",
+						},
+					},
+				},
+			},
+			Expected: &TaskDiff{
+				Type: DiffTypeEdited,
+				Objects: []*ObjectDiff{
+					{
+						Type: DiffTypeEdited,
+						Name: "LogConfig",
+						Objects: []*ObjectDiff{
+							{
+								Type: DiffTypeAdded,
+								Name: "LogSink",
+								Fields: []*FieldDiff{
+									{
+										Type: DiffTypeAdded,
+										Name: "Address",
+										Old:  "",
+										New:  "127.0.0.1:5000",
+									},
+									{
+										Type: DiffTypeAdded,
+										Name: "BufferSize",
+										Old:  "",
+										New:  "0",
+									},
+									{
+										Type: DiffTypeAdded,
+										Name: "Type",
+										Old:  "",
+										New:  "tcp",
+									},
+								},
+							},
+						},
+					},
+				},
+			},
+		},
+		{
+			Name: "LogConfig rate limit added and compression enabled",
+			Old: &Task{
+				LogConfig: &LogConfig{
+					MaxFiles:      1,
+					MaxFileSizeMB: 10,
+				},
+			},
+			New: &Task{
+				LogConfig: &LogConfig{
+					MaxFiles:        1,
+					MaxFileSizeMB:   10,
+					CompressRotated: true,
+					RateLimit: &LogRateLimit{
+						LinesPerSecond: 100,
+					},
+				},
+			},
+			Expected: &TaskDiff{
+				Type: DiffTypeEdited,
+				Objects: []*ObjectDiff{
+					{
+						Type: DiffTypeEdited,
+						Name: "LogConfig",
+						Fields: []*FieldDiff{
+							{
+								Type: DiffTypeEdited,
+								Name: "CompressRotated",
+								Old:  "false",
+								New:  "true",
+							},
+						},
+						Objects: []*ObjectDiff{
+							{
+								Type: DiffTypeAdded,
+								Name: "RateLimit",
+								Fields: []*FieldDiff{
+									{
+										Type: DiffTypeAdded,
+										Name: "BytesPerSecond",
+										Old:  "",
+										New:  "0",
+									},
+									{
+										Type: DiffTypeAdded,
+										Name: "LinesPerSecond",
+										Old:  "",
+										New:  "100",
+									},
+								},
+							},
+						},
+					},
+				},
+			},
+		},
 		{
 			Name: "Artifacts edited",
 			Old: &Task{
@@ -5589,6 +5725,12 @@ func TestTaskDiff(t *testing.T) {
 								Old:  "foo",
 								New:  "bar",
 							},
+							{
+								Type: DiffTypeNone,
+								Name: "Provider",
+								Old:  "",
+								New:  "",
+							},
 							{
 								Type: DiffTypeAdded,
 								Name: "TaskName",
@@ -5733,6 +5875,10 @@ func TestTaskDiff(t *testing.T) {
 								Type: DiffTypeNone,
 								Name: "PortLabel",
 							},
+							{
+								Type: DiffTypeNone,
+								Name: "Provider",
+							},
 							{
 								Type: DiffTypeNone,
 								Name: "TaskName",
@@ -6252,6 +6398,12 @@ func TestTaskDiff(t *testing.T) {
 								Old:  "",
 								New:  "",
 							},
+							{
+								Type: DiffTypeNone,
+								Name: "Provider",
+								Old:  "",
+								New:  "",
+							},
 							{
 								Type: DiffTypeNone,
 								Name: "TaskName",
@@ -6926,8 +7078,14 @@ func TestTaskDiff(t *testing.T) {
 						EmbeddedTmpl: "baz3",
 						ChangeMode:   "bam3",
 						ChangeSignal: "SIGHUP3",
-						Splay:        3,
-						Perms:        "0776",
+						ChangeScript: &ChangeScript{
+							Command:     "/bin/reload",
+							Args:        []string{"foo"},
+							Timeout:     5,
+							FailOnError: true,
+						},
+						Splay: 3,
+						Perms: "0776",
 					},
 				},
 			},
@@ -6993,6 +7151,46 @@ func TestTaskDiff(t *testing.T) {
 								New:  "0",
 							},
 						},
+						Objects: []*ObjectDiff{
+							{
+								Type: DiffTypeAdded,
+								Name: "ChangeScript",
+								Fields: []*FieldDiff{
+									{
+										Type: DiffTypeAdded,
+										Name: "Command",
+										Old:  "",
+										New:  "/bin/reload",
+									},
+									{
+										Type: DiffTypeAdded,
+										Name: "FailOnError",
+										Old:  "",
+										New:  "true",
+									},
+									{
+										Type: DiffTypeAdded,
+										Name: "Timeout",
+										Old:  "",
+										New:  "5",
+									},
+								},
+								Objects: []*ObjectDiff{
+									{
+										Type: DiffTypeAdded,
+										Name: "Args",
+										Fields: []*FieldDiff{
+											{
+												Type: DiffTypeAdded,
+												Name: "Args",
+												Old:  "",
+												New:  "foo",
+											},
+										},
+									},
+								},
+							},
+						},
 					},
 					{
 						Type: DiffTypeDeleted,
@@ -7279,6 +7477,10 @@ func TestServicesDiff(t *testing.T) {
 							Old:  "http",
 							New:  "https",
 						},
+						{
+							Type: DiffTypeNone,
+							Name: "Provider",
+						},
 						{
 							Type: DiffTypeNone,
 							Name: "TaskName",
@@ -7364,6 +7566,10 @@ func TestServicesDiff(t *testing.T) {
 							Name: "PortLabel",
 							New:  "http",
 						},
+						{
+							Type: DiffTypeNone,
+							Name: "Provider",
+						},
 						{
 							Type: DiffTypeNone,
 							Name: "TaskName",
@@ -7423,6 +7629,10 @@ func TestServicesDiff(t *testing.T) {
 							Name: "PortLabel",
 							New:  "https",
 						},
+						{
+							Type: DiffTypeNone,
+							Name: "Provider",
+						},
 						{
 							Type: DiffTypeNone,
 							Name: "TaskName",
@@ -7489,6 +7699,10 @@ func TestServicesDiff(t *testing.T) {
 							Old:  "http",
 							New:  "https-redirect",
 						},
+						{
+							Type: DiffTypeNone,
+							Name: "Provider",
+						},
 						{
 							Type: DiffTypeNone,
 							Name: "TaskName",
@@ -7559,6 +7773,10 @@ func TestServicesDiff(t *testing.T) {
 							Old:  "http",
 							New:  "http",
 						},
+						{
+							Type: DiffTypeNone,
+							Name: "Provider",
+						},
 						{
 							Type: DiffTypeNone,
 							Name: "TaskName",
@@ -23,6 +23,7 @@ const (
 	TopicNode       Topic = "Node"
 	TopicACLPolicy  Topic = "ACLPolicy"
 	TopicACLToken   Topic = "ACLToken"
+	TopicService    Topic = "Service"
 	TopicAll        Topic = "*"
 
 	TypeNodeRegistration              = "NodeRegistration"
@@ -45,6 +46,8 @@ const (
 	TypeACLTokenUpserted              = "ACLTokenUpserted"
 	TypeACLPolicyDeleted              = "ACLPolicyDeleted"
 	TypeACLPolicyUpserted             = "ACLPolicyUpserted"
+	TypeServiceRegistration           = "ServiceRegistration"
+	TypeServiceDeregistration         = "ServiceDeregistration"
 )
 
 // Event represents a change in Nomads state.
@@ -118,9 +121,16 @@ type NodeStreamEvent struct {
 	Node *Node
 }
 
+// ServiceRegistrationStreamEvent holds a newly updated or deleted service
+// registration.
+type ServiceRegistrationStreamEvent struct {
+	Service *ServiceRegistration
+}
+
 type ACLTokenEvent struct {
-	ACLToken *ACLToken
-	secretID string
+	ACLToken   *ACLToken
+	secretID   string
+	secretHash string
 }
 
 // NewACLTokenEvent takes a token and creates a new ACLTokenEvent.  It creates
@@ -128,17 +138,28 @@ type ACLTokenEvent struct {
 func NewACLTokenEvent(token *ACLToken) *ACLTokenEvent {
 	c := token.Copy()
 	c.SecretID = ""
+	c.SecretHash = ""
+	c.EncryptedSecretID = nil
+	c.SecretKeyID = ""
 
 	return &ACLTokenEvent{
-		ACLToken: c,
-		secretID: token.SecretID,
+		ACLToken:   c,
+		secretID:   token.SecretID,
+		secretHash: token.SecretHash,
 	}
 }
 
+// SecretID returns the SecretID of the token, which is empty if the token's
+// secret is stored encrypted.
 func (a *ACLTokenEvent) SecretID() string {
 	return a.secretID
 }
 
+// SecretHash returns the hash of the SecretID of the token.
+func (a *ACLTokenEvent) SecretHash() string {
+	return a.secretHash
+}
+
 type ACLPolicyEvent struct {
 	ACLPolicy *ACLPolicy
 }
@@ -0,0 +1,259 @@
+package structs
+
+import (
+	"crypto/rand"
+	"fmt"
+	"time"
+
+	"github.com/hashicorp/nomad/helper"
+	"github.com/hashicorp/nomad/helper/uuid"
+)
+
+// EncryptionAlgorithm chooses which algorithm is used for encrypting and
+// decrypting data with a root key.
+type EncryptionAlgorithm string
+
+const (
+	EncryptionAlgorithmAES256GCM EncryptionAlgorithm = "aes256-gcm"
+)
+
+// RootKeyState enumerates the possible states of a root key.
+type RootKeyState string
+
+const (
+	// RootKeyStateInactive keys can be used for decryption but not encryption.
+	RootKeyStateInactive RootKeyState = "inactive"
+
+	// RootKeyStateActive is the single key used for all new encryption.
+	RootKeyStateActive RootKeyState = "active"
+)
+
+// RootKey is used to encrypt and decrypt variables. It is never stored in
+// Raft; only its metadata is.
+type RootKey struct {
+	Meta *RootKeyMeta
+	Key  []byte // serialized to keystore as base64 blob
+}
+
+// NewRootKey returns a new root key and its metadata.
+func NewRootKey(algorithm EncryptionAlgorithm) (*RootKey, error) {
+	meta := NewRootKeyMeta()
+	meta.Algorithm = algorithm
+
+	rootKey := &RootKey{
+		Meta: meta,
+	}
+
+	switch algorithm {
+	case EncryptionAlgorithmAES256GCM:
+		key := make([]byte, 32)
+		if _, err := rand.Read(key); err != nil {
+			return nil, fmt.Errorf("failed to generate key: %v", err)
+		}
+		rootKey.Key = key
+	default:
+		return nil, fmt.Errorf("unsupported encryption algorithm %q", algorithm)
+	}
+
+	return rootKey, nil
+}
+
+// Copy returns a deep copy of the root key.
+func (k *RootKey) Copy() *RootKey {
+	if k == nil {
+		return nil
+	}
+	key := make([]byte, len(k.Key))
+	copy(key, k.Key)
+	return &RootKey{
+		Meta: k.Meta.Copy(),
+		Key:  key,
+	}
+}
+
+// RootKeyMeta is the metadata used to refer to a RootKey. It is stored in
+// raft.
+type RootKeyMeta struct {
+	KeyID       string // UUID
+	Algorithm   EncryptionAlgorithm
+	CreateTime  int64
+	CreateIndex uint64
+	ModifyIndex uint64
+	State       RootKeyState
+}
+
+// NewRootKeyMeta returns a new RootKeyMeta with default values.
+func NewRootKeyMeta() *RootKeyMeta {
+	return &RootKeyMeta{
+		KeyID:      uuid.Generate(),
+		Algorithm:  EncryptionAlgorithmAES256GCM,
+		State:      RootKeyStateInactive,
+		CreateTime: time.Now().UTC().UnixNano(),
+	}
+}
+
+// Active indicates this key is the one currently being used for crypto
+// operations (at most one key can be Active).
+func (rkm *RootKeyMeta) Active() bool {
+	return rkm.State == RootKeyStateActive
+}
+
+// SetActive marks the key as the one used for encryption.
+func (rkm *RootKeyMeta) SetActive() {
+	rkm.State = RootKeyStateActive
+}
+
+// SetInactive marks the key as no longer used for encryption. It can still be
+// used to decrypt existing data.
+func (rkm *RootKeyMeta) SetInactive() {
+	rkm.State = RootKeyStateInactive
+}
+
+// Copy returns a copy of the root key metadata.
+func (rkm *RootKeyMeta) Copy() *RootKeyMeta {
+	if rkm == nil {
+		return nil
+	}
+	out := *rkm
+	return &out
+}
+
+// Validate checks the root key metadata for required fields.
+func (rkm *RootKeyMeta) Validate() error {
+	if rkm == nil {
+		return fmt.Errorf("root key metadata is required")
+	}
+	if rkm.KeyID == "" || !helper.IsUUID(rkm.KeyID) {
+		return fmt.Errorf("root key UUID is required")
+	}
+	if rkm.Algorithm == "" {
+		return fmt.Errorf("root key algorithm is required")
+	}
+	switch rkm.State {
+	case RootKeyStateInactive, RootKeyStateActive:
+	default:
+		return fmt.Errorf("root key state %q is invalid", rkm.State)
+	}
+	return nil
+}
+
+// KeyringUpdateRootKeyMetaRequest is used internally by the leader to write
+// the metadata of a new root key to Raft.
+type KeyringUpdateRootKeyMetaRequest struct {
+	RootKeyMeta *RootKeyMeta
+	WriteRequest
+}
+
+// KeyringUpdateRootKeyMetaResponse is the response to a
+// KeyringUpdateRootKeyMetaRequest.
+type KeyringUpdateRootKeyMetaResponse struct {
+	WriteMeta
+}
+
+// KeyringGetRootKeyRequest is used by servers to fetch the key material of a
+// root key from a peer.
+type KeyringGetRootKeyRequest struct {
+	KeyID string
+	QueryOptions
+}
+
+// KeyringGetRootKeyResponse is the response to a KeyringGetRootKeyRequest.
+type KeyringGetRootKeyResponse struct {
+	Key *RootKey
+	QueryMeta
+}
+
+// KeyringRotateRootKeyRequest is used to generate a new root key and make it
+// the active key.
+type KeyringRotateRootKeyRequest struct {
+	Algorithm EncryptionAlgorithm
+
+	// Full indicates that all existing variables and ACL tokens should be
+	// re-encrypted with the new key, so that the previous keys can be
+	// removed.
+	Full bool
+
+	WriteRequest
+}
+
+// KeyringRotateRootKeyResponse returns the metadata of the new active key.
+type KeyringRotateRootKeyResponse struct {
+	Key *RootKeyMeta
+	WriteMeta
+}
+
+// KeyringListRootKeyMetaRequest is used to list the metadata of all root
+// keys. The key material is never returned.
+type KeyringListRootKeyMetaRequest struct {
+	QueryOptions
+}
+
+// KeyringListRootKeyMetaResponse is the response to a
+// KeyringListRootKeyMetaRequest.
+type KeyringListRootKeyMetaResponse struct {
+	Keys []*RootKeyMeta
+	QueryMeta
+}
+
+// KeyringDeleteRootKeyRequest is used to remove an inactive root key from
+// the keyring.
+type KeyringDeleteRootKeyRequest struct {
+	KeyID string
+	WriteRequest
+}
+
+// KeyringDeleteRootKeyResponse is the response to a
+// KeyringDeleteRootKeyRequest.
+type KeyringDeleteRootKeyResponse struct {
+	WriteMeta
+}
+
+// ACLTokenEncrypted is an ACL token whose SecretID has been encrypted with a
+// root key. It is the form in which tokens are written to the Raft log and
+// to snapshots once the keyring has been initialized.
+type ACLTokenEncrypted struct {
+	// Token is a copy of the ACL token with its SecretID removed.
+	Token *ACLToken
+
+	// SecretID is the encrypted SecretID of the token.
+	SecretID []byte
+
+	// KeyID is the ID of the root key used to encrypt the SecretID.
+	KeyID string
+}
+
+// ACLToken returns the token as it is stored in the state store, with only
+// its encrypted SecretID.
+func (e *ACLTokenEncrypted) ACLToken() *ACLToken {
+	token := e.Token.Copy()
+	token.SecretID = ""
+	token.EncryptedSecretID = e.SecretID
+	token.SecretKeyID = e.KeyID
+	return token
+}
+
+const (
+	// PubKeyAlgEdDSA is the algorithm of the public keys used to verify
+	// workload identities.
+	PubKeyAlgEdDSA = "EdDSA"
+
+	// PubKeyUseSig indicates a public key is used to verify signatures.
+	PubKeyUseSig = "sig"
+)
+
+// KeyringPublicKey is the public key used to verify the workload identities
+// signed with a root key.
+type KeyringPublicKey struct {
+	KeyID      string
+	PublicKey  []byte
+	Algorithm  string
+	Use        string
+	CreateTime int64
+}
+
+// KeyringListPublicResponse is the response to a request for the public keys
+// used to verify workload identities.
+type KeyringListPublicResponse struct {
+	PublicKeys []*KeyringPublicKey
+	QueryMeta
+}
@@ -70,6 +70,12 @@ func TestNode_ComputedClass(t *testing.T) {
 	require.NotEqual(n.ComputedClass, old)
 	old = n.ComputedClass
 
+	// Move the node to another node pool
+	n.NodePool = "new-pool"
+	require.NoError(n.ComputeClass())
+	require.NotEqual(n.ComputedClass, old)
+	old = n.ComputedClass
+
 	// Add a device
 	n.NodeResources.Devices = append(n.NodeResources.Devices, &NodeDeviceResource{
 		Vendor: "foo",
@@ -0,0 +1,214 @@
+package structs
+
+import (
+	"fmt"
+	"regexp"
+
+	multierror "github.com/hashicorp/go-multierror"
+	"github.com/hashicorp/nomad/helper"
+)
+
+const (
+	// NodePoolAll is a built-in node pool that always includes all nodes in
+	// the cluster. Jobs in this pool may be placed on any node.
+	NodePoolAll = "all"
+	// NodePoolAllDescription is the description of the built-in "all" pool.
+	NodePoolAllDescription = "Node pool with all nodes in the cluster."
+
+	// NodePoolDefault is a built-in node pool for nodes that don't specify
+	// a pool and jobs that don't specify a pool.
+	NodePoolDefault = "default"
+	// NodePoolDefaultDescription is the description of the built-in
+	// "default" pool.
+	NodePoolDefaultDescription = "Default node pool."
+
+	// maxNodePoolDescriptionLength is the maximum length of a node pool's
+	// description field.
+	maxNodePoolDescriptionLength = 256
+)
+
+var (
+	// validNodePoolName is the rule used to validate a node pool name.
+	validNodePoolName = regexp.MustCompile("^[a-zA-Z0-9-_.]{1,128}$")
+)
+
+// ValidateNodePoolName returns an error if the given name is not a valid
+// node pool name.
+func ValidateNodePoolName(pool string) error {
+	if !validNodePoolName.MatchString(pool) {
+		return fmt.Errorf("invalid name %q, must match regex %s", pool, validNodePoolName)
+	}
+	return nil
+}
+
+// NodePool allows partitioning the client nodes of a cluster. Jobs target a
+// single node pool and are only placed on nodes that belong to it.
+type NodePool struct {
+	// Name is the node pool name. It must be unique.
+	Name string
+
+	// Description is the human-friendly description of the node pool.
+	Description string
+
+	// Meta is a set of user-provided metadata for the node pool.
+	Meta map[string]string
+
+	// SchedulerConfiguration holds overrides of the cluster scheduler
+	// configuration that apply to jobs placed in this node pool.
+	SchedulerConfiguration *NodePoolSchedulerConfiguration
+
+	// Raft indexes.
+	CreateIndex uint64
+	ModifyIndex uint64
+}
+
+// GetName returns the name of the node pool. It is used for pagination.
+func (n *NodePool) GetName() string {
+	return n.Name
+}
+
+// Validate returns an error if the node pool is invalid.
+func (n *NodePool) Validate() error {
+	var mErr multierror.Error
+
+	if err := ValidateNodePoolName(n.Name); err != nil {
+		mErr.Errors = append(mErr.Errors, err)
+	}
+	if len(n.Description) > maxNodePoolDescriptionLength {
+		mErr.Errors = append(mErr.Errors, fmt.Errorf("description longer than %d", maxNodePoolDescriptionLength))
+	}
+	if err := n.SchedulerConfiguration.Validate(); err != nil {
+		mErr.Errors = append(mErr.Errors, err)
+	}
+
+	return mErr.ErrorOrNil()
+}
+
+// Copy returns a deep copy of the node pool.
+func (n *NodePool) Copy() *NodePool {
+	if n == nil {
+		return nil
+	}
+
+	nc := new(NodePool)
+	*nc = *n
+	nc.Meta = helper.CopyMapStringString(n.Meta)
+	nc.SchedulerConfiguration = n.SchedulerConfiguration.Copy()
+	return nc
+}
+
+// IsBuiltIn returns true if the node pool is one of the built-in pools.
+// Built-in node pools are created automatically by Nomad and can never be
+// deleted or modified so they are always present in the cluster.
+func (n *NodePool) IsBuiltIn() bool {
+	switch n.Name {
+	case NodePoolAll, NodePoolDefault:
+		return true
+	default:
+		return false
+	}
+}
+
+// NodePoolSchedulerConfiguration is the scheduler configuration applied to
+// a node pool. Unset fields fall back to the cluster-wide scheduler
+// configuration.
+type NodePoolSchedulerConfiguration struct {
+	// SchedulerAlgorithm is the scheduling algorithm to use for the pool.
+	// If not defined, the global cluster scheduling algorithm is used.
+	SchedulerAlgorithm SchedulerAlgorithm `hcl:"scheduler_algorithm"`
+
+	// MemoryOversubscriptionEnabled specifies whether memory
+	// oversubscription is enabled for the pool. If not defined, the global
+	// cluster configuration is used.
+	MemoryOversubscriptionEnabled *bool `hcl:"memory_oversubscription_enabled"`
+}
+
+// Copy returns a deep copy of the node pool scheduler configuration.
+func (n *NodePoolSchedulerConfiguration) Copy() *NodePoolSchedulerConfiguration {
+	if n == nil {
+		return nil
+	}
+
+	nc := new(NodePoolSchedulerConfiguration)
+	*nc = *n
+	if n.MemoryOversubscriptionEnabled != nil {
+		nc.MemoryOversubscriptionEnabled = helper.BoolToPtr(*n.MemoryOversubscriptionEnabled)
+	}
+	return nc
+}
+
+// Validate returns an error if the node pool scheduler configuration is
+// invalid.
+func (n *NodePoolSchedulerConfiguration) Validate() error {
+	if n == nil {
+		return nil
+	}
+
+	switch n.SchedulerAlgorithm {
+	case "", SchedulerAlgorithmBinpack, SchedulerAlgorithmSpread:
+	default:
+		return fmt.Errorf("invalid scheduler algorithm %q", n.SchedulerAlgorithm)
+	}
+	return nil
+}
+
+// NodePoolUpsertRequest is used to create or update a set of node pools.
+type NodePoolUpsertRequest struct {
+	NodePools []*NodePool
+	WriteRequest
+}
+
+// NodePoolDeleteRequest is used to delete a set of node pools.
+type NodePoolDeleteRequest struct {
+	Names []string
+	WriteRequest
+}
+
+// NodePoolListRequest is used to list node pools.
+type NodePoolListRequest struct {
+	QueryOptions
+}
+
+// NodePoolListResponse is the response to node pools list request.
+type NodePoolListResponse struct {
+	NodePools []*NodePool
+	QueryMeta
+}
+
+// NodePoolSpecificRequest is used to make RPC requests targeted at a
+// specific node pool.
+type NodePoolSpecificRequest struct {
+	Name string
+	QueryOptions
+}
+
+// SingleNodePoolResponse is the response to a specific node pool request.
+type SingleNodePoolResponse struct {
+	NodePool *NodePool
+	QueryMeta
+}
+
+// NodePoolNodesRequest is used to list the nodes in a node pool.
+type NodePoolNodesRequest struct {
+	Name   string
+	Fields *NodeStubFields
+	QueryOptions
+}
+
+// NodePoolNodesResponse is the response to a node pool nodes request.
+type NodePoolNodesResponse struct {
+	Nodes []*NodeListStub
+	QueryMeta
+}
+
+// NodePoolJobsRequest is used to list the jobs in a node pool.
+type NodePoolJobsRequest struct {
+	Name string
+	QueryOptions
+}
+
+// NodePoolJobsResponse is the response to a node pool jobs request.
+type NodePoolJobsResponse struct {
+	Jobs []*JobListStub
+	QueryMeta
+}
@@ -0,0 +1,112 @@
+package structs
+
+import (
+	"strings"
+	"testing"
+
+	"github.com/hashicorp/nomad/helper"
+	"github.com/stretchr/testify/require"
+)
+
+func TestNodePool_Validate(t *testing.T) {
+	t.Parallel()
+
+	testCases := []struct {
+		name          string
+		pool          *NodePool
+		expectedError string
+	}{
+		{
+			name: "valid pool",
+			pool: &NodePool{Name: "valid", Description: "desc"},
+		},
+		{
+			name:          "invalid name",
+			pool:          &NodePool{Name: "not@valid"},
+			expectedError: "invalid name",
+		},
+		{
+			name:          "empty name",
+			pool:          &NodePool{},
+			expectedError: "invalid name",
+		},
+		{
+			name:          "description too long",
+			pool:          &NodePool{Name: "valid", Description: strings.Repeat("a", 300)},
+			expectedError: "description longer than",
+		},
+		{
+			name: "invalid scheduler algorithm",
+			pool: &NodePool{
+				Name: "valid",
+				SchedulerConfiguration: &NodePoolSchedulerConfiguration{
+					SchedulerAlgorithm: "invalid",
+				},
+			},
+			expectedError: "invalid scheduler algorithm",
+		},
+	}
+
+	for _, tc := range testCases {
+		t.Run(tc.name, func(t *testing.T) {
+			err := tc.pool.Validate()
+			if tc.expectedError == "" {
+				require.NoError(t, err)
+			} else {
+				require.Error(t, err)
+				require.Contains(t, err.Error(), tc.expectedError)
+			}
+		})
+	}
+}
+
+func TestNodePool_Copy(t *testing.T) {
+	t.Parallel()
+
+	pool := &NodePool{
+		Name: "original",
+		Meta: map[string]string{"team": "platform"},
+		SchedulerConfiguration: &NodePoolSchedulerConfiguration{
+			SchedulerAlgorithm:            SchedulerAlgorithmSpread,
+			MemoryOversubscriptionEnabled: helper.BoolToPtr(true),
+		},
+	}
+	poolCopy := pool.Copy()
+	require.Equal(t, pool, poolCopy)
+
+	poolCopy.Meta["team"] = "other"
+	*poolCopy.SchedulerConfiguration.MemoryOversubscriptionEnabled = false
+	require.Equal(t, "platform", pool.Meta["team"])
+	require.True(t, *pool.SchedulerConfiguration.MemoryOversubscriptionEnabled)
+}
+
+func TestSchedulerConfiguration_WithNodePool(t *testing.T) {
+	t.Parallel()
+
+	global := &SchedulerConfiguration{
+		SchedulerAlgorithm:            SchedulerAlgorithmBinpack,
+		MemoryOversubscriptionEnabled: false,
+	}
+
+	// A pool without overrides keeps the global configuration.
+	require.Equal(t, global, global.WithNodePool(&NodePool{Name: "empty"}))
+
+	pool := &NodePool{
+		Name: "spread",
+		SchedulerConfiguration: &NodePoolSchedulerConfiguration{
+			SchedulerAlgorithm:            SchedulerAlgorithmSpread,
+			MemoryOversubscriptionEnabled: helper.BoolToPtr(true),
+		},
+	}
+	got := global.WithNodePool(pool)
+	require.Equal(t, SchedulerAlgorithmSpread, got.EffectiveSchedulerAlgorithm())
+	require.True(t, got.MemoryOversubscriptionEnabled)
+
+	// The global configuration must not be modified.
+	require.Equal(t, SchedulerAlgorithmBinpack, global.SchedulerAlgorithm)
+	require.False(t, global.MemoryOversubscriptionEnabled)
+
+	// Overrides apply even when there is no global configuration.
+	var nilConfig *SchedulerConfiguration
+	require.Equal(t, SchedulerAlgorithmSpread, nilConfig.WithNodePool(pool).EffectiveSchedulerAlgorithm())
+}
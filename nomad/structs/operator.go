@@ -151,6 +151,18 @@ type SchedulerConfiguration struct {
 
 	MemoryOversubscriptionEnabled bool `hcl:"memory_oversubscription_enabled"`
 
+	// PauseEvalBroker is a boolean to control whether the evaluation broker
+	// should be paused on the cluster leader. Only a single broker runs per
+	// region, and it must be persisted to state so the parameter is consistent
+	// during leadership transitions.
+	PauseEvalBroker bool `hcl:"pause_eval_broker"`
+
+	// EvalBrokerFairShare enables weighted fair-share dequeueing of
+	// evaluations across namespaces, so a single namespace with many
+	// evaluations cannot starve the others. Namespaces are weighted by
+	// their Weight field.
+	EvalBrokerFairShare bool `hcl:"eval_broker_fair_share"`
+
 	// CreateIndex/ModifyIndex store the create/modify indexes of this configuration.
 	CreateIndex uint64
 	ModifyIndex uint64
@@ -164,6 +176,27 @@ func (s *SchedulerConfiguration) EffectiveSchedulerAlgorithm() SchedulerAlgorith
 	return s.SchedulerAlgorithm
 }
 
+// WithNodePool returns a copy of the scheduler configuration with the
+// overrides of the given node pool applied.
+func (s *SchedulerConfiguration) WithNodePool(pool *NodePool) *SchedulerConfiguration {
+	if pool == nil || pool.SchedulerConfiguration == nil {
+		return s
+	}
+
+	var sc SchedulerConfiguration
+	if s != nil {
+		sc = *s
+	}
+	poolConfig := pool.SchedulerConfiguration
+	if poolConfig.SchedulerAlgorithm != "" {
+		sc.SchedulerAlgorithm = poolConfig.SchedulerAlgorithm
+	}
+	if poolConfig.MemoryOversubscriptionEnabled != nil {
+		sc.MemoryOversubscriptionEnabled = *poolConfig.MemoryOversubscriptionEnabled
+	}
+	return &sc
+}
+
 func (s *SchedulerConfiguration) Canonicalize() {
 	if s != nil && s.SchedulerAlgorithm == "" {
 		s.SchedulerAlgorithm = SchedulerAlgorithmBinpack
@@ -0,0 +1,261 @@
+package structs
+
+import (
+	"errors"
+	"fmt"
+	"regexp"
+	"time"
+
+	multierror "github.com/hashicorp/go-multierror"
+	"github.com/hashicorp/nomad/helper"
+)
+
+const (
+	// ReservationTaskName is the name of the task of the phantom allocations
+	// holding the capacity of reservations.
+	ReservationTaskName = "reservation"
+)
+
+var (
+	// validReservationName is the rule used to validate a reservation name.
+	validReservationName = regexp.MustCompile("^[a-zA-Z0-9-_.]{1,128}$")
+)
+
+// Reservation holds capacity on client nodes for workloads that will be
+// submitted later, such as the jobs of a planned failover. The instances of a
+// reservation are placed on nodes when it is created and are accounted for as
+// phantom allocations until they are claimed by the allocations of jobs that
+// reference the reservation.
+type Reservation struct {
+	// Name is the name of the reservation. It is unique within a namespace.
+	Name string
+
+	// Namespace is the namespace of the reservation. Only jobs in the same
+	// namespace can claim it.
+	Namespace string
+
+	// Datacenters are the datacenters the instances may be placed in.
+	Datacenters []string
+
+	// NodePool is the node pool the instances may be placed in.
+	NodePool string
+
+	// Constraints restrict the nodes the instances may be placed on.
+	Constraints []*Constraint
+
+	// Resources are the resources held by each instance.
+	Resources *ReservationResources
+
+	// Count is the number of instances to reserve.
+	Count int
+
+	// ExpiresAt is when the reservation stops holding capacity. The zero
+	// value means the reservation never expires.
+	ExpiresAt time.Time
+
+	// NodeIDs are the nodes the instances are placed on, one per instance.
+	// They are set by the servers when the reservation is created.
+	NodeIDs []string
+
+	// Raft indexes.
+	CreateIndex uint64
+	ModifyIndex uint64
+}
+
+// ReservationResources are the resources held by each instance of a
+// reservation.
+type ReservationResources struct {
+	CPU      int
+	MemoryMB int
+	DiskMB   int
+}
+
+// Copy returns a copy of the reservation resources.
+func (r *ReservationResources) Copy() *ReservationResources {
+	if r == nil {
+		return nil
+	}
+	nr := *r
+	return &nr
+}
+
+// Canonicalize sets the defaults of the reservation.
+func (r *Reservation) Canonicalize() {
+	if r.Namespace == "" {
+		r.Namespace = DefaultNamespace
+	}
+	if r.NodePool == "" {
+		r.NodePool = NodePoolDefault
+	}
+}
+
+// Validate returns an error if the reservation is invalid.
+func (r *Reservation) Validate() error {
+	var mErr multierror.Error
+
+	if !validReservationName.MatchString(r.Name) {
+		mErr.Errors = append(mErr.Errors, fmt.Errorf("invalid name %q, must match regex %s", r.Name, validReservationName))
+	}
+	if len(r.Datacenters) == 0 {
+		mErr.Errors = append(mErr.Errors, errors.New("Missing reservation datacenters"))
+	}
+	for _, dc := range r.Datacenters {
+		if dc == "" {
+			mErr.Errors = append(mErr.Errors, errors.New("Reservation datacenter must be non-empty string"))
+		}
+	}
+	if r.Count <= 0 {
+		mErr.Errors = append(mErr.Errors, errors.New("Reservation count must be positive"))
+	}
+	if r.Resources == nil {
+		mErr.Errors = append(mErr.Errors, errors.New("Missing reservation resources"))
+	} else {
+		if r.Resources.CPU < 0 || r.Resources.MemoryMB < 0 || r.Resources.DiskMB < 0 {
+			mErr.Errors = append(mErr.Errors, errors.New("Reservation resources must not be negative"))
+		}
+		if r.Resources.CPU == 0 && r.Resources.MemoryMB == 0 && r.Resources.DiskMB == 0 {
+			mErr.Errors = append(mErr.Errors, errors.New("Reservation must reserve at least one resource"))
+		}
+	}
+	for idx, c := range r.Constraints {
+		if err := c.Validate(); err != nil {
+			outer := fmt.Errorf("Constraint %d validation failed: %s", idx+1, err)
+			mErr.Errors = append(mErr.Errors, outer)
+		}
+	}
+
+	return mErr.ErrorOrNil()
+}
+
+// Copy returns a deep copy of the reservation.
+func (r *Reservation) Copy() *Reservation {
+	if r == nil {
+		return nil
+	}
+
+	nr := new(Reservation)
+	*nr = *r
+	nr.Datacenters = helper.CopySliceString(r.Datacenters)
+	nr.Constraints = CopySliceConstraints(r.Constraints)
+	nr.Resources = r.Resources.Copy()
+	nr.NodeIDs = helper.CopySliceString(r.NodeIDs)
+	return nr
+}
+
+// GetName returns the name of the reservation. It is used for pagination.
+func (r *Reservation) GetName() string {
+	return r.Name
+}
+
+// Expired returns whether the reservation no longer holds capacity at the
+// given time.
+func (r *Reservation) Expired(now time.Time) bool {
+	return !r.ExpiresAt.IsZero() && !now.Before(r.ExpiresAt)
+}
+
+// ClaimedBy returns whether the job claims the reservation.
+func (r *Reservation) ClaimedBy(job *Job) bool {
+	return job != nil && job.Reservation != "" &&
+		job.Namespace == r.Namespace && job.Reservation == r.Name
+}
+
+// AllocatedResources returns the resources held by a single instance of the
+// reservation.
+func (r *Reservation) AllocatedResources() *AllocatedResources {
+	return &AllocatedResources{
+		Tasks: map[string]*AllocatedTaskResources{
+			ReservationTaskName: {
+				Cpu: AllocatedCpuResources{
+					CpuShares: int64(r.Resources.CPU),
+				},
+				Memory: AllocatedMemoryResources{
+					MemoryMB: int64(r.Resources.MemoryMB),
+				},
+			},
+		},
+		Shared: AllocatedSharedResources{
+			DiskMB: int64(r.Resources.DiskMB),
+		},
+	}
+}
+
+// ReservationAllocs returns phantom allocations holding the capacity of the
+// instances of the reservations placed on the node that aren't claimed. Each
+// non-terminal allocation of a job claiming a reservation claims one of its
+// instances on the node. Allocations without a job, such as the placements of
+// a plan, belong to the passed job. Expired reservations don't hold any
+// capacity.
+func ReservationAllocs(nodeID string, reservations []*Reservation, allocs []*Allocation, job *Job, now time.Time) []*Allocation {
+	var phantoms []*Allocation
+	for _, r := range reservations {
+		if r.Expired(now) || r.Resources == nil {
+			continue
+		}
+
+		unclaimed := 0
+		for _, id := range r.NodeIDs {
+			if id == nodeID {
+				unclaimed++
+			}
+		}
+		for _, alloc := range allocs {
+			allocJob := alloc.Job
+			if allocJob == nil {
+				allocJob = job
+			}
+			if !alloc.TerminalStatus() && r.ClaimedBy(allocJob) {
+				unclaimed--
+			}
+		}
+
+		for i := 0; i < unclaimed; i++ {
+			phantoms = append(phantoms, &Allocation{
+				ID:                 fmt.Sprintf("reservation.%s.%s[%d]", r.Namespace, r.Name, i),
+				Namespace:          r.Namespace,
+				NodeID:             nodeID,
+				TaskGroup:          r.Name,
+				AllocatedResources: r.AllocatedResources(),
+				DesiredStatus:      AllocDesiredStatusRun,
+				ClientStatus:       AllocClientStatusPending,
+			})
+		}
+	}
+	return phantoms
+}
+
+// ReservationUpsertRequest is used to create a reservation.
+type ReservationUpsertRequest struct {
+	Reservation *Reservation
+	WriteRequest
+}
+
+// ReservationDeleteRequest is used to delete a reservation.
+type ReservationDeleteRequest struct {
+	Name string
+	WriteRequest
+}
+
+// ReservationListRequest is used to list reservations.
+type ReservationListRequest struct {
+	QueryOptions
+}
+
+// ReservationListResponse is the response to a reservations list request.
+type ReservationListResponse struct {
+	Reservations []*Reservation
+	QueryMeta
+}
+
+// ReservationSpecificRequest is used to make RPC requests targeted at a
+// specific reservation.
+type ReservationSpecificRequest struct {
+	Name string
+	QueryOptions
+}
+
+// SingleReservationResponse is the response to a specific reservation
+// request.
+type SingleReservationResponse struct {
+	Reservation *Reservation
+	QueryMeta
+}
@@ -0,0 +1,128 @@
+package structs
+
+import (
+	"testing"
+	"time"
+
+	"github.com/stretchr/testify/require"
+)
+
+func testReservation() *Reservation {
+	return &Reservation{
+		Name:        "failover",
+		Namespace:   DefaultNamespace,
+		Datacenters: []string{"dc1"},
+		NodePool:    NodePoolDefault,
+		Resources: &ReservationResources{
+			CPU:      500,
+			MemoryMB: 256,
+		},
+		Count:   3,
+		NodeIDs: []string{"node1", "node1", "node2"},
+	}
+}
+
+func TestReservation_Validate(t *testing.T) {
+	t.Parallel()
+
+	testCases := []struct {
+		name          string
+		fn            func(r *Reservation)
+		expectedError string
+	}{
+		{
+			name: "valid reservation",
+			fn:   func(r *Reservation) {},
+		},
+		{
+			name:          "invalid name",
+			fn:            func(r *Reservation) { r.Name = "bad name" },
+			expectedError: "invalid name",
+		},
+		{
+			name:          "missing datacenters",
+			fn:            func(r *Reservation) { r.Datacenters = nil },
+			expectedError: "Missing reservation datacenters",
+		},
+		{
+			name:          "invalid count",
+			fn:            func(r *Reservation) { r.Count = 0 },
+			expectedError: "count must be positive",
+		},
+		{
+			name:          "missing resources",
+			fn:            func(r *Reservation) { r.Resources = nil },
+			expectedError: "Missing reservation resources",
+		},
+		{
+			name:          "empty resources",
+			fn:            func(r *Reservation) { r.Resources = &ReservationResources{} },
+			expectedError: "at least one resource",
+		},
+		{
+			name: "invalid constraint",
+			fn: func(r *Reservation) {
+				r.Constraints = []*Constraint{{Operand: "bad"}}
+			},
+			expectedError: "Constraint 1 validation failed",
+		},
+	}
+
+	for _, tc := range testCases {
+		t.Run(tc.name, func(t *testing.T) {
+			r := testReservation()
+			tc.fn(r)
+
+			err := r.Validate()
+			if tc.expectedError == "" {
+				require.NoError(t, err)
+			} else {
+				require.Error(t, err)
+				require.Contains(t, err.Error(), tc.expectedError)
+			}
+		})
+	}
+}
+
+func TestReservationAllocs(t *testing.T) {
+	t.Parallel()
+
+	now := time.Now()
+	r := testReservation()
+
+	// Unclaimed instances are phantom allocations on their nodes
+	phantoms := ReservationAllocs("node1", []*Reservation{r}, nil, nil, now)
+	require.Len(t, phantoms, 2)
+	for _, phantom := range phantoms {
+		require.Equal(t, "node1", phantom.NodeID)
+		require.False(t, phantom.TerminalStatus())
+		require.Equal(t, int64(500), phantom.ComparableResources().Flattened.Cpu.CpuShares)
+		require.Equal(t, int64(256), phantom.ComparableResources().Flattened.Memory.MemoryMB)
+	}
+	require.Empty(t, ReservationAllocs("node3", []*Reservation{r}, nil, nil, now))
+
+	// Allocations of claiming jobs claim an instance each
+	claimant := &Job{ID: "web", Namespace: DefaultNamespace, Reservation: r.Name}
+	other := &Job{ID: "batch", Namespace: DefaultNamespace}
+	allocs := []*Allocation{
+		{Job: claimant, DesiredStatus: AllocDesiredStatusRun, ClientStatus: AllocClientStatusRunning},
+		{Job: other, DesiredStatus: AllocDesiredStatusRun, ClientStatus: AllocClientStatusRunning},
+		{Job: claimant, DesiredStatus: AllocDesiredStatusStop, ClientStatus: AllocClientStatusComplete},
+	}
+	require.Len(t, ReservationAllocs("node1", []*Reservation{r}, allocs, nil, now), 1)
+
+	// Allocations without a job belong to the passed job
+	planned := []*Allocation{
+		{DesiredStatus: AllocDesiredStatusRun, ClientStatus: AllocClientStatusPending},
+	}
+	require.Len(t, ReservationAllocs("node1", []*Reservation{r}, planned, nil, now), 2)
+	require.Len(t, ReservationAllocs("node1", []*Reservation{r}, planned, claimant, now), 1)
+
+	// Jobs in other namespaces don't claim the reservation
+	claimant.Namespace = "other"
+	require.Len(t, ReservationAllocs("node1", []*Reservation{r}, allocs, nil, now), 2)
+
+	// Expired reservations don't hold capacity
+	r.ExpiresAt = now.Add(-time.Minute)
+	require.Empty(t, ReservationAllocs("node1", []*Reservation{r}, nil, nil, now))
+}
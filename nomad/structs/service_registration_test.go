@@ -0,0 +1,98 @@
+package structs
+
+import (
+	"testing"
+
+	"github.com/stretchr/testify/require"
+)
+
+func TestServiceRegistration_Copy(t *testing.T) {
+	t.Parallel()
+
+	sr := &ServiceRegistration{
+		ID:          "_nomad-task-ca60e901-675a-0ab2-2e57-2f3b05fdc540-group-api-countdash-api-http",
+		ServiceName: "countdash-api",
+		Namespace:   "default",
+		NodeID:      "2873cf75-42e5-7c45-ca1c-415f3e18be3d",
+		Datacenter:  "dc1",
+		JobID:       "countdash",
+		AllocID:     "ca60e901-675a-0ab2-2e57-2f3b05fdc540",
+		Tags:        []string{"bar"},
+		Address:     "192.168.200.200",
+		Port:        29000,
+	}
+	newSR := sr.Copy()
+	require.True(t, sr.Equals(newSR))
+
+	// Modifying the copy must not modify the original.
+	newSR.Tags[0] = "foo"
+	require.Equal(t, "bar", sr.Tags[0])
+	require.False(t, sr.Equals(newSR))
+
+	var nilSR *ServiceRegistration
+	require.Nil(t, nilSR.Copy())
+}
+
+func TestServiceRegistration_Validate(t *testing.T) {
+	t.Parallel()
+
+	testCases := []struct {
+		name      string
+		sr        *ServiceRegistration
+		expErrStr string
+	}{
+		{
+			name: "valid",
+			sr: &ServiceRegistration{
+				ID:          "_nomad-task-ca60e901-675a-0ab2-2e57-2f3b05fdc540-group-api-countdash-api-http",
+				ServiceName: "countdash-api",
+				Namespace:   "default",
+				NodeID:      "2873cf75-42e5-7c45-ca1c-415f3e18be3d",
+				JobID:       "countdash",
+				AllocID:     "ca60e901-675a-0ab2-2e57-2f3b05fdc540",
+			},
+		},
+		{
+			name: "missing ID",
+			sr: &ServiceRegistration{
+				ServiceName: "countdash-api",
+			},
+			expErrStr: "missing service registration ID",
+		},
+		{
+			name: "missing alloc ID",
+			sr: &ServiceRegistration{
+				ID:          "_nomad-task-ca60e901-675a-0ab2-2e57-2f3b05fdc540-group-api-countdash-api-http",
+				ServiceName: "countdash-api",
+				Namespace:   "default",
+				NodeID:      "2873cf75-42e5-7c45-ca1c-415f3e18be3d",
+				JobID:       "countdash",
+			},
+			expErrStr: `missing allocation ID for service "countdash-api"`,
+		},
+		{
+			name: "invalid service name",
+			sr: &ServiceRegistration{
+				ID:          "_nomad-task-ca60e901-675a-0ab2-2e57-2f3b05fdc540-group-api-countdash-api-http",
+				ServiceName: "--invalid--",
+				Namespace:   "default",
+				NodeID:      "2873cf75-42e5-7c45-ca1c-415f3e18be3d",
+				JobID:       "countdash",
+				AllocID:     "ca60e901-675a-0ab2-2e57-2f3b05fdc540",
+			},
+			expErrStr: "Service name must be valid per RFC 1123",
+		},
+	}
+
+	for _, tc := range testCases {
+		t.Run(tc.name, func(t *testing.T) {
+			err := tc.sr.Validate()
+			if tc.expErrStr == "" {
+				require.NoError(t, err)
+			} else {
+				require.Error(t, err)
+				require.Contains(t, err.Error(), tc.expErrStr)
+			}
+		})
+	}
+}
@@ -468,12 +468,26 @@ type Service struct {
 	// OnUpdate Specifies how the service and its checks should be evaluated
 	// during an update
 	OnUpdate string
+
+	// Provider dictates which service discovery provider to use. This can be
+	// either ServiceProviderConsul or ServiceProviderNomad and defaults to the
+	// former when left empty by the operator.
+	Provider string
 }
 
 const (
 	OnUpdateRequireHealthy = "require_healthy"
 	OnUpdateIgnoreWarn     = "ignore_warnings"
 	OnUpdateIgnore         = "ignore"
+
+	// ServiceProviderConsul is the default service discovery provider and
+	// registers services and checks into Consul.
+	ServiceProviderConsul = "consul"
+
+	// ServiceProviderNomad is the native service discovery provider. Service
+	// registrations are stored within the Nomad state store and do not
+	// support checks or Consul Connect.
+	ServiceProviderNomad = "nomad"
 )
 
 // Copy the stanza recursively. Returns nil if nil.
@@ -533,6 +547,11 @@ func (s *Service) Canonicalize(job string, taskGroup string, task string) {
 	if s.Namespace == "" {
 		s.Namespace = "default"
 	}
+
+	// Default to the Consul service discovery provider.
+	if s.Provider == "" {
+		s.Provider = ServiceProviderConsul
+	}
 }
 
 // Validate checks if the Service definition is valid
@@ -562,6 +581,22 @@ func (s *Service) Validate() error {
 		mErr.Errors = append(mErr.Errors, fmt.Errorf("Service on_update must be %q, %q, or %q; not %q", OnUpdateRequireHealthy, OnUpdateIgnoreWarn, OnUpdateIgnore, s.OnUpdate))
 	}
 
+	switch s.Provider {
+	case "", ServiceProviderConsul:
+		// OK
+	case ServiceProviderNomad:
+		// The Nomad provider only stores the service address and tags, so
+		// reject the Consul specific features it cannot honor.
+		if len(s.Checks) > 0 {
+			mErr.Errors = append(mErr.Errors, fmt.Errorf("Service with provider %q does not support checks", ServiceProviderNomad))
+		}
+		if s.Connect != nil {
+			mErr.Errors = append(mErr.Errors, fmt.Errorf("Service with provider %q does not support Consul Connect", ServiceProviderNomad))
+		}
+	default:
+		mErr.Errors = append(mErr.Errors, fmt.Errorf("Service provider must be %q or %q; not %q", ServiceProviderConsul, ServiceProviderNomad, s.Provider))
+	}
+
 	// check checks
 	for _, c := range s.Checks {
 		if s.PortLabel == "" && c.PortLabel == "" && c.RequiresPort() {
@@ -598,6 +633,15 @@ func (s *Service) Validate() error {
 	return mErr.ErrorOrNil()
 }
 
+// providerOrDefault returns the service discovery provider of the service,
+// accounting for services which have not yet been canonicalized.
+func (s *Service) providerOrDefault() string {
+	if s.Provider == "" {
+		return ServiceProviderConsul
+	}
+	return s.Provider
+}
+
 // ValidateName checks if the service Name is valid and should be called after
 // the name has been interpolated
 func (s *Service) ValidateName(name string) error {
@@ -747,6 +791,10 @@ OUTER:
 		return false
 	}
 
+	if s.Provider != o.Provider {
+		return false
+	}
+
 	return true
 }
 
@@ -292,6 +292,82 @@ func TestService_Hash(t *testing.T) {
 	})
 }
 
+func TestService_Validate_Provider(t *testing.T) {
+	t.Parallel()
+
+	testCases := []struct {
+		name      string
+		service   *Service
+		expErrStr string
+	}{
+		{
+			name: "consul provider with checks",
+			service: &Service{
+				Name:     "testservice",
+				Provider: ServiceProviderConsul,
+				Checks: []*ServiceCheck{{
+					Name:     "check",
+					Type:     ServiceCheckTCP,
+					Interval: 10 * time.Second,
+					Timeout:  2 * time.Second,
+				}},
+				PortLabel: "http",
+			},
+		},
+		{
+			name: "nomad provider",
+			service: &Service{
+				Name:     "testservice",
+				Provider: ServiceProviderNomad,
+			},
+		},
+		{
+			name: "nomad provider with checks",
+			service: &Service{
+				Name:     "testservice",
+				Provider: ServiceProviderNomad,
+				Checks: []*ServiceCheck{{
+					Name:     "check",
+					Type:     ServiceCheckTCP,
+					Interval: 10 * time.Second,
+					Timeout:  2 * time.Second,
+				}},
+				PortLabel: "http",
+			},
+			expErrStr: `does not support checks`,
+		},
+		{
+			name: "nomad provider with connect",
+			service: &Service{
+				Name:     "testservice",
+				Provider: ServiceProviderNomad,
+				Connect:  &ConsulConnect{Native: true},
+			},
+			expErrStr: `does not support Consul Connect`,
+		},
+		{
+			name: "unknown provider",
+			service: &Service{
+				Name:     "testservice",
+				Provider: "nope",
+			},
+			expErrStr: `Service provider must be "consul" or "nomad"; not "nope"`,
+		},
+	}
+
+	for _, tc := range testCases {
+		t.Run(tc.name, func(t *testing.T) {
+			err := tc.service.Validate()
+			if tc.expErrStr == "" {
+				require.NoError(t, err)
+			} else {
+				require.Error(t, err)
+				require.Contains(t, err.Error(), tc.expErrStr)
+			}
+		})
+	}
+}
+
 func TestConsulConnect_Validate(t *testing.T) {
 	t.Parallel()
 
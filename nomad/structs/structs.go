@@ -16,6 +16,7 @@ import (
 	"hash/crc32"
 	"math"
 	"net"
+	"net/url"
 	"os"
 	"path/filepath"
 	"reflect"
@@ -104,6 +105,16 @@ const (
 	OneTimeTokenUpsertRequestType                MessageType = 44
 	OneTimeTokenDeleteRequestType                MessageType = 45
 	OneTimeTokenExpireRequestType                MessageType = 46
+	ServiceRegistrationUpsertRequestType         MessageType = 47
+	ServiceRegistrationDeleteByIDRequestType     MessageType = 48
+	ServiceRegistrationDeleteByNodeIDRequestType MessageType = 49
+	VarApplyStateRequestType                     MessageType = 50
+	RootKeyMetaUpsertRequestType                 MessageType = 51
+	RootKeyMetaDeleteRequestType                 MessageType = 52
+	NodePoolUpsertRequestType                    MessageType = 53
+	NodePoolDeleteRequestType                    MessageType = 54
+	ReservationUpsertRequestType                 MessageType = 55
+	ReservationDeleteRequestType                 MessageType = 56
 
 	// Namespace types were moved from enterprise and therefore start at 64
 	NamespaceUpsertRequestType MessageType = 64
@@ -281,6 +292,14 @@ type QueryOptions struct {
 	// that support paginated lists.
 	NextToken string
 
+	// Filter is a boolean expression evaluated against the objects returned
+	// by queries that support filtered lists.
+	Filter string
+
+	// Reverse is used to reverse the default order of list results in
+	// queries that support paginated lists.
+	Reverse bool
+
 	InternalRpcInfo
 }
 
@@ -436,6 +455,11 @@ type QueryMeta struct {
 
 	// Used to indicate if there is a known leader node
 	KnownLeader bool
+
+	// NextToken is the token returned with queries that support paginated
+	// lists. To resume paging from this point, pass this token in the next
+	// request's QueryOptions.
+	NextToken string
 }
 
 // WriteMeta allows a write response to include potentially
@@ -663,6 +687,8 @@ type JobPlanRequest struct {
 	Diff bool // Toggles an annotated diff
 	// PolicyOverride is set when the user is attempting to override any policies
 	PolicyOverride bool
+	// Explain toggles recording why each node was or wasn't selected
+	Explain bool
 	WriteRequest
 }
 
@@ -733,6 +759,11 @@ type JobDispatchRequest struct {
 	JobID   string
 	Payload []byte
 	Meta    map[string]string
+
+	// MaxRunDuration, if set, overrides the max_run_duration of every task
+	// group of the dispatched job.
+	MaxRunDuration time.Duration
+
 	WriteRequest
 }
 
@@ -803,9 +834,33 @@ type EvalUpdateRequest struct {
 type EvalDeleteRequest struct {
 	Evals  []string
 	Allocs []string
+
+	// Filter is a filter expression selecting the evaluations to delete. It
+	// is only used by the Eval.Delete RPC and cannot be combined with Evals.
+	Filter string
+
 	WriteRequest
 }
 
+// EvalDeleteResponse is used to respond to an Eval.Delete request.
+type EvalDeleteResponse struct {
+	// Count is the number of evaluations deleted.
+	Count int
+	WriteMeta
+}
+
+// EvalCountRequest is used to count the evaluations matching the filter
+// expression of the query.
+type EvalCountRequest struct {
+	QueryOptions
+}
+
+// EvalCountResponse is used to respond to an Eval.Count request.
+type EvalCountResponse struct {
+	Count int
+	QueryMeta
+}
+
 // EvalSpecificRequest is used when we just need to specify a target evaluation
 type EvalSpecificRequest struct {
 	EvalID string
@@ -1413,6 +1468,10 @@ type JobPlanResponse struct {
 	// FailedTGAllocs is the placement failures per task group.
 	FailedTGAllocs map[string]*AllocMetric
 
+	// PlacedTGAllocs is the metrics of the first placement of each task
+	// group. It is only populated when the plan is explained.
+	PlacedTGAllocs map[string]*AllocMetric
+
 	// JobModifyIndex is the modification index of the job. The value can be
 	// used when running `nomad run` to ensure that the Job wasn’t modified
 	// since the last plan. If the job is being created, the value is zero.
@@ -1655,9 +1714,10 @@ func (ne *NodeEvent) AddDetail(k, v string) *NodeEvent {
 }
 
 const (
-	NodeStatusInit  = "initializing"
-	NodeStatusReady = "ready"
-	NodeStatusDown  = "down"
+	NodeStatusInit         = "initializing"
+	NodeStatusReady        = "ready"
+	NodeStatusDown         = "down"
+	NodeStatusDisconnected = "disconnected"
 )
 
 // ShouldDrainNode checks if a given node status should trigger an
@@ -1666,7 +1726,7 @@ func ShouldDrainNode(status string) bool {
 	switch status {
 	case NodeStatusInit, NodeStatusReady:
 		return false
-	case NodeStatusDown:
+	case NodeStatusDown, NodeStatusDisconnected:
 		return true
 	default:
 		panic(fmt.Sprintf("unhandled node status %s", status))
@@ -1676,7 +1736,7 @@ func ShouldDrainNode(status string) bool {
 // ValidNodeStatus is used to check if a node status is valid
 func ValidNodeStatus(status string) bool {
 	switch status {
-	case NodeStatusInit, NodeStatusReady, NodeStatusDown:
+	case NodeStatusInit, NodeStatusReady, NodeStatusDown, NodeStatusDisconnected:
 		return true
 	default:
 		return false
@@ -1822,6 +1882,9 @@ type Node struct {
 	// Datacenter for this node
 	Datacenter string
 
+	// NodePool is the node pool the node belongs to.
+	NodePool string
+
 	// Node name
 	Name string
 
@@ -1930,6 +1993,15 @@ func (n *Node) Sanitize() *Node {
 	return clean
 }
 
+// GetID is a helper for getting the ID when the object may be nil and is
+// required for pagination.
+func (n *Node) GetID() string {
+	if n == nil {
+		return ""
+	}
+	return n.ID
+}
+
 // Ready returns true if the node is ready for running allocations
 func (n *Node) Ready() bool {
 	return n.Status == NodeStatusReady && n.DrainStrategy == nil && n.SchedulingEligibility == NodeSchedulingEligible
@@ -1948,6 +2020,12 @@ func (n *Node) Canonicalize() {
 		n.SchedulingEligibility = NodeSchedulingEligible
 	}
 
+	// Nodes that don't specify a pool, including nodes registered before
+	// node pools existed, belong to the default pool.
+	if n.NodePool == "" {
+		n.NodePool = NodePoolDefault
+	}
+
 	// COMPAT remove in 1.0
 	// In v0.12.0 we introduced a separate node specific network resource struct
 	// so we need to covert any pre 0.12 clients to the correct struct
@@ -2134,6 +2212,7 @@ func (n *Node) Stub(fields *NodeStubFields) *NodeListStub {
 		Address:               addr,
 		ID:                    n.ID,
 		Datacenter:            n.Datacenter,
+		NodePool:              n.NodePool,
 		Name:                  n.Name,
 		NodeClass:             n.NodeClass,
 		Version:               n.Attributes["nomad.version"],
@@ -2164,6 +2243,7 @@ type NodeListStub struct {
 	Address               string
 	ID                    string
 	Datacenter            string
+	NodePool              string
 	Name                  string
 	NodeClass             string
 	Version               string
@@ -2196,12 +2276,73 @@ type Resources struct {
 	IOPS        int // COMPAT(0.10): Only being used to issue warnings
 	Networks    Networks
 	Devices     ResourceDevices
+
+	// NUMA is omitted from msgpack when unset so that the legacy resources
+	// embedded in every allocation don't grow the plan raft log entries.
+	NUMA *NUMA `codec:",omitempty"`
 }
 
 const (
 	BytesInMegabyte = 1024 * 1024
 )
 
+const (
+	// NUMAAffinityNone places the reserved cores of a task without regard
+	// for the NUMA topology of the node.
+	NUMAAffinityNone = "none"
+
+	// NUMAAffinityPrefer places the reserved cores of a task on a single
+	// NUMA node when possible, falling back to spanning NUMA nodes.
+	NUMAAffinityPrefer = "prefer"
+
+	// NUMAAffinityRequire only places a task on nodes where all its reserved
+	// cores fit on a single NUMA node.
+	NUMAAffinityRequire = "require"
+)
+
+// NUMA is used to describe how the reserved cores of a task are placed in
+// relation to the NUMA topology of the node.
+type NUMA struct {
+	// Affinity is one of "none", "prefer" or "require".
+	Affinity string
+}
+
+// Copy returns a deep copy of the NUMA block.
+func (n *NUMA) Copy() *NUMA {
+	if n == nil {
+		return nil
+	}
+	nn := *n
+	return &nn
+}
+
+// Equals returns whether the NUMA blocks are equivalent.
+func (n *NUMA) Equals(o *NUMA) bool {
+	return n.EffectiveAffinity() == o.EffectiveAffinity()
+}
+
+// EffectiveAffinity returns the NUMA affinity, defaulting to "none".
+func (n *NUMA) EffectiveAffinity() string {
+	if n == nil || n.Affinity == "" {
+		return NUMAAffinityNone
+	}
+	return n.Affinity
+}
+
+// Validate returns an error if the NUMA block is invalid.
+func (n *NUMA) Validate() error {
+	if n == nil {
+		return nil
+	}
+	switch n.Affinity {
+	case "", NUMAAffinityNone, NUMAAffinityPrefer, NUMAAffinityRequire:
+		return nil
+	default:
+		return fmt.Errorf("invalid numa affinity %q, must be one of %q, %q or %q",
+			n.Affinity, NUMAAffinityNone, NUMAAffinityPrefer, NUMAAffinityRequire)
+	}
+}
+
 // DefaultResources is a small resources object that contains the
 // default resources requests that we will provide to an object.
 // ---  THIS FUNCTION IS REPLICATED IN api/resources.go and should
@@ -2258,6 +2399,12 @@ func (r *Resources) Validate() error {
 		mErr.Errors = append(mErr.Errors, fmt.Errorf("MemoryMaxMB value (%d) should be larger than MemoryMB value (%d)", r.MemoryMaxMB, r.MemoryMB))
 	}
 
+	if err := r.NUMA.Validate(); err != nil {
+		mErr.Errors = append(mErr.Errors, err)
+	} else if r.NUMA.EffectiveAffinity() != NUMAAffinityNone && r.Cores == 0 {
+		mErr.Errors = append(mErr.Errors, errors.New("NUMA affinity requires the task to reserve 'cores'"))
+	}
+
 	return mErr.ErrorOrNil()
 }
 
@@ -2285,6 +2432,9 @@ func (r *Resources) Merge(other *Resources) {
 	if len(other.Devices) != 0 {
 		r.Devices = other.Devices
 	}
+	if other.NUMA != nil {
+		r.NUMA = other.NUMA.Copy()
+	}
 }
 
 // Equals Resources.
@@ -2304,7 +2454,8 @@ func (r *Resources) Equals(o *Resources) bool {
 		r.DiskMB == o.DiskMB &&
 		r.IOPS == o.IOPS &&
 		r.Networks.Equals(&o.Networks) &&
-		r.Devices.Equals(&o.Devices)
+		r.Devices.Equals(&o.Devices) &&
+		r.NUMA.Equals(o.NUMA)
 }
 
 // ResourceDevices are part of Resources.
@@ -2392,6 +2543,8 @@ func (r *Resources) Copy() *Resources {
 		}
 	}
 
+	newR.NUMA = r.NUMA.Copy()
+
 	return newR
 }
 
@@ -2999,6 +3152,32 @@ type NodeCpuResources struct {
 	// This value is currently only reported on Linux platforms which support cgroups and is
 	// discovered by inspecting the cpuset of the agent's cgroup.
 	ReservableCpuCores []uint16
+
+	// NUMANodes is the NUMA topology of the node. It is only reported on
+	// Linux platforms.
+	NUMANodes []NUMANode
+}
+
+// NUMANode describes a NUMA node of a client and the cores that belong to it.
+type NUMANode struct {
+	// ID is the NUMA node ID as reported by the operating system.
+	ID uint16
+
+	// Cores is the set of cpus that belong to the NUMA node.
+	Cores []uint16
+}
+
+// NUMANodeOf returns the ID of the NUMA node the core belongs to, and false if
+// the node has no NUMA topology or the core is not part of it.
+func (n *NodeCpuResources) NUMANodeOf(core uint16) (uint16, bool) {
+	for _, numaNode := range n.NUMANodes {
+		for _, c := range numaNode.Cores {
+			if c == core {
+				return numaNode.ID, true
+			}
+		}
+	}
+	return 0, false
 }
 
 func (n *NodeCpuResources) Merge(o *NodeCpuResources) {
@@ -3017,6 +3196,10 @@ func (n *NodeCpuResources) Merge(o *NodeCpuResources) {
 	if len(o.ReservableCpuCores) != 0 {
 		n.ReservableCpuCores = o.ReservableCpuCores
 	}
+
+	if len(o.NUMANodes) != 0 {
+		n.NUMANodes = o.NUMANodes
+	}
 }
 
 func (n *NodeCpuResources) Equals(o *NodeCpuResources) bool {
@@ -3044,7 +3227,8 @@ func (n *NodeCpuResources) Equals(o *NodeCpuResources) bool {
 			return false
 		}
 	}
-	return true
+
+	return reflect.DeepEqual(n.NUMANodes, o.NUMANodes)
 }
 
 func (n *NodeCpuResources) SharesPerCore() int64 {
@@ -3709,6 +3893,11 @@ func (a *AllocatedSharedResources) Canonicalize() {
 type AllocatedCpuResources struct {
 	CpuShares     int64
 	ReservedCores []uint16
+
+	// NUMANodes is the set of NUMA nodes the reserved cores belong to. It is
+	// only set for tasks with a NUMA affinity and is used to pin the memory
+	// of the task to those NUMA nodes.
+	NUMANodes []uint16
 }
 
 func (a *AllocatedCpuResources) Add(delta *AllocatedCpuResources) {
@@ -3719,6 +3908,9 @@ func (a *AllocatedCpuResources) Add(delta *AllocatedCpuResources) {
 	a.CpuShares += delta.CpuShares
 
 	a.ReservedCores = cpuset.New(a.ReservedCores...).Union(cpuset.New(delta.ReservedCores...)).ToSlice()
+	if len(delta.NUMANodes) != 0 {
+		a.NUMANodes = cpuset.New(a.NUMANodes...).Union(cpuset.New(delta.NUMANodes...)).ToSlice()
+	}
 }
 
 func (a *AllocatedCpuResources) Subtract(delta *AllocatedCpuResources) {
@@ -3742,6 +3934,10 @@ func (a *AllocatedCpuResources) Max(other *AllocatedCpuResources) {
 	if len(other.ReservedCores) > len(a.ReservedCores) {
 		a.ReservedCores = other.ReservedCores
 	}
+
+	if len(other.NUMANodes) > len(a.NUMANodes) {
+		a.NUMANodes = other.NUMANodes
+	}
 }
 
 // AllocatedMemoryResources captures the allocated memory resources.
@@ -4000,6 +4196,16 @@ type Job struct {
 	// Datacenters contains all the datacenters this job is allowed to span
 	Datacenters []string
 
+	// NodePool specifies the node pool this job is allowed to run on.
+	//
+	// An empty value is allowed during job registration, in which case the
+	// job is placed in the default node pool.
+	NodePool string
+
+	// Reservation is the name of a reservation in the job's namespace whose
+	// capacity the allocations of the job may claim.
+	Reservation string
+
 	// Constraints can be specified at a job level and apply to
 	// all the task groups and tasks.
 	Constraints []*Constraint
@@ -4008,10 +4214,19 @@ type Job struct {
 	// scheduling preferences that apply to all groups and tasks
 	Affinities []*Affinity
 
+	// AllocAffinities can be specified at the job level to express
+	// scheduling preferences relative to the allocations of other jobs
+	// that apply to all groups
+	AllocAffinities []*AllocAffinity
+
 	// Spread can be specified at the job level to express spreading
 	// allocations across a desired attribute, such as datacenter
 	Spreads []*Spread
 
+	// DependsOn are the jobs that must reach a given status before the
+	// evaluations of this job are processed
+	DependsOn []*JobDependency
+
 	// TaskGroups are the collections of task groups that this job needs
 	// to run. Each task group is an atomic unit of scheduling and placement.
 	TaskGroups []*TaskGroup
@@ -4093,6 +4308,24 @@ type Job struct {
 	JobModifyIndex uint64
 }
 
+// GetID is a helper for getting the ID when the object may be nil and is
+// required for pagination.
+func (j *Job) GetID() string {
+	if j == nil {
+		return ""
+	}
+	return j.ID
+}
+
+// GetNamespace is a helper for getting the namespace when the object may be
+// nil and is required for pagination.
+func (j *Job) GetNamespace() string {
+	if j == nil {
+		return ""
+	}
+	return j.Namespace
+}
+
 // NamespacedID returns the namespaced id useful for logging
 func (j *Job) NamespacedID() NamespacedID {
 	return NamespacedID{
@@ -4119,6 +4352,11 @@ func (j *Job) Canonicalize() {
 		j.Namespace = DefaultNamespace
 	}
 
+	// Ensure the job is in a node pool.
+	if j.NodePool == "" {
+		j.NodePool = NodePoolDefault
+	}
+
 	for _, tg := range j.TaskGroups {
 		tg.Canonicalize(j)
 	}
@@ -4147,6 +4385,8 @@ func (j *Job) Copy() *Job {
 	nj.Datacenters = helper.CopySliceString(nj.Datacenters)
 	nj.Constraints = CopySliceConstraints(nj.Constraints)
 	nj.Affinities = CopySliceAffinities(nj.Affinities)
+	nj.AllocAffinities = CopySliceAllocAffinities(nj.AllocAffinities)
+	nj.DependsOn = CopySliceJobDependencies(nj.DependsOn)
 	nj.Multiregion = nj.Multiregion.Copy()
 
 	if j.TaskGroups != nil {
@@ -4204,6 +4444,14 @@ func (j *Job) Validate() error {
 			}
 		}
 	}
+	if j.NodePool != "" {
+		if err := ValidateNodePoolName(j.NodePool); err != nil {
+			mErr.Errors = append(mErr.Errors, fmt.Errorf("Invalid job node pool: %v", err))
+		}
+	}
+	if j.Reservation != "" && !validReservationName.MatchString(j.Reservation) {
+		mErr.Errors = append(mErr.Errors, fmt.Errorf("Invalid job reservation %q", j.Reservation))
+	}
 	if len(j.TaskGroups) == 0 {
 		mErr.Errors = append(mErr.Errors, errors.New("Missing job task groups"))
 	}
@@ -4226,6 +4474,19 @@ func (j *Job) Validate() error {
 		}
 	}
 
+	if j.Type == JobTypeSystem {
+		if j.AllocAffinities != nil {
+			mErr.Errors = append(mErr.Errors, fmt.Errorf("System jobs may not have an alloc_affinity stanza"))
+		}
+	} else {
+		for idx, affinity := range j.AllocAffinities {
+			if err := affinity.Validate(); err != nil {
+				outer := fmt.Errorf("Alloc affinity %d validation failed: %s", idx+1, err)
+				mErr.Errors = append(mErr.Errors, outer)
+			}
+		}
+	}
+
 	if j.Type == JobTypeSystem {
 		if j.Spreads != nil {
 			mErr.Errors = append(mErr.Errors, fmt.Errorf("System jobs may not have a spread stanza"))
@@ -4263,6 +4524,25 @@ func (j *Job) Validate() error {
 			}
 		}
 
+		if tg.MaxClientDisconnect != nil {
+			if *tg.MaxClientDisconnect < 0 {
+				mErr.Errors = append(mErr.Errors, errors.New("max_client_disconnect cannot be negative"))
+			} else if !(j.Type == JobTypeBatch || j.Type == JobTypeService) {
+				mErr.Errors = append(mErr.Errors, errors.New("max_client_disconnect can only be set in batch and service jobs"))
+			}
+			if tg.StopAfterClientDisconnect != nil && *tg.StopAfterClientDisconnect != 0 {
+				mErr.Errors = append(mErr.Errors, errors.New("max_client_disconnect and stop_after_client_disconnect are mutually exclusive"))
+			}
+		}
+
+		if tg.MaxRunDuration != nil {
+			if *tg.MaxRunDuration <= 0 {
+				mErr.Errors = append(mErr.Errors, errors.New("max_run_duration must be a positive value"))
+			} else if !(j.Type == JobTypeBatch || j.Type == JobTypeSysBatch) {
+				mErr.Errors = append(mErr.Errors, errors.New("max_run_duration can only be set in batch and sysbatch jobs"))
+			}
+		}
+
 		if j.Type == "system" && tg.Count > 1 {
 			mErr.Errors = append(mErr.Errors,
 				fmt.Errorf("Job task group %s has count %d. Count cannot exceed 1 with system scheduler",
@@ -4303,6 +4583,23 @@ func (j *Job) Validate() error {
 		}
 	}
 
+	if len(j.DependsOn) != 0 {
+		if j.Type != JobTypeBatch && j.Type != JobTypeSysBatch {
+			mErr.Errors = append(mErr.Errors, fmt.Errorf(
+				"Dependencies can only be used with %q or %q scheduler", JobTypeBatch, JobTypeSysBatch,
+			))
+		}
+
+		for idx, dep := range j.DependsOn {
+			if err := dep.Validate(); err != nil {
+				outer := fmt.Errorf("Dependency %d validation failed: %s", idx+1, err)
+				mErr.Errors = append(mErr.Errors, outer)
+			} else if dep.JobID == j.ID {
+				mErr.Errors = append(mErr.Errors, fmt.Errorf("Dependency %d refers to the job itself", idx+1))
+			}
+		}
+	}
+
 	if j.IsMultiregion() {
 		if err := j.Multiregion.Validate(j.Type, j.Datacenters); err != nil {
 			mErr.Errors = append(mErr.Errors, err)
@@ -4414,6 +4711,7 @@ func (j *Job) Stub(summary *JobSummary) *JobListStub {
 		ParentID:          j.ParentID,
 		Name:              j.Name,
 		Datacenters:       j.Datacenters,
+		NodePool:          j.NodePool,
 		Multiregion:       j.Multiregion,
 		Type:              j.Type,
 		Priority:          j.Priority,
@@ -4586,6 +4884,7 @@ type JobListStub struct {
 	Name              string
 	Namespace         string `json:",omitempty"`
 	Datacenters       []string
+	NodePool          string
 	Multiregion       *Multiregion
 	Type              string
 	Priority          int
@@ -4660,6 +4959,7 @@ type TaskGroupSummary struct {
 	Running  int
 	Starting int
 	Lost     int
+	Unknown  int
 }
 
 const (
@@ -4873,6 +5173,11 @@ type Namespace struct {
 	// against.
 	Quota string
 
+	// Weight is the share of the eval broker the namespace gets relative to
+	// other namespaces when fair-share dequeueing is enabled. A weight of
+	// zero is treated as a weight of one.
+	Weight int
+
 	// Hash is the hash of the namespace which is used to efficiently replicate
 	// cross-regions.
 	Hash []byte
@@ -4894,10 +5199,21 @@ func (n *Namespace) Validate() error {
 		err := fmt.Errorf("description longer than %d", maxNamespaceDescriptionLength)
 		mErr.Errors = append(mErr.Errors, err)
 	}
+	if n.Weight < 0 {
+		mErr.Errors = append(mErr.Errors, errors.New("weight cannot be negative"))
+	}
 
 	return mErr.ErrorOrNil()
 }
 
+// EffectiveWeight returns the fair-share weight of the namespace.
+func (n *Namespace) EffectiveWeight() int {
+	if n.Weight <= 0 {
+		return 1
+	}
+	return n.Weight
+}
+
 // SetHash is used to compute and set the hash of the namespace
 func (n *Namespace) SetHash() []byte {
 	// Initialize a 256bit Blake2 hash (32 bytes)
@@ -4910,6 +5226,9 @@ func (n *Namespace) SetHash() []byte {
 	_, _ = hash.Write([]byte(n.Name))
 	_, _ = hash.Write([]byte(n.Description))
 	_, _ = hash.Write([]byte(n.Quota))
+	if n.Weight != 0 {
+		_, _ = hash.Write([]byte(strconv.Itoa(n.Weight)))
+	}
 
 	// Finalize the hash
 	hashVal := hash.Sum(nil)
@@ -5231,6 +5550,113 @@ func (d *DispatchPayloadConfig) Validate() error {
 	return nil
 }
 
+const (
+	// JobDependencyConditionComplete is met once the upstream job is dead
+	// and every task group completed its allocations successfully.
+	JobDependencyConditionComplete = "complete"
+
+	// JobDependencyConditionFailed is met once the upstream job is dead
+	// without completing successfully.
+	JobDependencyConditionFailed = "failed"
+
+	// JobDependencyConditionDead is met once the upstream job is dead,
+	// regardless of the outcome of its allocations.
+	JobDependencyConditionDead = "dead"
+)
+
+// JobDependency is a job that must reach a given status before the
+// evaluations of a dependent job are processed. Dependencies on periodic or
+// parameterized jobs are met by their children, the jobs with the upstream
+// job as their ParentID, or by a single child if Child is set.
+type JobDependency struct {
+	// JobID is the ID of the upstream job, in the namespace of the dependent
+	// job.
+	JobID string
+
+	// Child is the optional ID of a child of a periodic or parameterized
+	// upstream job, such as a dispatched job. If set, only this child must
+	// reach the condition.
+	Child string
+
+	// Condition is the status the upstream job must reach.
+	Condition string
+}
+
+func (d *JobDependency) Copy() *JobDependency {
+	if d == nil {
+		return nil
+	}
+	nd := new(JobDependency)
+	*nd = *d
+	return nd
+}
+
+func (d *JobDependency) String() string {
+	if d.Child != "" {
+		return fmt.Sprintf("job %q to be %s", d.Child, d.Condition)
+	}
+	return fmt.Sprintf("job %q to be %s", d.JobID, d.Condition)
+}
+
+func (d *JobDependency) Validate() error {
+	var mErr multierror.Error
+	if d.JobID == "" {
+		mErr.Errors = append(mErr.Errors, errors.New("Missing job ID"))
+	}
+
+	// Child jobs are always named after their parent
+	if d.Child != "" && !strings.HasPrefix(d.Child, d.JobID+"/") {
+		mErr.Errors = append(mErr.Errors, fmt.Errorf("Child %q is not a child of job %q", d.Child, d.JobID))
+	}
+
+	switch d.Condition {
+	case JobDependencyConditionComplete, JobDependencyConditionFailed, JobDependencyConditionDead:
+	default:
+		mErr.Errors = append(mErr.Errors, fmt.Errorf("Invalid condition %q", d.Condition))
+	}
+	return mErr.ErrorOrNil()
+}
+
+// Met returns whether the upstream job with the given summary meets the
+// condition of the dependency.
+func (d *JobDependency) Met(job *Job, summary *JobSummary) bool {
+	if job == nil || job.Status != JobStatusDead {
+		return false
+	}
+
+	complete := summary != nil
+	for _, tg := range job.TaskGroups {
+		if !complete {
+			break
+		}
+		if tgSummary, ok := summary.Summary[tg.Name]; !ok || tgSummary.Complete < tg.Count {
+			complete = false
+		}
+	}
+
+	switch d.Condition {
+	case JobDependencyConditionComplete:
+		return complete
+	case JobDependencyConditionFailed:
+		return !complete
+	default:
+		return true
+	}
+}
+
+func CopySliceJobDependencies(s []*JobDependency) []*JobDependency {
+	l := len(s)
+	if l == 0 {
+		return nil
+	}
+
+	c := make([]*JobDependency, l)
+	for i, v := range s {
+		c[i] = v.Copy()
+	}
+	return c
+}
+
 const (
 	TaskLifecycleHookPrestart  = "prestart"
 	TaskLifecycleHookPoststart = "poststart"
@@ -5446,6 +5872,15 @@ func (p *ScalingPolicy) Canonicalize() {
 	}
 }
 
+// GetID is a helper for getting the ID when the object may be nil and is
+// required for pagination.
+func (p *ScalingPolicy) GetID() string {
+	if p == nil {
+		return ""
+	}
+	return p.ID
+}
+
 func (p *ScalingPolicy) Copy() *ScalingPolicy {
 	if p == nil {
 		return nil
@@ -5965,6 +6400,10 @@ type TaskGroup struct {
 	// scheduling preferences.
 	Affinities []*Affinity
 
+	// AllocAffinities can be specified at the task group level to express
+	// scheduling preferences relative to the allocations of other jobs.
+	AllocAffinities []*AllocAffinity
+
 	// Spread can be specified at the task group level to express spreading
 	// allocations across a desired attribute, such as datacenter
 	Spreads []*Spread
@@ -5989,6 +6428,38 @@ type TaskGroup struct {
 	// StopAfterClientDisconnect, if set, configures the client to stop the task group
 	// after this duration since the last known good heartbeat
 	StopAfterClientDisconnect *time.Duration
+
+	// MaxClientDisconnect, if set, configures the server to mark the
+	// allocations of the task group as unknown instead of lost when their
+	// client misses heartbeats, for up to this duration. Replacements are
+	// placed in the meantime, and if the client reconnects the scheduler
+	// picks which of the original or replacement allocations to keep.
+	MaxClientDisconnect *time.Duration
+
+	// Placement controls whether the allocations of the task group may be
+	// placed partially. Defaults to TaskGroupPlacementBestEffort.
+	Placement string
+
+	// MaxRunDuration, if set, is the maximum amount of time the tasks of
+	// the group may run before the client kills them and marks them as
+	// failed. Only supported for batch and sysbatch jobs.
+	MaxRunDuration *time.Duration
+}
+
+const (
+	// TaskGroupPlacementBestEffort places as many allocations of the task
+	// group as fit, and blocks the remainder until resources free up.
+	TaskGroupPlacementBestEffort = "best_effort"
+
+	// TaskGroupPlacementAllOrNothing only places the allocations of the
+	// task group if all of them fit, otherwise none are placed.
+	TaskGroupPlacementAllOrNothing = "all_or_nothing"
+)
+
+// IsAllOrNothing returns true if the allocations of the task group must all
+// be placed at once.
+func (tg *TaskGroup) IsAllOrNothing() bool {
+	return tg.Placement == TaskGroupPlacementAllOrNothing
 }
 
 func (tg *TaskGroup) Copy() *TaskGroup {
@@ -6002,6 +6473,7 @@ func (tg *TaskGroup) Copy() *TaskGroup {
 	ntg.RestartPolicy = ntg.RestartPolicy.Copy()
 	ntg.ReschedulePolicy = ntg.ReschedulePolicy.Copy()
 	ntg.Affinities = CopySliceAffinities(ntg.Affinities)
+	ntg.AllocAffinities = CopySliceAllocAffinities(ntg.AllocAffinities)
 	ntg.Spreads = CopySliceSpreads(ntg.Spreads)
 	ntg.Volumes = CopyMapVolumeRequest(ntg.Volumes)
 	ntg.Scaling = ntg.Scaling.Copy()
@@ -6045,6 +6517,14 @@ func (tg *TaskGroup) Copy() *TaskGroup {
 		ntg.StopAfterClientDisconnect = tg.StopAfterClientDisconnect
 	}
 
+	if tg.MaxClientDisconnect != nil {
+		ntg.MaxClientDisconnect = tg.MaxClientDisconnect
+	}
+
+	if tg.MaxRunDuration != nil {
+		ntg.MaxRunDuration = tg.MaxRunDuration
+	}
+
 	return ntg
 }
 
@@ -6127,6 +6607,19 @@ func (tg *TaskGroup) Validate(j *Job) error {
 		}
 	}
 
+	if j.Type == JobTypeSystem {
+		if tg.AllocAffinities != nil {
+			mErr.Errors = append(mErr.Errors, fmt.Errorf("System jobs may not have an alloc_affinity stanza"))
+		}
+	} else {
+		for idx, affinity := range tg.AllocAffinities {
+			if err := affinity.Validate(); err != nil {
+				outer := fmt.Errorf("Alloc affinity %d validation failed: %s", idx+1, err)
+				mErr.Errors = append(mErr.Errors, outer)
+			}
+		}
+	}
+
 	if tg.RestartPolicy != nil {
 		if err := tg.RestartPolicy.Validate(); err != nil {
 			mErr.Errors = append(mErr.Errors, err)
@@ -6148,6 +6641,17 @@ func (tg *TaskGroup) Validate(j *Job) error {
 		}
 	}
 
+	switch tg.Placement {
+	case "", TaskGroupPlacementBestEffort:
+	case TaskGroupPlacementAllOrNothing:
+		if j.Type != JobTypeBatch {
+			mErr.Errors = append(mErr.Errors, fmt.Errorf("Placement %q is only valid for batch jobs", tg.Placement))
+		}
+	default:
+		mErr.Errors = append(mErr.Errors, fmt.Errorf("Invalid placement %q, must be one of %q or %q",
+			tg.Placement, TaskGroupPlacementBestEffort, TaskGroupPlacementAllOrNothing))
+	}
+
 	if j.Type == JobTypeSystem {
 		if tg.ReschedulePolicy != nil {
 			mErr.Errors = append(mErr.Errors, fmt.Errorf("System jobs should not have a reschedule policy"))
@@ -6376,6 +6880,13 @@ func (tg *TaskGroup) validateServices() error {
 	var mErr multierror.Error
 	knownTasks := make(map[string]struct{})
 
+	// Track the providers used by all group and task services so we can
+	// ensure they do not mix
+	providers := make(map[string]struct{})
+	for _, service := range tg.Services {
+		providers[service.providerOrDefault()] = struct{}{}
+	}
+
 	// Create a map of known tasks and their services so we can compare
 	// vs the group-level services and checks
 	for _, task := range tg.Tasks {
@@ -6384,6 +6895,7 @@ func (tg *TaskGroup) validateServices() error {
 			continue
 		}
 		for _, service := range task.Services {
+			providers[service.providerOrDefault()] = struct{}{}
 			for _, check := range service.Checks {
 				if check.TaskName != "" {
 					mErr.Errors = append(mErr.Errors, fmt.Errorf("Check %s is invalid: only task group service checks can be assigned tasks", check.Name))
@@ -6391,6 +6903,10 @@ func (tg *TaskGroup) validateServices() error {
 			}
 		}
 	}
+
+	if len(providers) > 1 {
+		mErr.Errors = append(mErr.Errors, errors.New("Multiple service providers used: task group services must use the same provider"))
+	}
 	for i, service := range tg.Services {
 		if err := service.Validate(); err != nil {
 			outer := fmt.Errorf("Service[%d] %s validation failed: %s", i, service.Name, err)
@@ -6613,6 +7129,17 @@ const (
 type LogConfig struct {
 	MaxFiles      int
 	MaxFileSizeMB int
+
+	// Sinks are additional destinations each line of the task's output is
+	// shipped to, on top of the rotated log files.
+	Sinks []*LogSink
+
+	// RateLimit limits the rate of the task's output. Output exceeding the
+	// limit is dropped.
+	RateLimit *LogRateLimit
+
+	// CompressRotated gzips the log files once they are rotated.
+	CompressRotated bool
 }
 
 func (l *LogConfig) Equals(o *LogConfig) bool {
@@ -6628,6 +7155,23 @@ func (l *LogConfig) Equals(o *LogConfig) bool {
 		return false
 	}
 
+	if len(l.Sinks) != len(o.Sinks) {
+		return false
+	}
+	for i, sink := range l.Sinks {
+		if !sink.Equals(o.Sinks[i]) {
+			return false
+		}
+	}
+
+	if !l.RateLimit.Equals(o.RateLimit) {
+		return false
+	}
+
+	if l.CompressRotated != o.CompressRotated {
+		return false
+	}
+
 	return true
 }
 
@@ -6636,8 +7180,11 @@ func (l *LogConfig) Copy() *LogConfig {
 		return nil
 	}
 	return &LogConfig{
-		MaxFiles:      l.MaxFiles,
-		MaxFileSizeMB: l.MaxFileSizeMB,
+		MaxFiles:        l.MaxFiles,
+		MaxFileSizeMB:   l.MaxFileSizeMB,
+		Sinks:           CopySliceLogSinks(l.Sinks),
+		RateLimit:       l.RateLimit.Copy(),
+		CompressRotated: l.CompressRotated,
 	}
 }
 
@@ -6659,22 +7206,206 @@ func (l *LogConfig) Validate() error {
 	if l.MaxFileSizeMB < 1 {
 		mErr.Errors = append(mErr.Errors, fmt.Errorf("minimum file size is 1MB; got %d", l.MaxFileSizeMB))
 	}
+	if l.RateLimit != nil {
+		if err := l.RateLimit.Validate(); err != nil {
+			mErr.Errors = append(mErr.Errors, fmt.Errorf("rate_limit: %v", err))
+		}
+	}
+	for i, sink := range l.Sinks {
+		if err := sink.Validate(); err != nil {
+			mErr.Errors = append(mErr.Errors, fmt.Errorf("log_sink %d: %v", i+1, err))
+			continue
+		}
+
+		// Task file sinks are written by the client, so they are relative
+		// to the allocation's log directory and can't escape it
+		if sink.Type == LogSinkTypeFile && LogSinkPathEscapesLogDir(sink.Path) {
+			mErr.Errors = append(mErr.Errors, fmt.Errorf("log_sink %d: path %q must be relative and within the log directory", i+1, sink.Path))
+		}
+	}
 	return mErr.ErrorOrNil()
 }
 
-// Task is a single process typically that is executed as part of a task group.
-type Task struct {
-	// Name of the task
-	Name string
+// LogSinkPathEscapesLogDir returns whether the path of a task file sink is
+// absolute or escapes the allocation's log directory it is relative to.
+func LogSinkPathEscapesLogDir(path string) bool {
+	if filepath.IsAbs(path) {
+		return true
+	}
+	logDir := filepath.Join("/", "alloc-dir", "alloc-id", "alloc", "logs")
+	return helper.PathEscapesSandbox(logDir, filepath.Join(logDir, path))
+}
 
-	// Driver is used to control which driver is used
-	Driver string
+// LogRateLimit limits the rate of a task's output. Lines exceeding either
+// limit are dropped by logmon, which reports the number of dropped lines in a
+// task event.
+type LogRateLimit struct {
+	// LinesPerSecond is the number of lines allowed per second. Zero means
+	// unlimited.
+	LinesPerSecond int
 
-	// User is used to determine which user will run the task. It defaults to
-	// the same user the Nomad client is being run as.
-	User string
+	// BytesPerSecond is the number of bytes allowed per second. Zero means
+	// unlimited.
+	BytesPerSecond int
+}
 
-	// Config is provided to the driver to initialize
+func (r *LogRateLimit) Equals(o *LogRateLimit) bool {
+	if r == nil || o == nil {
+		return r == o
+	}
+	return *r == *o
+}
+
+func (r *LogRateLimit) Copy() *LogRateLimit {
+	if r == nil {
+		return nil
+	}
+	nr := *r
+	return &nr
+}
+
+// Validate returns an error if the rate limit is invalid.
+func (r *LogRateLimit) Validate() error {
+	var mErr multierror.Error
+	if r.LinesPerSecond < 0 {
+		mErr.Errors = append(mErr.Errors, fmt.Errorf("lines per second must not be negative; got %d", r.LinesPerSecond))
+	}
+	if r.BytesPerSecond < 0 {
+		mErr.Errors = append(mErr.Errors, fmt.Errorf("bytes per second must not be negative; got %d", r.BytesPerSecond))
+	}
+	if r.LinesPerSecond == 0 && r.BytesPerSecond == 0 {
+		mErr.Errors = append(mErr.Errors, errors.New("lines per second or bytes per second must be set"))
+	}
+	return mErr.ErrorOrNil()
+}
+
+const (
+	// LogSinkTypeSyslog ships lines as RFC5424 messages to a syslog unix or
+	// TCP socket.
+	LogSinkTypeSyslog = "syslog"
+
+	// LogSinkTypeFile appends lines to a local file as JSON objects.
+	LogSinkTypeFile = "file"
+
+	// LogSinkTypeTCP ships lines as newline delimited JSON objects over a
+	// TCP connection.
+	LogSinkTypeTCP = "tcp"
+
+	// LogSinkTypeHTTP ships batches of lines as newline delimited JSON
+	// objects in the body of HTTP POST requests.
+	LogSinkTypeHTTP = "http"
+)
+
+// LogSink is a destination the lines of a task's output are shipped to by
+// logmon. Lines are buffered per sink and dropped when the buffer is full,
+// so a slow or unavailable sink never blocks the task.
+type LogSink struct {
+	// Type is one of "syslog", "file", "tcp" or "http".
+	Type string `hcl:"type"`
+
+	// Address is where lines are shipped to. It is a "unix://",
+	// "unixgram://" or "tcp://" URL for syslog sinks, a host and port for
+	// TCP sinks and a URL for HTTP sinks.
+	Address string `hcl:"address"`
+
+	// Path is the file lines are appended to for file sinks.
+	Path string `hcl:"path"`
+
+	// BufferSize is the number of lines buffered before new lines are
+	// dropped. Logmon picks a default when zero.
+	BufferSize int `hcl:"buffer_size"`
+}
+
+func (s *LogSink) Equals(o *LogSink) bool {
+	if s == nil || o == nil {
+		return s == o
+	}
+	return *s == *o
+}
+
+func (s *LogSink) Copy() *LogSink {
+	if s == nil {
+		return nil
+	}
+	ns := *s
+	return &ns
+}
+
+func CopySliceLogSinks(s []*LogSink) []*LogSink {
+	l := len(s)
+	if l == 0 {
+		return nil
+	}
+
+	c := make([]*LogSink, l)
+	for i, sink := range s {
+		c[i] = sink.Copy()
+	}
+	return c
+}
+
+// ClientLogSinkConfig is a log sink set in the client configuration. It ships
+// the output of every task on the client, in addition to the task's sinks.
+type ClientLogSinkConfig struct {
+	Name    string `hcl:",key"`
+	LogSink `hcl:",squash"`
+}
+
+// Validate returns an error if the log sink is invalid.
+func (s *LogSink) Validate() error {
+	if s == nil {
+		return errors.New("missing log sink")
+	}
+
+	var mErr multierror.Error
+	switch s.Type {
+	case LogSinkTypeSyslog:
+		if s.Address != "" {
+			u, err := url.Parse(s.Address)
+			if err != nil {
+				mErr.Errors = append(mErr.Errors, fmt.Errorf("invalid syslog address %q: %v", s.Address, err))
+			} else if u.Scheme != "unix" && u.Scheme != "unixgram" && u.Scheme != "tcp" {
+				mErr.Errors = append(mErr.Errors, fmt.Errorf("syslog address %q must use the unix, unixgram or tcp scheme", s.Address))
+			}
+		}
+	case LogSinkTypeFile:
+		if s.Path == "" {
+			mErr.Errors = append(mErr.Errors, errors.New("file sinks require a path"))
+		}
+	case LogSinkTypeTCP:
+		if _, _, err := net.SplitHostPort(s.Address); err != nil {
+			mErr.Errors = append(mErr.Errors, fmt.Errorf("invalid tcp address %q: %v", s.Address, err))
+		}
+	case LogSinkTypeHTTP:
+		u, err := url.Parse(s.Address)
+		if err != nil {
+			mErr.Errors = append(mErr.Errors, fmt.Errorf("invalid http address %q: %v", s.Address, err))
+		} else if u.Scheme != "http" && u.Scheme != "https" {
+			mErr.Errors = append(mErr.Errors, fmt.Errorf("http address %q must use the http or https scheme", s.Address))
+		}
+	default:
+		mErr.Errors = append(mErr.Errors, fmt.Errorf("invalid log sink type %q, must be one of %q, %q, %q or %q",
+			s.Type, LogSinkTypeSyslog, LogSinkTypeFile, LogSinkTypeTCP, LogSinkTypeHTTP))
+	}
+	if s.BufferSize < 0 {
+		mErr.Errors = append(mErr.Errors, fmt.Errorf("buffer size must not be negative; got %d", s.BufferSize))
+	}
+	return mErr.ErrorOrNil()
+}
+
+// Task is a single process typically that is executed as part of a task group.
+type Task struct {
+	// Name of the task
+	Name string
+
+	// Driver is used to control which driver is used
+	Driver string
+
+	// User is used to determine which user will run the task. It defaults to
+	// the same user the Nomad client is being run as.
+	User string
+
+	// Config is provided to the driver to initialize
 	Config map[string]interface{}
 
 	// Map of environment variables to be used by the driver
@@ -6707,6 +7438,10 @@ type Task struct {
 	// DispatchPayload configures how the task retrieves its input from a dispatch
 	DispatchPayload *DispatchPayloadConfig
 
+	// Identity controls whether and how the task's workload identity is
+	// exposed to the task.
+	Identity *WorkloadIdentity
+
 	Lifecycle *TaskLifecycleConfig
 
 	// Meta is used to associate arbitrary metadata with this
@@ -6789,6 +7524,7 @@ func (t *Task) Copy() *Task {
 	nt.LogConfig = nt.LogConfig.Copy()
 	nt.Meta = helper.CopyMapStringString(nt.Meta)
 	nt.DispatchPayload = nt.DispatchPayload.Copy()
+	nt.Identity = nt.Identity.Copy()
 	nt.Lifecycle = nt.Lifecycle.Copy()
 
 	if t.Artifacts != nil {
@@ -6955,7 +7691,7 @@ func (t *Task) Validate(ephemeralDisk *EphemeralDisk, jobType string, tgServices
 
 	destinations := make(map[string]int, len(t.Templates))
 	for idx, tmpl := range t.Templates {
-		if err := tmpl.Validate(); err != nil {
+		if err := tmpl.Validate(t.Driver); err != nil {
 			outer := fmt.Errorf("Template %d validation failed: %s", idx+1, err)
 			mErr.Errors = append(mErr.Errors, outer)
 		}
@@ -7335,14 +8071,66 @@ const (
 	// TemplateChangeModeRestart marks that the task should be restarted if the
 	// template is re-rendered
 	TemplateChangeModeRestart = "restart"
+
+	// TemplateChangeModeScript marks that a script should be executed within
+	// the task if the template is re-rendered
+	TemplateChangeModeScript = "script"
 )
 
 var (
 	// TemplateChangeModeInvalidError is the error for when an invalid change
 	// mode is given
-	TemplateChangeModeInvalidError = errors.New("Invalid change mode. Must be one of the following: noop, signal, restart")
+	TemplateChangeModeInvalidError = errors.New("Invalid change mode. Must be one of the following: noop, signal, restart, script")
+
+	// templateNoExecDrivers are the builtin task drivers that don't support
+	// exec and so can't run change scripts. Capabilities of external driver
+	// plugins aren't known until the task is started on a client.
+	templateNoExecDrivers = map[string]struct{}{
+		"java": {},
+		"qemu": {},
+	}
 )
 
+// ChangeScript holds the configuration of the script executed within the task
+// when a template with change mode script is re-rendered.
+type ChangeScript struct {
+	// Command is the command to execute
+	Command string
+
+	// Args are the arguments passed to the command
+	Args []string
+
+	// Timeout is the maximum duration the script may run for
+	Timeout time.Duration
+
+	// FailOnError fails the task if the script can't be executed or exits
+	// with a non-zero code
+	FailOnError bool
+}
+
+func (cs *ChangeScript) Copy() *ChangeScript {
+	if cs == nil {
+		return nil
+	}
+	ncs := new(ChangeScript)
+	*ncs = *cs
+	ncs.Args = helper.CopySliceString(cs.Args)
+	return ncs
+}
+
+func (cs *ChangeScript) Validate() error {
+	var mErr multierror.Error
+
+	if cs.Command == "" {
+		_ = multierror.Append(&mErr, fmt.Errorf("Must specify a change script command"))
+	}
+	if cs.Timeout <= 0 {
+		_ = multierror.Append(&mErr, fmt.Errorf("Must specify positive change script timeout"))
+	}
+
+	return mErr.ErrorOrNil()
+}
+
 // Template represents a template configuration to be rendered for a given task
 type Template struct {
 	// SourcePath is the path to the template to be rendered
@@ -7362,6 +8150,10 @@ type Template struct {
 	// requires it.
 	ChangeSignal string
 
+	// ChangeScript is the script that should be executed within the task if
+	// the change mode requires it.
+	ChangeScript *ChangeScript
+
 	// Splay is used to avoid coordinated restarts of processes by applying a
 	// random wait between 0 and the given splay value before signalling the
 	// application of a change
@@ -7409,6 +8201,7 @@ func (t *Template) Copy() *Template {
 	}
 	copy := new(Template)
 	*copy = *t
+	copy.ChangeScript = t.ChangeScript.Copy()
 	return copy
 }
 
@@ -7418,7 +8211,8 @@ func (t *Template) Canonicalize() {
 	}
 }
 
-func (t *Template) Validate() error {
+// Validate validates the template for a task using the given driver.
+func (t *Template) Validate(driver string) error {
 	var mErr multierror.Error
 
 	// Verify we have something to render
@@ -7449,6 +8243,18 @@ func (t *Template) Validate() error {
 		if t.Envvars {
 			_ = multierror.Append(&mErr, fmt.Errorf("cannot use signals with env var templates"))
 		}
+	case TemplateChangeModeScript:
+		if t.ChangeScript == nil {
+			_ = multierror.Append(&mErr, fmt.Errorf("Must specify change script configuration when change mode is script"))
+		} else if err := t.ChangeScript.Validate(); err != nil {
+			_ = multierror.Append(&mErr, err)
+		}
+		if t.Envvars {
+			_ = multierror.Append(&mErr, fmt.Errorf("cannot use change scripts with env var templates"))
+		}
+		if _, ok := templateNoExecDrivers[driver]; ok {
+			_ = multierror.Append(&mErr, fmt.Errorf("cannot use change scripts with the %q driver as it doesn't support exec", driver))
+		}
 	default:
 		_ = multierror.Append(&mErr, TemplateChangeModeInvalidError)
 	}
@@ -7689,6 +8495,22 @@ const (
 
 	// TaskPluginHealthy indicates that a plugin managed by Nomad became healthy
 	TaskPluginHealthy = "Plugin became healthy"
+
+	// TaskMaxRunDurationExceeded indicates that the task was killed because
+	// it ran longer than the max_run_duration of its task group.
+	TaskMaxRunDurationExceeded = "Max Run Duration Exceeded"
+
+	// TaskLogsDropped indicates that output of the task was dropped because
+	// it exceeded the rate limit of its logs.
+	TaskLogsDropped = "Logs Dropped"
+
+	// TaskArtifactCacheHit indicates that an artifact was installed from the
+	// client's artifact cache instead of being downloaded.
+	TaskArtifactCacheHit = "Artifact Cache Hit"
+
+	// TaskArtifactCached indicates that an artifact was downloaded into the
+	// client's artifact cache.
+	TaskArtifactCached = "Artifact Cached"
 )
 
 // TaskEvent is an event that effects the state of a task and contains meta-data
@@ -7900,6 +8722,8 @@ func (event *TaskEvent) PopulateEventDisplayMessage() {
 		desc = "Leader Task in Group dead"
 	case TaskMainDead:
 		desc = "Main tasks in the group died"
+	case TaskMaxRunDurationExceeded:
+		desc = "Task exceeded the max run duration of its group"
 	default:
 		desc = event.Message
 	}
@@ -8478,6 +9302,74 @@ func (a *Affinity) Validate() error {
 	return mErr.ErrorOrNil()
 }
 
+// AllocAffinity is used to score placement options based on the allocations
+// of another job in the same namespace already running on the nodes
+type AllocAffinity struct {
+	Job       string // ID of the job whose allocations are matched
+	TaskGroup string // Optional task group of the job whose allocations are matched
+	Weight    int8   // Weight applied to nodes running matching allocations. Can be negative
+	Hard      bool   // Whether nodes that don't satisfy the affinity are infeasible
+}
+
+// Equals checks if two alloc affinities are equal.
+func (a *AllocAffinity) Equals(o *AllocAffinity) bool {
+	return a == o ||
+		a.Job == o.Job &&
+			a.TaskGroup == o.TaskGroup &&
+			a.Weight == o.Weight &&
+			a.Hard == o.Hard
+}
+
+func (a *AllocAffinity) Copy() *AllocAffinity {
+	if a == nil {
+		return nil
+	}
+	na := new(AllocAffinity)
+	*na = *a
+	return na
+}
+
+func (a *AllocAffinity) String() string {
+	target := a.Job
+	if a.TaskGroup != "" {
+		target = a.Job + "." + a.TaskGroup
+	}
+	if a.Hard {
+		if a.Weight < 0 {
+			return fmt.Sprintf("alloc_anti_affinity %s", target)
+		}
+		return fmt.Sprintf("alloc_affinity %s", target)
+	}
+	return fmt.Sprintf("alloc_affinity %s %v", target, a.Weight)
+}
+
+// Matches returns true if the allocation is matched by the affinity. Only
+// allocations in the given namespace, which is the namespace of the job with
+// the affinity, are matched.
+func (a *AllocAffinity) Matches(namespace string, alloc *Allocation) bool {
+	return alloc.Namespace == namespace &&
+		alloc.JobID == a.Job &&
+		(a.TaskGroup == "" || alloc.TaskGroup == a.TaskGroup)
+}
+
+func (a *AllocAffinity) Validate() error {
+	var mErr multierror.Error
+	if a.Job == "" {
+		mErr.Errors = append(mErr.Errors, errors.New("Missing alloc affinity job"))
+	}
+
+	// Ensure that weight is between -100 and 100, and not zero
+	if a.Weight == 0 {
+		mErr.Errors = append(mErr.Errors, fmt.Errorf("Alloc affinity weight cannot be zero"))
+	}
+
+	if a.Weight > 100 || a.Weight < -100 {
+		mErr.Errors = append(mErr.Errors, fmt.Errorf("Alloc affinity weight must be within the range [-100,100]"))
+	}
+
+	return mErr.ErrorOrNil()
+}
+
 // Spread is used to specify desired distribution of allocations according to weight
 type Spread struct {
 	// Attribute is the node attribute used as the spread criteria
@@ -8490,6 +9382,15 @@ type Spread struct {
 	// SpreadTarget is used to describe desired percentages for each attribute value
 	SpreadTarget []*SpreadTarget
 
+	// MaxSkew is the maximum allowed difference between the number of
+	// allocations of the task group with a given attribute value and the
+	// value with the fewest allocations. It is only used by hard spreads.
+	MaxSkew int
+
+	// Hard makes the spread a feasibility check instead of a scoring
+	// preference, so placements exceeding MaxSkew are never made.
+	Hard bool
+
 	// Memoized string representation
 	str string
 }
@@ -8546,6 +9447,16 @@ func (s *Spread) Validate() error {
 	if s.Weight <= 0 || s.Weight > 100 {
 		mErr.Errors = append(mErr.Errors, errors.New("Spread stanza must have a positive weight from 0 to 100"))
 	}
+	if s.Hard {
+		if s.MaxSkew < 1 {
+			mErr.Errors = append(mErr.Errors, errors.New("Hard spread stanza must have a max_skew of at least 1"))
+		}
+		if len(s.SpreadTarget) > 0 {
+			mErr.Errors = append(mErr.Errors, errors.New("Hard spread stanza cannot have targets"))
+		}
+	} else if s.MaxSkew != 0 {
+		mErr.Errors = append(mErr.Errors, errors.New("Spread stanza max_skew requires hard to be set"))
+	}
 	seen := make(map[string]struct{})
 	sumPercent := uint32(0)
 
@@ -8876,6 +9787,15 @@ func (d *Deployment) GetID() string {
 	return d.ID
 }
 
+// GetNamespace is a helper for getting the namespace when the object may be
+// nil and is required for pagination.
+func (d *Deployment) GetNamespace() string {
+	if d == nil {
+		return ""
+	}
+	return d.Namespace
+}
+
 // HasPlacedCanaries returns whether the deployment has placed canaries
 func (d *Deployment) HasPlacedCanaries() bool {
 	if d == nil || len(d.TaskGroups) == 0 {
@@ -9118,6 +10038,7 @@ const (
 	AllocClientStatusComplete = "complete"
 	AllocClientStatusFailed   = "failed"
 	AllocClientStatusLost     = "lost"
+	AllocClientStatusUnknown  = "unknown"
 )
 
 // Allocation is used to allocate the placement of a task group to a node.
@@ -9245,6 +10166,11 @@ type Allocation struct {
 
 	// ModifyTime is the time the allocation was last updated.
 	ModifyTime int64
+
+	// SignedIdentities is a map of task names to the workload identity
+	// token signed for that task. It is populated by the plan applier and is
+	// never returned by the HTTP API.
+	SignedIdentities map[string]string `json:"-"`
 }
 
 // ConsulNamespace returns the Consul namespace of the task group associated
@@ -9257,6 +10183,24 @@ func (a *Allocation) JobNamespacedID() NamespacedID {
 	return NewNamespacedID(a.JobID, a.Namespace)
 }
 
+// GetID is a helper for getting the ID when the object may be nil and is
+// required for pagination.
+func (a *Allocation) GetID() string {
+	if a == nil {
+		return ""
+	}
+	return a.ID
+}
+
+// GetNamespace is a helper for getting the namespace when the object may be
+// nil and is required for pagination.
+func (a *Allocation) GetNamespace() string {
+	if a == nil {
+		return ""
+	}
+	return a.Namespace
+}
+
 // Index returns the index of the allocation. If the allocation is from a task
 // group with count greater than 1, there will be multiple allocations for it.
 func (a *Allocation) Index() uint {
@@ -9347,6 +10291,7 @@ func (a *Allocation) copyImpl(job bool) *Allocation {
 
 	na.RescheduleTracker = a.RescheduleTracker.Copy()
 	na.PreemptedAllocations = helper.CopySliceString(a.PreemptedAllocations)
+	na.SignedIdentities = helper.CopyMapStringString(a.SignedIdentities)
 	return na
 }
 
@@ -9398,7 +10343,7 @@ func (a *Allocation) ShouldReschedule(reschedulePolicy *ReschedulePolicy, failTi
 // RescheduleEligible returns if the allocation is eligible to be rescheduled according
 // to its ReschedulePolicy and the current state of its reschedule trackers
 func (a *Allocation) RescheduleEligible(reschedulePolicy *ReschedulePolicy, failTime time.Time) bool {
-	if reschedulePolicy == nil {
+	if reschedulePolicy == nil || a.MaxRunDurationExceeded() {
 		return false
 	}
 	attempts := reschedulePolicy.Attempts
@@ -9471,6 +10416,12 @@ func (a *Allocation) NextRescheduleTime() (time.Time, bool) {
 		return time.Time{}, false
 	}
 
+	// Allocations killed for running past their deadline would only hit it
+	// again, so they are never rescheduled
+	if a.MaxRunDurationExceeded() {
+		return time.Time{}, false
+	}
+
 	nextDelay := a.NextDelay()
 	nextRescheduleTime := failTime.Add(nextDelay)
 	rescheduleEligible := reschedulePolicy.Unlimited || (reschedulePolicy.Attempts > 0 && a.RescheduleTracker == nil)
@@ -9489,6 +10440,22 @@ func (a *Allocation) NextRescheduleTime() (time.Time, bool) {
 	return nextRescheduleTime, rescheduleEligible
 }
 
+// MaxRunDurationExceeded returns true if any task of the allocation was
+// killed because it ran longer than the max_run_duration of its task group.
+func (a *Allocation) MaxRunDurationExceeded() bool {
+	for _, ts := range a.TaskStates {
+		if ts == nil {
+			continue
+		}
+		for _, e := range ts.Events {
+			if e.Type == TaskMaxRunDurationExceeded {
+				return true
+			}
+		}
+	}
+	return false
+}
+
 // ShouldClientStop tests an alloc for StopAfterClientDisconnect configuration
 func (a *Allocation) ShouldClientStop() bool {
 	tg := a.Job.LookupTaskGroup(a.TaskGroup)
@@ -9532,6 +10499,78 @@ func (a *Allocation) WaitClientStop() time.Time {
 	return t.Add(*tg.StopAfterClientDisconnect + kill)
 }
 
+// SupportsDisconnectedClients returns true if the allocation's task group is
+// configured with MaxClientDisconnect, so it may be marked unknown instead of
+// lost when its client misses heartbeats.
+func (a *Allocation) SupportsDisconnectedClients() bool {
+	if a.Job == nil {
+		return false
+	}
+	tg := a.Job.LookupTaskGroup(a.TaskGroup)
+	return tg != nil && tg.MaxClientDisconnect != nil
+}
+
+// LastUnknown returns the time of the last transition of the allocation's
+// client status to unknown, or the zero time if it was never unknown.
+func (a *Allocation) LastUnknown() time.Time {
+	var t time.Time
+	for _, s := range a.AllocStates {
+		if s.Field == AllocStateFieldClientStatus &&
+			s.Value == AllocClientStatusUnknown {
+			if s.Time.After(t) {
+				t = s.Time
+			}
+		}
+	}
+	return t.UTC()
+}
+
+// DisconnectTimeout returns the time after which an allocation on a
+// disconnected client is considered lost. The timeout is counted from the
+// time the allocation was marked unknown, or from now if it hasn't been yet.
+func (a *Allocation) DisconnectTimeout(now time.Time) time.Time {
+	if a.Job == nil {
+		return now
+	}
+	tg := a.Job.LookupTaskGroup(a.TaskGroup)
+	if tg == nil || tg.MaxClientDisconnect == nil {
+		return now
+	}
+
+	t := a.LastUnknown()
+	if t.IsZero() {
+		t = now
+	}
+	return t.Add(*tg.MaxClientDisconnect)
+}
+
+// Expired returns true if the allocation's client has been disconnected for
+// longer than the task group's MaxClientDisconnect.
+func (a *Allocation) Expired(now time.Time) bool {
+	if a.ClientStatus != AllocClientStatusUnknown || !a.SupportsDisconnectedClients() {
+		return false
+	}
+	return now.After(a.DisconnectTimeout(now))
+}
+
+// NeedsToReconcile returns true if the allocation was marked unknown while
+// its client was disconnected and the client has since reported a new
+// status that the scheduler hasn't reconciled yet.
+func (a *Allocation) NeedsToReconcile() bool {
+	if a.ClientStatus == AllocClientStatusUnknown {
+		return false
+	}
+
+	for i := len(a.AllocStates) - 1; i >= 0; i-- {
+		s := a.AllocStates[i]
+		if s.Field != AllocStateFieldClientStatus {
+			continue
+		}
+		return s.Value == AllocClientStatusUnknown
+	}
+	return false
+}
+
 // NextDelay returns a duration after which the allocation can be rescheduled.
 // It is calculated according to the delay function and previous reschedule attempts.
 func (a *Allocation) NextDelay() time.Duration {
@@ -9895,6 +10934,30 @@ type AllocMetric struct {
 	// This is to prevent creating many failed allocations for a
 	// single task group.
 	CoalescedFailures int
+
+	// PlacementsWithheld is the number of allocations that fit on a node
+	// but were not placed because their task group requires all of its
+	// allocations to be placed at once. They are included in
+	// CoalescedFailures.
+	PlacementsWithheld int
+
+	// SpreadSkewFiltered is the number of nodes filtered because placing on
+	// them would exceed the max skew of a hard spread, by spread attribute
+	SpreadSkewFiltered map[string]int
+
+	// NodeExplanations explains why each evaluated node was filtered,
+	// exhausted or how it was scored. It is only populated when explaining
+	// a job plan and is bounded by MaxExplainedNodes.
+	NodeExplanations []*NodeExplanation
+
+	// ClassExplanations summarizes the evaluated nodes by node class when
+	// explaining a job plan.
+	ClassExplanations map[string]*ClassExplanation
+
+	// explain enables recording node and class explanations, and
+	// nodeExplanations indexes NodeExplanations by node ID.
+	explain          bool
+	nodeExplanations map[string]*NodeExplanation
 }
 
 func (a *AllocMetric) Copy() *AllocMetric {
@@ -9906,19 +10969,105 @@ func (a *AllocMetric) Copy() *AllocMetric {
 	na.NodesAvailable = helper.CopyMapStringInt(na.NodesAvailable)
 	na.ClassFiltered = helper.CopyMapStringInt(na.ClassFiltered)
 	na.ConstraintFiltered = helper.CopyMapStringInt(na.ConstraintFiltered)
+	na.SpreadSkewFiltered = helper.CopyMapStringInt(na.SpreadSkewFiltered)
 	na.ClassExhausted = helper.CopyMapStringInt(na.ClassExhausted)
 	na.DimensionExhausted = helper.CopyMapStringInt(na.DimensionExhausted)
 	na.QuotaExhausted = helper.CopySliceString(na.QuotaExhausted)
 	na.Scores = helper.CopyMapStringFloat64(na.Scores)
 	na.ScoreMetaData = CopySliceNodeScoreMeta(na.ScoreMetaData)
+
+	if a.NodeExplanations != nil {
+		na.NodeExplanations = make([]*NodeExplanation, len(a.NodeExplanations))
+		for i, e := range a.NodeExplanations {
+			na.NodeExplanations[i] = e.Copy()
+		}
+	}
+	if a.ClassExplanations != nil {
+		na.ClassExplanations = make(map[string]*ClassExplanation, len(a.ClassExplanations))
+		for class, e := range a.ClassExplanations {
+			na.ClassExplanations[class] = e.Copy()
+		}
+	}
+	if a.nodeExplanations != nil {
+		na.nodeExplanations = make(map[string]*NodeExplanation, len(a.nodeExplanations))
+		for id := range a.nodeExplanations {
+			na.nodeExplanations[id] = nil
+		}
+		for _, e := range na.NodeExplanations {
+			na.nodeExplanations[e.NodeID] = e
+		}
+	}
 	return na
 }
 
+// EnableExplain enables recording why each node was filtered, exhausted or
+// how it was scored.
+func (a *AllocMetric) EnableExplain() {
+	a.explain = true
+}
+
+// Explaining returns whether the metric records why each node was filtered,
+// exhausted or how it was scored.
+func (a *AllocMetric) Explaining() bool {
+	return a.explain
+}
+
+// explainNode returns the explanation of the node and the summary of its
+// class. The node explanation is nil for nodes evaluated after
+// MaxExplainedNodes nodes have been explained.
+func (a *AllocMetric) explainNode(node *Node) (*NodeExplanation, *ClassExplanation) {
+	if a.ClassExplanations == nil {
+		a.ClassExplanations = make(map[string]*ClassExplanation)
+	}
+	class, ok := a.ClassExplanations[node.NodeClass]
+	if !ok {
+		class = &ClassExplanation{}
+		a.ClassExplanations[node.NodeClass] = class
+	}
+
+	if e, ok := a.nodeExplanations[node.ID]; ok {
+		return e, class
+	}
+	class.Evaluated++
+
+	if a.nodeExplanations == nil {
+		a.nodeExplanations = make(map[string]*NodeExplanation)
+	}
+	if len(a.NodeExplanations) >= MaxExplainedNodes {
+		// Remember the node so it's only counted once by its class.
+		a.nodeExplanations[node.ID] = nil
+		return nil, class
+	}
+
+	e := &NodeExplanation{
+		NodeID:    node.ID,
+		NodeName:  node.Name,
+		NodeClass: node.NodeClass,
+	}
+	a.nodeExplanations[node.ID] = e
+	a.NodeExplanations = append(a.NodeExplanations, e)
+	return e, class
+}
+
+// explainFiltered records the reason the node was found infeasible.
+func (a *AllocMetric) explainFiltered(node *Node, reason string) {
+	e, class := a.explainNode(node)
+	class.Filtered++
+	class.reason(reason)
+	if e != nil {
+		e.Filtered = reason
+	}
+}
+
 func (a *AllocMetric) EvaluateNode() {
 	a.NodesEvaluated += 1
 }
 
 func (a *AllocMetric) FilterNode(node *Node, constraint string) {
+	if a.explain && node != nil && constraint != "" {
+		a.explainFiltered(node, constraint)
+	}
+
 	a.NodesFiltered += 1
 	if node != nil && node.NodeClass != "" {
 		if a.ClassFiltered == nil {
@@ -9934,7 +11083,30 @@ func (a *AllocMetric) FilterNode(node *Node, constraint string) {
 	}
 }
 
+// SpreadSkewFilterNode records a node filtered because placing on it would
+// exceed the max skew of the hard spread on the given attribute.
+func (a *AllocMetric) SpreadSkewFilterNode(node *Node, attribute string) {
+	if a.explain && node != nil {
+		a.explainFiltered(node, fmt.Sprintf("spread %s max_skew", attribute))
+	}
+
+	a.FilterNode(node, "")
+	if a.SpreadSkewFiltered == nil {
+		a.SpreadSkewFiltered = make(map[string]int)
+	}
+	a.SpreadSkewFiltered[attribute] += 1
+}
+
 func (a *AllocMetric) ExhaustedNode(node *Node, dimension string) {
+	if a.explain && node != nil {
+		e, class := a.explainNode(node)
+		class.Exhausted++
+		class.reason(dimension)
+		if e != nil {
+			e.Exhausted = dimension
+		}
+	}
+
 	a.NodesExhausted += 1
 	if node != nil && node.NodeClass != "" {
 		if a.ClassExhausted == nil {
@@ -9989,6 +11161,10 @@ func (a *AllocMetric) ExhaustResources(tg *TaskGroup) {
 
 // ScoreNode is used to gather top K scoring nodes in a heap
 func (a *AllocMetric) ScoreNode(node *Node, name string, score float64) {
+	if a.explain {
+		a.explainScore(node, name, score)
+	}
+
 	// Create nodeScoreMeta lazily if its the first time or if its a new node
 	if a.nodeScoreMeta == nil || a.nodeScoreMeta.NodeID != node.ID {
 		a.nodeScoreMeta = &NodeScoreMeta{
@@ -10014,6 +11190,29 @@ func (a *AllocMetric) ScoreNode(node *Node, name string, score float64) {
 	}
 }
 
+// explainScore records the score given to the node by a ranking iterator.
+func (a *AllocMetric) explainScore(node *Node, name string, score float64) {
+	e, class := a.explainNode(node)
+	if name == NormScorerName {
+		if class.Scored == 0 || score > class.TopScore {
+			class.TopScore = score
+		}
+		class.Scored++
+	}
+	if e == nil {
+		return
+	}
+
+	if name == NormScorerName {
+		e.NormScore = score
+		return
+	}
+	if e.Scores == nil {
+		e.Scores = make(map[string]float64)
+	}
+	e.Scores[name] = score
+}
+
 // PopulateScoreMetaData populates a map of scorer to scoring metadata
 // The map is populated by popping elements from a heap of top K scores
 // maintained per scorer
@@ -10060,6 +11259,79 @@ func (s *NodeScoreMeta) Data() interface{} {
 	return s
 }
 
+// MaxExplainedNodes is the maximum number of nodes explained individually
+// by an AllocMetric. Further nodes are only summarized by node class.
+const MaxExplainedNodes = 100
+
+// NodeExplanation explains the outcome of evaluating a node for a
+// placement.
+type NodeExplanation struct {
+	NodeID    string
+	NodeName  string
+	NodeClass string
+
+	// Filtered is the constraint or check the node failed, if any.
+	Filtered string
+
+	// Exhausted is the resource dimension exhausted on the node, if any.
+	Exhausted string
+
+	// Scores is the score given to the node by each ranking iterator and
+	// NormScore its final normalized score.
+	Scores    map[string]float64
+	NormScore float64
+}
+
+func (e *NodeExplanation) Copy() *NodeExplanation {
+	if e == nil {
+		return nil
+	}
+	ne := new(NodeExplanation)
+	*ne = *e
+	ne.Scores = helper.CopyMapStringFloat64(e.Scores)
+	return ne
+}
+
+// ClassExplanation summarizes the outcome of evaluating the nodes of a node
+// class for a placement.
+type ClassExplanation struct {
+	// Evaluated is the number of nodes of the class that were evaluated.
+	Evaluated int
+
+	// Filtered and Exhausted are the number of nodes that failed a
+	// feasibility check or were exhausted of a resource.
+	Filtered  int
+	Exhausted int
+
+	// Scored is the number of feasible nodes that were scored and TopScore
+	// the highest normalized score among them.
+	Scored   int
+	TopScore float64
+
+	// Reasons is the number of nodes by filtering or exhaustion reason.
+	Reasons map[string]int
+}
+
+func (e *ClassExplanation) Copy() *ClassExplanation {
+	if e == nil {
+		return nil
+	}
+	ne := new(ClassExplanation)
+	*ne = *e
+	ne.Reasons = helper.CopyMapStringInt(e.Reasons)
+	return ne
+}
+
+func (e *ClassExplanation) reason(reason string) {
+	if reason == "" {
+		return
+	}
+	if e.Reasons == nil {
+		e.Reasons = make(map[string]int)
+	}
+	e.Reasons[reason]++
+}
+
 // AllocNetworkStatus captures the status of an allocation's network during runtime.
 // Depending on the network mode, an allocation's address may need to be known to other
 // systems in Nomad such as service registration.
@@ -10156,24 +11428,28 @@ const (
 	EvalStatusComplete  = "complete"
 	EvalStatusFailed    = "failed"
 	EvalStatusCancelled = "canceled"
+	EvalStatusWaiting   = "waiting"
 )
 
 const (
-	EvalTriggerJobRegister       = "job-register"
-	EvalTriggerJobDeregister     = "job-deregister"
-	EvalTriggerPeriodicJob       = "periodic-job"
-	EvalTriggerNodeDrain         = "node-drain"
-	EvalTriggerNodeUpdate        = "node-update"
-	EvalTriggerAllocStop         = "alloc-stop"
-	EvalTriggerScheduled         = "scheduled"
-	EvalTriggerRollingUpdate     = "rolling-update"
-	EvalTriggerDeploymentWatcher = "deployment-watcher"
-	EvalTriggerFailedFollowUp    = "failed-follow-up"
-	EvalTriggerMaxPlans          = "max-plan-attempts"
-	EvalTriggerRetryFailedAlloc  = "alloc-failure"
-	EvalTriggerQueuedAllocs      = "queued-allocs"
-	EvalTriggerPreemption        = "preemption"
-	EvalTriggerScaling           = "job-scaling"
+	EvalTriggerJobRegister          = "job-register"
+	EvalTriggerJobDeregister        = "job-deregister"
+	EvalTriggerPeriodicJob          = "periodic-job"
+	EvalTriggerNodeDrain            = "node-drain"
+	EvalTriggerNodeUpdate           = "node-update"
+	EvalTriggerAllocStop            = "alloc-stop"
+	EvalTriggerScheduled            = "scheduled"
+	EvalTriggerRollingUpdate        = "rolling-update"
+	EvalTriggerDeploymentWatcher    = "deployment-watcher"
+	EvalTriggerFailedFollowUp       = "failed-follow-up"
+	EvalTriggerMaxPlans             = "max-plan-attempts"
+	EvalTriggerRetryFailedAlloc     = "alloc-failure"
+	EvalTriggerQueuedAllocs         = "queued-allocs"
+	EvalTriggerPreemption           = "preemption"
+	EvalTriggerScaling              = "job-scaling"
+	EvalTriggerMaxDisconnectTimeout = "max-disconnect-timeout"
+	EvalTriggerReconnect            = "reconnect"
+	EvalTriggerJobDependencies      = "job-dependencies"
 )
 
 const (
@@ -10314,6 +11590,11 @@ type Evaluation struct {
 	// during the evaluation. This should not be set during normal operations.
 	AnnotatePlan bool
 
+	// ExplainPlan triggers the scheduler to record why each node was or
+	// wasn't selected for a placement. It is only set when explaining a job
+	// plan.
+	ExplainPlan bool
+
 	// QueuedAllocations is the number of unplaced allocations at the time the
 	// evaluation was processed. The map is keyed by Task Group names.
 	QueuedAllocations map[string]int
@@ -10339,6 +11620,24 @@ type Evaluation struct {
 	ModifyTime int64
 }
 
+// GetID is a helper for getting the ID when the object may be nil and is
+// required for pagination.
+func (e *Evaluation) GetID() string {
+	if e == nil {
+		return ""
+	}
+	return e.ID
+}
+
+// GetNamespace is a helper for getting the namespace when the object may be
+// nil and is required for pagination.
+func (e *Evaluation) GetNamespace() string {
+	if e == nil {
+		return ""
+	}
+	return e.Namespace
+}
+
 // TerminalStatus returns if the current status is terminal and
 // will no longer transition.
 func (e *Evaluation) TerminalStatus() bool {
@@ -10397,7 +11696,7 @@ func (e *Evaluation) ShouldEnqueue() bool {
 	switch e.Status {
 	case EvalStatusPending:
 		return true
-	case EvalStatusComplete, EvalStatusFailed, EvalStatusBlocked, EvalStatusCancelled:
+	case EvalStatusComplete, EvalStatusFailed, EvalStatusBlocked, EvalStatusCancelled, EvalStatusWaiting:
 		return false
 	default:
 		panic(fmt.Sprintf("unhandled evaluation (%s) status %s", e.ID, e.Status))
@@ -10410,7 +11709,7 @@ func (e *Evaluation) ShouldBlock() bool {
 	switch e.Status {
 	case EvalStatusBlocked:
 		return true
-	case EvalStatusComplete, EvalStatusFailed, EvalStatusPending, EvalStatusCancelled:
+	case EvalStatusComplete, EvalStatusFailed, EvalStatusPending, EvalStatusCancelled, EvalStatusWaiting:
 		return false
 	default:
 		panic(fmt.Sprintf("unhandled evaluation (%s) status %s", e.ID, e.Status))
@@ -10479,6 +11778,27 @@ func (e *Evaluation) CreateBlockedEval(classEligibility map[string]bool,
 	}
 }
 
+// CreateWaitingEval creates a waiting evaluation to followup this eval once
+// the dependencies of the job are met. The reason describes the unmet
+// dependency.
+func (e *Evaluation) CreateWaitingEval(reason string) *Evaluation {
+	now := time.Now().UTC().UnixNano()
+	return &Evaluation{
+		ID:                uuid.Generate(),
+		Namespace:         e.Namespace,
+		Priority:          e.Priority,
+		Type:              e.Type,
+		TriggeredBy:       EvalTriggerJobDependencies,
+		JobID:             e.JobID,
+		JobModifyIndex:    e.JobModifyIndex,
+		Status:            EvalStatusWaiting,
+		StatusDescription: reason,
+		PreviousEval:      e.ID,
+		CreateTime:        now,
+		ModifyTime:        now,
+	}
+}
+
 // CreateFailedFollowUpEval creates a follow up evaluation when the current one
 // has been marked as failed because it has hit the delivery limit and will not
 // be retried by the eval_broker. Callers should copy the created eval's ID to
@@ -10574,6 +11894,11 @@ type Plan struct {
 	// Plan. The leader will wait to evaluate the plan until its StateStore
 	// has reached at least this index.
 	SnapshotIndex uint64
+
+	// Reservation is a reservation to create instead of allocations to
+	// place. The leader verifies its instances still fit on their nodes
+	// before committing it, so it is serialized with the other plans.
+	Reservation *Reservation
 }
 
 func (p *Plan) GoString() string {
@@ -10715,6 +12040,67 @@ func (p *Plan) PopUpdate(alloc *Allocation) {
 	}
 }
 
+// RemoveUpdate removes a stopped allocation from the plan, wherever it is in
+// the updates of its node.
+func (p *Plan) RemoveUpdate(alloc *Allocation) {
+	existing := p.NodeUpdate[alloc.NodeID]
+	for i, update := range existing {
+		if update.ID != alloc.ID {
+			continue
+		}
+		existing = append(existing[:i:i], existing[i+1:]...)
+		if len(existing) > 0 {
+			p.NodeUpdate[alloc.NodeID] = existing
+		} else {
+			delete(p.NodeUpdate, alloc.NodeID)
+		}
+		return
+	}
+}
+
+// RemovePlacement removes a placed allocation from the plan, along with the
+// allocations it preempts.
+func (p *Plan) RemovePlacement(alloc *Allocation) {
+	node := alloc.NodeID
+
+	var allocs []*Allocation
+	for _, a := range p.NodeAllocation[node] {
+		if a.ID != alloc.ID {
+			allocs = append(allocs, a)
+		}
+	}
+	if len(allocs) > 0 {
+		p.NodeAllocation[node] = allocs
+	} else {
+		delete(p.NodeAllocation, node)
+	}
+
+	var preemptions []*Allocation
+	for _, a := range p.NodePreemptions[node] {
+		if a.PreemptedByAllocation != alloc.ID {
+			preemptions = append(preemptions, a)
+		}
+	}
+	if len(preemptions) > 0 {
+		p.NodePreemptions[node] = preemptions
+	} else {
+		delete(p.NodePreemptions, node)
+	}
+}
+
+// AppendUnknownAlloc marks an allocation as unknown because its client is
+// disconnected, and appends it to the plan allocations.
+func (p *Plan) AppendUnknownAlloc(alloc *Allocation) {
+	// Strip the job as it's set once on the ApplyPlanResultRequest.
+	alloc.Job = nil
+	alloc.ClientStatus = AllocClientStatusUnknown
+	alloc.AppendState(AllocStateFieldClientStatus, AllocClientStatusUnknown)
+
+	node := alloc.NodeID
+	existing := p.NodeAllocation[node]
+	p.NodeAllocation[node] = append(existing, alloc)
+}
+
 // AppendAlloc appends the alloc to the plan allocations.
 // Uses the passed job if explicitly passed, otherwise
 // it is assumed the alloc will use the plan Job version.
@@ -11103,6 +12489,18 @@ type ACLToken struct {
 	Global      bool     // Global or Region local
 	Hash        []byte
 	CreateTime  time.Time // Time of creation
+
+	// SecretHash is the hash of the SecretID, by which tokens are looked up
+	// in the state store. See ACLTokenSecretHash.
+	SecretHash string
+
+	// EncryptedSecretID is the SecretID encrypted with the root key
+	// SecretKeyID. Once the keyring has been initialized tokens are stored
+	// with only their encrypted secret, which is decrypted outside of the
+	// FSM when the token is returned to a user.
+	EncryptedSecretID []byte
+	SecretKeyID       string
+
 	CreateIndex uint64
 	ModifyIndex uint64
 }
@@ -11115,10 +12513,28 @@ func (a *ACLToken) Copy() *ACLToken {
 	copy(c.Policies, a.Policies)
 	c.Hash = make([]byte, len(a.Hash))
 	copy(c.Hash, a.Hash)
+	if a.EncryptedSecretID != nil {
+		c.EncryptedSecretID = make([]byte, len(a.EncryptedSecretID))
+		copy(c.EncryptedSecretID, a.EncryptedSecretID)
+	}
 
 	return c
 }
 
+// ACLTokenSecretHash returns the hash of an ACL token's SecretID.
+func ACLTokenSecretHash(secretID string) string {
+	sum := sha256.Sum256([]byte(secretID))
+	return hex.EncodeToString(sum[:])
+}
+
+// SetSecretHash sets the SecretHash of the token from its SecretID if it is
+// not already set.
+func (a *ACLToken) SetSecretHash() {
+	if a.SecretHash == "" && a.SecretID != "" {
+		a.SecretHash = ACLTokenSecretHash(a.SecretID)
+	}
+}
+
 var (
 	// AnonymousACLToken is used no SecretID is provided, and the
 	// request is made anonymously.
@@ -11206,6 +12622,15 @@ func (a *ACLToken) Validate() error {
 	return mErr.ErrorOrNil()
 }
 
+// GetID is a helper for getting the accessor ID when the object may be nil
+// and is required for pagination.
+func (a *ACLToken) GetID() string {
+	if a == nil {
+		return ""
+	}
+	return a.AccessorID
+}
+
 // PolicySubset checks if a given set of policies is a subset of the token
 func (a *ACLToken) PolicySubset(policies []string) bool {
 	// Hot-path the management tokens, superset of all policies.
@@ -11282,12 +12707,28 @@ type ACLTokenDeleteRequest struct {
 type ACLTokenBootstrapRequest struct {
 	Token      *ACLToken // Not client specifiable
 	ResetIndex uint64    // Reset index is used to clear the bootstrap token
+
+	// EncryptedToken is set instead of Token once the keyring has been
+	// initialized, so that the secret is never written to Raft in the clear.
+	EncryptedToken *ACLTokenEncrypted
+
 	WriteRequest
 }
 
 // ACLTokenUpsertRequest is used to upsert a set of tokens
 type ACLTokenUpsertRequest struct {
 	Tokens []*ACLToken
+
+	// EncryptedTokens is set instead of Tokens once the keyring has been
+	// initialized, so that the secrets are never written to Raft in the
+	// clear.
+	EncryptedTokens []*ACLTokenEncrypted
+
+	// Rekey indicates that only the encrypted secrets of existing tokens
+	// are being replaced, after they were re-encrypted with a new root key.
+	// Tokens which no longer exist or whose secret changed are skipped.
+	Rekey bool
+
 	WriteRequest
 }
 
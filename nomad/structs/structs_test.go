@@ -2362,6 +2362,26 @@ func TestLogConfig_Equals(t *testing.T) {
 		require.False(t, a.Equals(b))
 	})
 
+	t.Run("sinks", func(t *testing.T) {
+		a := &LogConfig{MaxFiles: 1, MaxFileSizeMB: 200, Sinks: []*LogSink{{Type: LogSinkTypeTCP, Address: "127.0.0.1:5000"}}}
+		b := &LogConfig{MaxFiles: 1, MaxFileSizeMB: 200, Sinks: []*LogSink{{Type: LogSinkTypeTCP, Address: "127.0.0.1:5001"}}}
+		require.False(t, a.Equals(b))
+		require.True(t, a.Equals(a.Copy()))
+	})
+
+	t.Run("rate limit", func(t *testing.T) {
+		a := &LogConfig{MaxFiles: 1, MaxFileSizeMB: 200, RateLimit: &LogRateLimit{LinesPerSecond: 100}}
+		b := &LogConfig{MaxFiles: 1, MaxFileSizeMB: 200, RateLimit: &LogRateLimit{LinesPerSecond: 200}}
+		require.False(t, a.Equals(b))
+		require.True(t, a.Equals(a.Copy()))
+	})
+
+	t.Run("compress rotated", func(t *testing.T) {
+		a := &LogConfig{MaxFiles: 1, MaxFileSizeMB: 200, CompressRotated: true}
+		b := &LogConfig{MaxFiles: 1, MaxFileSizeMB: 200}
+		require.False(t, a.Equals(b))
+	})
+
 	t.Run("same", func(t *testing.T) {
 		a := &LogConfig{MaxFiles: 1, MaxFileSizeMB: 200}
 		b := &LogConfig{MaxFiles: 1, MaxFileSizeMB: 200}
@@ -2369,6 +2389,135 @@ func TestLogConfig_Equals(t *testing.T) {
 	})
 }
 
+func TestLogConfig_Validate_RateLimit(t *testing.T) {
+	cases := []struct {
+		name      string
+		rateLimit *LogRateLimit
+		err       string
+	}{
+		{
+			name:      "lines",
+			rateLimit: &LogRateLimit{LinesPerSecond: 100},
+		},
+		{
+			name:      "bytes",
+			rateLimit: &LogRateLimit{BytesPerSecond: 1024},
+		},
+		{
+			name:      "unset",
+			rateLimit: &LogRateLimit{},
+			err:       "lines per second or bytes per second must be set",
+		},
+		{
+			name:      "negative",
+			rateLimit: &LogRateLimit{LinesPerSecond: -1, BytesPerSecond: 1024},
+			err:       "lines per second must not be negative",
+		},
+	}
+
+	for _, tc := range cases {
+		t.Run(tc.name, func(t *testing.T) {
+			config := DefaultLogConfig()
+			config.RateLimit = tc.rateLimit
+			err := config.Validate()
+			if tc.err == "" {
+				require.NoError(t, err)
+				return
+			}
+			require.Error(t, err)
+			require.Contains(t, err.Error(), tc.err)
+		})
+	}
+}
+
+func TestLogConfig_Validate_Sinks(t *testing.T) {
+	cases := []struct {
+		name string
+		sink *LogSink
+		err  string
+	}{
+		{
+			name: "syslog default address",
+			sink: &LogSink{Type: LogSinkTypeSyslog},
+		},
+		{
+			name: "syslog tcp",
+			sink: &LogSink{Type: LogSinkTypeSyslog, Address: "tcp://127.0.0.1:514"},
+		},
+		{
+			name: "syslog bad scheme",
+			sink: &LogSink{Type: LogSinkTypeSyslog, Address: "udp://127.0.0.1:514"},
+			err:  "must use the unix, unixgram or tcp scheme",
+		},
+		{
+			name: "file",
+			sink: &LogSink{Type: LogSinkTypeFile, Path: "web.json"},
+		},
+		{
+			name: "file missing path",
+			sink: &LogSink{Type: LogSinkTypeFile},
+			err:  "require a path",
+		},
+		{
+			name: "file escapes",
+			sink: &LogSink{Type: LogSinkTypeFile, Path: "../../../etc/passwd"},
+			err:  "must be relative and within the log directory",
+		},
+		{
+			name: "file escapes log dir",
+			sink: &LogSink{Type: LogSinkTypeFile, Path: "../tmp/web.json"},
+			err:  "must be relative and within the log directory",
+		},
+		{
+			name: "file absolute",
+			sink: &LogSink{Type: LogSinkTypeFile, Path: "/var/log/web.json"},
+			err:  "must be relative and within the log directory",
+		},
+		{
+			name: "tcp",
+			sink: &LogSink{Type: LogSinkTypeTCP, Address: "logs.example.com:5000"},
+		},
+		{
+			name: "tcp missing port",
+			sink: &LogSink{Type: LogSinkTypeTCP, Address: "logs.example.com"},
+			err:  "invalid tcp address",
+		},
+		{
+			name: "http",
+			sink: &LogSink{Type: LogSinkTypeHTTP, Address: "https://logs.example.com/ingest"},
+		},
+		{
+			name: "http bad scheme",
+			sink: &LogSink{Type: LogSinkTypeHTTP, Address: "logs.example.com"},
+			err:  "must use the http or https scheme",
+		},
+		{
+			name: "negative buffer size",
+			sink: &LogSink{Type: LogSinkTypeTCP, Address: "127.0.0.1:5000", BufferSize: -1},
+			err:  "buffer size must not be negative",
+		},
+		{
+			name: "unknown type",
+			sink: &LogSink{Type: "kafka"},
+			err:  "invalid log sink type",
+		},
+	}
+
+	for _, tc := range cases {
+		t.Run(tc.name, func(t *testing.T) {
+			l := DefaultLogConfig()
+			l.Sinks = []*LogSink{tc.sink}
+			err := l.Validate()
+			if tc.err == "" {
+				require.NoError(t, err)
+				return
+			}
+			require.Error(t, err)
+			require.Contains(t, err.Error(), tc.err)
+		})
+	}
+}
+
 func TestTask_Validate_CSIPluginConfig(t *testing.T) {
 	table := []struct {
 		name          string
@@ -2463,6 +2612,7 @@ func TestTask_Validate_Template(t *testing.T) {
 func TestTemplate_Validate(t *testing.T) {
 	cases := []struct {
 		Tmpl         *Template
+		Driver       string
 		Fail         bool
 		ContainsErrs []string
 	}{
@@ -2542,10 +2692,80 @@ func TestTemplate_Validate(t *testing.T) {
 				"as octal",
 			},
 		},
+		{
+			Tmpl: &Template{
+				SourcePath: "foo",
+				DestPath:   "local/foo",
+				ChangeMode: "script",
+			},
+			Fail: true,
+			ContainsErrs: []string{
+				"specify change script configuration",
+			},
+		},
+		{
+			Tmpl: &Template{
+				SourcePath:   "foo",
+				DestPath:     "local/foo",
+				ChangeMode:   "script",
+				ChangeScript: &ChangeScript{},
+			},
+			Fail: true,
+			ContainsErrs: []string{
+				"specify a change script command",
+				"positive change script timeout",
+			},
+		},
+		{
+			Tmpl: &Template{
+				SourcePath: "foo",
+				DestPath:   "local/foo",
+				ChangeMode: "script",
+				ChangeScript: &ChangeScript{
+					Command: "/bin/reload",
+					Timeout: 5 * time.Second,
+				},
+				Envvars: true,
+			},
+			Fail: true,
+			ContainsErrs: []string{
+				"cannot use change scripts with env var templates",
+			},
+		},
+		{
+			Tmpl: &Template{
+				SourcePath: "foo",
+				DestPath:   "local/foo",
+				ChangeMode: "script",
+				ChangeScript: &ChangeScript{
+					Command: "/bin/reload",
+					Args:    []string{"-c", "local/foo"},
+					Timeout: 5 * time.Second,
+				},
+			},
+			Driver: "exec",
+			Fail:   false,
+		},
+		{
+			Tmpl: &Template{
+				SourcePath: "foo",
+				DestPath:   "local/foo",
+				ChangeMode: "script",
+				ChangeScript: &ChangeScript{
+					Command: "/bin/reload",
+					Timeout: 5 * time.Second,
+				},
+			},
+			Driver: "qemu",
+			Fail:   true,
+			ContainsErrs: []string{
+				`cannot use change scripts with the "qemu" driver`,
+			},
+		},
 	}
 
 	for i, c := range cases {
-		err := c.Tmpl.Validate()
+		err := c.Tmpl.Validate(c.Driver)
 		if err != nil {
 			if !c.Fail {
 				t.Fatalf("Case %d: shouldn't have failed: %v", i+1, err)
@@ -2721,6 +2941,46 @@ func TestAffinity_Validate(t *testing.T) {
 	}
 }
 
+func TestAllocAffinity_Validate(t *testing.T) {
+	testCases := []struct {
+		name     string
+		affinity *AllocAffinity
+		err      string
+	}{
+		{
+			name:     "missing job",
+			affinity: &AllocAffinity{Weight: 50},
+			err:      "Missing alloc affinity job",
+		},
+		{
+			name:     "zero weight",
+			affinity: &AllocAffinity{Job: "cache"},
+			err:      "Alloc affinity weight cannot be zero",
+		},
+		{
+			name:     "weight out of range",
+			affinity: &AllocAffinity{Job: "cache", Weight: -110},
+			err:      "Alloc affinity weight must be within the range [-100,100]",
+		},
+		{
+			name:     "valid",
+			affinity: &AllocAffinity{Job: "cache", TaskGroup: "redis", Weight: -100, Hard: true},
+		},
+	}
+
+	for _, tc := range testCases {
+		t.Run(tc.name, func(t *testing.T) {
+			err := tc.affinity.Validate()
+			if tc.err != "" {
+				require.Error(t, err)
+				require.Contains(t, err.Error(), tc.err)
+			} else {
+				require.NoError(t, err)
+			}
+		})
+	}
+}
+
 func TestUpdateStrategy_Validate(t *testing.T) {
 	u := &UpdateStrategy{
 		MaxParallel:      -1,
@@ -3763,6 +4023,64 @@ func TestAllocation_Index(t *testing.T) {
 	}
 }
 
+func TestAllocMetric_Explain(t *testing.T) {
+	nodes := make([]*Node, MaxExplainedNodes+10)
+	for i := range nodes {
+		nodes[i] = &Node{
+			ID:        fmt.Sprintf("node-%d", i),
+			Name:      fmt.Sprintf("node-%d", i),
+			NodeClass: "small",
+		}
+	}
+	nodes[0].NodeClass = "large"
+
+	// Nothing is explained unless enabled
+	m := new(AllocMetric)
+	m.FilterNode(nodes[0], "constraint")
+	require.Nil(t, m.NodeExplanations)
+	require.Nil(t, m.ClassExplanations)
+
+	m = new(AllocMetric)
+	m.EnableExplain()
+	m.FilterNode(nodes[0], "constraint")
+	m.ExhaustedNode(nodes[1], "memory")
+	for i, node := range nodes[2:] {
+		score := 0.25
+		if i == 0 {
+			score = 0.75
+		}
+		m.ScoreNode(node, "binpack", 0.5)
+		m.ScoreNode(node, NormScorerName, score)
+	}
+
+	require.Len(t, m.NodeExplanations, MaxExplainedNodes)
+	require.Equal(t, "constraint", m.NodeExplanations[0].Filtered)
+	require.Equal(t, "memory", m.NodeExplanations[1].Exhausted)
+	require.Equal(t, 0.75, m.NodeExplanations[2].NormScore)
+	require.Equal(t, map[string]float64{"binpack": 0.5}, m.NodeExplanations[2].Scores)
+
+	// Nodes past the limit are still summarized by class
+	require.Equal(t, &ClassExplanation{
+		Evaluated: 1,
+		Filtered:  1,
+		Reasons:   map[string]int{"constraint": 1},
+	}, m.ClassExplanations["large"])
+
+	small := m.ClassExplanations["small"]
+	require.Equal(t, len(nodes)-1, small.Evaluated)
+	require.Equal(t, 1, small.Exhausted)
+	require.Equal(t, len(nodes)-2, small.Scored)
+	require.Equal(t, 0.75, small.TopScore)
+
+	// Copies are independent
+	c := m.Copy()
+	c.ScoreNode(nodes[3], "binpack", 1)
+	c.FilterNode(nodes[len(nodes)-1], "other")
+	require.Equal(t, 0.5, m.NodeExplanations[3].Scores["binpack"])
+	require.Equal(t, len(nodes)-1, m.ClassExplanations["small"].Evaluated)
+	require.Equal(t, len(nodes)-1, c.ClassExplanations["small"].Evaluated)
+}
+
 func TestTaskArtifact_Validate_Source(t *testing.T) {
 	valid := &TaskArtifact{GetterSource: "google.com"}
 	if err := valid.Validate(); err != nil {
@@ -4908,6 +5226,87 @@ func TestAllocation_WaitClientStop(t *testing.T) {
 	}
 }
 
+func TestAllocation_DisconnectTimeout(t *testing.T) {
+	j := testJob()
+	maxDisconnect := 5 * time.Minute
+	j.TaskGroups[0].MaxClientDisconnect = &maxDisconnect
+
+	a := &Allocation{
+		ClientStatus: AllocClientStatusRunning,
+		Job:          j,
+		TaskGroup:    j.TaskGroups[0].Name,
+	}
+	require.True(t, a.SupportsDisconnectedClients())
+
+	// Before the alloc is marked unknown the timeout starts from now.
+	now := time.Now().UTC()
+	require.Equal(t, now.Add(maxDisconnect), a.DisconnectTimeout(now))
+	require.False(t, a.Expired(now))
+	require.False(t, a.NeedsToReconcile())
+
+	// Once unknown the timeout starts from the transition.
+	a.ClientStatus = AllocClientStatusUnknown
+	a.AllocStates = []*AllocState{{
+		Field: AllocStateFieldClientStatus,
+		Value: AllocClientStatusUnknown,
+		Time:  now.Add(-10 * time.Minute),
+	}}
+	require.Equal(t, now.Add(-5*time.Minute), a.DisconnectTimeout(now))
+	require.True(t, a.Expired(now))
+	require.False(t, a.NeedsToReconcile())
+
+	// The client reconnected and reported a new status.
+	a.ClientStatus = AllocClientStatusRunning
+	require.False(t, a.Expired(now))
+	require.True(t, a.NeedsToReconcile())
+
+	// The scheduler reconciled the allocation.
+	a.AppendState(AllocStateFieldClientStatus, AllocClientStatusRunning)
+	require.False(t, a.NeedsToReconcile())
+
+	// Allocations without max_client_disconnect don't support disconnects.
+	j.TaskGroups[0].MaxClientDisconnect = nil
+	require.False(t, a.SupportsDisconnectedClients())
+}
+
+func TestAllocation_MaxRunDurationExceeded(t *testing.T) {
+	j := testJob()
+	j.Type = JobTypeBatch
+	j.TaskGroups[0].ReschedulePolicy = &ReschedulePolicy{
+		Attempts:      1,
+		Interval:      time.Hour,
+		Delay:         5 * time.Second,
+		DelayFunction: "constant",
+	}
+
+	now := time.Now().UTC()
+	a := &Allocation{
+		ClientStatus: AllocClientStatusFailed,
+		Job:          j,
+		TaskGroup:    j.TaskGroups[0].Name,
+		TaskStates: map[string]*TaskState{
+			"web": {
+				State:      TaskStateDead,
+				Failed:     true,
+				FinishedAt: now,
+				Events:     []*TaskEvent{NewTaskEvent(TaskTerminated)},
+			},
+		},
+	}
+	require.False(t, a.MaxRunDurationExceeded())
+	require.True(t, a.ShouldReschedule(j.TaskGroups[0].ReschedulePolicy, now))
+	_, eligible := a.NextRescheduleTime()
+	require.True(t, eligible)
+
+	// Allocations killed at their deadline are not rescheduled
+	a.TaskStates["web"].Events = append(a.TaskStates["web"].Events,
+		NewTaskEvent(TaskMaxRunDurationExceeded).SetFailsTask())
+	require.True(t, a.MaxRunDurationExceeded())
+	require.False(t, a.ShouldReschedule(j.TaskGroups[0].ReschedulePolicy, now))
+	_, eligible = a.NextRescheduleTime()
+	require.False(t, eligible)
+}
+
 func TestAllocation_Canonicalize_Old(t *testing.T) {
 	alloc := MockAlloc()
 	alloc.AllocatedResources = nil
@@ -5079,6 +5478,78 @@ func TestJobConfig_Validate_StopAferClientDisconnect(t *testing.T) {
 	require.NoError(t, err)
 }
 
+func TestJobConfig_Validate_MaxClientDisconnect(t *testing.T) {
+	// Setup a system Job with max_client_disconnect set, which is invalid
+	job := testJob()
+	job.Type = JobTypeSystem
+	maxDisconnect := 1 * time.Minute
+	job.TaskGroups[0].MaxClientDisconnect = &maxDisconnect
+
+	err := job.Validate()
+	require.Error(t, err)
+	require.Contains(t, err.Error(), "max_client_disconnect can only be set in batch and service jobs")
+
+	// Modify the job to a service job with a negative max_client_disconnect
+	job.Type = JobTypeService
+	invalid := -1 * time.Minute
+	job.TaskGroups[0].MaxClientDisconnect = &invalid
+
+	err = job.Validate()
+	require.Error(t, err)
+	require.Contains(t, err.Error(), "max_client_disconnect cannot be negative")
+
+	// max_client_disconnect can't be combined with stop_after_client_disconnect
+	job.TaskGroups[0].MaxClientDisconnect = &maxDisconnect
+	job.TaskGroups[0].StopAfterClientDisconnect = &maxDisconnect
+	err = job.Validate()
+	require.Error(t, err)
+	require.Contains(t, err.Error(), "mutually exclusive")
+
+	job.TaskGroups[0].StopAfterClientDisconnect = nil
+	err = job.Validate()
+	require.NoError(t, err)
+}
+
+func TestJobConfig_Validate_Placement(t *testing.T) {
+	// all_or_nothing placement is invalid for service jobs
+	job := testJob()
+	job.Type = JobTypeService
+	job.TaskGroups[0].Placement = TaskGroupPlacementAllOrNothing
+
+	err := job.Validate()
+	require.Error(t, err)
+	require.Contains(t, err.Error(), "only valid for batch jobs")
+
+	job.Type = JobTypeBatch
+	require.NoError(t, job.Validate())
+
+	job.TaskGroups[0].Placement = "some"
+	err = job.Validate()
+	require.Error(t, err)
+	require.Contains(t, err.Error(), "Invalid placement")
+}
+
+func TestJobConfig_Validate_MaxRunDuration(t *testing.T) {
+	// max_run_duration is invalid for service jobs
+	job := testJob()
+	job.Type = JobTypeService
+	maxRun := 10 * time.Minute
+	job.TaskGroups[0].MaxRunDuration = &maxRun
+
+	err := job.Validate()
+	require.Error(t, err)
+	require.Contains(t, err.Error(), "max_run_duration can only be set in batch and sysbatch jobs")
+
+	job.Type = JobTypeBatch
+	require.NoError(t, job.Validate())
+
+	invalid := time.Duration(0)
+	job.TaskGroups[0].MaxRunDuration = &invalid
+	err = job.Validate()
+	require.Error(t, err)
+	require.Contains(t, err.Error(), "max_run_duration must be a positive value")
+}
+
 func TestParameterizedJobConfig_Canonicalize(t *testing.T) {
 	d := &ParameterizedJobConfig{}
 	d.Canonicalize()
@@ -5797,6 +6268,40 @@ func TestSpread_Validate(t *testing.T) {
 			err:  fmt.Errorf("Spread stanza must have a positive weight from 0 to 100"),
 			name: "Invalid weight",
 		},
+		{
+			spread: &Spread{
+				Attribute: "${node.datacenter}",
+				Weight:    50,
+				Hard:      true,
+			},
+			err:  fmt.Errorf("Hard spread stanza must have a max_skew of at least 1"),
+			name: "Hard spread without max skew",
+		},
+		{
+			spread: &Spread{
+				Attribute: "${node.datacenter}",
+				Weight:    50,
+				MaxSkew:   1,
+			},
+			err:  fmt.Errorf("Spread stanza max_skew requires hard to be set"),
+			name: "Max skew without hard",
+		},
+		{
+			spread: &Spread{
+				Attribute: "${node.datacenter}",
+				Weight:    50,
+				MaxSkew:   1,
+				Hard:      true,
+				SpreadTarget: []*SpreadTarget{
+					{
+						Value:   "dc1",
+						Percent: 50,
+					},
+				},
+			},
+			err:  fmt.Errorf("Hard spread stanza cannot have targets"),
+			name: "Hard spread with targets",
+		},
 		{
 			spread: &Spread{
 				Attribute: "${node.datacenter}",
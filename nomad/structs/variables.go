@@ -0,0 +1,343 @@
+package structs
+
+import (
+	"bytes"
+	"fmt"
+	"regexp"
+	"strings"
+
+	"github.com/hashicorp/nomad/helper"
+)
+
+const (
+	// VariablesMaxSize is the maximum size of the unencrypted contents of a
+	// variable. This size is deliberately set low and is not configurable, to
+	// discourage DoS'ing the cluster.
+	VariablesMaxSize = 16384
+
+	// VariablesJobPathPrefix is the reserved path prefix under which Nomad
+	// grants tasks implicit read access to the variables of their job.
+	VariablesJobPathPrefix = "nomad/jobs"
+)
+
+var (
+	// validVariablePath is used to validate variable paths. Paths are made of
+	// slash separated segments of alphanumeric characters, dashes,
+	// underscores and tildes.
+	validVariablePath = regexp.MustCompile("^[a-zA-Z0-9-_~/]{1,128}$")
+)
+
+// VariableMetadata is the metadata envelope for a Variable. It is the list
+// object and is shared data between a VariableEncrypted and a
+// VariableDecrypted object.
+type VariableMetadata struct {
+	Namespace   string
+	Path        string
+	CreateIndex uint64
+	CreateTime  int64
+	ModifyIndex uint64
+	ModifyTime  int64
+}
+
+// VariableEncrypted structs are returned from the Encrypter's encrypt method.
+// They are the only form that should ever be persisted to storage.
+type VariableEncrypted struct {
+	VariableMetadata
+	VariableData
+}
+
+// VariableData is the secret data for a Variable.
+type VariableData struct {
+	// Data is the encrypted contents of the variable, including the nonce.
+	Data []byte
+
+	// KeyID is the ID of the root key used to encrypt this entry.
+	KeyID string
+}
+
+// VariableDecrypted structs are returned from the Encrypter's decrypt method.
+// Since they contain sensitive material, they should never be persisted to
+// disk.
+type VariableDecrypted struct {
+	VariableMetadata
+	Items VariableItems
+}
+
+// VariableItems are the actual secrets stored in a variable. They are always
+// encrypted and decrypted as a single unit.
+type VariableItems map[string]string
+
+// Size returns the number of bytes used by the keys and values of the items.
+func (vi VariableItems) Size() uint64 {
+	var out uint64
+	for k, v := range vi {
+		out += uint64(len(k))
+		out += uint64(len(v))
+	}
+	return out
+}
+
+// Equals checks both the metadata and items in a VariableDecrypted struct.
+func (v1 VariableDecrypted) Equals(v2 VariableDecrypted) bool {
+	return v1.VariableMetadata.Equals(v2.VariableMetadata) &&
+		v1.Items.Equals(v2.Items)
+}
+
+// Equals is a convenience method to provide similar equality checking syntax
+// for metadata and the VariablesData or VariableItems struct.
+func (sv VariableMetadata) Equals(sv2 VariableMetadata) bool {
+	return sv == sv2
+}
+
+// Equals performs deep equality checking on the cleartext items of a
+// VariableDecrypted.
+func (i1 VariableItems) Equals(i2 VariableItems) bool {
+	return helper.CompareMapStringString(i1, i2)
+}
+
+// Equals checks both the metadata and encrypted data for a VariableEncrypted
+// struct.
+func (v1 VariableEncrypted) Equals(v2 VariableEncrypted) bool {
+	return v1.VariableMetadata.Equals(v2.VariableMetadata) &&
+		v1.VariableData.Equals(v2.VariableData)
+}
+
+// Equals performs deep equality checking on the encrypted data part of a
+// VariableEncrypted.
+func (d1 VariableData) Equals(d2 VariableData) bool {
+	return d1.KeyID == d2.KeyID &&
+		bytes.Equal(d1.Data, d2.Data)
+}
+
+// Copy returns a deep copy of the decrypted variable.
+func (sv VariableDecrypted) Copy() VariableDecrypted {
+	return VariableDecrypted{
+		VariableMetadata: sv.VariableMetadata,
+		Items:            sv.Items.Copy(),
+	}
+}
+
+// Copy returns a copy of the variable items.
+func (sv VariableItems) Copy() VariableItems {
+	return helper.CopyMapStringString(sv)
+}
+
+// Copy returns a deep copy of the encrypted variable.
+func (sv VariableEncrypted) Copy() VariableEncrypted {
+	return VariableEncrypted{
+		VariableMetadata: sv.VariableMetadata,
+		VariableData:     sv.VariableData.Copy(),
+	}
+}
+
+// Copy returns a deep copy of the encrypted data.
+func (sv VariableData) Copy() VariableData {
+	out := make([]byte, len(sv.Data))
+	copy(out, sv.Data)
+	return VariableData{
+		Data:  out,
+		KeyID: sv.KeyID,
+	}
+}
+
+// Validate ensures the decrypted variable has a valid path and namespace,
+// and that its items are within the size limits.
+func (sv VariableDecrypted) Validate() error {
+	if len(sv.Path) == 0 {
+		return fmt.Errorf("variable requires path")
+	}
+	if !validVariablePath.MatchString(sv.Path) {
+		return fmt.Errorf("invalid path %q", sv.Path)
+	}
+
+	parts := strings.Split(sv.Path, "/")
+	switch {
+	case len(parts) == 1 && parts[0] == "nomad":
+		return fmt.Errorf("\"nomad\" is a reserved top-level directory path, but you may write variables to \"%s\" or below", VariablesJobPathPrefix)
+	case len(parts) >= 2 && parts[0] == "nomad" && parts[1] != "jobs":
+		return fmt.Errorf("only paths at \"%s\" or below are valid paths under the top-level \"nomad\" directory", VariablesJobPathPrefix)
+	}
+
+	for _, part := range parts {
+		if part == "" {
+			return fmt.Errorf("invalid path %q: path segments must not be empty", sv.Path)
+		}
+	}
+
+	if len(sv.Items) == 0 {
+		return fmt.Errorf("empty variables are invalid")
+	}
+	if sv.Items.Size() > VariablesMaxSize {
+		return fmt.Errorf("variables are limited to %d bytes in total size", VariablesMaxSize)
+	}
+	if sv.Namespace == AllNamespacesSentinel {
+		return fmt.Errorf("can not target wildcard (\"%s\") namespace", AllNamespacesSentinel)
+	}
+	return nil
+}
+
+// Canonicalize sets the default namespace for the variable.
+func (sv *VariableDecrypted) Canonicalize() {
+	if sv.Namespace == "" {
+		sv.Namespace = DefaultNamespace
+	}
+}
+
+// VarOp constants give possible operations available in a transaction.
+type VarOp string
+
+const (
+	VarOpSet       VarOp = "set"
+	VarOpDelete    VarOp = "delete"
+	VarOpDeleteCAS VarOp = "delete-cas"
+	VarOpCAS       VarOp = "cas"
+)
+
+// VarOpResult constants give possible operations results from a transaction.
+type VarOpResult string
+
+const (
+	VarOpResultOk       VarOpResult = "ok"
+	VarOpResultConflict VarOpResult = "conflict"
+	VarOpResultRedacted VarOpResult = "conflict-redacted"
+	VarOpResultError    VarOpResult = "error"
+)
+
+// VariablesApplyRequest is used by users to operate on the variable store.
+type VariablesApplyRequest struct {
+	Op  VarOp
+	Var *VariableDecrypted
+	WriteRequest
+}
+
+// VariablesApplyResponse is sent back to the user to inform them of success
+// or failure.
+type VariablesApplyResponse struct {
+	Op       VarOp
+	Input    *VariableDecrypted
+	Result   VarOpResult
+	Conflict *VariableDecrypted
+	Output   *VariableDecrypted
+	WriteMeta
+}
+
+// IsOk returns whether the operation was successful.
+func (r *VariablesApplyResponse) IsOk() bool {
+	return r.Result == VarOpResultOk
+}
+
+// IsConflict returns whether the operation failed the check-and-set.
+func (r *VariablesApplyResponse) IsConflict() bool {
+	return r.Result == VarOpResultConflict || r.Result == VarOpResultRedacted
+}
+
+// IsError returns whether the operation failed for any reason other than a
+// check-and-set conflict.
+func (r *VariablesApplyResponse) IsError() bool {
+	return r.Result == VarOpResultError
+}
+
+// IsRedacted returns whether the conflicting variable was redacted because
+// the caller does not have permission to read it.
+func (r *VariablesApplyResponse) IsRedacted() bool {
+	return r.Result == VarOpResultRedacted
+}
+
+// VarApplyStateRequest is used by the Variables RPC endpoint to apply
+// operations to the state store via Raft. Only the encrypted form of the
+// variable is ever written.
+type VarApplyStateRequest struct {
+	Op  VarOp
+	Var *VariableEncrypted
+	WriteRequest
+}
+
+// VarApplyStateResponse is used by the FSM to inform the RPC server of
+// success or failure.
+type VarApplyStateResponse struct {
+	Op            VarOp
+	Result        VarOpResult
+	Error         error
+	Conflict      *VariableEncrypted
+	WrittenSVMeta *VariableMetadata
+	WriteMeta
+}
+
+// ErrorResponse returns a response for a failed state store operation.
+func (r VarApplyStateRequest) ErrorResponse(raftIndex uint64, err error) *VarApplyStateResponse {
+	return &VarApplyStateResponse{
+		Op:        r.Op,
+		Result:    VarOpResultError,
+		Error:     err,
+		WriteMeta: WriteMeta{Index: raftIndex},
+	}
+}
+
+// SuccessResponse returns a response for a successful state store operation.
+func (r VarApplyStateRequest) SuccessResponse(raftIndex uint64, meta *VariableMetadata) *VarApplyStateResponse {
+	return &VarApplyStateResponse{
+		Op:            r.Op,
+		Result:        VarOpResultOk,
+		WrittenSVMeta: meta,
+		WriteMeta:     WriteMeta{Index: raftIndex},
+	}
+}
+
+// ConflictResponse returns a response for a failed check-and-set operation,
+// including the conflicting variable.
+func (r VarApplyStateRequest) ConflictResponse(raftIndex uint64, cv *VariableEncrypted) *VarApplyStateResponse {
+	var cvCopy VariableEncrypted
+	if cv != nil {
+		// make a copy so that we aren't sending
+		// the live state store version
+		cvCopy = cv.Copy()
+	}
+	return &VarApplyStateResponse{
+		Op:        r.Op,
+		Result:    VarOpResultConflict,
+		Conflict:  &cvCopy,
+		WriteMeta: WriteMeta{Index: raftIndex},
+	}
+}
+
+// IsOk returns whether the operation was successful.
+func (r *VarApplyStateResponse) IsOk() bool {
+	return r.Result == VarOpResultOk
+}
+
+// IsConflict returns whether the operation failed the check-and-set.
+func (r *VarApplyStateResponse) IsConflict() bool {
+	return r.Result == VarOpResultConflict
+}
+
+// IsError returns whether the operation failed for any reason other than a
+// check-and-set conflict.
+func (r *VarApplyStateResponse) IsError() bool {
+	return r.Result == VarOpResultError
+}
+
+// VariablesListRequest is used to list the variables within a namespace,
+// optionally filtered by path prefix.
+type VariablesListRequest struct {
+	QueryOptions
+}
+
+// VariablesListResponse is the response to a VariablesListRequest. Only the
+// metadata of each variable is returned.
+type VariablesListResponse struct {
+	Data []*VariableMetadata
+	QueryMeta
+}
+
+// VariablesReadRequest is used to read a single variable by its path.
+type VariablesReadRequest struct {
+	Path string
+	QueryOptions
+}
+
+// VariablesReadResponse is the response to a VariablesReadRequest. Data is
+// nil when the variable does not exist.
+type VariablesReadResponse struct {
+	Data *VariableDecrypted
+	QueryMeta
+}
@@ -0,0 +1,64 @@
+package structs
+
+import (
+	"strings"
+	"testing"
+
+	"github.com/stretchr/testify/require"
+)
+
+func TestVariableDecrypted_Validate(t *testing.T) {
+	t.Parallel()
+
+	testCases := []struct {
+		name          string
+		path          string
+		namespace     string
+		items         VariableItems
+		expectedError string
+	}{
+		{name: "valid", path: "foo/bar", items: VariableItems{"k": "v"}},
+		{name: "valid job path", path: "nomad/jobs/example", items: VariableItems{"k": "v"}},
+		{name: "missing path", path: "", items: VariableItems{"k": "v"}, expectedError: "variable requires path"},
+		{name: "invalid characters", path: "foo/b@r", items: VariableItems{"k": "v"}, expectedError: "invalid path"},
+		{name: "empty segment", path: "foo//bar", items: VariableItems{"k": "v"}, expectedError: "path segments must not be empty"},
+		{name: "reserved prefix", path: "nomad", items: VariableItems{"k": "v"}, expectedError: "reserved top-level directory"},
+		{name: "reserved subpath", path: "nomad/foo", items: VariableItems{"k": "v"}, expectedError: "only paths at"},
+		{name: "no items", path: "foo", expectedError: "empty variables are invalid"},
+		{name: "too large", path: "foo", items: VariableItems{"k": strings.Repeat("x", VariablesMaxSize)}, expectedError: "limited to"},
+		{name: "wildcard namespace", path: "foo", namespace: AllNamespacesSentinel, items: VariableItems{"k": "v"}, expectedError: "can not target wildcard"},
+	}
+
+	for _, tc := range testCases {
+		t.Run(tc.name, func(t *testing.T) {
+			v := VariableDecrypted{
+				VariableMetadata: VariableMetadata{
+					Namespace: tc.namespace,
+					Path:      tc.path,
+				},
+				Items: tc.items,
+			}
+			err := v.Validate()
+			if tc.expectedError == "" {
+				require.NoError(t, err)
+			} else {
+				require.Error(t, err)
+				require.Contains(t, err.Error(), tc.expectedError)
+			}
+		})
+	}
+}
+
+func TestVariableEncrypted_Copy(t *testing.T) {
+	t.Parallel()
+
+	v := VariableEncrypted{
+		VariableMetadata: VariableMetadata{Namespace: "default", Path: "foo"},
+		VariableData:     VariableData{Data: []byte("ciphertext"), KeyID: "key"},
+	}
+	c := v.Copy()
+	require.True(t, v.Equals(c))
+
+	c.Data[0] = 'x'
+	require.False(t, v.Equals(c))
+}
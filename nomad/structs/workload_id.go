@@ -0,0 +1,91 @@
+package structs
+
+import (
+	"time"
+
+	"github.com/hashicorp/nomad/helper/uuid"
+	"gopkg.in/square/go-jose.v2/jwt"
+)
+
+const (
+	// WorkloadIdentityFile is the name of the file in the task's secrets
+	// directory to which the workload identity is written.
+	WorkloadIdentityFile = "nomad_token"
+
+	// WorkloadIdentityTTL is how long a signed workload identity is valid
+	// for. Clients renew the identities of running tasks before they
+	// expire.
+	WorkloadIdentityTTL = 24 * time.Hour
+)
+
+// WorkloadIdentity is the jobspec block which determines if and how a
+// workload identity is exposed to a task. Every task is given an identity
+// which the client uses on its behalf; this block only controls whether the
+// task itself can read it.
+type WorkloadIdentity struct {
+	// Env injects the workload identity into the task's environment as
+	// NOMAD_TOKEN if set. The environment is only updated with a renewed
+	// identity when the task is restarted.
+	Env bool
+
+	// File writes the workload identity into the task's secrets directory
+	// if set. The file is rewritten whenever the identity is renewed.
+	File bool
+}
+
+// Copy returns a copy of the workload identity block.
+func (wi *WorkloadIdentity) Copy() *WorkloadIdentity {
+	if wi == nil {
+		return nil
+	}
+	return &WorkloadIdentity{
+		Env:  wi.Env,
+		File: wi.File,
+	}
+}
+
+// IdentityClaims are the claims of the JWT which identifies a single task
+// of an allocation. They are signed by the servers with the active root key.
+type IdentityClaims struct {
+	Namespace    string `json:"nomad_namespace"`
+	JobID        string `json:"nomad_job_id"`
+	AllocationID string `json:"nomad_allocation_id"`
+	TaskName     string `json:"nomad_task"`
+
+	jwt.Claims
+}
+
+// NewIdentityClaims returns the claims identifying the given task of the
+// allocation, valid from now until WorkloadIdentityTTL has elapsed.
+func NewIdentityClaims(alloc *Allocation, taskName string, now time.Time) *IdentityClaims {
+	return &IdentityClaims{
+		Namespace:    alloc.Namespace,
+		JobID:        alloc.JobID,
+		AllocationID: alloc.ID,
+		TaskName:     taskName,
+		Claims: jwt.Claims{
+			ID:        uuid.Generate(),
+			Subject:   alloc.Namespace + ":" + alloc.JobID + ":" + alloc.ID + ":" + taskName,
+			IssuedAt:  jwt.NewNumericDate(now),
+			NotBefore: jwt.NewNumericDate(now),
+			Expiry:    jwt.NewNumericDate(now.Add(WorkloadIdentityTTL)),
+		},
+	}
+}
+
+// SignIdentitiesRequest is used by clients to request freshly signed workload
+// identities for the given tasks of an allocation running on the node.
+type SignIdentitiesRequest struct {
+	NodeID   string
+	SecretID string
+	AllocID  string
+	Tasks    []string
+	QueryOptions
+}
+
+// SignIdentitiesResponse returns the signed workload identities keyed by task
+// name.
+type SignIdentitiesResponse struct {
+	SignedIdentities map[string]string
+	QueryMeta
+}
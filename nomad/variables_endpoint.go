@@ -0,0 +1,434 @@
+package nomad
+
+import (
+	"encoding/json"
+	"fmt"
+	"strings"
+	"time"
+
+	metrics "github.com/armon/go-metrics"
+	log "github.com/hashicorp/go-hclog"
+	memdb "github.com/hashicorp/go-memdb"
+
+	"github.com/hashicorp/nomad/acl"
+	"github.com/hashicorp/nomad/helper"
+	"github.com/hashicorp/nomad/nomad/state"
+	"github.com/hashicorp/nomad/nomad/structs"
+)
+
+// Variables encapsulates the variables RPC endpoint which is callable via the
+// Variables RPCs and externally via the "/v1/var{s}" HTTP API.
+type Variables struct {
+	srv       *Server
+	logger    log.Logger
+	encrypter *Encrypter
+}
+
+// Apply is used to apply a variable update request to the data store. The
+// items are encrypted before being written to Raft, and the response
+// contains the cleartext of the written or conflicting variable.
+func (sv *Variables) Apply(args *structs.VariablesApplyRequest, reply *structs.VariablesApplyResponse) error {
+	if done, err := sv.srv.forward("Variables.Apply", args, args, reply); done {
+		return err
+	}
+	defer metrics.MeasureSinceWithLabels([]string{"nomad", "variables", "apply"}, time.Now(),
+		[]metrics.Label{{Name: "op", Value: string(args.Op)}})
+
+	if args.Var == nil {
+		return fmt.Errorf("missing variable in request")
+	}
+
+	// Check if the Namespace is explicitly set on the variable. If not, use
+	// the RequestNamespace
+	if args.Var.Namespace == "" {
+		args.Var.Namespace = args.RequestNamespace()
+	}
+
+	canRead, err := sv.hasPermission(args.AuthToken, args.Var.Namespace, args.Var.Path,
+		acl.NamespaceCapabilityVariablesWrite)
+	if err != nil {
+		return err
+	}
+
+	switch args.Op {
+	case structs.VarOpSet, structs.VarOpCAS:
+		args.Var.Canonicalize()
+		if err := args.Var.Validate(); err != nil {
+			return err
+		}
+	case structs.VarOpDelete, structs.VarOpDeleteCAS:
+		if args.Var.Path == "" {
+			return fmt.Errorf("delete requires a Path")
+		}
+	default:
+		return fmt.Errorf("invalid variable operation %q", args.Op)
+	}
+
+	ev, err := sv.encrypt(args.Var)
+	if err != nil {
+		return fmt.Errorf("variable error: encrypt: %w", err)
+	}
+
+	// Apply the update.
+	sr := &structs.VarApplyStateRequest{
+		Op:           args.Op,
+		Var:          ev,
+		WriteRequest: args.WriteRequest,
+	}
+	out, index, err := sv.srv.raftApply(structs.VarApplyStateRequestType, sr)
+	if err != nil {
+		return fmt.Errorf("raft apply failed: %w", err)
+	}
+	if err, ok := out.(error); ok && err != nil {
+		return err
+	}
+
+	r, ok := out.(*structs.VarApplyStateResponse)
+	if !ok {
+		return fmt.Errorf("unexpected response type %T from raft apply", out)
+	}
+	if r.IsError() {
+		return r.Error
+	}
+
+	input := args.Var.Copy()
+	reply.Op = args.Op
+	reply.Input = &input
+	reply.Index = index
+
+	switch {
+	case r.IsConflict():
+		reply.Result = structs.VarOpResultConflict
+		if !canRead {
+			// The caller cannot read the variable, so only return the
+			// metadata of the conflicting value.
+			reply.Result = structs.VarOpResultRedacted
+			reply.Conflict = &structs.VariableDecrypted{
+				VariableMetadata: r.Conflict.VariableMetadata,
+			}
+			return nil
+		}
+		if r.Conflict.KeyID == "" {
+			// The conflicting variable does not exist, so there is nothing
+			// to decrypt.
+			reply.Conflict = &structs.VariableDecrypted{
+				VariableMetadata: r.Conflict.VariableMetadata,
+			}
+			return nil
+		}
+		conflict, err := sv.decrypt(r.Conflict)
+		if err != nil {
+			return fmt.Errorf("variable error: decrypt: %w", err)
+		}
+		reply.Conflict = conflict
+
+	case r.IsOk():
+		reply.Result = structs.VarOpResultOk
+		if r.WrittenSVMeta != nil {
+			reply.Output = &structs.VariableDecrypted{
+				VariableMetadata: *r.WrittenSVMeta,
+				Items:            args.Var.Items.Copy(),
+			}
+		}
+	}
+
+	return nil
+}
+
+// Read is used to get a specific variable.
+func (sv *Variables) Read(args *structs.VariablesReadRequest, reply *structs.VariablesReadResponse) error {
+	if done, err := sv.srv.forward("Variables.Read", args, args, reply); done {
+		return err
+	}
+	defer metrics.MeasureSince([]string{"nomad", "variables", "read"}, time.Now())
+
+	if _, err := sv.hasPermission(args.AuthToken, args.RequestNamespace(), args.Path,
+		acl.NamespaceCapabilityVariablesRead); err != nil {
+		return err
+	}
+
+	// Setup the blocking query
+	return sv.srv.blockingRPC(&blockingOptions{
+		queryOpts: &args.QueryOptions,
+		queryMeta: &reply.QueryMeta,
+		run: func(ws memdb.WatchSet, s *state.StateStore) error {
+			out, err := s.GetVariable(ws, args.RequestNamespace(), args.Path)
+			if err != nil {
+				return err
+			}
+
+			// Setup the output
+			reply.Data = nil
+			if out != nil {
+				dv, err := sv.decrypt(out)
+				if err != nil {
+					return err
+				}
+				reply.Data = dv
+			}
+
+			return sv.setReplyQueryMeta(s, &reply.QueryMeta)
+		},
+	})
+}
+
+// List is used to list variables held within state. It supports single and
+// wildcard namespace listings, optionally filtered by a path prefix. Only
+// the metadata of each variable is returned.
+func (sv *Variables) List(args *structs.VariablesListRequest, reply *structs.VariablesListResponse) error {
+	if done, err := sv.srv.forward("Variables.List", args, args, reply); done {
+		return err
+	}
+	defer metrics.MeasureSince([]string{"nomad", "variables", "list"}, time.Now())
+
+	// If the caller has requested to list variables across all namespaces,
+	// use the custom function to perform this.
+	if args.RequestNamespace() == structs.AllNamespacesSentinel {
+		return sv.listAllVariables(args, reply)
+	}
+
+	if aclObj, err := sv.srv.ResolveToken(args.AuthToken); err != nil {
+		return err
+	} else if aclObj != nil && !aclObj.AllowNsOp(args.RequestNamespace(), acl.NamespaceCapabilityVariablesRead) {
+		return structs.ErrPermissionDenied
+	}
+
+	// Set up and return the blocking query.
+	return sv.srv.blockingRPC(&blockingOptions{
+		queryOpts: &args.QueryOptions,
+		queryMeta: &reply.QueryMeta,
+		run: func(ws memdb.WatchSet, stateStore *state.StateStore) error {
+
+			// Perform the state query to get an iterator.
+			iter, err := stateStore.GetVariablesByNamespaceAndPrefix(ws, args.RequestNamespace(), args.Prefix)
+			if err != nil {
+				return err
+			}
+
+			// Set up our output after we have checked the error.
+			svs := make([]*structs.VariableMetadata, 0)
+
+			for raw := iter.Next(); raw != nil; raw = iter.Next() {
+				v := raw.(*structs.VariableEncrypted)
+				meta := v.VariableMetadata
+				svs = append(svs, &meta)
+			}
+			reply.Data = svs
+
+			return sv.setReplyQueryMeta(stateStore, &reply.QueryMeta)
+		},
+	})
+}
+
+// listAllVariables is used to list variables held within state where the
+// caller has used the namespace wildcard identifier.
+func (sv *Variables) listAllVariables(args *structs.VariablesListRequest, reply *structs.VariablesListResponse) error {
+
+	// Perform token resolution. The request already goes through forwarding
+	// and metrics setup before being called.
+	aclObj, err := sv.srv.ResolveToken(args.AuthToken)
+	if err != nil {
+		return err
+	}
+
+	// allowFunc checks whether the caller has the variables-read capability
+	// on the passed namespace.
+	allowFunc := func(ns string) bool {
+		return aclObj.AllowNsOp(ns, acl.NamespaceCapabilityVariablesRead)
+	}
+
+	// Set up and return the blocking query.
+	return sv.srv.blockingRPC(&blockingOptions{
+		queryOpts: &args.QueryOptions,
+		queryMeta: &reply.QueryMeta,
+		run: func(ws memdb.WatchSet, stateStore *state.StateStore) error {
+
+			// Identify which namespaces the caller has access to. If they do
+			// not have access to any, send them an empty response. Otherwise,
+			// handle any error in a traditional manner.
+			allowedNSes, err := allowedNSes(aclObj, stateStore, allowFunc)
+			switch err {
+			case structs.ErrPermissionDenied:
+				reply.Data = make([]*structs.VariableMetadata, 0)
+				return nil
+			case nil:
+				// Fallthrough.
+			default:
+				return err
+			}
+
+			// Get all the variables stored within state.
+			iter, err := stateStore.Variables(ws)
+			if err != nil {
+				return err
+			}
+
+			svs := make([]*structs.VariableMetadata, 0)
+			for raw := iter.Next(); raw != nil; raw = iter.Next() {
+				v := raw.(*structs.VariableEncrypted)
+
+				// Check whether the variable is within a namespace the caller
+				// is permitted to view. nil allowedNSes means the caller can
+				// view all namespaces.
+				if allowedNSes != nil && !allowedNSes[v.Namespace] {
+					continue
+				}
+				if !strings.HasPrefix(v.Path, args.Prefix) {
+					continue
+				}
+				meta := v.VariableMetadata
+				svs = append(svs, &meta)
+			}
+			reply.Data = svs
+
+			return sv.setReplyQueryMeta(stateStore, &reply.QueryMeta)
+		},
+	})
+}
+
+// hasPermission checks whether the caller is allowed to perform the
+// operation on the variable at the given namespace and path. ACL tokens are
+// checked for the passed capability. Nodes, which authenticate with their
+// secret ID, may only read the variables of jobs which have allocations
+// running on them, and workload identities only those of their own job. The
+// returned boolean indicates whether the caller may
+// read the variable, which is used to redact check-and-set conflicts.
+func (sv *Variables) hasPermission(token, ns, path, capability string) (bool, error) {
+	if sv.srv.config.ACLEnabled {
+		claims, err := sv.srv.ResolveClaims(token)
+		if err != nil {
+			return false, err
+		}
+		if claims != nil {
+			if capability != acl.NamespaceCapabilityVariablesRead ||
+				claims.Namespace != ns || jobIDForVariablePath(path) != claims.JobID {
+				return false, structs.ErrPermissionDenied
+			}
+			return true, nil
+		}
+	}
+
+	aclObj, err := sv.srv.ResolveToken(token)
+	if err == nil {
+		if aclObj == nil {
+			// ACLs are disabled.
+			return true, nil
+		}
+		if !aclObj.AllowNsOp(ns, capability) {
+			return false, structs.ErrPermissionDenied
+		}
+		return aclObj.AllowNsOp(ns, acl.NamespaceCapabilityVariablesRead), nil
+	}
+
+	// If ResolveToken had an unexpected error return that
+	if err != structs.ErrTokenNotFound {
+		return false, err
+	}
+
+	// Nodes may only read variables; they never hold write permission.
+	if capability != acl.NamespaceCapabilityVariablesRead {
+		return false, structs.ErrPermissionDenied
+	}
+
+	node, stateErr := sv.srv.fsm.State().NodeBySecretID(nil, token)
+	if stateErr != nil {
+		return false, stateErr
+	}
+	if node == nil {
+		return false, structs.ErrTokenNotFound
+	}
+
+	if ok, err := sv.nodeCanReadPath(node, ns, path); err != nil {
+		return false, err
+	} else if !ok {
+		return false, structs.ErrPermissionDenied
+	}
+	return true, nil
+}
+
+// nodeCanReadPath returns whether the node is running a non-terminal
+// allocation of the job which owns the variable at the given path. Jobs own
+// the variables at "nomad/jobs/<job ID>" and below.
+func (sv *Variables) nodeCanReadPath(node *structs.Node, ns, path string) (bool, error) {
+	jobID := jobIDForVariablePath(path)
+	if jobID == "" {
+		return false, nil
+	}
+
+	allocs, err := sv.srv.fsm.State().AllocsByNode(nil, node.ID)
+	if err != nil {
+		return false, err
+	}
+	for _, alloc := range allocs {
+		if alloc.Namespace == ns && alloc.JobID == jobID && !alloc.TerminalStatus() {
+			return true, nil
+		}
+	}
+	return false, nil
+}
+
+// jobIDForVariablePath returns the ID of the job which owns the variable at
+// the given path, or the empty string if the path is not owned by a job.
+func jobIDForVariablePath(path string) string {
+	prefix := structs.VariablesJobPathPrefix + "/"
+	if !strings.HasPrefix(path, prefix) {
+		return ""
+	}
+	return strings.SplitN(strings.TrimPrefix(path, prefix), "/", 2)[0]
+}
+
+// encrypt encrypts the items of the variable with the active root key.
+func (sv *Variables) encrypt(v *structs.VariableDecrypted) (*structs.VariableEncrypted, error) {
+	ev := &structs.VariableEncrypted{
+		VariableMetadata: v.VariableMetadata,
+	}
+
+	// Deletes do not carry any items, so there is nothing to encrypt.
+	if len(v.Items) == 0 {
+		return ev, nil
+	}
+
+	buf, err := json.Marshal(v.Items)
+	if err != nil {
+		return nil, err
+	}
+	ev.Data, ev.KeyID, err = sv.encrypter.Encrypt(buf)
+	if err != nil {
+		return nil, err
+	}
+	return ev, nil
+}
+
+// decrypt decrypts the data of the variable with the root key it was
+// encrypted with.
+func (sv *Variables) decrypt(v *structs.VariableEncrypted) (*structs.VariableDecrypted, error) {
+	buf, err := sv.encrypter.Decrypt(v.Data, v.KeyID)
+	if err != nil {
+		return nil, err
+	}
+
+	dv := &structs.VariableDecrypted{
+		VariableMetadata: v.VariableMetadata,
+	}
+	if err := json.Unmarshal(buf, &dv.Items); err != nil {
+		return nil, err
+	}
+	return dv, nil
+}
+
+// setReplyQueryMeta is an RPC helper function to set the QueryMeta fields
+// using the variables table index.
+func (sv *Variables) setReplyQueryMeta(stateStore *state.StateStore, queryMeta *structs.QueryMeta) error {
+
+	// Use the index table to populate the query meta as we have no way of
+	// tracking the max index on deletes.
+	index, err := stateStore.Index(state.TableVariables)
+	if err != nil {
+		return err
+	}
+	queryMeta.Index = helper.Uint64Max(1, index)
+
+	// Set the query response.
+	sv.srv.setQueryMeta(queryMeta)
+	return nil
+}
@@ -0,0 +1,260 @@
+package nomad
+
+import (
+	"testing"
+	"time"
+
+	msgpackrpc "github.com/hashicorp/net-rpc-msgpackrpc"
+	"github.com/hashicorp/nomad/acl"
+	"github.com/hashicorp/nomad/nomad/mock"
+	"github.com/hashicorp/nomad/nomad/structs"
+	"github.com/hashicorp/nomad/testutil"
+	"github.com/stretchr/testify/require"
+)
+
+func TestVariablesEndpoint_Apply(t *testing.T) {
+	t.Parallel()
+	s1, rootToken, cleanupS1 := TestACLServer(t, nil)
+	defer cleanupS1()
+	codec := rpcClient(t, s1)
+	testutil.WaitForLeader(t, s1.RPC)
+	waitForKeyring(t, s1)
+
+	sv := &structs.VariableDecrypted{
+		VariableMetadata: structs.VariableMetadata{
+			Namespace: structs.DefaultNamespace,
+			Path:      "foo/bar",
+		},
+		Items: structs.VariableItems{"username": "admin"},
+	}
+
+	// Attempt the write with a token that can only read variables.
+	policy := mock.NamespacePolicy(structs.DefaultNamespace, "", []string{acl.NamespaceCapabilityVariablesRead})
+	readToken := mock.CreatePolicyAndToken(t, s1.fsm.State(), 10, "test-var-read", policy)
+
+	applyReq := &structs.VariablesApplyRequest{
+		Op:  structs.VarOpSet,
+		Var: sv,
+		WriteRequest: structs.WriteRequest{
+			Region:    DefaultRegion,
+			AuthToken: readToken.SecretID,
+		},
+	}
+	var applyResp structs.VariablesApplyResponse
+	err := msgpackrpc.CallWithCodec(codec, "Variables.Apply", applyReq, &applyResp)
+	require.EqualError(t, err, structs.ErrPermissionDenied.Error())
+
+	// Write the variable using the root token.
+	applyReq.AuthToken = rootToken.SecretID
+	require.NoError(t, msgpackrpc.CallWithCodec(codec, "Variables.Apply", applyReq, &applyResp))
+	require.True(t, applyResp.IsOk())
+	require.NotNil(t, applyResp.Output)
+	require.Equal(t, sv.Items, applyResp.Output.Items)
+	writeIndex := applyResp.Output.ModifyIndex
+
+	// The variable must be stored encrypted.
+	stored, err := s1.fsm.State().GetVariable(nil, structs.DefaultNamespace, "foo/bar")
+	require.NoError(t, err)
+	require.NotNil(t, stored)
+	require.NotEmpty(t, stored.KeyID)
+	require.NotContains(t, string(stored.Data), "admin")
+
+	// A check-and-set with a stale index returns the conflicting variable.
+	casVar := sv.Copy()
+	casVar.Items = structs.VariableItems{"username": "root"}
+	casVar.ModifyIndex = writeIndex - 1
+	casReq := &structs.VariablesApplyRequest{
+		Op:  structs.VarOpCAS,
+		Var: &casVar,
+		WriteRequest: structs.WriteRequest{
+			Region:    DefaultRegion,
+			AuthToken: rootToken.SecretID,
+		},
+	}
+	var casResp structs.VariablesApplyResponse
+	require.NoError(t, msgpackrpc.CallWithCodec(codec, "Variables.Apply", casReq, &casResp))
+	require.True(t, casResp.IsConflict())
+	require.False(t, casResp.IsRedacted())
+	require.Equal(t, writeIndex, casResp.Conflict.ModifyIndex)
+	require.Equal(t, "admin", casResp.Conflict.Items["username"])
+
+	// A check-and-set with the current index succeeds.
+	casVar.ModifyIndex = writeIndex
+	require.NoError(t, msgpackrpc.CallWithCodec(codec, "Variables.Apply", casReq, &casResp))
+	require.True(t, casResp.IsOk())
+
+	// Delete the variable.
+	deleteReq := &structs.VariablesApplyRequest{
+		Op: structs.VarOpDelete,
+		Var: &structs.VariableDecrypted{
+			VariableMetadata: structs.VariableMetadata{Path: "foo/bar"},
+		},
+		WriteRequest: structs.WriteRequest{
+			Region:    DefaultRegion,
+			Namespace: structs.DefaultNamespace,
+			AuthToken: rootToken.SecretID,
+		},
+	}
+	var deleteResp structs.VariablesApplyResponse
+	require.NoError(t, msgpackrpc.CallWithCodec(codec, "Variables.Apply", deleteReq, &deleteResp))
+	require.True(t, deleteResp.IsOk())
+
+	stored, err = s1.fsm.State().GetVariable(nil, structs.DefaultNamespace, "foo/bar")
+	require.NoError(t, err)
+	require.Nil(t, stored)
+}
+
+func TestVariablesEndpoint_Read(t *testing.T) {
+	t.Parallel()
+	s1, rootToken, cleanupS1 := TestACLServer(t, nil)
+	defer cleanupS1()
+	codec := rpcClient(t, s1)
+	testutil.WaitForLeader(t, s1.RPC)
+	waitForKeyring(t, s1)
+
+	// Write a variable owned by a job, and one which is not.
+	for _, path := range []string{"nomad/jobs/example", "secret/db"} {
+		req := &structs.VariablesApplyRequest{
+			Op: structs.VarOpSet,
+			Var: &structs.VariableDecrypted{
+				VariableMetadata: structs.VariableMetadata{Path: path},
+				Items:            structs.VariableItems{"password": "hunter2"},
+			},
+			WriteRequest: structs.WriteRequest{
+				Region:    DefaultRegion,
+				Namespace: structs.DefaultNamespace,
+				AuthToken: rootToken.SecretID,
+			},
+		}
+		var resp structs.VariablesApplyResponse
+		require.NoError(t, msgpackrpc.CallWithCodec(codec, "Variables.Apply", req, &resp))
+	}
+
+	// Reading with a token with the variables-read capability succeeds.
+	policy := mock.NamespacePolicy(structs.DefaultNamespace, "", []string{acl.NamespaceCapabilityVariablesRead})
+	readToken := mock.CreatePolicyAndToken(t, s1.fsm.State(), 1000, "test-var-read", policy)
+
+	readReq := &structs.VariablesReadRequest{
+		Path: "secret/db",
+		QueryOptions: structs.QueryOptions{
+			Region:    DefaultRegion,
+			Namespace: structs.DefaultNamespace,
+			AuthToken: readToken.SecretID,
+		},
+	}
+	var readResp structs.VariablesReadResponse
+	require.NoError(t, msgpackrpc.CallWithCodec(codec, "Variables.Read", readReq, &readResp))
+	require.NotNil(t, readResp.Data)
+	require.Equal(t, "hunter2", readResp.Data.Items["password"])
+
+	// Reading a missing variable returns no data.
+	readReq.Path = "secret/missing"
+	var missingResp structs.VariablesReadResponse
+	require.NoError(t, msgpackrpc.CallWithCodec(codec, "Variables.Read", readReq, &missingResp))
+	require.Nil(t, missingResp.Data)
+
+	// A node can only read the variables of jobs with allocations on it.
+	node := mock.Node()
+	require.NoError(t, s1.fsm.State().UpsertNode(structs.MsgTypeTestSetup, 1010, node))
+
+	readReq.AuthToken = node.SecretID
+	readReq.Path = "nomad/jobs/example"
+	err := msgpackrpc.CallWithCodec(codec, "Variables.Read", readReq, &readResp)
+	require.EqualError(t, err, structs.ErrPermissionDenied.Error())
+
+	alloc := mock.Alloc()
+	alloc.NodeID = node.ID
+	alloc.JobID = "example"
+	alloc.Job.ID = "example"
+	require.NoError(t, s1.fsm.State().UpsertJobSummary(1020, mock.JobSummary(alloc.JobID)))
+	require.NoError(t, s1.fsm.State().UpsertAllocs(structs.MsgTypeTestSetup, 1030, []*structs.Allocation{alloc}))
+
+	require.NoError(t, msgpackrpc.CallWithCodec(codec, "Variables.Read", readReq, &readResp))
+	require.NotNil(t, readResp.Data)
+	require.Equal(t, "hunter2", readResp.Data.Items["password"])
+
+	readReq.Path = "secret/db"
+	err = msgpackrpc.CallWithCodec(codec, "Variables.Read", readReq, &readResp)
+	require.EqualError(t, err, structs.ErrPermissionDenied.Error())
+
+	// The workload identity of a task can only read the variables of its
+	// own job.
+	identity, err := s1.encrypter.SignClaims(structs.NewIdentityClaims(alloc, "web", time.Now()))
+	require.NoError(t, err)
+
+	readReq.AuthToken = identity
+	readReq.Path = "nomad/jobs/example"
+	var identityResp structs.VariablesReadResponse
+	require.NoError(t, msgpackrpc.CallWithCodec(codec, "Variables.Read", readReq, &identityResp))
+	require.NotNil(t, identityResp.Data)
+	require.Equal(t, "hunter2", identityResp.Data.Items["password"])
+
+	readReq.Path = "secret/db"
+	err = msgpackrpc.CallWithCodec(codec, "Variables.Read", readReq, &identityResp)
+	require.EqualError(t, err, structs.ErrPermissionDenied.Error())
+}
+
+func TestVariablesEndpoint_List(t *testing.T) {
+	t.Parallel()
+	s1, rootToken, cleanupS1 := TestACLServer(t, nil)
+	defer cleanupS1()
+	codec := rpcClient(t, s1)
+	testutil.WaitForLeader(t, s1.RPC)
+	waitForKeyring(t, s1)
+
+	ns := mock.Namespace()
+	require.NoError(t, s1.fsm.State().UpsertNamespaces(10, []*structs.Namespace{ns}))
+
+	for _, v := range []struct{ namespace, path string }{
+		{structs.DefaultNamespace, "a/one"},
+		{structs.DefaultNamespace, "a/two"},
+		{structs.DefaultNamespace, "b/one"},
+		{ns.Name, "a/one"},
+	} {
+		req := &structs.VariablesApplyRequest{
+			Op: structs.VarOpSet,
+			Var: &structs.VariableDecrypted{
+				VariableMetadata: structs.VariableMetadata{Namespace: v.namespace, Path: v.path},
+				Items:            structs.VariableItems{"k": "v"},
+			},
+			WriteRequest: structs.WriteRequest{
+				Region:    DefaultRegion,
+				AuthToken: rootToken.SecretID,
+			},
+		}
+		var resp structs.VariablesApplyResponse
+		require.NoError(t, msgpackrpc.CallWithCodec(codec, "Variables.Apply", req, &resp))
+	}
+
+	listReq := &structs.VariablesListRequest{
+		QueryOptions: structs.QueryOptions{
+			Region:    DefaultRegion,
+			Namespace: structs.DefaultNamespace,
+			Prefix:    "a/",
+			AuthToken: rootToken.SecretID,
+		},
+	}
+	var listResp structs.VariablesListResponse
+	require.NoError(t, msgpackrpc.CallWithCodec(codec, "Variables.List", listReq, &listResp))
+	require.Len(t, listResp.Data, 2)
+	require.Greater(t, listResp.Index, uint64(10))
+
+	// A token which can only read the default namespace only sees those
+	// variables when listing all namespaces.
+	policy := mock.NamespacePolicy(structs.DefaultNamespace, "", []string{acl.NamespaceCapabilityVariablesRead})
+	readToken := mock.CreatePolicyAndToken(t, s1.fsm.State(), 1000, "test-var-read", policy)
+
+	listReq.Namespace = structs.AllNamespacesSentinel
+	listReq.Prefix = ""
+	listReq.AuthToken = readToken.SecretID
+	require.NoError(t, msgpackrpc.CallWithCodec(codec, "Variables.List", listReq, &listResp))
+	require.Len(t, listResp.Data, 3)
+	for _, v := range listResp.Data {
+		require.Equal(t, structs.DefaultNamespace, v.Namespace)
+	}
+
+	// Listing a namespace the token cannot read is denied.
+	listReq.Namespace = ns.Name
+	err := msgpackrpc.CallWithCodec(codec, "Variables.List", listReq, &listResp)
+	require.EqualError(t, err, structs.ErrPermissionDenied.Error())
+}
@@ -0,0 +1,223 @@
+package nomad
+
+import (
+	"context"
+	"sync"
+	"time"
+
+	log "github.com/hashicorp/go-hclog"
+	memdb "github.com/hashicorp/go-memdb"
+	"github.com/hashicorp/nomad/nomad/state"
+	"github.com/hashicorp/nomad/nomad/structs"
+)
+
+const (
+	// waitingEvalsRetryInterval is the interval after which releasing
+	// evaluations is retried if it failed.
+	waitingEvalsRetryInterval = 5 * time.Second
+)
+
+// WaitingEvals is used to track evaluations waiting on the dependencies of
+// their job. An evaluation is put into the waiting state by the scheduler when
+// the job it evaluates has unmet dependencies. It is released by updating it
+// to pending once the dependencies are met, or once the job is stopped or
+// purged so the scheduler can handle it.
+type WaitingEvals struct {
+	logger log.Logger
+
+	// apply is used to release evaluations via Raft
+	apply raftApplyFn
+
+	enabled bool
+	l       sync.Mutex
+
+	// evals is the set of waiting evaluations by ID
+	evals map[string]*structs.Evaluation
+
+	// updateCh is used to signal that an evaluation was tracked
+	updateCh chan struct{}
+
+	// stopCh is used to stop the watcher goroutine
+	stopCh chan struct{}
+}
+
+// NewWaitingEvals creates a new waiting eval tracker that releases evaluations
+// via the passed Raft apply function.
+func NewWaitingEvals(apply raftApplyFn, logger log.Logger) *WaitingEvals {
+	return &WaitingEvals{
+		logger:   logger.Named("waiting_evals"),
+		apply:    apply,
+		evals:    make(map[string]*structs.Evaluation),
+		updateCh: make(chan struct{}, 1),
+	}
+}
+
+// Enabled is used to check if the tracker is enabled.
+func (w *WaitingEvals) Enabled() bool {
+	w.l.Lock()
+	defer w.l.Unlock()
+	return w.enabled
+}
+
+// SetEnabled is used to control if the waiting eval tracker is enabled. The
+// tracker should only be enabled on the active leader. The state store is
+// looked up through getState, so the tracker follows snapshot restores.
+func (w *WaitingEvals) SetEnabled(enabled bool, getState func() *state.StateStore) {
+	w.l.Lock()
+	defer w.l.Unlock()
+
+	if w.enabled == enabled {
+		return
+	}
+	w.enabled = enabled
+
+	if enabled {
+		w.stopCh = make(chan struct{})
+		go w.watch(w.stopCh, getState)
+	} else {
+		close(w.stopCh)
+		w.evals = make(map[string]*structs.Evaluation)
+	}
+}
+
+// Track is used to add a waiting evaluation to the tracker.
+func (w *WaitingEvals) Track(eval *structs.Evaluation) {
+	w.l.Lock()
+	defer w.l.Unlock()
+
+	if !w.enabled {
+		return
+	}
+	w.evals[eval.ID] = eval
+	w.notify()
+}
+
+// Tracked returns the number of tracked evaluations.
+func (w *WaitingEvals) Tracked() int {
+	w.l.Lock()
+	defer w.l.Unlock()
+	return len(w.evals)
+}
+
+// notify signals the watcher without blocking. The lock must be held.
+func (w *WaitingEvals) notify() {
+	select {
+	case w.updateCh <- struct{}{}:
+	default:
+	}
+}
+
+// watch releases the tracked evaluations whose dependencies are met. It
+// rechecks them whenever an evaluation is tracked, the state of the jobs they
+// depend on changes, or the state store is abandoned after a restore.
+func (w *WaitingEvals) watch(stopCh <-chan struct{}, getState func() *state.StateStore) {
+	ctx, cancel := context.WithCancel(context.Background())
+	defer cancel()
+	go func() {
+		<-stopCh
+		cancel()
+	}()
+
+	for {
+		state := getState()
+		ws := memdb.NewWatchSet()
+		ws.Add(state.AbandonCh())
+		w.release(ws, state)
+
+		// Wait for a change to any of the checked objects or for a newly
+		// tracked evaluation
+		watchCtx, watchCancel := context.WithCancel(ctx)
+		go func() {
+			select {
+			case <-w.updateCh:
+				watchCancel()
+			case <-watchCtx.Done():
+			}
+		}()
+		ws.WatchCtx(watchCtx)
+		watchCancel()
+
+		if ctx.Err() != nil {
+			return
+		}
+	}
+}
+
+// release updates the tracked evaluations that are ready to be processed to
+// pending. The objects checked are added to the watch set.
+func (w *WaitingEvals) release(ws memdb.WatchSet, state *state.StateStore) {
+	w.l.Lock()
+	evals := make([]*structs.Evaluation, 0, len(w.evals))
+	for _, eval := range w.evals {
+		evals = append(evals, eval)
+	}
+	w.l.Unlock()
+
+	var released []*structs.Evaluation
+	var untrack []string
+	for _, eval := range evals {
+		ready, tracked, err := w.ready(ws, state, eval)
+		if err != nil {
+			w.logger.Error("failed to check waiting evaluation", "eval_id", eval.ID, "error", err)
+			continue
+		}
+		if !tracked {
+			untrack = append(untrack, eval.ID)
+			continue
+		}
+		if ready {
+			newEval := eval.Copy()
+			newEval.Status = structs.EvalStatusPending
+			newEval.StatusDescription = ""
+			newEval.UpdateModifyTime()
+			released = append(released, newEval)
+			untrack = append(untrack, eval.ID)
+		}
+	}
+
+	if len(released) != 0 {
+		req := &structs.EvalUpdateRequest{Evals: released}
+		if _, _, err := w.apply(structs.EvalUpdateRequestType, req); err != nil {
+			w.logger.Error("failed to release waiting evaluations", "error", err)
+			time.AfterFunc(waitingEvalsRetryInterval, func() {
+				w.l.Lock()
+				defer w.l.Unlock()
+				w.notify()
+			})
+			return
+		}
+		w.logger.Debug("released waiting evaluations", "count", len(released))
+	}
+
+	w.l.Lock()
+	for _, id := range untrack {
+		delete(w.evals, id)
+	}
+	w.l.Unlock()
+}
+
+// ready returns whether the evaluation should be released, and whether it is
+// still waiting at all.
+func (w *WaitingEvals) ready(ws memdb.WatchSet, state *state.StateStore, eval *structs.Evaluation) (bool, bool, error) {
+	existing, err := state.EvalByID(ws, eval.ID)
+	if err != nil {
+		return false, true, err
+	}
+	if existing == nil || existing.Status != structs.EvalStatusWaiting {
+		return false, false, nil
+	}
+
+	job, err := state.JobByID(ws, eval.Namespace, eval.JobID)
+	if err != nil {
+		return false, true, err
+	}
+	if job == nil || job.Stopped() {
+		return true, true, nil
+	}
+
+	met, _, err := state.JobDependenciesMet(ws, job)
+	if err != nil {
+		return false, true, err
+	}
+	return met, true, nil
+}
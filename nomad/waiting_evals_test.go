@@ -0,0 +1,211 @@
+package nomad
+
+import (
+	"fmt"
+	"sync"
+	"testing"
+
+	"github.com/hashicorp/nomad/helper/testlog"
+	"github.com/hashicorp/nomad/nomad/mock"
+	"github.com/hashicorp/nomad/nomad/state"
+	"github.com/hashicorp/nomad/nomad/structs"
+	"github.com/hashicorp/nomad/testutil"
+	"github.com/stretchr/testify/require"
+)
+
+// testWaitingEvals returns an enabled waiting eval tracker whose releases
+// are applied directly to the current state store. The state store can be
+// replaced with the returned function, as when restoring a snapshot.
+func testWaitingEvals(t *testing.T) (*WaitingEvals, func() *state.StateStore, func(*state.StateStore)) {
+	var l sync.Mutex
+	current := state.TestStateStore(t)
+	getState := func() *state.StateStore {
+		l.Lock()
+		defer l.Unlock()
+		return current
+	}
+	setState := func(s *state.StateStore) {
+		l.Lock()
+		old := current
+		current = s
+		l.Unlock()
+		old.Abandon()
+	}
+
+	apply := func(_ structs.MessageType, msg interface{}) (interface{}, uint64, error) {
+		req := msg.(*structs.EvalUpdateRequest)
+		s := getState()
+		index, err := s.LatestIndex()
+		if err != nil {
+			return nil, 0, err
+		}
+		return nil, index + 1, s.UpsertEvals(structs.MsgTypeTestSetup, index+1, req.Evals)
+	}
+
+	waiting := NewWaitingEvals(apply, testlog.HCLogger(t))
+	waiting.SetEnabled(true, getState)
+	t.Cleanup(func() { waiting.SetEnabled(false, nil) })
+	return waiting, getState, setState
+}
+
+func TestWaitingEvals_Track_Disabled(t *testing.T) {
+	t.Parallel()
+
+	waiting := NewWaitingEvals(nil, testlog.HCLogger(t))
+
+	eval := mock.Eval()
+	eval.Status = structs.EvalStatusWaiting
+	waiting.Track(eval)
+
+	require.Zero(t, waiting.Tracked())
+}
+
+func TestWaitingEvals_Release(t *testing.T) {
+	t.Parallel()
+
+	waiting, getState, _ := testWaitingEvals(t)
+	s := getState()
+
+	upstream := mock.BatchJob()
+	upstream.TaskGroups[0].Count = 1
+	require.NoError(t, s.UpsertJob(structs.MsgTypeTestSetup, 1000, upstream))
+
+	job := mock.BatchJob()
+	job.DependsOn = []*structs.JobDependency{{
+		JobID:     upstream.ID,
+		Condition: structs.JobDependencyConditionComplete,
+	}}
+	require.NoError(t, s.UpsertJob(structs.MsgTypeTestSetup, 1001, job))
+
+	eval := mock.Eval()
+	eval.Type = job.Type
+	eval.JobID = job.ID
+	eval.Status = structs.EvalStatusWaiting
+	require.NoError(t, s.UpsertEvals(structs.MsgTypeTestSetup, 1002, []*structs.Evaluation{eval}))
+	waiting.Track(eval)
+
+	// The eval keeps waiting while the upstream job is running
+	alloc := mock.Alloc()
+	alloc.Job = upstream
+	alloc.JobID = upstream.ID
+	alloc.TaskGroup = upstream.TaskGroups[0].Name
+	require.NoError(t, s.UpsertAllocs(structs.MsgTypeTestSetup, 1003, []*structs.Allocation{alloc}))
+
+	out, err := s.EvalByID(nil, eval.ID)
+	require.NoError(t, err)
+	require.Equal(t, structs.EvalStatusWaiting, out.Status)
+	require.Equal(t, 1, waiting.Tracked())
+
+	// Completing the upstream job releases the eval
+	update := alloc.Copy()
+	update.ClientStatus = structs.AllocClientStatusComplete
+	require.NoError(t, s.UpdateAllocsFromClient(structs.MsgTypeTestSetup, 1004, []*structs.Allocation{update}))
+
+	testutil.WaitForResult(func() (bool, error) {
+		out, err := s.EvalByID(nil, eval.ID)
+		if err != nil {
+			return false, err
+		}
+		if out.Status != structs.EvalStatusPending {
+			return false, fmt.Errorf("expected eval to be pending, got %q", out.Status)
+		}
+		if n := waiting.Tracked(); n != 0 {
+			return false, fmt.Errorf("expected no tracked evals, got %d", n)
+		}
+		return true, nil
+	}, func(err error) {
+		t.Fatal(err)
+	})
+}
+
+func TestWaitingEvals_Release_JobStopped(t *testing.T) {
+	t.Parallel()
+
+	waiting, getState, _ := testWaitingEvals(t)
+	s := getState()
+
+	job := mock.BatchJob()
+	job.DependsOn = []*structs.JobDependency{{
+		JobID:     "missing",
+		Condition: structs.JobDependencyConditionDead,
+	}}
+	require.NoError(t, s.UpsertJob(structs.MsgTypeTestSetup, 1000, job))
+
+	eval := mock.Eval()
+	eval.Type = job.Type
+	eval.JobID = job.ID
+	eval.Status = structs.EvalStatusWaiting
+	require.NoError(t, s.UpsertEvals(structs.MsgTypeTestSetup, 1001, []*structs.Evaluation{eval}))
+	waiting.Track(eval)
+
+	// Stopping the job releases the eval even though its dependency can
+	// never be met
+	stopped := job.Copy()
+	stopped.Stop = true
+	require.NoError(t, s.UpsertJob(structs.MsgTypeTestSetup, 1002, stopped))
+
+	testutil.WaitForResult(func() (bool, error) {
+		out, err := s.EvalByID(nil, eval.ID)
+		if err != nil {
+			return false, err
+		}
+		if out.Status != structs.EvalStatusPending {
+			return false, fmt.Errorf("expected eval to be pending, got %q", out.Status)
+		}
+		return true, nil
+	}, func(err error) {
+		t.Fatal(err)
+	})
+}
+
+func TestWaitingEvals_Release_StateRestored(t *testing.T) {
+	t.Parallel()
+
+	waiting, _, setState := testWaitingEvals(t)
+
+	// Restore a snapshot in which the eval waits on a running upstream job
+	s := state.TestStateStore(t)
+	upstream := mock.BatchJob()
+	upstream.TaskGroups[0].Count = 1
+	require.NoError(t, s.UpsertJob(structs.MsgTypeTestSetup, 1000, upstream))
+
+	job := mock.BatchJob()
+	job.DependsOn = []*structs.JobDependency{{
+		JobID:     upstream.ID,
+		Condition: structs.JobDependencyConditionComplete,
+	}}
+	require.NoError(t, s.UpsertJob(structs.MsgTypeTestSetup, 1001, job))
+
+	eval := mock.Eval()
+	eval.Type = job.Type
+	eval.JobID = job.ID
+	eval.Status = structs.EvalStatusWaiting
+	require.NoError(t, s.UpsertEvals(structs.MsgTypeTestSetup, 1002, []*structs.Evaluation{eval}))
+
+	alloc := mock.Alloc()
+	alloc.Job = upstream
+	alloc.JobID = upstream.ID
+	alloc.TaskGroup = upstream.TaskGroups[0].Name
+	require.NoError(t, s.UpsertAllocs(structs.MsgTypeTestSetup, 1003, []*structs.Allocation{alloc}))
+
+	setState(s)
+	waiting.Track(eval)
+
+	// Completing the upstream job in the restored state releases the eval
+	update := alloc.Copy()
+	update.ClientStatus = structs.AllocClientStatusComplete
+	require.NoError(t, s.UpdateAllocsFromClient(structs.MsgTypeTestSetup, 1004, []*structs.Allocation{update}))
+
+	testutil.WaitForResult(func() (bool, error) {
+		out, err := s.EvalByID(nil, eval.ID)
+		if err != nil {
+			return false, err
+		}
+		if out.Status != structs.EvalStatusPending {
+			return false, fmt.Errorf("expected eval to be pending, got %q", out.Status)
+		}
+		return true, nil
+	}, func(err error) {
+		t.Fatal(err)
+	})
+}
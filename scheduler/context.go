@@ -80,6 +80,9 @@ type EvalContext struct {
 	logger      log.Logger
 	metrics     *structs.AllocMetric
 	eligibility *EvalEligibility
+
+	// explain enables explanations in the metrics of every placement
+	explain bool
 }
 
 // NewEvalContext constructs a new EvalContext
@@ -115,6 +118,18 @@ func (e *EvalContext) SetState(s State) {
 
 func (e *EvalContext) Reset() {
 	e.metrics = new(structs.AllocMetric)
+	if e.explain {
+		e.metrics.EnableExplain()
+	}
+}
+
+// SetExplain sets whether the metrics of every placement record why each
+// node was or wasn't selected.
+func (e *EvalContext) SetExplain(explain bool) {
+	e.explain = explain
+	if explain {
+		e.metrics.EnableExplain()
+	}
 }
 
 func (e *EvalContext) ProposedAllocs(nodeID string) ([]*structs.Allocation, error) {
@@ -598,6 +598,100 @@ func (iter *DistinctHostsIterator) Reset() {
 	iter.source.Reset()
 }
 
+// AllocAffinityConstraintIterator is a FeasibleIterator which returns nodes
+// that pass the hard alloc_affinity stanzas of a job and task group. A hard
+// affinity with a positive weight requires a matching allocation of the
+// target job on the node, while a negative weight forbids one.
+type AllocAffinityConstraintIterator struct {
+	ctx        Context
+	source     FeasibleIterator
+	namespace  string
+	jobHard    []*structs.AllocAffinity
+	affinities []*structs.AllocAffinity
+}
+
+// NewAllocAffinityConstraintIterator creates an
+// AllocAffinityConstraintIterator from a source.
+func NewAllocAffinityConstraintIterator(ctx Context, source FeasibleIterator) *AllocAffinityConstraintIterator {
+	return &AllocAffinityConstraintIterator{
+		ctx:    ctx,
+		source: source,
+	}
+}
+
+func (iter *AllocAffinityConstraintIterator) SetJob(job *structs.Job) {
+	iter.namespace = job.Namespace
+	iter.jobHard = hardAllocAffinities(job.AllocAffinities)
+}
+
+func (iter *AllocAffinityConstraintIterator) SetTaskGroup(tg *structs.TaskGroup) {
+	iter.affinities = nil
+	iter.affinities = append(iter.affinities, iter.jobHard...)
+	iter.affinities = append(iter.affinities, hardAllocAffinities(tg.AllocAffinities)...)
+}
+
+// hardAllocAffinities returns the alloc affinities that are hard constraints.
+func hardAllocAffinities(affinities []*structs.AllocAffinity) []*structs.AllocAffinity {
+	var hard []*structs.AllocAffinity
+	for _, a := range affinities {
+		if a.Hard {
+			hard = append(hard, a)
+		}
+	}
+	return hard
+}
+
+func (iter *AllocAffinityConstraintIterator) Next() *structs.Node {
+	for {
+		// Get the next option from the source
+		option := iter.source.Next()
+
+		// Hot-path if the option is nil or there are no hard alloc affinities
+		if option == nil || len(iter.affinities) == 0 {
+			return option
+		}
+
+		proposed, err := iter.ctx.ProposedAllocs(option.ID)
+		if err != nil {
+			iter.ctx.Logger().Named("alloc_affinity").Error("failed to get proposed allocations", "error", err)
+			continue
+		}
+
+		if affinity := iter.unsatisfied(proposed); affinity != nil {
+			iter.ctx.Metrics().FilterNode(option, affinity.String())
+			continue
+		}
+
+		return option
+	}
+}
+
+// unsatisfied returns the first hard alloc affinity that is not satisfied by
+// the proposed allocations of a node, or nil if all are satisfied.
+func (iter *AllocAffinityConstraintIterator) unsatisfied(proposed []*structs.Allocation) *structs.AllocAffinity {
+	for _, affinity := range iter.affinities {
+		if matchesAllocAffinity(iter.namespace, affinity, proposed) != (affinity.Weight > 0) {
+			return affinity
+		}
+	}
+	return nil
+}
+
+func (iter *AllocAffinityConstraintIterator) Reset() {
+	iter.source.Reset()
+}
+
+// matchesAllocAffinity returns whether any of the allocations is matched by
+// the alloc affinity.
+func matchesAllocAffinity(namespace string, affinity *structs.AllocAffinity, allocs []*structs.Allocation) bool {
+	for _, alloc := range allocs {
+		if affinity.Matches(namespace, alloc) {
+			return true
+		}
+	}
+	return false
+}
+
 // DistinctPropertyIterator is a FeasibleIterator which returns nodes that pass the
 // distinct_property constraint. The constraint ensures that multiple allocations
 // do not use the same value of the given property.
@@ -703,6 +797,50 @@ func (iter *DistinctPropertyIterator) Reset() {
 	}
 }
 
+// NodePoolChecker is a FeasibilityChecker which returns nodes that belong to
+// the node pool of the job. Jobs in the built-in "all" node pool may be
+// placed on any node.
+type NodePoolChecker struct {
+	ctx  Context
+	pool string
+}
+
+// NewNodePoolChecker creates a NodePoolChecker. The pool is set later with
+// SetPool and defaults to the default node pool.
+func NewNodePoolChecker(ctx Context) *NodePoolChecker {
+	return &NodePoolChecker{
+		ctx:  ctx,
+		pool: structs.NodePoolDefault,
+	}
+}
+
+func (c *NodePoolChecker) SetPool(pool string) {
+	if pool == "" {
+		pool = structs.NodePoolDefault
+	}
+	c.pool = pool
+}
+
+func (c *NodePoolChecker) Feasible(option *structs.Node) bool {
+	if c.pool == structs.NodePoolAll {
+		return true
+	}
+	if nodePool(option) == c.pool {
+		return true
+	}
+	c.ctx.Metrics().FilterNode(option, "node pool")
+	return false
+}
+
+// nodePool returns the node pool of the node. Nodes registered before node
+// pools existed are treated as part of the default node pool.
+func nodePool(node *structs.Node) string {
+	if node.NodePool == "" {
+		return structs.NodePoolDefault
+	}
+	return node.NodePool
+}
+
 // ConstraintChecker is a FeasibilityChecker which returns nodes that match a
 // given set of constraints. This is used to filter on job, task group, and task
 // constraints.
@@ -759,6 +897,9 @@ func resolveTarget(target string, node *structs.Node) (interface{}, bool) {
 	case "${node.datacenter}" == target:
 		return node.Datacenter, true
 
+	case "${node.pool}" == target:
+		return node.NodePool, true
+
 	case "${node.unique.name}" == target:
 		return node.Name, true
 
@@ -1071,12 +1212,16 @@ OUTER:
 		}
 
 		// Check if the job has been marked as eligible or ineligible.
-		jobEscaped, jobUnknown := false, false
+		jobEscaped, jobUnknown, jobIneligible := false, false, false
 		switch evalElig.JobStatus(option.ComputedClass) {
 		case EvalComputedClassIneligible:
-			// Fast path the ineligible case
-			metrics.FilterNode(option, "computed class ineligible")
-			continue
+			// Fast path the ineligible case, unless explaining which check
+			// each node failed
+			if !metrics.Explaining() {
+				metrics.FilterNode(option, "computed class ineligible")
+				continue
+			}
+			jobIneligible = true
 		case EvalComputedClassEscaped:
 			jobEscaped = true
 		case EvalComputedClassUnknown:
@@ -1096,6 +1241,13 @@ OUTER:
 			}
 		}
 
+		// Nodes of an ineligible class are never placed on, even if the
+		// checks run to explain them passed.
+		if jobIneligible {
+			metrics.FilterNode(option, "computed class ineligible")
+			continue
+		}
+
 		// Set the job eligibility if the constraints weren't escaped and it
 		// hasn't been set before.
 		if !jobEscaped && jobUnknown {
@@ -1103,12 +1255,16 @@ OUTER:
 		}
 
 		// Check if the task group has been marked as eligible or ineligible.
-		tgEscaped, tgUnknown := false, false
+		tgEscaped, tgUnknown, tgIneligible := false, false, false
 		switch evalElig.TaskGroupStatus(w.tg, option.ComputedClass) {
 		case EvalComputedClassIneligible:
-			// Fast path the ineligible case
-			metrics.FilterNode(option, "computed class ineligible")
-			continue
+			// Fast path the ineligible case, unless explaining which check
+			// each node failed
+			if !metrics.Explaining() {
+				metrics.FilterNode(option, "computed class ineligible")
+				continue
+			}
+			tgIneligible = true
 		case EvalComputedClassEligible:
 			// Fast path the eligible case
 			if w.available(option) {
@@ -1136,6 +1292,11 @@ OUTER:
 			}
 		}
 
+		if tgIneligible {
+			metrics.FilterNode(option, "computed class ineligible")
+			continue
+		}
+
 		// Set the task group eligibility if the constraints weren't escaped and
 		// it hasn't been set before.
 		if !tgEscaped && tgUnknown {
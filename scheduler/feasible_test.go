@@ -1421,6 +1421,86 @@ func TestDistinctHostsIterator_TaskGroupDistinctHosts(t *testing.T) {
 // This test puts creates allocations across task groups that use a property
 // value to detect if the constraint at the job level properly considers all
 // task groups.
+func TestAllocAffinityConstraintIterator(t *testing.T) {
+	_, ctx := testContext(t)
+	nodes := []*structs.Node{
+		mock.Node(),
+		mock.Node(),
+		mock.Node(),
+	}
+	static := NewStaticIterator(ctx, nodes)
+
+	// Place the cache job on the first node and a job with the same ID in
+	// another namespace on the second node, which should be ignored.
+	plan := ctx.Plan()
+	plan.NodeAllocation[nodes[0].ID] = []*structs.Allocation{
+		{
+			ID:        uuid.Generate(),
+			Namespace: structs.DefaultNamespace,
+			JobID:     "cache",
+			TaskGroup: "redis",
+		},
+	}
+	plan.NodeAllocation[nodes[1].ID] = []*structs.Allocation{
+		{
+			ID:        uuid.Generate(),
+			Namespace: "other",
+			JobID:     "cache",
+			TaskGroup: "redis",
+		},
+	}
+
+	cases := []struct {
+		name       string
+		affinities []*structs.AllocAffinity
+		expected   []*structs.Node
+	}{
+		{
+			name: "soft affinities are ignored",
+			affinities: []*structs.AllocAffinity{
+				{Job: "cache", Weight: 50},
+			},
+			expected: nodes,
+		},
+		{
+			name: "affinity",
+			affinities: []*structs.AllocAffinity{
+				{Job: "cache", Weight: 50, Hard: true},
+			},
+			expected: []*structs.Node{nodes[0]},
+		},
+		{
+			name: "affinity to other group",
+			affinities: []*structs.AllocAffinity{
+				{Job: "cache", TaskGroup: "memcached", Weight: 50, Hard: true},
+			},
+			expected: nil,
+		},
+		{
+			name: "anti-affinity",
+			affinities: []*structs.AllocAffinity{
+				{Job: "cache", TaskGroup: "redis", Weight: -50, Hard: true},
+			},
+			expected: []*structs.Node{nodes[1], nodes[2]},
+		},
+	}
+
+	for _, tc := range cases {
+		t.Run(tc.name, func(t *testing.T) {
+			job := mock.Job()
+			tg := job.TaskGroups[0]
+			tg.AllocAffinities = tc.affinities
+
+			static.Reset()
+			iter := NewAllocAffinityConstraintIterator(ctx, static)
+			iter.SetJob(job)
+			iter.SetTaskGroup(tg)
+
+			require.Equal(t, tc.expected, collectFeasible(iter))
+		})
+	}
+}
+
 func TestDistinctPropertyIterator_JobDistinctProperty(t *testing.T) {
 	state, ctx := testContext(t)
 	nodes := []*structs.Node{
@@ -2337,6 +2417,41 @@ func TestFeasibilityWrapper_JobEligible_TgEscaped(t *testing.T) {
 	}
 }
 
+// TestFeasibilityWrapper_Explain_IneligibleClass asserts that when explaining
+// a plan every node of an ineligible class is explained by the check it
+// failed, not only the first node of the class.
+func TestFeasibilityWrapper_Explain_IneligibleClass(t *testing.T) {
+	_, ctx := testContext(t)
+	ctx.SetExplain(true)
+
+	nodes := []*structs.Node{mock.Node(), mock.Node(), mock.Node()}
+	for _, node := range nodes {
+		require.NoError(t, node.ComputeClass())
+		require.Equal(t, nodes[0].ComputedClass, node.ComputedClass)
+	}
+
+	constraint := &structs.Constraint{
+		LTarget: "${attr.kernel.name}",
+		RTarget: "windows",
+		Operand: "=",
+	}
+	jobChecker := NewConstraintChecker(ctx, []*structs.Constraint{constraint})
+	tgChecker := NewConstraintChecker(ctx, nil)
+	wrapper := NewFeasibilityWrapper(ctx, NewStaticIterator(ctx, nodes),
+		[]FeasibilityChecker{jobChecker}, []FeasibilityChecker{tgChecker}, nil)
+
+	require.Empty(t, collectFeasible(wrapper))
+	require.Equal(t, EvalComputedClassIneligible, ctx.Eligibility().JobStatus(nodes[0].ComputedClass))
+
+	metrics := ctx.Metrics()
+	require.Len(t, metrics.NodeExplanations, len(nodes))
+	for _, e := range metrics.NodeExplanations {
+		require.Equal(t, constraint.String(), e.Filtered)
+	}
+	require.Equal(t, len(nodes), metrics.ConstraintFiltered[constraint.String()])
+	require.Zero(t, metrics.ConstraintFiltered["computed class ineligible"])
+}
+
 func TestSetContainsAny(t *testing.T) {
 	require.True(t, checkSetContainsAny("a", "a"))
 	require.True(t, checkSetContainsAny("a,b", "a"))
@@ -43,6 +43,14 @@ const (
 	// allocRescheduled is the status used when an allocation failed and was rescheduled
 	allocRescheduled = "alloc was rescheduled because it failed"
 
+	// allocUnknown is the status used when an allocation is unknown because
+	// its client is disconnected
+	allocUnknown = "alloc is unknown since its node is disconnected"
+
+	// allocReconnected is the status used when a replacement allocation is
+	// stopped because the original allocation reconnected
+	allocReconnected = "alloc not needed due to disconnected client reconnect"
+
 	// blockedEvalMaxPlanDesc is the description used for blocked evals that are
 	// a result of hitting the max number of plan attempts
 	blockedEvalMaxPlanDesc = "created due to placement conflicts"
@@ -55,6 +63,10 @@ const (
 	// up evals for delayed rescheduling
 	reschedulingFollowupEvalDesc = "created for delayed rescheduling"
 
+	// disconnectTimeoutFollowupEvalDesc is the description used when creating
+	// follow up evals for allocations that may exceed max_client_disconnect
+	disconnectTimeoutFollowupEvalDesc = "created for delayed disconnect timeout"
+
 	// maxPastRescheduleEvents is the maximum number of past reschedule event
 	// that we track when unlimited rescheduling is enabled
 	maxPastRescheduleEvents = 5
@@ -138,7 +150,7 @@ func (s *GenericScheduler) Process(eval *structs.Evaluation) error {
 		structs.EvalTriggerPeriodicJob, structs.EvalTriggerMaxPlans,
 		structs.EvalTriggerDeploymentWatcher, structs.EvalTriggerRetryFailedAlloc,
 		structs.EvalTriggerFailedFollowUp, structs.EvalTriggerPreemption,
-		structs.EvalTriggerScaling:
+		structs.EvalTriggerScaling, structs.EvalTriggerJobDependencies:
 	default:
 		desc := fmt.Sprintf("scheduler cannot handle '%s' evaluation reason",
 			eval.TriggeredBy)
@@ -147,6 +159,18 @@ func (s *GenericScheduler) Process(eval *structs.Evaluation) error {
 			s.deployment.GetID())
 	}
 
+	// Hold the evaluation while the dependencies of the job are unmet
+	if s.batch {
+		waiting, err := holdForDependencies(s.state, s.planner, s.eval)
+		if err != nil {
+			return err
+		} else if waiting != nil {
+			s.logger.Debug("job dependencies unmet, waiting eval created", "waiting_eval_id", waiting.ID)
+			return setStatus(s.logger, s.planner, s.eval, waiting, nil, nil,
+				structs.EvalStatusComplete, "", nil, "")
+		}
+	}
+
 	// Retry up to the maxScheduleAttempts and reset if progress is made.
 	progress := func() bool { return progressMade(s.planResult) }
 	limit := maxServiceScheduleAttempts
@@ -246,6 +270,7 @@ func (s *GenericScheduler) process() (bool, error) {
 
 	// Create an evaluation context
 	s.ctx = NewEvalContext(s.state, s.plan, s.logger)
+	s.ctx.SetExplain(s.eval.ExplainPlan)
 
 	// Construct the placement stack
 	s.stack = NewGenericStack(s.batch, s.ctx)
@@ -327,6 +352,16 @@ func (s *GenericScheduler) process() (bool, error) {
 	return true, nil
 }
 
+// nodeByID returns the node with the given ID, or nil if it can't be found.
+func (s *GenericScheduler) nodeByID(nodeID string) *structs.Node {
+	node, err := s.state.NodeByID(nil, nodeID)
+	if err != nil {
+		s.logger.Error("failed to lookup node", "node_id", nodeID, "error", err)
+		return nil
+	}
+	return node
+}
+
 // computeJobAllocs is used to reconcile differences between the job,
 // existing allocations and node status to update the allocations.
 func (s *GenericScheduler) computeJobAllocs() error {
@@ -352,6 +387,7 @@ func (s *GenericScheduler) computeJobAllocs() error {
 	reconciler := NewAllocReconciler(s.logger,
 		genericAllocUpdateFn(s.ctx, s.stack, s.eval.ID),
 		s.batch, s.eval.JobID, s.job, s.deployment, allocs, tainted, s.eval.ID)
+	reconciler.nodeByID = s.nodeByID
 	results := reconciler.Compute()
 	s.logger.Debug("reconciled current state with desired state", "results", log.Fmt("%#v", results))
 
@@ -396,6 +432,16 @@ func (s *GenericScheduler) computeJobAllocs() error {
 		s.ctx.Plan().AppendAlloc(update, nil)
 	}
 
+	// Mark the allocations on disconnected clients as unknown
+	for _, update := range results.disconnectUpdates {
+		s.ctx.Plan().AppendUnknownAlloc(update)
+	}
+
+	// Record that the reconnected allocations were reconciled
+	for _, update := range results.reconnectUpdates {
+		s.ctx.Plan().AppendAlloc(update, nil)
+	}
+
 	// Nothing remaining to do if placement is not required
 	if len(results.place)+len(results.destructiveUpdate) == 0 {
 		// If the job has been purged we don't have access to the job. Otherwise
@@ -471,7 +517,7 @@ func (s *GenericScheduler) downgradedJobForPlacement(p placementResult) (string,
 // destructive updates to place and the set of new placements to place.
 func (s *GenericScheduler) computePlacements(destructive, place []placementResult) error {
 	// Get the base nodes
-	nodes, _, byDC, err := readyNodesInDCs(s.state, s.job.Datacenters)
+	nodes, _, byDC, err := readyNodesInDCs(s.state, s.job.Datacenters, s.job.NodePool)
 	if err != nil {
 		return err
 	}
@@ -487,6 +533,10 @@ func (s *GenericScheduler) computePlacements(destructive, place []placementResul
 	// Capture current time to use as the start time for any rescheduled allocations
 	now := time.Now()
 
+	// Track the placements of all_or_nothing task groups, so they can be
+	// withheld if any allocation of the group fails to be placed.
+	allOrNothing := make(map[string]*allOrNothingPlacements)
+
 	// Have to handle destructive changes first as we need to discount their
 	// resources. To understand this imagine the resources were reduced and the
 	// count was scaled up.
@@ -626,6 +676,18 @@ func (s *GenericScheduler) computePlacements(destructive, place []placementResul
 				// Track the placement
 				s.plan.AppendAlloc(alloc, downgradedJob)
 
+				if tg.IsAllOrNothing() {
+					p, ok := allOrNothing[tg.Name]
+					if !ok {
+						p = &allOrNothingPlacements{}
+						allOrNothing[tg.Name] = p
+					}
+					p.placed = append(p.placed, alloc)
+					if stopPrevAlloc {
+						p.stopped = append(p.stopped, prevAllocation)
+					}
+				}
+
 			} else {
 				// Lazy initialize the failed map
 				if s.failedTGAllocs == nil {
@@ -648,9 +710,41 @@ func (s *GenericScheduler) computePlacements(destructive, place []placementResul
 		}
 	}
 
+	s.withholdAllOrNothingPlacements(allOrNothing)
 	return nil
 }
 
+// allOrNothingPlacements tracks the changes made to the plan while placing
+// the allocations of an all_or_nothing task group.
+type allOrNothingPlacements struct {
+	placed  []*structs.Allocation
+	stopped []*structs.Allocation
+}
+
+// withholdAllOrNothingPlacements removes from the plan the placements of
+// all_or_nothing task groups that failed to place at least one allocation,
+// along with the stops and preemptions those placements required. The
+// withheld allocations are recorded as failures of the task group, so the
+// blocked evaluation retries all of them once resources free up.
+func (s *GenericScheduler) withholdAllOrNothingPlacements(allOrNothing map[string]*allOrNothingPlacements) {
+	for tgName, p := range allOrNothing {
+		metric, failed := s.failedTGAllocs[tgName]
+		if !failed {
+			continue
+		}
+
+		for _, alloc := range p.placed {
+			s.plan.RemovePlacement(alloc)
+		}
+		for _, alloc := range p.stopped {
+			s.plan.RemoveUpdate(alloc)
+		}
+
+		metric.CoalescedFailures += len(p.placed)
+		metric.PlacementsWithheld += len(p.placed)
+	}
+}
+
 // propagateTaskState copies task handles from previous allocations to
 // replacement allocations when the previous allocation is being drained or was
 // lost. Remote task drivers rely on this to reconnect to remote tasks when the
@@ -6383,3 +6383,134 @@ func TestPropagateTaskState(t *testing.T) {
 		})
 	}
 }
+
+func TestBatchSched_AllOrNothing(t *testing.T) {
+	h := NewHarness(t)
+
+	// Create two nodes that each fit a single allocation
+	for i := 0; i < 2; i++ {
+		node := mock.Node()
+		require.NoError(t, h.State.UpsertNode(structs.MsgTypeTestSetup, h.NextIndex(), node))
+	}
+
+	// Create a job with more allocations than fit on the nodes
+	job := mock.BatchJob()
+	job.TaskGroups[0].Count = 3
+	job.TaskGroups[0].Placement = structs.TaskGroupPlacementAllOrNothing
+	job.TaskGroups[0].Tasks[0].Resources.CPU = 3000
+	require.NoError(t, h.State.UpsertJob(structs.MsgTypeTestSetup, h.NextIndex(), job))
+
+	// Create a mock evaluation to register the job
+	eval := &structs.Evaluation{
+		Namespace:   structs.DefaultNamespace,
+		ID:          uuid.Generate(),
+		Priority:    job.Priority,
+		TriggeredBy: structs.EvalTriggerJobRegister,
+		JobID:       job.ID,
+		Status:      structs.EvalStatusPending,
+	}
+	require.NoError(t, h.State.UpsertEvals(structs.MsgTypeTestSetup, h.NextIndex(), []*structs.Evaluation{eval}))
+
+	// Process the evaluation
+	require.NoError(t, h.Process(NewBatchScheduler, eval))
+
+	// Ensure none of the allocations were placed
+	require.Empty(t, h.Plans)
+	ws := memdb.NewWatchSet()
+	out, err := h.State.AllocsByJob(ws, job.Namespace, job.ID, false)
+	require.NoError(t, err)
+	require.Empty(t, out)
+
+	// Ensure a blocked eval was created
+	require.Len(t, h.CreateEvals, 1)
+	require.Equal(t, structs.EvalStatusBlocked, h.CreateEvals[0].Status)
+
+	// Ensure the failed metric explains the withheld placements
+	require.Len(t, h.Evals, 1)
+	metrics, ok := h.Evals[0].FailedTGAllocs[job.TaskGroups[0].Name]
+	require.True(t, ok)
+	require.Equal(t, 2, metrics.CoalescedFailures)
+	require.Equal(t, 2, metrics.PlacementsWithheld)
+	require.Equal(t, 3, h.Evals[0].QueuedAllocations[job.TaskGroups[0].Name])
+
+	// Add a node that fits the remaining allocation and retry
+	node := mock.Node()
+	require.NoError(t, h.State.UpsertNode(structs.MsgTypeTestSetup, h.NextIndex(), node))
+	blocked := h.CreateEvals[0]
+	require.NoError(t, h.State.UpsertEvals(structs.MsgTypeTestSetup, h.NextIndex(), []*structs.Evaluation{blocked}))
+	require.NoError(t, h.Process(NewBatchScheduler, blocked))
+
+	// Ensure all of the allocations were placed
+	require.Len(t, h.Plans, 1)
+	out, err = h.State.AllocsByJob(ws, job.Namespace, job.ID, false)
+	require.NoError(t, err)
+	require.Len(t, out, 3)
+}
+
+func TestBatchSched_JobDependencies(t *testing.T) {
+	h := NewHarness(t)
+
+	node := mock.Node()
+	require.NoError(t, h.State.UpsertNode(structs.MsgTypeTestSetup, h.NextIndex(), node))
+
+	// Register the upstream job without running it
+	upstream := mock.BatchJob()
+	upstream.TaskGroups[0].Count = 1
+	require.NoError(t, h.State.UpsertJob(structs.MsgTypeTestSetup, h.NextIndex(), upstream))
+
+	// Register a job depending on the upstream job to complete
+	job := mock.BatchJob()
+	job.TaskGroups[0].Count = 1
+	job.DependsOn = []*structs.JobDependency{{
+		JobID:     upstream.ID,
+		Condition: structs.JobDependencyConditionComplete,
+	}}
+	require.NoError(t, h.State.UpsertJob(structs.MsgTypeTestSetup, h.NextIndex(), job))
+
+	eval := &structs.Evaluation{
+		Namespace:   structs.DefaultNamespace,
+		ID:          uuid.Generate(),
+		Priority:    job.Priority,
+		TriggeredBy: structs.EvalTriggerJobRegister,
+		JobID:       job.ID,
+		Status:      structs.EvalStatusPending,
+	}
+	require.NoError(t, h.State.UpsertEvals(structs.MsgTypeTestSetup, h.NextIndex(), []*structs.Evaluation{eval}))
+	require.NoError(t, h.Process(NewBatchScheduler, eval))
+
+	// Ensure nothing was placed and a waiting eval was created
+	require.Empty(t, h.Plans)
+	require.Len(t, h.CreateEvals, 1)
+	waiting := h.CreateEvals[0]
+	require.Equal(t, structs.EvalStatusWaiting, waiting.Status)
+	require.Equal(t, structs.EvalTriggerJobDependencies, waiting.TriggeredBy)
+	require.Contains(t, waiting.StatusDescription, upstream.ID)
+
+	require.Len(t, h.Evals, 1)
+	require.Equal(t, structs.EvalStatusComplete, h.Evals[0].Status)
+	require.Equal(t, waiting.ID, h.Evals[0].NextEval)
+
+	// Complete the upstream job
+	alloc := mock.Alloc()
+	alloc.Job = upstream
+	alloc.JobID = upstream.ID
+	alloc.NodeID = node.ID
+	alloc.TaskGroup = upstream.TaskGroups[0].Name
+	require.NoError(t, h.State.UpsertAllocs(structs.MsgTypeTestSetup, h.NextIndex(), []*structs.Allocation{alloc}))
+	update := alloc.Copy()
+	update.ClientStatus = structs.AllocClientStatusComplete
+	require.NoError(t, h.State.UpdateAllocsFromClient(structs.MsgTypeTestSetup, h.NextIndex(), []*structs.Allocation{update}))
+
+	// Process the released waiting eval
+	released := waiting.Copy()
+	released.Status = structs.EvalStatusPending
+	require.NoError(t, h.State.UpsertEvals(structs.MsgTypeTestSetup, h.NextIndex(), []*structs.Evaluation{released}))
+	require.NoError(t, h.Process(NewBatchScheduler, released))
+
+	// Ensure the job was placed
+	require.Len(t, h.Plans, 1)
+	require.Len(t, h.CreateEvals, 1)
+	out, err := h.State.AllocsByJob(nil, job.Namespace, job.ID, false)
+	require.NoError(t, err)
+	require.Len(t, out, 1)
+}
@@ -0,0 +1,75 @@
+package scheduler
+
+import (
+	"sort"
+
+	"github.com/hashicorp/nomad/lib/cpuset"
+	"github.com/hashicorp/nomad/nomad/structs"
+)
+
+// selectCores picks the given number of cores from the cores available on a
+// node according to the NUMA affinity of the task. It returns the selected
+// cores and, when the affinity is not "none", the NUMA nodes they belong to.
+// It returns false if the cores cannot be placed on the node.
+func selectCores(cpu *structs.NodeCpuResources, available cpuset.CPUSet, count int, numa *structs.NUMA) ([]uint16, []uint16, bool) {
+	if available.Size() < count {
+		return nil, nil, false
+	}
+
+	affinity := numa.EffectiveAffinity()
+
+	// Without NUMA topology the node is treated as a single NUMA node, so
+	// any affinity is satisfied.
+	if affinity == structs.NUMAAffinityNone || len(cpu.NUMANodes) == 0 {
+		return available.ToSlice()[0:count], nil, true
+	}
+
+	// Compute the available cores of each NUMA node
+	type numaCores struct {
+		id    uint16
+		cores []uint16
+	}
+	nodes := make([]numaCores, 0, len(cpu.NUMANodes))
+	for _, n := range cpu.NUMANodes {
+		cores := available.Intersection(cpuset.New(n.Cores...)).ToSlice()
+		if len(cores) > 0 {
+			nodes = append(nodes, numaCores{id: n.ID, cores: cores})
+		}
+	}
+
+	// Prefer the NUMA node with the fewest available cores that still fits
+	// the task, to keep larger blocks of cores free for other tasks.
+	sort.SliceStable(nodes, func(i, j int) bool {
+		return len(nodes[i].cores) < len(nodes[j].cores)
+	})
+	for _, n := range nodes {
+		if len(n.cores) >= count {
+			return n.cores[0:count], []uint16{n.id}, true
+		}
+	}
+
+	if affinity == structs.NUMAAffinityRequire {
+		return nil, nil, false
+	}
+
+	// Span as few NUMA nodes as possible by taking cores from the NUMA
+	// nodes with the most available cores first.
+	var cores, ids []uint16
+	for i := len(nodes) - 1; i >= 0 && len(cores) < count; i-- {
+		n := nodes[i]
+		need := count - len(cores)
+		if need > len(n.cores) {
+			need = len(n.cores)
+		}
+		cores = append(cores, n.cores[0:need]...)
+		ids = append(ids, n.id)
+	}
+
+	// Cores that are not part of any NUMA node make up the difference.
+	if len(cores) < count {
+		rest := available.Difference(cpuset.New(cores...)).ToSlice()
+		cores = append(cores, rest[0:count-len(cores)]...)
+	}
+
+	return cpuset.New(cores...).ToSlice(), cpuset.New(ids...).ToSlice(), true
+}
@@ -0,0 +1,107 @@
+package scheduler
+
+import (
+	"testing"
+
+	"github.com/hashicorp/nomad/lib/cpuset"
+	"github.com/hashicorp/nomad/nomad/structs"
+	"github.com/stretchr/testify/require"
+)
+
+func TestSelectCores(t *testing.T) {
+	t.Parallel()
+
+	twoSockets := &structs.NodeCpuResources{
+		ReservableCpuCores: []uint16{0, 1, 2, 3, 4, 5, 6, 7},
+		NUMANodes: []structs.NUMANode{
+			{ID: 0, Cores: []uint16{0, 1, 2, 3}},
+			{ID: 1, Cores: []uint16{4, 5, 6, 7}},
+		},
+	}
+
+	cases := []struct {
+		name          string
+		cpu           *structs.NodeCpuResources
+		available     cpuset.CPUSet
+		count         int
+		affinity      string
+		expectedCores []uint16
+		expectedNUMA  []uint16
+		expectedOK    bool
+	}{
+		{
+			name:          "no affinity",
+			cpu:           twoSockets,
+			available:     cpuset.New(2, 3, 4, 5, 6, 7),
+			count:         3,
+			affinity:      structs.NUMAAffinityNone,
+			expectedCores: []uint16{2, 3, 4},
+			expectedOK:    true,
+		},
+		{
+			name:          "no topology",
+			cpu:           &structs.NodeCpuResources{},
+			available:     cpuset.New(0, 1, 2, 3),
+			count:         2,
+			affinity:      structs.NUMAAffinityRequire,
+			expectedCores: []uint16{0, 1},
+			expectedOK:    true,
+		},
+		{
+			name:          "require fits one node",
+			cpu:           twoSockets,
+			available:     cpuset.New(2, 3, 4, 5, 6, 7),
+			count:         3,
+			affinity:      structs.NUMAAffinityRequire,
+			expectedCores: []uint16{4, 5, 6},
+			expectedNUMA:  []uint16{1},
+			expectedOK:    true,
+		},
+		{
+			name:          "require best fit",
+			cpu:           twoSockets,
+			available:     cpuset.New(2, 3, 4, 5, 6, 7),
+			count:         2,
+			affinity:      structs.NUMAAffinityRequire,
+			expectedCores: []uint16{2, 3},
+			expectedNUMA:  []uint16{0},
+			expectedOK:    true,
+		},
+		{
+			name:       "require does not fit",
+			cpu:        twoSockets,
+			available:  cpuset.New(2, 3, 4, 5, 6),
+			count:      4,
+			affinity:   structs.NUMAAffinityRequire,
+			expectedOK: false,
+		},
+		{
+			name:          "prefer spans nodes",
+			cpu:           twoSockets,
+			available:     cpuset.New(2, 3, 4, 5, 6),
+			count:         4,
+			affinity:      structs.NUMAAffinityPrefer,
+			expectedCores: []uint16{2, 4, 5, 6},
+			expectedNUMA:  []uint16{0, 1},
+			expectedOK:    true,
+		},
+		{
+			name:       "not enough cores",
+			cpu:        twoSockets,
+			available:  cpuset.New(0, 1),
+			count:      3,
+			affinity:   structs.NUMAAffinityPrefer,
+			expectedOK: false,
+		},
+	}
+
+	for _, tc := range cases {
+		t.Run(tc.name, func(t *testing.T) {
+			cores, numaNodes, ok := selectCores(tc.cpu, tc.available, tc.count,
+				&structs.NUMA{Affinity: tc.affinity})
+			require.Equal(t, tc.expectedOK, ok)
+			require.Equal(t, tc.expectedCores, cores)
+			require.Equal(t, tc.expectedNUMA, numaNodes)
+		})
+	}
+}
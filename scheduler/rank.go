@@ -3,6 +3,7 @@ package scheduler
 import (
 	"fmt"
 	"math"
+	"time"
 
 	"github.com/hashicorp/nomad/lib/cpuset"
 
@@ -154,6 +155,7 @@ type BinPackIterator struct {
 	evict                  bool
 	priority               int
 	jobId                  structs.NamespacedID
+	job                    *structs.Job
 	taskGroup              *structs.TaskGroup
 	memoryOversubscription bool
 	scoreFit               func(*structs.Node, *structs.ComparableResources) float64
@@ -162,28 +164,34 @@ type BinPackIterator struct {
 // NewBinPackIterator returns a BinPackIterator which tries to fit tasks
 // potentially evicting other tasks based on a given priority.
 func NewBinPackIterator(ctx Context, source RankIterator, evict bool, priority int, schedConfig *structs.SchedulerConfiguration) *BinPackIterator {
+	iter := &BinPackIterator{
+		ctx:      ctx,
+		source:   source,
+		evict:    evict,
+		priority: priority,
+	}
+	iter.SetSchedulerConfiguration(schedConfig)
+	iter.ctx.Logger().Named("binpack").Trace("NewBinPackIterator created", "algorithm", schedConfig.EffectiveSchedulerAlgorithm())
+	return iter
+}
 
-	algorithm := schedConfig.EffectiveSchedulerAlgorithm()
+// SetSchedulerConfiguration updates the scoring algorithm and memory
+// oversubscription setting of the iterator. It is used to apply the
+// scheduler configuration overrides of the job's node pool.
+func (iter *BinPackIterator) SetSchedulerConfiguration(schedConfig *structs.SchedulerConfiguration) {
 	scoreFn := structs.ScoreFitBinPack
-	if algorithm == structs.SchedulerAlgorithmSpread {
+	if schedConfig.EffectiveSchedulerAlgorithm() == structs.SchedulerAlgorithmSpread {
 		scoreFn = structs.ScoreFitSpread
 	}
 
-	iter := &BinPackIterator{
-		ctx:                    ctx,
-		source:                 source,
-		evict:                  evict,
-		priority:               priority,
-		memoryOversubscription: schedConfig != nil && schedConfig.MemoryOversubscriptionEnabled,
-		scoreFit:               scoreFn,
-	}
-	iter.ctx.Logger().Named("binpack").Trace("NewBinPackIterator created", "algorithm", algorithm)
-	return iter
+	iter.scoreFit = scoreFn
+	iter.memoryOversubscription = schedConfig != nil && schedConfig.MemoryOversubscriptionEnabled
 }
 
 func (iter *BinPackIterator) SetJob(job *structs.Job) {
 	iter.priority = job.Priority
 	iter.jobId = job.NamespacedID()
+	iter.job = job
 }
 
 func (iter *BinPackIterator) SetTaskGroup(taskGroup *structs.TaskGroup) {
@@ -451,15 +459,23 @@ OUTER:
 				// set of CPUs not yet reserved on the node
 				availableCPUSet := nodeCPUSet.Difference(allocatedCPUSet)
 
-				// If not enough cores are available mark the node as exhausted
-				if availableCPUSet.Size() < task.Resources.Cores {
+				// Select the cores honoring the task's NUMA affinity. If not
+				// enough cores are available mark the node as exhausted
+				cores, numaNodes, ok := selectCores(&option.Node.NodeResources.Cpu,
+					availableCPUSet, task.Resources.Cores, task.Resources.NUMA)
+				if !ok {
 					// TODO preemption
-					iter.ctx.Metrics().ExhaustedNode(option.Node, "cores")
+					dimension := "cores"
+					if availableCPUSet.Size() >= task.Resources.Cores {
+						dimension = "numa cores"
+					}
+					iter.ctx.Metrics().ExhaustedNode(option.Node, dimension)
 					continue OUTER
 				}
 
 				// Set the task's reserved cores
-				taskResources.Cpu.ReservedCores = availableCPUSet.ToSlice()[0:task.Resources.Cores]
+				taskResources.Cpu.ReservedCores = cores
+				taskResources.Cpu.NUMANodes = numaNodes
 				// Total CPU usage on the node is still tracked by CPUShares. Even though the task will have the entire
 				// core reserved, we still track overall usage by cpu shares.
 				taskResources.Cpu.CpuShares = option.Node.NodeResources.Cpu.SharesPerCore() * int64(task.Resources.Cores)
@@ -477,7 +493,23 @@ OUTER:
 		current := proposed
 
 		// Add the resources we are trying to fit
-		proposed = append(proposed, &structs.Allocation{AllocatedResources: total})
+		placing := &structs.Allocation{
+			Job:                iter.job,
+			AllocatedResources: total,
+			DesiredStatus:      structs.AllocDesiredStatusRun,
+			ClientStatus:       structs.AllocClientStatusPending,
+		}
+		proposed = append(proposed, placing)
+
+		// Hold the capacity of the reservations on the node that aren't
+		// claimed, including by the allocation being placed. Reservations
+		// are never preempted.
+		reservations, err := iter.ctx.State().ReservationsByNode(nil, option.Node.ID)
+		if err != nil {
+			iter.ctx.Logger().Named("binpack").Error("failed retrieving reservations", "error", err)
+			continue
+		}
+		proposed = append(proposed, structs.ReservationAllocs(option.Node.ID, reservations, proposed, iter.job, time.Now())...)
 
 		// Check if these allocations fit, if they do not, simply skip this node
 		fit, dim, util, _ := structs.AllocsFit(option.Node, proposed, netIdx, false)
@@ -734,6 +766,86 @@ func matchesAffinity(ctx Context, affinity *structs.Affinity, option *structs.No
 	return checkAffinity(ctx, affinity.Operand, lVal, rVal, lOk, rOk)
 }
 
+// AllocAffinityIterator is used to apply a weighted score to nodes based on
+// whether they run allocations matched by the soft alloc_affinity stanzas of
+// the job or task group. Hard alloc affinities are enforced by the
+// AllocAffinityConstraintIterator instead.
+type AllocAffinityIterator struct {
+	ctx           Context
+	source        RankIterator
+	namespace     string
+	jobAffinities []*structs.AllocAffinity
+	affinities    []*structs.AllocAffinity
+}
+
+// NewAllocAffinityIterator is used to create an AllocAffinityIterator that
+// applies a weighted score according to whether nodes run allocations
+// matching any alloc affinities in the job or task group.
+func NewAllocAffinityIterator(ctx Context, source RankIterator) *AllocAffinityIterator {
+	return &AllocAffinityIterator{
+		ctx:    ctx,
+		source: source,
+	}
+}
+
+func (iter *AllocAffinityIterator) SetJob(job *structs.Job) {
+	iter.namespace = job.Namespace
+	iter.jobAffinities = job.AllocAffinities
+}
+
+func (iter *AllocAffinityIterator) SetTaskGroup(tg *structs.TaskGroup) {
+	// Merge job and task group alloc affinities, skipping hard ones
+	for _, affinities := range [][]*structs.AllocAffinity{iter.jobAffinities, tg.AllocAffinities} {
+		for _, affinity := range affinities {
+			if !affinity.Hard {
+				iter.affinities = append(iter.affinities, affinity)
+			}
+		}
+	}
+}
+
+func (iter *AllocAffinityIterator) Reset() {
+	iter.source.Reset()
+	// This method is called between each task group, so only reset the merged list
+	iter.affinities = nil
+}
+
+func (iter *AllocAffinityIterator) hasAffinities() bool {
+	return len(iter.affinities) > 0
+}
+
+func (iter *AllocAffinityIterator) Next() *RankedNode {
+	option := iter.source.Next()
+	if option == nil {
+		return nil
+	}
+	if !iter.hasAffinities() {
+		iter.ctx.Metrics().ScoreNode(option.Node, "alloc-affinity", 0)
+		return option
+	}
+
+	proposed, err := option.ProposedAllocs(iter.ctx)
+	if err != nil {
+		iter.ctx.Logger().Named("alloc_affinity").Error("failed to get proposed allocations", "error", err)
+		return option
+	}
+
+	sumWeight := 0.0
+	totalAffinityScore := 0.0
+	for _, affinity := range iter.affinities {
+		sumWeight += math.Abs(float64(affinity.Weight))
+		if matchesAllocAffinity(iter.namespace, affinity, proposed) {
+			totalAffinityScore += float64(affinity.Weight)
+		}
+	}
+	normScore := totalAffinityScore / sumWeight
+	if totalAffinityScore != 0.0 {
+		option.Scores = append(option.Scores, normScore)
+		iter.ctx.Metrics().ScoreNode(option.Node, "alloc-affinity", normScore)
+	}
+	return option
+}
+
 // ScoreNormalizationIterator is used to combine scores from various prior
 // iterators and combine them into one final score. The current implementation
 // averages the scores together.
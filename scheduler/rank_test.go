@@ -1179,6 +1179,156 @@ func TestBinPackIterator_ExistingAlloc(t *testing.T) {
 	}
 }
 
+// TestBinPackIterator_Reservation asserts that reservations hold capacity on
+// their nodes, except for the jobs claiming them.
+func TestBinPackIterator_Reservation(t *testing.T) {
+	state, ctx := testContext(t)
+	nodes := []*RankedNode{
+		{
+			Node: &structs.Node{
+				ID: uuid.Generate(),
+				NodeResources: &structs.NodeResources{
+					Cpu: structs.NodeCpuResources{
+						CpuShares: 2048,
+					},
+					Memory: structs.NodeMemoryResources{
+						MemoryMB: 2048,
+					},
+				},
+			},
+		},
+		{
+			Node: &structs.Node{
+				ID: uuid.Generate(),
+				NodeResources: &structs.NodeResources{
+					Cpu: structs.NodeCpuResources{
+						CpuShares: 2048,
+					},
+					Memory: structs.NodeMemoryResources{
+						MemoryMB: 2048,
+					},
+				},
+			},
+		},
+	}
+
+	// Reserve all of the first node
+	reservation := mock.Reservation()
+	reservation.Resources = &structs.ReservationResources{
+		CPU:      1024,
+		MemoryMB: 1024,
+	}
+	reservation.Count = 2
+	reservation.NodeIDs = []string{nodes[0].Node.ID, nodes[0].Node.ID}
+	require.NoError(t, state.UpsertReservation(structs.MsgTypeTestSetup, 1000, reservation))
+
+	taskGroup := &structs.TaskGroup{
+		EphemeralDisk: &structs.EphemeralDisk{},
+		Tasks: []*structs.Task{
+			{
+				Name: "web",
+				Resources: &structs.Resources{
+					CPU:      1024,
+					MemoryMB: 1024,
+				},
+			},
+		},
+	}
+
+	// Jobs not claiming the reservation only fit on the second node
+	binp := NewBinPackIterator(ctx, NewStaticRankIterator(ctx, nodes), false, 0, testSchedulerConfig)
+	binp.SetJob(mock.Job())
+	binp.SetTaskGroup(taskGroup)
+
+	out := collectRanked(NewScoreNormalizationIterator(ctx, binp))
+	require.Len(t, out, 1)
+	require.Equal(t, nodes[1], out[0])
+
+	// Jobs claiming the reservation fit on both nodes
+	claimant := mock.Job()
+	claimant.Reservation = reservation.Name
+	binp = NewBinPackIterator(ctx, NewStaticRankIterator(ctx, nodes), false, 0, testSchedulerConfig)
+	binp.SetJob(claimant)
+	binp.SetTaskGroup(taskGroup)
+
+	out = collectRanked(NewScoreNormalizationIterator(ctx, binp))
+	require.Len(t, out, 2)
+}
+
+// TestBinPackIterator_Reservation_PlannedAllocs asserts that placements of
+// the same plan, which don't carry their job, claim the reservation instead
+// of being counted against it.
+func TestBinPackIterator_Reservation_PlannedAllocs(t *testing.T) {
+	state, ctx := testContext(t)
+	nodes := []*RankedNode{
+		{
+			Node: &structs.Node{
+				ID: uuid.Generate(),
+				NodeResources: &structs.NodeResources{
+					Cpu: structs.NodeCpuResources{
+						CpuShares: 2048,
+					},
+					Memory: structs.NodeMemoryResources{
+						MemoryMB: 2048,
+					},
+				},
+			},
+		},
+	}
+
+	// Reserve all of the node for two instances
+	reservation := mock.Reservation()
+	reservation.Resources = &structs.ReservationResources{
+		CPU:      1024,
+		MemoryMB: 1024,
+	}
+	reservation.Count = 2
+	reservation.NodeIDs = []string{nodes[0].Node.ID, nodes[0].Node.ID}
+	require.NoError(t, state.UpsertReservation(structs.MsgTypeTestSetup, 1000, reservation))
+
+	claimant := mock.Job()
+	claimant.Reservation = reservation.Name
+
+	// An earlier placement of the plan claims one of the instances
+	planned := &structs.Allocation{
+		ID:     uuid.Generate(),
+		NodeID: nodes[0].Node.ID,
+		AllocatedResources: &structs.AllocatedResources{
+			Tasks: map[string]*structs.AllocatedTaskResources{
+				"web": {
+					Cpu:    structs.AllocatedCpuResources{CpuShares: 1024},
+					Memory: structs.AllocatedMemoryResources{MemoryMB: 1024},
+				},
+			},
+		},
+		DesiredStatus: structs.AllocDesiredStatusRun,
+		ClientStatus:  structs.AllocClientStatusPending,
+	}
+	ctx.Plan().AppendAlloc(planned, nil)
+
+	taskGroup := &structs.TaskGroup{
+		EphemeralDisk: &structs.EphemeralDisk{},
+		Tasks: []*structs.Task{
+			{
+				Name: "web",
+				Resources: &structs.Resources{
+					CPU:      1024,
+					MemoryMB: 1024,
+				},
+			},
+		},
+	}
+
+	// The second placement claims the remaining instance
+	binp := NewBinPackIterator(ctx, NewStaticRankIterator(ctx, nodes), false, 0, testSchedulerConfig)
+	binp.SetJob(claimant)
+	binp.SetTaskGroup(taskGroup)
+
+	out := collectRanked(NewScoreNormalizationIterator(ctx, binp))
+	require.Len(t, out, 1)
+	require.Equal(t, nodes[0], out[0])
+}
+
 func TestBinPackIterator_ExistingAlloc_PlannedEvict(t *testing.T) {
 	state, ctx := testContext(t)
 	nodes := []*RankedNode{
@@ -1880,3 +2030,64 @@ func TestNodeAffinityIterator(t *testing.T) {
 	}
 
 }
+
+func TestAllocAffinityIterator(t *testing.T) {
+	_, ctx := testContext(t)
+	nodes := []*RankedNode{
+		{Node: mock.Node()},
+		{Node: mock.Node()},
+		{Node: mock.Node()},
+	}
+
+	plan := ctx.Plan()
+	plan.NodeAllocation[nodes[0].Node.ID] = []*structs.Allocation{
+		{
+			ID:        uuid.Generate(),
+			Namespace: structs.DefaultNamespace,
+			JobID:     "cache",
+			TaskGroup: "redis",
+		},
+	}
+	plan.NodeAllocation[nodes[1].Node.ID] = []*structs.Allocation{
+		{
+			ID:        uuid.Generate(),
+			Namespace: structs.DefaultNamespace,
+			JobID:     "batch",
+			TaskGroup: "worker",
+		},
+	}
+
+	static := NewStaticRankIterator(ctx, nodes)
+
+	job := mock.Job()
+	job.AllocAffinities = []*structs.AllocAffinity{
+		{Job: "cache", Weight: 100},
+	}
+	tg := job.TaskGroups[0]
+	tg.AllocAffinities = []*structs.AllocAffinity{
+		{Job: "batch", TaskGroup: "worker", Weight: -50},
+
+		// Hard affinities are enforced by the feasibility iterator
+		{Job: "other", Weight: 100, Hard: true},
+	}
+
+	allocAffinity := NewAllocAffinityIterator(ctx, static)
+	allocAffinity.SetJob(job)
+	allocAffinity.SetTaskGroup(tg)
+
+	scoreNorm := NewScoreNormalizationIterator(ctx, allocAffinity)
+
+	out := collectRanked(scoreNorm)
+
+	// Total weight = 150
+	expectedScores := map[string]float64{
+		nodes[0].Node.ID: 100.0 / 150.0,
+		nodes[1].Node.ID: -50.0 / 150.0,
+		nodes[2].Node.ID: 0,
+	}
+
+	require.Len(t, out, 3)
+	for _, n := range out {
+		require.Equal(t, expectedScores[n.Node.ID], n.FinalScore)
+	}
+}
@@ -70,6 +70,11 @@ type allocReconciler struct {
 	// taintedNodes contains a map of nodes that are tainted
 	taintedNodes map[string]*structs.Node
 
+	// nodeByID is used to look up the node of an allocation when choosing
+	// which allocations to stop for a task group with hard spreads. If nil,
+	// hard spreads are not taken into account when stopping allocations.
+	nodeByID func(nodeID string) *structs.Node
+
 	// existingAllocs is non-terminal existing allocations
 	existingAllocs []*structs.Allocation
 
@@ -119,6 +124,14 @@ type reconcileResults struct {
 	// desiredFollowupEvals is the map of follow up evaluations to create per task group
 	// This is used to create a delayed evaluation for rescheduling failed allocations.
 	desiredFollowupEvals map[string][]*structs.Evaluation
+
+	// disconnectUpdates is the set of allocations on disconnected clients
+	// that should be marked unknown.
+	disconnectUpdates map[string]*structs.Allocation
+
+	// reconnectUpdates is the set of allocations that reconnected after
+	// their client was disconnected and are kept by the scheduler.
+	reconnectUpdates map[string]*structs.Allocation
 }
 
 // delayedRescheduleInfo contains the allocation id and a time when its eligible to be rescheduled.
@@ -153,7 +166,8 @@ func (r *reconcileResults) GoString() string {
 
 // Changes returns the number of total changes
 func (r *reconcileResults) Changes() int {
-	return len(r.place) + len(r.inplaceUpdate) + len(r.stop)
+	return len(r.place) + len(r.inplaceUpdate) + len(r.stop) +
+		len(r.disconnectUpdates) + len(r.reconnectUpdates)
 }
 
 // NewAllocReconciler creates a new reconciler that should be used to determine
@@ -175,6 +189,8 @@ func NewAllocReconciler(logger log.Logger, allocUpdateFn allocUpdateType, batch
 		result: &reconcileResults{
 			desiredTGUpdates:     make(map[string]*structs.DesiredUpdates),
 			desiredFollowupEvals: make(map[string][]*structs.Evaluation),
+			disconnectUpdates:    make(map[string]*structs.Allocation),
+			reconnectUpdates:     make(map[string]*structs.Allocation),
 		},
 	}
 }
@@ -301,10 +317,11 @@ func (a *allocReconciler) cancelDeployments() {
 func (a *allocReconciler) handleStop(m allocMatrix) {
 	for group, as := range m {
 		as = filterByTerminal(as)
-		untainted, migrate, lost := as.filterByTainted(a.taintedNodes)
+		untainted, migrate, lost, disconnecting, reconnecting, ignore := as.filterByTainted(a.taintedNodes, a.now)
 		a.markStop(untainted, "", allocNotNeeded)
 		a.markStop(migrate, "", allocNotNeeded)
-		a.markStop(lost, structs.AllocClientStatusLost, allocLost)
+		a.markStop(reconnecting, "", allocNotNeeded)
+		a.markStop(lost.union(disconnecting, ignore), structs.AllocClientStatusLost, allocLost)
 		desiredChanges := new(structs.DesiredUpdates)
 		desiredChanges.Stop = uint64(len(as))
 		a.result.desiredTGUpdates[group] = desiredChanges
@@ -350,11 +367,13 @@ func (a *allocReconciler) computeGroup(group string, all allocSet) bool {
 	// If the task group is nil, then the task group has been removed so all we
 	// need to do is stop everything
 	if tg == nil {
-		untainted, migrate, lost := all.filterByTainted(a.taintedNodes)
+		untainted, migrate, lost, disconnecting, reconnecting, ignore := all.filterByTainted(a.taintedNodes, a.now)
 		a.markStop(untainted, "", allocNotNeeded)
 		a.markStop(migrate, "", allocNotNeeded)
+		a.markStop(reconnecting, "", allocNotNeeded)
+		lost = lost.union(disconnecting, ignore)
 		a.markStop(lost, structs.AllocClientStatusLost, allocLost)
-		desiredChanges.Stop = uint64(len(untainted) + len(migrate) + len(lost))
+		desiredChanges.Stop = uint64(len(untainted) + len(migrate) + len(reconnecting) + len(lost))
 		return true
 	}
 
@@ -383,7 +402,29 @@ func (a *allocReconciler) computeGroup(group string, all allocSet) bool {
 	canaries, all := a.handleGroupCanaries(all, desiredChanges)
 
 	// Determine what set of allocations are on tainted nodes
-	untainted, migrate, lost := all.filterByTainted(a.taintedNodes)
+	untainted, migrate, lost, disconnecting, reconnecting, ignore := all.filterByTainted(a.taintedNodes, a.now)
+	desiredChanges.Ignore += uint64(len(ignore))
+
+	// Determine which of the reconnecting allocations and their replacements
+	// to keep. The kept allocations are considered untainted from now on.
+	if len(reconnecting) > 0 {
+		reconnected, stopOriginals, stopReplacements := a.computeReconnecting(reconnecting, all)
+		a.markStop(stopOriginals, "", allocNotNeeded)
+		a.markStop(stopReplacements, "", allocReconnected)
+		desiredChanges.Stop += uint64(len(stopOriginals) + len(stopReplacements))
+
+		stop := stopOriginals.union(stopReplacements)
+		untainted = untainted.difference(stop).union(reconnected)
+		migrate = migrate.difference(stop)
+		lost = lost.difference(stop)
+		disconnecting = disconnecting.difference(stop)
+	}
+
+	// Mark the allocations on disconnected clients as unknown and create
+	// follow up evaluations for when they exceed max_client_disconnect.
+	if len(disconnecting) > 0 {
+		a.computeDisconnecting(disconnecting, tg.Name)
+	}
 
 	// Determine what set of terminal allocations need to be rescheduled
 	untainted, rescheduleNow, rescheduleLater := untainted.filterByRescheduleable(a.batch, a.now, a.evalID, a.deployment)
@@ -399,7 +440,7 @@ func (a *allocReconciler) computeGroup(group string, all allocSet) bool {
 	// Create a structure for choosing names. Seed with the taken names
 	// which is the union of untainted, rescheduled, allocs on migrating
 	// nodes, and allocs on down nodes (includes canaries)
-	nameIndex := newAllocNameIndex(a.jobID, group, tg.Count, untainted.union(migrate, rescheduleNow, lost))
+	nameIndex := newAllocNameIndex(a.jobID, group, tg.Count, untainted.union(migrate, rescheduleNow, lost, disconnecting))
 
 	// Stop any unneeded allocations and update the untainted set to not
 	// include stopped allocations.
@@ -456,7 +497,9 @@ func (a *allocReconciler) computeGroup(group string, all allocSet) bool {
 	// * An alloc was lost
 	var place []allocPlaceResult
 	if len(lostLater) == 0 {
-		place = a.computePlacements(tg, nameIndex, untainted, migrate, rescheduleNow, canaryState, lost)
+		// Allocations on disconnected clients are replaced like lost ones,
+		// but they are not stopped in case their client reconnects.
+		place = a.computePlacements(tg, nameIndex, untainted, migrate, rescheduleNow, canaryState, lost.union(disconnecting))
 		if !existingDeployment {
 			dstate.DesiredTotal += len(place)
 		}
@@ -478,8 +521,8 @@ func (a *allocReconciler) computeGroup(group string, all allocSet) bool {
 		// We do not want to place additional allocations but in the case we
 		// have lost allocations or allocations that require rescheduling now,
 		// we do so regardless to avoid odd user experiences.
-		if len(lost) != 0 {
-			allowed := helper.IntMin(len(lost), len(place))
+		if replace := len(lost) + len(disconnecting); replace != 0 {
+			allowed := helper.IntMin(replace, len(place))
 			desiredChanges.Place += uint64(allowed)
 			a.result.place = append(a.result.place, place[:allowed]...)
 		}
@@ -509,7 +552,7 @@ func (a *allocReconciler) computeGroup(group string, all allocSet) bool {
 		min := helper.IntMin(len(destructive), limit)
 		desiredChanges.DestructiveUpdate += uint64(min)
 		desiredChanges.Ignore += uint64(len(destructive) - min)
-		for _, alloc := range destructive.nameOrder()[:min] {
+		for _, alloc := range a.destructiveUpdateOrder(tg, untainted, destructive, min) {
 			a.result.destructiveUpdate = append(a.result.destructiveUpdate, allocDestructiveResult{
 				placeName:             alloc.Name,
 				placeTaskGroup:        tg,
@@ -649,11 +692,12 @@ func (a *allocReconciler) handleGroupCanaries(all allocSet, desiredChanges *stru
 		}
 
 		canaries = all.fromKeys(canaryIDs)
-		untainted, migrate, lost := canaries.filterByTainted(a.taintedNodes)
+		untainted, migrate, lost, disconnecting, reconnecting, ignore := canaries.filterByTainted(a.taintedNodes, a.now)
 		a.markStop(migrate, "", allocMigrating)
+		lost = lost.union(disconnecting, ignore)
 		a.markStop(lost, structs.AllocClientStatusLost, allocLost)
 
-		canaries = untainted
+		canaries = untainted.union(reconnecting)
 		all = all.difference(migrate, lost)
 	}
 
@@ -836,6 +880,21 @@ func (a *allocReconciler) computeStop(group *structs.TaskGroup, nameIndex *alloc
 		}
 	}
 
+	// Stop allocs on the most used attribute values of hard spreads so the
+	// remaining allocs don't exceed the max skew
+	if spreads := hardSpreads(a.job, group); len(spreads) > 0 && a.nodeByID != nil {
+		for _, alloc := range a.hardSpreadStops(spreads, untainted, untainted, remove) {
+			stop[alloc.ID] = alloc
+			a.result.stop = append(a.result.stop, allocStopResult{
+				alloc:             alloc,
+				statusDescription: allocNotNeeded,
+			})
+			delete(untainted, alloc.ID)
+			nameIndex.UnsetIndex(alloc.Index())
+		}
+		return stop
+	}
+
 	// Select the allocs with the highest count to remove
 	removeNames := nameIndex.Highest(uint(remove))
 	for id, alloc := range untainted {
@@ -873,6 +932,84 @@ func (a *allocReconciler) computeStop(group *structs.TaskGroup, nameIndex *alloc
 	return stop
 }
 
+// hardSpreadStops returns the allocations of the candidates to stop to remove
+// the given number of running allocations from a task group with hard
+// spreads. Allocations are picked one at a time from the most used attribute
+// values among the running allocations, preferring allocations on nodes
+// missing the attributes and then those with the highest index. The
+// candidates must be a subset of the running allocations.
+func (a *allocReconciler) hardSpreadStops(spreads []*structs.Spread, running, candidateSet allocSet, remove int) []*structs.Allocation {
+	// Resolve the attribute values of the nodes of the allocations and count
+	// how many allocations use each value
+	candidates := make([]*structs.Allocation, 0, len(candidateSet))
+	values := make(map[string][]string, len(running))
+	counts := make([]map[string]int, len(spreads))
+	for i := range counts {
+		counts[i] = make(map[string]int)
+	}
+	for _, alloc := range running {
+		node := a.nodeByID(alloc.NodeID)
+		allocValues := make([]string, len(spreads))
+		for i, spread := range spreads {
+			if value, ok := getProperty(node, spread.Attribute); ok {
+				allocValues[i] = value
+				counts[i][value]++
+			}
+		}
+		values[alloc.ID] = allocValues
+		if _, ok := candidateSet[alloc.ID]; ok {
+			candidates = append(candidates, alloc)
+		}
+	}
+	sort.Slice(candidates, func(i, j int) bool {
+		return candidates[i].Index() > candidates[j].Index()
+	})
+
+	var stops []*structs.Allocation
+	for len(stops) < remove && len(candidates) > 0 {
+		best, bestScore := 0, -1
+		for i, alloc := range candidates {
+			score := 0
+			for s, value := range values[alloc.ID] {
+				if value == "" {
+					score += len(running) + 1
+				} else {
+					score += counts[s][value]
+				}
+			}
+			if score > bestScore {
+				best, bestScore = i, score
+			}
+		}
+
+		alloc := candidates[best]
+		for s, value := range values[alloc.ID] {
+			if value != "" {
+				counts[s][value]--
+			}
+		}
+		stops = append(stops, alloc)
+		candidates = append(candidates[:best], candidates[best+1:]...)
+	}
+
+	return stops
+}
+
+// destructiveUpdateOrder returns the given number of allocations to update
+// destructively. When the task group has hard spreads, allocations on the most
+// used attribute values of the running allocations are updated first, so that
+// the allocations left running while their replacements are placed don't
+// exceed the max skew. Allocations being rescheduled aren't running, so they
+// don't count towards the attribute values.
+func (a *allocReconciler) destructiveUpdateOrder(group *structs.TaskGroup, untainted, destructive allocSet, n int) []*structs.Allocation {
+	if n < len(destructive) && a.nodeByID != nil {
+		if spreads := hardSpreads(a.job, group); len(spreads) > 0 {
+			return a.hardSpreadStops(spreads, filterByTerminal(untainted).union(destructive), destructive, n)
+		}
+	}
+	return destructive.nameOrder()[:n]
+}
+
 // computeUpdates determines which allocations for the passed group require
 // updates. Three groups are returned:
 // 1. Those that require no upgrades
@@ -900,6 +1037,110 @@ func (a *allocReconciler) computeUpdates(group *structs.TaskGroup, untainted all
 	return
 }
 
+// computeReconnecting decides which of the allocations that reconnected after
+// their client was disconnected and their replacements to keep. Original
+// allocations that are still running or pending are kept, along with their
+// state, and the replacements placed while they were disconnected are
+// stopped. Originals that failed or stopped while disconnected, or that run
+// an older version of the job than one of their replacements, are stopped
+// instead and their replacements are kept.
+func (a *allocReconciler) computeReconnecting(reconnecting, all allocSet) (reconnected, stopOriginals, stopReplacements allocSet) {
+	reconnected = make(map[string]*structs.Allocation)
+	stopOriginals = make(map[string]*structs.Allocation)
+	stopReplacements = make(map[string]*structs.Allocation)
+
+	for _, alloc := range reconnecting {
+		replacements := make(map[string]*structs.Allocation)
+		for _, replacement := range all {
+			if replacement.ID == alloc.ID || replacement.ServerTerminalStatus() {
+				continue
+			}
+			if all.isReplacementOf(replacement, alloc.ID) {
+				replacements[replacement.ID] = replacement
+			}
+		}
+
+		if len(replacements) > 0 && !keepReconnected(alloc, replacements) {
+			stopOriginals[alloc.ID] = alloc
+			continue
+		}
+
+		// Record that the reconnected allocation has been reconciled so
+		// it's not considered reconnecting again. Terminal allocations are
+		// left to the rescheduling logic, which updates them itself.
+		if !alloc.ClientTerminalStatus() {
+			updated := alloc.Copy()
+			updated.AppendState(structs.AllocStateFieldClientStatus, alloc.ClientStatus)
+			a.result.reconnectUpdates[updated.ID] = updated
+		}
+		reconnected[alloc.ID] = alloc
+
+		for id, replacement := range replacements {
+			stopReplacements[id] = replacement
+		}
+	}
+
+	return reconnected, stopOriginals, stopReplacements
+}
+
+// keepReconnected returns whether the reconnected allocation should be kept
+// over its replacements. It's only kept if it's still running or pending
+// and isn't on an older version of the job than any of its replacements.
+func keepReconnected(alloc *structs.Allocation, replacements allocSet) bool {
+	if alloc.ClientTerminalStatus() || alloc.DesiredStatus != structs.AllocDesiredStatusRun {
+		return false
+	}
+	for _, replacement := range replacements {
+		if alloc.Job.Version < replacement.Job.Version {
+			return false
+		}
+	}
+	return true
+}
+
+// computeDisconnecting marks the allocations on disconnected clients as
+// unknown and creates follow up evaluations for when they exceed their task
+// group's max_client_disconnect.
+func (a *allocReconciler) computeDisconnecting(disconnecting allocSet, tgName string) {
+	var timeouts []*delayedRescheduleInfo
+	for _, alloc := range disconnecting {
+		timeouts = append(timeouts, &delayedRescheduleInfo{
+			allocID:        alloc.ID,
+			alloc:          alloc,
+			rescheduleTime: alloc.DisconnectTimeout(a.now),
+		})
+	}
+
+	sort.Slice(timeouts, func(i, j int) bool {
+		return timeouts[i].rescheduleTime.Before(timeouts[j].rescheduleTime)
+	})
+
+	var eval *structs.Evaluation
+	for _, timeout := range timeouts {
+		// Batch allocations that time out close to each other
+		if eval == nil || timeout.rescheduleTime.Sub(eval.WaitUntil) >= batchedFailedAllocWindowSize {
+			eval = &structs.Evaluation{
+				ID:                uuid.Generate(),
+				Namespace:         a.job.Namespace,
+				Priority:          a.job.Priority,
+				Type:              a.job.Type,
+				TriggeredBy:       structs.EvalTriggerMaxDisconnectTimeout,
+				JobID:             a.job.ID,
+				JobModifyIndex:    a.job.ModifyIndex,
+				Status:            structs.EvalStatusPending,
+				StatusDescription: disconnectTimeoutFollowupEvalDesc,
+				WaitUntil:         timeout.rescheduleTime,
+			}
+			a.result.desiredFollowupEvals[tgName] = append(a.result.desiredFollowupEvals[tgName], eval)
+		}
+
+		updated := timeout.alloc.Copy()
+		updated.ClientDescription = allocUnknown
+		updated.FollowupEvalID = eval.ID
+		a.result.disconnectUpdates[updated.ID] = updated
+	}
+}
+
 // handleDelayedReschedules creates batched followup evaluations with the WaitUntil field
 // set for allocations that are eligible to be rescheduled later, and marks the alloc with
 // the followupEvalID
@@ -977,7 +1218,7 @@ func (a *allocReconciler) handleDelayedLost(rescheduleLater []*delayedReschedule
 		}
 	}
 
-	a.result.desiredFollowupEvals[tgName] = evals
+	a.result.desiredFollowupEvals[tgName] = append(a.result.desiredFollowupEvals[tgName], evals...)
 
 	return allocIDToFollowupEvalID
 }
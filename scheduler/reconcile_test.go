@@ -256,6 +256,8 @@ type resultExpectation struct {
 	destructive       int
 	inplace           int
 	attributeUpdates  int
+	disconnectUpdates int
+	reconnectUpdates  int
 	stop              int
 	desiredTGUpdates  map[string]*structs.DesiredUpdates
 }
@@ -282,6 +284,8 @@ func assertResults(t *testing.T, r *reconcileResults, exp *resultExpectation) {
 	assert.Len(r.destructiveUpdate, exp.destructive, "Expected Destructive")
 	assert.Len(r.inplaceUpdate, exp.inplace, "Expected Inplace Updates")
 	assert.Len(r.attributeUpdates, exp.attributeUpdates, "Expected Attribute Updates")
+	assert.Len(r.disconnectUpdates, exp.disconnectUpdates, "Expected Disconnect Updates")
+	assert.Len(r.reconnectUpdates, exp.reconnectUpdates, "Expected Reconnect Updates")
 	assert.Len(r.stop, exp.stop, "Expected Stops")
 	assert.EqualValues(exp.desiredTGUpdates, r.desiredTGUpdates, "Expected Desired TG Update Annotations")
 }
@@ -385,6 +389,193 @@ func TestReconciler_ScaleDown_Partial(t *testing.T) {
 	assertNamesHaveIndexes(t, intRange(10, 19), stopResultsToNames(r.stop))
 }
 
+// Tests the reconciler properly handles stopping allocations for a job that has
+// scaled down and has a hard spread, by stopping allocations from the most used
+// attribute values
+func TestReconciler_ScaleDown_HardSpread(t *testing.T) {
+	job := mock.Job()
+	job.TaskGroups[0].Count = 4
+	job.TaskGroups[0].Spreads = []*structs.Spread{
+		{
+			Attribute: "${node.datacenter}",
+			Weight:    50,
+			MaxSkew:   1,
+			Hard:      true,
+		},
+	}
+
+	// Create 6 existing allocations, 4 in dc1 and 2 in dc2
+	nodes := make(map[string]*structs.Node)
+	var allocs []*structs.Allocation
+	for i := 0; i < 6; i++ {
+		node := mock.Node()
+		if i >= 4 {
+			node.Datacenter = "dc2"
+		}
+		nodes[node.ID] = node
+
+		alloc := mock.Alloc()
+		alloc.Job = job
+		alloc.JobID = job.ID
+		alloc.NodeID = node.ID
+		alloc.Name = structs.AllocName(job.ID, job.TaskGroups[0].Name, uint(i))
+		allocs = append(allocs, alloc)
+	}
+
+	reconciler := NewAllocReconciler(testlog.HCLogger(t), allocUpdateFnIgnore, false, job.ID, job, nil, allocs, nil, "")
+	reconciler.nodeByID = func(nodeID string) *structs.Node {
+		return nodes[nodeID]
+	}
+	r := reconciler.Compute()
+
+	// Assert the correct results
+	assertResults(t, r, &resultExpectation{
+		createDeployment:  nil,
+		deploymentUpdates: nil,
+		place:             0,
+		inplace:           0,
+		stop:              2,
+		desiredTGUpdates: map[string]*structs.DesiredUpdates{
+			job.TaskGroups[0].Name: {
+				Ignore: 4,
+				Stop:   2,
+			},
+		},
+	})
+
+	// The highest indexes in dc1 are stopped, leaving 2 allocations per
+	// datacenter
+	assertNamesHaveIndexes(t, intRange(2, 3), stopResultsToNames(r.stop))
+}
+
+// hardSpreadAllocs returns existing allocations of the job, with the
+// allocations of the given indexes on nodes in dc2 and the others on nodes in
+// dc1, along with the nodes by ID.
+func hardSpreadAllocs(job *structs.Job, count int, dc2 ...int) ([]*structs.Allocation, map[string]*structs.Node) {
+	nodes := make(map[string]*structs.Node)
+	var allocs []*structs.Allocation
+	for i := 0; i < count; i++ {
+		node := mock.Node()
+		for _, idx := range dc2 {
+			if i == idx {
+				node.Datacenter = "dc2"
+			}
+		}
+		nodes[node.ID] = node
+
+		alloc := mock.Alloc()
+		alloc.Job = job
+		alloc.JobID = job.ID
+		alloc.NodeID = node.ID
+		alloc.Name = structs.AllocName(job.ID, job.TaskGroups[0].Name, uint(i))
+		alloc.TaskGroup = job.TaskGroups[0].Name
+		allocs = append(allocs, alloc)
+	}
+	return allocs, nodes
+}
+
+// Tests the reconciler picks the allocations to destructively update from the
+// most used attribute values of a hard spread, so the allocations left
+// running during the update don't exceed the max skew
+func TestReconciler_DestructiveMaxParallel_HardSpread(t *testing.T) {
+	job := mock.Job()
+	job.TaskGroups[0].Count = 6
+	job.TaskGroups[0].Update = noCanaryUpdate.Copy()
+	job.TaskGroups[0].Update.MaxParallel = 2
+	job.TaskGroups[0].Spreads = []*structs.Spread{
+		{
+			Attribute: "${node.datacenter}",
+			Weight:    50,
+			MaxSkew:   1,
+			Hard:      true,
+		},
+	}
+
+	// The lowest indexes are in dc2, so updating in name order would leave
+	// 4 allocations running in dc1 and none in dc2
+	allocs, nodes := hardSpreadAllocs(job, 6, 0, 1)
+
+	reconciler := NewAllocReconciler(testlog.HCLogger(t), allocUpdateFnDestructive, false, job.ID, job, nil, allocs, nil, "")
+	reconciler.nodeByID = func(nodeID string) *structs.Node {
+		return nodes[nodeID]
+	}
+	r := reconciler.Compute()
+
+	d := structs.NewDeployment(job)
+	d.TaskGroups[job.TaskGroups[0].Name] = &structs.DeploymentState{
+		DesiredTotal: 6,
+	}
+
+	assertResults(t, r, &resultExpectation{
+		createDeployment:  d,
+		deploymentUpdates: nil,
+		destructive:       2,
+		desiredTGUpdates: map[string]*structs.DesiredUpdates{
+			job.TaskGroups[0].Name: {
+				DestructiveUpdate: 2,
+				Ignore:            4,
+			},
+		},
+	})
+
+	// The highest indexes in dc1 are updated, leaving 2 allocations running
+	// per datacenter
+	assertNamesHaveIndexes(t, intRange(4, 5), destructiveResultsToNames(r.destructiveUpdate))
+}
+
+// Tests the reconciler doesn't count allocations being rescheduled towards
+// the attribute values of a hard spread when picking the allocations to
+// destructively update in the same pass
+func TestReconciler_RescheduleNow_DestructiveMaxParallel_HardSpread(t *testing.T) {
+	job := mock.Job()
+	job.TaskGroups[0].Count = 6
+	job.TaskGroups[0].Update = noCanaryUpdate.Copy()
+	job.TaskGroups[0].Update.MaxParallel = 2
+	job.TaskGroups[0].ReschedulePolicy = &structs.ReschedulePolicy{
+		Attempts:  1,
+		Interval:  24 * time.Hour,
+		Delay:     5 * time.Second,
+		MaxDelay:  1 * time.Hour,
+		Unlimited: false,
+	}
+	job.TaskGroups[0].Spreads = []*structs.Spread{
+		{
+			Attribute: "${node.datacenter}",
+			Weight:    50,
+			MaxSkew:   1,
+			Hard:      true,
+		},
+	}
+
+	// 3 allocations in each datacenter, one of which failed in dc2
+	allocs, nodes := hardSpreadAllocs(job, 6, 3, 4, 5)
+	for _, alloc := range allocs {
+		alloc.ClientStatus = structs.AllocClientStatusRunning
+	}
+	now := time.Now()
+	allocs[5].ClientStatus = structs.AllocClientStatusFailed
+	allocs[5].TaskStates = map[string]*structs.TaskState{job.TaskGroups[0].Name: {
+		State:      "dead",
+		StartedAt:  now.Add(-1 * time.Hour),
+		FinishedAt: now.Add(-10 * time.Second),
+	}}
+
+	reconciler := NewAllocReconciler(testlog.HCLogger(t), allocUpdateFnDestructive, false, job.ID, job, nil, allocs, nil, "")
+	reconciler.nodeByID = func(nodeID string) *structs.Node {
+		return nodes[nodeID]
+	}
+	r := reconciler.Compute()
+
+	// The failed allocation is rescheduled, which uses up one of the
+	// parallel updates
+	require.Len(t, r.place, 1)
+	require.True(t, r.place[0].IsRescheduling())
+	require.Equal(t, allocs[5].ID, r.place[0].PreviousAllocation().ID)
+
+	// dc1 has the most running allocations, so its highest index is updated
+	assertNamesHaveIndexes(t, intRange(2, 2), destructiveResultsToNames(r.destructiveUpdate))
+}
+
 // Tests the reconciler properly handles stopping allocations for a job that has
 // scaled down to zero desired
 func TestReconciler_ScaleDown_Zero(t *testing.T) {
@@ -837,6 +1028,271 @@ func TestReconciler_LostNode(t *testing.T) {
 	assertNamesHaveIndexes(t, intRange(0, 1), placeResultsToNames(r.place))
 }
 
+// Tests the reconciler marks allocations on disconnected nodes as unknown and
+// replaces them without stopping them
+func TestReconciler_DisconnectedNode(t *testing.T) {
+	job := mock.Job()
+	job.TaskGroups[0].Count = 3
+	job.TaskGroups[0].MaxClientDisconnect = helper.TimeToPtr(5 * time.Minute)
+
+	// Create 3 existing running allocations
+	var allocs []*structs.Allocation
+	for i := 0; i < 3; i++ {
+		alloc := mock.Alloc()
+		alloc.Job = job
+		alloc.JobID = job.ID
+		alloc.NodeID = uuid.Generate()
+		alloc.Name = structs.AllocName(job.ID, job.TaskGroups[0].Name, uint(i))
+		alloc.ClientStatus = structs.AllocClientStatusRunning
+		allocs = append(allocs, alloc)
+	}
+
+	// Disconnect the node of the first allocation
+	n := mock.Node()
+	n.ID = allocs[0].NodeID
+	n.Status = structs.NodeStatusDisconnected
+	tainted := map[string]*structs.Node{n.ID: n}
+
+	reconciler := NewAllocReconciler(testlog.HCLogger(t), allocUpdateFnIgnore, false, job.ID, job, nil, allocs, tainted, "")
+	r := reconciler.Compute()
+
+	// Assert the correct results
+	assertResults(t, r, &resultExpectation{
+		createDeployment:  nil,
+		deploymentUpdates: nil,
+		place:             1,
+		disconnectUpdates: 1,
+		stop:              0,
+		desiredTGUpdates: map[string]*structs.DesiredUpdates{
+			job.TaskGroups[0].Name: {
+				Place:  1,
+				Ignore: 2,
+			},
+		},
+	})
+
+	assertNamesHaveIndexes(t, intRange(0, 0), placeResultsToNames(r.place))
+	require.Equal(t, allocs[0].ID, r.place[0].PreviousAllocation().ID)
+
+	// A follow up evaluation is created for the disconnect timeout
+	evals := r.desiredFollowupEvals[job.TaskGroups[0].Name]
+	require.Len(t, evals, 1)
+	require.Equal(t, structs.EvalTriggerMaxDisconnectTimeout, evals[0].TriggeredBy)
+
+	unknown := r.disconnectUpdates[allocs[0].ID]
+	require.NotNil(t, unknown)
+	require.Equal(t, evals[0].ID, unknown.FollowupEvalID)
+}
+
+// Tests the reconciler keeps an allocation that reconnected after its node was
+// disconnected and stops its replacement
+func TestReconciler_ReconnectedNode(t *testing.T) {
+	job := mock.Job()
+	job.TaskGroups[0].Count = 3
+	job.TaskGroups[0].MaxClientDisconnect = helper.TimeToPtr(5 * time.Minute)
+
+	// Create 3 existing running allocations
+	var allocs []*structs.Allocation
+	for i := 0; i < 3; i++ {
+		alloc := mock.Alloc()
+		alloc.Job = job
+		alloc.JobID = job.ID
+		alloc.NodeID = uuid.Generate()
+		alloc.Name = structs.AllocName(job.ID, job.TaskGroups[0].Name, uint(i))
+		alloc.ClientStatus = structs.AllocClientStatusRunning
+		allocs = append(allocs, alloc)
+	}
+
+	// The first allocation was unknown and its client reported it running
+	// after reconnecting
+	allocs[0].AllocStates = []*structs.AllocState{{
+		Field: structs.AllocStateFieldClientStatus,
+		Value: structs.AllocClientStatusUnknown,
+		Time:  time.Now().Add(-1 * time.Minute),
+	}}
+
+	// Create the replacement placed while it was disconnected
+	replacement := allocs[0].Copy()
+	replacement.ID = uuid.Generate()
+	replacement.NodeID = uuid.Generate()
+	replacement.PreviousAllocation = allocs[0].ID
+	replacement.AllocStates = nil
+	allocs = append(allocs, replacement)
+
+	reconciler := NewAllocReconciler(testlog.HCLogger(t), allocUpdateFnIgnore, false, job.ID, job, nil, allocs, nil, "")
+	r := reconciler.Compute()
+
+	// Assert the correct results
+	assertResults(t, r, &resultExpectation{
+		createDeployment:  nil,
+		deploymentUpdates: nil,
+		place:             0,
+		reconnectUpdates:  1,
+		stop:              1,
+		desiredTGUpdates: map[string]*structs.DesiredUpdates{
+			job.TaskGroups[0].Name: {
+				Stop:   1,
+				Ignore: 3,
+			},
+		},
+	})
+
+	require.Equal(t, replacement.ID, r.stop[0].alloc.ID)
+	reconnected := r.reconnectUpdates[allocs[0].ID]
+	require.NotNil(t, reconnected)
+	require.False(t, reconnected.NeedsToReconcile())
+}
+
+// reconnectTestAllocs returns the running allocations of the job, the first
+// of which reconnected after being marked unknown, along with the
+// replacement placed for it while it was disconnected.
+func reconnectTestAllocs(job *structs.Job) ([]*structs.Allocation, *structs.Allocation) {
+	var allocs []*structs.Allocation
+	for i := 0; i < 3; i++ {
+		alloc := mock.Alloc()
+		alloc.Job = job
+		alloc.JobID = job.ID
+		alloc.NodeID = uuid.Generate()
+		alloc.Name = structs.AllocName(job.ID, job.TaskGroups[0].Name, uint(i))
+		alloc.ClientStatus = structs.AllocClientStatusRunning
+		allocs = append(allocs, alloc)
+	}
+
+	allocs[0].AllocStates = []*structs.AllocState{{
+		Field: structs.AllocStateFieldClientStatus,
+		Value: structs.AllocClientStatusUnknown,
+		Time:  time.Now().Add(-1 * time.Minute),
+	}}
+
+	replacement := allocs[0].Copy()
+	replacement.ID = uuid.Generate()
+	replacement.NodeID = uuid.Generate()
+	replacement.PreviousAllocation = allocs[0].ID
+	replacement.AllocStates = nil
+	allocs = append(allocs, replacement)
+
+	return allocs, replacement
+}
+
+// Tests the reconciler stops an allocation that failed while its node was
+// disconnected and keeps its replacement
+func TestReconciler_ReconnectedNode_Failed(t *testing.T) {
+	job := mock.Job()
+	job.TaskGroups[0].Count = 3
+	job.TaskGroups[0].MaxClientDisconnect = helper.TimeToPtr(5 * time.Minute)
+
+	allocs, _ := reconnectTestAllocs(job)
+	allocs[0].ClientStatus = structs.AllocClientStatusFailed
+
+	reconciler := NewAllocReconciler(testlog.HCLogger(t), allocUpdateFnIgnore, false, job.ID, job, nil, allocs, nil, "")
+	r := reconciler.Compute()
+
+	// Assert the correct results
+	assertResults(t, r, &resultExpectation{
+		createDeployment:  nil,
+		deploymentUpdates: nil,
+		place:             0,
+		reconnectUpdates:  0,
+		stop:              1,
+		desiredTGUpdates: map[string]*structs.DesiredUpdates{
+			job.TaskGroups[0].Name: {
+				Stop:   1,
+				Ignore: 3,
+			},
+		},
+	})
+
+	require.Equal(t, allocs[0].ID, r.stop[0].alloc.ID)
+	require.Equal(t, allocNotNeeded, r.stop[0].statusDescription)
+}
+
+// Tests the reconciler reschedules an allocation that failed while its node
+// was disconnected if it has no replacement
+func TestReconciler_ReconnectedNode_FailedNoReplacement(t *testing.T) {
+	job := mock.Job()
+	job.TaskGroups[0].Count = 3
+	job.TaskGroups[0].MaxClientDisconnect = helper.TimeToPtr(5 * time.Minute)
+	job.TaskGroups[0].ReschedulePolicy = &structs.ReschedulePolicy{
+		Attempts:      1,
+		Interval:      24 * time.Hour,
+		Delay:         5 * time.Second,
+		DelayFunction: "constant",
+	}
+
+	allocs, _ := reconnectTestAllocs(job)
+	allocs = allocs[:3]
+	allocs[0].ClientStatus = structs.AllocClientStatusFailed
+	allocs[0].TaskStates = map[string]*structs.TaskState{"web": {
+		State:      "dead",
+		StartedAt:  time.Now().Add(-1 * time.Hour),
+		FinishedAt: time.Now().Add(-10 * time.Second),
+	}}
+
+	reconciler := NewAllocReconciler(testlog.HCLogger(t), allocUpdateFnIgnore, false, job.ID, job, nil, allocs, nil, "")
+	r := reconciler.Compute()
+
+	// Assert the correct results
+	assertResults(t, r, &resultExpectation{
+		createDeployment:  nil,
+		deploymentUpdates: nil,
+		place:             1,
+		reconnectUpdates:  0,
+		stop:              1,
+		desiredTGUpdates: map[string]*structs.DesiredUpdates{
+			job.TaskGroups[0].Name: {
+				Place:  1,
+				Stop:   1,
+				Ignore: 2,
+			},
+		},
+	})
+
+	require.Equal(t, allocRescheduled, r.stop[0].statusDescription)
+	require.Equal(t, allocs[0].ID, r.place[0].PreviousAllocation().ID)
+	require.True(t, r.place[0].IsRescheduling())
+}
+
+// Tests the reconciler stops an allocation that reconnected on an older
+// version of the job than its replacement and keeps the replacement
+func TestReconciler_ReconnectedNode_OlderJobVersion(t *testing.T) {
+	job := mock.Job()
+	job.TaskGroups[0].Count = 3
+	job.TaskGroups[0].MaxClientDisconnect = helper.TimeToPtr(5 * time.Minute)
+
+	allocs, replacement := reconnectTestAllocs(job)
+
+	// The job was updated while the node was disconnected and the
+	// replacement placed with the new version
+	newJob := job.Copy()
+	newJob.Version++
+	newJob.JobModifyIndex++
+	replacement.Job = newJob
+	for _, alloc := range allocs[1:3] {
+		alloc.Job = newJob
+	}
+
+	reconciler := NewAllocReconciler(testlog.HCLogger(t), allocUpdateFnIgnore, false, newJob.ID, newJob, nil, allocs, nil, "")
+	r := reconciler.Compute()
+
+	// Assert the correct results
+	assertResults(t, r, &resultExpectation{
+		createDeployment:  nil,
+		deploymentUpdates: nil,
+		place:             0,
+		reconnectUpdates:  0,
+		stop:              1,
+		desiredTGUpdates: map[string]*structs.DesiredUpdates{
+			newJob.TaskGroups[0].Name: {
+				Stop:   1,
+				Ignore: 3,
+			},
+		},
+	})
+
+	require.Equal(t, allocs[0].ID, r.stop[0].alloc.ID)
+	require.Equal(t, allocNotNeeded, r.stop[0].statusDescription)
+}
+
 // Tests the reconciler properly handles lost nodes with allocations while
 // scaling up
 func TestReconciler_LostNode_ScaleUp(t *testing.T) {
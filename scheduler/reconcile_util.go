@@ -210,15 +210,32 @@ func (a allocSet) fromKeys(keys ...[]string) allocSet {
 }
 
 // filterByTainted takes a set of tainted nodes and filters the allocation set
-// into three groups:
+// into the following groups:
 // 1. Those that exist on untainted nodes
 // 2. Those exist on nodes that are draining
 // 3. Those that exist on lost nodes
-func (a allocSet) filterByTainted(nodes map[string]*structs.Node) (untainted, migrate, lost allocSet) {
+// 4. Those that are on nodes that are disconnected, but have not had their ClientState set to unknown
+// 5. Those that have had their ClientState set to unknown, but their node has reconnected.
+// 6. Those that are in a state that the scheduler should ignore, like unknown
+// allocations waiting for their client to reconnect.
+func (a allocSet) filterByTainted(nodes map[string]*structs.Node, now time.Time) (untainted, migrate, lost, disconnecting, reconnecting, ignore allocSet) {
 	untainted = make(map[string]*structs.Allocation)
 	migrate = make(map[string]*structs.Allocation)
 	lost = make(map[string]*structs.Allocation)
+	disconnecting = make(map[string]*structs.Allocation)
+	reconnecting = make(map[string]*structs.Allocation)
+	ignore = make(map[string]*structs.Allocation)
+
 	for _, alloc := range a {
+		// Allocs that failed or completed while their client was
+		// disconnected must be reconciled against their replacements
+		if alloc.ClientTerminalStatus() &&
+			alloc.DesiredStatus == structs.AllocDesiredStatusRun &&
+			alloc.NeedsToReconcile() {
+			reconnecting[alloc.ID] = alloc
+			continue
+		}
+
 		// Terminal allocs are always untainted as they should never be migrated
 		if alloc.TerminalStatus() {
 			untainted[alloc.ID] = alloc
@@ -231,25 +248,83 @@ func (a allocSet) filterByTainted(nodes map[string]*structs.Node) (untainted, mi
 			continue
 		}
 
-		n, ok := nodes[alloc.NodeID]
-		if !ok {
-			// Node is untainted so alloc is untainted
+		taintedNode, nodeIsTainted := nodes[alloc.NodeID]
+
+		// Unknown allocs are waiting for their client to reconnect. They are
+		// lost once they exceed max_client_disconnect or if their node is
+		// gone, otherwise they are ignored since a replacement was already
+		// placed.
+		if alloc.ClientStatus == structs.AllocClientStatusUnknown {
+			if alloc.Expired(now) ||
+				(nodeIsTainted && (taintedNode == nil || taintedNode.TerminalStatus())) {
+				lost[alloc.ID] = alloc
+				continue
+			}
+			ignore[alloc.ID] = alloc
+			continue
+		}
+
+		if !nodeIsTainted {
+			// Node is untainted so alloc is untainted, unless it reconnected
+			// after being marked unknown.
+			if alloc.NeedsToReconcile() {
+				reconnecting[alloc.ID] = alloc
+				continue
+			}
 			untainted[alloc.ID] = alloc
 			continue
 		}
 
 		// Allocs on GC'd (nil) or lost nodes are Lost
-		if n == nil || n.TerminalStatus() {
+		if taintedNode == nil || taintedNode.TerminalStatus() {
+			lost[alloc.ID] = alloc
+			continue
+		}
+
+		// Running allocs on disconnected nodes that support disconnected
+		// clients are marked unknown, all others are Lost
+		if taintedNode.Status == structs.NodeStatusDisconnected {
+			if alloc.SupportsDisconnectedClients() &&
+				alloc.ClientStatus == structs.AllocClientStatusRunning {
+				disconnecting[alloc.ID] = alloc
+				continue
+			}
 			lost[alloc.ID] = alloc
 			continue
 		}
 
 		// All other allocs are untainted
+		if alloc.NeedsToReconcile() {
+			reconnecting[alloc.ID] = alloc
+			continue
+		}
 		untainted[alloc.ID] = alloc
 	}
 	return
 }
 
+// isReplacementOf returns true if the allocation replaced the allocation with
+// the given ID, directly or through a chain of replacements within the set.
+func (a allocSet) isReplacementOf(alloc *structs.Allocation, originalID string) bool {
+	seen := make(map[string]struct{})
+	for prev := alloc.PreviousAllocation; prev != ""; {
+		if prev == originalID {
+			return true
+		}
+		if _, ok := seen[prev]; ok {
+			return false
+		}
+		seen[prev] = struct{}{}
+
+		prevAlloc, ok := a[prev]
+		if !ok {
+			return false
+		}
+		prev = prevAlloc.PreviousAllocation
+	}
+	return false
+}
+
 // filterByRescheduleable filters the allocation set to return the set of allocations that are either
 // untainted or a set of allocations that must be rescheduled now. Allocations that can be rescheduled
 // at a future time are also returned so that we can create follow up evaluations for them. Allocs are
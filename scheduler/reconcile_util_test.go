@@ -2,6 +2,7 @@ package scheduler
 
 import (
 	"testing"
+	"time"
 
 	"github.com/hashicorp/nomad/helper"
 	"github.com/hashicorp/nomad/nomad/mock"
@@ -46,6 +47,10 @@ func TestAllocSet_filterByTainted(t *testing.T) {
 			Status: structs.NodeStatusDown,
 		},
 		"nil": nil,
+		"disconnected": {
+			ID:     "disconnected",
+			Status: structs.NodeStatusDisconnected,
+		},
 		"normal": {
 			ID:     "normal",
 			Status: structs.NodeStatusReady,
@@ -56,6 +61,18 @@ func TestAllocSet_filterByTainted(t *testing.T) {
 		Type: structs.JobTypeBatch,
 	}
 
+	now := time.Now().UTC()
+	disconnectJob := mock.Job()
+	disconnectJob.TaskGroups[0].MaxClientDisconnect = helper.TimeToPtr(5 * time.Minute)
+	tgName := disconnectJob.TaskGroups[0].Name
+	unknownState := func(at time.Time) []*structs.AllocState {
+		return []*structs.AllocState{{
+			Field: structs.AllocStateFieldClientStatus,
+			Value: structs.AllocClientStatusUnknown,
+			Time:  at,
+		}}
+	}
+
 	allocs := allocSet{
 		// Non-terminal alloc with migrate=true should migrate on a draining node
 		"migrating1": {
@@ -114,9 +131,54 @@ func TestAllocSet_filterByTainted(t *testing.T) {
 			Job:          batchJob,
 			NodeID:       "lost",
 		},
+		// Running allocs on disconnected nodes without max_client_disconnect
+		// are lost
+		"lost3": {
+			ID:           "lost3",
+			ClientStatus: structs.AllocClientStatusRunning,
+			Job:          batchJob,
+			NodeID:       "disconnected",
+		},
+		// Unknown allocs that exceeded max_client_disconnect are lost
+		"lost4": {
+			ID:           "lost4",
+			ClientStatus: structs.AllocClientStatusUnknown,
+			Job:          disconnectJob,
+			TaskGroup:    tgName,
+			NodeID:       "disconnected",
+			AllocStates:  unknownState(now.Add(-10 * time.Minute)),
+		},
+		// Running allocs on disconnected nodes with max_client_disconnect are
+		// disconnecting
+		"disconnecting1": {
+			ID:           "disconnecting1",
+			ClientStatus: structs.AllocClientStatusRunning,
+			Job:          disconnectJob,
+			TaskGroup:    tgName,
+			NodeID:       "disconnected",
+		},
+		// Unknown allocs within max_client_disconnect are ignored
+		"ignore1": {
+			ID:           "ignore1",
+			ClientStatus: structs.AllocClientStatusUnknown,
+			Job:          disconnectJob,
+			TaskGroup:    tgName,
+			NodeID:       "disconnected",
+			AllocStates:  unknownState(now.Add(-1 * time.Minute)),
+		},
+		// Allocs that were unknown and reported a new status after their
+		// node reconnected are reconnecting
+		"reconnecting1": {
+			ID:           "reconnecting1",
+			ClientStatus: structs.AllocClientStatusRunning,
+			Job:          disconnectJob,
+			TaskGroup:    tgName,
+			NodeID:       "normal",
+			AllocStates:  unknownState(now.Add(-1 * time.Minute)),
+		},
 	}
 
-	untainted, migrate, lost := allocs.filterByTainted(nodes)
+	untainted, migrate, lost, disconnecting, reconnecting, ignore := allocs.filterByTainted(nodes, now)
 	require.Len(untainted, 4)
 	require.Contains(untainted, "untainted1")
 	require.Contains(untainted, "untainted2")
@@ -125,7 +187,15 @@ func TestAllocSet_filterByTainted(t *testing.T) {
 	require.Len(migrate, 2)
 	require.Contains(migrate, "migrating1")
 	require.Contains(migrate, "migrating2")
-	require.Len(lost, 2)
+	require.Len(lost, 4)
 	require.Contains(lost, "lost1")
 	require.Contains(lost, "lost2")
+	require.Contains(lost, "lost3")
+	require.Contains(lost, "lost4")
+	require.Len(disconnecting, 1)
+	require.Contains(disconnecting, "disconnecting1")
+	require.Len(reconnecting, 1)
+	require.Contains(reconnecting, "reconnecting1")
+	require.Len(ignore, 1)
+	require.Contains(ignore, "ignore1")
 }
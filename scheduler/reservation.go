@@ -0,0 +1,98 @@
+package scheduler
+
+import (
+	"fmt"
+
+	log "github.com/hashicorp/go-hclog"
+	"github.com/hashicorp/nomad/helper/uuid"
+	"github.com/hashicorp/nomad/nomad/structs"
+)
+
+// PlaceReservation selects the nodes the instances of the reservation are
+// placed on, returning one node ID per instance. Instances are placed on the
+// ready nodes of the reservation's datacenters and node pool that satisfy its
+// constraints and have enough capacity left, accounting for the allocations
+// and other reservations already on them.
+func PlaceReservation(state State, reservation *structs.Reservation, logger log.Logger) ([]string, error) {
+	job := reservationJob(reservation)
+	tg := job.TaskGroups[0]
+
+	nodes, _, _, err := readyNodesInDCs(state, job.Datacenters, job.NodePool)
+	if err != nil {
+		return nil, fmt.Errorf("failed to get ready nodes: %v", err)
+	}
+
+	plan := &structs.Plan{
+		NodeAllocation: make(map[string][]*structs.Allocation),
+	}
+	ctx := NewEvalContext(state, plan, logger)
+	ctx.Eligibility().SetJob(job)
+
+	// Build a minimal stack, since the instances have no drivers, devices
+	// or networks to check
+	source := NewRandomIterator(ctx, nodes)
+	feasible := NewFeasibilityWrapper(ctx, source,
+		[]FeasibilityChecker{NewConstraintChecker(ctx, job.Constraints)}, nil, nil)
+	feasible.SetTaskGroup(tg.Name)
+	binPack := NewBinPackIterator(ctx, NewFeasibleRankIterator(ctx, feasible), false, 0,
+		nodePoolSchedulerConfig(ctx, job))
+	binPack.SetJob(job)
+	binPack.SetTaskGroup(tg)
+	maxScore := NewMaxScoreIterator(ctx, NewScoreNormalizationIterator(ctx, binPack))
+
+	nodeIDs := make([]string, 0, reservation.Count)
+	for i := 0; i < reservation.Count; i++ {
+		maxScore.Reset()
+		ctx.Reset()
+
+		option := maxScore.Next()
+		if option == nil {
+			metrics := ctx.Metrics()
+			return nil, fmt.Errorf("only %d of %d instances could be placed: %d nodes evaluated, %d filtered, %d exhausted",
+				i, reservation.Count, metrics.NodesEvaluated, metrics.NodesFiltered, metrics.NodesExhausted)
+		}
+
+		// Add the instance to the plan so the following instances account
+		// for it
+		alloc := &structs.Allocation{
+			ID:                 uuid.Generate(),
+			Namespace:          job.Namespace,
+			NodeID:             option.Node.ID,
+			JobID:              job.ID,
+			TaskGroup:          tg.Name,
+			AllocatedResources: reservation.AllocatedResources(),
+			DesiredStatus:      structs.AllocDesiredStatusRun,
+			ClientStatus:       structs.AllocClientStatusPending,
+		}
+		plan.AppendAlloc(alloc, nil)
+		nodeIDs = append(nodeIDs, option.Node.ID)
+	}
+
+	return nodeIDs, nil
+}
+
+// reservationJob returns a job with a single task group whose allocations
+// require the resources of an instance of the reservation.
+func reservationJob(reservation *structs.Reservation) *structs.Job {
+	return &structs.Job{
+		ID:          reservation.Name,
+		Namespace:   reservation.Namespace,
+		Datacenters: reservation.Datacenters,
+		NodePool:    reservation.NodePool,
+		Constraints: reservation.Constraints,
+		TaskGroups: []*structs.TaskGroup{{
+			Name:  reservation.Name,
+			Count: reservation.Count,
+			EphemeralDisk: &structs.EphemeralDisk{
+				SizeMB: reservation.Resources.DiskMB,
+			},
+			Tasks: []*structs.Task{{
+				Name: structs.ReservationTaskName,
+				Resources: &structs.Resources{
+					CPU:      reservation.Resources.CPU,
+					MemoryMB: reservation.Resources.MemoryMB,
+				},
+			}},
+		}},
+	}
+}
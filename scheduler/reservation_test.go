@@ -0,0 +1,58 @@
+package scheduler
+
+import (
+	"testing"
+
+	"github.com/hashicorp/nomad/helper/testlog"
+	"github.com/hashicorp/nomad/nomad/mock"
+	"github.com/hashicorp/nomad/nomad/state"
+	"github.com/hashicorp/nomad/nomad/structs"
+	"github.com/stretchr/testify/require"
+)
+
+func TestPlaceReservation(t *testing.T) {
+	t.Parallel()
+
+	store := state.TestStateStore(t)
+
+	// Each node fits two instances
+	var nodes []*structs.Node
+	for i := 0; i < 3; i++ {
+		node := mock.Node()
+		node.NodeResources.Cpu.CpuShares = 1000
+		node.Reserved = nil
+		node.ReservedResources = nil
+		require.NoError(t, store.UpsertNode(structs.MsgTypeTestSetup, uint64(1000+i), node))
+		nodes = append(nodes, node)
+	}
+
+	// Exclude the last node with a constraint
+	reservation := mock.Reservation()
+	reservation.Count = 4
+	reservation.Constraints = []*structs.Constraint{{
+		LTarget: "${node.unique.id}",
+		RTarget: nodes[2].ID,
+		Operand: "!=",
+	}}
+
+	nodeIDs, err := PlaceReservation(store, reservation, testlog.HCLogger(t))
+	require.NoError(t, err)
+	require.Len(t, nodeIDs, 4)
+
+	placed := map[string]int{}
+	for _, id := range nodeIDs {
+		placed[id]++
+	}
+	require.Equal(t, map[string]int{nodes[0].ID: 2, nodes[1].ID: 2}, placed)
+
+	// The nodes are full once the reservation is stored
+	reservation.NodeIDs = nodeIDs
+	require.NoError(t, store.UpsertReservation(structs.MsgTypeTestSetup, 2000, reservation))
+
+	other := mock.Reservation()
+	other.Count = 2
+	other.Constraints = reservation.Constraints
+	_, err = PlaceReservation(store, other, testlog.HCLogger(t))
+	require.Error(t, err)
+	require.Contains(t, err.Error(), "only 0 of 2 instances could be placed")
+}
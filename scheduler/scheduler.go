@@ -102,11 +102,22 @@ type State interface {
 	// SchedulerConfig returns config options for the scheduler
 	SchedulerConfig() (uint64, *structs.SchedulerConfiguration, error)
 
+	// NodePoolByName is used to lookup a node pool by name
+	NodePoolByName(ws memdb.WatchSet, name string) (*structs.NodePool, error)
+
+	// ReservationsByNode returns the reservations with instances placed on
+	// the node
+	ReservationsByNode(ws memdb.WatchSet, nodeID string) ([]*structs.Reservation, error)
+
 	// CSIVolumeByID fetch CSI volumes, containing controller jobs
 	CSIVolumeByID(memdb.WatchSet, string, string) (*structs.CSIVolume, error)
 
 	// CSIVolumeByID fetch CSI volumes, containing controller jobs
 	CSIVolumesByNodeID(memdb.WatchSet, string, string) (memdb.ResultIterator, error)
+
+	// JobDependenciesMet returns whether the dependencies of the job are met
+	// and otherwise describes the first unmet dependency
+	JobDependenciesMet(ws memdb.WatchSet, job *structs.Job) (bool, string, error)
 }
 
 // Planner interface is used to submit a task allocation plan.
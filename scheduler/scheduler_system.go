@@ -84,6 +84,18 @@ func (s *SystemScheduler) Process(eval *structs.Evaluation) error {
 			s.queuedAllocs, "")
 	}
 
+	// Hold the evaluation while the dependencies of the job are unmet
+	if s.sysbatch {
+		waiting, err := holdForDependencies(s.state, s.planner, s.eval)
+		if err != nil {
+			return err
+		} else if waiting != nil {
+			s.logger.Debug("job dependencies unmet, waiting eval created", "waiting_eval_id", waiting.ID)
+			return setStatus(s.logger, s.planner, s.eval, waiting, nil, nil, structs.EvalStatusComplete, "",
+				nil, "")
+		}
+	}
+
 	limit := maxSystemScheduleAttempts
 	if s.sysbatch {
 		limit = maxSysBatchScheduleAttempts
@@ -123,7 +135,7 @@ func (s *SystemScheduler) process() (bool, error) {
 
 	// Get the ready nodes in the required datacenters
 	if !s.job.Stopped() {
-		s.nodes, s.notReadyNodes, s.nodesByDC, err = readyNodesInDCs(s.state, s.job.Datacenters)
+		s.nodes, s.notReadyNodes, s.nodesByDC, err = readyNodesInDCs(s.state, s.job.Datacenters, s.job.NodePool)
 		if err != nil {
 			return false, fmt.Errorf("failed to get ready nodes: %v", err)
 		}
@@ -137,6 +149,7 @@ func (s *SystemScheduler) process() (bool, error) {
 
 	// Create an evaluation context
 	s.ctx = NewEvalContext(s.state, s.plan, s.logger)
+	s.ctx.SetExplain(s.eval.ExplainPlan)
 
 	// Construct the placement stack
 	s.stack = NewSystemStack(s.sysbatch, s.ctx)
@@ -495,7 +508,8 @@ func (s *SystemScheduler) canHandle(trigger string) bool {
 	default:
 		switch s.sysbatch {
 		case true:
-			return trigger == structs.EvalTriggerPeriodicJob
+			return trigger == structs.EvalTriggerPeriodicJob ||
+				trigger == structs.EvalTriggerJobDependencies
 		case false:
 			return false
 		}
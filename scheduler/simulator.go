@@ -0,0 +1,348 @@
+package scheduler
+
+import (
+	"fmt"
+	"sort"
+	"sync"
+	"time"
+
+	log "github.com/hashicorp/go-hclog"
+	memdb "github.com/hashicorp/go-memdb"
+	"github.com/hashicorp/nomad/helper/uuid"
+	"github.com/hashicorp/nomad/nomad/state"
+	"github.com/hashicorp/nomad/nomad/structs"
+)
+
+// Simulator runs the built in schedulers against a private state store
+// without any side effects on a cluster. It implements the Planner interface
+// the same way the testing Harness does, applying every submitted plan
+// directly to its state store, and records the outcome of each evaluation so
+// the resulting placements can be summarized.
+type Simulator struct {
+	logger log.Logger
+
+	// State is the state store the schedulers are run against.
+	State *state.StateStore
+
+	// Evals are the evaluations updated by the schedulers.
+	Evals []*structs.Evaluation
+
+	// Placed is the number of allocations created by the schedulers.
+	Placed int
+
+	planLock  sync.Mutex
+	nextIndex uint64
+}
+
+// NewSimulator returns a Simulator that schedules against the given state
+// store. Writes made by the simulator start after the latest index in it.
+func NewSimulator(logger log.Logger, state *state.StateStore) (*Simulator, error) {
+	index, err := state.LatestIndex()
+	if err != nil {
+		return nil, err
+	}
+
+	return &Simulator{
+		logger:    logger.Named("simulator"),
+		State:     state,
+		nextIndex: index + 1,
+	}, nil
+}
+
+// Register upserts the job into the state store and processes a job register
+// evaluation for it using the scheduler matching its type.
+func (s *Simulator) Register(job *structs.Job) error {
+	if err := s.State.UpsertJob(structs.JobRegisterRequestType, s.NextIndex(), job); err != nil {
+		return fmt.Errorf("failed to upsert job %q: %v", job.ID, err)
+	}
+
+	// Read the job back so the evaluation references the stored version.
+	stored, err := s.State.JobByID(nil, job.Namespace, job.ID)
+	if err != nil {
+		return err
+	}
+
+	eval := &structs.Evaluation{
+		ID:             uuid.Generate(),
+		Namespace:      stored.Namespace,
+		Priority:       stored.Priority,
+		Type:           stored.Type,
+		TriggeredBy:    structs.EvalTriggerJobRegister,
+		JobID:          stored.ID,
+		JobModifyIndex: stored.JobModifyIndex,
+		Status:         structs.EvalStatusPending,
+	}
+	if err := s.State.UpsertEvals(structs.EvalUpdateRequestType, s.NextIndex(), []*structs.Evaluation{eval}); err != nil {
+		return fmt.Errorf("failed to upsert evaluation for job %q: %v", job.ID, err)
+	}
+
+	return s.Process(eval)
+}
+
+// Process runs the scheduler matching the evaluation type against a snapshot
+// of the state store.
+func (s *Simulator) Process(eval *structs.Evaluation) error {
+	snap, err := s.State.Snapshot()
+	if err != nil {
+		return err
+	}
+
+	sched, err := NewScheduler(eval.Type, s.logger, snap, s)
+	if err != nil {
+		return err
+	}
+	return sched.Process(eval)
+}
+
+// SubmitPlan is used to handle plan submission
+func (s *Simulator) SubmitPlan(plan *structs.Plan) (*structs.PlanResult, State, error) {
+	// Ensure sequential plan application
+	s.planLock.Lock()
+	defer s.planLock.Unlock()
+
+	index := s.NextIndex()
+
+	result := new(structs.PlanResult)
+	result.NodeUpdate = plan.NodeUpdate
+	result.NodeAllocation = plan.NodeAllocation
+	result.NodePreemptions = plan.NodePreemptions
+	result.AllocIndex = index
+
+	now := time.Now().UTC().UnixNano()
+
+	var allocs []*structs.Allocation
+	for _, updateList := range plan.NodeUpdate {
+		allocs = append(allocs, updateList...)
+	}
+	for _, allocList := range plan.NodeAllocation {
+		for _, alloc := range allocList {
+			existing, err := s.State.AllocByID(nil, alloc.ID)
+			if err != nil {
+				return nil, nil, err
+			}
+			if existing == nil {
+				s.Placed++
+			}
+		}
+		allocs = append(allocs, allocList...)
+	}
+	updateCreateTimestamp(allocs, now)
+
+	var preemptedAllocs []*structs.Allocation
+	for _, preemptions := range result.NodePreemptions {
+		for _, alloc := range preemptions {
+			alloc.ModifyTime = now
+			preemptedAllocs = append(preemptedAllocs, alloc)
+		}
+	}
+
+	req := structs.ApplyPlanResultsRequest{
+		AllocUpdateRequest: structs.AllocUpdateRequest{
+			Job:   plan.Job,
+			Alloc: allocs,
+		},
+		Deployment:        plan.Deployment,
+		DeploymentUpdates: plan.DeploymentUpdates,
+		EvalID:            plan.EvalID,
+		NodePreemptions:   preemptedAllocs,
+	}
+
+	err := s.State.UpsertPlanResults(structs.ApplyPlanResultsRequestType, index, &req)
+	return result, nil, err
+}
+
+// UpdateEval records the evaluation
+func (s *Simulator) UpdateEval(eval *structs.Evaluation) error {
+	s.planLock.Lock()
+	defer s.planLock.Unlock()
+
+	s.Evals = append(s.Evals, eval)
+	return nil
+}
+
+// CreateEval is a no-op since follow up evaluations, such as blocked
+// evaluations, are never processed by the simulator.
+func (s *Simulator) CreateEval(*structs.Evaluation) error {
+	return nil
+}
+
+// ReblockEval is a no-op since blocked evaluations are never created.
+func (s *Simulator) ReblockEval(*structs.Evaluation) error {
+	return nil
+}
+
+// NextIndex returns the next index
+func (s *Simulator) NextIndex() uint64 {
+	idx := s.nextIndex
+	s.nextIndex += 1
+	return idx
+}
+
+// SimulatedFailure is a task group that could not be fully placed.
+type SimulatedFailure struct {
+	Namespace string
+	JobID     string
+	TaskGroup string
+
+	// Count is the number of allocations that failed to be placed.
+	Count int
+}
+
+// Failures returns the placement failures of the processed evaluations,
+// sorted by namespace, job and task group.
+func (s *Simulator) Failures() []*SimulatedFailure {
+	var failures []*SimulatedFailure
+	for _, eval := range s.Evals {
+		for tg, metric := range eval.FailedTGAllocs {
+			failures = append(failures, &SimulatedFailure{
+				Namespace: eval.Namespace,
+				JobID:     eval.JobID,
+				TaskGroup: tg,
+				Count:     metric.CoalescedFailures + 1,
+			})
+		}
+	}
+
+	sort.Slice(failures, func(i, j int) bool {
+		a, b := failures[i], failures[j]
+		if a.Namespace != b.Namespace {
+			return a.Namespace < b.Namespace
+		}
+		if a.JobID != b.JobID {
+			return a.JobID < b.JobID
+		}
+		return a.TaskGroup < b.TaskGroup
+	})
+	return failures
+}
+
+// SimulatedClass summarizes the utilization of the ready nodes of a node
+// class once the simulation is done.
+type SimulatedClass struct {
+	Class string
+
+	// Nodes is the number of ready nodes and UsedNodes the number of those
+	// running at least one allocation.
+	Nodes     int
+	UsedNodes int
+
+	// Allocs is the number of non-terminal allocations on the nodes.
+	Allocs int
+
+	CPUUsed     int64
+	CPUTotal    int64
+	MemoryUsed  int64
+	MemoryTotal int64
+
+	// cpuStranded and memoryStranded is the free capacity left on nodes
+	// that are running allocations.
+	cpuStranded    int64
+	memoryStranded int64
+
+	// Exhausted is the number of times a node of the class was filtered
+	// for lack of resources by a failed placement.
+	Exhausted int
+}
+
+// Fragmentation returns the percentage of free capacity that is left on
+// partially used nodes instead of on empty nodes, averaged over CPU and
+// memory when they have free capacity. Free capacity spread across many
+// nodes can't be used by large allocations even though the class has
+// enough of it in total.
+func (c *SimulatedClass) Fragmentation() float64 {
+	var frag float64
+	dims := 0
+	if free := c.CPUTotal - c.CPUUsed; free > 0 {
+		frag += float64(c.cpuStranded) / float64(free)
+		dims++
+	}
+	if free := c.MemoryTotal - c.MemoryUsed; free > 0 {
+		frag += float64(c.memoryStranded) / float64(free)
+		dims++
+	}
+	if dims == 0 {
+		return 0
+	}
+	return frag / float64(dims) * 100
+}
+
+// Classes returns the utilization of the nodes in the state store grouped by
+// node class, sorted by class name.
+func (s *Simulator) Classes() ([]*SimulatedClass, error) {
+	iter, err := s.State.Nodes(memdb.NewWatchSet())
+	if err != nil {
+		return nil, err
+	}
+
+	classes := make(map[string]*SimulatedClass)
+	for {
+		raw := iter.Next()
+		if raw == nil {
+			break
+		}
+		node := raw.(*structs.Node)
+		if !node.Ready() {
+			continue
+		}
+
+		class, ok := classes[node.NodeClass]
+		if !ok {
+			class = &SimulatedClass{Class: node.NodeClass}
+			classes[node.NodeClass] = class
+		}
+
+		allocs, err := s.State.AllocsByNode(nil, node.ID)
+		if err != nil {
+			return nil, err
+		}
+
+		used := new(structs.ComparableResources)
+		count := 0
+		for _, alloc := range allocs {
+			if alloc.TerminalStatus() {
+				continue
+			}
+			used.Add(alloc.ComparableResources())
+			count++
+		}
+
+		total := node.NodeResources.Comparable()
+		total.Subtract(node.ReservedResources.Comparable())
+
+		class.Nodes++
+		class.Allocs += count
+		class.CPUTotal += total.Flattened.Cpu.CpuShares
+		class.MemoryTotal += total.Flattened.Memory.MemoryMB
+		class.CPUUsed += used.Flattened.Cpu.CpuShares
+		class.MemoryUsed += used.Flattened.Memory.MemoryMB
+
+		if count > 0 {
+			class.UsedNodes++
+			if free := total.Flattened.Cpu.CpuShares - used.Flattened.Cpu.CpuShares; free > 0 {
+				class.cpuStranded += free
+			}
+			if free := total.Flattened.Memory.MemoryMB - used.Flattened.Memory.MemoryMB; free > 0 {
+				class.memoryStranded += free
+			}
+		}
+	}
+
+	for _, eval := range s.Evals {
+		for _, metric := range eval.FailedTGAllocs {
+			for name, n := range metric.ClassExhausted {
+				if class, ok := classes[name]; ok {
+					class.Exhausted += n
+				}
+			}
+		}
+	}
+
+	result := make([]*SimulatedClass, 0, len(classes))
+	for _, class := range classes {
+		result = append(result, class)
+	}
+	sort.Slice(result, func(i, j int) bool {
+		return result[i].Class < result[j].Class
+	})
+	return result, nil
+}
@@ -0,0 +1,55 @@
+package scheduler
+
+import (
+	"testing"
+
+	"github.com/hashicorp/nomad/helper/testlog"
+	"github.com/hashicorp/nomad/nomad/mock"
+	"github.com/hashicorp/nomad/nomad/state"
+	"github.com/hashicorp/nomad/nomad/structs"
+	"github.com/stretchr/testify/require"
+)
+
+func TestSimulator_Register(t *testing.T) {
+	store := state.TestStateStore(t)
+
+	// Create two nodes, each fitting three allocations of the job
+	for i := 0; i < 2; i++ {
+		node := mock.Node()
+		node.NodeResources.Cpu.CpuShares = 1600
+		node.ReservedResources.Cpu.CpuShares = 100
+		require.NoError(t, store.UpsertNode(structs.MsgTypeTestSetup, uint64(100+i), node))
+	}
+
+	sim, err := NewSimulator(testlog.HCLogger(t), store)
+	require.NoError(t, err)
+
+	// Register a job with more allocations than fit
+	job := mock.Job()
+	job.TaskGroups[0].Count = 8
+	require.NoError(t, sim.Register(job))
+
+	require.Equal(t, 6, sim.Placed)
+
+	failures := sim.Failures()
+	require.Len(t, failures, 1)
+	require.Equal(t, job.ID, failures[0].JobID)
+	require.Equal(t, "web", failures[0].TaskGroup)
+	require.Equal(t, 2, failures[0].Count)
+
+	classes, err := sim.Classes()
+	require.NoError(t, err)
+	require.Len(t, classes, 1)
+
+	class := classes[0]
+	require.Equal(t, "linux-medium-pci", class.Class)
+	require.Equal(t, 2, class.Nodes)
+	require.Equal(t, 2, class.UsedNodes)
+	require.Equal(t, 6, class.Allocs)
+	require.Equal(t, int64(3000), class.CPUTotal)
+	require.Equal(t, int64(3000), class.CPUUsed)
+	require.Positive(t, class.Exhausted)
+
+	// All of the free memory is left on used nodes
+	require.Equal(t, 100.0, class.Fragmentation())
+}
@@ -255,3 +255,163 @@ func (iter *SpreadIterator) computeSpreadInfo(tg *structs.TaskGroup) {
 	}
 	iter.tgSpreadInfo[tg.Name] = spreadInfos
 }
+
+// hardSpreads returns the hard spreads of the job and task group.
+func hardSpreads(job *structs.Job, tg *structs.TaskGroup) []*structs.Spread {
+	var spreads []*structs.Spread
+	if job != nil {
+		for _, spread := range job.Spreads {
+			if spread.Hard {
+				spreads = append(spreads, spread)
+			}
+		}
+	}
+	for _, spread := range tg.Spreads {
+		if spread.Hard {
+			spreads = append(spreads, spread)
+		}
+	}
+	return spreads
+}
+
+// SpreadConstraintIterator is a FeasibleIterator which returns nodes that
+// satisfy the hard spreads of a job and task group. Placing on a node must not
+// make the difference between the number of allocations with the node's
+// attribute value and the least used attribute value exceed the max skew.
+// Attribute values are taken from the nodes being considered for placement.
+type SpreadConstraintIterator struct {
+	ctx    Context
+	source FeasibleIterator
+	job    *structs.Job
+	tg     *structs.TaskGroup
+
+	// nodes are the nodes being considered for placement and values are
+	// their attribute values, memoized by attribute
+	nodes  []*structs.Node
+	values map[string]map[string]struct{}
+
+	// groupSpreads is a memoized map from task group to its hard spreads and
+	// their property sets
+	groupSpreads map[string][]*hardSpread
+}
+
+type hardSpread struct {
+	spread *structs.Spread
+	pset   *propertySet
+}
+
+// NewSpreadConstraintIterator creates a SpreadConstraintIterator from a
+// source.
+func NewSpreadConstraintIterator(ctx Context, source FeasibleIterator) *SpreadConstraintIterator {
+	return &SpreadConstraintIterator{
+		ctx:          ctx,
+		source:       source,
+		values:       make(map[string]map[string]struct{}),
+		groupSpreads: make(map[string][]*hardSpread),
+	}
+}
+
+func (iter *SpreadConstraintIterator) SetNodes(nodes []*structs.Node) {
+	iter.nodes = nodes
+	iter.values = make(map[string]map[string]struct{})
+}
+
+func (iter *SpreadConstraintIterator) SetJob(job *structs.Job) {
+	iter.job = job
+
+	// The memoized spreads and property sets belong to the previous job
+	iter.groupSpreads = make(map[string][]*hardSpread)
+}
+
+func (iter *SpreadConstraintIterator) SetTaskGroup(tg *structs.TaskGroup) {
+	iter.tg = tg
+
+	if _, ok := iter.groupSpreads[tg.Name]; ok {
+		return
+	}
+
+	var spreads []*hardSpread
+	for _, spread := range hardSpreads(iter.job, tg) {
+		pset := NewPropertySet(iter.ctx, iter.job)
+		pset.SetTargetAttribute(spread.Attribute, tg.Name)
+
+		// The iterator was already reset for this placement, so account for
+		// the allocations of the plan now
+		pset.PopulateProposed()
+		spreads = append(spreads, &hardSpread{spread: spread, pset: pset})
+	}
+	iter.groupSpreads[tg.Name] = spreads
+}
+
+func (iter *SpreadConstraintIterator) Next() *structs.Node {
+	for {
+		option := iter.source.Next()
+
+		// Hot-path if the option is nil or there are no hard spreads
+		if option == nil || len(iter.groupSpreads[iter.tg.Name]) == 0 {
+			return option
+		}
+
+		if !iter.satisfiesSpreads(option) {
+			continue
+		}
+
+		return option
+	}
+}
+
+// satisfiesSpreads checks if placing on the node satisfies the max skew of
+// every hard spread, recording the reason in the metrics otherwise.
+func (iter *SpreadConstraintIterator) satisfiesSpreads(option *structs.Node) bool {
+	for _, hs := range iter.groupSpreads[iter.tg.Name] {
+		_, errorMsg, usedCount := hs.pset.UsedCount(option, iter.tg.Name)
+		if errorMsg != "" {
+			iter.ctx.Metrics().FilterNode(option, errorMsg)
+			return false
+		}
+
+		// Find the least used attribute value. Values without allocations
+		// have a count of zero.
+		combinedUse := hs.pset.GetCombinedUseMap()
+		minCount := usedCount
+		for value := range iter.attributeValues(hs.spread.Attribute) {
+			if count := combinedUse[value]; count < minCount {
+				minCount = count
+			}
+		}
+
+		// Add one to include placement on this node
+		if skew := usedCount + 1 - minCount; skew > uint64(hs.spread.MaxSkew) {
+			iter.ctx.Metrics().SpreadSkewFilterNode(option, hs.spread.Attribute)
+			return false
+		}
+	}
+
+	return true
+}
+
+// attributeValues returns the set of values of the attribute across the
+// nodes being considered for placement.
+func (iter *SpreadConstraintIterator) attributeValues(attribute string) map[string]struct{} {
+	if values, ok := iter.values[attribute]; ok {
+		return values
+	}
+
+	values := make(map[string]struct{})
+	for _, node := range iter.nodes {
+		if value, ok := getProperty(node, attribute); ok {
+			values[value] = struct{}{}
+		}
+	}
+	iter.values[attribute] = values
+	return values
+}
+
+func (iter *SpreadConstraintIterator) Reset() {
+	iter.source.Reset()
+	for _, spreads := range iter.groupSpreads {
+		for _, hs := range spreads {
+			hs.pset.PopulateProposed()
+		}
+	}
+}
@@ -568,3 +568,83 @@ func Test_evenSpreadScoreBoost(t *testing.T) {
 	require.False(t, math.IsInf(boost, 1))
 	require.Equal(t, 1.0, boost)
 }
+
+func TestSpreadConstraintIterator(t *testing.T) {
+	state, ctx := testContext(t)
+	dcs := []string{"dc1", "dc1", "dc2", "dc3"}
+	var nodes []*structs.Node
+
+	// Add these nodes to the state store
+	for i, dc := range dcs {
+		node := mock.Node()
+		node.Datacenter = dc
+		require.NoError(t, state.UpsertNode(structs.MsgTypeTestSetup, uint64(100+i), node))
+		nodes = append(nodes, node)
+	}
+
+	job := mock.Job()
+	tg := job.TaskGroups[0]
+	tg.Spreads = []*structs.Spread{
+		{
+			Attribute: "${node.datacenter}",
+			Weight:    50,
+			MaxSkew:   1,
+			Hard:      true,
+		},
+	}
+
+	// Add an existing alloc in dc1 and a proposed alloc in dc2
+	existing := &structs.Allocation{
+		Namespace: structs.DefaultNamespace,
+		TaskGroup: tg.Name,
+		JobID:     job.ID,
+		Job:       job,
+		ID:        uuid.Generate(),
+		EvalID:    uuid.Generate(),
+		NodeID:    nodes[0].ID,
+	}
+	require.NoError(t, state.UpsertAllocs(structs.MsgTypeTestSetup, 1000, []*structs.Allocation{existing}))
+
+	ctx.plan.NodeAllocation[nodes[2].ID] = []*structs.Allocation{
+		{
+			Namespace: structs.DefaultNamespace,
+			TaskGroup: tg.Name,
+			JobID:     job.ID,
+			Job:       job,
+			ID:        uuid.Generate(),
+			NodeID:    nodes[2].ID,
+		},
+	}
+
+	static := NewStaticIterator(ctx, nodes)
+	spreadIter := NewSpreadConstraintIterator(ctx, static)
+	spreadIter.SetNodes(nodes)
+	spreadIter.SetJob(job)
+	spreadIter.SetTaskGroup(tg)
+
+	// Only dc3 has no allocs, so placing in dc1 or dc2 exceeds the max skew
+	out := collectFeasible(spreadIter)
+	require.Equal(t, []*structs.Node{nodes[3]}, out)
+	require.Equal(t, map[string]int{"${node.datacenter}": 3}, ctx.Metrics().SpreadSkewFiltered)
+
+	// Stopping the existing alloc, as done by destructive updates, makes dc1
+	// the least used datacenter along with dc3
+	ctx.plan.AppendStoppedAlloc(existing, "", "", "")
+	ctx.Reset()
+	spreadIter.Reset()
+
+	out = collectFeasible(spreadIter)
+	require.Equal(t, []*structs.Node{nodes[0], nodes[1], nodes[3]}, out)
+
+	// Setting a job without hard spreads drops those of the previous job,
+	// even though its task group has the same name
+	job2 := mock.Job()
+	job2.TaskGroups[0].Name = tg.Name
+	spreadIter.SetJob(job2)
+	spreadIter.SetTaskGroup(job2.TaskGroups[0])
+	ctx.Reset()
+	spreadIter.Reset()
+
+	out = collectFeasible(spreadIter)
+	require.Equal(t, nodes, out)
+}
@@ -48,6 +48,7 @@ type GenericStack struct {
 	wrappedChecks        *FeasibilityWrapper
 	quota                FeasibleIterator
 	jobVersion           *uint64
+	jobNodePool          *NodePoolChecker
 	jobConstraint        *ConstraintChecker
 	taskGroupDrivers     *DriverChecker
 	taskGroupConstraint  *ConstraintChecker
@@ -58,12 +59,15 @@ type GenericStack struct {
 
 	distinctHostsConstraint    *DistinctHostsIterator
 	distinctPropertyConstraint *DistinctPropertyIterator
+	allocAffinityConstraint    *AllocAffinityConstraintIterator
+	spreadConstraint           *SpreadConstraintIterator
 	binPack                    *BinPackIterator
 	jobAntiAff                 *JobAntiAffinityIterator
 	nodeReschedulingPenalty    *NodeReschedulingPenaltyIterator
 	limit                      *LimitIterator
 	maxScore                   *MaxScoreIterator
 	nodeAffinity               *NodeAffinityIterator
+	allocAffinity              *AllocAffinityIterator
 	spread                     *SpreadIterator
 	scoreNorm                  *ScoreNormalizationIterator
 }
@@ -74,6 +78,7 @@ func (s *GenericStack) SetNodes(baseNodes []*structs.Node) {
 
 	// Update the set of base nodes
 	s.source.SetNodes(baseNodes)
+	s.spreadConstraint.SetNodes(baseNodes)
 
 	// Apply a limit function. This is to avoid scanning *every* possible node.
 	// For batch jobs we only need to evaluate 2 options and depend on the
@@ -98,12 +103,17 @@ func (s *GenericStack) SetJob(job *structs.Job) {
 	jobVer := job.Version
 	s.jobVersion = &jobVer
 
+	s.jobNodePool.SetPool(job.NodePool)
 	s.jobConstraint.SetConstraints(job.Constraints)
 	s.distinctHostsConstraint.SetJob(job)
 	s.distinctPropertyConstraint.SetJob(job)
+	s.allocAffinityConstraint.SetJob(job)
+	s.spreadConstraint.SetJob(job)
 	s.binPack.SetJob(job)
+	s.binPack.SetSchedulerConfiguration(nodePoolSchedulerConfig(s.ctx, job))
 	s.jobAntiAff.SetJob(job)
 	s.nodeAffinity.SetJob(job)
+	s.allocAffinity.SetJob(job)
 	s.spread.SetJob(job)
 	s.ctx.Eligibility().SetJob(job)
 	s.taskGroupCSIVolumes.SetNamespace(job.Namespace)
@@ -150,6 +160,8 @@ func (s *GenericStack) Select(tg *structs.TaskGroup, options *SelectOptions) *Ra
 	}
 	s.distinctHostsConstraint.SetTaskGroup(tg)
 	s.distinctPropertyConstraint.SetTaskGroup(tg)
+	s.allocAffinityConstraint.SetTaskGroup(tg)
+	s.spreadConstraint.SetTaskGroup(tg)
 	s.wrappedChecks.SetTaskGroup(tg.Name)
 	s.binPack.SetTaskGroup(tg)
 	if options != nil {
@@ -160,9 +172,10 @@ func (s *GenericStack) Select(tg *structs.TaskGroup, options *SelectOptions) *Ra
 		s.nodeReschedulingPenalty.SetPenaltyNodes(options.PenaltyNodeIDs)
 	}
 	s.nodeAffinity.SetTaskGroup(tg)
+	s.allocAffinity.SetTaskGroup(tg)
 	s.spread.SetTaskGroup(tg)
 
-	if s.nodeAffinity.hasAffinities() || s.spread.hasSpreads() {
+	if s.nodeAffinity.hasAffinities() || s.allocAffinity.hasAffinities() || s.spread.hasSpreads() {
 		s.limit.SetLimit(math.MaxInt32)
 	}
 
@@ -186,6 +199,7 @@ type SystemStack struct {
 
 	wrappedChecks        *FeasibilityWrapper
 	quota                FeasibleIterator
+	jobNodePool          *NodePoolChecker
 	jobConstraint        *ConstraintChecker
 	taskGroupDrivers     *DriverChecker
 	taskGroupConstraint  *ConstraintChecker
@@ -212,6 +226,9 @@ func NewSystemStack(sysbatch bool, ctx Context) *SystemStack {
 	// have to evaluate on all nodes.
 	s.source = NewStaticIterator(ctx, nil)
 
+	// Filter on the job's node pool. The job is filled in later.
+	s.jobNodePool = NewNodePoolChecker(ctx)
+
 	// Attach the job constraints. The job is filled in later.
 	s.jobConstraint = NewConstraintChecker(ctx, nil)
 
@@ -237,7 +254,7 @@ func NewSystemStack(sysbatch bool, ctx Context) *SystemStack {
 	// which feasibility checking can be skipped if the computed node class has
 	// previously been marked as eligible or ineligible. Generally this will be
 	// checks that only needs to examine the single node to determine feasibility.
-	jobs := []FeasibilityChecker{s.jobConstraint}
+	jobs := []FeasibilityChecker{s.jobNodePool, s.jobConstraint}
 	tgs := []FeasibilityChecker{
 		s.taskGroupDrivers,
 		s.taskGroupConstraint,
@@ -288,9 +305,11 @@ func (s *SystemStack) SetNodes(baseNodes []*structs.Node) {
 }
 
 func (s *SystemStack) SetJob(job *structs.Job) {
+	s.jobNodePool.SetPool(job.NodePool)
 	s.jobConstraint.SetConstraints(job.Constraints)
 	s.distinctPropertyConstraint.SetJob(job)
 	s.binPack.SetJob(job)
+	s.binPack.SetSchedulerConfiguration(nodePoolSchedulerConfig(s.ctx, job))
 	s.ctx.Eligibility().SetJob(job)
 
 	if contextual, ok := s.quota.(ContextualIterator); ok {
@@ -345,6 +364,9 @@ func NewGenericStack(batch bool, ctx Context) *GenericStack {
 	// balancing across eligible nodes.
 	s.source = NewRandomIterator(ctx, nil)
 
+	// Filter on the job's node pool. The job is filled in later.
+	s.jobNodePool = NewNodePoolChecker(ctx)
+
 	// Attach the job constraints. The job is filled in later.
 	s.jobConstraint = NewConstraintChecker(ctx, nil)
 
@@ -370,7 +392,7 @@ func NewGenericStack(batch bool, ctx Context) *GenericStack {
 	// which feasibility checking can be skipped if the computed node class has
 	// previously been marked as eligible or ineligible. Generally this will be
 	// checks that only needs to examine the single node to determine feasibility.
-	jobs := []FeasibilityChecker{s.jobConstraint}
+	jobs := []FeasibilityChecker{s.jobNodePool, s.jobConstraint}
 	tgs := []FeasibilityChecker{
 		s.taskGroupDrivers,
 		s.taskGroupConstraint,
@@ -387,12 +409,18 @@ func NewGenericStack(batch bool, ctx Context) *GenericStack {
 	// Filter on distinct property constraints.
 	s.distinctPropertyConstraint = NewDistinctPropertyIterator(ctx, s.distinctHostsConstraint)
 
+	// Filter on hard alloc affinities.
+	s.allocAffinityConstraint = NewAllocAffinityConstraintIterator(ctx, s.distinctPropertyConstraint)
+
+	// Filter on the max skew of hard spreads.
+	s.spreadConstraint = NewSpreadConstraintIterator(ctx, s.allocAffinityConstraint)
+
 	// Create the quota iterator to determine if placements would result in
 	// the quota attached to the namespace of the job to go over.
 	// Note: the quota iterator must be the last feasibility iterator before
 	// we upgrade to ranking, or our quota usage will include ineligible
 	// nodes!
-	s.quota = NewQuotaIterator(ctx, s.distinctPropertyConstraint)
+	s.quota = NewQuotaIterator(ctx, s.spreadConstraint)
 
 	// Upgrade from feasible to rank iterator
 	rankSource := NewFeasibleRankIterator(ctx, s.quota)
@@ -413,8 +441,11 @@ func NewGenericStack(batch bool, ctx Context) *GenericStack {
 	// Apply scores based on affinity stanza
 	s.nodeAffinity = NewNodeAffinityIterator(ctx, s.nodeReschedulingPenalty)
 
+	// Apply scores based on alloc_affinity stanza
+	s.allocAffinity = NewAllocAffinityIterator(ctx, s.nodeAffinity)
+
 	// Apply scores based on spread stanza
-	s.spread = NewSpreadIterator(ctx, s.nodeAffinity)
+	s.spread = NewSpreadIterator(ctx, s.allocAffinity)
 
 	// Add the preemption options scoring iterator
 	preemptionScorer := NewPreemptionScoringIterator(ctx, s.spread)
@@ -429,3 +460,22 @@ func NewGenericStack(batch bool, ctx Context) *GenericStack {
 	s.maxScore = NewMaxScoreIterator(ctx, s.limit)
 	return s
 }
+
+// nodePoolSchedulerConfig returns the scheduler configuration for the job
+// with the overrides of the job's node pool applied.
+func nodePoolSchedulerConfig(ctx Context, job *structs.Job) *structs.SchedulerConfiguration {
+	_, schedConfig, err := ctx.State().SchedulerConfig()
+	if err != nil {
+		ctx.Logger().Error("failed to get scheduler configuration", "error", err)
+	}
+
+	if job.NodePool == "" {
+		return schedConfig
+	}
+	pool, err := ctx.State().NodePoolByName(nil, job.NodePool)
+	if err != nil {
+		ctx.Logger().Error("failed to get node pool", "node_pool", job.NodePool, "error", err)
+		return schedConfig
+	}
+	return schedConfig.WithNodePool(pool)
+}
@@ -6,6 +6,7 @@ import (
 	"runtime"
 	"testing"
 
+	"github.com/hashicorp/nomad/helper"
 	"github.com/hashicorp/nomad/nomad/mock"
 	"github.com/hashicorp/nomad/nomad/structs"
 	"github.com/stretchr/testify/require"
@@ -347,6 +348,54 @@ func TestServiceStack_Select_ConstraintFilter(t *testing.T) {
 	}
 }
 
+func TestServiceStack_Select_NodePool(t *testing.T) {
+	state, ctx := testContext(t)
+	nodes := []*structs.Node{
+		mock.Node(),
+		mock.Node(),
+	}
+	gpu := nodes[1]
+	gpu.NodePool = "gpu"
+	require.NoError(t, gpu.ComputeClass())
+
+	pool := &structs.NodePool{
+		Name: "gpu",
+		SchedulerConfiguration: &structs.NodePoolSchedulerConfiguration{
+			SchedulerAlgorithm:            structs.SchedulerAlgorithmSpread,
+			MemoryOversubscriptionEnabled: helper.BoolToPtr(true),
+		},
+	}
+	require.NoError(t, state.UpsertNodePools(structs.MsgTypeTestSetup, 1000, []*structs.NodePool{pool}))
+
+	stack := NewGenericStack(false, ctx)
+	stack.SetNodes(nodes)
+
+	job := mock.Job()
+	job.NodePool = "gpu"
+	stack.SetJob(job)
+
+	// The node pool scheduler configuration overrides are applied
+	require.True(t, stack.binPack.memoryOversubscription)
+
+	node := stack.Select(job.TaskGroups[0], &SelectOptions{})
+	require.NotNil(t, node, "missing node %#v", ctx.Metrics())
+	require.Equal(t, gpu, node.Node)
+
+	met := ctx.Metrics()
+	require.Equal(t, 1, met.NodesFiltered)
+	require.Equal(t, 1, met.ConstraintFiltered["node pool"])
+
+	// Jobs in the "all" node pool can be placed on any node
+	job = mock.Job()
+	job.Version = 1
+	job.NodePool = structs.NodePoolAll
+	stack.SetJob(job)
+	ctx.Reset()
+	node = stack.Select(job.TaskGroups[0], &SelectOptions{})
+	require.NotNil(t, node)
+	require.False(t, stack.binPack.memoryOversubscription)
+}
+
 func TestServiceStack_Select_BinPack_Overflow(t *testing.T) {
 	_, ctx := testContext(t)
 	nodes := []*structs.Node{
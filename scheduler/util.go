@@ -274,9 +274,13 @@ func diffSystemAllocs(
 	return result
 }
 
-// readyNodesInDCs returns all the ready nodes in the given datacenters and a
-// mapping of each data center to the count of ready nodes.
-func readyNodesInDCs(state State, dcs []string) ([]*structs.Node, map[string]struct{}, map[string]int, error) {
+// readyNodesInDCs returns all the ready nodes in the given datacenters and
+// node pool, and a mapping of each data center to the count of ready nodes.
+func readyNodesInDCs(state State, dcs []string, pool string) ([]*structs.Node, map[string]struct{}, map[string]int, error) {
+	if pool == "" {
+		pool = structs.NodePoolDefault
+	}
+
 	// Index the DCs
 	dcMap := make(map[string]int, len(dcs))
 	for _, dc := range dcs {
@@ -306,6 +310,9 @@ func readyNodesInDCs(state State, dcs []string) ([]*structs.Node, map[string]str
 		if _, ok := dcMap[node.Datacenter]; !ok {
 			continue
 		}
+		if pool != structs.NodePoolAll && nodePool(node) != pool {
+			continue
+		}
 		out = append(out, node)
 		dcMap[node.Datacenter]++
 	}
@@ -419,6 +426,11 @@ func tasksUpdated(jobA, jobB *structs.Job, taskGroup string) bool {
 		return true
 	}
 
+	// Check AllocAffinities
+	if allocAffinitiesUpdated(jobA, jobB, taskGroup) {
+		return true
+	}
+
 	// Check consul namespace updated
 	if consulNamespaceUpdated(a, b) {
 		return true
@@ -480,6 +492,8 @@ func tasksUpdated(jobA, jobB *structs.Job, taskGroup string) bool {
 			return true
 		} else if !ar.Devices.Equals(&br.Devices) {
 			return true
+		} else if !ar.NUMA.Equals(br.NUMA) {
+			return true
 		}
 	}
 	return false
@@ -680,6 +694,29 @@ func spreadsUpdated(jobA, jobB *structs.Job, taskGroup string) bool {
 	return !reflect.DeepEqual(aSpreads, bSpreads)
 }
 
+func allocAffinitiesUpdated(jobA, jobB *structs.Job, taskGroup string) bool {
+	var aAffinities []*structs.AllocAffinity
+	var bAffinities []*structs.AllocAffinity
+
+	tgA := jobA.LookupTaskGroup(taskGroup)
+	tgB := jobB.LookupTaskGroup(taskGroup)
+
+	// append jobA and task group level alloc affinities
+	aAffinities = append(aAffinities, jobA.AllocAffinities...)
+	aAffinities = append(aAffinities, tgA.AllocAffinities...)
+
+	// append jobB and task group level alloc affinities
+	bAffinities = append(bAffinities, jobB.AllocAffinities...)
+	bAffinities = append(bAffinities, tgB.AllocAffinities...)
+
+	// Check for equality
+	if len(aAffinities) != len(bAffinities) {
+		return true
+	}
+
+	return !reflect.DeepEqual(aAffinities, bAffinities)
+}
+
 // setStatus is used to update the status of the evaluation
 func setStatus(logger log.Logger, planner Planner,
 	eval, nextEval, spawnedBlocked *structs.Evaluation,
@@ -705,6 +742,49 @@ func setStatus(logger log.Logger, planner Planner,
 	return planner.UpdateEval(newEval)
 }
 
+// holdForDependencies creates a waiting evaluation if the job of the
+// evaluation has unmet dependencies and no allocations yet. It returns the
+// waiting evaluation, or nil if the evaluation can be processed. Once the
+// job has allocations its dependencies are no longer checked.
+func holdForDependencies(state State, planner Planner, eval *structs.Evaluation) (*structs.Evaluation, error) {
+	// Plans show the placements that would be made once the dependencies
+	// are met
+	if eval.AnnotatePlan {
+		return nil, nil
+	}
+
+	ws := memdb.NewWatchSet()
+	job, err := state.JobByID(ws, eval.Namespace, eval.JobID)
+	if err != nil {
+		return nil, fmt.Errorf("failed to get job %q: %v", eval.JobID, err)
+	}
+	if job == nil || job.Stopped() || len(job.DependsOn) == 0 {
+		return nil, nil
+	}
+
+	allocs, err := state.AllocsByJob(ws, eval.Namespace, eval.JobID, true)
+	if err != nil {
+		return nil, fmt.Errorf("failed to get allocs for job %q: %v", eval.JobID, err)
+	}
+	if len(allocs) != 0 {
+		return nil, nil
+	}
+
+	met, reason, err := state.JobDependenciesMet(ws, job)
+	if err != nil {
+		return nil, fmt.Errorf("failed to check dependencies of job %q: %v", eval.JobID, err)
+	}
+	if met {
+		return nil, nil
+	}
+
+	waiting := eval.CreateWaitingEval(reason)
+	if err := planner.CreateEval(waiting); err != nil {
+		return nil, err
+	}
+	return waiting, nil
+}
+
 // inplaceUpdate attempts to update allocations in-place where possible. It
 // returns the allocs that couldn't be done inplace and then those that could.
 func inplaceUpdate(ctx Context, eval *structs.Evaluation, job *structs.Job,
@@ -996,7 +1076,8 @@ func updateNonTerminalAllocsToLost(plan *structs.Plan, tainted map[string]*struc
 		if (alloc.DesiredStatus == structs.AllocDesiredStatusStop ||
 			alloc.DesiredStatus == structs.AllocDesiredStatusEvict) &&
 			(alloc.ClientStatus == structs.AllocClientStatusRunning ||
-				alloc.ClientStatus == structs.AllocClientStatusPending) {
+				alloc.ClientStatus == structs.AllocClientStatusPending ||
+				alloc.ClientStatus == structs.AllocClientStatusUnknown) {
 			plan.AppendStoppedAlloc(alloc, allocLost, structs.AllocClientStatusLost, "")
 		}
 	}
@@ -404,7 +404,7 @@ func TestReadyNodesInDCs(t *testing.T) {
 	require.NoError(t, state.UpsertNode(structs.MsgTypeTestSetup, 1002, node3))
 	require.NoError(t, state.UpsertNode(structs.MsgTypeTestSetup, 1003, node4))
 
-	nodes, notReady, dc, err := readyNodesInDCs(state, []string{"dc1", "dc2"})
+	nodes, notReady, dc, err := readyNodesInDCs(state, []string{"dc1", "dc2"}, structs.NodePoolAll)
 	require.NoError(t, err)
 	require.Equal(t, 2, len(nodes))
 	require.NotEqual(t, node3.ID, nodes[0].ID)
@@ -419,6 +419,32 @@ func TestReadyNodesInDCs(t *testing.T) {
 	require.Contains(t, notReady, node4.ID)
 }
 
+func TestReadyNodesInDCs_NodePool(t *testing.T) {
+	state := state.TestStateStore(t)
+	node1 := mock.Node()
+	node2 := mock.Node()
+	node2.NodePool = "gpu"
+
+	require.NoError(t, state.UpsertNode(structs.MsgTypeTestSetup, 1000, node1))
+	require.NoError(t, state.UpsertNode(structs.MsgTypeTestSetup, 1001, node2))
+
+	// Nodes without a node pool are in the default pool
+	nodes, _, _, err := readyNodesInDCs(state, []string{"dc1"}, structs.NodePoolDefault)
+	require.NoError(t, err)
+	require.Len(t, nodes, 1)
+	require.Equal(t, node1.ID, nodes[0].ID)
+
+	nodes, _, dc, err := readyNodesInDCs(state, []string{"dc1"}, "gpu")
+	require.NoError(t, err)
+	require.Len(t, nodes, 1)
+	require.Equal(t, node2.ID, nodes[0].ID)
+	require.Equal(t, 1, dc["dc1"])
+
+	nodes, _, _, err = readyNodesInDCs(state, []string{"dc1"}, structs.NodePoolAll)
+	require.NoError(t, err)
+	require.Len(t, nodes, 2)
+}
+
 func TestRetryMax(t *testing.T) {
 	calls := 0
 	bad := func() (bool, error) {
@@ -643,6 +669,58 @@ func TestTaskUpdatedSpread(t *testing.T) {
 
 	require.False(t, tasksUpdated(j5, j6, name))
 }
+
+func TestTaskUpdatedAllocAffinity(t *testing.T) {
+	j1 := mock.Job()
+	j2 := mock.Job()
+	name := j1.TaskGroups[0].Name
+
+	require.False(t, tasksUpdated(j1, j2, name))
+
+	// TaskGroup AllocAffinity
+	j2.TaskGroups[0].AllocAffinities = []*structs.AllocAffinity{
+		{
+			Job:    "cache",
+			Weight: 50,
+		},
+	}
+	require.True(t, tasksUpdated(j1, j2, name))
+
+	// Job AllocAffinity
+	j3 := mock.Job()
+	j3.AllocAffinities = []*structs.AllocAffinity{
+		{
+			Job:    "cache",
+			Weight: 50,
+		},
+	}
+	require.True(t, tasksUpdated(j1, j3, name))
+
+	// Changed weight
+	j4 := j3.Copy()
+	j4.AllocAffinities[0].Weight = -50
+	require.True(t, tasksUpdated(j3, j4, name))
+
+	// check different level of same alloc affinity
+	j5 := mock.Job()
+	j5.AllocAffinities = []*structs.AllocAffinity{
+		{
+			Job:    "cache",
+			Weight: 50,
+		},
+	}
+
+	j6 := mock.Job()
+	j6.TaskGroups[0].AllocAffinities = []*structs.AllocAffinity{
+		{
+			Job:    "cache",
+			Weight: 50,
+		},
+	}
+
+	require.False(t, tasksUpdated(j5, j6, name))
+}
+
 func TestTasksUpdated(t *testing.T) {
 	j1 := mock.Job()
 	j2 := mock.Job()
@@ -753,6 +831,16 @@ func TestTasksUpdated(t *testing.T) {
 	j21.TaskGroups[0].Tasks[0].Resources.Cores = 4
 	require.True(t, tasksUpdated(j20, j21, name))
 
+	// Change NUMA affinity
+	j22 := j20.Copy()
+	j22.TaskGroups[0].Tasks[0].Resources.NUMA = &structs.NUMA{Affinity: structs.NUMAAffinityRequire}
+	require.True(t, tasksUpdated(j20, j22, name))
+
+	// Setting the default NUMA affinity is not an update
+	j23 := j20.Copy()
+	j23.TaskGroups[0].Tasks[0].Resources.NUMA = &structs.NUMA{Affinity: structs.NUMAAffinityNone}
+	require.False(t, tasksUpdated(j20, j23, name))
+
 }
 
 func TestTasksUpdated_connectServiceUpdated(t *testing.T) {